@@ -28,16 +28,180 @@ type ConfigurationSpec struct {
 	// +optional
 	FailFast bool `json:"failFast,omitempty"`
 
+	// FailFastCancel cancels the running tests when a test fails in fail fast mode, their cleanup still runs.
+	// +optional
+	FailFastCancel bool `json:"failFastCancel,omitempty"`
+
+	// DryRun validates the tests without modifying the cluster, the operations creating or modifying resources are rendered and dry run on the server if a cluster is reachable, the other operations are skipped.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
 	// The maximum number of tests to run at once.
 	// +kubebuilder:validation:Format:=int
 	// +kubebuilder:validation:Minimum:=1
 	// +optional
 	Parallel *int `json:"parallel,omitempty"`
 
+	// Dashboard renders the progress of the run live while tests run.
+	// On terminals it takes over the console, test logs are not printed.
+	// +optional
+	Dashboard bool `json:"dashboard,omitempty"`
+
+	// Progress prints a line with the number of completed and failed tests while tests run.
+	// +optional
+	Progress bool `json:"progress,omitempty"`
+
+	// ProgressInterval, if set, prints the progress line periodically instead of after each test completion.
+	// +optional
+	ProgressInterval *metav1.Duration `json:"progressInterval,omitempty"`
+
+	// Quiet disables the progress line, typically when the output is parsed by machines.
+	// +optional
+	Quiet bool `json:"quiet,omitempty"`
+
+	// LogFormat determines the format of the test logs printed on the console (text|json). It defaults to "text".
+	// +optional
+	LogFormat string `json:"logFormat,omitempty"`
+
+	// LogJSONPath is a file the test logs are written to as JSON lines, whatever the console format.
+	// +optional
+	LogJSONPath string `json:"logJSONPath,omitempty"`
+
+	// LogSyslog also sends the log lines to a syslog daemon, like journald, as RFC 5424 messages whose MSGID is the test name. It is either local, for the socket of the local daemon, or a URL like unix:///dev/log, udp://host:514 or tcp://host:601. When the daemon can not be reached the run goes on with a warning.
+	// +optional
+	LogSyslog string `json:"logSyslog,omitempty"`
+
+	// LogFile is a file a copy of the test logs is written to, color codes are stripped.
+	// +optional
+	LogFile string `json:"logFile,omitempty"`
+
+	// LogFilePerTest writes the logs of each test to its own file, LogFile is then the folder holding the files.
+	// +optional
+	LogFilePerTest bool `json:"logFilePerTest,omitempty"`
+
+	// LogFileMaxSize rotates the log files once they reach the given size in megabytes, 0 disables rotation.
+	// +kubebuilder:validation:Format:=int
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	LogFileMaxSize int `json:"logFileMaxSize,omitempty"`
+
+	// LogFileMaxBackups is the number of rotated log files kept, the oldest are removed, 0 keeps them all.
+	// +kubebuilder:validation:Format:=int
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	LogFileMaxBackups int `json:"logFileMaxBackups,omitempty"`
+
+	// LogFileCompress compresses rotated log files with gzip.
+	// +optional
+	LogFileCompress bool `json:"logFileCompress,omitempty"`
+
+	// LogElapsed prints the time elapsed since the start of the test, and of the running operation, in each log line.
+	// +optional
+	LogElapsed bool `json:"logElapsed,omitempty"`
+
+	// LogWorker prints the worker slot the test runs in, like w03, in each log line, it matches the worker of the test in the report.
+	// +optional
+	LogWorker bool `json:"logWorker,omitempty"`
+
+	// LogNamespace prints the namespace of the test, like [chainsaw-happy-cat], in each log line once it is assigned. JSON lines always hold it.
+	// +optional
+	LogNamespace bool `json:"logNamespace,omitempty"`
+
+	// LogCaller prints the location of the runner code logging the error lines, like processors/step.go:42, after each of them. It is either error, for the error lines, or warn, for the warning lines too. JSON lines hold it as caller.
+	// +optional
+	LogCaller string `json:"logCaller,omitempty"`
+
+	// LogResourceLinks makes the resources of the log lines printed on the terminal hyperlinks to the URL built from this template, like https://dashboard/#/{{.Kind}}/{{.Namespace}}/{{.Name}}. The template gets the Group, Kind, Namespace and Name of the resource. Log files and captured logs never hold hyperlinks.
+	// +optional
+	LogResourceLinks string `json:"logResourceLinks,omitempty"`
+
+	// LogHyperlinks determines whether resources are hyperlinks when LogResourceLinks is set, auto (the default) on terminals known to support them, always or never.
+	// +optional
+	LogHyperlinks string `json:"logHyperlinks,omitempty"`
+
+	// LogTimestampFormat is the Go reference layout, or the name of a standard layout like RFC3339Nano, of the timestamps of the test logs. It defaults to "15:04:05", "none" removes timestamps.
+	// +optional
+	LogTimestampFormat string `json:"logTimestampFormat,omitempty"`
+
+	// LogDeterministic renders the same test logs on every identical run, to compare them with golden files. Timestamps are suppressed, colors are disabled, durations are replaced with a placeholder and the worker slot is not printed.
+	// +optional
+	LogDeterministic bool `json:"logDeterministic,omitempty"`
+
+	// LogOrdinals numbers the steps and the operations in the prefix of the test logs after their names too, like deploy (2/5). Steps and operations without a name are always numbered.
+	// +optional
+	LogOrdinals bool `json:"logOrdinals,omitempty"`
+
+	// LogResourceFormat determines how resources are rendered in the test logs (full|namespaced|short). It defaults to "full".
+	// +optional
+	LogResourceFormat string `json:"logResourceFormat,omitempty"`
+
+	// LogResourceList determines how the resources of a log line about several resources are rendered, either counted or listed (count|list). It defaults to "count".
+	// +optional
+	LogResourceList string `json:"logResourceList,omitempty"`
+
+	// LogRedactPatterns lists regular expressions whose matches are redacted from the test logs, in addition to bearer tokens, AWS keys and secret looking values.
+	// +optional
+	LogRedactPatterns []string `json:"logRedactPatterns,omitempty"`
+
+	// LogColumnWidths aligns the test, step and operation columns of the test logs, names are padded or truncated in the middle. It is either "default" or overrides of the default widths like "test=40,step=20".
+	// +optional
+	LogColumnWidths string `json:"logColumnWidths,omitempty"`
+
+	// LogContinuation determines how the continuation lines of multi-line messages are rendered in the test logs (none|prefix|indent). It defaults to "none".
+	// +optional
+	LogContinuation string `json:"logContinuation,omitempty"`
+
+	// LogVerbosityLevels maps the V-levels of the lines logged through klog and logr, like the messages of client-go, to log levels. It is either "default" (0=info,1=debug,5=off) or a list like "0=info,4=debug,6=off", a V-level gets the level of the highest V-level listed at or below it.
+	// +optional
+	LogVerbosityLevels string `json:"logVerbosityLevels,omitempty"`
+
+	// LogGitHubGroups groups the logs of each test in a collapsible section of the GitHub Actions output, and reports failures as error annotations. It is enabled by default when running in GitHub Actions.
+	// +optional
+	LogGitHubGroups *bool `json:"logGitHubGroups,omitempty"`
+
+	// LogBuffered buffers the logs of tests running concurrently and prints each test as a contiguous block when it completes, the logs of failed tests are printed again at the end of the run.
+	// +optional
+	LogBuffered bool `json:"logBuffered,omitempty"`
+
+	// LogBufferOrder is the order in which buffered tests are printed, either when they complete (completion) or in the order they are declared (declaration).
+	// +optional
+	LogBufferOrder string `json:"logBufferOrder,omitempty"`
+
+	// LogDedupe collapses consecutive identical log lines of an operation into one, followed by a line telling how many times it was repeated.
+	// +optional
+	LogDedupe bool `json:"logDedupe,omitempty"`
+
+	// LogFailuresOnly prints the logs of failed tests only, followed by their failure, passing tests print nothing. The run summary is always printed and reports still get the logs of every test. It is also enabled by the CHAINSAW_LOG_FAILURES_ONLY environment variable.
+	// +optional
+	LogFailuresOnly bool `json:"logFailuresOnly,omitempty"`
+
+	// LogOperationTypes limits the logs printed on the console to the lines of operations of the given types, like script and command. Lines not logged by an operation are always printed, captured logs, log files and reports still get every line. Unless set, the CHAINSAW_LOG_OPERATION_TYPES environment variable holds a comma separated list of types.
+	// +optional
+	LogOperationTypes []string `json:"logOperationTypes,omitempty"`
+
+	// LogMessageMaxSize truncates the messages of log lines longer than the given size in bytes, before they reach any sink. It defaults to 16384, 0 keeps messages whole.
+	// +kubebuilder:validation:Format:=int
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	LogMessageMaxSize *int `json:"logMessageMaxSize,omitempty"`
+
+	// LogDiffMaxHunks is the maximum number of hunks of the diffs logged when an assertion fails, the omitted hunks are counted. It defaults to 10, 0 keeps diffs whole.
+	// +kubebuilder:validation:Format:=int
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	LogDiffMaxHunks *int `json:"logDiffMaxHunks,omitempty"`
+
+	// LogWaitInterval is the interval at which operations waiting for a resource, like asserts, log that they are still waiting, with the elapsed time and the last error. It defaults to 30s, 0 disables it.
+	// +optional
+	LogWaitInterval *metav1.Duration `json:"logWaitInterval,omitempty"`
+
+	// EventStream writes the events of the run, like tests starting and operations failing, as JSON lines to the given file, or file descriptor with fd:<n>.
+	// +optional
+	EventStream string `json:"eventStream,omitempty"`
+
 	// ReportFormat determines test report format (JSON|XML|nil) nil == no report.
 	// maps to report.Type, however we don't want generated.deepcopy to have reference to it.
 	// +optional
-	// +kubebuilder:validation:Enum:=JSON;XML;
 	ReportFormat ReportFormatType `json:"reportFormat,omitempty"`
 
 	// ReportPath defines the path.
@@ -49,6 +213,88 @@ type ConfigurationSpec struct {
 	// +kubebuilder:default:="chainsaw-report"
 	ReportName string `json:"reportName,omitempty"`
 
+	// ReportStrict fails the run if the generated report violates structural invariants.
+	// +optional
+	ReportStrict bool `json:"reportStrict,omitempty"`
+
+	// ReportFailures also writes a report holding only the failed tests, its name is suffixed with "-failures".
+	// +optional
+	ReportFailures bool `json:"reportFailures,omitempty"`
+
+	// ReportLogs embeds the console output captured while running each test in the report.
+	// +optional
+	ReportLogs bool `json:"reportLogs,omitempty"`
+
+	// ReportLogsFailedOnly restricts embedded console output to failed tests.
+	// +optional
+	ReportLogsFailedOnly bool `json:"reportLogsFailedOnly,omitempty"`
+
+	// ReportLogsFullMessages keeps the full text of truncated log messages in the console output embedded in the report.
+	// +optional
+	ReportLogsFullMessages bool `json:"reportLogsFullMessages,omitempty"`
+
+	// ReportLogsMaxSize caps the size in bytes of the console output embedded for a test, the oldest lines are dropped first.
+	// It defaults to 65536.
+	// +kubebuilder:validation:Format:=int
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	ReportLogsMaxSize *int `json:"reportLogsMaxSize,omitempty"`
+
+	// ReportEnv lists the names of environment variables recorded in the report of each test,
+	// values of variables whose name looks like a secret are redacted.
+	// +optional
+	ReportEnv []string `json:"reportEnv,omitempty"`
+
+	// ReportGroupBy groups tests into nested suites in XML reports (directory|label:<key>|prefix:<separator>).
+	// +optional
+	ReportGroupBy string `json:"reportGroupBy,omitempty"`
+
+	// ReportRetentionCount, if set, only keeps the given number of newest reports generated from a templated report name in the report path.
+	// +kubebuilder:validation:Format:=int
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	ReportRetentionCount *int `json:"reportRetentionCount,omitempty"`
+
+	// ReportRetentionMaxAge, if set, removes the reports generated from a templated report name in the report path older than the given duration.
+	// +optional
+	ReportRetentionMaxAge *metav1.Duration `json:"reportRetentionMaxAge,omitempty"`
+
+	// ReportRetentionDryRun prints the reports the retention would remove without removing them.
+	// +optional
+	ReportRetentionDryRun bool `json:"reportRetentionDryRun,omitempty"`
+
+	// NotificationURL is the webhook a summary of the run is posted to when tests fail.
+	// +optional
+	NotificationURL string `json:"notificationURL,omitempty"`
+
+	// NotificationFormat determines the notification payload (slack|webhook). It defaults to "slack".
+	// +optional
+	NotificationFormat string `json:"notificationFormat,omitempty"`
+
+	// NotificationAlways sends the notification even when all tests passed.
+	// +optional
+	NotificationAlways bool `json:"notificationAlways,omitempty"`
+
+	// PushgatewayURL is the Prometheus Pushgateway the metrics of the run are pushed to.
+	// +optional
+	PushgatewayURL string `json:"pushgatewayURL,omitempty"`
+
+	// PushgatewayJob is the job label of the metrics pushed to the Pushgateway. It defaults to "chainsaw".
+	// +optional
+	PushgatewayJob string `json:"pushgatewayJob,omitempty"`
+
+	// PushgatewayInstance is the instance label of the metrics pushed to the Pushgateway. It defaults to the suite name.
+	// +optional
+	PushgatewayInstance string `json:"pushgatewayInstance,omitempty"`
+
+	// OTLPEndpoint is the host and port of the OpenTelemetry collector the trace of the run is exported to.
+	// +optional
+	OTLPEndpoint string `json:"otlpEndpoint,omitempty"`
+
+	// OTLPProtocol determines the protocol used to export the trace (grpc|http). It defaults to "grpc".
+	// +optional
+	OTLPProtocol string `json:"otlpProtocol,omitempty"`
+
 	// Namespace defines the namespace to use for tests.
 	// If not specified, every test will execute in a random ephemeral namespace
 	// unless the namespace is overridden in a the test spec.
@@ -71,12 +317,37 @@ type ConfigurationSpec struct {
 	// +optional
 	IncludeTestRegex string `json:"includeTestRegex,omitempty"`
 
+	// Quarantine lists the names of known flaky tests, their failures are reported but don't fail the run.
+	// +optional
+	Quarantine []string `json:"quarantine,omitempty"`
+
+	// QuarantineSelector is a label selector matching known flaky tests, their failures are reported but don't fail the run.
+	// +optional
+	QuarantineSelector string `json:"quarantineSelector,omitempty"`
+
 	// RepeatCount indicates how many times the tests should be executed.
 	// +kubebuilder:validation:Format:=int
 	// +kubebuilder:validation:Minimum:=1
 	// +optional
 	RepeatCount *int `json:"repeatCount,omitempty"`
 
+	// Iterations overrides the number of times the tests matching IterationsFilter run, each iteration in its own namespace.
+	// +kubebuilder:validation:Format:=int
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	Iterations int `json:"iterations,omitempty"`
+
+	// IterationsFilter is a regular expression matching the names of the tests Iterations applies to, all tests if empty.
+	// +optional
+	IterationsFilter string `json:"iterationsFilter,omitempty"`
+
+	// PassRate is the minimum percentage of passed iterations of a test, any failed iteration fails the run if it is not set.
+	// +kubebuilder:validation:Format:=int
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:validation:Maximum:=100
+	// +optional
+	PassRate int `json:"passRate,omitempty"`
+
 	// TestFile is the name of the file containing the test to run.
 	// If no extension is provided, chainsaw will try with .yaml first and .yml if needed.
 	// +kubebuilder:default:="chainsaw-test"
@@ -11,6 +11,11 @@ type Operation struct {
 	// +optional
 	ContinueOnError *bool `json:"continueOnError,omitempty"`
 
+	// Retry determines how the operation is retried when it fails with a transient error.
+	// It is supported by apply, create, delete, patch and update operations, they are not retried by default.
+	// +optional
+	Retry *Retry `json:"retry,omitempty"`
+
 	// Apply represents resources that should be applied for this test step. This can include things
 	// like configuration settings or any other resources that need to be available during the test.
 	// +optional
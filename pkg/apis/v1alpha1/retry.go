@@ -0,0 +1,40 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RetryOn is a class of transient errors an operation can be retried on.
+type RetryOn string
+
+const (
+	// RetryOnConflict retries operations failing because the resource was modified concurrently.
+	RetryOnConflict RetryOn = "Conflict"
+	// RetryOnServerTimeout retries operations the API server did not complete in time.
+	RetryOnServerTimeout RetryOn = "ServerTimeout"
+	// RetryOnTooManyRequests retries operations throttled by the API server.
+	RetryOnTooManyRequests RetryOn = "TooManyRequests"
+	// RetryOnNetworkError retries operations failing to reach the API server.
+	RetryOnNetworkError RetryOn = "NetworkError"
+)
+
+// Retry defines how an operation failing with a transient error is retried.
+type Retry struct {
+	// Attempts is the maximum number of attempts of the operation, the first one included.
+	// +kubebuilder:validation:Minimum:=1
+	Attempts int `json:"attempts"`
+
+	// Interval is the delay before the first retry, it defaults to 1s.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// Factor multiplies the delay after each retry, it defaults to 2.
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	Factor *int `json:"factor,omitempty"`
+
+	// On lists the classes of errors the operation is retried on (Conflict|ServerTimeout|TooManyRequests|NetworkError).
+	// It defaults to all of them.
+	// +optional
+	On []RetryOn `json:"on,omitempty"`
+}
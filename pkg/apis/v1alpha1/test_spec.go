@@ -26,6 +26,19 @@ type TestSpec struct {
 	// +optional
 	Concurrent *bool `json:"concurrent,omitempty"`
 
+	// Iterations is the number of times the test runs, each iteration in its own namespace. It defaults to 1.
+	// +kubebuilder:validation:Format:=int
+	// +kubebuilder:validation:Minimum:=1
+	// +optional
+	Iterations *int `json:"iterations,omitempty"`
+
+	// PassRate is the minimum percentage of passed iterations of the test, any failed iteration fails the run if it is not set.
+	// +kubebuilder:validation:Format:=int
+	// +kubebuilder:validation:Minimum:=0
+	// +kubebuilder:validation:Maximum:=100
+	// +optional
+	PassRate *int `json:"passRate,omitempty"`
+
 	// SkipDelete determines whether the resources created by the test should be deleted after the test is executed.
 	// +optional
 	SkipDelete *bool `json:"skipDelete,omitempty"`
@@ -18,6 +18,10 @@ type TestStepSpec struct {
 	// +optional
 	SkipDelete *bool `json:"skipDelete,omitempty"`
 
+	// SkipIf is a JMESPath expression evaluated against the bindings before the step runs, the step is skipped if it evaluates to true.
+	// +optional
+	SkipIf string `json:"skipIf,omitempty"`
+
 	// Template determines whether resources should be considered for templating.
 	// +optional
 	Template *bool `json:"template,omitempty"`
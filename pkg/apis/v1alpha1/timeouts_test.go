@@ -101,3 +101,82 @@ func TestTimeouts_Combine(t *testing.T) {
 		})
 	}
 }
+
+func TestTimeouts_Precedence(t *testing.T) {
+	duration := func(d time.Duration) *metav1.Duration {
+		return &metav1.Duration{Duration: d}
+	}
+	config := Timeouts{
+		Apply:  duration(1 * time.Minute),
+		Assert: duration(1 * time.Minute),
+		Exec:   duration(1 * time.Minute),
+	}
+	tests := []struct {
+		name    string
+		test    *Timeouts
+		step    *Timeouts
+		resolve func(Timeouts) time.Duration
+		want    time.Duration
+	}{{
+		name:    "default",
+		resolve: Timeouts.CleanupDuration,
+		want:    DefaultCleanupTimeout,
+	}, {
+		name:    "config",
+		resolve: Timeouts.ApplyDuration,
+		want:    1 * time.Minute,
+	}, {
+		name:    "test overrides config",
+		test:    &Timeouts{Apply: duration(2 * time.Minute)},
+		resolve: Timeouts.ApplyDuration,
+		want:    2 * time.Minute,
+	}, {
+		name:    "test overrides default",
+		test:    &Timeouts{Delete: duration(2 * time.Minute)},
+		resolve: Timeouts.DeleteDuration,
+		want:    2 * time.Minute,
+	}, {
+		name:    "step overrides test",
+		test:    &Timeouts{Assert: duration(2 * time.Minute)},
+		step:    &Timeouts{Assert: duration(3 * time.Minute)},
+		resolve: Timeouts.AssertDuration,
+		want:    3 * time.Minute,
+	}, {
+		name:    "step overrides config",
+		test:    &Timeouts{},
+		step:    &Timeouts{Exec: duration(3 * time.Minute)},
+		resolve: Timeouts.ExecDuration,
+		want:    3 * time.Minute,
+	}, {
+		name:    "step overrides default",
+		step:    &Timeouts{Error: duration(3 * time.Minute)},
+		resolve: Timeouts.ErrorDuration,
+		want:    3 * time.Minute,
+	}, {
+		name:    "empty step keeps test",
+		test:    &Timeouts{Assert: duration(2 * time.Minute)},
+		step:    &Timeouts{},
+		resolve: Timeouts.AssertDuration,
+		want:    2 * time.Minute,
+	}, {
+		name:    "other kinds are not overridden",
+		test:    &Timeouts{Assert: duration(2 * time.Minute)},
+		step:    &Timeouts{Exec: duration(3 * time.Minute)},
+		resolve: Timeouts.ApplyDuration,
+		want:    1 * time.Minute,
+	}, {
+		name:    "cleanup",
+		test:    &Timeouts{Cleanup: duration(2 * time.Minute)},
+		step:    &Timeouts{Delete: duration(3 * time.Minute)},
+		resolve: Timeouts.CleanupDuration,
+		want:    2 * time.Minute,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved := config.Combine(tt.test).Combine(tt.step)
+			assert.Equal(t, tt.want, tt.resolve(resolved))
+		})
+	}
+	// combining doesn't modify the timeouts of the configuration
+	assert.Equal(t, 1*time.Minute, config.ApplyDuration())
+}
@@ -320,10 +320,70 @@ func (in *ConfigurationSpec) DeepCopyInto(out *ConfigurationSpec) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.LogRedactPatterns != nil {
+		in, out := &in.LogRedactPatterns, &out.LogRedactPatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LogGitHubGroups != nil {
+		in, out := &in.LogGitHubGroups, &out.LogGitHubGroups
+		*out = new(bool)
+		**out = **in
+	}
+	if in.LogOperationTypes != nil {
+		in, out := &in.LogOperationTypes, &out.LogOperationTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LogMessageMaxSize != nil {
+		in, out := &in.LogMessageMaxSize, &out.LogMessageMaxSize
+		*out = new(int)
+		**out = **in
+	}
+	if in.LogDiffMaxHunks != nil {
+		in, out := &in.LogDiffMaxHunks, &out.LogDiffMaxHunks
+		*out = new(int)
+		**out = **in
+	}
+	if in.LogWaitInterval != nil {
+		in, out := &in.LogWaitInterval, &out.LogWaitInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ReportLogsMaxSize != nil {
+		in, out := &in.ReportLogsMaxSize, &out.ReportLogsMaxSize
+		*out = new(int)
+		**out = **in
+	}
+	if in.ReportEnv != nil {
+		in, out := &in.ReportEnv, &out.ReportEnv
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ReportRetentionCount != nil {
+		in, out := &in.ReportRetentionCount, &out.ReportRetentionCount
+		*out = new(int)
+		**out = **in
+	}
+	if in.ReportRetentionMaxAge != nil {
+		in, out := &in.ReportRetentionMaxAge, &out.ReportRetentionMaxAge
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ProgressInterval != nil {
+		in, out := &in.ProgressInterval, &out.ProgressInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	if in.NamespaceTemplate != nil {
 		in, out := &in.NamespaceTemplate, &out.NamespaceTemplate
 		*out = (*in).DeepCopy()
 	}
+	if in.Quarantine != nil {
+		in, out := &in.Quarantine, &out.Quarantine
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.RepeatCount != nil {
 		in, out := &in.RepeatCount, &out.RepeatCount
 		*out = new(int)
@@ -830,6 +890,11 @@ func (in *Operation) DeepCopyInto(out *Operation) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(Retry)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Apply != nil {
 		in, out := &in.Apply, &out.Apply
 		*out = new(Apply)
@@ -1015,6 +1080,37 @@ func (in *ResourceReference) DeepCopy() *ResourceReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Retry) DeepCopyInto(out *Retry) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Factor != nil {
+		in, out := &in.Factor, &out.Factor
+		*out = new(int)
+		**out = **in
+	}
+	if in.On != nil {
+		in, out := &in.On, &out.On
+		*out = make([]RetryOn, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Retry.
+func (in *Retry) DeepCopy() *Retry {
+	if in == nil {
+		return nil
+	}
+	out := new(Retry)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Script) DeepCopyInto(out *Script) {
 	*out = *in
@@ -1123,6 +1219,16 @@ func (in *TestSpec) DeepCopyInto(out *TestSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Iterations != nil {
+		in, out := &in.Iterations, &out.Iterations
+		*out = new(int)
+		**out = **in
+	}
+	if in.PassRate != nil {
+		in, out := &in.PassRate, &out.PassRate
+		*out = new(int)
+		**out = **in
+	}
 	if in.SkipDelete != nil {
 		in, out := &in.SkipDelete, &out.SkipDelete
 		*out = new(bool)
@@ -3,16 +3,21 @@ package test
 import (
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path"
 	"strings"
+	"time"
 
+	fatihcolor "github.com/fatih/color"
 	"github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
 	"github.com/kyverno/chainsaw/pkg/config"
 	"github.com/kyverno/chainsaw/pkg/data"
 	"github.com/kyverno/chainsaw/pkg/discovery"
+	"github.com/kyverno/chainsaw/pkg/report"
 	"github.com/kyverno/chainsaw/pkg/runner"
+	"github.com/kyverno/chainsaw/pkg/runner/logging"
 	"github.com/kyverno/chainsaw/pkg/runner/template"
 	flagutils "github.com/kyverno/chainsaw/pkg/utils/flag"
 	fsutils "github.com/kyverno/chainsaw/pkg/utils/fs"
@@ -28,6 +33,9 @@ import (
 	"k8s.io/utils/clock"
 )
 
+// dryRunReachableTimeout is how long the cluster has to answer before a dry run goes on without it.
+const dryRunReachableTimeout = 5 * time.Second
+
 type options struct {
 	config                      string
 	testFile                    string
@@ -41,20 +49,86 @@ type options struct {
 	skipDelete                  bool
 	template                    bool
 	failFast                    bool
+	failFastCancel              bool
+	dryRun                      bool
+	dashboard                   bool
+	progress                    bool
+	quiet                       bool
+	progressInterval            metav1.Duration
+	logFormat                   string
+	logJSONPath                 string
+	logSyslog                   string
+	logFile                     string
+	logFilePerTest              bool
+	logFileMaxSize              int
+	logFileMaxBackups           int
+	logFileCompress             bool
+	logElapsed                  bool
+	logWorker                   bool
+	logNamespace                bool
+	logCaller                   string
+	logResourceLinks            string
+	logHyperlinks               string
+	logTimestampFormat          string
+	logDeterministic            bool
+	logOrdinals                 bool
+	logResourceFormat           string
+	logResourceList             string
+	logRedactPatterns           []string
+	logColumnWidths             string
+	logContinuation             string
+	logVerbosityLevels          string
+	logBuffered                 bool
+	logBufferOrder              string
+	logDedupe                   bool
+	logFailuresOnly             bool
+	logOperationTypes           []string
+	logMessageMaxSize           int
+	logDiffMaxHunks             int
+	logWaitInterval             metav1.Duration
+	eventStream                 string
+	logGitHubGroups             bool
 	parallel                    int
 	repeatCount                 int
+	iterations                  int
+	iterationsFilter            string
+	passRate                    int
 	reportFormat                string
 	reportPath                  string
 	reportName                  string
+	reportStrict                bool
+	reportFailures              bool
+	reportLogs                  bool
+	reportLogsFailedOnly        bool
+	reportLogsFullMessages      bool
+	reportLogsMaxSize           int
+	reportEnv                   []string
+	reportGroupBy               string
+	reportRetentionCount        int
+	reportRetentionMaxAge       metav1.Duration
+	reportRetentionDryRun       bool
+	notificationURL             string
+	notificationFormat          string
+	notificationAlways          bool
+	pushgatewayURL              string
+	pushgatewayJob              string
+	pushgatewayInstance         string
+	otlpEndpoint                string
+	otlpProtocol                string
 	namespace                   string
 	fullName                    bool
 	excludeTestRegex            string
 	includeTestRegex            string
+	quarantine                  []string
+	quarantineSelector          string
 	noColor                     bool
+	color                       string
+	glyphs                      string
 	kubeConfigOverrides         clientcmd.ConfigOverrides
 	forceTerminationGracePeriod metav1.Duration
 	delayBeforeCleanup          metav1.Duration
 	selector                    []string
+	rerunFailed                 string
 	noCluster                   bool
 	values                      []string
 	clusters                    []string
@@ -67,7 +141,27 @@ func Command() *cobra.Command {
 		Short:        "Run tests",
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			color.Init(options.noColor, true)
+			colorMode, err := logging.ParseColorMode(options.color)
+			if err != nil {
+				return err
+			}
+			if options.noColor {
+				colorMode = logging.ColorNever
+			}
+			glyphMode, err := logging.ParseGlyphMode(options.glyphs)
+			if err != nil {
+				return err
+			}
+			logging.SetGlyphMode(glyphMode)
+			if err := logging.LoadThemeEnv(); err != nil {
+				return err
+			}
+			logging.SetUTC(logging.UTCFromEnv())
+			colors := colorMode.Enabled()
+			logging.SetColorMode(colorMode)
+			color.Init(!colors, true)
+			// the run summary relies on the global switch
+			fatihcolor.NoColor = !colors
 			clock := clock.RealClock{}
 			out := cmd.OutOrStdout()
 			fmt.Fprintf(out, "Version: %s\n", version.Version())
@@ -131,12 +225,138 @@ func Command() *cobra.Command {
 			if flagutils.IsSet(flags, "fail-fast") {
 				configuration.Spec.FailFast = options.failFast
 			}
+			if flagutils.IsSet(flags, "fail-fast-cancel") {
+				configuration.Spec.FailFastCancel = options.failFastCancel
+			}
+			if flagutils.IsSet(flags, "dry-run") {
+				configuration.Spec.DryRun = options.dryRun
+			}
+			if flagutils.IsSet(flags, "dashboard") {
+				configuration.Spec.Dashboard = options.dashboard
+			}
+			if flagutils.IsSet(flags, "progress") {
+				configuration.Spec.Progress = options.progress
+			}
+			if flagutils.IsSet(flags, "quiet") {
+				configuration.Spec.Quiet = options.quiet
+			}
+			if flagutils.IsSet(flags, "progress-interval") {
+				configuration.Spec.ProgressInterval = &options.progressInterval
+			}
+			if flagutils.IsSet(flags, "log-format") {
+				configuration.Spec.LogFormat = options.logFormat
+			}
+			if flagutils.IsSet(flags, "log-json-path") {
+				configuration.Spec.LogJSONPath = options.logJSONPath
+			}
+			if flagutils.IsSet(flags, "log-syslog") {
+				configuration.Spec.LogSyslog = options.logSyslog
+			}
+			if flagutils.IsSet(flags, "log-file") {
+				configuration.Spec.LogFile = options.logFile
+			}
+			if flagutils.IsSet(flags, "log-file-per-test") {
+				configuration.Spec.LogFilePerTest = options.logFilePerTest
+			}
+			if flagutils.IsSet(flags, "log-file-max-size") {
+				configuration.Spec.LogFileMaxSize = options.logFileMaxSize
+			}
+			if flagutils.IsSet(flags, "log-file-max-backups") {
+				configuration.Spec.LogFileMaxBackups = options.logFileMaxBackups
+			}
+			if flagutils.IsSet(flags, "log-file-compress") {
+				configuration.Spec.LogFileCompress = options.logFileCompress
+			}
+			if flagutils.IsSet(flags, "log-elapsed") {
+				configuration.Spec.LogElapsed = options.logElapsed
+			}
+			if flagutils.IsSet(flags, "log-worker") {
+				configuration.Spec.LogWorker = options.logWorker
+			}
+			if flagutils.IsSet(flags, "log-namespace") {
+				configuration.Spec.LogNamespace = options.logNamespace
+			}
+			if flagutils.IsSet(flags, "log-caller") {
+				configuration.Spec.LogCaller = options.logCaller
+			}
+			if flagutils.IsSet(flags, "log-resource-links") {
+				configuration.Spec.LogResourceLinks = options.logResourceLinks
+			}
+			if flagutils.IsSet(flags, "log-hyperlinks") {
+				configuration.Spec.LogHyperlinks = options.logHyperlinks
+			}
+			if flagutils.IsSet(flags, "log-timestamp-format") {
+				configuration.Spec.LogTimestampFormat = options.logTimestampFormat
+			}
+			if flagutils.IsSet(flags, "log-deterministic") {
+				configuration.Spec.LogDeterministic = options.logDeterministic
+			}
+			if flagutils.IsSet(flags, "log-ordinals") {
+				configuration.Spec.LogOrdinals = options.logOrdinals
+			}
+			if flagutils.IsSet(flags, "log-resource-format") {
+				configuration.Spec.LogResourceFormat = options.logResourceFormat
+			}
+			if flagutils.IsSet(flags, "log-resource-list") {
+				configuration.Spec.LogResourceList = options.logResourceList
+			}
+			if flagutils.IsSet(flags, "log-redact-pattern") {
+				configuration.Spec.LogRedactPatterns = options.logRedactPatterns
+			}
+			if flagutils.IsSet(flags, "log-column-widths") {
+				configuration.Spec.LogColumnWidths = options.logColumnWidths
+			}
+			if flagutils.IsSet(flags, "log-continuation") {
+				configuration.Spec.LogContinuation = options.logContinuation
+			}
+			if flagutils.IsSet(flags, "log-verbosity-levels") {
+				configuration.Spec.LogVerbosityLevels = options.logVerbosityLevels
+			}
+			if flagutils.IsSet(flags, "log-buffered") {
+				configuration.Spec.LogBuffered = options.logBuffered
+			}
+			if flagutils.IsSet(flags, "log-buffer-order") {
+				configuration.Spec.LogBufferOrder = options.logBufferOrder
+			}
+			if flagutils.IsSet(flags, "log-dedupe") {
+				configuration.Spec.LogDedupe = options.logDedupe
+			}
+			if flagutils.IsSet(flags, "log-failures-only") {
+				configuration.Spec.LogFailuresOnly = options.logFailuresOnly
+			}
+			if flagutils.IsSet(flags, "log-operation-types") {
+				configuration.Spec.LogOperationTypes = options.logOperationTypes
+			}
+			if flagutils.IsSet(flags, "log-message-max-size") {
+				configuration.Spec.LogMessageMaxSize = &options.logMessageMaxSize
+			}
+			if flagutils.IsSet(flags, "log-diff-max-hunks") {
+				configuration.Spec.LogDiffMaxHunks = &options.logDiffMaxHunks
+			}
+			if flagutils.IsSet(flags, "log-wait-interval") {
+				configuration.Spec.LogWaitInterval = &options.logWaitInterval
+			}
+			if flagutils.IsSet(flags, "event-stream") {
+				configuration.Spec.EventStream = options.eventStream
+			}
+			if flagutils.IsSet(flags, "log-github-groups") {
+				configuration.Spec.LogGitHubGroups = &options.logGitHubGroups
+			}
 			if flagutils.IsSet(flags, "parallel") {
 				configuration.Spec.Parallel = &options.parallel
 			}
 			if flagutils.IsSet(flags, "repeat-count") {
 				configuration.Spec.RepeatCount = &options.repeatCount
 			}
+			if flagutils.IsSet(flags, "iterations") {
+				configuration.Spec.Iterations = options.iterations
+			}
+			if flagutils.IsSet(flags, "iterations-filter") {
+				configuration.Spec.IterationsFilter = options.iterationsFilter
+			}
+			if flagutils.IsSet(flags, "pass-rate") {
+				configuration.Spec.PassRate = options.passRate
+			}
 			if flagutils.IsSet(flags, "report-format") {
 				configuration.Spec.ReportFormat = v1alpha1.ReportFormatType(options.reportFormat)
 			}
@@ -146,6 +366,63 @@ func Command() *cobra.Command {
 			if flagutils.IsSet(flags, "report-name") {
 				configuration.Spec.ReportName = options.reportName
 			}
+			if flagutils.IsSet(flags, "report-strict") {
+				configuration.Spec.ReportStrict = options.reportStrict
+			}
+			if flagutils.IsSet(flags, "report-failures") {
+				configuration.Spec.ReportFailures = options.reportFailures
+			}
+			if flagutils.IsSet(flags, "report-logs") {
+				configuration.Spec.ReportLogs = options.reportLogs
+			}
+			if flagutils.IsSet(flags, "report-logs-failed-only") {
+				configuration.Spec.ReportLogsFailedOnly = options.reportLogsFailedOnly
+			}
+			if flagutils.IsSet(flags, "report-logs-full-messages") {
+				configuration.Spec.ReportLogsFullMessages = options.reportLogsFullMessages
+			}
+			if flagutils.IsSet(flags, "report-logs-max-size") {
+				configuration.Spec.ReportLogsMaxSize = &options.reportLogsMaxSize
+			}
+			if flagutils.IsSet(flags, "report-env") {
+				configuration.Spec.ReportEnv = options.reportEnv
+			}
+			if flagutils.IsSet(flags, "report-group-by") {
+				configuration.Spec.ReportGroupBy = options.reportGroupBy
+			}
+			if flagutils.IsSet(flags, "report-retention-count") {
+				configuration.Spec.ReportRetentionCount = &options.reportRetentionCount
+			}
+			if flagutils.IsSet(flags, "report-retention-max-age") {
+				configuration.Spec.ReportRetentionMaxAge = &options.reportRetentionMaxAge
+			}
+			if flagutils.IsSet(flags, "report-retention-dry-run") {
+				configuration.Spec.ReportRetentionDryRun = options.reportRetentionDryRun
+			}
+			if flagutils.IsSet(flags, "notification-url") {
+				configuration.Spec.NotificationURL = options.notificationURL
+			}
+			if flagutils.IsSet(flags, "notification-format") {
+				configuration.Spec.NotificationFormat = options.notificationFormat
+			}
+			if flagutils.IsSet(flags, "notification-always") {
+				configuration.Spec.NotificationAlways = options.notificationAlways
+			}
+			if flagutils.IsSet(flags, "pushgateway-url") {
+				configuration.Spec.PushgatewayURL = options.pushgatewayURL
+			}
+			if flagutils.IsSet(flags, "pushgateway-job") {
+				configuration.Spec.PushgatewayJob = options.pushgatewayJob
+			}
+			if flagutils.IsSet(flags, "pushgateway-instance") {
+				configuration.Spec.PushgatewayInstance = options.pushgatewayInstance
+			}
+			if flagutils.IsSet(flags, "otlp-endpoint") {
+				configuration.Spec.OTLPEndpoint = options.otlpEndpoint
+			}
+			if flagutils.IsSet(flags, "otlp-protocol") {
+				configuration.Spec.OTLPProtocol = options.otlpProtocol
+			}
 			if flagutils.IsSet(flags, "namespace") {
 				configuration.Spec.Namespace = options.namespace
 			}
@@ -158,6 +435,15 @@ func Command() *cobra.Command {
 			if flagutils.IsSet(flags, "exclude-test-regex") {
 				configuration.Spec.ExcludeTestRegex = options.excludeTestRegex
 			}
+			if flagutils.IsSet(flags, "quarantine") {
+				configuration.Spec.Quarantine = options.quarantine
+			}
+			if flagutils.IsSet(flags, "quarantine-selector") {
+				if _, err := labels.Parse(options.quarantineSelector); err != nil {
+					return fmt.Errorf("invalid quarantine selector: %w", err)
+				}
+				configuration.Spec.QuarantineSelector = options.quarantineSelector
+			}
 			if flagutils.IsSet(flags, "force-termination-grace-period") {
 				configuration.Spec.ForceTerminationGracePeriod = &options.forceTerminationGracePeriod
 			}
@@ -192,6 +478,24 @@ func Command() *cobra.Command {
 				}
 			}
 			options.testDirs = append(options.testDirs, args...)
+			var rerun *report.TestSelection
+			if options.rerunFailed != "" {
+				selection, err := report.LoadFailedTestSelection(options.rerunFailed)
+				if err != nil {
+					return err
+				}
+				for _, warning := range selection.Warnings {
+					fmt.Fprintf(out, "WARNING: %s\n", warning)
+				}
+				if len(selection.Tests) == 0 {
+					fmt.Fprintf(out, "No failed test to rerun in %s\n", options.rerunFailed)
+				}
+				// tests are discovered from their own folders when the report records them
+				if paths := selection.Paths(); paths != nil {
+					options.testDirs = paths
+				}
+				rerun = &selection
+			}
 			if len(options.testDirs) == 0 {
 				options.testDirs = append(options.testDirs, ".")
 			}
@@ -199,15 +503,183 @@ func Command() *cobra.Command {
 			fmt.Fprintf(out, "- TestDirs %v\n", options.testDirs)
 			fmt.Fprintf(out, "- SkipDelete %v\n", configuration.Spec.SkipDelete)
 			fmt.Fprintf(out, "- FailFast %v\n", configuration.Spec.FailFast)
+			if configuration.Spec.FailFastCancel {
+				fmt.Fprintf(out, "- FailFastCancel %v\n", configuration.Spec.FailFastCancel)
+			}
+			if configuration.Spec.DryRun {
+				fmt.Fprintf(out, "- DryRun %v\n", configuration.Spec.DryRun)
+			}
+			if configuration.Spec.Dashboard {
+				fmt.Fprintf(out, "- Dashboard %v\n", configuration.Spec.Dashboard)
+			}
+			if configuration.Spec.Progress {
+				fmt.Fprintf(out, "- Progress %v\n", configuration.Spec.Progress)
+			}
+			if configuration.Spec.Quiet {
+				fmt.Fprintf(out, "- Quiet %v\n", configuration.Spec.Quiet)
+			}
+			if configuration.Spec.ProgressInterval != nil {
+				fmt.Fprintf(out, "- ProgressInterval %v\n", configuration.Spec.ProgressInterval.Duration)
+			}
+			if configuration.Spec.LogFormat != "" {
+				fmt.Fprintf(out, "- LogFormat '%v'\n", configuration.Spec.LogFormat)
+			}
+			if configuration.Spec.LogJSONPath != "" {
+				fmt.Fprintf(out, "- LogJSONPath '%v'\n", configuration.Spec.LogJSONPath)
+			}
+			if configuration.Spec.LogSyslog != "" {
+				fmt.Fprintf(out, "- LogSyslog %v\n", configuration.Spec.LogSyslog)
+			}
+			if configuration.Spec.LogFile != "" {
+				fmt.Fprintf(out, "- LogFile '%v'\n", configuration.Spec.LogFile)
+			}
+			if configuration.Spec.LogFilePerTest {
+				fmt.Fprintf(out, "- LogFilePerTest %v\n", configuration.Spec.LogFilePerTest)
+			}
+			if configuration.Spec.LogFileMaxSize != 0 {
+				fmt.Fprintf(out, "- LogFileMaxSize %d\n", configuration.Spec.LogFileMaxSize)
+			}
+			if configuration.Spec.LogFileMaxBackups != 0 {
+				fmt.Fprintf(out, "- LogFileMaxBackups %d\n", configuration.Spec.LogFileMaxBackups)
+			}
+			if configuration.Spec.LogFileCompress {
+				fmt.Fprintf(out, "- LogFileCompress %v\n", configuration.Spec.LogFileCompress)
+			}
+			if configuration.Spec.LogElapsed {
+				fmt.Fprintf(out, "- LogElapsed %v\n", configuration.Spec.LogElapsed)
+			}
+			if configuration.Spec.LogWorker {
+				fmt.Fprintf(out, "- LogWorker %v\n", configuration.Spec.LogWorker)
+			}
+			if configuration.Spec.LogNamespace {
+				fmt.Fprintf(out, "- LogNamespace %v\n", configuration.Spec.LogNamespace)
+			}
+			if configuration.Spec.LogCaller != "" {
+				fmt.Fprintf(out, "- LogCaller %v\n", configuration.Spec.LogCaller)
+			}
+			if configuration.Spec.LogResourceLinks != "" {
+				fmt.Fprintf(out, "- LogResourceLinks %v\n", configuration.Spec.LogResourceLinks)
+			}
+			if configuration.Spec.LogHyperlinks != "" {
+				fmt.Fprintf(out, "- LogHyperlinks %v\n", configuration.Spec.LogHyperlinks)
+			}
+			if configuration.Spec.LogTimestampFormat != "" {
+				fmt.Fprintf(out, "- LogTimestampFormat %v\n", configuration.Spec.LogTimestampFormat)
+			}
+			if configuration.Spec.LogDeterministic {
+				fmt.Fprintf(out, "- LogDeterministic %v\n", configuration.Spec.LogDeterministic)
+			}
+			if configuration.Spec.LogOrdinals {
+				fmt.Fprintf(out, "- LogOrdinals %v\n", configuration.Spec.LogOrdinals)
+			}
+			if configuration.Spec.LogResourceFormat != "" {
+				fmt.Fprintf(out, "- LogResourceFormat %v\n", configuration.Spec.LogResourceFormat)
+			}
+			if configuration.Spec.LogResourceList != "" {
+				fmt.Fprintf(out, "- LogResourceList %v\n", configuration.Spec.LogResourceList)
+			}
+			if len(configuration.Spec.LogRedactPatterns) != 0 {
+				fmt.Fprintf(out, "- LogRedactPatterns %v\n", configuration.Spec.LogRedactPatterns)
+			}
+			if configuration.Spec.LogColumnWidths != "" {
+				fmt.Fprintf(out, "- LogColumnWidths %v\n", configuration.Spec.LogColumnWidths)
+			}
+			if configuration.Spec.LogContinuation != "" {
+				fmt.Fprintf(out, "- LogContinuation %v\n", configuration.Spec.LogContinuation)
+			}
+			if configuration.Spec.LogVerbosityLevels != "" {
+				fmt.Fprintf(out, "- LogVerbosityLevels %v\n", configuration.Spec.LogVerbosityLevels)
+			}
+			if configuration.Spec.LogBuffered {
+				fmt.Fprintf(out, "- LogBuffered %v\n", configuration.Spec.LogBuffered)
+			}
+			if configuration.Spec.LogBufferOrder != "" {
+				fmt.Fprintf(out, "- LogBufferOrder %v\n", configuration.Spec.LogBufferOrder)
+			}
+			if configuration.Spec.LogDedupe {
+				fmt.Fprintf(out, "- LogDedupe %v\n", configuration.Spec.LogDedupe)
+			}
+			if configuration.Spec.LogFailuresOnly {
+				fmt.Fprintf(out, "- LogFailuresOnly %v\n", configuration.Spec.LogFailuresOnly)
+			}
+			if len(configuration.Spec.LogOperationTypes) != 0 {
+				fmt.Fprintf(out, "- LogOperationTypes %v\n", configuration.Spec.LogOperationTypes)
+			}
+			if configuration.Spec.LogMessageMaxSize != nil {
+				fmt.Fprintf(out, "- LogMessageMaxSize %d\n", *configuration.Spec.LogMessageMaxSize)
+			}
+			if configuration.Spec.LogDiffMaxHunks != nil {
+				fmt.Fprintf(out, "- LogDiffMaxHunks %d\n", *configuration.Spec.LogDiffMaxHunks)
+			}
+			if configuration.Spec.LogWaitInterval != nil {
+				fmt.Fprintf(out, "- LogWaitInterval %v\n", configuration.Spec.LogWaitInterval.Duration)
+			}
+			if configuration.Spec.EventStream != "" {
+				fmt.Fprintf(out, "- EventStream %v\n", configuration.Spec.EventStream)
+			}
+			if configuration.Spec.LogGitHubGroups != nil {
+				fmt.Fprintf(out, "- LogGitHubGroups %v\n", *configuration.Spec.LogGitHubGroups)
+			}
 			fmt.Fprintf(out, "- ReportFormat '%v'\n", configuration.Spec.ReportFormat)
 			fmt.Fprintf(out, "- ReportName '%v'\n", configuration.Spec.ReportName)
 			if configuration.Spec.ReportPath != "" {
 				fmt.Fprintf(out, "- ReportPath '%v'\n", configuration.Spec.ReportPath)
 			}
+			if configuration.Spec.ReportStrict {
+				fmt.Fprintf(out, "- ReportStrict %v\n", configuration.Spec.ReportStrict)
+			}
+			if configuration.Spec.ReportFailures {
+				fmt.Fprintf(out, "- ReportFailures %v\n", configuration.Spec.ReportFailures)
+			}
+			if configuration.Spec.ReportLogs {
+				fmt.Fprintf(out, "- ReportLogs %v\n", configuration.Spec.ReportLogs)
+				if configuration.Spec.ReportLogsFailedOnly {
+					fmt.Fprintf(out, "- ReportLogsFailedOnly %v\n", configuration.Spec.ReportLogsFailedOnly)
+				}
+				if configuration.Spec.ReportLogsFullMessages {
+					fmt.Fprintf(out, "- ReportLogsFullMessages %v\n", configuration.Spec.ReportLogsFullMessages)
+				}
+				if configuration.Spec.ReportLogsMaxSize != nil {
+					fmt.Fprintf(out, "- ReportLogsMaxSize %d\n", *configuration.Spec.ReportLogsMaxSize)
+				}
+			}
+			if len(configuration.Spec.ReportEnv) != 0 {
+				fmt.Fprintf(out, "- ReportEnv %v\n", configuration.Spec.ReportEnv)
+			}
+			if configuration.Spec.ReportGroupBy != "" {
+				fmt.Fprintf(out, "- ReportGroupBy %s\n", configuration.Spec.ReportGroupBy)
+			}
+			if configuration.Spec.ReportRetentionCount != nil {
+				fmt.Fprintf(out, "- ReportRetentionCount %d\n", *configuration.Spec.ReportRetentionCount)
+			}
+			if configuration.Spec.ReportRetentionMaxAge != nil {
+				fmt.Fprintf(out, "- ReportRetentionMaxAge %v\n", configuration.Spec.ReportRetentionMaxAge.Duration)
+			}
+			if configuration.Spec.ReportRetentionDryRun {
+				fmt.Fprintf(out, "- ReportRetentionDryRun %v\n", configuration.Spec.ReportRetentionDryRun)
+			}
+			if configuration.Spec.NotificationURL != "" {
+				fmt.Fprintf(out, "- NotificationFormat '%v'\n", configuration.Spec.NotificationFormat)
+				fmt.Fprintf(out, "- NotificationAlways %v\n", configuration.Spec.NotificationAlways)
+			}
+			if configuration.Spec.PushgatewayURL != "" {
+				fmt.Fprintf(out, "- PushgatewayJob '%v'\n", configuration.Spec.PushgatewayJob)
+				fmt.Fprintf(out, "- PushgatewayInstance '%v'\n", configuration.Spec.PushgatewayInstance)
+			}
+			if configuration.Spec.OTLPEndpoint != "" {
+				fmt.Fprintf(out, "- OTLPEndpoint '%v'\n", configuration.Spec.OTLPEndpoint)
+				fmt.Fprintf(out, "- OTLPProtocol '%v'\n", configuration.Spec.OTLPProtocol)
+			}
 			fmt.Fprintf(out, "- Namespace '%v'\n", configuration.Spec.Namespace)
 			fmt.Fprintf(out, "- FullName %v\n", configuration.Spec.FullName)
 			fmt.Fprintf(out, "- IncludeTestRegex '%v'\n", configuration.Spec.IncludeTestRegex)
 			fmt.Fprintf(out, "- ExcludeTestRegex '%v'\n", configuration.Spec.ExcludeTestRegex)
+			if len(configuration.Spec.Quarantine) != 0 {
+				fmt.Fprintf(out, "- Quarantine %v\n", configuration.Spec.Quarantine)
+			}
+			if configuration.Spec.QuarantineSelector != "" {
+				fmt.Fprintf(out, "- QuarantineSelector %s\n", configuration.Spec.QuarantineSelector)
+			}
 			fmt.Fprintf(out, "- ApplyTimeout %v\n", configuration.Spec.Timeouts.ApplyDuration())
 			fmt.Fprintf(out, "- AssertTimeout %v\n", configuration.Spec.Timeouts.AssertDuration())
 			fmt.Fprintf(out, "- CleanupTimeout %v\n", configuration.Spec.Timeouts.CleanupDuration())
@@ -220,6 +692,15 @@ func Command() *cobra.Command {
 			if configuration.Spec.RepeatCount != nil {
 				fmt.Fprintf(out, "- RepeatCount %v\n", *configuration.Spec.RepeatCount)
 			}
+			if configuration.Spec.Iterations != 0 {
+				fmt.Fprintf(out, "- Iterations %v\n", configuration.Spec.Iterations)
+			}
+			if configuration.Spec.IterationsFilter != "" {
+				fmt.Fprintf(out, "- IterationsFilter %v\n", configuration.Spec.IterationsFilter)
+			}
+			if configuration.Spec.PassRate != 0 {
+				fmt.Fprintf(out, "- PassRate %v\n", configuration.Spec.PassRate)
+			}
 			if configuration.Spec.ForceTerminationGracePeriod != nil {
 				fmt.Fprintf(out, "- ForceTerminationGracePeriod %v\n", configuration.Spec.ForceTerminationGracePeriod.Duration)
 			}
@@ -229,6 +710,9 @@ func Command() *cobra.Command {
 			if len(options.selector) != 0 {
 				fmt.Fprintf(out, "- Selector %v\n", options.selector)
 			}
+			if options.rerunFailed != "" {
+				fmt.Fprintf(out, "- RerunFailed %s\n", options.rerunFailed)
+			}
 			if len(options.values) != 0 {
 				fmt.Fprintf(out, "- Values %v\n", options.values)
 			}
@@ -258,6 +742,9 @@ func Command() *cobra.Command {
 			}
 			var testToRun []discovery.Test
 			for _, test := range tests {
+				if rerun != nil && !rerun.Matches(test.Name, test.BasePath) {
+					continue
+				}
 				if test.Err != nil {
 					fmt.Fprintf(out, "- %s (%s) - (%s)\n", test.Name, test.BasePath, test.Err)
 				} else {
@@ -276,17 +763,39 @@ func Command() *cobra.Command {
 			var restConfig *rest.Config
 			if !options.noCluster {
 				cfg, err := restutils.DefaultConfig(options.kubeConfigOverrides)
+				// in dry run mode the cluster is optional, resources are only rendered without it
+				if configuration.Spec.DryRun {
+					if err == nil {
+						err = restutils.Reachable(cfg, dryRunReachableTimeout)
+					}
+					if err != nil {
+						fmt.Fprintln(out, "- Dry run without a cluster, resources are not validated by the server:", err)
+						cfg, err = nil, nil
+					}
+				}
 				if err != nil {
 					return err
 				}
 				restConfig = cfg
 			}
 			summary, err := runner.Run(restConfig, clock, configuration.Spec, values, testToRun...)
+			// a failed report upload only fails the run when the sink says so
+			err = uploadWarnings(cmd.ErrOrStderr(), err)
+			// when the report is written to stdout, keep human readable output out of it
+			if configuration.Spec.ReportFormat != "" && configuration.Spec.ReportName == report.StdoutName {
+				out = cmd.ErrOrStderr()
+			}
 			if summary != nil {
 				fmt.Fprintln(out, "Tests Summary...")
 				fmt.Fprintln(out, "- Passed  tests", summary.Passed())
 				fmt.Fprintln(out, "- Failed  tests", summary.Failed())
 				fmt.Fprintln(out, "- Skipped tests", summary.Skipped())
+				if summary.Quarantined() > 0 {
+					fmt.Fprintln(out, "- Quarantined failed tests", summary.Quarantined())
+				}
+				if summary.Tolerated() > 0 {
+					fmt.Fprintln(out, "- Tolerated failed iterations", summary.Tolerated())
+				}
 			}
 			if err != nil {
 				fmt.Fprintln(out, "Done with error.")
@@ -311,19 +820,85 @@ func Command() *cobra.Command {
 	cmd.Flags().BoolVar(&options.skipDelete, "skip-delete", false, "If set, do not delete the resources after running the tests")
 	cmd.Flags().BoolVar(&options.template, "template", template.DefaultTemplate, "If set, resources will be considered for templating")
 	cmd.Flags().BoolVar(&options.failFast, "fail-fast", false, "Stop the test upon encountering the first failure")
+	cmd.Flags().BoolVar(&options.failFastCancel, "fail-fast-cancel", false, "Cancel the running tests when a test fails in fail fast mode")
+	cmd.Flags().BoolVar(&options.dryRun, "dry-run", false, "Validate the tests without modifying the cluster, operations not creating or modifying resources are skipped")
+	cmd.Flags().BoolVar(&options.dashboard, "dashboard", false, "Render the progress of the run live while tests run")
+	cmd.Flags().BoolVar(&options.progress, "progress", false, "Print the number of completed and failed tests while tests run")
+	cmd.Flags().BoolVar(&options.quiet, "quiet", false, "Disable the progress line, for output parsed by machines")
+	cmd.Flags().DurationVar(&options.progressInterval.Duration, "progress-interval", 0, "Print the progress periodically instead of after each test completion")
+	cmd.Flags().StringVar(&options.logFormat, "log-format", "", "Format of the test logs printed on the console (text|json)")
+	cmd.Flags().StringVar(&options.logJSONPath, "log-json-path", "", "File the test logs are written to as JSON lines")
+	cmd.Flags().StringVar(&options.logSyslog, "log-syslog", "", "Also send the log lines to a syslog daemon, either local or a URL like unix:///dev/log, udp://host:514 or tcp://host:601")
+	cmd.Flags().StringVar(&options.logFile, "log-file", "", "File a copy of the test logs is written to, without colors")
+	cmd.Flags().BoolVar(&options.logFilePerTest, "log-file-per-test", false, "Write the logs of each test to its own file, the log file is then a folder")
+	cmd.Flags().IntVar(&options.logFileMaxSize, "log-file-max-size", 0, "Size in megabytes the log files are rotated at (0 disables rotation)")
+	cmd.Flags().IntVar(&options.logFileMaxBackups, "log-file-max-backups", 0, "Number of rotated log files kept, the oldest are removed (0 keeps them all)")
+	cmd.Flags().BoolVar(&options.logFileCompress, "log-file-compress", false, "Compress rotated log files with gzip")
+	cmd.Flags().BoolVar(&options.logElapsed, "log-elapsed", false, "Print the time elapsed since the start of the test and of the operation in each log line")
+	cmd.Flags().BoolVar(&options.logWorker, "log-worker", false, "Print the worker slot the test runs in, like w03, in each log line")
+	cmd.Flags().BoolVar(&options.logNamespace, "log-namespace", false, "Print the namespace of the test, like [chainsaw-happy-cat], in each log line")
+	cmd.Flags().StringVar(&options.logCaller, "log-caller", "", "Print the location of the runner code logging the lines at or above the given level (error|warn)")
+	cmd.Flags().StringVar(&options.logResourceLinks, "log-resource-links", "", "URL template making the resources of log lines hyperlinks on the terminal, like https://dashboard/#/{{.Kind}}/{{.Namespace}}/{{.Name}}")
+	cmd.Flags().StringVar(&options.logHyperlinks, "log-hyperlinks", "", "Whether resources are hyperlinks when --log-resource-links is set (auto|always|never)")
+	cmd.Flags().StringVar(&options.logTimestampFormat, "log-timestamp-format", "", "Layout of the log timestamps, a Go reference layout, a standard layout name like RFC3339Nano, or none")
+	cmd.Flags().BoolVar(&options.logDeterministic, "log-deterministic", false, "Render the same logs on every identical run, without timestamps, colors or durations, to compare them with golden files")
+	cmd.Flags().BoolVar(&options.logOrdinals, "log-ordinals", false, "Number the steps and the operations in the log prefixes after their names too, unnamed ones are always numbered")
+	cmd.Flags().StringVar(&options.logResourceFormat, "log-resource-format", "", "Format of the resources in the test logs (full|namespaced|short)")
+	cmd.Flags().StringVar(&options.logResourceList, "log-resource-list", "", "Rendering of the resources of log lines about several resources (count|list)")
+	cmd.Flags().StringSliceVar(&options.logRedactPatterns, "log-redact-pattern", nil, "Regular expressions whose matches are redacted from the test logs, in addition to bearer tokens, AWS keys and secret looking values")
+	cmd.Flags().StringVar(&options.logColumnWidths, "log-column-widths", "", "Align the test, step and operation columns of the test logs, either default or overrides like test=40,step=20")
+	cmd.Flags().StringVar(&options.logContinuation, "log-continuation", "", "Rendering of the continuation lines of multi-line messages in the test logs (none|prefix|indent)")
+	cmd.Flags().StringVar(&options.logVerbosityLevels, "log-verbosity-levels", "", "Map the V-levels of klog and logr lines, like the messages of client-go, to log levels, either default or a list like 0=info,4=debug")
+	cmd.Flags().BoolVar(&options.logBuffered, "log-buffered", false, "Buffer the logs of tests running concurrently and print each test as a block when it completes")
+	cmd.Flags().StringVar(&options.logBufferOrder, "log-buffer-order", "", "Order in which buffered tests are printed (completion|declaration)")
+	cmd.Flags().BoolVar(&options.logDedupe, "log-dedupe", false, "Collapse consecutive identical log lines of an operation into one")
+	cmd.Flags().BoolVar(&options.logFailuresOnly, "log-failures-only", false, "Print the logs of failed tests only, passing tests print nothing")
+	cmd.Flags().StringSliceVar(&options.logOperationTypes, "log-operation-types", nil, "Operation types whose log lines are printed on the console, like script,command, other lines are still captured in reports")
+	cmd.Flags().IntVar(&options.logMessageMaxSize, "log-message-max-size", 16384, "Maximum size in bytes of the message of a log line, longer messages are truncated (0 keeps messages whole)")
+	cmd.Flags().IntVar(&options.logDiffMaxHunks, "log-diff-max-hunks", 10, "Maximum number of hunks of the diffs logged when an assertion fails (0 keeps diffs whole)")
+	cmd.Flags().DurationVar(&options.logWaitInterval.Duration, "log-wait-interval", 0, "Interval at which operations waiting for a resource, like asserts, log that they are still waiting, 30s by default (0 disables it)")
+	cmd.Flags().StringVar(&options.eventStream, "event-stream", "", "Write the events of the run as JSON lines to a file, or a file descriptor with fd:<n>")
+	cmd.Flags().BoolVar(&options.logGitHubGroups, "log-github-groups", false, "Group the logs of each test in GitHub Actions, enabled by default when GITHUB_ACTIONS is true")
 	cmd.Flags().IntVar(&options.parallel, "parallel", 0, "The maximum number of tests to run at once")
 	cmd.Flags().IntVar(&options.repeatCount, "repeat-count", 1, "Number of times to repeat each test")
+	cmd.Flags().IntVar(&options.iterations, "iterations", 0, "Number of iterations of the tests matching the iterations filter, each in its own namespace")
+	cmd.Flags().StringVar(&options.iterationsFilter, "iterations-filter", "", "Regular expression matching the names of the tests the iterations apply to")
+	cmd.Flags().IntVar(&options.passRate, "pass-rate", 0, "Minimum percentage of passed iterations of a test, any failed iteration fails the run if not set")
 	cmd.Flags().StringVar(&options.reportFormat, "report-format", "", "Test report format (JSON|XML|nil)")
-	cmd.Flags().StringVar(&options.reportName, "report-name", "chainsaw-report", "The name of the report to create")
+	cmd.Flags().StringVar(&options.reportName, "report-name", "chainsaw-report", "The name of the report to create (use - to write the report to stdout)")
 	cmd.Flags().StringVar(&options.reportPath, "report-path", "", "The path of the report to create")
+	cmd.Flags().BoolVar(&options.reportStrict, "report-strict", false, "Fail the run if the generated report is inconsistent")
+	cmd.Flags().BoolVar(&options.reportFailures, "report-failures", false, "Also write a report containing only the failed tests")
+	cmd.Flags().BoolVar(&options.reportLogs, "report-logs", false, "Embed the console output of each test in the report")
+	cmd.Flags().BoolVar(&options.reportLogsFailedOnly, "report-logs-failed-only", false, "Only embed the console output of failed tests in the report")
+	cmd.Flags().BoolVar(&options.reportLogsFullMessages, "report-logs-full-messages", false, "Keep the full text of truncated log messages in the console output embedded in the report")
+	cmd.Flags().IntVar(&options.reportLogsMaxSize, "report-logs-max-size", 65536, "Maximum size in bytes of the console output embedded for a test")
+	cmd.Flags().StringSliceVar(&options.reportEnv, "report-env", nil, "Names of environment variables recorded in the report of each test, secret looking values are redacted")
+	cmd.Flags().StringVar(&options.reportGroupBy, "report-group-by", "", "Group tests into nested suites in XML reports (directory|label:<key>|prefix:<separator>)")
+	cmd.Flags().IntVar(&options.reportRetentionCount, "report-retention-count", 0, "Only keep the given number of newest reports generated from a templated report name")
+	cmd.Flags().DurationVar(&options.reportRetentionMaxAge.Duration, "report-retention-max-age", 0, "Remove the reports generated from a templated report name older than the given duration")
+	cmd.Flags().BoolVar(&options.reportRetentionDryRun, "report-retention-dry-run", false, "Print the reports the retention would remove without removing them")
+	cmd.Flags().StringVar(&options.notificationURL, "notification-url", "", "Webhook to post a summary of the run to when tests fail")
+	cmd.Flags().StringVar(&options.notificationFormat, "notification-format", "", "Notification payload format (slack|webhook)")
+	cmd.Flags().BoolVar(&options.notificationAlways, "notification-always", false, "Send the notification even when all tests passed")
+	cmd.Flags().StringVar(&options.pushgatewayURL, "pushgateway-url", "", "Prometheus Pushgateway to push the metrics of the run to")
+	cmd.Flags().StringVar(&options.pushgatewayJob, "pushgateway-job", "", "Job label of the metrics pushed to the Pushgateway")
+	cmd.Flags().StringVar(&options.pushgatewayInstance, "pushgateway-instance", "", "Instance label of the metrics pushed to the Pushgateway (defaults to the suite name)")
+	cmd.Flags().StringVar(&options.otlpEndpoint, "otlp-endpoint", "", "OpenTelemetry collector to export the trace of the run to")
+	cmd.Flags().StringVar(&options.otlpProtocol, "otlp-protocol", "", "OTLP protocol (grpc|http)")
 	cmd.Flags().StringVar(&options.namespace, "namespace", "", "Namespace to use for tests")
 	cmd.Flags().BoolVar(&options.fullName, "full-name", false, "Use full test case folder path instead of folder name")
 	cmd.Flags().StringVar(&options.includeTestRegex, "include-test-regex", "", "Regular expression to include tests")
 	cmd.Flags().StringVar(&options.excludeTestRegex, "exclude-test-regex", "", "Regular expression to exclude tests")
+	cmd.Flags().StringSliceVar(&options.quarantine, "quarantine", nil, "Names of known flaky tests, their failures don't fail the run")
+	cmd.Flags().StringVar(&options.quarantineSelector, "quarantine-selector", "", "Selector (label query) matching known flaky tests, their failures don't fail the run")
 	cmd.Flags().BoolVar(&options.noColor, "no-color", false, "Removes output colors")
+	cmd.Flags().StringVar(&options.color, "color", string(logging.ColorAuto), "Colorize the output (auto|always|never), auto disables colors when NO_COLOR is set or the output is not a terminal")
+	cmd.Flags().StringVar(&options.glyphs, "glyphs", string(logging.GlyphAuto), "Glyphs marking the outcome of operations in the logs (auto|unicode|ascii), auto uses unicode on terminals with a UTF-8 locale")
 	cmd.Flags().DurationVar(&options.forceTerminationGracePeriod.Duration, "force-termination-grace-period", 0, "If specified, overrides termination grace periods in applicable resources")
 	cmd.Flags().DurationVar(&options.delayBeforeCleanup.Duration, "cleanup-delay", 0, "Adds a delay between the time a test ends and the time cleanup starts")
 	cmd.Flags().StringSliceVar(&options.selector, "selector", nil, "Selector (label query) to filter on")
+	cmd.Flags().StringVar(&options.rerunFailed, "rerun-failed", "", "Only run the tests that failed in the given JSON or XML report")
 	cmd.Flags().BoolVar(&options.noCluster, "no-cluster", false, "Runs without cluster")
 	cmd.Flags().StringSliceVar(&options.values, "values", nil, "Values passed to the tests")
 	cmd.Flags().StringSliceVar(&options.clusters, "cluster", nil, "Register cluster (format <cluster name>=<kubeconfig path>:[context name])")
@@ -333,3 +908,21 @@ func Command() *cobra.Command {
 	}
 	return cmd
 }
+
+// uploadWarnings prints the non fatal upload errors held by err as warnings and returns the other errors.
+func uploadWarnings(out io.Writer, err error) error {
+	errs := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+	var remaining []error
+	for _, err := range errs {
+		var uploadErr *report.UploadError
+		if errors.As(err, &uploadErr) && !uploadErr.Fatal {
+			fmt.Fprintln(out, "WARNING:", err)
+		} else {
+			remaining = append(remaining, err)
+		}
+	}
+	return errors.Join(remaining...)
+}
@@ -2,11 +2,15 @@ package test
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/kyverno/chainsaw/pkg/report"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -177,3 +181,15 @@ func TestChainsawCommand(t *testing.T) {
 		})
 	}
 }
+
+func Test_uploadWarnings(t *testing.T) {
+	notification := fmt.Errorf("failed to send notification: %w", &report.UploadError{StatusCode: 500})
+	push := fmt.Errorf("failed to push metrics: %w", &report.UploadError{StatusCode: 502, Fatal: true})
+	var out bytes.Buffer
+	assert.NoError(t, uploadWarnings(&out, nil))
+	assert.NoError(t, uploadWarnings(&out, notification))
+	// only the fatal errors of the sinks are returned
+	err := uploadWarnings(&out, errors.Join(notification, push))
+	assert.Equal(t, push.Error(), err.Error())
+	assert.Equal(t, strings.Repeat("WARNING: "+notification.Error()+"\n", 2), out.String())
+}
@@ -0,0 +1,132 @@
+package report
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// ChecksumExtension is appended to the report file name to name its checksum file.
+	ChecksumExtension = ".sha256"
+	// SignatureExtension is appended to the report file name to name its signature file.
+	SignatureExtension = ".sig"
+)
+
+var (
+	// ErrChecksumMismatch is returned when a report doesn't match its checksum file.
+	ErrChecksumMismatch = errors.New("report checksum mismatch")
+	// ErrSignatureMismatch is returned when a report doesn't match its signature file.
+	ErrSignatureMismatch = errors.New("report signature mismatch")
+)
+
+func sign(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// sidecars hashes the bytes of a report as they are written, for its checksum and signature files.
+type sidecars struct {
+	checksum  hash.Hash
+	signature hash.Hash
+}
+
+// sidecars returns the hashes of the checksum and signature files configured by the options.
+func (o SaveOptions) sidecars() *sidecars {
+	var s sidecars
+	if o.Checksum {
+		s.checksum = sha256.New()
+	}
+	if len(o.SigningKey) != 0 {
+		s.signature = hmac.New(sha256.New, o.SigningKey)
+	}
+	return &s
+}
+
+func (s *sidecars) Write(p []byte) (int, error) {
+	// hashes never return an error
+	if s.checksum != nil {
+		s.checksum.Write(p)
+	}
+	if s.signature != nil {
+		s.signature.Write(p)
+	}
+	return len(p), nil
+}
+
+// write writes the checksum file, in the sha256sum format, and the signature file, the hex encoded HMAC-SHA256, of the
+// bytes written to the given file.
+func (s *sidecars) write(ctx context.Context, sink ReportSink, filePath string) error {
+	if s.checksum != nil {
+		content := fmt.Sprintf("%s  %s\n", hex.EncodeToString(s.checksum.Sum(nil)), filepath.Base(filePath))
+		if err := sink.Write(ctx, filePath+ChecksumExtension, []byte(content)); err != nil {
+			return err
+		}
+	}
+	if s.signature != nil {
+		if err := sink.Write(ctx, filePath+SignatureExtension, []byte(hex.EncodeToString(s.signature.Sum(nil))+"\n")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasSidecars returns true if the options require checksum or signature files.
+func (o SaveOptions) hasSidecars() bool {
+	return o.Checksum || len(o.SigningKey) != 0
+}
+
+// VerifyChecksum checks the report file against the SHA-256 recorded in the checksum file.
+// It returns ErrChecksumMismatch if the report was modified after the checksum was computed.
+func VerifyChecksum(reportPath, sumPath string) error {
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		return err
+	}
+	sum, err := os.ReadFile(sumPath)
+	if err != nil {
+		return err
+	}
+	fields := strings.Fields(string(sum))
+	if len(fields) == 0 {
+		return fmt.Errorf("checksum file %s is empty", sumPath)
+	}
+	expected, err := hex.DecodeString(fields[0])
+	if err != nil {
+		return fmt.Errorf("failed to parse checksum file %s: %w", sumPath, err)
+	}
+	actual := sha256.Sum256(data)
+	if !hmac.Equal(expected, actual[:]) {
+		return fmt.Errorf("%w: %s", ErrChecksumMismatch, reportPath)
+	}
+	return nil
+}
+
+// VerifySignature checks the report file against the HMAC-SHA256 signature recorded in the signature file.
+// It returns ErrSignatureMismatch if the report was modified or signed with another key.
+func VerifySignature(reportPath, sigPath string, key []byte) error {
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		return err
+	}
+	sig, err := os.ReadFile(sigPath)
+	if err != nil {
+		return err
+	}
+	expected, err := hex.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil {
+		return fmt.Errorf("failed to parse signature file %s: %w", sigPath, err)
+	}
+	if !hmac.Equal(expected, sign(key, data)) {
+		return fmt.Errorf("%w: %s", ErrSignatureMismatch, reportPath)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecksum_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  SaveOptions
+		fileName string
+	}{{
+		name:     "plain",
+		options:  SaveOptions{Checksum: true},
+		fileName: "chainsaw-report.json",
+	}, {
+		name:     "compressed",
+		options:  SaveOptions{Checksum: true, Compress: true},
+		fileName: "chainsaw-report.json.gz",
+	}, {
+		name:     "signed",
+		options:  SaveOptions{Checksum: true, SigningKey: []byte("secret")},
+		fileName: "chainsaw-report.json",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			report := syntheticReport(3)
+			path := filepath.Join(dir, "chainsaw-report.json")
+			assert.NoError(t, SaveReportWithOptions(report, JSONSerializer{}, path, tt.options))
+			reportPath := filepath.Join(dir, tt.fileName)
+			sum, err := os.ReadFile(reportPath + ChecksumExtension)
+			assert.NoError(t, err)
+			assert.True(t, strings.HasSuffix(string(sum), "  "+tt.fileName+"\n"))
+			assert.NoError(t, VerifyChecksum(reportPath, reportPath+ChecksumExtension))
+			if tt.options.SigningKey != nil {
+				assert.NoError(t, VerifySignature(reportPath, reportPath+SignatureExtension, tt.options.SigningKey))
+				assert.ErrorIs(t, VerifySignature(reportPath, reportPath+SignatureExtension, []byte("other")), ErrSignatureMismatch)
+			} else {
+				_, err := os.Stat(reportPath + SignatureExtension)
+				assert.True(t, os.IsNotExist(err))
+			}
+		})
+	}
+}
+
+func TestChecksum_Tampered(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "chainsaw-report.xml")
+	key := []byte("secret")
+	assert.NoError(t, SaveReportWithOptions(syntheticReport(3), XMLSerializer{}, path, SaveOptions{Checksum: true, SigningKey: key}))
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	tampered := strings.Replace(string(data), `failures="0"`, `failures="1"`, 1)
+	assert.NotEqual(t, string(data), tampered)
+	assert.NoError(t, os.WriteFile(path, []byte(tampered), 0o600))
+	assert.ErrorIs(t, VerifyChecksum(path, path+ChecksumExtension), ErrChecksumMismatch)
+	assert.ErrorIs(t, VerifySignature(path, path+SignatureExtension, key), ErrSignatureMismatch)
+}
+
+func TestChecksum_Errors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "chainsaw-report.json")
+	assert.NoError(t, os.WriteFile(path, []byte("{}"), 0o600))
+	assert.Error(t, VerifyChecksum(path, filepath.Join(dir, "missing.sha256")))
+	assert.NoError(t, os.WriteFile(path+ChecksumExtension, nil, 0o600))
+	assert.Error(t, VerifyChecksum(path, path+ChecksumExtension))
+	assert.NoError(t, os.WriteFile(path+ChecksumExtension, []byte("not-hex  chainsaw-report.json\n"), 0o600))
+	assert.Error(t, VerifyChecksum(path, path+ChecksumExtension))
+	assert.Error(t, SaveReportWithOptions(syntheticReport(1), JSONSerializer{}, StdoutName, SaveOptions{Checksum: true}))
+	assert.Error(t, SaveReportWithOptions(syntheticReport(1), JSONSerializer{}, "https://example.com/upload", SaveOptions{SigningKey: []byte("secret")}))
+}
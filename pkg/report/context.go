@@ -0,0 +1,36 @@
+package report
+
+import (
+	"context"
+)
+
+type (
+	testContextKey      struct{}
+	operationContextKey struct{}
+)
+
+func TestFromContext(ctx context.Context) *TestReport {
+	if ctx != nil {
+		if v, ok := ctx.Value(testContextKey{}).(*TestReport); ok {
+			return v
+		}
+	}
+	return nil
+}
+
+func TestIntoContext(ctx context.Context, test *TestReport) context.Context {
+	return context.WithValue(ctx, testContextKey{}, test)
+}
+
+func OperationFromContext(ctx context.Context) *OperationReport {
+	if ctx != nil {
+		if v, ok := ctx.Value(operationContextKey{}).(*OperationReport); ok {
+			return v
+		}
+	}
+	return nil
+}
+
+func OperationIntoContext(ctx context.Context, op *OperationReport) context.Context {
+	return context.WithValue(ctx, operationContextKey{}, op)
+}
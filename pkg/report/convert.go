@@ -0,0 +1,125 @@
+package report
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	v1alpha1 "github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
+)
+
+// Convert loads the report at inPath and saves it to outPath in the given format, outPath ending with .gz is compressed.
+// It returns the data lost by the conversion, see ConversionWarnings.
+func Convert(inPath string, outFormat v1alpha1.ReportFormatType, outPath string) ([]string, error) {
+	serializer, err := GetSerializer(outFormat)
+	if err != nil {
+		return nil, err
+	}
+	report, err := Load(inPath)
+	if err != nil {
+		return nil, err
+	}
+	warnings, err := ConversionWarnings(report, outFormat)
+	if err != nil {
+		return nil, err
+	}
+	options := SaveOptions{Compress: filepath.Ext(outPath) == ".gz"}
+	if err := SaveReportWithOptions(report, serializer, outPath, options); err != nil {
+		return nil, err
+	}
+	return warnings, nil
+}
+
+// groupsType is the type of the groups of reports and groups, they are nested test suites in XML and have no JSON form.
+var groupsType = reflect.TypeOf([]*TestsGroup(nil))
+
+// ConversionWarnings returns a description of the data that can't be expressed when the report is written in the given format.
+// Every field of the report is walked, warnings name fields by their path in JSON reports, like testsuite[0].failure.message.
+// The report is expected to be complete, like a loaded report.
+func ConversionWarnings(report *TestsReport, outFormat v1alpha1.ReportFormatType) ([]string, error) {
+	format, err := ParseFormat(string(outFormat))
+	if err != nil {
+		return nil, err
+	}
+	var warnings []string
+	conversionWarnings(reflect.ValueOf(report).Elem(), "", format, &warnings)
+	return warnings, nil
+}
+
+// conversionWarnings appends the warnings of v, found at path, to warnings: fields the format has no room for, and in XML
+// the text holding escape sequences or characters not allowed in XML 1.0, they are stripped.
+func conversionWarnings(v reflect.Value, path string, format v1alpha1.ReportFormatType, warnings *[]string) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			conversionWarnings(v.Elem(), path, format, warnings)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name := fieldPath(path, field)
+			value := v.Field(i)
+			switch {
+			case field.Type == groupsType:
+				// the tests of groups are those of the report, only their names are written
+				if format == v1alpha1.JSONFormat && value.Len() != 0 {
+					*warnings = append(*warnings, fmt.Sprintf("%s can't be represented in %s", name, format))
+				} else if format == v1alpha1.XMLFormat {
+					groupWarnings(value.Interface().([]*TestsGroup), name, warnings)
+				}
+			case field.Tag.Get(strings.ToLower(string(format))) == "-":
+				if !value.IsZero() {
+					*warnings = append(*warnings, fmt.Sprintf("%s can't be represented in %s", name, format))
+				}
+			default:
+				conversionWarnings(value, name, format, warnings)
+			}
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			conversionWarnings(v.Index(i), fmt.Sprintf("%s[%d]", path, i), format, warnings)
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		for _, key := range keys {
+			conversionWarnings(v.MapIndex(key), path+"."+key.String(), format, warnings)
+		}
+	case reflect.String:
+		if format == v1alpha1.XMLFormat {
+			lossyXML(path, v.String(), warnings)
+		}
+	}
+}
+
+// fieldPath returns the path of a field of the struct found at path, fields are named as in JSON reports.
+func fieldPath(path string, field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		name = strings.ToLower(field.Name[:1]) + field.Name[1:]
+	}
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// groupWarnings appends the warnings of the names of groups and of the groups nested in them.
+func groupWarnings(groups []*TestsGroup, path string, warnings *[]string) {
+	for i, group := range groups {
+		lossyXML(fmt.Sprintf("%s[%d].name", path, i), group.Name, warnings)
+		groupWarnings(group.Groups, fmt.Sprintf("%s[%d].groups", path, i), warnings)
+	}
+}
+
+// lossyXML appends a warning if text can't be written as is in XML.
+func lossyXML(path, text string, warnings *[]string) {
+	if sanitizeXML(text) != text {
+		*warnings = append(*warnings, fmt.Sprintf("%s contains characters that can't be represented in XML", path))
+	}
+}
@@ -0,0 +1,244 @@
+package report
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	v1alpha1 "github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvert(t *testing.T) {
+	report := syntheticReport(3)
+	report.Reports[0].NewFailure("boom")
+	report.Reports[1].AddWarning(WarningTypeOther, "warning")
+	report.Recompute()
+	expected, err := JSONSerializer{}.Serialize(report)
+	assert.NoError(t, err)
+	formats := []struct {
+		format     v1alpha1.ReportFormatType
+		serializer ReportSerializer
+		ext        string
+	}{
+		{format: v1alpha1.JSONFormat, serializer: JSONSerializer{}, ext: ".json"},
+		{format: v1alpha1.XMLFormat, serializer: XMLSerializer{}, ext: ".xml"},
+	}
+	for _, source := range formats {
+		for _, target := range formats {
+			t.Run(string(source.format)+" to "+string(target.format), func(t *testing.T) {
+				dir := t.TempDir()
+				in := filepath.Join(dir, "in"+source.ext)
+				out := filepath.Join(dir, "out"+target.ext)
+				assert.NoError(t, SaveReport(report, source.serializer, in))
+				warnings, err := Convert(in, target.format, out)
+				assert.NoError(t, err)
+				assert.Empty(t, warnings)
+				loaded, err := Load(out)
+				assert.NoError(t, err)
+				actual, err := JSONSerializer{}.Serialize(loaded)
+				assert.NoError(t, err)
+				assert.JSONEq(t, string(expected), string(actual))
+			})
+		}
+	}
+}
+
+func TestConvert_Compressed(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "in.json")
+	out := filepath.Join(dir, "out.xml.gz")
+	assert.NoError(t, SaveReport(syntheticReport(2), JSONSerializer{}, in))
+	_, err := Convert(in, v1alpha1.XMLFormat, out)
+	assert.NoError(t, err)
+	data, err := os.ReadFile(out)
+	assert.NoError(t, err)
+	assert.Equal(t, gzipMagic, data[:2])
+	loaded, err := Load(out)
+	assert.NoError(t, err)
+	assert.Len(t, loaded.Reports, 2)
+}
+
+func TestConvert_Errors(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "in.json")
+	assert.NoError(t, SaveReport(syntheticReport(1), JSONSerializer{}, in))
+	_, err := Convert(in, "yaml", filepath.Join(dir, "out.yaml"))
+	assert.Error(t, err)
+	_, err = Convert(filepath.Join(dir, "missing.json"), v1alpha1.XMLFormat, filepath.Join(dir, "out.xml"))
+	assert.Error(t, err)
+}
+
+func TestConversionWarnings(t *testing.T) {
+	report := syntheticReport(2)
+	assert.Empty(t, mustConversionWarnings(t, report, v1alpha1.XMLFormat))
+	report.Reports[1].NewFailure("\x1b[31mboom\x1b[0m")
+	assert.Equal(t, []string{"testsuite[1].failure.message contains characters that can't be represented in XML"}, mustConversionWarnings(t, report, v1alpha1.XMLFormat))
+	assert.Empty(t, mustConversionWarnings(t, report, v1alpha1.JSONFormat))
+	_, err := ConversionWarnings(report, "yaml")
+	assert.Error(t, err)
+}
+
+func mustConversionWarnings(t *testing.T, report *TestsReport, format v1alpha1.ReportFormatType) []string {
+	t.Helper()
+	warnings, err := ConversionWarnings(report, format)
+	assert.NoError(t, err)
+	return warnings
+}
+
+// filledReport returns a report with every field set, text fields are set to text.
+func filledReport(text string) *TestsReport {
+	var report TestsReport
+	fill(reflect.ValueOf(&report).Elem(), text)
+	report.GroupBy(GroupByLabel("team"))
+	return &report
+}
+
+// fill sets the exported fields of v and of the values it holds, groups are left to GroupBy.
+func fill(v reflect.Value, text string) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		v.Set(reflect.New(v.Type().Elem()))
+		fill(v.Elem(), text)
+	case reflect.Struct:
+		if v.Type() == reflect.TypeOf(time.Time{}) {
+			v.Set(reflect.ValueOf(time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)))
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if field := v.Type().Field(i); field.IsExported() && field.Type != groupsType {
+				fill(v.Field(i), text)
+			}
+		}
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+		fill(v.Index(0), text)
+	case reflect.Map:
+		m := reflect.MakeMap(v.Type())
+		value := reflect.New(v.Type().Elem()).Elem()
+		fill(value, text)
+		m.SetMapIndex(reflect.ValueOf("team"), value)
+		v.Set(m)
+	case reflect.String:
+		v.SetString(text)
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int:
+		v.SetInt(1)
+	default:
+		panic(fmt.Sprintf("fill doesn't support %s", v.Type()))
+	}
+}
+
+// walkFields calls f with the path of every field of v holding a value, groups and maps are not walked into.
+func walkFields(v reflect.Value, path string, f func(path string, v reflect.Value)) {
+	switch {
+	case v.Kind() == reflect.Pointer:
+		if !v.IsNil() {
+			walkFields(v.Elem(), path, f)
+		}
+	case v.Kind() == reflect.Struct && v.Type() != reflect.TypeOf(time.Time{}):
+		for i := 0; i < v.NumField(); i++ {
+			if field := v.Type().Field(i); field.IsExported() {
+				walkFields(v.Field(i), fieldPath(path, field), f)
+			}
+		}
+	case v.Kind() == reflect.Slice && v.Type() != groupsType:
+		for i := 0; i < v.Len(); i++ {
+			walkFields(v.Index(i), fmt.Sprintf("%s[%d]", path, i), f)
+		}
+	default:
+		f(path, v)
+	}
+}
+
+// lostFields returns the paths of the fields of in that out doesn't hold.
+func lostFields(in, out *TestsReport) []string {
+	values := map[string]reflect.Value{}
+	walkFields(reflect.ValueOf(out).Elem(), "", func(path string, v reflect.Value) { values[path] = v })
+	var lost []string
+	walkFields(reflect.ValueOf(in).Elem(), "", func(path string, v reflect.Value) {
+		value, ok := values[path]
+		switch {
+		case !ok:
+			lost = append(lost, path)
+		case v.Type() == groupsType:
+			if v.Len() != value.Len() {
+				lost = append(lost, path)
+			}
+		case v.Type() == reflect.TypeOf(time.Time{}):
+			if !v.Interface().(time.Time).Equal(value.Interface().(time.Time)) {
+				lost = append(lost, path)
+			}
+		case !reflect.DeepEqual(v.Interface(), value.Interface()):
+			lost = append(lost, path)
+		}
+	})
+	return lost
+}
+
+func TestConvert_AllFields(t *testing.T) {
+	formats := []struct {
+		format     v1alpha1.ReportFormatType
+		serializer ReportSerializer
+		ext        string
+	}{
+		{format: v1alpha1.JSONFormat, serializer: JSONSerializer{}, ext: ".json"},
+		{format: v1alpha1.XMLFormat, serializer: XMLSerializer{}, ext: ".xml"},
+	}
+	for _, source := range formats {
+		for _, target := range formats {
+			t.Run(string(source.format)+" to "+string(target.format), func(t *testing.T) {
+				dir := t.TempDir()
+				in := filepath.Join(dir, "in"+source.ext)
+				out := filepath.Join(dir, "out"+target.ext)
+				assert.NoError(t, SaveReport(filledReport("value"), source.serializer, in))
+				loadedIn, err := Load(in)
+				assert.NoError(t, err)
+				warnings, err := Convert(in, target.format, out)
+				assert.NoError(t, err)
+				loadedOut, err := Load(out)
+				assert.NoError(t, err)
+				// every field lost by the conversion is warned about
+				lost := lostFields(loadedIn, loadedOut)
+				for _, path := range lost {
+					assert.Contains(t, warnings, path+" can't be represented in "+string(target.format))
+				}
+				assert.Len(t, warnings, len(lost))
+			})
+		}
+	}
+}
+
+func TestConversionWarnings_AllFields(t *testing.T) {
+	// every text field is checked, whatever its depth in the report
+	var paths []string
+	walkFields(reflect.ValueOf(filledReport("value")).Elem(), "", func(path string, v reflect.Value) {
+		if v.Kind() == reflect.String {
+			paths = append(paths, path)
+		}
+	})
+	assert.NotEmpty(t, paths)
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			report := filledReport("value")
+			walkFields(reflect.ValueOf(report).Elem(), "", func(p string, v reflect.Value) {
+				if p == path {
+					v.SetString("\x1b[31mvalue\x1b[0m")
+				}
+			})
+			assert.ElementsMatch(t, []string{
+				"testsuite[0].labels can't be represented in XML",
+				path + " contains characters that can't be represented in XML",
+			}, mustConversionWarnings(t, report, v1alpha1.XMLFormat))
+		})
+	}
+	// groups are nested test suites in XML, only their names are text
+	report := filledReport("value")
+	report.Groups[0].Name = "\x1b[31mvalue\x1b[0m"
+	assert.Contains(t, mustConversionWarnings(t, report, v1alpha1.XMLFormat), "groups[0].name contains characters that can't be represented in XML")
+	assert.Equal(t, []string{"groups can't be represented in JSON"}, mustConversionWarnings(t, report, v1alpha1.JSONFormat))
+}
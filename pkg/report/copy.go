@@ -0,0 +1,162 @@
+package report
+
+// DeepCopy returns a copy of the report sharing no mutable state with the original.
+func (tr *TestsReport) DeepCopy() *TestsReport {
+	tr.lock.Lock()
+	defer tr.lock.Unlock()
+	return tr.copyWith(tr.Reports)
+}
+
+// copyWith returns a deep copy of the report holding deep copies of the given tests, the caller owns the report lock.
+func (tr *TestsReport) copyWith(tests []*TestReport) *TestsReport {
+	out := &TestsReport{
+		Name:                tr.Name,
+		RunID:               tr.RunID,
+		Version:             tr.Version,
+		Parallel:            tr.Parallel,
+		TimeStamp:           tr.TimeStamp,
+		Time:                tr.Time,
+		Test:                tr.Test,
+		Failures:            tr.Failures,
+		QuarantinedFailures: tr.QuarantinedFailures,
+		Warnings:            tr.Warnings,
+		Reports:             make([]*TestReport, 0, len(tests)),
+		groupBy:             tr.groupBy,
+	}
+	if tr.Git != nil {
+		git := *tr.Git
+		out.Git = &git
+	}
+	copies := map[*TestReport]*TestReport{}
+	for _, test := range tests {
+		copied := test.deepCopy()
+		copies[test] = copied
+		out.Reports = append(out.Reports, copied)
+	}
+	// groups reference the copied tests, groups left empty are dropped
+	out.Groups = copyGroups(tr.Groups, copies)
+	for _, group := range out.Groups {
+		group.aggregate()
+	}
+	return out
+}
+
+// copyGroups copies groups and the groups nested in them, keeping the tests found in copies.
+func copyGroups(groups []*TestsGroup, copies map[*TestReport]*TestReport) []*TestsGroup {
+	var out []*TestsGroup
+	for _, group := range groups {
+		copied := &TestsGroup{Name: group.Name, Groups: copyGroups(group.Groups, copies)}
+		for _, test := range group.Reports {
+			if test, found := copies[test]; found {
+				copied.Reports = append(copied.Reports, test)
+			}
+		}
+		if len(copied.Reports) != 0 || len(copied.Groups) != 0 {
+			out = append(out, copied)
+		}
+	}
+	return out
+}
+
+func (t *TestReport) deepCopy() *TestReport {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	out := &TestReport{
+		Name:          t.Name,
+		CorrelationID: t.CorrelationID,
+		TimeStamp:     t.TimeStamp,
+		Time:          t.Time,
+		Test:          t.Test,
+		Concurrent:    t.Concurrent,
+		Iteration:     t.Iteration,
+		Worker:        t.Worker,
+		Namespace:     t.Namespace,
+		Path:          t.Path,
+		Skip:          t.Skip,
+		SkipReason:    t.SkipReason,
+		Quarantined:   t.Quarantined,
+		Interrupted:   t.Interrupted,
+		SkipDelete:    t.SkipDelete,
+	}
+	if t.Failure != nil {
+		failure := *t.Failure
+		out.Failure = &failure
+	}
+	if t.Warnings != nil {
+		out.Warnings = append([]Warning{}, t.Warnings...)
+	}
+	if t.Environment != nil {
+		out.Environment = append(Environment{}, t.Environment...)
+	}
+	if t.Labels != nil {
+		out.Labels = make(map[string]string, len(t.Labels))
+		for key, value := range t.Labels {
+			out.Labels[key] = value
+		}
+	}
+	if t.Logs != nil {
+		out.Logs = append(Logs{}, t.Logs...)
+	}
+	if t.Artifacts != nil {
+		out.Artifacts = append([]string{}, t.Artifacts...)
+	}
+	if t.Steps != nil {
+		out.Steps = make([]*TestSpecStepReport, 0, len(t.Steps))
+		for _, step := range t.Steps {
+			out.Steps = append(out.Steps, step.deepCopy())
+		}
+	}
+	return out
+}
+
+func (ts *TestSpecStepReport) deepCopy() *TestSpecStepReport {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+	out := &TestSpecStepReport{
+		Name:       ts.Name,
+		Index:      ts.Index,
+		Skip:       ts.Skip,
+		SkipReason: ts.SkipReason,
+	}
+	if ts.Results != nil {
+		out.Results = make([]*OperationReport, 0, len(ts.Results))
+		for _, op := range ts.Results {
+			out.Results = append(out.Results, op.deepCopy())
+		}
+	}
+	return out
+}
+
+func (op *OperationReport) deepCopy() *OperationReport {
+	op.lock.Lock()
+	defer op.lock.Unlock()
+	out := &OperationReport{
+		Name:          op.Name,
+		CorrelationID: op.CorrelationID,
+		Phase:         op.Phase,
+		Index:         op.Index,
+		TimeStamp:     op.TimeStamp,
+		Time:          op.Time,
+		Timeout:       op.Timeout,
+		Result:        op.Result,
+		Message:       op.Message,
+		Diff:          op.Diff,
+		OperationType: op.OperationType,
+		Attempts:      op.Attempts,
+		minInterval:   op.minInterval,
+		maxInterval:   op.maxInterval,
+	}
+	if op.FirstAttemptAt != nil {
+		first := *op.FirstAttemptAt
+		out.FirstAttemptAt = &first
+	}
+	if op.LastAttemptAt != nil {
+		last := *op.LastAttemptAt
+		out.LastAttemptAt = &last
+	}
+	if op.AttemptIntervalStats != nil {
+		stats := *op.AttemptIntervalStats
+		out.AttemptIntervalStats = &stats
+	}
+	return out
+}
@@ -0,0 +1,31 @@
+package report
+
+import (
+	"strconv"
+	"sync/atomic"
+)
+
+// correlationIDPrefix is the number of characters of the run identifier prefixing correlation identifiers.
+const correlationIDPrefix = 6
+
+// correlationIDs numbers the tests and operations of a run. Identifiers are a prefix of the run identifier followed
+// by a counter, like 3f9a1c-12, short enough for log lines and unique within the run. It is safe for concurrent use.
+type correlationIDs struct {
+	prefix string
+	count  atomic.Int64
+}
+
+func newCorrelationIDs(runID string) *correlationIDs {
+	if len(runID) > correlationIDPrefix {
+		runID = runID[:correlationIDPrefix]
+	}
+	return &correlationIDs{prefix: runID}
+}
+
+func (c *correlationIDs) next() string {
+	id := strconv.FormatInt(c.count.Add(1), 10)
+	if c.prefix == "" {
+		return id
+	}
+	return c.prefix + "-" + id
+}
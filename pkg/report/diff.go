@@ -0,0 +1,187 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+type TestStatus string
+
+const (
+	TestStatusPassed  TestStatus = "passed"
+	TestStatusFailed  TestStatus = "failed"
+	TestStatusSkipped TestStatus = "skipped"
+)
+
+// DiffEntry describes how a test changed between two reports.
+type DiffEntry struct {
+	// Name of the test.
+	Name string `json:"name"`
+	// Old is the status of the test in the old report.
+	Old TestStatus `json:"old,omitempty"`
+	// New is the status of the test in the new report.
+	New TestStatus `json:"new,omitempty"`
+	// Message is the most relevant failure message, if any.
+	Message string `json:"message,omitempty"`
+}
+
+// ReportDiff holds the differences between two reports, tests are matched by name.
+type ReportDiff struct {
+	// NewlyFailed lists tests that passed in the old report and failed in the new one.
+	NewlyFailed []DiffEntry `json:"newlyFailed"`
+	// Fixed lists tests that failed in the old report and passed in the new one.
+	Fixed []DiffEntry `json:"fixed"`
+	// StillFailing lists tests that failed in both reports.
+	StillFailing []DiffEntry `json:"stillFailing"`
+	// Added lists tests only present in the new report.
+	Added []DiffEntry `json:"added"`
+	// Removed lists tests only present in the old report.
+	Removed []DiffEntry `json:"removed"`
+	// Skipped lists tests skipped in only one of the reports.
+	Skipped []DiffEntry `json:"skipped"`
+}
+
+// testOutcome is the aggregated outcome of all the runs of a test in a report.
+type testOutcome struct {
+	status  TestStatus
+	message string
+}
+
+// outcomes aggregates tests by name, a repeated test is failed if any of its runs failed
+// and skipped only if all of its runs were skipped.
+func outcomes(report *TestsReport) map[string]testOutcome {
+	out := map[string]testOutcome{}
+	if report == nil {
+		return out
+	}
+	for _, test := range report.Reports {
+		current, found := out[test.Name]
+		switch {
+		case test.Failure != nil:
+			if current.status != TestStatusFailed {
+				out[test.Name] = testOutcome{status: TestStatusFailed, message: test.Failure.Message}
+			}
+		case test.Skip:
+			if !found {
+				out[test.Name] = testOutcome{status: TestStatusSkipped}
+			}
+		default:
+			if !found || current.status == TestStatusSkipped {
+				out[test.Name] = testOutcome{status: TestStatusPassed}
+			}
+		}
+	}
+	return out
+}
+
+// Diff compares two reports and returns the tests whose outcome changed.
+func Diff(old, new *TestsReport) *ReportDiff {
+	oldOutcomes, newOutcomes := outcomes(old), outcomes(new)
+	diff := &ReportDiff{
+		NewlyFailed:  []DiffEntry{},
+		Fixed:        []DiffEntry{},
+		StillFailing: []DiffEntry{},
+		Added:        []DiffEntry{},
+		Removed:      []DiffEntry{},
+		Skipped:      []DiffEntry{},
+	}
+	for name, n := range newOutcomes {
+		o, found := oldOutcomes[name]
+		if !found {
+			diff.Added = append(diff.Added, DiffEntry{Name: name, New: n.status, Message: n.message})
+			continue
+		}
+		entry := DiffEntry{Name: name, Old: o.status, New: n.status, Message: n.message}
+		switch {
+		case o.status == n.status && n.status != TestStatusFailed:
+			// unchanged
+		case o.status == TestStatusSkipped || n.status == TestStatusSkipped:
+			if entry.Message == "" {
+				entry.Message = o.message
+			}
+			diff.Skipped = append(diff.Skipped, entry)
+		case o.status == TestStatusFailed && n.status == TestStatusFailed:
+			diff.StillFailing = append(diff.StillFailing, entry)
+		case n.status == TestStatusFailed:
+			diff.NewlyFailed = append(diff.NewlyFailed, entry)
+		default:
+			entry.Message = o.message
+			diff.Fixed = append(diff.Fixed, entry)
+		}
+	}
+	for name, o := range oldOutcomes {
+		if _, found := newOutcomes[name]; !found {
+			diff.Removed = append(diff.Removed, DiffEntry{Name: name, Old: o.status, Message: o.message})
+		}
+	}
+	for _, entries := range []*[]DiffEntry{&diff.NewlyFailed, &diff.Fixed, &diff.StillFailing, &diff.Added, &diff.Removed, &diff.Skipped} {
+		sort.Slice(*entries, func(i, j int) bool { return (*entries)[i].Name < (*entries)[j].Name })
+	}
+	return diff
+}
+
+// HasRegressions returns true if some tests started failing.
+func (d *ReportDiff) HasRegressions() bool {
+	return len(d.NewlyFailed) != 0
+}
+
+// RenderJSON writes the diff as indented JSON, suitable for tooling.
+func (d *ReportDiff) RenderJSON(w io.Writer) error {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// RenderText writes the diff as markdown text, suitable for pull request comments.
+func (d *ReportDiff) RenderText(w io.Writer) error {
+	sections := []struct {
+		title   string
+		entries []DiffEntry
+	}{
+		{"Newly failing", d.NewlyFailed},
+		{"Fixed", d.Fixed},
+		{"Still failing", d.StillFailing},
+		{"Added", d.Added},
+		{"Removed", d.Removed},
+		{"Skipped in one run", d.Skipped},
+	}
+	var sb strings.Builder
+	empty := true
+	for _, section := range sections {
+		if len(section.entries) == 0 {
+			continue
+		}
+		if !empty {
+			sb.WriteString("\n")
+		}
+		empty = false
+		fmt.Fprintf(&sb, "### %s (%d)\n\n", section.title, len(section.entries))
+		for _, entry := range section.entries {
+			fmt.Fprintf(&sb, "- `%s`", entry.Name)
+			if entry.Old != "" && entry.New != "" && entry.Old != entry.New {
+				fmt.Fprintf(&sb, " (%s -> %s)", entry.Old, entry.New)
+			}
+			if entry.Message != "" {
+				fmt.Fprintf(&sb, ": %s", firstLine(entry.Message))
+			}
+			sb.WriteString("\n")
+		}
+	}
+	if empty {
+		sb.WriteString("No changes.\n")
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// firstLine returns the first line of a possibly multi line message.
+func firstLine(message string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	return line
+}
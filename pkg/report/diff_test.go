@@ -0,0 +1,144 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func diffTest(name string, status TestStatus) *TestReport {
+	test := &TestReport{Name: name}
+	switch status {
+	case TestStatusFailed:
+		test.Failure = &Failure{Message: name + " failed"}
+	case TestStatusSkipped:
+		test.Skip = true
+	}
+	return test
+}
+
+func diffReport(tests ...*TestReport) *TestsReport {
+	return &TestsReport{Name: "suite", Reports: tests}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		old  *TestsReport
+		new  *TestsReport
+		want *ReportDiff
+	}{{
+		name: "no changes",
+		old:  diffReport(diffTest("a", TestStatusPassed), diffTest("b", TestStatusSkipped)),
+		new:  diffReport(diffTest("a", TestStatusPassed), diffTest("b", TestStatusSkipped)),
+		want: &ReportDiff{
+			NewlyFailed:  []DiffEntry{},
+			Fixed:        []DiffEntry{},
+			StillFailing: []DiffEntry{},
+			Added:        []DiffEntry{},
+			Removed:      []DiffEntry{},
+			Skipped:      []DiffEntry{},
+		},
+	}, {
+		name: "all buckets",
+		old: diffReport(
+			diffTest("regressed", TestStatusPassed),
+			diffTest("fixed", TestStatusFailed),
+			diffTest("broken", TestStatusFailed),
+			diffTest("removed", TestStatusPassed),
+			diffTest("now-skipped", TestStatusFailed),
+			diffTest("was-skipped", TestStatusSkipped),
+		),
+		new: diffReport(
+			diffTest("regressed", TestStatusFailed),
+			diffTest("fixed", TestStatusPassed),
+			diffTest("broken", TestStatusFailed),
+			diffTest("added", TestStatusPassed),
+			diffTest("now-skipped", TestStatusSkipped),
+			diffTest("was-skipped", TestStatusPassed),
+		),
+		want: &ReportDiff{
+			NewlyFailed:  []DiffEntry{{Name: "regressed", Old: TestStatusPassed, New: TestStatusFailed, Message: "regressed failed"}},
+			Fixed:        []DiffEntry{{Name: "fixed", Old: TestStatusFailed, New: TestStatusPassed, Message: "fixed failed"}},
+			StillFailing: []DiffEntry{{Name: "broken", Old: TestStatusFailed, New: TestStatusFailed, Message: "broken failed"}},
+			Added:        []DiffEntry{{Name: "added", New: TestStatusPassed}},
+			Removed:      []DiffEntry{{Name: "removed", Old: TestStatusPassed}},
+			Skipped: []DiffEntry{
+				{Name: "now-skipped", Old: TestStatusFailed, New: TestStatusSkipped, Message: "now-skipped failed"},
+				{Name: "was-skipped", Old: TestStatusSkipped, New: TestStatusPassed},
+			},
+		},
+	}, {
+		name: "repeated test fails if any run failed",
+		old:  diffReport(diffTest("a", TestStatusPassed), diffTest("a", TestStatusPassed)),
+		new:  diffReport(diffTest("a", TestStatusPassed), diffTest("a", TestStatusFailed)),
+		want: &ReportDiff{
+			NewlyFailed:  []DiffEntry{{Name: "a", Old: TestStatusPassed, New: TestStatusFailed, Message: "a failed"}},
+			Fixed:        []DiffEntry{},
+			StillFailing: []DiffEntry{},
+			Added:        []DiffEntry{},
+			Removed:      []DiffEntry{},
+			Skipped:      []DiffEntry{},
+		},
+	}, {
+		name: "nil old report",
+		old:  nil,
+		new:  diffReport(diffTest("a", TestStatusPassed)),
+		want: &ReportDiff{
+			NewlyFailed:  []DiffEntry{},
+			Fixed:        []DiffEntry{},
+			StillFailing: []DiffEntry{},
+			Added:        []DiffEntry{{Name: "a", New: TestStatusPassed}},
+			Removed:      []DiffEntry{},
+			Skipped:      []DiffEntry{},
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Diff(tt.old, tt.new)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReportDiff_HasRegressions(t *testing.T) {
+	assert.False(t, Diff(diffReport(diffTest("a", TestStatusFailed)), diffReport(diffTest("a", TestStatusFailed))).HasRegressions())
+	assert.True(t, Diff(diffReport(diffTest("a", TestStatusPassed)), diffReport(diffTest("a", TestStatusFailed))).HasRegressions())
+}
+
+func TestReportDiff_RenderText(t *testing.T) {
+	tests := []struct {
+		name string
+		diff *ReportDiff
+		want string
+	}{{
+		name: "empty",
+		diff: Diff(diffReport(), diffReport()),
+		want: "No changes.\n",
+	}, {
+		name: "changes",
+		diff: Diff(
+			diffReport(diffTest("a", TestStatusPassed), diffTest("b", TestStatusFailed)),
+			diffReport(diffTest("a", TestStatusFailed), diffTest("b", TestStatusPassed), diffTest("c", TestStatusPassed)),
+		),
+		want: "### Newly failing (1)\n\n- `a` (passed -> failed): a failed\n\n### Fixed (1)\n\n- `b` (failed -> passed): b failed\n\n### Added (1)\n\n- `c`\n",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.NoError(t, tt.diff.RenderText(&buf))
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func TestReportDiff_RenderJSON(t *testing.T) {
+	diff := Diff(diffReport(diffTest("a", TestStatusPassed)), diffReport(diffTest("a", TestStatusFailed)))
+	var buf bytes.Buffer
+	assert.NoError(t, diff.RenderJSON(&buf))
+	var got ReportDiff
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	assert.Equal(t, *diff, got)
+}
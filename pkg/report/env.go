@@ -0,0 +1,96 @@
+package report
+
+import (
+	"encoding/xml"
+	"os"
+	"regexp"
+	"sort"
+)
+
+// RedactedValue replaces the value of captured environment variables holding secrets.
+const RedactedValue = "[redacted]"
+
+// DefaultSecretPattern matches the names of environment variables whose value is redacted by default.
+var DefaultSecretPattern = regexp.MustCompile(`(?i)(secret|token|passw(or)?d|credential|auth|private|key)`)
+
+// EnvVar is an environment variable captured when a test started.
+type EnvVar struct {
+	// Name of the variable.
+	Name string `json:"name" xml:"name,attr"`
+	// Value of the variable, RedactedValue if the name looks like a secret.
+	Value string `json:"value" xml:"value,attr"`
+	// Unset indicates the variable was not set.
+	Unset bool `json:"unset,omitempty" xml:"unset,attr,omitempty"`
+}
+
+// Environment is the set of environment variables captured for a test.
+// It is serialized as an array in JSON and as JUnit <properties> in XML.
+type Environment []EnvVar
+
+// EnvCapture configures the environment variables recorded in test reports.
+type EnvCapture struct {
+	// Names is the allowlist of captured variables, nothing is captured when empty.
+	Names []string
+	// SecretPattern matches the names of variables whose value is redacted, it defaults to DefaultSecretPattern.
+	SecretPattern *regexp.Regexp
+	// lookup reads a variable, it defaults to os.LookupEnv and can be overridden in tests.
+	lookup func(string) (string, bool)
+}
+
+// Capture reads the allowlisted variables, sorted by name. Variables that are not set are recorded as unset.
+func (c EnvCapture) Capture() Environment {
+	if len(c.Names) == 0 {
+		return nil
+	}
+	lookup := os.LookupEnv
+	if c.lookup != nil {
+		lookup = c.lookup
+	}
+	secret := c.SecretPattern
+	if secret == nil {
+		secret = DefaultSecretPattern
+	}
+	seen := map[string]bool{}
+	vars := make(Environment, 0, len(c.Names))
+	for _, name := range c.Names {
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		value, found := lookup(name)
+		switch {
+		case !found:
+			vars = append(vars, EnvVar{Name: name, Unset: true})
+		case secret.MatchString(name):
+			vars = append(vars, EnvVar{Name: name, Value: RedactedValue})
+		default:
+			vars = append(vars, EnvVar{Name: name, Value: value})
+		}
+	}
+	sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+	return vars
+}
+
+// SetEnvironment records the environment variables captured when the test started.
+func (t *TestReport) SetEnvironment(vars Environment) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.Environment = vars
+}
+
+type xmlProperties struct {
+	Properties []EnvVar `xml:"property"`
+}
+
+func (e Environment) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	return enc.EncodeElement(xmlProperties{Properties: e}, start)
+}
+
+func (e *Environment) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var properties xmlProperties
+	if err := d.DecodeElement(&properties, &start); err != nil {
+		return err
+	}
+	*e = properties.Properties
+	return nil
+}
@@ -0,0 +1,105 @@
+package report
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvCapture_Capture(t *testing.T) {
+	env := map[string]string{
+		"REGISTRY":          "mirror.local",
+		"GITHUB_TOKEN":      "ghp_0123456789",
+		"DB_PASSWORD":       "hunter2",
+		"aws_secret_access": "abc",
+		"KUBECONFIG":        "/root/.kube/config",
+		"EMPTY":             "",
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+	tests := []struct {
+		name    string
+		capture EnvCapture
+		want    Environment
+	}{{
+		name:    "empty allowlist",
+		capture: EnvCapture{},
+	}, {
+		name:    "sorted by name",
+		capture: EnvCapture{Names: []string{"REGISTRY", "KUBECONFIG", "EMPTY"}},
+		want: Environment{
+			{Name: "EMPTY", Value: ""},
+			{Name: "KUBECONFIG", Value: "/root/.kube/config"},
+			{Name: "REGISTRY", Value: "mirror.local"},
+		},
+	}, {
+		name:    "secrets redacted",
+		capture: EnvCapture{Names: []string{"GITHUB_TOKEN", "DB_PASSWORD", "aws_secret_access", "REGISTRY"}},
+		want: Environment{
+			{Name: "DB_PASSWORD", Value: RedactedValue},
+			{Name: "GITHUB_TOKEN", Value: RedactedValue},
+			{Name: "REGISTRY", Value: "mirror.local"},
+			{Name: "aws_secret_access", Value: RedactedValue},
+		},
+	}, {
+		name:    "unset and duplicates",
+		capture: EnvCapture{Names: []string{"MISSING", "REGISTRY", "", "REGISTRY"}},
+		want: Environment{
+			{Name: "MISSING", Unset: true},
+			{Name: "REGISTRY", Value: "mirror.local"},
+		},
+	}, {
+		name:    "custom secret pattern",
+		capture: EnvCapture{Names: []string{"GITHUB_TOKEN", "REGISTRY"}, SecretPattern: regexp.MustCompile(`^REGISTRY$`)},
+		want: Environment{
+			{Name: "GITHUB_TOKEN", Value: "ghp_0123456789"},
+			{Name: "REGISTRY", Value: RedactedValue},
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.capture.lookup = lookup
+			assert.Equal(t, tt.want, tt.capture.Capture())
+		})
+	}
+}
+
+func TestEnvironment_Serialize(t *testing.T) {
+	testsReport := NewTests("SampleTestSuite")
+	test := NewTest("Test1")
+	test.SetEnvironment(Environment{{Name: "GITHUB_TOKEN", Value: RedactedValue}, {Name: "MISSING", Unset: true}, {Name: "REGISTRY", Value: "mirror <&>"}})
+	testsReport.AddTest(test)
+	testsReport.AddTest(NewTest("Test2"))
+	testsReport.Close()
+	t.Run("json", func(t *testing.T) {
+		data, err := JSONSerializer{}.Serialize(testsReport)
+		assert.NoError(t, err)
+		var raw struct {
+			Tests []map[string]any `json:"testsuite"`
+		}
+		assert.NoError(t, json.Unmarshal(data, &raw))
+		assert.Len(t, raw.Tests[0]["environment"], 3)
+		assert.NotContains(t, raw.Tests[1], "environment")
+		var loaded TestsReport
+		assert.NoError(t, json.Unmarshal(data, &loaded))
+		assert.Equal(t, test.Environment, loaded.Reports[0].Environment)
+	})
+	t.Run("xml", func(t *testing.T) {
+		data, err := XMLSerializer{}.Serialize(testsReport)
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), `<property name="GITHUB_TOKEN" value="[redacted]"></property>`)
+		assert.Contains(t, string(data), `<property name="MISSING" value="" unset="true"></property>`)
+		assert.Contains(t, string(data), `<property name="REGISTRY" value="mirror &lt;&amp;&gt;"></property>`)
+		assert.Equal(t, 1, strings.Count(string(data), "<properties>"))
+		var loaded TestsReport
+		assert.NoError(t, xml.Unmarshal(data, &loaded))
+		assert.Equal(t, test.Environment, loaded.Reports[0].Environment)
+		assert.Nil(t, loaded.Reports[1].Environment)
+	})
+}
@@ -0,0 +1,61 @@
+package report
+
+import (
+	"time"
+)
+
+// Filter returns a deep copy of the report holding only the tests matching the predicate.
+// Counts are recomputed and the timing spans the matching tests, mutating the result never affects the original.
+func (tr *TestsReport) Filter(predicate func(*TestReport) bool) *TestsReport {
+	tr.lock.Lock()
+	defer tr.lock.Unlock()
+	var tests []*TestReport
+	for _, test := range tr.Reports {
+		if predicate(test) {
+			tests = append(tests, test)
+		}
+	}
+	out := tr.copyWith(tests)
+	out.aggregate()
+	if start, end, ok := testsSpan(out.Reports); ok {
+		out.TimeStamp = start
+		out.Time = calculateDuration(start, end)
+	}
+	return out
+}
+
+// FilterFailed returns a deep copy of the report holding only the failed tests.
+func (tr *TestsReport) FilterFailed() *TestsReport {
+	return tr.Filter(func(test *TestReport) bool {
+		return test.Failure != nil
+	})
+}
+
+// FailuresName returns the name of the report holding only failed tests, e.g. chainsaw-report-failures.json.
+func FailuresName(name string) string {
+	base, ext := splitExt(name)
+	return base + "-failures" + ext
+}
+
+// testsSpan returns the earliest start and latest end of the given tests.
+func testsSpan(tests []*TestReport) (time.Time, time.Time, bool) {
+	var start, end time.Time
+	found := false
+	for _, test := range tests {
+		if test.TimeStamp.IsZero() {
+			continue
+		}
+		testEnd := test.TimeStamp
+		if duration, err := parseDuration(test.Time); err == nil {
+			testEnd = testEnd.Add(duration)
+		}
+		if !found || test.TimeStamp.Before(start) {
+			start = test.TimeStamp
+		}
+		if !found || testEnd.After(end) {
+			end = testEnd
+		}
+		found = true
+	}
+	return start, end, found
+}
@@ -0,0 +1,93 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTestsReport_Filter(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	test := func(name string, offset time.Duration, duration string, failed bool) *TestReport {
+		t := &TestReport{
+			Name:      name,
+			TimeStamp: start.Add(offset),
+			Time:      duration,
+			Test:      1,
+			Warnings:  []Warning{{Type: WarningTypeOther, Message: "warning"}},
+			Steps: []*TestSpecStepReport{{
+				Name:    "step",
+				Results: []*OperationReport{{Name: "op", TimeStamp: start, Time: "0.100", Result: "Success"}},
+			}},
+		}
+		if failed {
+			t.Failure = &Failure{Message: name + " failed"}
+		}
+		return t
+	}
+	report := &TestsReport{
+		Name:      "suite",
+		RunID:     "run",
+		TimeStamp: start,
+		Time:      "60.000",
+		Reports: []*TestReport{
+			test("a", 0, "10.000", false),
+			test("b", 5*time.Second, "10.000", true),
+			test("c", 20*time.Second, "5.000", true),
+			test("d", 30*time.Second, "10.000", false),
+		},
+	}
+	report.aggregate()
+	failed := report.FilterFailed()
+	assert.Equal(t, "suite", failed.Name)
+	assert.Equal(t, "run", failed.RunID)
+	assert.Len(t, failed.Reports, 2)
+	assert.Equal(t, "b", failed.Reports[0].Name)
+	assert.Equal(t, "c", failed.Reports[1].Name)
+	assert.Equal(t, 2, failed.Failures)
+	assert.Equal(t, 2, failed.Test)
+	assert.Equal(t, 2, failed.Warnings)
+	assert.Equal(t, start.Add(5*time.Second), failed.TimeStamp)
+	assert.Equal(t, "20.000", failed.Time)
+	assert.NoError(t, failed.Validate())
+	// mutating the filtered report doesn't touch the original
+	failed.Reports[0].Name = "mutated"
+	failed.Reports[0].Failure.Message = "mutated"
+	failed.Reports[0].Warnings[0].Message = "mutated"
+	failed.Reports[0].Steps[0].Name = "mutated"
+	failed.Reports[0].Steps[0].Results[0].Message = "mutated"
+	failed.Reports = append(failed.Reports, &TestReport{Name: "extra"})
+	assert.Equal(t, "b", report.Reports[1].Name)
+	assert.Equal(t, "b failed", report.Reports[1].Failure.Message)
+	assert.Equal(t, "warning", report.Reports[1].Warnings[0].Message)
+	assert.Equal(t, "step", report.Reports[1].Steps[0].Name)
+	assert.Empty(t, report.Reports[1].Steps[0].Results[0].Message)
+	assert.Len(t, report.Reports, 4)
+	assert.Equal(t, 2, report.Failures)
+	// a predicate matching nothing keeps the suite timing
+	none := report.Filter(func(*TestReport) bool { return false })
+	assert.Empty(t, none.Reports)
+	assert.Equal(t, 0, none.Failures)
+	assert.Equal(t, 0, none.Test)
+	assert.Equal(t, report.TimeStamp, none.TimeStamp)
+	assert.Equal(t, report.Time, none.Time)
+}
+
+func TestTestsReport_DeepCopy(t *testing.T) {
+	report := syntheticReport(3)
+	op := report.Reports[0].Steps[0].Results[0]
+	op.RecordAttempt(time.Now())
+	op.RecordAttempt(time.Now().Add(time.Second))
+	copied := report.DeepCopy()
+	assert.Equal(t, report.Reports[0].Steps[0].Results[0].AttemptIntervalStats, copied.Reports[0].Steps[0].Results[0].AttemptIntervalStats)
+	copied.Reports[0].Steps[0].Results[0].AttemptIntervalStats.Max = "mutated"
+	assert.NotEqual(t, "mutated", op.AttemptIntervalStats.Max)
+	assert.NotSame(t, op.FirstAttemptAt, copied.Reports[0].Steps[0].Results[0].FirstAttemptAt)
+}
+
+func TestFailuresName(t *testing.T) {
+	assert.Equal(t, "chainsaw-report-failures", FailuresName("chainsaw-report"))
+	assert.Equal(t, "chainsaw-report-failures.xml", FailuresName("chainsaw-report.xml"))
+	assert.Equal(t, "reports/run-failures.json.gz", FailuresName("reports/run.json.gz"))
+}
@@ -0,0 +1,38 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	v1alpha1 "github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
+)
+
+// formats maps lower cased format names and aliases to the canonical report format.
+var formats = map[string]v1alpha1.ReportFormatType{
+	"json":  v1alpha1.JSONFormat,
+	"xml":   v1alpha1.XMLFormat,
+	"junit": v1alpha1.XMLFormat,
+}
+
+// SupportedFormats returns the sorted list of canonical report formats.
+func SupportedFormats() []string {
+	seen := map[v1alpha1.ReportFormatType]bool{}
+	var supported []string
+	for _, format := range formats {
+		if !seen[format] {
+			seen[format] = true
+			supported = append(supported, string(format))
+		}
+	}
+	sort.Strings(supported)
+	return supported
+}
+
+// ParseFormat returns the canonical report format for a format name or alias, the comparison is case insensitive.
+func ParseFormat(format string) (v1alpha1.ReportFormatType, error) {
+	if parsed, found := formats[strings.ToLower(strings.TrimSpace(format))]; found {
+		return parsed, nil
+	}
+	return "", fmt.Errorf("unsupported report format %q, supported formats are %s", format, strings.Join(SupportedFormats(), ", "))
+}
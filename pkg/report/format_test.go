@@ -0,0 +1,78 @@
+package report
+
+import (
+	"testing"
+
+	v1alpha1 "github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		want    v1alpha1.ReportFormatType
+		wantErr string
+	}{{
+		name:   "JSON",
+		format: "JSON",
+		want:   v1alpha1.JSONFormat,
+	}, {
+		name:   "json",
+		format: "json",
+		want:   v1alpha1.JSONFormat,
+	}, {
+		name:   "Json with spaces",
+		format: " Json ",
+		want:   v1alpha1.JSONFormat,
+	}, {
+		name:   "xml",
+		format: "xml",
+		want:   v1alpha1.XMLFormat,
+	}, {
+		name:   "junit",
+		format: "junit",
+		want:   v1alpha1.XMLFormat,
+	}, {
+		name:   "JUnit",
+		format: "JUnit",
+		want:   v1alpha1.XMLFormat,
+	}, {
+		name:    "unsupported",
+		format:  "yaml",
+		wantErr: `unsupported report format "yaml", supported formats are JSON, XML`,
+	}, {
+		name:    "empty",
+		format:  "",
+		wantErr: `unsupported report format "", supported formats are JSON, XML`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFormat(tt.format)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSupportedFormats(t *testing.T) {
+	assert.Equal(t, []string{"JSON", "XML"}, SupportedFormats())
+}
+
+func TestGetSerializer_CaseInsensitive(t *testing.T) {
+	serializer, err := GetSerializer("json")
+	assert.NoError(t, err)
+	assert.Equal(t, JSONSerializer{}, serializer)
+	serializer, err = GetSerializer("junit")
+	assert.NoError(t, err)
+	assert.Equal(t, XMLSerializer{}, serializer)
+}
+
+func TestFilePath_Alias(t *testing.T) {
+	assert.Equal(t, "report.xml", FilePath("junit", "", "report"))
+	assert.Equal(t, "report.json", FilePath("json", "", "report"))
+}
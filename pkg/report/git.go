@@ -0,0 +1,59 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// gitTimeout bounds the time spent looking up git information.
+const gitTimeout = 5 * time.Second
+
+// GitInfo identifies the revision of the repository holding the tests.
+type GitInfo struct {
+	// Commit is the SHA of the checked out commit.
+	Commit string `json:"commit" xml:"commit,attr"`
+	// Branch is the checked out branch, it is empty when the HEAD is detached.
+	Branch string `json:"branch,omitempty" xml:"branch,attr,omitempty"`
+	// Dirty indicates the working tree has uncommitted changes.
+	Dirty bool `json:"dirty,omitempty" xml:"dirty,attr,omitempty"`
+}
+
+// LookupGitInfo returns the git revision of the repository holding dir.
+// It returns nil if dir is not in a git repository or git is not available.
+func LookupGitInfo(ctx context.Context, dir string) *GitInfo {
+	ctx, cancel := context.WithTimeout(ctx, gitTimeout)
+	defer cancel()
+	commit, err := git(ctx, dir, "rev-parse", "--verify", "HEAD")
+	if err != nil || commit == "" {
+		return nil
+	}
+	info := GitInfo{Commit: commit}
+	// the symbolic name is HEAD when it is detached
+	if branch, err := git(ctx, dir, "rev-parse", "--abbrev-ref", "HEAD"); err == nil && branch != "HEAD" {
+		info.Branch = branch
+	}
+	if status, err := git(ctx, dir, "status", "--porcelain"); err == nil {
+		info.Dirty = status != ""
+	}
+	return &info
+}
+
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...) //nolint:gosec
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
+// SetGit records the git revision of the repository holding the tests.
+func (tr *TestsReport) SetGit(info *GitInfo) {
+	tr.lock.Lock()
+	defer tr.lock.Unlock()
+	tr.Git = info
+}
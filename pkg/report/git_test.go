@@ -0,0 +1,107 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// gitFixture creates a git repository with a single commit on the main branch, it returns the repository folder.
+func gitFixture(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+	dir := t.TempDir()
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=chainsaw", "-c", "user.email=chainsaw@kyverno.io"}, args...)...)
+		out, err := cmd.CombinedOutput()
+		assert.NoError(t, err, string(out))
+		return string(out)
+	}
+	run("init", "--quiet", "--initial-branch=main")
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "tests", "quick"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "tests", "quick", "chainsaw-test.yaml"), []byte("kind: Test\n"), 0o600))
+	run("add", "-A")
+	run("commit", "--quiet", "-m", "initial")
+	return dir
+}
+
+func TestLookupGitInfo(t *testing.T) {
+	dir := gitFixture(t)
+	head, err := exec.Command("git", "-C", dir, "rev-parse", "HEAD").Output()
+	assert.NoError(t, err)
+	commit := string(head[:len(head)-1])
+	// from a test folder nested in the repository
+	info := LookupGitInfo(context.Background(), filepath.Join(dir, "tests", "quick"))
+	assert.Equal(t, &GitInfo{Commit: commit, Branch: "main"}, info)
+	// uncommitted changes
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "tests", "quick", "chainsaw-test.yaml"), []byte("kind: Test\nchanged: true\n"), 0o600))
+	info = LookupGitInfo(context.Background(), dir)
+	assert.Equal(t, &GitInfo{Commit: commit, Branch: "main", Dirty: true}, info)
+	// detached head
+	assert.NoError(t, exec.Command("git", "-C", dir, "checkout", "--quiet", "--detach").Run())
+	info = LookupGitInfo(context.Background(), dir)
+	assert.Equal(t, &GitInfo{Commit: commit, Dirty: true}, info)
+}
+
+func TestLookupGitInfo_NotARepository(t *testing.T) {
+	dir := t.TempDir()
+	// prevent git from finding a repository in the parent folders
+	t.Setenv("GIT_CEILING_DIRECTORIES", filepath.Dir(dir))
+	assert.Nil(t, LookupGitInfo(context.Background(), dir))
+	assert.Nil(t, LookupGitInfo(context.Background(), filepath.Join(dir, "missing")))
+}
+
+func TestGitInfo_Serialize(t *testing.T) {
+	git := &GitInfo{Commit: "0123456789abcdef0123456789abcdef01234567", Branch: "feature/git", Dirty: true}
+	testsReport := NewTests("SampleTestSuite")
+	testsReport.SetGit(git)
+	testsReport.AddTest(NewTest("Test1"))
+	testsReport.Close()
+	t.Run("json", func(t *testing.T) {
+		data, err := JSONSerializer{}.Serialize(testsReport)
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), `"git": {`)
+		var loaded TestsReport
+		assert.NoError(t, json.Unmarshal(data, &loaded))
+		assert.Equal(t, git, loaded.Git)
+	})
+	t.Run("xml", func(t *testing.T) {
+		data, err := XMLSerializer{}.Serialize(testsReport)
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), `<git commit="0123456789abcdef0123456789abcdef01234567" branch="feature/git" dirty="true"></git>`)
+		var loaded TestsReport
+		assert.NoError(t, xml.Unmarshal(data, &loaded))
+		assert.Equal(t, git, loaded.Git)
+	})
+	t.Run("grouped xml", func(t *testing.T) {
+		grouped := testsReport.DeepCopy()
+		grouped.GroupBy(GroupByDirectory)
+		data, err := XMLSerializer{}.Serialize(grouped)
+		assert.NoError(t, err)
+		var loaded TestsReport
+		assert.NoError(t, xml.Unmarshal(data, &loaded))
+		assert.Equal(t, git, loaded.Git)
+	})
+	t.Run("merge", func(t *testing.T) {
+		other := NewTests("other")
+		other.SetGit(git)
+		other.AddTest(NewTest("Test2"))
+		other.Close()
+		merged, err := Merge(testsReport, other)
+		assert.NoError(t, err)
+		assert.Equal(t, git, merged.Git)
+		other.SetGit(&GitInfo{Commit: "fedcba9876543210fedcba9876543210fedcba98"})
+		merged, err = Merge(testsReport, other)
+		assert.NoError(t, err)
+		assert.Nil(t, merged.Git)
+	})
+}
@@ -0,0 +1,279 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultGroup is the group of tests without a grouping key.
+const DefaultGroup = "default"
+
+// GroupKeyFunc returns the group of a test, tests with an empty key fall into DefaultGroup.
+// Keys are paths, the group of a key holding slashes is nested in the group of its parent, e.g. tests/component in tests.
+type GroupKeyFunc func(*TestReport) string
+
+// TestsGroup is a sub suite gathering the tests sharing a grouping key, and the groups nested in it.
+type TestsGroup struct {
+	// Name is the grouping key.
+	Name string `json:"name" xml:"name,attr"`
+	// TimeStamp marks when the first test of the group began execution.
+	TimeStamp time.Time `json:"timestamp" xml:"timestamp,attr"`
+	// Time spans the tests of the group.
+	Time string `json:"time" xml:"time,attr"`
+	// Test counts the number of tests in the group and its nested groups.
+	Test int `json:"tests" xml:"tests,attr"`
+	// Failures counts the number of failed tests in the group.
+	Failures int `json:"failures" xml:"failures,attr"`
+	// QuarantinedFailures counts the number of failed tests left out of Failures because they are quarantined.
+	QuarantinedFailures int `json:"quarantinedFailures,omitempty" xml:"quarantinedFailures,attr,omitempty"`
+	// Warnings counts the number of warnings raised by the tests in the group.
+	Warnings int `json:"warnings,omitempty" xml:"warnings,attr,omitempty"`
+	// Reports are the tests of the group.
+	Reports []*TestReport `json:"testsuite" xml:"testsuite"`
+	// Groups are the groups nested in the group.
+	Groups []*TestsGroup `json:"groups,omitempty" xml:"-"`
+}
+
+// GroupByDirectory groups tests by the directory holding their folder, e.g. tests/component for tests/component/test.
+func GroupByDirectory(test *TestReport) string {
+	if test.Path == "" {
+		return ""
+	}
+	dir := path.Dir(filepath.ToSlash(test.Path))
+	if dir == "." {
+		return ""
+	}
+	return dir
+}
+
+// GroupByLabel groups tests by the value of a label.
+func GroupByLabel(label string) GroupKeyFunc {
+	return func(test *TestReport) string {
+		return test.Labels[label]
+	}
+}
+
+// GroupByNamePrefix groups tests by the part of their name before the first separator.
+func GroupByNamePrefix(separator string) GroupKeyFunc {
+	return func(test *TestReport) string {
+		if prefix, _, found := strings.Cut(test.Name, separator); found {
+			return prefix
+		}
+		return ""
+	}
+}
+
+// ParseGroupBy returns the grouping key function described by spec: directory, label:<key> or prefix:<separator>.
+func ParseGroupBy(spec string) (GroupKeyFunc, error) {
+	kind, arg, _ := strings.Cut(spec, ":")
+	switch {
+	case kind == "directory" && arg == "":
+		return GroupByDirectory, nil
+	case kind == "label" && arg != "":
+		return GroupByLabel(arg), nil
+	case kind == "prefix" && arg != "":
+		return GroupByNamePrefix(arg), nil
+	}
+	return nil, fmt.Errorf("unsupported report grouping %q, expected directory, label:<key> or prefix:<separator>", spec)
+}
+
+// GroupBy groups the tests of the report, groups are kept up to date when counts are recomputed.
+// In XML reports, groups are nested test suites.
+func (tr *TestsReport) GroupBy(key GroupKeyFunc) {
+	tr.lock.Lock()
+	defer tr.lock.Unlock()
+	tr.groupBy = key
+	tr.aggregate()
+}
+
+// groupTests gathers tests by key in order of first appearance, nested groups follow the tests of their parent.
+// The default group comes last.
+func groupTests(tests []*TestReport, key GroupKeyFunc) []*TestsGroup {
+	var root TestsGroup
+	var defaultGroup *TestsGroup
+	byName := map[string]*TestsGroup{}
+	for _, test := range tests {
+		test.lock.Lock()
+		name := groupName(key(test))
+		test.lock.Unlock()
+		if name == "" {
+			if defaultGroup == nil {
+				defaultGroup = &TestsGroup{Name: DefaultGroup}
+			}
+			defaultGroup.Reports = append(defaultGroup.Reports, test)
+			continue
+		}
+		group := root.group(name, byName)
+		group.Reports = append(group.Reports, test)
+	}
+	groups := root.Groups
+	if defaultGroup != nil {
+		groups = append(groups, defaultGroup)
+	}
+	for _, group := range groups {
+		group.aggregate()
+	}
+	return groups
+}
+
+// groupName cleans a grouping key, leading, trailing and repeated slashes don't make groups.
+func groupName(key string) string {
+	if key == "" {
+		return ""
+	}
+	return strings.Trim(path.Clean("/"+key), "/")
+}
+
+// group returns the group named name nested in g, it is created along with its parents if needed.
+func (g *TestsGroup) group(name string, byName map[string]*TestsGroup) *TestsGroup {
+	if group := byName[name]; group != nil {
+		return group
+	}
+	parent := g
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		parent = g.group(name[:i], byName)
+	}
+	group := &TestsGroup{Name: name}
+	parent.Groups = append(parent.Groups, group)
+	byName[name] = group
+	return group
+}
+
+// tests returns the tests of the group and of its nested groups.
+func (g *TestsGroup) tests() []*TestReport {
+	tests := g.Reports
+	for _, group := range g.Groups {
+		tests = append(tests[:len(tests):len(tests)], group.tests()...)
+	}
+	return tests
+}
+
+// walkGroups calls f with every group, parents before the groups nested in them.
+func walkGroups(groups []*TestsGroup, f func(*TestsGroup)) {
+	for _, group := range groups {
+		f(group)
+		walkGroups(group.Groups, f)
+	}
+}
+
+// aggregate computes the counts and timing of the group and its nested groups from their tests.
+func (g *TestsGroup) aggregate() {
+	for _, group := range g.Groups {
+		group.aggregate()
+	}
+	tests := g.tests()
+	g.Test, g.Failures, g.QuarantinedFailures, g.Warnings = 0, 0, 0, 0
+	for _, test := range tests {
+		test.lock.Lock()
+		if test.Failure != nil {
+			if test.Quarantined {
+				g.QuarantinedFailures++
+			} else {
+				g.Failures++
+			}
+		}
+		g.Warnings += len(test.Warnings)
+		g.Test += test.Test
+		test.lock.Unlock()
+	}
+	if start, end, ok := testsSpan(tests); ok {
+		g.TimeStamp = start
+		g.Time = calculateDuration(start, end)
+	}
+}
+
+// plainTestsReport has the fields of TestsReport without its XML methods.
+type plainTestsReport TestsReport
+
+// plainTestsGroup has the fields of TestsGroup without its XML methods.
+type plainTestsGroup TestsGroup
+
+// groupedTestsReport is the XML form of a grouped report, groups replace the flat list of tests.
+type groupedTestsReport struct {
+	*plainTestsReport
+	Groups []*TestsGroup `xml:"testsuite"`
+}
+
+// xmlTestsGroup is the XML form of a group, its tests and the groups nested in it are all nested test suites.
+type xmlTestsGroup struct {
+	*plainTestsGroup
+	Suites []any `xml:"testsuite"`
+}
+
+// xmlSuites decodes the test suites nested in a report or a group, a test suite is a group when it has a failures attribute,
+// tests never have one.
+type xmlSuites struct {
+	tests  []*TestReport
+	groups []*TestsGroup
+}
+
+func (s *xmlSuites) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "failures" {
+			var group TestsGroup
+			if err := d.DecodeElement(&group, &start); err != nil {
+				return err
+			}
+			s.groups = append(s.groups, &group)
+			return nil
+		}
+	}
+	var test TestReport
+	if err := d.DecodeElement(&test, &start); err != nil {
+		return err
+	}
+	s.tests = append(s.tests, &test)
+	return nil
+}
+
+func (tr *TestsReport) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if tr.Groups == nil {
+		return e.EncodeElement((*plainTestsReport)(tr), start)
+	}
+	return e.EncodeElement(groupedTestsReport{plainTestsReport: (*plainTestsReport)(tr), Groups: tr.Groups}, start)
+}
+
+// UnmarshalXML reads both flat and grouped reports, tests of grouped reports are also gathered in Reports.
+func (tr *TestsReport) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var suites xmlSuites
+	report := struct {
+		*plainTestsReport
+		Suites *xmlSuites `xml:"testsuite"`
+	}{plainTestsReport: (*plainTestsReport)(tr), Suites: &suites}
+	if err := d.DecodeElement(&report, &start); err != nil {
+		return err
+	}
+	tr.Reports, tr.Groups = suites.tests, suites.groups
+	for _, group := range suites.groups {
+		tr.Reports = append(tr.Reports, group.tests()...)
+	}
+	return nil
+}
+
+func (g *TestsGroup) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	suites := make([]any, 0, len(g.Reports)+len(g.Groups))
+	for _, test := range g.Reports {
+		suites = append(suites, test)
+	}
+	for _, group := range g.Groups {
+		suites = append(suites, group)
+	}
+	return e.EncodeElement(xmlTestsGroup{plainTestsGroup: (*plainTestsGroup)(g), Suites: suites}, start)
+}
+
+func (g *TestsGroup) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var suites xmlSuites
+	group := struct {
+		*plainTestsGroup
+		Suites *xmlSuites `xml:"testsuite"`
+	}{plainTestsGroup: (*plainTestsGroup)(g), Suites: &suites}
+	if err := d.DecodeElement(&group, &start); err != nil {
+		return err
+	}
+	g.Reports, g.Groups = suites.tests, suites.groups
+	return nil
+}
@@ -0,0 +1,225 @@
+package report
+
+import (
+	"encoding/xml"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	v1alpha1 "github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update the golden files of the tests")
+
+// assertGolden compares got with the golden file name of testdata, the file is written instead with -update.
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		assert.NoError(t, os.MkdirAll("testdata", 0o755))
+		assert.NoError(t, os.WriteFile(path, got, 0o600))
+	}
+	want, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+}
+
+func groupedReport() *TestsReport {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	test := func(name, path string, offset time.Duration, duration string, failed bool) *TestReport {
+		t := &TestReport{
+			Name:      name,
+			Path:      path,
+			Labels:    map[string]string{"team": path},
+			TimeStamp: start.Add(offset),
+			Time:      duration,
+			Test:      1,
+		}
+		if failed {
+			t.Failure = &Failure{Message: name + " failed"}
+		}
+		return t
+	}
+	report := &TestsReport{
+		Name:      "suite",
+		TimeStamp: start,
+		Time:      "60.000",
+		Reports: []*TestReport{
+			test("a", "tests/component/a", 0, "10.000", false),
+			test("b", "tests/other/b", 5*time.Second, "10.000", true),
+			test("c", "c", 20*time.Second, "5.000", false),
+			test("d", "tests/component/d", 30*time.Second, "10.000", true),
+		},
+	}
+	report.aggregate()
+	return report
+}
+
+func TestParseGroupBy(t *testing.T) {
+	test := &TestReport{Name: "network-policy", Path: "tests/network/policy", Labels: map[string]string{"team": "net"}}
+	tests := []struct {
+		spec    string
+		want    string
+		wantErr bool
+	}{
+		{spec: "directory", want: "tests/network"},
+		{spec: "label:team", want: "net"},
+		{spec: "label:missing", want: ""},
+		{spec: "prefix:-", want: "network"},
+		{spec: "prefix:/", want: ""},
+		{spec: "", wantErr: true},
+		{spec: "directory:tests", wantErr: true},
+		{spec: "label", wantErr: true},
+		{spec: "prefix:", wantErr: true},
+		{spec: "file", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			key, err := ParseGroupBy(tt.spec)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, key(test))
+		})
+	}
+}
+
+func TestTestsReport_GroupBy(t *testing.T) {
+	report := groupedReport()
+	report.GroupBy(GroupByDirectory)
+	assert.Len(t, report.Groups, 2)
+	tests, defaultGroup := report.Groups[0], report.Groups[1]
+	// directories are nested in their parent directory
+	assert.Equal(t, "tests", tests.Name)
+	assert.Empty(t, tests.Reports)
+	assert.Equal(t, 3, tests.Test)
+	assert.Equal(t, 2, tests.Failures)
+	assert.Equal(t, "40.000", tests.Time)
+	assert.Len(t, tests.Groups, 2)
+	component, other := tests.Groups[0], tests.Groups[1]
+	assert.Equal(t, "tests/component", component.Name)
+	assert.Equal(t, 2, component.Test)
+	assert.Equal(t, 1, component.Failures)
+	assert.Equal(t, report.TimeStamp, component.TimeStamp)
+	assert.Equal(t, "40.000", component.Time)
+	assert.Equal(t, "tests/other", other.Name)
+	assert.Equal(t, 1, other.Failures)
+	assert.Equal(t, "10.000", other.Time)
+	// tests without a grouping key fall into the default group, listed last
+	assert.Equal(t, DefaultGroup, defaultGroup.Name)
+	assert.Equal(t, "c", defaultGroup.Reports[0].Name)
+	// groups follow tests added after the report is closed
+	report.Close()
+	report.AddTest(&TestReport{Name: "e", Path: "tests/other/e", TimeStamp: report.TimeStamp, Time: "1.000", Test: 1})
+	assert.Equal(t, 2, report.Groups[0].Groups[1].Test)
+	assert.Equal(t, 4, report.Groups[0].Test)
+	// groups are copied with the report, groups left without tests are dropped
+	failed := report.FilterFailed()
+	assert.Len(t, failed.Groups, 1)
+	assert.Equal(t, 2, failed.Groups[0].Test)
+	assert.Len(t, failed.Groups[0].Groups, 2)
+	assert.Equal(t, "tests/other", failed.Groups[0].Groups[0].Name)
+	assert.Equal(t, "b", failed.Groups[0].Groups[0].Reports[0].Name)
+	assert.Same(t, failed.Reports[0], failed.Groups[0].Groups[0].Reports[0])
+}
+
+func Test_groupName(t *testing.T) {
+	assert.Equal(t, "", groupName(""))
+	assert.Equal(t, "", groupName("/"))
+	assert.Equal(t, "tests/component", groupName("tests/component"))
+	assert.Equal(t, "tests/component", groupName("/tests//component/"))
+}
+
+func TestTestsReport_GroupByLabel(t *testing.T) {
+	report := groupedReport()
+	report.GroupBy(GroupByLabel("team"))
+	// label values are paths too, the teams of the report are nested in tests
+	assert.Len(t, report.Groups, 2)
+	assert.Equal(t, "tests", report.Groups[0].Name)
+	assert.Len(t, report.Groups[0].Groups, 2)
+	assert.Equal(t, "c", report.Groups[1].Name)
+	report.GroupBy(GroupByNamePrefix("-"))
+	assert.Len(t, report.Groups, 1)
+	assert.Equal(t, DefaultGroup, report.Groups[0].Name)
+	assert.Equal(t, 4, report.Groups[0].Test)
+}
+
+func TestTestsReport_GroupedXML(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	test := func(name, path, team string, offset int, failure string) *TestReport {
+		t := &TestReport{
+			Name:      name,
+			Path:      path,
+			Labels:    map[string]string{"team": team},
+			TimeStamp: start.Add(time.Duration(offset) * time.Second),
+			Time:      "1.000",
+			Test:      1,
+		}
+		if failure != "" {
+			t.Failure = &Failure{Message: failure}
+		}
+		return t
+	}
+	tests := []struct {
+		name    string
+		groupBy GroupKeyFunc
+		golden  string
+	}{{
+		name:    "directory",
+		groupBy: GroupByDirectory,
+		golden:  "grouped-directory.xml",
+	}, {
+		name:    "label",
+		groupBy: GroupByLabel("team"),
+		golden:  "grouped-label.xml",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := &TestsReport{
+				Name:      "suite",
+				TimeStamp: start,
+				Time:      "6.000",
+				Reports: []*TestReport{
+					test("a", "tests/network/policy/a", "net", 0, ""),
+					test("b", "tests/network/b", "net", 1, "failed"),
+					test("c", "tests/storage/volume/c", "storage", 2, ""),
+					test("d", "tests/network/policy/d", "", 3, ""),
+					test("e", "e", "", 4, "failed"),
+					test("f", "tests/f", "storage", 5, ""),
+				},
+			}
+			report.aggregate()
+			report.GroupBy(tt.groupBy)
+			data, err := XMLSerializer{}.Serialize(report)
+			assert.NoError(t, err)
+			assertGolden(t, tt.golden, data)
+			strictParse(t, data)
+			// grouped reports load back with their groups and tests
+			loaded, err := Parse(data, v1alpha1.XMLFormat)
+			assert.NoError(t, err)
+			assert.Len(t, loaded.Reports, 6)
+			assert.Equal(t, len(report.Groups), len(loaded.Groups))
+			assert.Equal(t, report.Failures, loaded.Failures)
+			again, err := XMLSerializer{}.Serialize(loaded)
+			assert.NoError(t, err)
+			assert.Equal(t, string(data), string(again))
+		})
+	}
+}
+
+func TestTestsReport_FlatXML(t *testing.T) {
+	report := groupedReport()
+	data, err := xml.Marshal(report)
+	assert.NoError(t, err)
+	var loaded TestsReport
+	assert.NoError(t, xml.Unmarshal(data, &loaded))
+	assert.Nil(t, loaded.Groups)
+	assert.Len(t, loaded.Reports, 4)
+	assert.Equal(t, "tests/other/b", loaded.Reports[1].Path)
+	assert.Equal(t, 2, loaded.Failures)
+}
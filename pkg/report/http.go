@@ -0,0 +1,165 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultHTTPTimeout = 30 * time.Second
+	defaultHTTPBackoff = time.Second
+	// maxErrorBodySize is the maximum number of response body bytes included in an upload error.
+	maxErrorBodySize = 512
+)
+
+// HTTPReportSink uploads serialized reports to an HTTP endpoint.
+type HTTPReportSink struct {
+	// URL is the endpoint the report is uploaded to.
+	URL string
+	// Method is the HTTP method, defaults to POST.
+	Method string
+	// Headers are added to the request, typically used for bearer tokens.
+	Headers map[string]string
+	// ContentType overrides the content type derived from the serializer.
+	ContentType string
+	// Timeout applies to each attempt, defaults to 30s.
+	Timeout time.Duration
+	// Retries is the number of additional attempts made when the server responds with a 5xx status.
+	Retries int
+	// Backoff is the delay before the first retry, it doubles after each retry. Defaults to 1s.
+	Backoff time.Duration
+	// FailOnError indicates that a failed upload should fail the run.
+	FailOnError bool
+	// Client is the HTTP client used to upload the report, defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+// UploadError is returned when a report could not be uploaded.
+type UploadError struct {
+	// StatusCode is the status of the last response, zero if no response was received.
+	StatusCode int
+	// Body is a snippet of the last response body.
+	Body string
+	// Fatal indicates the error should fail the run.
+	Fatal bool
+	// Err is the underlying error if no response was received.
+	Err error
+}
+
+func (e *UploadError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("failed to upload report: %v", e.Err)
+	}
+	return fmt.Sprintf("failed to upload report: unexpected status code %d: %s", e.StatusCode, e.Body)
+}
+
+func (e *UploadError) Unwrap() error {
+	return e.Err
+}
+
+// Upload serializes the report and uploads it, retrying with backoff on 5xx responses.
+func (s *HTTPReportSink) Upload(ctx context.Context, report *TestsReport, serializer ReportSerializer) error {
+	data, err := serializer.Serialize(report)
+	if err != nil {
+		return err
+	}
+	contentType := s.ContentType
+	if contentType == "" {
+		contentType = ContentType(serializer)
+	}
+	return s.upload(ctx, contentType, data)
+}
+
+// Write uploads data to the sink URL, it implements ReportSink.
+// The content type is derived from the name extension unless ContentType is set.
+func (s *HTTPReportSink) Write(ctx context.Context, name string, data []byte) error {
+	contentType := s.ContentType
+	if contentType == "" {
+		contentType = contentTypeFromName(name)
+	}
+	return s.upload(ctx, contentType, data)
+}
+
+func (s *HTTPReportSink) upload(ctx context.Context, contentType string, data []byte) error {
+	backoff := s.Backoff
+	if backoff <= 0 {
+		backoff = defaultHTTPBackoff
+	}
+	var uploadErr *UploadError
+	for attempt := 0; attempt <= s.Retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return &UploadError{Fatal: s.FailOnError, Err: ctx.Err()}
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+		uploadErr = s.attempt(ctx, contentType, data)
+		if uploadErr == nil {
+			return nil
+		}
+		// only server errors are worth retrying
+		if uploadErr.Err == nil && uploadErr.StatusCode < http.StatusInternalServerError {
+			break
+		}
+	}
+	return uploadErr
+}
+
+func (s *HTTPReportSink) attempt(ctx context.Context, contentType string, data []byte) *UploadError {
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = defaultHTTPTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	method := s.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.URL, bytes.NewReader(data))
+	if err != nil {
+		return &UploadError{Fatal: s.FailOnError, Err: err}
+	}
+	req.Header.Set("Content-Type", contentType)
+	for key, value := range s.Headers {
+		req.Header.Set(key, value)
+	}
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return &UploadError{Fatal: s.FailOnError, Err: err}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	return &UploadError{
+		StatusCode: resp.StatusCode,
+		Body:       strings.TrimSpace(string(body)),
+		Fatal:      s.FailOnError,
+	}
+}
+
+// ContentType returns the media type of the data produced by the serializer.
+func ContentType(serializer ReportSerializer) string {
+	switch serializer.(type) {
+	case JSONSerializer, *JSONSerializer:
+		return "application/json"
+	case XMLSerializer, *XMLSerializer:
+		return "application/xml"
+	default:
+		return "application/octet-stream"
+	}
+}
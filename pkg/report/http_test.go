@@ -0,0 +1,111 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPReportSink_Upload(t *testing.T) {
+	tests := []struct {
+		name        string
+		statuses    []int
+		retries     int
+		failOnError bool
+		want        *UploadError
+		wantCalls   int32
+	}{{
+		name:      "success",
+		statuses:  []int{http.StatusCreated},
+		wantCalls: 1,
+	}, {
+		name:      "retry on server error",
+		statuses:  []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK},
+		retries:   2,
+		wantCalls: 3,
+	}, {
+		name:        "retries exhausted",
+		statuses:    []int{http.StatusInternalServerError, http.StatusInternalServerError},
+		retries:     1,
+		failOnError: true,
+		want:        &UploadError{StatusCode: http.StatusInternalServerError, Body: "status 500", Fatal: true},
+		wantCalls:   2,
+	}, {
+		name:      "no retry on client error",
+		statuses:  []int{http.StatusUnauthorized, http.StatusOK},
+		retries:   3,
+		want:      &UploadError{StatusCode: http.StatusUnauthorized, Body: "status 401"},
+		wantCalls: 1,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				call := calls.Add(1)
+				assert.Equal(t, http.MethodPut, r.Method)
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.Contains(t, string(body), `"name": "suite"`)
+				status := tt.statuses[call-1]
+				w.WriteHeader(status)
+				fmt.Fprintf(w, "status %d", status)
+			}))
+			defer server.Close()
+			sink := &HTTPReportSink{
+				URL:         server.URL,
+				Method:      http.MethodPut,
+				Headers:     map[string]string{"Authorization": "Bearer secret"},
+				Retries:     tt.retries,
+				Backoff:     time.Millisecond,
+				FailOnError: tt.failOnError,
+			}
+			err := sink.Upload(context.Background(), NewTests("suite"), JSONSerializer{})
+			if tt.want == nil {
+				assert.NoError(t, err)
+			} else {
+				var uploadErr *UploadError
+				assert.True(t, errors.As(err, &uploadErr))
+				assert.Equal(t, tt.want, uploadErr)
+			}
+			assert.Equal(t, tt.wantCalls, calls.Load())
+		})
+	}
+}
+
+func TestHTTPReportSink_UploadTimeout(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(done)
+	sink := &HTTPReportSink{URL: server.URL, Timeout: 10 * time.Millisecond}
+	err := sink.Upload(context.Background(), NewTests("suite"), XMLSerializer{})
+	var uploadErr *UploadError
+	assert.True(t, errors.As(err, &uploadErr))
+	assert.Error(t, uploadErr.Err)
+	assert.False(t, uploadErr.Fatal)
+}
+
+func TestUploadError_Error(t *testing.T) {
+	assert.Equal(t, "failed to upload report: unexpected status code 400: bad request", (&UploadError{StatusCode: 400, Body: "bad request"}).Error())
+	assert.Equal(t, "failed to upload report: boom", (&UploadError{Err: errors.New("boom")}).Error())
+}
+
+func TestContentType(t *testing.T) {
+	assert.Equal(t, "application/json", ContentType(JSONSerializer{}))
+	assert.Equal(t, "application/xml", ContentType(XMLSerializer{}))
+}
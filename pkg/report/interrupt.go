@@ -0,0 +1,61 @@
+package report
+
+import (
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/utils/clock"
+)
+
+// InterruptedMessage is the failure message of tests that didn't complete because the run was interrupted.
+const InterruptedMessage = "interrupted"
+
+// Interrupt marks the tests that didn't complete as interrupted failures and ends them now, along with their running operations.
+// Completed tests are left untouched.
+// It is meant to finalize a partial report when the run is cancelled, Close should be called afterwards.
+// It returns the number of interrupted tests.
+func (tr *TestsReport) Interrupt() int {
+	tr.lock.Lock()
+	defer tr.lock.Unlock()
+	now := clock.OrReal(tr.clock).Now()
+	interrupted := 0
+	for _, test := range tr.Reports {
+		if test.interrupt(now) {
+			interrupted++
+		}
+	}
+	return interrupted
+}
+
+func (t *TestReport) interrupt(now time.Time) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.Time != "" {
+		return false
+	}
+	t.Interrupted = true
+	if t.Failure == nil {
+		t.Failure = &Failure{Message: InterruptedMessage}
+	}
+	t.Time = calculateDuration(t.TimeStamp, now)
+	t.Test = 0
+	for _, step := range t.Steps {
+		step.lock.Lock()
+		t.Test += len(step.Results)
+		for _, op := range step.Results {
+			op.interrupt(now)
+		}
+		step.lock.Unlock()
+	}
+	return true
+}
+
+func (op *OperationReport) interrupt(now time.Time) {
+	op.lock.Lock()
+	defer op.lock.Unlock()
+	if op.Time != "" {
+		return
+	}
+	op.Time = calculateDuration(op.TimeStamp, now)
+	op.Result = "Failure"
+	op.Message = InterruptedMessage
+}
@@ -0,0 +1,39 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTestsReport_Interrupt(t *testing.T) {
+	testsReport := NewTests("suite")
+	completed := NewTest("completed")
+	testsReport.AddTest(completed)
+	completed.MarkTestEnd()
+	running := NewTest("running")
+	step := NewTestSpecStep("step-1")
+	step.AddOperation(NewOperation("apply", OperationTypeApply))
+	running.AddTestStep(step)
+	testsReport.AddTest(running)
+	failing := NewTest("failing")
+	failing.NewFailure("assertion failed")
+	testsReport.AddTest(failing)
+	assert.Equal(t, 2, testsReport.Interrupt())
+	testsReport.Close()
+	assert.False(t, completed.Interrupted)
+	assert.Nil(t, completed.Failure)
+	assert.True(t, running.Interrupted)
+	assert.Equal(t, &Failure{Message: InterruptedMessage}, running.Failure)
+	assert.NotEmpty(t, running.Time)
+	assert.Equal(t, 1, running.Test)
+	assert.Equal(t, "Failure", step.Results[0].Result)
+	assert.Equal(t, InterruptedMessage, step.Results[0].Message)
+	// the failure that happened before the interruption is kept
+	assert.True(t, failing.Interrupted)
+	assert.Equal(t, &Failure{Message: "assertion failed"}, failing.Failure)
+	assert.Equal(t, 2, testsReport.Failures)
+	assert.NoError(t, testsReport.Validate())
+	// interrupting again doesn't change anything
+	assert.Equal(t, 0, testsReport.Interrupt())
+}
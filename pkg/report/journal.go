@@ -0,0 +1,165 @@
+package report
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// JournalPath returns the path of the journal associated with a report file.
+func JournalPath(filePath string) string {
+	return filePath + ".journal"
+}
+
+// journalEntry is a single line of a journal, it holds either the suite header or a completed test.
+type journalEntry struct {
+	Suite *journalSuite `json:"suite,omitempty"`
+	Test  *TestReport   `json:"test,omitempty"`
+}
+
+type journalSuite struct {
+	Name      string    `json:"name"`
+	RunID     string    `json:"runId,omitempty"`
+	Git       *GitInfo  `json:"git,omitempty"`
+	Parallel  int       `json:"parallel,omitempty"`
+	TimeStamp time.Time `json:"timestamp"`
+}
+
+// Journal persists test reports as NDJSON lines as soon as tests complete,
+// so that a partial report can be recovered if the process dies before the final report is written.
+type Journal struct {
+	lock sync.Mutex
+	path string
+	file *os.File
+	err  error
+}
+
+// NewJournal creates a journal at the given path for the given suite, truncating any existing one.
+func NewJournal(path string, report *TestsReport) (*Journal, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create journal directory %s: %w", dir, err)
+		}
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	journal := &Journal{
+		path: path,
+		file: file,
+	}
+	if err := journal.write(journalEntry{Suite: &journalSuite{Name: report.Name, RunID: report.RunID, Git: report.Git, Parallel: report.Parallel, TimeStamp: report.TimeStamp}}); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return journal, nil
+}
+
+// Record appends a completed test to the journal, it is safe for concurrent use.
+// The first error encountered is retained and returned by subsequent calls and by Close.
+func (j *Journal) Record(test *TestReport) error {
+	return j.write(journalEntry{Test: test})
+}
+
+func (j *Journal) write(entry journalEntry) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	if j.err != nil {
+		return j.err
+	}
+	if j.file == nil {
+		j.err = errors.New("journal is closed")
+		return j.err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		j.err = err
+		return err
+	}
+	if _, err := j.file.Write(append(data, '\n')); err != nil {
+		j.err = err
+		return err
+	}
+	if err := j.file.Sync(); err != nil {
+		j.err = err
+		return err
+	}
+	return nil
+}
+
+// Close closes the journal file and returns the first error encountered while writing.
+func (j *Journal) Close() error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	if j.file == nil {
+		return j.err
+	}
+	err := j.file.Close()
+	j.file = nil
+	if j.err != nil {
+		return j.err
+	}
+	return err
+}
+
+// Remove closes and deletes the journal, it is typically called once the final report has been written.
+// Write errors are ignored as the journal content is not needed anymore.
+func (j *Journal) Remove() error {
+	_ = j.Close()
+	return os.Remove(j.path)
+}
+
+// RecoverFromJournal rebuilds a partial TestsReport from a journal.
+// A truncated trailing line, as left by a process killed mid-write, is ignored.
+func RecoverFromJournal(path string) (*TestsReport, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var report *TestsReport
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		var entry journalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			// only the last line can be partially written
+			if !scanner.Scan() {
+				break
+			}
+			return nil, fmt.Errorf("failed to parse journal %s at line %d: %w", path, line, err)
+		}
+		if entry.Suite != nil {
+			report = &TestsReport{
+				Name:      entry.Suite.Name,
+				RunID:     entry.Suite.RunID,
+				Git:       entry.Suite.Git,
+				Version:   FormatVersion,
+				Parallel:  entry.Suite.Parallel,
+				TimeStamp: entry.Suite.TimeStamp,
+				Reports:   []*TestReport{},
+			}
+		} else if entry.Test != nil {
+			if report == nil {
+				return nil, fmt.Errorf("journal %s has no suite header", path)
+			}
+			report.Reports = append(report.Reports, entry.Test)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if report == nil {
+		return nil, fmt.Errorf("journal %s has no suite header", path)
+	}
+	report.Close()
+	return report, nil
+}
@@ -0,0 +1,111 @@
+package report
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", JournalPath("chainsaw-report.json"))
+	report := NewTests("SampleTestSuite")
+	report.SetGit(&GitInfo{Commit: "0123456789abcdef0123456789abcdef01234567", Branch: "main"})
+	journal, err := NewJournal(path, report)
+	assert.NoError(t, err)
+	report.SetJournal(journal)
+	const count = 100
+	tests := make([]*TestReport, 0, count)
+	for i := 0; i < count; i++ {
+		test := NewTest(fmt.Sprintf("test-%d", i))
+		report.AddTest(test)
+		tests = append(tests, test)
+	}
+	var wg sync.WaitGroup
+	for i, test := range tests {
+		wg.Add(1)
+		go func(i int, test *TestReport) {
+			defer wg.Done()
+			if i%2 == 0 {
+				test.NewFailure("test failed")
+			}
+			test.MarkTestEnd()
+		}(i, test)
+	}
+	wg.Wait()
+	assert.NoError(t, journal.Close())
+	recovered, err := RecoverFromJournal(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "SampleTestSuite", recovered.Name)
+	assert.True(t, report.TimeStamp.Equal(recovered.TimeStamp))
+	assert.Equal(t, report.Git, recovered.Git)
+	assert.Len(t, recovered.Reports, count)
+	assert.Equal(t, count/2, recovered.Failures)
+	names := map[string]bool{}
+	for _, test := range recovered.Reports {
+		names[test.Name] = true
+	}
+	assert.Len(t, names, count)
+}
+
+func TestRecoverFromJournal_Truncated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.journal")
+	report := NewTests("SampleTestSuite")
+	journal, err := NewJournal(path, report)
+	assert.NoError(t, err)
+	report.SetJournal(journal)
+	test := NewTest("test-1")
+	report.AddTest(test)
+	test.MarkTestEnd()
+	assert.NoError(t, journal.Close())
+	// simulate a process killed while writing the next line
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	assert.NoError(t, err)
+	_, err = f.WriteString(`{"test":{"name":"test-2","times`)
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+	recovered, err := RecoverFromJournal(path)
+	assert.NoError(t, err)
+	assert.Len(t, recovered.Reports, 1)
+	assert.Equal(t, "test-1", recovered.Reports[0].Name)
+}
+
+func TestRecoverFromJournal_Errors(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		content string
+	}{{
+		name:    "empty",
+		content: "",
+	}, {
+		name:    "no header",
+		content: "{\"test\":{\"name\":\"test-1\"}}\n",
+	}, {
+		name:    "corrupted line",
+		content: "{\"suite\":{\"name\":\"suite\"}}\nnot json\n{\"test\":{\"name\":\"test-1\"}}\n",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name)
+			assert.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+			_, err := RecoverFromJournal(path)
+			assert.Error(t, err)
+		})
+	}
+	_, err := RecoverFromJournal(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+func TestJournal_Remove(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.journal")
+	journal, err := NewJournal(path, NewTests("SampleTestSuite"))
+	assert.NoError(t, err)
+	assert.NoError(t, journal.Remove())
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+	assert.Error(t, journal.Record(NewTest("test-1")), "recording in a removed journal should fail")
+}
@@ -0,0 +1,100 @@
+package report
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	v1alpha1 "github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
+)
+
+// gzipMagic are the first bytes of gzip compressed data.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// utf8BOM is the byte order mark some tools write at the beginning of text files.
+var utf8BOM = []byte{0xef, 0xbb, 0xbf}
+
+// Load reads a report from the file at the given path, gzip compressed files are transparently decompressed.
+// The format is detected from the file extension, falling back to the file content.
+func Load(path string) (*TestsReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err = decompress(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress report %s: %w", path, err)
+	}
+	report, err := Parse(data, formatFromName(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse report %s: %w", path, err)
+	}
+	return report, nil
+}
+
+// Parse parses a serialized report, an empty format is detected from the content.
+// Unknown fields are ignored so that reports written by newer versions can still be read.
+func Parse(data []byte, format v1alpha1.ReportFormatType) (*TestsReport, error) {
+	if format == v1alpha1.NoReport {
+		format = sniffFormat(data)
+		if format == v1alpha1.NoReport {
+			return nil, fmt.Errorf("unable to detect report format")
+		}
+	}
+	format, err := ParseFormat(string(format))
+	if err != nil {
+		return nil, err
+	}
+	var report TestsReport
+	switch format {
+	case v1alpha1.JSONFormat:
+		if err := json.Unmarshal(data, &report); err != nil {
+			return nil, err
+		}
+	case v1alpha1.XMLFormat:
+		if err := xml.Unmarshal(data, &report); err != nil {
+			return nil, err
+		}
+	}
+	return &report, nil
+}
+
+// formatFromName returns the report format matching the file extension, ignoring the .gz extension.
+func formatFromName(path string) v1alpha1.ReportFormatType {
+	ext := filepath.Ext(strings.TrimSuffix(path, ".gz"))
+	if format, err := ParseFormat(strings.TrimPrefix(ext, ".")); err == nil {
+		return format
+	}
+	return v1alpha1.NoReport
+}
+
+// sniffFormat returns the report format matching the first significant byte of data.
+func sniffFormat(data []byte) v1alpha1.ReportFormatType {
+	data = bytes.TrimLeft(bytes.TrimPrefix(data, utf8BOM), " \t\r\n")
+	switch {
+	case bytes.HasPrefix(data, []byte("{")):
+		return v1alpha1.JSONFormat
+	case bytes.HasPrefix(data, []byte("<")):
+		return v1alpha1.XMLFormat
+	}
+	return v1alpha1.NoReport
+}
+
+// decompress decompresses gzip data, detected by magic bytes, other data is returned unchanged.
+func decompress(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, gzipMagic) {
+		return data, nil
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
@@ -0,0 +1,170 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	v1alpha1 "github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		options SaveOptions
+	}{{
+		name: "plain",
+	}, {
+		name:    "compressed",
+		options: SaveOptions{Compress: true},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := syntheticReport(3)
+			filePath := filepath.Join(t.TempDir(), "report.json")
+			assert.NoError(t, SaveReportWithOptions(report, JSONSerializer{}, filePath, tt.options))
+			if tt.options.Compress {
+				filePath += ".gz"
+			}
+			loaded, err := Load(filePath)
+			assert.NoError(t, err)
+			assert.Equal(t, report.Name, loaded.Name)
+			assert.Len(t, loaded.Reports, 3)
+			assert.Equal(t, report.Test, loaded.Test)
+		})
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	dir := t.TempDir()
+	_, err := Load(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+	invalid := filepath.Join(dir, "invalid.json")
+	assert.NoError(t, os.WriteFile(invalid, []byte("not json"), 0o600))
+	_, err = Load(invalid)
+	assert.Error(t, err)
+	corrupted := filepath.Join(dir, "corrupted.json.gz")
+	assert.NoError(t, os.WriteFile(corrupted, append(gzipMagic, 0x00), 0o600))
+	_, err = Load(corrupted)
+	assert.Error(t, err)
+}
+
+func TestParse_RoundTrip(t *testing.T) {
+	report := syntheticReport(3)
+	report.Reports[0].NewFailure("boom")
+	report.Reports[1].AddWarning(WarningTypeSlowCleanup, "slow")
+	op := report.Reports[2].Steps[0].Results[0]
+	op.RecordAttempt(report.TimeStamp)
+	op.RecordAttempt(report.TimeStamp.Add(time.Second))
+	report.Recompute()
+	expected, err := JSONSerializer{}.Serialize(report)
+	assert.NoError(t, err)
+	tests := []struct {
+		name       string
+		serializer ReportSerializer
+		format     v1alpha1.ReportFormatType
+	}{{
+		name:       "json",
+		serializer: JSONSerializer{},
+		format:     v1alpha1.JSONFormat,
+	}, {
+		name:       "xml",
+		serializer: XMLSerializer{},
+		format:     v1alpha1.XMLFormat,
+	}, {
+		name:       "json sniffed",
+		serializer: JSONSerializer{},
+	}, {
+		name:       "xml sniffed",
+		serializer: XMLSerializer{},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.serializer.Serialize(report)
+			assert.NoError(t, err)
+			loaded, err := Parse(data, tt.format)
+			assert.NoError(t, err)
+			assert.True(t, report.TimeStamp.Equal(loaded.TimeStamp))
+			assert.True(t, report.Reports[0].TimeStamp.Equal(loaded.Reports[0].TimeStamp))
+			assert.True(t, op.FirstAttemptAt.Equal(*loaded.Reports[2].Steps[0].Results[0].FirstAttemptAt))
+			actual, err := JSONSerializer{}.Serialize(loaded)
+			assert.NoError(t, err)
+			assert.JSONEq(t, string(expected), string(actual))
+		})
+	}
+}
+
+func TestParse_UnknownFields(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{{
+		name: "json",
+		data: `{"name":"suite","future":{"a":1},"tests":1,"failures":0,"testsuite":[{"name":"test","tests":1,"extra":true}]}`,
+	}, {
+		name: "xml",
+		data: "\ufeff" + `<TestsReport name="suite" future="a" tests="1" failures="0"><future/><testsuite name="test" tests="1" extra="true"></testsuite></TestsReport>`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loaded, err := Parse([]byte(tt.data), "")
+			assert.NoError(t, err)
+			assert.Equal(t, "suite", loaded.Name)
+			assert.Len(t, loaded.Reports, 1)
+			assert.Equal(t, "test", loaded.Reports[0].Name)
+		})
+	}
+}
+
+func TestLoad_Formats(t *testing.T) {
+	report := syntheticReport(2)
+	dir := t.TempDir()
+	tests := []struct {
+		name       string
+		file       string
+		serializer ReportSerializer
+		options    SaveOptions
+		load       string
+	}{{
+		name:       "json",
+		file:       "report.json",
+		serializer: JSONSerializer{},
+		load:       "report.json",
+	}, {
+		name:       "xml",
+		file:       "report.xml",
+		serializer: XMLSerializer{},
+		load:       "report.xml",
+	}, {
+		name:       "compressed xml",
+		file:       "compressed.xml",
+		serializer: XMLSerializer{},
+		options:    SaveOptions{Compress: true},
+		load:       "compressed.xml.gz",
+	}, {
+		name:       "no extension",
+		file:       "report",
+		serializer: XMLSerializer{},
+		load:       "report",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NoError(t, SaveReportWithOptions(report, tt.serializer, filepath.Join(dir, tt.file), tt.options))
+			loaded, err := Load(filepath.Join(dir, tt.load))
+			assert.NoError(t, err)
+			assert.Equal(t, report.Name, loaded.Name)
+			assert.Len(t, loaded.Reports, 2)
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	_, err := Parse([]byte("not a report"), "")
+	assert.Error(t, err)
+	_, err = Parse([]byte("{}"), "yaml")
+	assert.Error(t, err)
+	_, err = Parse([]byte("<TestsReport"), v1alpha1.XMLFormat)
+	assert.Error(t, err)
+}
@@ -0,0 +1,74 @@
+package report
+
+import (
+	"encoding/xml"
+	"strings"
+	"time"
+)
+
+// LogLine is a line of console output captured while running a test.
+type LogLine struct {
+	// Time is when the line was logged.
+	Time time.Time `json:"time"`
+	// Message is the logged text, free of ANSI escape sequences.
+	Message string `json:"message"`
+}
+
+// Logs is the console output captured for a test.
+// It is serialized as an array of lines in JSON and as the text of a JUnit <system-out> element in XML.
+type Logs []LogLine
+
+// StripANSI removes ANSI escape sequences from s.
+func StripANSI(s string) string {
+	return ansiEscape.ReplaceAllString(s, "")
+}
+
+// SetLogs records the console output captured for the test, ANSI escape sequences are stripped.
+func (t *TestReport) SetLogs(lines []LogLine) {
+	logs := make(Logs, 0, len(lines))
+	for _, line := range lines {
+		logs = append(logs, LogLine{Time: line.Time, Message: StripANSI(line.Message)})
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.Logs = logs
+}
+
+func (l Logs) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	var text strings.Builder
+	for _, line := range l {
+		text.WriteString(line.Time.Format(time.RFC3339Nano))
+		text.WriteString(" ")
+		text.WriteString(line.Message)
+		text.WriteString("\n")
+	}
+	// chardata isn't newline escaped, keeping the output readable
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	if err := e.EncodeToken(xml.CharData(text.String())); err != nil {
+		return err
+	}
+	return e.EncodeToken(start.End())
+}
+
+func (l *Logs) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var text string
+	if err := d.DecodeElement(&text, &start); err != nil {
+		return err
+	}
+	var logs Logs
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		timestamp, message, _ := strings.Cut(line, " ")
+		if ts, err := time.Parse(time.RFC3339Nano, timestamp); err == nil {
+			logs = append(logs, LogLine{Time: ts, Message: message})
+		} else if len(logs) != 0 {
+			// multi line messages continue the previous line
+			logs[len(logs)-1].Message += "\n" + line
+		} else if line != "" {
+			logs = append(logs, LogLine{Message: line})
+		}
+	}
+	*l = logs
+	return nil
+}
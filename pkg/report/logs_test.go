@@ -0,0 +1,61 @@
+package report
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetLogs(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	test := NewTest("Test1")
+	test.SetLogs([]LogLine{
+		{Time: now, Message: "\x1b[1;31mERROR\x1b[0m something went wrong"},
+		{Time: now.Add(time.Second), Message: "plain"},
+	})
+	assert.Equal(t, Logs{
+		{Time: now, Message: "ERROR something went wrong"},
+		{Time: now.Add(time.Second), Message: "plain"},
+	}, test.Logs)
+}
+
+func TestLogs_Serialize(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	testsReport := NewTests("SampleTestSuite")
+	test := NewTest("Test1")
+	test.SetLogs([]LogLine{
+		{Time: now, Message: "| 10:00:00 | Test1 | step-1 | APPLY | RUN |"},
+		{Time: now.Add(time.Second), Message: "| 10:00:01 | Test1 | step-1 | APPLY | ERROR |\n=== ERROR\nboom <&>"},
+	})
+	testsReport.AddTest(test)
+	testsReport.AddTest(NewTest("Test2"))
+	testsReport.Close()
+	t.Run("json", func(t *testing.T) {
+		data, err := JSONSerializer{}.Serialize(testsReport)
+		assert.NoError(t, err)
+		var raw struct {
+			Tests []map[string]any `json:"testsuite"`
+		}
+		assert.NoError(t, json.Unmarshal(data, &raw))
+		assert.Len(t, raw.Tests[0]["logs"], 2)
+		assert.NotContains(t, raw.Tests[1], "logs")
+		var loaded TestsReport
+		assert.NoError(t, json.Unmarshal(data, &loaded))
+		assert.Equal(t, test.Logs, loaded.Reports[0].Logs)
+	})
+	t.Run("xml", func(t *testing.T) {
+		data, err := XMLSerializer{}.Serialize(testsReport)
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), "<system-out>2024-01-01T10:00:00Z | 10:00:00 | Test1 | step-1 | APPLY | RUN |\n2024-01-01T10:00:01Z")
+		assert.Contains(t, string(data), "boom &lt;&amp;&gt;")
+		assert.Equal(t, 1, strings.Count(string(data), "<system-out>"))
+		var loaded TestsReport
+		assert.NoError(t, xml.Unmarshal(data, &loaded))
+		assert.Equal(t, test.Logs, loaded.Reports[0].Logs)
+		assert.Nil(t, loaded.Reports[1].Logs)
+	})
+}
@@ -0,0 +1,180 @@
+package report
+
+import (
+	"fmt"
+	"time"
+)
+
+type DuplicatePolicy string
+
+const (
+	// DuplicateError fails the merge when the same test name is found in more than one report.
+	DuplicateError DuplicatePolicy = "error"
+	// DuplicateRename renames duplicated tests by appending the index of the report they come from.
+	DuplicateRename DuplicatePolicy = "rename"
+)
+
+// MergeOptions configures how reports are merged.
+type MergeOptions struct {
+	// Name of the merged suite, defaults to the name of the first report.
+	Name string
+	// OnDuplicate determines what happens when the same test name is found in more than one report.
+	// It defaults to DuplicateError.
+	OnDuplicate DuplicatePolicy
+}
+
+// Merge combines multiple reports, typically coming from sharded runs, into a single suite report.
+func Merge(reports ...*TestsReport) (*TestsReport, error) {
+	return MergeWithOptions(MergeOptions{}, reports...)
+}
+
+// MergeWithOptions combines multiple reports into a single suite report.
+// The merged report starts when the earliest report started and ends when the latest report ended,
+// its duration reflects the wall clock span rather than the sum of the reports durations.
+func MergeWithOptions(options MergeOptions, reports ...*TestsReport) (*TestsReport, error) {
+	merged := &TestsReport{
+		Name:    options.Name,
+		Version: FormatVersion,
+		Reports: []*TestReport{},
+	}
+	var start, end time.Time
+	owners := map[string]int{}
+	for i, report := range reports {
+		if report == nil {
+			continue
+		}
+		if merged.Name == "" {
+			merged.Name = report.Name
+		}
+		reportStart, reportEnd, err := report.span()
+		if err != nil {
+			return nil, err
+		}
+		if start.IsZero() || reportStart.Before(start) {
+			start = reportStart
+		}
+		if reportEnd.After(end) {
+			end = reportEnd
+		}
+		for _, test := range report.Reports {
+			// the same name can appear multiple times in a single report when tests are repeated
+			if owner, found := owners[test.Name]; found && owner != i {
+				switch options.OnDuplicate {
+				case DuplicateRename:
+					test = test.renamed(fmt.Sprintf("%s (%d)", test.Name, i+1))
+				default:
+					return nil, fmt.Errorf("duplicate test %s found in reports %d and %d", test.Name, owner+1, i+1)
+				}
+			} else {
+				owners[test.Name] = i
+			}
+			merged.Reports = append(merged.Reports, test)
+		}
+	}
+	merged.Git = commonGit(reports)
+	merged.Parallel = commonParallel(reports)
+	merged.TimeStamp = start
+	merged.Time = calculateDuration(start, end)
+	merged.aggregate()
+	return merged, nil
+}
+
+// commonGit returns the revision recorded by all the reports, typically sharded runs of the same revision.
+// It returns nil if a report doesn't record one or reports record different ones.
+func commonGit(reports []*TestsReport) *GitInfo {
+	var git *GitInfo
+	for _, report := range reports {
+		if report == nil {
+			continue
+		}
+		if report.Git == nil || (git != nil && *report.Git != *git) {
+			return nil
+		}
+		if git == nil {
+			copied := *report.Git
+			git = &copied
+		}
+	}
+	return git
+}
+
+// commonParallel returns the parallelism limit recorded by all the reports, 0 if reports record different ones.
+func commonParallel(reports []*TestsReport) int {
+	parallel := -1
+	for _, report := range reports {
+		if report == nil {
+			continue
+		}
+		if parallel != -1 && report.Parallel != parallel {
+			return 0
+		}
+		parallel = report.Parallel
+	}
+	return max(parallel, 0)
+}
+
+// span returns the start and end times of a closed report.
+func (tr *TestsReport) span() (time.Time, time.Time, error) {
+	if tr.Time == "" {
+		return tr.TimeStamp, tr.TimeStamp, nil
+	}
+	duration, err := parseDuration(tr.Time)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid duration in report %s: %w", tr.Name, err)
+	}
+	return tr.TimeStamp, tr.TimeStamp.Add(duration), nil
+}
+
+// aggregate computes the counts of the TestsReport from its tests, the caller must own the report lock.
+func (tr *TestsReport) aggregate() {
+	tr.Test = 0
+	tr.Failures = 0
+	tr.QuarantinedFailures = 0
+	tr.Warnings = 0
+	for _, testReport := range tr.Reports {
+		testReport.lock.Lock()
+		if testReport.Failure != nil {
+			if testReport.Quarantined {
+				tr.QuarantinedFailures++
+			} else {
+				tr.Failures++
+			}
+		}
+		tr.Warnings += len(testReport.Warnings)
+		tr.Test += testReport.Test
+		testReport.lock.Unlock()
+	}
+	if tr.groupBy != nil {
+		tr.Groups = groupTests(tr.Reports, tr.groupBy)
+	}
+}
+
+// renamed returns a shallow copy of the TestReport with the given name, the original is left untouched.
+func (t *TestReport) renamed(name string) *TestReport {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return &TestReport{
+		Name:          name,
+		CorrelationID: t.CorrelationID,
+		TimeStamp:     t.TimeStamp,
+		Time:          t.Time,
+		Failure:       t.Failure,
+		Test:          t.Test,
+		Steps:         t.Steps,
+		Concurrent:    t.Concurrent,
+		Iteration:     t.Iteration,
+		Worker:        t.Worker,
+		Namespace:     t.Namespace,
+		Path:          t.Path,
+		Labels:        t.Labels,
+		Environment:   t.Environment,
+		Skip:          t.Skip,
+		SkipReason:    t.SkipReason,
+		Quarantined:   t.Quarantined,
+		Interrupted:   t.Interrupted,
+		SkipDelete:    t.SkipDelete,
+		Warnings:      t.Warnings,
+		Logs:          t.Logs,
+		Artifacts:     t.Artifacts,
+	}
+}
@@ -0,0 +1,137 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	shard := func(name string, offset time.Duration, duration string, tests ...*TestReport) *TestsReport {
+		return &TestsReport{
+			Name:      name,
+			TimeStamp: start.Add(offset),
+			Time:      duration,
+			Reports:   tests,
+		}
+	}
+	passed := func(name string) *TestReport {
+		return &TestReport{Name: name, Test: 2}
+	}
+	failed := func(name string) *TestReport {
+		return &TestReport{Name: name, Test: 1, Failure: &Failure{Message: "test failed"}}
+	}
+	tests := []struct {
+		name          string
+		options       MergeOptions
+		reports       []*TestsReport
+		wantErr       bool
+		wantName      string
+		wantTimeStamp time.Time
+		wantTime      string
+		wantTests     []string
+		wantTest      int
+		wantFailures  int
+	}{{
+		name: "overlapping shards",
+		reports: []*TestsReport{
+			shard("suite", 10*time.Second, "60.000", passed("a"), failed("b")),
+			shard("suite", 0, "30.000", passed("c")),
+		},
+		wantName:      "suite",
+		wantTimeStamp: start,
+		wantTime:      "70.000",
+		wantTests:     []string{"a", "b", "c"},
+		wantTest:      5,
+		wantFailures:  1,
+	}, {
+		name:    "custom name",
+		options: MergeOptions{Name: "merged"},
+		reports: []*TestsReport{
+			shard("suite-1", 0, "1.000", passed("a")),
+			shard("suite-2", 0, "2.000", passed("b")),
+		},
+		wantName:      "merged",
+		wantTimeStamp: start,
+		wantTime:      "2.000",
+		wantTests:     []string{"a", "b"},
+		wantTest:      4,
+	}, {
+		name: "duplicates error",
+		reports: []*TestsReport{
+			shard("suite", 0, "1.000", passed("a")),
+			shard("suite", 0, "1.000", passed("a")),
+		},
+		wantErr: true,
+	}, {
+		name:    "duplicates rename",
+		options: MergeOptions{OnDuplicate: DuplicateRename},
+		reports: []*TestsReport{
+			shard("suite", 0, "1.000", passed("a")),
+			shard("suite", 0, "1.000", failed("a")),
+		},
+		wantName:      "suite",
+		wantTimeStamp: start,
+		wantTime:      "1.000",
+		wantTests:     []string{"a", "a (2)"},
+		wantTest:      3,
+		wantFailures:  1,
+	}, {
+		name: "repeated tests in a single report",
+		reports: []*TestsReport{
+			shard("suite", 0, "1.000", passed("a"), failed("a")),
+		},
+		wantName:      "suite",
+		wantTimeStamp: start,
+		wantTime:      "1.000",
+		wantTests:     []string{"a", "a"},
+		wantTest:      3,
+		wantFailures:  1,
+	}, {
+		name: "invalid duration",
+		reports: []*TestsReport{
+			shard("suite", 0, "abc", passed("a")),
+		},
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, err := MergeWithOptions(tt.options, tt.reports...)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantName, merged.Name)
+			assert.True(t, tt.wantTimeStamp.Equal(merged.TimeStamp))
+			assert.Equal(t, tt.wantTime, merged.Time)
+			var names []string
+			for _, test := range merged.Reports {
+				names = append(names, test.Name)
+			}
+			assert.Equal(t, tt.wantTests, names)
+			assert.Equal(t, tt.wantTest, merged.Test)
+			assert.Equal(t, tt.wantFailures, merged.Failures)
+		})
+	}
+}
+
+func TestMerge_DoesNotMutateInputs(t *testing.T) {
+	first := &TestsReport{Name: "suite", Reports: []*TestReport{{Name: "a"}}}
+	second := &TestsReport{Name: "suite", Reports: []*TestReport{{Name: "a"}}}
+	_, err := MergeWithOptions(MergeOptions{OnDuplicate: DuplicateRename}, first, second)
+	assert.NoError(t, err)
+	assert.Equal(t, "a", second.Reports[0].Name)
+}
+
+func TestMerge_Parallel(t *testing.T) {
+	merged, err := Merge(&TestsReport{Name: "suite", Parallel: 2}, nil, &TestsReport{Name: "suite", Parallel: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, merged.Parallel)
+	// shards run with different limits record none
+	merged, err = Merge(&TestsReport{Name: "suite", Parallel: 2}, &TestsReport{Name: "suite", Parallel: 4})
+	assert.NoError(t, err)
+	assert.Zero(t, merged.Parallel)
+}
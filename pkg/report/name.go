@@ -0,0 +1,105 @@
+package report
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// NameData is the data available to report name and object key templates.
+type NameData struct {
+	// Name of the test suite.
+	Name string
+	// StartTime is when the test suite began execution, in UTC.
+	StartTime time.Time
+	// Date is the start date formatted as 2006-01-02.
+	Date string
+	// RunID identifies the run.
+	RunID string
+	// Ext is the file extension matching the report format, including the leading dot.
+	Ext string
+}
+
+// DefaultSuiteName is the name of the test suite of reports whose name is templated, a template can't render itself.
+const DefaultSuiteName = "chainsaw"
+
+// SuiteName returns the name of the test suite of reports named reportName, DefaultSuiteName if it is templated.
+func SuiteName(reportName string) string {
+	if strings.Contains(reportName, "{{") {
+		return DefaultSuiteName
+	}
+	return reportName
+}
+
+// unsafeNameChars are replaced in templated file names.
+var unsafeNameChars = strings.NewReplacer("/", "-", "\\", "-", ":", "-", "*", "-", "?", "-", "\"", "-", "<", "-", ">", "-", "|", "-")
+
+// newRunID returns a random identifier for a run, falling back to the current time if randomness is not available.
+func newRunID() string {
+	var id [6]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return time.Now().UTC().Format("20060102T150405Z")
+	}
+	return hex.EncodeToString(id[:])
+}
+
+// nameData returns the template data for the report.
+func (tr *TestsReport) nameData(ext string) NameData {
+	start := tr.TimeStamp.UTC()
+	runID := tr.RunID
+	if runID == "" {
+		runID = start.Format("20060102T150405Z")
+	}
+	return NameData{
+		Name:      SuiteName(tr.Name),
+		StartTime: start,
+		Date:      start.Format("2006-01-02"),
+		RunID:     runID,
+		Ext:       ext,
+	}
+}
+
+// renderName renders a go template against the given data.
+func renderName(text string, data NameData) (string, error) {
+	tmpl, err := template.New("name").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid report name template %q: %w", text, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render report name template %q: %w", text, err)
+	}
+	return buf.String(), nil
+}
+
+// ResolveName renders the placeholders of a report name, e.g. `chainsaw-{{ .StartTime.Format "20060102-150405" }}-{{ .RunID }}`.
+// Names without placeholders are returned as is. Templated file names are sanitized so that they always
+// designate a single file, directories should be configured with the report path.
+func (tr *TestsReport) ResolveName(name string) (string, error) {
+	return tr.resolveName(name, "")
+}
+
+// resolveName renders the placeholders of a report name like ResolveName, with ext as the extension of the report.
+func (tr *TestsReport) resolveName(name string, ext string) (string, error) {
+	if !strings.Contains(name, "{{") {
+		return name, nil
+	}
+	resolved, err := renderName(name, tr.nameData(ext))
+	if err != nil {
+		return "", err
+	}
+	if IsFile(name) {
+		resolved = strings.TrimSpace(unsafeNameChars.Replace(resolved))
+		if resolved == "." || resolved == ".." {
+			resolved = ""
+		}
+	}
+	if resolved == "" {
+		return "", fmt.Errorf("report name template %q rendered an empty name", name)
+	}
+	return resolved, nil
+}
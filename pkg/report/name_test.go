@@ -0,0 +1,105 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTestsReport_ResolveName(t *testing.T) {
+	report := &TestsReport{
+		Name:      "suite",
+		RunID:     "abc123",
+		TimeStamp: time.Date(2024, 3, 4, 5, 6, 7, 0, time.FixedZone("CET", 3600)),
+	}
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{{
+		name:  "static",
+		input: "chainsaw-report",
+		want:  "chainsaw-report",
+	}, {
+		name:  "static with separators",
+		input: "reports/chainsaw-report",
+		want:  "reports/chainsaw-report",
+	}, {
+		name:  "start time and run id",
+		input: `chainsaw-{{ .StartTime.Format "20060102-150405" }}-{{ .RunID }}`,
+		want:  "chainsaw-20240304-040607-abc123",
+	}, {
+		name:  "name and date",
+		input: "{{ .Name }}-{{ .Date }}.json",
+		want:  "suite-2024-03-04.json",
+	}, {
+		name:  "separators are sanitized",
+		input: `{{ .StartTime.Format "2006/01/02" }}`,
+		want:  "2024-03-04",
+	}, {
+		name:  "url is not sanitized",
+		input: "https://example.com/{{ .RunID }}",
+		want:  "https://example.com/abc123",
+	}, {
+		name:    "unknown field",
+		input:   "chainsaw-{{ .Unknown }}",
+		wantErr: true,
+	}, {
+		name:    "invalid template",
+		input:   "chainsaw-{{ .Name",
+		wantErr: true,
+	}, {
+		name:    "empty result",
+		input:   `{{ "" }}`,
+		wantErr: true,
+	}, {
+		name:    "parent directory",
+		input:   `{{ ".." }}`,
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := report.ResolveName(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNewTests_RunID(t *testing.T) {
+	first, second := NewTests("suite"), NewTests("suite")
+	assert.Len(t, first.RunID, 12)
+	assert.NotEqual(t, first.RunID, second.RunID)
+}
+
+func TestSaveReportBasedOnType_TemplatedName(t *testing.T) {
+	dir := t.TempDir()
+	report := NewTests("suite")
+	report.RunID = "run"
+	assert.NoError(t, report.SaveReportBasedOnType("JSON", dir, "{{ .Name }}-{{ .RunID }}", SaveOptions{}))
+	_, err := os.Stat(filepath.Join(dir, "suite-run.json"))
+	assert.NoError(t, err)
+	assert.Error(t, report.SaveReportBasedOnType("JSON", dir, "{{ .Missing }}", SaveOptions{}))
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestSuiteName(t *testing.T) {
+	assert.Equal(t, "chainsaw-report", SuiteName("chainsaw-report"))
+	assert.Equal(t, DefaultSuiteName, SuiteName("{{ .Name }}-{{ .RunID }}"))
+	// a templated suite name doesn't render itself
+	report := NewTests("{{ .Name }}-{{ .RunID }}")
+	report.RunID = "run"
+	name, err := report.ResolveName(report.Name)
+	assert.NoError(t, err)
+	assert.Equal(t, "chainsaw-run", name)
+}
@@ -0,0 +1,168 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type NotificationFormat string
+
+const (
+	// NotificationFormatSlack posts a Slack incoming webhook message.
+	NotificationFormatSlack NotificationFormat = "slack"
+	// NotificationFormatWebhook posts the NotificationSummary as JSON.
+	NotificationFormatWebhook NotificationFormat = "webhook"
+)
+
+const (
+	defaultNotificationFailures = 5
+	defaultNotificationRetries  = 2
+)
+
+// NotificationFailure identifies a failed test in a notification.
+type NotificationFailure struct {
+	// Test is the name of the failed test.
+	Test string `json:"test"`
+	// Message is the first line of the failure message.
+	Message string `json:"message,omitempty"`
+}
+
+// NotificationSummary is the compact summary of a run sent in notifications.
+type NotificationSummary struct {
+	// Suite is the name of the test suite.
+	Suite string `json:"suite"`
+	// RunID identifies the run that produced the report.
+	RunID string `json:"runId,omitempty"`
+	// Failed counts the failed tests.
+	Failed int `json:"failed"`
+	// Total counts the tests that ran.
+	Total int `json:"total"`
+	// Duration is the duration of the run.
+	Duration string `json:"duration"`
+	// Failures lists the first failed tests.
+	Failures []NotificationFailure `json:"failures,omitempty"`
+	// More counts the failed tests not listed in Failures.
+	More int `json:"more,omitempty"`
+}
+
+// Summarize builds the notification summary of a report, listing at most maxFailures failed tests.
+// Failures of quarantined tests are left out.
+func Summarize(report *TestsReport, maxFailures int) NotificationSummary {
+	report.lock.Lock()
+	defer report.lock.Unlock()
+	summary := NotificationSummary{
+		Suite: report.Name,
+		RunID: report.RunID,
+		Total: len(report.Reports),
+	}
+	if d, err := parseDuration(report.Time); err == nil {
+		summary.Duration = d.Round(time.Millisecond).String()
+	}
+	for _, test := range report.Reports {
+		test.lock.Lock()
+		failure, quarantined := test.Failure, test.Quarantined
+		test.lock.Unlock()
+		// quarantined tests are known to be flaky, their failures don't page anyone
+		if failure == nil || quarantined {
+			continue
+		}
+		summary.Failed++
+		if len(summary.Failures) < maxFailures {
+			summary.Failures = append(summary.Failures, NotificationFailure{Test: test.Name, Message: firstLine(failure.Message)})
+		} else {
+			summary.More++
+		}
+	}
+	return summary
+}
+
+// Text renders the summary as a short markdown message.
+func (s NotificationSummary) Text() string {
+	var text strings.Builder
+	if s.Failed == 0 {
+		fmt.Fprintf(&text, "*%s*: all %d tests passed", s.Suite, s.Total)
+	} else {
+		fmt.Fprintf(&text, "*%s*: %d/%d tests failed", s.Suite, s.Failed, s.Total)
+	}
+	if s.Duration != "" {
+		fmt.Fprintf(&text, " in %s", s.Duration)
+	}
+	if s.RunID != "" {
+		fmt.Fprintf(&text, " (run %s)", s.RunID)
+	}
+	for _, failure := range s.Failures {
+		fmt.Fprintf(&text, "\n• `%s`", failure.Test)
+		if failure.Message != "" {
+			fmt.Fprintf(&text, ": %s", failure.Message)
+		}
+	}
+	if s.More > 0 {
+		fmt.Fprintf(&text, "\n…and %d more", s.More)
+	}
+	return text.String()
+}
+
+// NotificationSink posts a summary of a finished run to a webhook.
+type NotificationSink struct {
+	// URL is the webhook endpoint.
+	URL string
+	// Format selects the payload, defaults to NotificationFormatSlack.
+	Format NotificationFormat
+	// Always sends the notification even when all tests passed.
+	Always bool
+	// MaxFailures is the number of failed tests listed in the notification, defaults to 5.
+	MaxFailures int
+	// Retries is the number of additional attempts made when the notification can't be delivered, defaults to 2.
+	Retries int
+	// Backoff is the delay before the first retry, it doubles after each retry. Defaults to 1s.
+	Backoff time.Duration
+	// Timeout applies to each attempt, defaults to 30s.
+	Timeout time.Duration
+	// Client is the HTTP client used to post the notification, defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+// Notify posts the summary of the report, nothing is sent when no test failed unless Always is set.
+// Delivery errors are returned as non fatal UploadErrors, a notification never fails the run.
+func (s *NotificationSink) Notify(ctx context.Context, report *TestsReport) error {
+	maxFailures := s.MaxFailures
+	if maxFailures <= 0 {
+		maxFailures = defaultNotificationFailures
+	}
+	summary := Summarize(report, maxFailures)
+	if summary.Failed == 0 && !s.Always {
+		return nil
+	}
+	payload, err := s.payload(summary)
+	if err != nil {
+		return &UploadError{Err: err}
+	}
+	retries := s.Retries
+	if retries <= 0 {
+		retries = defaultNotificationRetries
+	}
+	sink := HTTPReportSink{
+		URL:         s.URL,
+		ContentType: "application/json",
+		Timeout:     s.Timeout,
+		Retries:     retries,
+		Backoff:     s.Backoff,
+		Client:      s.Client,
+	}
+	return sink.upload(ctx, sink.ContentType, payload)
+}
+
+func (s *NotificationSink) payload(summary NotificationSummary) ([]byte, error) {
+	switch s.Format {
+	case "", NotificationFormatSlack:
+		return json.Marshal(map[string]string{"text": summary.Text()})
+	case NotificationFormatWebhook:
+		return json.Marshal(summary)
+	default:
+		return nil, fmt.Errorf("unsupported notification format %q", s.Format)
+	}
+}
@@ -0,0 +1,122 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func notificationReport(failures int) *TestsReport {
+	report := syntheticReport(4)
+	report.Name = "nightly"
+	report.RunID = "0123456789ab"
+	report.Time = "90.500"
+	for i := 0; i < failures; i++ {
+		report.Reports[i].NewFailure("assertion failed\ndetails")
+	}
+	report.Recompute()
+	return report
+}
+
+func TestNotificationSink_Payload(t *testing.T) {
+	tests := []struct {
+		name   string
+		sink   NotificationSink
+		report *TestsReport
+		want   string
+	}{{
+		name:   "slack",
+		sink:   NotificationSink{},
+		report: notificationReport(2),
+		want:   `{"text":"*nightly*: 2/4 tests failed in 1m30.5s (run 0123456789ab)\n• ` + "`test-0`" + `: assertion failed\n• ` + "`test-1`" + `: assertion failed"}`,
+	}, {
+		name:   "slack truncated",
+		sink:   NotificationSink{Format: NotificationFormatSlack, MaxFailures: 1},
+		report: notificationReport(3),
+		want:   `{"text":"*nightly*: 3/4 tests failed in 1m30.5s (run 0123456789ab)\n• ` + "`test-0`" + `: assertion failed\n…and 2 more"}`,
+	}, {
+		name:   "slack always",
+		sink:   NotificationSink{Always: true},
+		report: notificationReport(0),
+		want:   `{"text":"*nightly*: all 4 tests passed in 1m30.5s (run 0123456789ab)"}`,
+	}, {
+		name:   "webhook",
+		sink:   NotificationSink{Format: NotificationFormatWebhook, MaxFailures: 1},
+		report: notificationReport(2),
+		want:   `{"suite":"nightly","runId":"0123456789ab","failed":2,"total":4,"duration":"1m30.5s","failures":[{"test":"test-0","message":"assertion failed"}],"more":1}`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body, contentType string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				data, _ := io.ReadAll(r.Body)
+				body = string(data)
+				contentType = r.Header.Get("Content-Type")
+			}))
+			defer server.Close()
+			tt.sink.URL = server.URL
+			assert.NoError(t, tt.sink.Notify(context.Background(), tt.report))
+			assert.Equal(t, "application/json", contentType)
+			assert.JSONEq(t, tt.want, body)
+		})
+	}
+}
+
+func TestNotificationSink_NoFailures(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer server.Close()
+	sink := NotificationSink{URL: server.URL}
+	assert.NoError(t, sink.Notify(context.Background(), notificationReport(0)))
+	// failures of quarantined tests don't notify
+	quarantined := notificationReport(1)
+	quarantined.Reports[0].Quarantined = true
+	quarantined.Recompute()
+	assert.NoError(t, sink.Notify(context.Background(), quarantined))
+	assert.Equal(t, int32(0), calls.Load())
+}
+
+func TestNotificationSink_Retries(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+	sink := NotificationSink{URL: server.URL, Backoff: time.Millisecond}
+	assert.NoError(t, sink.Notify(context.Background(), notificationReport(1)))
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestNotificationSink_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+	tests := []struct {
+		name string
+		sink NotificationSink
+	}{{
+		name: "unreachable",
+		sink: NotificationSink{URL: server.URL, Backoff: time.Millisecond},
+	}, {
+		name: "unsupported format",
+		sink: NotificationSink{URL: server.URL, Format: "email"},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.sink.Notify(context.Background(), notificationReport(1))
+			var uploadErr *UploadError
+			assert.True(t, errors.As(err, &uploadErr))
+			assert.False(t, uploadErr.Fatal)
+		})
+	}
+}
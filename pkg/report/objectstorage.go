@@ -0,0 +1,166 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// DefaultObjectKeyTemplate is the key template used when none is configured.
+	DefaultObjectKeyTemplate = "{{ .Name }}/{{ .Date }}/{{ .RunID }}{{ .Ext }}"
+	// minPartSize is the minimum part size accepted by S3 compatible multipart uploads.
+	minPartSize = 5 * 1024 * 1024
+	// defaultPartSize is the part size used when none is configured.
+	defaultPartSize = 8 * 1024 * 1024
+)
+
+// ObjectInput describes an object to be written to object storage.
+type ObjectInput struct {
+	Bucket      string
+	Key         string
+	ContentType string
+	// Headers are passed through to the storage provider, typically server side encryption headers.
+	Headers map[string]string
+}
+
+// CompletedPart identifies an uploaded part of a multipart upload.
+type CompletedPart struct {
+	PartNumber int64
+	ETag       string
+}
+
+// ObjectStorageClient is the minimal set of object storage operations needed to upload reports.
+// It keeps provider SDKs out of the sink so that other implementations can be plugged in.
+type ObjectStorageClient interface {
+	PutObject(ctx context.Context, input ObjectInput, data []byte) error
+	CreateMultipartUpload(ctx context.Context, input ObjectInput) (string, error)
+	UploadPart(ctx context.Context, input ObjectInput, uploadID string, partNumber int64, data []byte) (string, error)
+	CompleteMultipartUpload(ctx context.Context, input ObjectInput, uploadID string, parts []CompletedPart) error
+	AbortMultipartUpload(ctx context.Context, input ObjectInput, uploadID string) error
+}
+
+// ObjectStorageSink uploads serialized reports to S3 compatible object storage.
+type ObjectStorageSink struct {
+	// Client performs the storage operations.
+	Client ObjectStorageClient
+	// Bucket is the destination bucket.
+	Bucket string
+	// KeyTemplate is a go template rendered with NameData, defaults to DefaultObjectKeyTemplate.
+	KeyTemplate string
+	// RunID identifies the run in the object key, defaults to the report run id.
+	RunID string
+	// Headers are passed through on upload, typically server side encryption headers.
+	Headers map[string]string
+	// PartSize is the size above which multipart upload is used, and the size of each part.
+	PartSize int64
+	// FailOnError indicates that a failed upload should fail the run.
+	FailOnError bool
+}
+
+// Key renders the object key for the given report.
+func (s *ObjectStorageSink) Key(report *TestsReport, serializer ReportSerializer) (string, error) {
+	keyTemplate := s.KeyTemplate
+	if keyTemplate == "" {
+		keyTemplate = DefaultObjectKeyTemplate
+	}
+	data := report.nameData(extension(serializer))
+	if s.RunID != "" {
+		data.RunID = s.RunID
+	}
+	rendered, err := renderName(keyTemplate, data)
+	if err != nil {
+		return "", err
+	}
+	key := strings.TrimPrefix(filepath.ToSlash(rendered), "/")
+	if key == "" {
+		return "", fmt.Errorf("object key template %q rendered an empty key", keyTemplate)
+	}
+	return key, nil
+}
+
+// Upload serializes the report and uploads it, using a multipart upload for large reports.
+func (s *ObjectStorageSink) Upload(ctx context.Context, report *TestsReport, serializer ReportSerializer) error {
+	data, err := serializer.Serialize(report)
+	if err != nil {
+		return err
+	}
+	key, err := s.Key(report, serializer)
+	if err != nil {
+		return err
+	}
+	return s.put(ctx, key, ContentType(serializer), data)
+}
+
+// Write uploads data under the given key, it implements ReportSink.
+func (s *ObjectStorageSink) Write(ctx context.Context, name string, data []byte) error {
+	return s.put(ctx, name, contentTypeFromName(name), data)
+}
+
+func (s *ObjectStorageSink) put(ctx context.Context, key, contentType string, data []byte) error {
+	input := ObjectInput{
+		Bucket:      s.Bucket,
+		Key:         key,
+		ContentType: contentType,
+		Headers:     s.Headers,
+	}
+	if err := s.upload(ctx, input, data); err != nil {
+		return &UploadError{Fatal: s.FailOnError, Err: fmt.Errorf("s3://%s/%s: %w", input.Bucket, input.Key, err)}
+	}
+	return nil
+}
+
+func (s *ObjectStorageSink) upload(ctx context.Context, input ObjectInput, data []byte) error {
+	partSize := s.PartSize
+	if partSize <= 0 {
+		partSize = defaultPartSize
+	}
+	if partSize < minPartSize {
+		partSize = minPartSize
+	}
+	if int64(len(data)) <= partSize {
+		return s.Client.PutObject(ctx, input, data)
+	}
+	uploadID, err := s.Client.CreateMultipartUpload(ctx, input)
+	if err != nil {
+		return err
+	}
+	var parts []CompletedPart
+	for offset, number := int64(0), int64(1); offset < int64(len(data)); offset, number = offset+partSize, number+1 {
+		end := min(offset+partSize, int64(len(data)))
+		etag, err := s.Client.UploadPart(ctx, input, uploadID, number, data[offset:end])
+		if err != nil {
+			_ = s.Client.AbortMultipartUpload(ctx, input, uploadID)
+			return err
+		}
+		parts = append(parts, CompletedPart{PartNumber: number, ETag: etag})
+	}
+	if err := s.Client.CompleteMultipartUpload(ctx, input, uploadID, parts); err != nil {
+		_ = s.Client.AbortMultipartUpload(ctx, input, uploadID)
+		return err
+	}
+	return nil
+}
+
+// objectDestination returns the destination of a report uploaded to object storage, destinations naming only the
+// bucket get the DefaultObjectKeyTemplate key.
+func objectDestination(destination string) string {
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(destination, "s3://"), "/")
+	if key == "" {
+		return "s3://" + bucket + "/" + DefaultObjectKeyTemplate
+	}
+	return destination
+}
+
+// extension returns the file extension matching the serializer output.
+func extension(serializer ReportSerializer) string {
+	switch serializer.(type) {
+	case JSONSerializer, *JSONSerializer:
+		return ".json"
+	case XMLSerializer, *XMLSerializer:
+		return ".xml"
+	default:
+		return ""
+	}
+}
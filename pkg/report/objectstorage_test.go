@@ -0,0 +1,272 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+type fakeObjectStorageClient struct {
+	objects map[string][]byte
+	inputs  []ObjectInput
+	parts   map[int64][]byte
+	aborted bool
+	failAt  int64
+}
+
+func (c *fakeObjectStorageClient) PutObject(_ context.Context, input ObjectInput, data []byte) error {
+	c.inputs = append(c.inputs, input)
+	if c.objects == nil {
+		c.objects = map[string][]byte{}
+	}
+	c.objects[input.Bucket+"/"+input.Key] = bytes.Clone(data)
+	return nil
+}
+
+func (c *fakeObjectStorageClient) CreateMultipartUpload(_ context.Context, input ObjectInput) (string, error) {
+	c.inputs = append(c.inputs, input)
+	c.parts = map[int64][]byte{}
+	return "upload", nil
+}
+
+func (c *fakeObjectStorageClient) UploadPart(_ context.Context, _ ObjectInput, _ string, partNumber int64, data []byte) (string, error) {
+	if partNumber == c.failAt {
+		return "", errors.New("part failed")
+	}
+	c.parts[partNumber] = data
+	return fmt.Sprintf("etag-%d", partNumber), nil
+}
+
+func (c *fakeObjectStorageClient) CompleteMultipartUpload(ctx context.Context, input ObjectInput, _ string, parts []CompletedPart) error {
+	var data []byte
+	for i, part := range parts {
+		if part.PartNumber != int64(i+1) || part.ETag != fmt.Sprintf("etag-%d", i+1) {
+			return errors.New("unexpected part")
+		}
+		data = append(data, c.parts[part.PartNumber]...)
+	}
+	return c.PutObject(ctx, input, data)
+}
+
+func (c *fakeObjectStorageClient) AbortMultipartUpload(context.Context, ObjectInput, string) error {
+	c.aborted = true
+	return nil
+}
+
+func TestObjectStorageSink_Key(t *testing.T) {
+	report := &TestsReport{Name: "suite", TimeStamp: time.Date(2024, 3, 4, 5, 6, 7, 0, time.UTC)}
+	tests := []struct {
+		name       string
+		template   string
+		runID      string
+		serializer ReportSerializer
+		want       string
+		wantErr    bool
+	}{{
+		name:       "default",
+		serializer: JSONSerializer{},
+		want:       "suite/2024-03-04/20240304T050607Z.json",
+	}, {
+		name:       "run id",
+		template:   "reports/{{ .RunID }}/{{ .Name }}{{ .Ext }}",
+		runID:      "1234",
+		serializer: XMLSerializer{},
+		want:       "reports/1234/suite.xml",
+	}, {
+		name:       "start time",
+		template:   "/{{ .StartTime.Format \"2006/01\" }}/{{ .Name }}",
+		serializer: JSONSerializer{},
+		want:       "2024/03/suite",
+	}, {
+		name:       "unknown field",
+		template:   "{{ .Unknown }}",
+		serializer: JSONSerializer{},
+		wantErr:    true,
+	}, {
+		name:       "invalid template",
+		template:   "{{ .Name",
+		serializer: JSONSerializer{},
+		wantErr:    true,
+	}, {
+		name:       "empty",
+		template:   "{{ \"\" }}",
+		serializer: JSONSerializer{},
+		wantErr:    true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &ObjectStorageSink{KeyTemplate: tt.template, RunID: tt.runID}
+			got, err := sink.Key(report, tt.serializer)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestObjectStorageSink_Upload(t *testing.T) {
+	client := &fakeObjectStorageClient{}
+	sink := &ObjectStorageSink{
+		Client:      client,
+		Bucket:      "bucket",
+		KeyTemplate: "{{ .Name }}{{ .Ext }}",
+		Headers:     map[string]string{"x-amz-server-side-encryption": "aws:kms"},
+	}
+	report := NewTests("suite")
+	assert.NoError(t, sink.Upload(context.Background(), report, JSONSerializer{}))
+	want, err := JSONSerializer{}.Serialize(report)
+	assert.NoError(t, err)
+	assert.Equal(t, want, client.objects["bucket/suite.json"])
+	assert.Equal(t, []ObjectInput{{
+		Bucket:      "bucket",
+		Key:         "suite.json",
+		ContentType: "application/json",
+		Headers:     map[string]string{"x-amz-server-side-encryption": "aws:kms"},
+	}}, client.inputs)
+}
+
+func TestObjectStorageSink_UploadMultipart(t *testing.T) {
+	report := syntheticReport(20000)
+	data, err := JSONSerializer{}.Serialize(report)
+	assert.NoError(t, err)
+	assert.Greater(t, len(data), 2*minPartSize)
+	t.Run("success", func(t *testing.T) {
+		client := &fakeObjectStorageClient{}
+		sink := &ObjectStorageSink{Client: client, Bucket: "bucket", KeyTemplate: "report", PartSize: 1}
+		assert.NoError(t, sink.Upload(context.Background(), report, JSONSerializer{}))
+		assert.Greater(t, len(client.parts), 2)
+		assert.True(t, bytes.Equal(data, client.objects["bucket/report"]))
+		assert.False(t, client.aborted)
+	})
+	t.Run("abort on failure", func(t *testing.T) {
+		client := &fakeObjectStorageClient{failAt: 2}
+		sink := &ObjectStorageSink{Client: client, Bucket: "bucket", KeyTemplate: "report", PartSize: minPartSize, FailOnError: true}
+		err := sink.Upload(context.Background(), report, JSONSerializer{})
+		var uploadErr *UploadError
+		assert.True(t, errors.As(err, &uploadErr))
+		assert.True(t, uploadErr.Fatal)
+		assert.ErrorContains(t, err, "s3://bucket/report")
+		assert.True(t, client.aborted)
+		assert.Empty(t, client.objects)
+	})
+}
+
+// s3Request is a request received by newS3Server.
+type s3Request struct {
+	method  string
+	path    string
+	query   string
+	headers http.Header
+}
+
+// newS3Server returns an S3 compatible endpoint recording the requests it receives, it supports multipart uploads.
+func newS3Server(t *testing.T) (*httptest.Server, *[]s3Request) {
+	t.Helper()
+	var lock sync.Mutex
+	var requests []s3Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		lock.Lock()
+		requests = append(requests, s3Request{method: r.Method, path: r.URL.Path, query: r.URL.RawQuery, headers: r.Header.Clone()})
+		lock.Unlock()
+		switch {
+		case r.Method == http.MethodPost && r.URL.Query().Has("uploads"):
+			_, _ = io.WriteString(w, `<InitiateMultipartUploadResult><UploadId>upload</UploadId></InitiateMultipartUploadResult>`)
+		case r.Method == http.MethodPost:
+			_, _ = io.WriteString(w, `<CompleteMultipartUploadResult><ETag>etag</ETag></CompleteMultipartUploadResult>`)
+		default:
+			w.Header().Set("ETag", `"etag"`)
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("AWS_ACCESS_KEY_ID", "key")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_ENDPOINT_URL", server.URL)
+	return server, &requests
+}
+
+func TestSaveReportBasedOnType_ObjectStorage(t *testing.T) {
+	report := NewTestsWithClock("suite", tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)))
+	report.RunID = "0123456789ab"
+	tests := []struct {
+		name        string
+		format      v1alpha1.ReportFormatType
+		reportName  string
+		options     SaveOptions
+		path        string
+		contentType string
+	}{{
+		name:        "templated key",
+		format:      v1alpha1.JSONFormat,
+		reportName:  "s3://bucket/reports/{{ .Name }}-{{ .RunID }}{{ .Ext }}",
+		path:        "/bucket/reports/suite-0123456789ab.json",
+		contentType: "application/json",
+	}, {
+		name:        "default key",
+		format:      v1alpha1.XMLFormat,
+		reportName:  "s3://bucket",
+		path:        "/bucket/suite/2024-03-01/0123456789ab.xml",
+		contentType: "application/xml",
+	}, {
+		name:        "compressed",
+		format:      v1alpha1.JSONFormat,
+		reportName:  "s3://bucket/{{ .Name }}{{ .Ext }}",
+		options:     SaveOptions{Compress: true},
+		path:        "/bucket/suite.json.gz",
+		contentType: "application/gzip",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, requests := newS3Server(t)
+			assert.NoError(t, report.SaveReportBasedOnType(tt.format, "", tt.reportName, tt.options))
+			assert.Len(t, *requests, 1)
+			assert.Equal(t, http.MethodPut, (*requests)[0].method)
+			assert.Equal(t, tt.path, (*requests)[0].path)
+			assert.Equal(t, tt.contentType, (*requests)[0].headers.Get("Content-Type"))
+		})
+	}
+}
+
+func TestS3Client_MultipartHeaders(t *testing.T) {
+	server, requests := newS3Server(t)
+	client, err := NewS3Client(server.URL, "")
+	assert.NoError(t, err)
+	sink := &ObjectStorageSink{
+		Client: client,
+		Bucket: "bucket",
+		Headers: map[string]string{
+			"x-amz-server-side-encryption-customer-algorithm": "AES256",
+			"x-amz-server-side-encryption-customer-key":       "key",
+		},
+		PartSize: minPartSize,
+	}
+	assert.NoError(t, sink.Write(context.Background(), "report.json", make([]byte, minPartSize+1)))
+	// create, two parts and complete, the SDK encodes the key and adds its digest
+	assert.Len(t, *requests, 4)
+	for _, request := range *requests {
+		assert.Equal(t, "AES256", request.headers.Get("x-amz-server-side-encryption-customer-algorithm"), request.query)
+		assert.Equal(t, "a2V5", request.headers.Get("x-amz-server-side-encryption-customer-key"), request.query)
+		assert.NotEmpty(t, request.headers.Get("x-amz-server-side-encryption-customer-key-MD5"), request.query)
+	}
+}
+
+func Test_customerKeyHeaders(t *testing.T) {
+	assert.Equal(t, map[string]string{"X-Amz-Server-Side-Encryption-Customer-Algorithm": "AES256"}, customerKeyHeaders(map[string]string{
+		"X-Amz-Server-Side-Encryption-Customer-Algorithm": "AES256",
+		"x-amz-server-side-encryption":                    "aws:kms",
+	}))
+}
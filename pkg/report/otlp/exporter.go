@@ -0,0 +1,193 @@
+// Package otlp sends traces built from chainsaw reports to OpenTelemetry backends.
+// It is kept apart from the report package so that only consumers exporting traces depend on the OpenTelemetry SDK.
+package otlp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/kyverno/chainsaw/pkg/report"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies chainsaw as the producer of the spans.
+const instrumentationName = "github.com/kyverno/chainsaw"
+
+// defaultServiceName is the service.name resource attribute used when none is configured.
+const defaultServiceName = "chainsaw"
+
+type Protocol string
+
+const (
+	ProtocolGRPC Protocol = "grpc"
+	ProtocolHTTP Protocol = "http"
+)
+
+// Options configures the OTLP exporter.
+type Options struct {
+	// Endpoint is the host and port of the collector.
+	Endpoint string
+	// Protocol selects OTLP over gRPC or HTTP, defaults to gRPC.
+	Protocol Protocol
+	// Insecure disables transport security.
+	Insecure bool
+	// Headers are sent with every export request, typically used for authentication.
+	Headers map[string]string
+	// ServiceName is the service.name resource attribute, defaults to "chainsaw".
+	ServiceName string
+}
+
+// Exporter converts report traces into OpenTelemetry spans and sends them with a span exporter.
+type Exporter struct {
+	exporter sdktrace.SpanExporter
+	resource *resource.Resource
+}
+
+// New returns an Exporter sending spans with the given span exporter.
+func New(exporter sdktrace.SpanExporter, serviceName string) *Exporter {
+	if serviceName == "" {
+		serviceName = defaultServiceName
+	}
+	return &Exporter{
+		exporter: exporter,
+		resource: resource.NewSchemaless(attribute.String("service.name", serviceName)),
+	}
+}
+
+// NewOTLP returns an Exporter sending spans to an OTLP collector.
+// Settings that aren't part of the options, like transport security and headers, are read from the standard OTEL_EXPORTER_OTLP_* variables.
+func NewOTLP(ctx context.Context, options Options) (*Exporter, error) {
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch options.Protocol {
+	case "", ProtocolGRPC:
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(options.Endpoint)}
+		if len(options.Headers) != 0 {
+			opts = append(opts, otlptracegrpc.WithHeaders(options.Headers))
+		}
+		if options.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		exporter, err = otlptracegrpc.New(ctx, opts...)
+	case ProtocolHTTP:
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(options.Endpoint)}
+		if len(options.Headers) != 0 {
+			opts = append(opts, otlptracehttp.WithHeaders(options.Headers))
+		}
+		if options.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unsupported OTLP protocol %q", options.Protocol)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return New(exporter, options.ServiceName), nil
+}
+
+// Export sends the spans of the trace, span timings are the ones recorded in the report.
+// Spans are recorded with a tracer provider of the SDK, so that their identifiers and hierarchy are built the way the SDK does,
+// and sent in a single batch once the whole trace is recorded.
+func (e *Exporter) Export(ctx context.Context, root *report.Span) error {
+	collector := newCollector()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithResource(e.resource),
+		sdktrace.WithSpanProcessor(collector),
+	)
+	record(ctx, provider.Tracer(instrumentationName), root, trace.WithNewRoot())
+	if err := provider.Shutdown(ctx); err != nil {
+		return err
+	}
+	return e.exporter.ExportSpans(ctx, collector.spans())
+}
+
+// Shutdown flushes and releases the underlying span exporter.
+func (e *Exporter) Shutdown(ctx context.Context) error {
+	return e.exporter.Shutdown(ctx)
+}
+
+// record starts the span with the tracer, records its children and ends it.
+func record(ctx context.Context, tracer trace.Tracer, span *report.Span, options ...trace.SpanStartOption) {
+	options = append(options,
+		trace.WithTimestamp(span.Start),
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(attributes(span.Attributes)...),
+	)
+	ctx, recorded := tracer.Start(ctx, span.Name, options...)
+	switch span.Status {
+	case report.SpanStatusOk:
+		recorded.SetStatus(codes.Ok, "")
+	case report.SpanStatusError:
+		recorded.SetStatus(codes.Error, span.Message)
+	}
+	for _, event := range span.Events {
+		recorded.AddEvent(event.Name, trace.WithTimestamp(event.Time), trace.WithAttributes(attributes(event.Attributes)...))
+	}
+	for _, child := range span.Children {
+		record(ctx, tracer, child)
+	}
+	recorded.End(trace.WithTimestamp(span.End))
+}
+
+// collector is a span processor keeping the ended spans in the order they were started, parents before their children.
+type collector struct {
+	lock  sync.Mutex
+	order []trace.SpanID
+	ended map[trace.SpanID]sdktrace.ReadOnlySpan
+}
+
+func newCollector() *collector {
+	return &collector{ended: map[trace.SpanID]sdktrace.ReadOnlySpan{}}
+}
+
+func (c *collector) OnStart(_ context.Context, span sdktrace.ReadWriteSpan) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.order = append(c.order, span.SpanContext().SpanID())
+}
+
+func (c *collector) OnEnd(span sdktrace.ReadOnlySpan) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.ended[span.SpanContext().SpanID()] = span
+}
+
+func (c *collector) Shutdown(context.Context) error { return nil }
+
+func (c *collector) ForceFlush(context.Context) error { return nil }
+
+// spans returns the ended spans.
+func (c *collector) spans() []sdktrace.ReadOnlySpan {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	out := make([]sdktrace.ReadOnlySpan, 0, len(c.ended))
+	for _, id := range c.order {
+		if span, ok := c.ended[id]; ok {
+			out = append(out, span)
+		}
+	}
+	return out
+}
+
+// attributes converts string attributes, skipping empty values.
+func attributes(in map[string]string) []attribute.KeyValue {
+	out := make([]attribute.KeyValue, 0, len(in))
+	for key, value := range in {
+		if value != "" {
+			out = append(out, attribute.String(key, value))
+		}
+	}
+	// sorted attributes keep the output stable
+	set := attribute.NewSet(out...)
+	return set.ToSlice()
+}
@@ -0,0 +1,123 @@
+package otlp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/report"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestExporter_Export(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	root := &report.Span{
+		Name:       "suite",
+		Start:      start,
+		End:        start.Add(time.Minute),
+		Status:     report.SpanStatusError,
+		Message:    "1 test failed",
+		Attributes: map[string]string{"chainsaw.suite": "suite", "chainsaw.run_id": ""},
+		Children: []*report.Span{{
+			Name:   "test",
+			Start:  start.Add(time.Second),
+			End:    start.Add(10 * time.Second),
+			Status: report.SpanStatusError,
+			Events: []report.SpanEvent{{Name: "failure", Time: start.Add(10 * time.Second), Attributes: map[string]string{"message": "boom"}}},
+			Children: []*report.Span{{
+				Name:   "step",
+				Start:  start.Add(2 * time.Second),
+				End:    start.Add(3 * time.Second),
+				Status: report.SpanStatusOk,
+			}},
+		}, {
+			Name:  "skipped",
+			Start: start,
+			End:   start,
+		}},
+	}
+	memory := tracetest.NewInMemoryExporter()
+	exporter := New(memory, "")
+	assert.NoError(t, exporter.Export(context.Background(), root))
+	spans := memory.GetSpans()
+	assert.Len(t, spans, 4)
+	suite, test, step, skipped := spans[0], spans[1], spans[2], spans[3]
+	// timings come from the report
+	assert.Equal(t, start, suite.StartTime)
+	assert.Equal(t, start.Add(time.Minute), suite.EndTime)
+	assert.Equal(t, start.Add(2*time.Second), step.StartTime)
+	// hierarchy
+	assert.False(t, suite.Parent.IsValid())
+	assert.Equal(t, suite.SpanContext.SpanID(), test.Parent.SpanID())
+	assert.Equal(t, test.SpanContext.SpanID(), step.Parent.SpanID())
+	assert.Equal(t, suite.SpanContext.SpanID(), skipped.Parent.SpanID())
+	for _, span := range spans {
+		assert.Equal(t, suite.SpanContext.TraceID(), span.SpanContext.TraceID())
+	}
+	assert.Equal(t, 2, suite.ChildSpanCount)
+	// status, attributes and events
+	assert.Equal(t, codes.Error, suite.Status.Code)
+	assert.Equal(t, "1 test failed", suite.Status.Description)
+	assert.Equal(t, codes.Ok, step.Status.Code)
+	assert.Equal(t, codes.Unset, skipped.Status.Code)
+	assert.Equal(t, []attribute.KeyValue{attribute.String("chainsaw.suite", "suite")}, suite.Attributes)
+	assert.Len(t, test.Events, 1)
+	assert.Equal(t, "failure", test.Events[0].Name)
+	assert.Equal(t, []attribute.KeyValue{attribute.String("message", "boom")}, test.Events[0].Attributes)
+	serviceName, _ := suite.Resource.Set().Value("service.name")
+	assert.Equal(t, "chainsaw", serviceName.AsString())
+	assert.Equal(t, instrumentationName, suite.InstrumentationLibrary.Name)
+}
+
+func TestExporter_ExportTrace(t *testing.T) {
+	memory := tracetest.NewInMemoryExporter()
+	testsReport := report.NewTests("suite")
+	test := report.NewTest("test")
+	step := report.NewTestSpecStep("step")
+	op := report.NewOperation("apply", report.OperationTypeApply)
+	op.MarkOperationEnd(nil)
+	step.AddOperation(op)
+	test.AddTestStep(step)
+	test.MarkTestEnd()
+	testsReport.AddTest(test)
+	testsReport.Close()
+	assert.NoError(t, report.ExportTrace(context.Background(), testsReport, New(memory, "e2e")))
+	spans := memory.GetSpans()
+	assert.Len(t, spans, 4)
+	assert.Equal(t, []string{"suite", "test", "step", "apply"}, []string{spans[0].Name, spans[1].Name, spans[2].Name, spans[3].Name})
+	assert.Equal(t, op.TimeStamp, spans[3].StartTime)
+	serviceName, _ := spans[0].Resource.Set().Value("service.name")
+	assert.Equal(t, "e2e", serviceName.AsString())
+}
+
+func TestNewOTLP(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+		wantErr bool
+	}{{
+		name:    "grpc",
+		options: Options{Endpoint: "localhost:4317", Insecure: true},
+	}, {
+		name:    "http",
+		options: Options{Endpoint: "localhost:4318", Protocol: ProtocolHTTP, Insecure: true},
+	}, {
+		name:    "unsupported",
+		options: Options{Protocol: "thrift"},
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter, err := NewOTLP(context.Background(), tt.options)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NoError(t, exporter.Shutdown(context.Background()))
+		})
+	}
+}
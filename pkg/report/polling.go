@@ -0,0 +1,61 @@
+package report
+
+import (
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/utils/clock"
+)
+
+// AttemptIntervalStats summarizes the gaps between consecutive evaluations of a polling operation.
+type AttemptIntervalStats struct {
+	// Min is the shortest gap between two consecutive evaluations.
+	Min string `json:"min" xml:"min,attr"`
+	// Max is the longest gap between two consecutive evaluations.
+	Max string `json:"max" xml:"max,attr"`
+	// Avg is the average gap between two consecutive evaluations.
+	Avg string `json:"avg" xml:"avg,attr"`
+}
+
+// RecordAttempt records an evaluation of a polling operation, or an attempt of a retried operation, that happened at
+// the given time.
+func (op *OperationReport) RecordAttempt(at time.Time) {
+	op.lock.Lock()
+	defer op.lock.Unlock()
+	op.Attempts++
+	if op.FirstAttemptAt == nil {
+		op.FirstAttemptAt = &at
+		op.LastAttemptAt = &at
+		return
+	}
+	interval := at.Sub(*op.LastAttemptAt)
+	op.LastAttemptAt = &at
+	if op.Attempts == 2 || interval < op.minInterval {
+		op.minInterval = interval
+	}
+	if op.Attempts == 2 || interval > op.maxInterval {
+		op.maxInterval = interval
+	}
+	avg := op.LastAttemptAt.Sub(*op.FirstAttemptAt) / time.Duration(op.Attempts-1)
+	op.AttemptIntervalStats = &AttemptIntervalStats{
+		Min: formatDuration(op.minInterval),
+		Max: formatDuration(op.maxInterval),
+		Avg: formatDuration(avg),
+	}
+}
+
+// RecordAttemptNow records an evaluation of a polling operation, or an attempt of a retried operation, stamped with
+// the clock of the operation report.
+func (op *OperationReport) RecordAttemptNow() {
+	op.RecordAttempt(clock.OrReal(op.clock).Now())
+}
+
+// WaitedFor returns the duration between the first and the last evaluation of a polling operation.
+// It returns zero for non polling operations.
+func (op *OperationReport) WaitedFor() time.Duration {
+	op.lock.Lock()
+	defer op.lock.Unlock()
+	if op.FirstAttemptAt == nil || op.LastAttemptAt == nil {
+		return 0
+	}
+	return op.LastAttemptAt.Sub(*op.FirstAttemptAt)
+}
@@ -0,0 +1,60 @@
+package report
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordAttempt(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		attempts  []time.Duration
+		wantStats *AttemptIntervalStats
+		wantWait  time.Duration
+	}{{
+		name:      "none",
+		attempts:  nil,
+		wantStats: nil,
+		wantWait:  0,
+	}, {
+		name:      "single",
+		attempts:  []time.Duration{0},
+		wantStats: nil,
+		wantWait:  0,
+	}, {
+		name:     "multiple",
+		attempts: []time.Duration{0, 100 * time.Millisecond, 150 * time.Millisecond, 450 * time.Millisecond},
+		wantStats: &AttemptIntervalStats{
+			Min: "0.050",
+			Max: "0.300",
+			Avg: "0.150",
+		},
+		wantWait: 450 * time.Millisecond,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op := NewOperation("Assert", OperationTypeAssert)
+			for _, offset := range tt.attempts {
+				op.RecordAttempt(start.Add(offset))
+			}
+			assert.Equal(t, len(tt.attempts), op.Attempts)
+			assert.Equal(t, tt.wantStats, op.AttemptIntervalStats)
+			assert.Equal(t, tt.wantWait, op.WaitedFor())
+		})
+	}
+}
+
+func TestRecordAttempt_NonPollingOmitted(t *testing.T) {
+	op := NewOperation("Create", OperationTypeCreate)
+	op.MarkOperationEnd(nil)
+	data, err := json.Marshal(op)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "attempts")
+	assert.NotContains(t, string(data), "firstAttemptAt")
+	assert.NotContains(t, string(data), "lastAttemptAt")
+	assert.NotContains(t, string(data), "attemptIntervalStats")
+}
@@ -0,0 +1,220 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// pushgatewayContentType is the media type of the Prometheus text exposition format.
+const pushgatewayContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultPushgatewayJob is the job label of the metrics pushed to a Pushgateway.
+const DefaultPushgatewayJob = "chainsaw"
+
+// Environment variables holding the Pushgateway credentials, they are kept out of the configuration.
+const (
+	PushgatewayUsernameEnv = "CHAINSAW_PUSHGATEWAY_USERNAME"
+	PushgatewayPasswordEnv = "CHAINSAW_PUSHGATEWAY_PASSWORD"
+	PushgatewayTokenEnv    = "CHAINSAW_PUSHGATEWAY_TOKEN"
+)
+
+// PushgatewaySink pushes the totals and test durations of a report to a Prometheus Pushgateway.
+// Metrics are grouped by job (DefaultPushgatewayJob) and instance (the suite name), a group that stays the same from one
+// run of the suite to the next so that pushing replaces the metrics of the previous run.
+type PushgatewaySink struct {
+	// URL is the base URL of the Pushgateway.
+	URL string
+	// Job overrides the job label, defaults to DefaultPushgatewayJob.
+	Job string
+	// Instance overrides the instance label, defaults to the suite name.
+	Instance string
+	// Username and Password, if set, are sent using basic authentication.
+	Username string
+	Password string
+	// BearerToken, if set, is sent in the Authorization header.
+	BearerToken string
+	// Timeout applies to each request, defaults to 30s.
+	Timeout time.Duration
+	// Retries is the number of additional attempts made when the gateway responds with a 5xx status.
+	Retries int
+	// Backoff is the delay before the first retry, it doubles after each retry. Defaults to 1s.
+	Backoff time.Duration
+	// FailOnError indicates that a failed push should fail the run.
+	FailOnError bool
+	// Client is the HTTP client used to push metrics, defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+// NewPushgatewaySink returns a sink pushing to the gateway at url, credentials are read from PushgatewayUsernameEnv,
+// PushgatewayPasswordEnv and PushgatewayTokenEnv.
+func NewPushgatewaySink(url, job, instance string) *PushgatewaySink {
+	return &PushgatewaySink{
+		URL:         url,
+		Job:         job,
+		Instance:    instance,
+		Username:    os.Getenv(PushgatewayUsernameEnv),
+		Password:    os.Getenv(PushgatewayPasswordEnv),
+		BearerToken: os.Getenv(PushgatewayTokenEnv),
+	}
+}
+
+// Push deletes the metrics previously pushed for the group, so that series of removed tests don't linger, and pushes the report metrics.
+func (s *PushgatewaySink) Push(ctx context.Context, report *TestsReport) error {
+	var metrics bytes.Buffer
+	if err := WriteMetrics(&metrics, report); err != nil {
+		return err
+	}
+	groupURL, err := s.groupURL(report)
+	if err != nil {
+		return &UploadError{Fatal: s.FailOnError, Err: err}
+	}
+	headers := map[string]string{}
+	if s.BearerToken != "" {
+		headers["Authorization"] = "Bearer " + s.BearerToken
+	} else if s.Username != "" || s.Password != "" {
+		headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(s.Username+":"+s.Password))
+	}
+	sink := HTTPReportSink{
+		URL:         groupURL,
+		Headers:     headers,
+		Timeout:     s.Timeout,
+		Retries:     s.Retries,
+		Backoff:     s.Backoff,
+		FailOnError: s.FailOnError,
+		Client:      s.Client,
+	}
+	sink.Method = http.MethodDelete
+	if err := sink.upload(ctx, pushgatewayContentType, nil); err != nil {
+		return err
+	}
+	sink.Method = http.MethodPut
+	return sink.upload(ctx, pushgatewayContentType, metrics.Bytes())
+}
+
+// groupURL returns the URL of the metrics group of the report.
+func (s *PushgatewaySink) groupURL(report *TestsReport) (string, error) {
+	job, instance := s.Job, s.Instance
+	if job == "" {
+		job = DefaultPushgatewayJob
+	}
+	if instance == "" {
+		instance = report.Name
+	}
+	if instance == "" {
+		return "", fmt.Errorf("pushgateway instance label can't be empty")
+	}
+	base, err := url.Parse(s.URL)
+	if err != nil {
+		return "", err
+	}
+	path := strings.TrimSuffix(base.Path, "/") + "/metrics" + groupingPath("job", job) + groupingPath("instance", instance)
+	// values may hold reserved characters, RawPath keeps them escaped
+	base.RawPath = path
+	base.Path, err = url.PathUnescape(path)
+	if err != nil {
+		return "", err
+	}
+	return base.String(), nil
+}
+
+// groupingPath returns the URL path segment of a grouping label, values holding a slash are base64 encoded as the Pushgateway requires.
+func groupingPath(label, value string) string {
+	if strings.Contains(value, "/") {
+		return "/" + label + "@base64/" + base64.RawURLEncoding.EncodeToString([]byte(value))
+	}
+	return "/" + label + "/" + url.PathEscape(value)
+}
+
+// WriteMetrics writes the report totals and per test durations in the Prometheus text exposition format.
+// The run ID is the label of an info metric, it changes with every run and isn't part of the grouping labels.
+// Tests sharing a name, as happens when tests are repeated, are reported once with their total duration and failed if any run failed.
+func WriteMetrics(w io.Writer, report *TestsReport) error {
+	report.lock.Lock()
+	defer report.lock.Unlock()
+	var skipped int
+	type testMetric struct {
+		name     string
+		duration float64
+		failed   int
+	}
+	byName := map[string]*testMetric{}
+	tests := make([]*testMetric, 0, len(report.Reports))
+	for _, test := range report.Reports {
+		test.lock.Lock()
+		metric := byName[test.Name]
+		if metric == nil {
+			metric = &testMetric{name: test.Name}
+			byName[test.Name] = metric
+			tests = append(tests, metric)
+		}
+		metric.duration += seconds(test.Time)
+		if test.Failure != nil {
+			metric.failed = 1
+		}
+		if test.Skip {
+			skipped++
+		}
+		test.lock.Unlock()
+	}
+	sort.Slice(tests, func(i, j int) bool { return tests[i].name < tests[j].name })
+	var out strings.Builder
+	gauge := func(name, help string) {
+		fmt.Fprintf(&out, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+	}
+	gauge("chainsaw_tests", "Number of tests in the suite.")
+	fmt.Fprintf(&out, "chainsaw_tests %d\n", len(report.Reports))
+	gauge("chainsaw_tests_failed", "Number of failed tests in the suite.")
+	fmt.Fprintf(&out, "chainsaw_tests_failed %d\n", report.Failures)
+	gauge("chainsaw_tests_skipped", "Number of skipped tests in the suite.")
+	fmt.Fprintf(&out, "chainsaw_tests_skipped %d\n", skipped)
+	gauge("chainsaw_warnings", "Number of warnings raised by the tests in the suite.")
+	fmt.Fprintf(&out, "chainsaw_warnings %d\n", report.Warnings)
+	gauge("chainsaw_suite_duration_seconds", "Duration of the test suite.")
+	fmt.Fprintf(&out, "chainsaw_suite_duration_seconds %s\n", formatFloat(seconds(report.Time)))
+	gauge("chainsaw_suite_start_time_seconds", "Unix time the test suite started.")
+	fmt.Fprintf(&out, "chainsaw_suite_start_time_seconds %s\n", formatFloat(float64(report.TimeStamp.UnixMilli())/1000))
+	if report.RunID != "" {
+		gauge("chainsaw_suite_info", "Information about the run of the test suite.")
+		fmt.Fprintf(&out, "chainsaw_suite_info{run_id=\"%s\"} 1\n", escapeLabelValue(report.RunID))
+	}
+	if len(tests) != 0 {
+		gauge("chainsaw_test_duration_seconds", "Duration of the test.")
+		for _, test := range tests {
+			fmt.Fprintf(&out, "chainsaw_test_duration_seconds{test=\"%s\"} %s\n", escapeLabelValue(test.name), formatFloat(test.duration))
+		}
+		gauge("chainsaw_test_failed", "Whether the test failed.")
+		for _, test := range tests {
+			fmt.Fprintf(&out, "chainsaw_test_failed{test=\"%s\"} %d\n", escapeLabelValue(test.name), test.failed)
+		}
+	}
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// seconds returns a report duration in seconds, zero if it can't be parsed.
+func seconds(duration string) float64 {
+	d, err := parseDuration(duration)
+	if err != nil {
+		return 0
+	}
+	return d.Seconds()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// escapeLabelValue escapes backslashes, double quotes and line feeds as required by the exposition format.
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
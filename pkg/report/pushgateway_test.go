@@ -0,0 +1,183 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func pushgatewayReport() *TestsReport {
+	report := &TestsReport{
+		Name:      "nightly",
+		RunID:     "0123456789ab",
+		TimeStamp: time.Unix(1704103200, 500_000_000),
+		Time:      "90.500",
+		Reports: []*TestReport{
+			{Name: "b", Time: "30.000", Test: 1},
+			{Name: `a "quoted"`, Time: "10.250", Test: 1, Failure: &Failure{Message: "boom"}},
+			{Name: "b", Time: "20.000", Test: 1, Failure: &Failure{Message: "boom"}},
+			{Name: "c", Time: "0.000", Test: 1, Skip: true},
+		},
+	}
+	report.aggregate()
+	return report
+}
+
+const pushgatewayMetrics = `# HELP chainsaw_tests Number of tests in the suite.
+# TYPE chainsaw_tests gauge
+chainsaw_tests 4
+# HELP chainsaw_tests_failed Number of failed tests in the suite.
+# TYPE chainsaw_tests_failed gauge
+chainsaw_tests_failed 2
+# HELP chainsaw_tests_skipped Number of skipped tests in the suite.
+# TYPE chainsaw_tests_skipped gauge
+chainsaw_tests_skipped 1
+# HELP chainsaw_warnings Number of warnings raised by the tests in the suite.
+# TYPE chainsaw_warnings gauge
+chainsaw_warnings 0
+# HELP chainsaw_suite_duration_seconds Duration of the test suite.
+# TYPE chainsaw_suite_duration_seconds gauge
+chainsaw_suite_duration_seconds 90.5
+# HELP chainsaw_suite_start_time_seconds Unix time the test suite started.
+# TYPE chainsaw_suite_start_time_seconds gauge
+chainsaw_suite_start_time_seconds 1704103200.5
+# HELP chainsaw_suite_info Information about the run of the test suite.
+# TYPE chainsaw_suite_info gauge
+chainsaw_suite_info{run_id="0123456789ab"} 1
+# HELP chainsaw_test_duration_seconds Duration of the test.
+# TYPE chainsaw_test_duration_seconds gauge
+chainsaw_test_duration_seconds{test="a \"quoted\""} 10.25
+chainsaw_test_duration_seconds{test="b"} 50
+chainsaw_test_duration_seconds{test="c"} 0
+# HELP chainsaw_test_failed Whether the test failed.
+# TYPE chainsaw_test_failed gauge
+chainsaw_test_failed{test="a \"quoted\""} 1
+chainsaw_test_failed{test="b"} 1
+chainsaw_test_failed{test="c"} 0
+`
+
+func TestWriteMetrics(t *testing.T) {
+	var out strings.Builder
+	assert.NoError(t, WriteMetrics(&out, pushgatewayReport()))
+	assert.Equal(t, pushgatewayMetrics, out.String())
+}
+
+type pushgatewayRequest struct {
+	method        string
+	path          string
+	authorization string
+	contentType   string
+	body          string
+}
+
+func TestNewPushgatewaySink(t *testing.T) {
+	t.Setenv(PushgatewayUsernameEnv, "user")
+	t.Setenv(PushgatewayPasswordEnv, "pass")
+	t.Setenv(PushgatewayTokenEnv, "token")
+	sink := NewPushgatewaySink("http://gateway", "e2e", "cluster-a")
+	assert.Equal(t, &PushgatewaySink{
+		URL:         "http://gateway",
+		Job:         "e2e",
+		Instance:    "cluster-a",
+		Username:    "user",
+		Password:    "pass",
+		BearerToken: "token",
+	}, sink)
+}
+
+func TestPushgatewaySink_Push(t *testing.T) {
+	tests := []struct {
+		name          string
+		sink          PushgatewaySink
+		report        func() *TestsReport
+		path          string
+		authorization string
+	}{{
+		name:   "defaults",
+		report: pushgatewayReport,
+		path:   "/metrics/job/chainsaw/instance/nightly",
+	}, {
+		name:          "bearer token",
+		sink:          PushgatewaySink{BearerToken: "token"},
+		report:        pushgatewayReport,
+		path:          "/metrics/job/chainsaw/instance/nightly",
+		authorization: "Bearer token",
+	}, {
+		name:          "basic auth",
+		sink:          PushgatewaySink{Username: "user", Password: "pass"},
+		report:        pushgatewayReport,
+		path:          "/metrics/job/chainsaw/instance/nightly",
+		authorization: "Basic dXNlcjpwYXNz",
+	}, {
+		name:   "labels with reserved characters",
+		sink:   PushgatewaySink{Job: "e2e/nightly", Instance: "run 1"},
+		report: pushgatewayReport,
+		path:   "/metrics/job@base64/ZTJlL25pZ2h0bHk/instance/run%201",
+	}, {
+		name:   "job and instance",
+		sink:   PushgatewaySink{Job: "e2e", Instance: "cluster-a"},
+		report: pushgatewayReport,
+		path:   "/metrics/job/e2e/instance/cluster-a",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var lock sync.Mutex
+			var requests []pushgatewayRequest
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				lock.Lock()
+				defer lock.Unlock()
+				requests = append(requests, pushgatewayRequest{
+					method:        r.Method,
+					path:          r.URL.EscapedPath(),
+					authorization: r.Header.Get("Authorization"),
+					contentType:   r.Header.Get("Content-Type"),
+					body:          string(body),
+				})
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer server.Close()
+			tt.sink.URL = server.URL
+			assert.NoError(t, tt.sink.Push(context.Background(), tt.report()))
+			assert.Len(t, requests, 2)
+			assert.Equal(t, http.MethodDelete, requests[0].method)
+			assert.Equal(t, http.MethodPut, requests[1].method)
+			for _, request := range requests {
+				assert.Equal(t, tt.path, request.path)
+				assert.Equal(t, tt.authorization, request.authorization)
+			}
+			assert.Equal(t, pushgatewayContentType, requests[1].contentType)
+			assert.Empty(t, requests[0].body)
+			if tt.name == "defaults" {
+				assert.Equal(t, pushgatewayMetrics, requests[1].body)
+			}
+		})
+	}
+}
+
+func TestPushgatewaySink_Errors(t *testing.T) {
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+	sink := PushgatewaySink{URL: server.URL, FailOnError: true}
+	err := sink.Push(context.Background(), pushgatewayReport())
+	var uploadErr *UploadError
+	assert.True(t, errors.As(err, &uploadErr))
+	assert.Equal(t, http.StatusUnauthorized, uploadErr.StatusCode)
+	assert.True(t, uploadErr.Fatal)
+	// the metrics aren't pushed if the group couldn't be deleted
+	assert.Equal(t, []string{http.MethodDelete}, methods)
+	noInstance := &TestsReport{}
+	assert.Error(t, sink.Push(context.Background(), noInstance))
+}
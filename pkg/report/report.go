@@ -3,14 +3,15 @@ package report
 import (
 	"encoding/json"
 	"encoding/xml"
-	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
-	"strings"
+	"io"
+	"slices"
+	"strconv"
+	"sync"
 	"time"
 
 	v1alpha1 "github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
+	"github.com/kyverno/chainsaw/pkg/utils/clock"
 )
 
 type OperationType string
@@ -26,10 +27,28 @@ const (
 	OperationTypeCommand OperationType = "command"
 )
 
+// OperationPhase is the phase of a step an operation runs in, operations are numbered within their phase.
+type OperationPhase string
+
+const (
+	OperationPhaseTry     OperationPhase = "try"
+	OperationPhaseCatch   OperationPhase = "catch"
+	OperationPhaseFinally OperationPhase = "finally"
+)
+
+// SkipReasonFailFast is the skip reason of the tests not started because a test failed in fail fast mode.
+const SkipReasonFailFast = "fail-fast"
+
 type ReportSerializer interface {
 	Serialize(report *TestsReport) ([]byte, error)
 }
 
+// StreamingReportSerializer is implemented by serializers able to write a report without building it in memory first.
+type StreamingReportSerializer interface {
+	ReportSerializer
+	SerializeTo(w io.Writer, report *TestsReport) error
+}
+
 // Failure represents details of a test failure.
 type Failure struct {
 	// Message provides a summary of the failure.
@@ -40,6 +59,14 @@ type Failure struct {
 type TestsReport struct {
 	// Name of the test suite.
 	Name string `json:"name" xml:"name,attr"`
+	// RunID uniquely identifies the run that produced the report.
+	RunID string `json:"runId,omitempty" xml:"runId,attr,omitempty"`
+	// Git identifies the revision of the repository holding the tests, if any.
+	Git *GitInfo `json:"git,omitempty" xml:"git,omitempty"`
+	// Version is the version of the report format, see FormatVersion. Reports written before versioning have none.
+	Version int `json:"version,omitempty" xml:"version,attr,omitempty"`
+	// Parallel is the maximum number of tests the run was configured to run at once, zero if it had no limit.
+	Parallel int `json:"parallel,omitempty" xml:"parallel,attr,omitempty"`
 	// TimeStamp marks when the test suite began execution.
 	TimeStamp time.Time `json:"timestamp" xml:"timestamp,attr"`
 	// Time indicates the total duration of the test suite.
@@ -50,12 +77,32 @@ type TestsReport struct {
 	Reports []*TestReport `json:"testsuite" xml:"testsuite"`
 	// Failures count the number of failed tests in the suite.
 	Failures int `json:"failures" xml:"failures,attr"`
+	// QuarantinedFailures count the number of failed tests left out of Failures because they are quarantined.
+	QuarantinedFailures int `json:"quarantinedFailures,omitempty" xml:"quarantinedFailures,attr,omitempty"`
+	// Warnings count the number of warnings raised by the tests in the suite.
+	Warnings int `json:"warnings,omitempty" xml:"warnings,attr,omitempty"`
+	// Groups, if the report is grouped, gathers the tests in sub suites, see GroupBy.
+	Groups []*TestsGroup `json:"-" xml:"-"`
+	// groupBy, if set, computes Groups when counts are recomputed.
+	groupBy GroupKeyFunc
+	// journal, if set, persists tests as they complete.
+	journal *Journal
+	// ids numbers the tests and operations of the run, it is created when the first test is added.
+	ids *correlationIDs
+	// closed is set once Close has been called.
+	closed bool
+	// clock stamps the start and the end of the run.
+	clock clock.Clock
+	// lock protects the report against concurrent mutations.
+	lock sync.Mutex
 }
 
 // TestReport represents a report for a single test.
 type TestReport struct {
 	// Name of the test.
 	Name string `json:"name" xml:"name,attr"`
+	// CorrelationID identifies the test in the log lines of the run, it is unique within the run.
+	CorrelationID string `json:"correlationId,omitempty" xml:"correlationId,attr,omitempty"`
 	// TimeStamp marks when the test began execution.
 	TimeStamp time.Time `json:"timestamp" xml:"timestamp,attr"`
 	// Time indicates the total duration of the test.
@@ -68,36 +115,101 @@ type TestReport struct {
 	Steps []*TestSpecStepReport `json:"testcase,omitempty" xml:"testcase,omitempty"`
 	// Concurrent indicates if the test runs concurrently with other tests.
 	Concurrent bool `json:"concurrent,omitempty" xml:"concurrent,attr,omitempty"`
+	// Iteration is the iteration of the test, numbered from 1, if the test runs more than once, see IterationName.
+	Iteration int `json:"iteration,omitempty" xml:"iteration,attr,omitempty"`
+	// Worker is the worker slot the test ran in, numbered from 1, log lines print it with the logWorker option.
+	Worker int `json:"worker,omitempty" xml:"worker,attr,omitempty"`
 	// Namespace in which the test runs.
 	Namespace string `json:"namespace,omitempty" xml:"namespace,attr,omitempty"`
+	// Path is the folder the test was loaded from.
+	Path string `json:"path,omitempty" xml:"path,attr,omitempty"`
+	// Labels are the labels of the test.
+	Labels map[string]string `json:"labels,omitempty" xml:"-"`
+	// Environment holds the allowlisted environment variables captured when the test started, sorted by name.
+	Environment Environment `json:"environment,omitempty" xml:"properties,omitempty"`
 	// Skip indicates if the test is skipped.
 	Skip bool `json:"skip,omitempty" xml:"skip,attr,omitempty"`
+	// SkipReason tells why the test is skipped, like SkipReasonFailFast, if known.
+	SkipReason string `json:"skipReason,omitempty" xml:"skipReason,attr,omitempty"`
+	// Quarantined indicates the test is known to be flaky, its failure doesn't fail the suite.
+	Quarantined bool `json:"quarantined,omitempty" xml:"quarantined,attr,omitempty"`
+	// Interrupted indicates the test didn't complete because the run was interrupted.
+	Interrupted bool `json:"interrupted,omitempty" xml:"interrupted,attr,omitempty"`
 	// SkipDelete indicates if resources are not deleted after test execution.
 	SkipDelete bool `json:"skipDelete,omitempty" xml:"skipDelete,attr,omitempty"`
+	// Warnings lists the warnings raised while running the test.
+	Warnings []Warning `json:"warnings,omitempty" xml:"warning,omitempty"`
+	// Logs holds the console output captured while running the test.
+	Logs Logs `json:"logs,omitempty" xml:"system-out,omitempty"`
+	// Artifacts lists the paths of the files written for the test, like its log file.
+	Artifacts []string `json:"artifacts,omitempty" xml:"artifact,omitempty"`
+	// journal, if set, persists the test when it completes.
+	journal *Journal
+	// ids, if set, numbers the operations of the test, it is shared by the tests of a run.
+	ids *correlationIDs
+	// clock stamps the start and the end of the test.
+	clock clock.Clock
+	// lock protects the report against concurrent mutations.
+	lock sync.Mutex
 }
 
 // TestSpecStepReport represents a report of a single step in a test.
 type TestSpecStepReport struct {
 	// Name of the test step.
 	Name string `json:"name,omitempty" xml:"name,attr,omitempty"`
+	// Index is the position of the step in the test, numbered from 1, it is zero if unknown.
+	Index int `json:"index,omitempty" xml:"index,attr,omitempty"`
+	// Skip indicates if the step is skipped.
+	Skip bool `json:"skip,omitempty" xml:"skip,attr,omitempty"`
+	// SkipReason tells why the step is skipped, like the skipIf expression it was skipped by.
+	SkipReason string `json:"skipReason,omitempty" xml:"skipReason,attr,omitempty"`
 	// Results are the outcomes of operations performed in this step.
 	Results []*OperationReport `json:"results,omitempty" xml:"results,omitempty"`
+	// ids, if set, numbers the operations of the step.
+	ids *correlationIDs
+	// lock protects the report against concurrent mutations.
+	lock sync.Mutex
 }
 
 // OperationReport details the outcome of a single operation within a test step.
 type OperationReport struct {
 	// Name of the operation.
 	Name string `json:"name" xml:"name,attr"`
+	// CorrelationID identifies the operation in the log lines of the run, it is unique within the run.
+	CorrelationID string `json:"correlationId,omitempty" xml:"correlationId,attr,omitempty"`
+	// Phase is the phase of the step the operation runs in, if known.
+	Phase OperationPhase `json:"phase,omitempty" xml:"phase,attr,omitempty"`
+	// Index is the position of the operation in its phase, numbered from 1, it is zero if unknown.
+	Index int `json:"index,omitempty" xml:"index,attr,omitempty"`
 	// TimeStamp marks when the operation began execution.
 	TimeStamp time.Time `json:"timestamp" xml:"timestamp,attr"`
 	// Time indicates the total duration of the operation.
 	Time string `json:"time" xml:"time,attr"`
+	// Timeout is the timeout the operation ran with, resolved from the operation, step, test and configuration timeouts.
+	Timeout string `json:"timeout,omitempty" xml:"timeout,attr,omitempty"`
 	// Result of the operation.
 	Result string `json:"result" xml:"result,attr"`
 	// Message provides additional information about the operation's outcome.
 	Message string `json:"message,omitempty" xml:"message,omitempty"`
+	// Diff is the uncolored mismatches and diff of the expected and actual resources of a failed assertion, if any.
+	Diff string `json:"diff,omitempty" xml:"diff,omitempty"`
 	// Type indicates the type of operation.
 	OperationType OperationType `json:"operationType,omitempty" xml:"operationType,attr"`
+	// Attempts counts the number of evaluations performed by a polling operation, or the attempts of a retried operation.
+	Attempts int `json:"attempts,omitempty" xml:"attempts,attr,omitempty"`
+	// FirstAttemptAt marks when a polling operation was first evaluated.
+	FirstAttemptAt *time.Time `json:"firstAttemptAt,omitempty" xml:"firstAttemptAt,attr,omitempty"`
+	// LastAttemptAt marks when a polling operation was last evaluated.
+	LastAttemptAt *time.Time `json:"lastAttemptAt,omitempty" xml:"lastAttemptAt,attr,omitempty"`
+	// AttemptIntervalStats summarizes the gaps between consecutive evaluations of a polling operation.
+	AttemptIntervalStats *AttemptIntervalStats `json:"attemptIntervalStats,omitempty" xml:"attemptIntervalStats,omitempty"`
+	// minInterval and maxInterval track the raw interval bounds used to compute AttemptIntervalStats.
+	minInterval time.Duration
+	maxInterval time.Duration
+	// clock stamps the start and the end of the operation.
+	clock clock.Clock
+	// lock protects the report against concurrent mutations.
+	lock sync.Mutex
 }
 
 type JSONSerializer struct{}
@@ -106,61 +218,78 @@ func (s JSONSerializer) Serialize(report *TestsReport) ([]byte, error) {
 	return json.MarshalIndent(report, "", "  ")
 }
 
+// XMLSerializer serializes reports as XML documents, text is sanitized so that strict XML 1.0 parsers accept it.
 type XMLSerializer struct{}
 
 func (s XMLSerializer) Serialize(report *TestsReport) ([]byte, error) {
-	return xml.MarshalIndent(report, "", "  ")
+	data, err := xml.MarshalIndent(report.xmlSafe(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
 }
 
-func SaveReport(report *TestsReport, serializer ReportSerializer, filePath string) error {
-	data, err := serializer.Serialize(report)
-	if err != nil {
+func (s XMLSerializer) SerializeTo(w io.Writer, report *TestsReport) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report.xmlSafe()); err != nil {
 		return err
 	}
-	return os.WriteFile(filePath, data, 0o600)
+	return encoder.Close()
 }
 
 func GetSerializer(format v1alpha1.ReportFormatType) (ReportSerializer, error) {
-	switch format {
+	parsed, err := ParseFormat(string(format))
+	if err != nil {
+		return nil, err
+	}
+	switch parsed {
 	case v1alpha1.JSONFormat:
 		return JSONSerializer{}, nil
-	case v1alpha1.XMLFormat:
-		return XMLSerializer{}, nil
 	default:
-		return nil, errors.New("unsupported report format")
-	}
-}
-
-func (report *TestsReport) SaveReportBasedOnType(reportFormat v1alpha1.ReportFormatType, reportPath, reportName string) error {
-	serializer, err := GetSerializer(reportFormat)
-	if err != nil {
-		return err
-	}
-	if filepath.Ext(reportName) == "" {
-		reportName += "." + strings.ToLower(string(reportFormat))
-	}
-	filePath := reportName
-	if reportPath != "" {
-		filePath = filepath.Join(reportPath, reportName)
+		return XMLSerializer{}, nil
 	}
-	return SaveReport(report, serializer, filePath)
 }
 
 // NewTests initializes a new TestsReport with the given name.
 func NewTests(name string) *TestsReport {
+	return NewTestsWithClock(name, clock.Real)
+}
+
+// NewTestsWithClock initializes a new TestsReport with the given name, its start and end are stamped with c.
+func NewTestsWithClock(name string, c clock.Clock) *TestsReport {
+	c = clock.OrReal(c)
 	return &TestsReport{
 		Name:      name,
-		TimeStamp: time.Now(),
+		RunID:     newRunID(),
+		Version:   FormatVersion,
+		TimeStamp: c.Now(),
 		Reports:   []*TestReport{},
+		clock:     c,
 	}
 }
 
+// IterationName returns the name of the report of the given iteration of a test.
+func IterationName(name string, iteration int) string {
+	return fmt.Sprintf("%s#%d", name, iteration)
+}
+
 // NewTest creates a new TestReport with the given name.
 func NewTest(name string) *TestReport {
+	return NewTestWithClock(name, clock.Real)
+}
+
+// NewTestWithClock creates a new TestReport with the given name, its start and end are stamped with c.
+func NewTestWithClock(name string, c clock.Clock) *TestReport {
+	c = clock.OrReal(c)
 	return &TestReport{
 		Name:      name,
-		TimeStamp: time.Now(),
+		TimeStamp: c.Now(),
 		Steps:     []*TestSpecStepReport{},
+		clock:     c,
 	}
 }
 
@@ -174,30 +303,112 @@ func NewTestSpecStep(name string) *TestSpecStepReport {
 
 // NewOperation creates a new OperationReport with the given details.
 func NewOperation(name string, operationType OperationType) *OperationReport {
+	return NewOperationWithClock(name, operationType, clock.Real)
+}
+
+// NewOperationWithClock creates a new OperationReport with the given details, its start and end are stamped with c.
+func NewOperationWithClock(name string, operationType OperationType, c clock.Clock) *OperationReport {
+	c = clock.OrReal(c)
 	return &OperationReport{
 		Name:          name,
-		TimeStamp:     time.Now(),
+		TimeStamp:     c.Now(),
 		OperationType: operationType,
+		clock:         c,
 	}
 }
 
+// SetJournal configures a journal used to persist tests as they complete.
+func (tr *TestsReport) SetJournal(journal *Journal) {
+	tr.lock.Lock()
+	defer tr.lock.Unlock()
+	tr.journal = journal
+}
+
 // AddTest adds a test report to the TestsReport.
 func (tr *TestsReport) AddTest(test *TestReport) {
+	tr.lock.Lock()
+	defer tr.lock.Unlock()
+	if tr.ids == nil {
+		tr.ids = newCorrelationIDs(tr.RunID)
+	}
+	test.lock.Lock()
+	if tr.journal != nil {
+		test.journal = tr.journal
+	}
+	// tests restored from a journal keep their identifier
+	if test.CorrelationID == "" {
+		test.CorrelationID = tr.ids.next()
+	}
+	test.ids = tr.ids
+	test.lock.Unlock()
 	tr.Reports = append(tr.Reports, test)
+	// totals of a closed report would be stale otherwise
+	if tr.closed {
+		tr.aggregate()
+	}
 }
 
 // AddTestStep adds a test step report to the TestReport.
 func (t *TestReport) AddTestStep(step *TestSpecStepReport) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	step.lock.Lock()
+	step.ids = t.ids
+	step.lock.Unlock()
 	t.Steps = append(t.Steps, step)
 }
 
 // AddOperation adds an operation report to the TestSpecStepReport.
 func (ts *TestSpecStepReport) AddOperation(op *OperationReport) {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+	if ts.ids != nil {
+		op.lock.Lock()
+		if op.CorrelationID == "" {
+			op.CorrelationID = ts.ids.next()
+		}
+		op.lock.Unlock()
+	}
 	ts.Results = append(ts.Results, op)
 }
 
+// MarkSkipped records that the step is skipped, reason tells why.
+func (ts *TestSpecStepReport) MarkSkipped(reason string) {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+	ts.Skip = true
+	ts.SkipReason = reason
+}
+
+// SetWorker records the worker slot the test runs in.
+func (t *TestReport) SetWorker(worker int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.Worker = worker
+}
+
+// MarkSkipped records that the test is skipped, reason tells why if not empty.
+func (t *TestReport) MarkSkipped(reason string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.Skip = true
+	t.SkipReason = reason
+}
+
+// AddArtifact records the path of a file written for the test, paths already recorded are ignored.
+func (t *TestReport) AddArtifact(path string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if slices.Contains(t.Artifacts, path) {
+		return
+	}
+	t.Artifacts = append(t.Artifacts, path)
+}
+
 // NewFailure creates a new Failure instance with the given message and type and assigns it to the TestReport.
 func (t *TestReport) NewFailure(message string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
 	if t.Failure == nil {
 		t.Failure = &Failure{
 			Message: message,
@@ -207,16 +418,26 @@ func (t *TestReport) NewFailure(message string) {
 
 // MarkTestEnd marks the end time of a TestReport and calculates its duration.
 func (t *TestReport) MarkTestEnd() {
-	t.Time = calculateDuration(t.TimeStamp, time.Now())
-
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.Time = calculateDuration(t.TimeStamp, clock.OrReal(t.clock).Now())
+	t.Test = 0
 	for _, step := range t.Steps {
+		step.lock.Lock()
 		t.Test += len(step.Results)
+		step.lock.Unlock()
+	}
+	if t.journal != nil {
+		// errors are retained by the journal and surfaced when it is closed
+		_ = t.journal.Record(t)
 	}
 }
 
 // MarkOperationEnd marks the end time of an OperationReport and calculates its duration.
 func (op *OperationReport) MarkOperationEnd(err error) {
-	op.Time = calculateDuration(op.TimeStamp, time.Now())
+	op.lock.Lock()
+	defer op.lock.Unlock()
+	op.Time = calculateDuration(op.TimeStamp, clock.OrReal(op.clock).Now())
 	if err == nil {
 		op.Result = "Success"
 		op.Message = "Operation completed successfully"
@@ -226,20 +447,62 @@ func (op *OperationReport) MarkOperationEnd(err error) {
 	}
 }
 
+// MarkOperationSkipped marks the end of an OperationReport not executed, reason tells why.
+func (op *OperationReport) MarkOperationSkipped(reason string) {
+	op.lock.Lock()
+	defer op.lock.Unlock()
+	op.Time = calculateDuration(op.TimeStamp, clock.OrReal(op.clock).Now())
+	op.Result = "Skipped"
+	op.Message = reason
+}
+
+// SetDiff records the mismatches and diff of the expected and actual resources of a failed assertion.
+func (op *OperationReport) SetDiff(diff string) {
+	op.lock.Lock()
+	defer op.lock.Unlock()
+	op.Diff = diff
+}
+
+// SetTimeout records the timeout the operation runs with.
+func (op *OperationReport) SetTimeout(timeout time.Duration) {
+	op.lock.Lock()
+	defer op.lock.Unlock()
+	op.Timeout = formatDuration(timeout)
+}
+
 // calculateDuration calculates the duration between two time points.
 func calculateDuration(start, end time.Time) string {
-	return fmt.Sprintf("%.3f", end.Sub(start).Seconds())
+	return formatDuration(end.Sub(start))
 }
 
-// Close finalizes the TestsReport, marking its end time and calculating the overall duration.
-func (tr *TestsReport) Close() {
-	tr.Time = calculateDuration(tr.TimeStamp, time.Now())
-	totalTests := 0
-	for _, testReport := range tr.Reports {
-		if testReport.Failure != nil {
-			tr.Failures++
-		}
-		totalTests += testReport.Test
+// formatDuration formats a duration in seconds with a millisecond precision.
+func formatDuration(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// parseDuration parses a duration formatted by formatDuration.
+func parseDuration(s string) (time.Duration, error) {
+	seconds, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
 	}
-	tr.Test = totalTests
+	return time.Duration(seconds * float64(time.Second)).Round(time.Millisecond), nil
+}
+
+// Close finalizes the TestsReport, marking its end time, calculating the overall duration and recomputing the counts.
+// It is safe to call Close multiple times, counts are recomputed from scratch every time.
+func (tr *TestsReport) Close() {
+	tr.lock.Lock()
+	defer tr.lock.Unlock()
+	tr.Time = calculateDuration(tr.TimeStamp, clock.OrReal(tr.clock).Now())
+	tr.closed = true
+	tr.aggregate()
+}
+
+// Recompute recomputes the counts of the TestsReport from its tests, the end time is left untouched.
+// It should be called after mutating tests of a closed report.
+func (tr *TestsReport) Recompute() {
+	tr.lock.Lock()
+	defer tr.lock.Unlock()
+	tr.aggregate()
 }
@@ -1,16 +1,21 @@
 package report
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	petName "github.com/dustinkirkland/golang-petname"
 	v1alpha1 "github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
 	"github.com/stretchr/testify/assert"
+	tclock "k8s.io/utils/clock/testing"
 )
 
 type FakeSerializer struct{}
@@ -39,16 +44,16 @@ func TestSaveReport(t *testing.T) {
 			expectError: false,
 		},
 		{
-			name:        "FailedSaveInvalidPathJSON",
-			reportName:  "/invalid_path/test_report.json",
+			name:        "SuccessfulSaveNestedPathJSON",
+			reportName:  "/missing_path/nested/test_report.json",
 			serializer:  JSONSerializer{},
-			expectError: true,
+			expectError: false,
 		},
 		{
-			name:        "FailedSaveInvalidPathXML",
-			reportName:  "/invalid_path/test_report.xml",
+			name:        "SuccessfulSaveNestedPathXML",
+			reportName:  "/missing_path/nested/test_report.xml",
 			serializer:  XMLSerializer{},
-			expectError: true,
+			expectError: false,
 		},
 		{
 			name:        "FakeSerializerError",
@@ -79,6 +84,30 @@ func TestSaveReport(t *testing.T) {
 	}
 }
 
+func TestSaveReport_InvalidPath(t *testing.T) {
+	report := NewTests("SampleTestSuite")
+	t.Run("PathIsDirectory", func(t *testing.T) {
+		dir := t.TempDir()
+		err := SaveReport(report, JSONSerializer{}, dir)
+		assert.ErrorContains(t, err, dir)
+	})
+	t.Run("ParentIsFile", func(t *testing.T) {
+		parent := filepath.Join(t.TempDir(), "file")
+		assert.NoError(t, os.WriteFile(parent, nil, 0o600))
+		err := SaveReport(report, JSONSerializer{}, filepath.Join(parent, "nested", "report.json"))
+		assert.ErrorContains(t, err, filepath.Join(parent, "nested"))
+	})
+	t.Run("PermissionDenied", func(t *testing.T) {
+		if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+			t.Skip("permissions are not enforced")
+		}
+		parent := filepath.Join(t.TempDir(), "readonly")
+		assert.NoError(t, os.Mkdir(parent, 0o500))
+		err := SaveReport(report, JSONSerializer{}, filepath.Join(parent, "nested", "report.json"))
+		assert.ErrorContains(t, err, filepath.Join(parent, "nested"))
+	})
+}
+
 func TestSaveReportBasedOnType(t *testing.T) {
 	testCases := []struct {
 		name        string
@@ -113,7 +142,7 @@ func TestSaveReportBasedOnType(t *testing.T) {
 
 			mockReportName := petName.Name()
 			mockReportPath := t.TempDir()
-			err := report.SaveReportBasedOnType(tc.format, mockReportPath, mockReportName)
+			err := report.SaveReportBasedOnType(tc.format, mockReportPath, mockReportName, SaveOptions{})
 
 			if tc.expectError {
 				assert.Error(t, err, "Expected an error")
@@ -164,6 +193,33 @@ func TestNewFailure(t *testing.T) {
 	assert.Equal(t, "Sample failure message", testReport.Failure.Message, "Failure message does not match")
 }
 
+func TestMarkSkipped(t *testing.T) {
+	testReport := NewTest("Test1")
+	testReport.MarkSkipped(SkipReasonFailFast)
+	assert.True(t, testReport.Skip)
+	assert.Equal(t, SkipReasonFailFast, testReport.SkipReason)
+	// copies keep the reason
+	assert.Equal(t, SkipReasonFailFast, testReport.deepCopy().SkipReason)
+	// steps are skipped on their own
+	stepReport := NewTestSpecStep("Step1")
+	stepReport.MarkSkipped("skipIf: $values.skip")
+	copied := stepReport.deepCopy()
+	assert.True(t, copied.Skip)
+	assert.Equal(t, "skipIf: $values.skip", copied.SkipReason)
+}
+
+func TestAddArtifact(t *testing.T) {
+	testReport := NewTest("Test1")
+	testReport.AddArtifact("logs/Test1.log")
+	testReport.AddArtifact("logs/Test1.log")
+	testReport.AddArtifact("logs/Test1.txt")
+	assert.Equal(t, []string{"logs/Test1.log", "logs/Test1.txt"}, testReport.Artifacts)
+	// copies don't share the artifacts
+	copied := testReport.deepCopy()
+	copied.Artifacts[0] = "changed"
+	assert.Equal(t, "logs/Test1.log", testReport.Artifacts[0])
+}
+
 func TestMarkTestEnd(t *testing.T) {
 	startTime := time.Now().Add(-10 * time.Second)
 	testReport := &TestReport{
@@ -205,6 +261,22 @@ func TestMarkOperationEnd(t *testing.T) {
 	}
 }
 
+func TestMarkOperationSkipped(t *testing.T) {
+	operation := &OperationReport{TimeStamp: time.Now().Add(-5 * time.Second)}
+	operation.MarkOperationSkipped("skipped in dry run mode")
+	assert.Regexp(t, `\d+\.\d{3}`, operation.Time, "Duration format is incorrect")
+	assert.Equal(t, "Skipped", operation.Result)
+	assert.Equal(t, "skipped in dry run mode", operation.Message)
+}
+
+func TestSetTimeout(t *testing.T) {
+	operation := NewOperation("Apply ", OperationTypeApply)
+	assert.Empty(t, operation.Timeout)
+	operation.SetTimeout(90 * time.Second)
+	assert.Equal(t, "90.000", operation.Timeout)
+	assert.Equal(t, "90.000", operation.deepCopy().Timeout)
+}
+
 func TestCalculateDuration(t *testing.T) {
 	startTime := time.Now().Add(-30 * time.Second) // mock start time 30 seconds ago
 	durationStr := calculateDuration(startTime, time.Now())
@@ -228,3 +300,248 @@ func TestClose(t *testing.T) {
 	assert.Equal(t, 1, testsReport.Failures, "Failures count should be 1")
 	assert.Equal(t, 2, testsReport.Test, "Total tests count should be 2")
 }
+
+func TestSaveReport_Stdout(t *testing.T) {
+	testCases := []struct {
+		name   string
+		format v1alpha1.ReportFormatType
+		prefix string
+	}{{
+		name:   "JSON",
+		format: v1alpha1.JSONFormat,
+		prefix: "{",
+	}, {
+		name:   "XML",
+		format: v1alpha1.XMLFormat,
+		prefix: "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<TestsReport",
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			original := stdout
+			defer func() { stdout = original }()
+			var buf bytes.Buffer
+			stdout = &buf
+			report := NewTests("SampleTestSuite")
+			report.AddTest(NewTest("Test1"))
+			dir := t.TempDir()
+			err := report.SaveReportBasedOnType(tc.format, dir, StdoutName, SaveOptions{})
+			assert.NoError(t, err)
+			assert.True(t, strings.HasPrefix(buf.String(), tc.prefix), "unexpected output: %s", buf.String())
+			entries, err := os.ReadDir(dir)
+			assert.NoError(t, err)
+			assert.Empty(t, entries, "no file should be written")
+		})
+	}
+}
+
+func TestTestsReport_ConcurrentMutations(t *testing.T) {
+	const count = 100
+	report := NewTests("suite")
+	test := NewTest("shared")
+	step := NewTestSpecStep("shared")
+	test.AddTestStep(step)
+	report.AddTest(test)
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			own := NewTest(fmt.Sprintf("test-%d", i))
+			report.AddTest(own)
+			own.AddTestStep(NewTestSpecStep("step"))
+			op := NewOperation("op", OperationTypeAssert)
+			op.RecordAttempt(time.Now())
+			op.MarkOperationEnd(nil)
+			step.AddOperation(op)
+			test.AddWarning(WarningTypeOther, "warning")
+			own.NewFailure("failed")
+			own.MarkTestEnd()
+		}(i)
+	}
+	wg.Wait()
+	test.MarkTestEnd()
+	report.Close()
+	assert.Len(t, report.Reports, count+1)
+	names := map[string]bool{}
+	for _, test := range report.Reports {
+		names[test.Name] = true
+	}
+	for i := 0; i < count; i++ {
+		assert.True(t, names[fmt.Sprintf("test-%d", i)])
+	}
+	assert.Len(t, step.Results, count)
+	assert.Equal(t, count, report.Failures)
+	assert.Equal(t, count, report.Warnings)
+	assert.Equal(t, count, report.Test)
+}
+
+func TestTestsReport_CloseTwice(t *testing.T) {
+	report := NewTests("suite")
+	test := NewTest("test")
+	test.AddWarning(WarningTypeOther, "warning")
+	step := NewTestSpecStep("step")
+	op := NewOperation("op", OperationTypeApply)
+	op.MarkOperationEnd(nil)
+	step.AddOperation(op)
+	test.AddTestStep(step)
+	test.NewFailure("failed")
+	test.MarkTestEnd()
+	test.MarkTestEnd()
+	report.AddTest(test)
+	report.Close()
+	report.Close()
+	assert.Equal(t, 1, report.Failures)
+	assert.Equal(t, 1, report.Warnings)
+	assert.Equal(t, 1, report.Test)
+	assert.Equal(t, 1, test.Test)
+	assert.NoError(t, report.Validate())
+}
+
+func TestTestsReport_AddTestAfterClose(t *testing.T) {
+	report := NewTests("suite")
+	report.Close()
+	test := NewTest("test")
+	test.NewFailure("failed")
+	report.AddTest(test)
+	assert.Equal(t, 1, report.Failures)
+}
+
+func TestTestsReport_Recompute(t *testing.T) {
+	report := NewTests("suite")
+	report.AddTest(NewTest("test"))
+	report.Close()
+	end := report.Time
+	assert.Equal(t, 0, report.Failures)
+	report.Reports[0].NewFailure("failed")
+	report.Recompute()
+	assert.Equal(t, 1, report.Failures)
+	assert.Equal(t, end, report.Time)
+}
+
+func TestTestsReport_CloseQuarantined(t *testing.T) {
+	report := NewTests("suite")
+	flaky, broken, fixed := NewTest("flaky"), NewTest("broken"), NewTest("fixed")
+	flaky.Quarantined, fixed.Quarantined = true, true
+	flaky.NewFailure("timed out")
+	broken.NewFailure("failed")
+	report.AddTest(flaky)
+	report.AddTest(broken)
+	report.AddTest(fixed)
+	for _, test := range report.Reports {
+		test.MarkTestEnd()
+	}
+	report.Close()
+	assert.Equal(t, 1, report.Failures)
+	assert.Equal(t, 1, report.QuarantinedFailures)
+	assert.NoError(t, report.Validate())
+	data, err := JSONSerializer{}.Serialize(report)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"quarantinedFailures": 1`)
+	assert.Contains(t, string(data), `"quarantined": true`)
+	data, err = XMLSerializer{}.Serialize(report)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `quarantinedFailures="1"`)
+	assert.Contains(t, string(data), `quarantined="true"`)
+}
+
+func TestReport_Clock(t *testing.T) {
+	start := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	fakeClock := tclock.NewFakePassiveClock(start)
+	testsReport := NewTestsWithClock("chainsaw-report", fakeClock)
+	// the run identifier is random, it prefixes correlation identifiers
+	testsReport.RunID = "run"
+	testReport := NewTestWithClock("test", fakeClock)
+	testsReport.AddTest(testReport)
+	step := NewTestSpecStep("step")
+	testReport.AddTestStep(step)
+	fakeClock.SetTime(start.Add(250 * time.Millisecond))
+	operation := NewOperationWithClock("Apply", OperationTypeApply, fakeClock)
+	step.AddOperation(operation)
+	fakeClock.SetTime(start.Add(1750 * time.Millisecond))
+	operation.MarkOperationEnd(nil)
+	fakeClock.SetTime(start.Add(2 * time.Second))
+	testReport.MarkTestEnd()
+	fakeClock.SetTime(start.Add(3*time.Second + 5*time.Millisecond))
+	testsReport.Close()
+	data, err := JSONSerializer{}.Serialize(testsReport)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+  "name": "chainsaw-report",
+  "runId": "run",
+  "version": 2,
+  "timestamp": "2024-03-01T10:30:00Z",
+  "time": "3.005",
+  "tests": 1,
+  "testsuite": [{
+    "name": "test",
+    "correlationId": "run-1",
+    "timestamp": "2024-03-01T10:30:00Z",
+    "time": "2.000",
+    "tests": 1,
+    "testcase": [{
+      "name": "step",
+      "results": [{
+        "name": "Apply",
+        "correlationId": "run-2",
+        "timestamp": "2024-03-01T10:30:00.25Z",
+        "time": "1.500",
+        "result": "Success",
+        "message": "Operation completed successfully",
+        "operationType": "apply"
+      }]
+    }]
+  }],
+  "failures": 0
+}`, string(data))
+	data, err = XMLSerializer{}.Serialize(testsReport)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `<TestsReport name="chainsaw-report" runId="run" version="2" timestamp="2024-03-01T10:30:00Z" time="3.005" tests="1" failures="0">`)
+	assert.Contains(t, string(data), `<testsuite name="test" correlationId="run-1" timestamp="2024-03-01T10:30:00Z" time="2.000" tests="1">`)
+	assert.Contains(t, string(data), `<results name="Apply" correlationId="run-2" timestamp="2024-03-01T10:30:00.25Z" time="1.500" result="Success" operationType="apply">`)
+}
+
+func TestReport_ClockInterrupt(t *testing.T) {
+	start := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	fakeClock := tclock.NewFakePassiveClock(start)
+	testsReport := NewTestsWithClock("chainsaw-report", fakeClock)
+	testReport := NewTestWithClock("test", fakeClock)
+	testsReport.AddTest(testReport)
+	fakeClock.SetTime(start.Add(42 * time.Second))
+	assert.Equal(t, 1, testsReport.Interrupt())
+	assert.Equal(t, "42.000", testReport.Time)
+	// reports without a clock use the wall time
+	assert.WithinDuration(t, time.Now(), NewTestWithClock("other", nil).TimeStamp, time.Minute)
+}
+
+func TestReport_CorrelationID(t *testing.T) {
+	testsReport := NewTests("chainsaw-report")
+	testsReport.RunID = "3f9a1c5e7b2d"
+	first, second := NewTest("first"), NewTest("second")
+	testsReport.AddTest(first)
+	step := NewTestSpecStep("step")
+	first.AddTestStep(step)
+	testsReport.AddTest(second)
+	apply, assert1 := NewOperation("Apply", OperationTypeApply), NewOperation("Assert", OperationTypeAssert)
+	step.AddOperation(apply)
+	step.AddOperation(assert1)
+	// tests and operations share the counter, identifiers are unique within the run
+	assert.Equal(t, "3f9a1c-1", first.CorrelationID)
+	assert.Equal(t, "3f9a1c-2", second.CorrelationID)
+	assert.Equal(t, "3f9a1c-3", apply.CorrelationID)
+	assert.Equal(t, "3f9a1c-4", assert1.CorrelationID)
+	// identifiers of restored tests are kept
+	restored := NewTest("restored")
+	restored.CorrelationID = "3f9a1c-1"
+	testsReport.AddTest(restored)
+	assert.Equal(t, "3f9a1c-1", restored.CorrelationID)
+	// steps of tests outside a run don't number their operations
+	orphan := NewTestSpecStep("step")
+	NewTest("orphan").AddTestStep(orphan)
+	operation := NewOperation("Apply", OperationTypeApply)
+	orphan.AddOperation(operation)
+	assert.Empty(t, operation.CorrelationID)
+	// copies keep the identifiers
+	copied := testsReport.DeepCopy()
+	assert.Equal(t, "3f9a1c-1", copied.Reports[0].CorrelationID)
+	assert.Equal(t, "3f9a1c-3", copied.Reports[0].Steps[0].Results[0].CorrelationID)
+}
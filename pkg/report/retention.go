@@ -0,0 +1,216 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	v1alpha1 "github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
+	"go.uber.org/multierr"
+)
+
+// RetentionPolicy configures which previous reports are kept when a report is saved, see ApplyRetention.
+// Reports are only removed when they are not retained by every configured limit.
+type RetentionPolicy struct {
+	// Keep, if positive, keeps the newest Keep reports, including the one just written.
+	Keep int
+	// MaxAge, if positive, keeps the reports modified less than MaxAge ago.
+	MaxAge time.Duration
+	// DryRun reports the files that would be removed without removing them.
+	DryRun bool
+	// OnRemove, if set, is called with every removed file, or every file that would be removed in dry run mode.
+	OnRemove func(path string)
+	// now returns the current time, it can be overridden in tests.
+	now func() time.Time
+}
+
+// enabled returns true if the policy removes anything.
+func (p RetentionPolicy) enabled() bool {
+	return p.Keep > 0 || p.MaxAge > 0
+}
+
+// ApplyRetention removes the previous reports generated from the same templated name in the directory of the report.
+// Only files matching the name template are considered, names without placeholders, stdout and remote destinations
+// are left alone. Checksum and signature files of removed reports are removed too. It returns the removed files.
+func (tr *TestsReport) ApplyRetention(reportFormat v1alpha1.ReportFormatType, reportPath, reportName string, compress bool, policy RetentionPolicy) ([]string, error) {
+	if !policy.enabled() || !strings.Contains(reportName, "{{") || !IsFile(reportName) {
+		return nil, nil
+	}
+	resolved, err := tr.ResolveName(reportName)
+	if err != nil {
+		return nil, err
+	}
+	written := SaveOptions{Compress: compress}.destination(FilePath(reportFormat, reportPath, resolved))
+	// the extension appended to the rendered name, if any, is part of the pattern
+	suffix := strings.TrimPrefix(filepath.Base(written), filepath.Base(resolved))
+	pattern, err := namePattern(reportName, tr.Name, suffix)
+	if err != nil {
+		return nil, err
+	}
+	return applyRetention(filepath.Dir(written), filepath.Base(written), pattern, policy)
+}
+
+type retainedFile struct {
+	name    string
+	modTime time.Time
+}
+
+func applyRetention(dir, current string, pattern *regexp.Regexp, policy RetentionPolicy) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var candidates []retainedFile
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || !pattern.MatchString(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			// the file may have been removed concurrently, leave it alone
+			continue
+		}
+		candidates = append(candidates, retainedFile{name: entry.Name(), modTime: info.ModTime()})
+	}
+	// newest first, the report just written always comes first
+	sort.Slice(candidates, func(i, j int) bool {
+		if (candidates[i].name == current) != (candidates[j].name == current) {
+			return candidates[i].name == current
+		}
+		if !candidates[i].modTime.Equal(candidates[j].modTime) {
+			return candidates[i].modTime.After(candidates[j].modTime)
+		}
+		return candidates[i].name > candidates[j].name
+	})
+	now := time.Now
+	if policy.now != nil {
+		now = policy.now
+	}
+	var removed []string
+	var errs []error
+	for i, file := range candidates {
+		if file.name == current {
+			continue
+		}
+		expired := policy.MaxAge > 0 && now().Sub(file.modTime) > policy.MaxAge
+		if !expired && (policy.Keep <= 0 || i < policy.Keep) {
+			continue
+		}
+		path := filepath.Join(dir, file.name)
+		for _, target := range []string{path, path + ChecksumExtension, path + SignatureExtension} {
+			if target != path {
+				if _, err := os.Lstat(target); err != nil {
+					continue
+				}
+			}
+			if !policy.DryRun {
+				if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+					errs = append(errs, err)
+					continue
+				}
+			}
+			removed = append(removed, target)
+			if policy.OnRemove != nil {
+				policy.OnRemove(target)
+			}
+		}
+	}
+	return removed, multierr.Combine(errs...)
+}
+
+// namePattern returns a regular expression matching the file names rendered from the report name template.
+// Placeholders are matched strictly by what they can render to, templates using anything else than the
+// documented fields are rejected rather than matched loosely.
+func namePattern(name, suite, suffix string) (*regexp.Regexp, error) {
+	tmpl, err := template.New("name").Parse(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid report name template %q: %w", name, err)
+	}
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	for _, node := range tmpl.Tree.Root.Nodes {
+		switch node := node.(type) {
+		case *parse.TextNode:
+			pattern.WriteString(regexp.QuoteMeta(unsafeNameChars.Replace(string(node.Text))))
+		case *parse.ActionNode:
+			expression, err := placeholderPattern(node, suite)
+			if err != nil {
+				return nil, fmt.Errorf("report name template %q doesn't support retention: %w", name, err)
+			}
+			pattern.WriteString(expression)
+		default:
+			return nil, fmt.Errorf("report name template %q doesn't support retention: unsupported %s", name, node)
+		}
+	}
+	pattern.WriteString(regexp.QuoteMeta(suffix))
+	pattern.WriteString("$")
+	return regexp.Compile(pattern.String())
+}
+
+// placeholderPattern returns the regular expression matching what a template action renders to.
+func placeholderPattern(node *parse.ActionNode, suite string) (string, error) {
+	if len(node.Pipe.Decl) != 0 || len(node.Pipe.Cmds) != 1 {
+		return "", fmt.Errorf("unsupported placeholder %s", node)
+	}
+	args := node.Pipe.Cmds[0].Args
+	field, ok := args[0].(*parse.FieldNode)
+	if !ok {
+		return "", fmt.Errorf("unsupported placeholder %s", node)
+	}
+	switch strings.Join(field.Ident, ".") {
+	case "Name":
+		if len(args) == 1 {
+			return regexp.QuoteMeta(unsafeNameChars.Replace(suite)), nil
+		}
+	case "RunID":
+		if len(args) == 1 {
+			// random run ids, or the start time when randomness is not available
+			return `(?:[0-9a-f]{12}|[0-9]{8}T[0-9]{6}Z)`, nil
+		}
+	case "Date":
+		if len(args) == 1 {
+			return `[0-9]{4}-[0-9]{2}-[0-9]{2}`, nil
+		}
+	case "Ext":
+		// report names are rendered without extension, see ResolveName
+		if len(args) == 1 {
+			return "", nil
+		}
+	case "StartTime.Format":
+		if len(args) == 2 {
+			if layout, ok := args[1].(*parse.StringNode); ok {
+				return layoutPattern(layout.Text)
+			}
+		}
+	}
+	return "", fmt.Errorf("unsupported placeholder %s", node)
+}
+
+// textualLayoutTokens are the time layout elements rendering names or time zones rather than digits.
+var textualLayoutTokens = []string{"Jan", "Mon", "MST", "PM", "pm", "Z07", "-07", "-7"}
+
+// layoutPattern returns the regular expression matching a numeric time layout, e.g. 20060102-150405.
+// Digits of the layout stand for digits of the rendered time, layouts rendering names or time zones are rejected.
+func layoutPattern(layout string) (string, error) {
+	for _, token := range textualLayoutTokens {
+		if strings.Contains(layout, token) {
+			return "", fmt.Errorf("unsupported time layout %q, only numeric layouts are supported", layout)
+		}
+	}
+	var pattern strings.Builder
+	for _, r := range unsafeNameChars.Replace(layout) {
+		if r >= '0' && r <= '9' {
+			pattern.WriteString("[0-9]")
+		} else {
+			pattern.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	return pattern.String(), nil
+}
@@ -0,0 +1,216 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	v1alpha1 "github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamePattern(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		suffix   string
+		matches  []string
+		others   []string
+		wantErr  bool
+	}{{
+		name:     "time and run id",
+		template: `chainsaw-{{ .StartTime.Format "20060102-150405" }}-{{ .RunID }}`,
+		suffix:   ".json",
+		matches:  []string{"chainsaw-20240101-100000-0123456789ab.json", "chainsaw-20231231-235959-20231231T235959Z.json"},
+		others: []string{
+			"chainsaw-20240101-100000-0123456789ab.xml",
+			"chainsaw-20240101-100000-0123456789ab.json.sha256",
+			"chainsaw-20240101-100000-0123456789ab.json.journal",
+			"chainsaw-20240101-100000-notarunid0000.json",
+			"chainsaw-2024-01-01-0123456789ab.json",
+			"chainsaw-report.json",
+			"my-chainsaw-20240101-100000-0123456789ab.json",
+			"chainsaw-20240101-100000-0123456789ab-failures.json",
+		},
+	}, {
+		name:     "date and name",
+		template: `{{ .Name }}-{{ .Date }}`,
+		suffix:   ".xml.gz",
+		matches:  []string{"suite-2024-01-01.xml.gz"},
+		others:   []string{"suite-2024-01-01.xml", "other-2024-01-01.xml.gz", "suite-latest.xml.gz"},
+	}, {
+		name:     "sanitized text",
+		template: `nightly/{{ .Date }}`,
+		matches:  []string{"nightly-2024-01-01"},
+		others:   []string{"nightly-2024-01-01.json", "nightlyx2024-01-01"},
+	}, {
+		name:     "layout with separators",
+		template: `run-{{ .StartTime.Format "2006-01-02T15:04:05" }}`,
+		suffix:   ".json",
+		matches:  []string{"run-2024-01-01T10-00-00.json"},
+		others:   []string{"run-2024-01-01T10:00:00.json", "run-2024-01-01X10-00-00.json"},
+	}, {
+		name:     "textual layout",
+		template: `run-{{ .StartTime.Format "Jan-02" }}`,
+		wantErr:  true,
+	}, {
+		name:     "time zone layout",
+		template: `run-{{ .StartTime.Format "20060102Z0700" }}`,
+		wantErr:  true,
+	}, {
+		name:     "unknown field",
+		template: `run-{{ .Unknown }}`,
+		wantErr:  true,
+	}, {
+		name:     "functions",
+		template: `run-{{ printf "%s" .RunID }}`,
+		wantErr:  true,
+	}, {
+		name:     "pipelines",
+		template: `run-{{ .RunID | printf "%s" }}`,
+		wantErr:  true,
+	}, {
+		name:     "conditionals",
+		template: `run{{ if .RunID }}-{{ .RunID }}{{ end }}`,
+		wantErr:  true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern, err := namePattern(tt.template, "suite", tt.suffix)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			for _, name := range tt.matches {
+				assert.True(t, pattern.MatchString(name), name)
+			}
+			for _, name := range tt.others {
+				assert.False(t, pattern.MatchString(name), name)
+			}
+		})
+	}
+}
+
+func TestTestsReport_ApplyRetention(t *testing.T) {
+	const template = `chainsaw-{{ .StartTime.Format "20060102-150405" }}-{{ .RunID }}`
+	now := time.Date(2024, 1, 10, 10, 0, 0, 0, time.UTC)
+	// reports from previous runs, newest first
+	previous := []string{
+		"chainsaw-20240109-100000-00000000000a.json",
+		"chainsaw-20240108-100000-00000000000b.json",
+		"chainsaw-20240105-100000-00000000000c.json",
+		"chainsaw-20240101-100000-00000000000d.json",
+	}
+	// files that must never be touched
+	untouched := []string{
+		"chainsaw-report.json",
+		"chainsaw-20240101-100000-00000000000d.xml",
+		"chainsaw-20240101-100000-00000000000d.json.journal",
+		"notes.txt",
+	}
+	setup := func(t *testing.T) (string, *TestsReport) {
+		dir := t.TempDir()
+		for i, file := range previous {
+			path := filepath.Join(dir, file)
+			assert.NoError(t, os.WriteFile(path, []byte("{}"), 0o600))
+			modTime := now.Add(-time.Duration(i+1) * 24 * time.Hour)
+			assert.NoError(t, os.Chtimes(path, modTime, modTime))
+		}
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, previous[3]+ChecksumExtension), []byte("sum"), 0o600))
+		for _, file := range untouched {
+			path := filepath.Join(dir, file)
+			assert.NoError(t, os.WriteFile(path, []byte("{}"), 0o600))
+			old := now.Add(-365 * 24 * time.Hour)
+			assert.NoError(t, os.Chtimes(path, old, old))
+		}
+		report := NewTests("suite")
+		report.TimeStamp = now
+		report.RunID = "0123456789ab"
+		report.Close()
+		return dir, report
+	}
+	tests := []struct {
+		name        string
+		policy      RetentionPolicy
+		wantRemoved []string
+	}{{
+		name:        "keep newest",
+		policy:      RetentionPolicy{Keep: 3},
+		wantRemoved: []string{previous[2], previous[3], previous[3] + ChecksumExtension},
+	}, {
+		name:        "max age",
+		policy:      RetentionPolicy{MaxAge: 72 * time.Hour},
+		wantRemoved: []string{previous[3], previous[3] + ChecksumExtension},
+	}, {
+		name:        "both limits",
+		policy:      RetentionPolicy{Keep: 3, MaxAge: 36 * time.Hour},
+		wantRemoved: []string{previous[1], previous[2], previous[3], previous[3] + ChecksumExtension},
+	}, {
+		name:        "keep one",
+		policy:      RetentionPolicy{Keep: 1},
+		wantRemoved: []string{previous[0], previous[1], previous[2], previous[3], previous[3] + ChecksumExtension},
+	}, {
+		name:   "disabled",
+		policy: RetentionPolicy{},
+	}}
+	for _, tt := range tests {
+		for _, dryRun := range []bool{false, true} {
+			name := tt.name
+			if dryRun {
+				name += " dry run"
+			}
+			t.Run(name, func(t *testing.T) {
+				dir, report := setup(t)
+				var notified []string
+				policy := tt.policy
+				policy.DryRun = dryRun
+				policy.now = func() time.Time { return now }
+				policy.OnRemove = func(path string) { notified = append(notified, filepath.Base(path)) }
+				options := SaveOptions{Retention: &policy}
+				assert.NoError(t, report.SaveReportBasedOnType(v1alpha1.JSONFormat, dir, template, options))
+				written := filepath.Join(dir, "chainsaw-20240110-100000-0123456789ab.json")
+				assert.FileExists(t, written)
+				sort.Strings(notified)
+				want := append([]string{}, tt.wantRemoved...)
+				sort.Strings(want)
+				if len(want) == 0 {
+					want = nil
+				}
+				assert.Equal(t, want, notified)
+				for _, file := range untouched {
+					assert.FileExists(t, filepath.Join(dir, file))
+				}
+				for _, file := range tt.wantRemoved {
+					if dryRun {
+						assert.FileExists(t, filepath.Join(dir, file))
+					} else {
+						assert.NoFileExists(t, filepath.Join(dir, file))
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestTestsReport_ApplyRetentionSkipped(t *testing.T) {
+	dir := t.TempDir()
+	old := filepath.Join(dir, "chainsaw-report.json")
+	assert.NoError(t, os.WriteFile(old, []byte("{}"), 0o600))
+	report := NewTests("suite")
+	policy := RetentionPolicy{Keep: 1}
+	// names without placeholders always designate the same file
+	removed, err := report.ApplyRetention(v1alpha1.JSONFormat, dir, "chainsaw-report", false, policy)
+	assert.NoError(t, err)
+	assert.Empty(t, removed)
+	removed, err = report.ApplyRetention(v1alpha1.JSONFormat, dir, StdoutName, false, policy)
+	assert.NoError(t, err)
+	assert.Empty(t, removed)
+	assert.FileExists(t, old)
+	// templates that can't be matched strictly are rejected
+	_, err = report.ApplyRetention(v1alpha1.JSONFormat, dir, `{{ printf "report" }}`, false, policy)
+	assert.Error(t, err)
+	assert.FileExists(t, old)
+}
@@ -0,0 +1,122 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+type s3Client struct {
+	client *s3.S3
+}
+
+// NewS3Client creates an S3 compatible client, credentials are read from the AWS_* environment variables.
+// When endpoint is set, path style addressing is used so that MinIO and similar servers work out of the box.
+func NewS3Client(endpoint, region string) (ObjectStorageClient, error) {
+	if region == "" {
+		region = os.Getenv("AWS_REGION")
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+	config := aws.NewConfig().
+		WithRegion(region).
+		WithCredentials(credentials.NewEnvCredentials())
+	if endpoint != "" {
+		config = config.WithEndpoint(endpoint).WithS3ForcePathStyle(true)
+	}
+	sess, err := session.NewSession(config)
+	if err != nil {
+		return nil, err
+	}
+	return &s3Client{client: s3.New(sess)}, nil
+}
+
+func withHeaders(headers map[string]string) request.Option {
+	return func(r *request.Request) {
+		for key, value := range headers {
+			r.HTTPRequest.Header.Set(key, value)
+		}
+	}
+}
+
+// customerKeyHeaders returns the SSE-C headers, they are required on every call of a multipart upload while
+// the other encryption headers are only accepted when the upload is created.
+func customerKeyHeaders(headers map[string]string) map[string]string {
+	out := map[string]string{}
+	for key, value := range headers {
+		if strings.HasPrefix(strings.ToLower(key), "x-amz-server-side-encryption-customer-") {
+			out[key] = value
+		}
+	}
+	return out
+}
+
+func (c *s3Client) PutObject(ctx context.Context, input ObjectInput, data []byte) error {
+	_, err := c.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(input.Bucket),
+		Key:         aws.String(input.Key),
+		ContentType: aws.String(input.ContentType),
+		Body:        bytes.NewReader(data),
+	}, withHeaders(input.Headers))
+	return err
+}
+
+func (c *s3Client) CreateMultipartUpload(ctx context.Context, input ObjectInput) (string, error) {
+	out, err := c.client.CreateMultipartUploadWithContext(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:      aws.String(input.Bucket),
+		Key:         aws.String(input.Key),
+		ContentType: aws.String(input.ContentType),
+	}, withHeaders(input.Headers))
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(out.UploadId), nil
+}
+
+func (c *s3Client) UploadPart(ctx context.Context, input ObjectInput, uploadID string, partNumber int64, data []byte) (string, error) {
+	out, err := c.client.UploadPartWithContext(ctx, &s3.UploadPartInput{
+		Bucket:     aws.String(input.Bucket),
+		Key:        aws.String(input.Key),
+		UploadId:   aws.String(uploadID),
+		PartNumber: aws.Int64(partNumber),
+		Body:       bytes.NewReader(data),
+	}, withHeaders(customerKeyHeaders(input.Headers)))
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(out.ETag), nil
+}
+
+func (c *s3Client) CompleteMultipartUpload(ctx context.Context, input ObjectInput, uploadID string, parts []CompletedPart) error {
+	completed := make([]*s3.CompletedPart, 0, len(parts))
+	for _, part := range parts {
+		completed = append(completed, &s3.CompletedPart{
+			PartNumber: aws.Int64(part.PartNumber),
+			ETag:       aws.String(part.ETag),
+		})
+	}
+	_, err := c.client.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(input.Bucket),
+		Key:             aws.String(input.Key),
+		UploadId:        aws.String(uploadID),
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: completed},
+	}, withHeaders(customerKeyHeaders(input.Headers)))
+	return err
+}
+
+func (c *s3Client) AbortMultipartUpload(ctx context.Context, input ObjectInput, uploadID string) error {
+	_, err := c.client.AbortMultipartUploadWithContext(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(input.Bucket),
+		Key:      aws.String(input.Key),
+		UploadId: aws.String(uploadID),
+	})
+	return err
+}
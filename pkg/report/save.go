@@ -0,0 +1,194 @@
+package report
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	v1alpha1 "github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
+)
+
+// StdoutName is the report name used to write the report to stdout instead of a file.
+const StdoutName = "-"
+
+// stdout is the dedicated writer used when the report is written to stdout, it can be overridden in tests.
+var stdout io.Writer = os.Stdout
+
+// SaveOptions configures how a report is saved.
+type SaveOptions struct {
+	// Compress gzips the serialized report and appends the .gz extension to the file name.
+	Compress bool
+	// MaxSize, if positive, splits reports serializing to more than MaxSize bytes into multiple parts, see Split.
+	// The size is checked before compression.
+	MaxSize int
+	// Checksum writes a sidecar file holding the SHA-256 of the written bytes next to the report, see VerifyChecksum.
+	Checksum bool
+	// SigningKey, if set, writes a sidecar file holding the HMAC-SHA256 of the written bytes next to the report, see VerifySignature.
+	SigningKey []byte
+	// Retention, if set, removes previous reports generated from the same templated name once the report is written.
+	// It only applies to reports saved with SaveReportBasedOnType, see ApplyRetention.
+	Retention *RetentionPolicy
+	// UTC serializes the timestamps of the report in UTC rather than in the time zone they were recorded in, see InUTC.
+	UTC bool
+}
+
+func SaveReport(report *TestsReport, serializer ReportSerializer, filePath string) error {
+	return SaveReportWithOptions(report, serializer, filePath, SaveOptions{})
+}
+
+// SaveReportTo serializes the report into w, streaming serializers write to w directly.
+func SaveReportTo(report *TestsReport, serializer ReportSerializer, w io.Writer) error {
+	if streaming, ok := serializer.(StreamingReportSerializer); ok {
+		return streaming.SerializeTo(w, report)
+	}
+	data, err := serializer.Serialize(report)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func SaveReportWithOptions(report *TestsReport, serializer ReportSerializer, filePath string, options SaveOptions) error {
+	return SaveReportContext(context.Background(), report, serializer, filePath, options)
+}
+
+// SaveReportContext serializes the report and writes it to the sink resolved from the destination.
+// Destinations without a scheme are files, see Register for the supported schemes.
+func SaveReportContext(ctx context.Context, report *TestsReport, serializer ReportSerializer, destination string, options SaveOptions) error {
+	if options.UTC {
+		report = report.InUTC()
+	}
+	serialize := func(w io.Writer) error {
+		return SaveReportTo(report, serializer, w)
+	}
+	if options.MaxSize > 0 && destination != StdoutName {
+		// the size of the report is only known once it is serialized
+		var buf bytes.Buffer
+		if err := serialize(&buf); err != nil {
+			return err
+		}
+		if buf.Len() > options.MaxSize {
+			return saveSplit(ctx, report, serializer, destination, options)
+		}
+		serialize = writeBytes(buf.Bytes())
+	}
+	return save(ctx, serializer, destination, options, serialize)
+}
+
+// save writes the data written by serialize to the sink resolved from the destination. It is streamed to sinks
+// implementing StreamingReportSink, and buffered for the others.
+func save(ctx context.Context, serializer ReportSerializer, destination string, options SaveOptions, serialize func(io.Writer) error) error {
+	if options.hasSidecars() && !IsFile(destination) {
+		return fmt.Errorf("checksum and signature files are only supported for file reports, not %s", destination)
+	}
+	sink, name, err := ResolveSink(options.destination(destination))
+	if err != nil {
+		return err
+	}
+	// urls carry no extension, tell http sinks what they upload
+	if httpSink, ok := sink.(*HTTPReportSink); ok && httpSink.ContentType == "" {
+		typed := *httpSink
+		typed.ContentType = ContentType(serializer)
+		if options.Compress {
+			typed.ContentType = "application/gzip"
+		}
+		sink = &typed
+	}
+	// sidecars cover the exact bytes written, after compression
+	sidecars := options.sidecars()
+	write := func(w io.Writer) error {
+		return options.write(io.MultiWriter(w, sidecars), serialize)
+	}
+	if streaming, ok := sink.(StreamingReportSink); ok {
+		err = streaming.WriteStream(ctx, name, write)
+	} else {
+		var buf bytes.Buffer
+		if err = write(&buf); err == nil {
+			err = sink.Write(ctx, name, buf.Bytes())
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return sidecars.write(ctx, sink, name)
+}
+
+// writeBytes returns a function writing data, to save data already serialized.
+func writeBytes(data []byte) func(io.Writer) error {
+	return func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	}
+}
+
+// destination returns the destination the data is actually written to, compressed files get the .gz extension.
+func (o SaveOptions) destination(destination string) string {
+	if destination != StdoutName && o.Compress && filepath.Ext(destination) != ".gz" {
+		return destination + ".gz"
+	}
+	return destination
+}
+
+// write writes the data written by serialize to w, compressing it on the fly if needed.
+func (o SaveOptions) write(w io.Writer, serialize func(io.Writer) error) error {
+	if !o.Compress {
+		return serialize(w)
+	}
+	gz := gzip.NewWriter(w)
+	if err := serialize(gz); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func (report *TestsReport) SaveReportBasedOnType(reportFormat v1alpha1.ReportFormatType, reportPath, reportName string, options SaveOptions) error {
+	serializer, err := GetSerializer(reportFormat)
+	if err != nil {
+		return err
+	}
+	// object keys are rendered with the extension of the format, file names get it from FilePath
+	name, ext := reportName, ""
+	if scheme(reportName) == "s3" {
+		name, ext = objectDestination(reportName), extension(serializer)
+	}
+	resolved, err := report.resolveName(name, ext)
+	if err != nil {
+		return err
+	}
+	if err := SaveReportWithOptions(report, serializer, FilePath(reportFormat, reportPath, resolved), options); err != nil {
+		return err
+	}
+	if options.Retention != nil {
+		if _, err := report.ApplyRetention(reportFormat, reportPath, reportName, options.Compress, *options.Retention); err != nil {
+			return fmt.Errorf("failed to apply report retention: %w", err)
+		}
+	}
+	return nil
+}
+
+// FilePath returns the path of the report file for the given format, path and name.
+// The format is used as extension when the name has none.
+func FilePath(reportFormat v1alpha1.ReportFormatType, reportPath, reportName string) string {
+	if reportName == StdoutName {
+		return StdoutName
+	}
+	if !IsFile(reportName) {
+		return reportName
+	}
+	if filepath.Ext(reportName) == "" {
+		if parsed, err := ParseFormat(string(reportFormat)); err == nil {
+			reportFormat = parsed
+		}
+		reportName += "." + strings.ToLower(string(reportFormat))
+	}
+	if reportPath != "" {
+		return filepath.Join(reportPath, reportName)
+	}
+	return reportName
+}
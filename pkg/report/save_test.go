@@ -0,0 +1,123 @@
+package report
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	v1alpha1 "github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func syntheticReport(tests int) *TestsReport {
+	report := NewTests("SyntheticTestSuite")
+	for i := 0; i < tests; i++ {
+		test := NewTest(fmt.Sprintf("test-%d", i))
+		step := NewTestSpecStep(fmt.Sprintf("step-%d", i))
+		op := NewOperation("Apply", OperationTypeApply)
+		op.MarkOperationEnd(nil)
+		step.AddOperation(op)
+		test.AddTestStep(step)
+		test.MarkTestEnd()
+		report.AddTest(test)
+	}
+	report.Close()
+	return report
+}
+
+func TestSaveReportBasedOnType_Compress(t *testing.T) {
+	report := syntheticReport(10000)
+	uncompressed, err := JSONSerializer{}.Serialize(report)
+	assert.NoError(t, err)
+	assert.Greater(t, len(uncompressed), 2*1024*1024, "report should be multi-megabyte")
+	dir := t.TempDir()
+	err = report.SaveReportBasedOnType(v1alpha1.JSONFormat, dir, "chainsaw-report", SaveOptions{Compress: true})
+	assert.NoError(t, err)
+	filePath := filepath.Join(dir, "chainsaw-report.json.gz")
+	info, err := os.Stat(filePath)
+	assert.NoError(t, err)
+	assert.Less(t, info.Size(), int64(len(uncompressed)))
+	f, err := os.Open(filePath)
+	assert.NoError(t, err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	assert.NoError(t, err)
+	data, err := io.ReadAll(gz)
+	assert.NoError(t, err)
+	assert.Equal(t, uncompressed, data)
+	var loaded TestsReport
+	assert.NoError(t, json.Unmarshal(data, &loaded))
+	assert.Len(t, loaded.Reports, 10000)
+}
+
+func TestSaveReportWithOptions_CompressExtension(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		expected string
+	}{{
+		name:     "no gz extension",
+		filePath: "report.json",
+		expected: "report.json.gz",
+	}, {
+		name:     "gz extension",
+		filePath: "report.json.gz",
+		expected: "report.json.gz",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			err := SaveReportWithOptions(NewTests("SampleTestSuite"), JSONSerializer{}, filepath.Join(dir, tt.filePath), SaveOptions{Compress: true})
+			assert.NoError(t, err)
+			entries, err := os.ReadDir(dir)
+			assert.NoError(t, err)
+			assert.Len(t, entries, 1)
+			assert.Equal(t, tt.expected, entries[0].Name())
+			assert.False(t, strings.HasSuffix(entries[0].Name(), ".gz.gz"))
+		})
+	}
+}
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestSaveReportTo(t *testing.T) {
+	report := syntheticReport(3)
+	tests := []struct {
+		name       string
+		serializer ReportSerializer
+	}{{
+		name:       "json",
+		serializer: JSONSerializer{},
+	}, {
+		name:       "xml",
+		serializer: XMLSerializer{},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.NoError(t, SaveReportTo(report, tt.serializer, &buf))
+			want, err := tt.serializer.Serialize(report)
+			assert.NoError(t, err)
+			assert.Equal(t, string(want), buf.String())
+			assert.EqualError(t, SaveReportTo(report, tt.serializer, failingWriter{}), "write failed")
+		})
+	}
+}
+
+func TestSaveReportTo_SerializerError(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Error(t, SaveReportTo(NewTests("suite"), FakeSerializer{}, &buf))
+	assert.Zero(t, buf.Len())
+}
@@ -0,0 +1,68 @@
+package report
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+
+	"github.com/xeipuuv/gojsonschema"
+	"go.uber.org/multierr"
+)
+
+// FormatVersion is the version of the report format written by this package.
+// It is bumped, together with a new JSON Schema, whenever fields are added to or removed from the report, the schemas
+// of the published versions never change.
+const FormatVersion = 2
+
+//go:embed schemas/tests-report-v*.json
+var schemas embed.FS
+
+// Schema returns the JSON Schema of the JSON report format, for the current FormatVersion.
+func Schema() []byte {
+	data, err := SchemaVersion(FormatVersion)
+	if err != nil {
+		// the schema of the current version is embedded, reading it doesn't fail
+		panic(err)
+	}
+	return data
+}
+
+// SchemaVersion returns the JSON Schema of the given version of the JSON report format.
+func SchemaVersion(version int) ([]byte, error) {
+	data, err := schemas.ReadFile(fmt.Sprintf("schemas/tests-report-v%d.json", version))
+	if err != nil {
+		return nil, fmt.Errorf("no schema for report format version %d", version)
+	}
+	return data, nil
+}
+
+// ValidateAgainstSchema checks a JSON report against the JSON Schema of the version of the report format it follows,
+// reports written before the format was versioned are checked against the first version.
+func ValidateAgainstSchema(data []byte) error {
+	var header struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return fmt.Errorf("failed to validate report against schema: %w", err)
+	}
+	version := header.Version
+	if version == 0 {
+		version = 1
+	}
+	schema, err := SchemaVersion(version)
+	if err != nil {
+		return fmt.Errorf("failed to validate report against schema: %w", err)
+	}
+	result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(schema), gojsonschema.NewBytesLoader(data))
+	if err != nil {
+		return fmt.Errorf("failed to validate report against schema: %w", err)
+	}
+	if result.Valid() {
+		return nil
+	}
+	var errs []error
+	for _, violation := range result.Errors() {
+		errs = append(errs, fmt.Errorf("%s: %s", violation.Field(), violation.Description()))
+	}
+	return multierr.Combine(errs...)
+}
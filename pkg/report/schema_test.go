@@ -0,0 +1,197 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// populatedReport returns a report where every field is set.
+func populatedReport() *TestsReport {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	first, last := start, start.Add(time.Second)
+	report := &TestsReport{
+		Name:      "suite",
+		RunID:     "run",
+		Git:       &GitInfo{Commit: "0123456789abcdef0123456789abcdef01234567", Branch: "main", Dirty: true},
+		Version:   FormatVersion,
+		TimeStamp: start,
+		Time:      "2.000",
+		Reports: []*TestReport{{
+			Name:        "test",
+			TimeStamp:   start,
+			Time:        "1.500",
+			Failure:     &Failure{Message: "failed"},
+			Concurrent:  true,
+			Namespace:   "chainsaw",
+			Path:        "tests/test",
+			Labels:      map[string]string{"team": "core"},
+			Environment: Environment{{Name: "REGISTRY", Value: "mirror"}, {Name: "MISSING", Unset: true}},
+			Skip:        true,
+			Quarantined: true,
+			Interrupted: true,
+			SkipDelete:  true,
+			Warnings:    []Warning{{Type: WarningTypeSlowCleanup, Message: "slow"}},
+			Logs:        Logs{{Time: start, Message: "log"}},
+			Steps: []*TestSpecStepReport{{
+				Name: "step",
+				Results: []*OperationReport{{
+					Name:                 "assert",
+					TimeStamp:            start,
+					Time:                 "1.000",
+					Result:               "Failure",
+					Message:              "not found",
+					OperationType:        OperationTypeAssert,
+					Attempts:             2,
+					FirstAttemptAt:       &first,
+					LastAttemptAt:        &last,
+					AttemptIntervalStats: &AttemptIntervalStats{Min: "1.000", Max: "1.000", Avg: "1.000"},
+				}},
+			}},
+		}},
+	}
+	report.aggregate()
+	return report
+}
+
+func TestValidateAgainstSchema(t *testing.T) {
+	data, err := JSONSerializer{}.Serialize(populatedReport())
+	assert.NoError(t, err)
+	assert.NoError(t, ValidateAgainstSchema(data))
+	assert.NoError(t, ValidateAgainstSchema(mustSerialize(t, syntheticReport(3))))
+	// reports of the previous versions are checked against their own schema
+	assert.NoError(t, ValidateAgainstSchema([]byte(`{"name":"suite","version":1,"timestamp":"2024-01-01T10:00:00Z","time":"1.000","tests":0,"testsuite":[],"failures":0}`)))
+	assert.NoError(t, ValidateAgainstSchema([]byte(`{"name":"suite","timestamp":"2024-01-01T10:00:00Z","time":"1.000","tests":0,"testsuite":[],"failures":0}`)))
+}
+
+// publishedSchemas are the SHA-256 of the schemas of the published versions of the report format.
+// Never update a hash: when the report changes, bump FormatVersion and add the schema of the new version.
+var publishedSchemas = map[int]string{
+	1: "437a430d4ef93247adf6b8db4db1e413a2a31607f139ad4ef39b2c8347dcd44c",
+	2: "e8522713556455da70cfdd293fb925a40156e531f4a0c0ccf972a3c46da0fb13",
+}
+
+// TestSchema_Published fails when the schema of a published version changes, or when a version has no schema.
+func TestSchema_Published(t *testing.T) {
+	assert.Len(t, publishedSchemas, FormatVersion, "the schema of every version up to FormatVersion must be published")
+	for version := 1; version <= FormatVersion; version++ {
+		data, err := SchemaVersion(version)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		sum := sha256.Sum256(data)
+		assert.Equal(t, publishedSchemas[version], hex.EncodeToString(sum[:]), "the schema of version %d changed, bump FormatVersion instead", version)
+		var doc struct {
+			ID         string `json:"$id"`
+			Properties struct {
+				Version struct {
+					Const int `json:"const"`
+				} `json:"version"`
+			} `json:"properties"`
+		}
+		assert.NoError(t, json.Unmarshal(data, &doc))
+		assert.Equal(t, fmt.Sprintf("https://kyverno.github.io/chainsaw/schemas/tests-report-v%d.json", version), doc.ID)
+		assert.Equal(t, version, doc.Properties.Version.Const)
+	}
+	_, err := SchemaVersion(FormatVersion + 1)
+	assert.Error(t, err)
+}
+
+func TestValidateAgainstSchema_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{{
+		name: "not json",
+		data: "{",
+		want: "failed to validate report against schema",
+	}, {
+		name: "missing fields",
+		data: `{"name":"suite"}`,
+		want: "timestamp is required",
+	}, {
+		name: "unknown field",
+		data: `{"name":"suite","timestamp":"2024-01-01T10:00:00Z","time":"1.000","tests":0,"testsuite":[],"failures":0,"extra":true}`,
+		want: "Additional property extra is not allowed",
+	}, {
+		name: "newer version",
+		data: `{"name":"suite","version":3,"timestamp":"2024-01-01T10:00:00Z","time":"1.000","tests":0,"testsuite":[],"failures":0}`,
+		want: "no schema for report format version 3",
+	}, {
+		name: "field of a later version",
+		data: `{"name":"suite","version":1,"git":{"commit":"0123456789abcdef0123456789abcdef01234567"},"timestamp":"2024-01-01T10:00:00Z","time":"1.000","tests":0,"testsuite":[],"failures":0}`,
+		want: "Additional property git is not allowed",
+	}, {
+		name: "bad duration",
+		data: `{"name":"suite","timestamp":"2024-01-01T10:00:00Z","time":"1s","tests":0,"testsuite":[],"failures":0}`,
+		want: "time",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAgainstSchema([]byte(tt.data))
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.want)
+			}
+		})
+	}
+}
+
+// TestSchema_Fields fails when a JSON field is added to the report types without being described in the schema.
+func TestSchema_Fields(t *testing.T) {
+	var doc struct {
+		Properties  map[string]any `json:"properties"`
+		Definitions map[string]struct {
+			Properties map[string]struct {
+				Properties map[string]any `json:"properties"`
+			} `json:"properties"`
+		} `json:"definitions"`
+	}
+	assert.NoError(t, json.Unmarshal(Schema(), &doc))
+	definitions := map[reflect.Type]map[string]any{
+		reflect.TypeOf(TestsReport{}): doc.Properties,
+	}
+	for name, typ := range map[string]reflect.Type{
+		"test":      reflect.TypeOf(TestReport{}),
+		"step":      reflect.TypeOf(TestSpecStepReport{}),
+		"operation": reflect.TypeOf(OperationReport{}),
+		"warning":   reflect.TypeOf(Warning{}),
+		"logLine":   reflect.TypeOf(LogLine{}),
+	} {
+		properties := map[string]any{}
+		for property := range doc.Definitions[name].Properties {
+			properties[property] = nil
+		}
+		definitions[typ] = properties
+	}
+	for typ, properties := range definitions {
+		for i := 0; i < typ.NumField(); i++ {
+			name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+			if name == "" || name == "-" {
+				continue
+			}
+			assert.Contains(t, properties, name, "%s.%s is not described in the schema", typ.Name(), typ.Field(i).Name)
+		}
+	}
+}
+
+func TestSchema(t *testing.T) {
+	// callers can't alter the embedded schema
+	data := Schema()
+	data[0] = 'x'
+	assert.True(t, json.Valid(Schema()))
+}
+
+func mustSerialize(t *testing.T, report *TestsReport) []byte {
+	t.Helper()
+	data, err := JSONSerializer{}.Serialize(report)
+	assert.NoError(t, err)
+	return data
+}
@@ -0,0 +1,105 @@
+package report
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// SelectedTest identifies a test to run again.
+type SelectedTest struct {
+	// Name of the test.
+	Name string
+	// Path is the folder the test was loaded from, reports written before paths were recorded have none.
+	Path string
+}
+
+// TestSelection is a set of tests to run again, typically the failed tests of a previous run.
+type TestSelection struct {
+	// Tests are the selected tests, in report order.
+	Tests []SelectedTest
+	// Warnings explain why failed tests were left out of the selection.
+	Warnings []string
+}
+
+// LoadFailedTestSelection loads a report, see Load, and returns the selection of its failed tests.
+func LoadFailedTestSelection(path string) (TestSelection, error) {
+	report, err := Load(path)
+	if err != nil {
+		return TestSelection{}, err
+	}
+	return FailedTestSelection(report), nil
+}
+
+// FailedTestSelection returns the failed tests of the report, including tests that errored before running any step.
+// Tests whose folder doesn't exist anymore are left out with a warning, repeated tests are selected once.
+func FailedTestSelection(report *TestsReport) TestSelection {
+	report.lock.Lock()
+	defer report.lock.Unlock()
+	var selection TestSelection
+	seen := map[SelectedTest]bool{}
+	for _, test := range report.Reports {
+		test.lock.Lock()
+		// older reports don't always record the test failure, only the failed operation
+		failed := test.Failure != nil || test.failedStep() != ""
+		selected := SelectedTest{Name: test.Name, Path: test.Path}
+		test.lock.Unlock()
+		if !failed || seen[selected] {
+			continue
+		}
+		seen[selected] = true
+		if selected.Path != "" {
+			if _, err := os.Stat(selected.Path); err != nil {
+				selection.Warnings = append(selection.Warnings, fmt.Sprintf("skipping failed test %s, its folder %s can't be read (%s)", selected.Name, selected.Path, err))
+				continue
+			}
+		}
+		selection.Tests = append(selection.Tests, selected)
+	}
+	return selection
+}
+
+// Paths returns the folders holding the selected tests, to be used as test directories.
+// It returns nil if the selection is empty or a selected test has no path, tests must then be discovered from the usual folders.
+func (s TestSelection) Paths() []string {
+	var paths []string
+	seen := map[string]bool{}
+	for _, test := range s.Tests {
+		if test.Path == "" {
+			return nil
+		}
+		if path := filepath.Clean(test.Path); !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// Matches returns true if the test with the given name, loaded from the given folder, is selected.
+func (s TestSelection) Matches(name, path string) bool {
+	for _, test := range s.Tests {
+		if test.Name == name && (test.Path == "" || filepath.Clean(test.Path) == filepath.Clean(path)) {
+			return true
+		}
+	}
+	return false
+}
+
+// IncludeTestRegex returns a regular expression matching the selected tests, suitable for the IncludeTestRegex configuration.
+// It matches test names, it doesn't apply when tests are named after their full path.
+func (s TestSelection) IncludeTestRegex() string {
+	var names []string
+	seen := map[string]bool{}
+	for _, test := range s.Tests {
+		if seen[test.Name] {
+			continue
+		}
+		seen[test.Name] = true
+		// the testing framework splits the expression by level on slashes not enclosed in brackets
+		names = append(names, strings.ReplaceAll(regexp.QuoteMeta(test.Name), "/", "[/]"))
+	}
+	return "^chainsaw$/^(" + strings.Join(names, "|") + ")$"
+}
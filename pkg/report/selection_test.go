@@ -0,0 +1,107 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFailedTestSelection(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "broken")
+	assert.NoError(t, os.Mkdir(existing, 0o755))
+	missing := filepath.Join(dir, "removed")
+	tests := []struct {
+		name         string
+		report       *TestsReport
+		want         []SelectedTest
+		wantWarnings int
+	}{{
+		name: "current report",
+		report: &TestsReport{
+			Version: FormatVersion,
+			Reports: []*TestReport{
+				{Name: "passed", Path: existing},
+				{Name: "broken", Path: existing, Failure: &Failure{Message: "failed"}},
+				{Name: "broken", Path: existing, Failure: &Failure{Message: "failed again"}},
+				{Name: "skipped", Path: existing, Skip: true},
+			},
+		},
+		want: []SelectedTest{{Name: "broken", Path: existing}},
+	}, {
+		name: "failed without steps",
+		report: &TestsReport{
+			Reports: []*TestReport{{Name: "errored", Path: existing, Failure: &Failure{Message: "failed to create namespace"}}},
+		},
+		want: []SelectedTest{{Name: "errored", Path: existing}},
+	}, {
+		name: "folder removed",
+		report: &TestsReport{
+			Reports: []*TestReport{
+				{Name: "removed", Path: missing, Failure: &Failure{Message: "failed"}},
+				{Name: "broken", Path: existing, Failure: &Failure{Message: "failed"}},
+			},
+		},
+		want:         []SelectedTest{{Name: "broken", Path: existing}},
+		wantWarnings: 1,
+	}, {
+		name: "nothing failed",
+		report: &TestsReport{
+			Reports: []*TestReport{{Name: "passed", Path: existing}},
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selection := FailedTestSelection(tt.report)
+			assert.Equal(t, tt.want, selection.Tests)
+			assert.Len(t, selection.Warnings, tt.wantWarnings)
+		})
+	}
+}
+
+func TestLoadFailedTestSelection_OlderFormat(t *testing.T) {
+	// reports written before versions and paths were recorded only flag the failed operation
+	data := `{
+  "name": "chainsaw",
+  "tests": 2,
+  "testsuite": [{
+    "name": "quick",
+    "testcase": [{"name": "step-1", "results": [{"name": "apply", "result": "Success"}]}]
+  }, {
+    "name": "broken",
+    "testcase": [{"name": "step-1", "results": [{"name": "assert", "result": "Failure"}]}]
+  }]
+}`
+	path := filepath.Join(t.TempDir(), "report.json")
+	assert.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+	selection, err := LoadFailedTestSelection(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []SelectedTest{{Name: "broken"}}, selection.Tests)
+	assert.Empty(t, selection.Warnings)
+	// without paths tests are discovered from the usual folders and matched by name
+	assert.Nil(t, selection.Paths())
+	assert.True(t, selection.Matches("broken", "anywhere"))
+	assert.False(t, selection.Matches("quick", "anywhere"))
+	_, err = LoadFailedTestSelection(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestTestSelection_Filters(t *testing.T) {
+	selection := TestSelection{Tests: []SelectedTest{
+		{Name: "first", Path: "tests/first"},
+		{Name: "second", Path: "./tests/second"},
+		{Name: "second", Path: "tests/second"},
+		{Name: "a/b.c", Path: "tests/other"},
+	}}
+	assert.Equal(t, []string{"tests/first", "tests/second", "tests/other"}, selection.Paths())
+	assert.True(t, selection.Matches("second", "tests/second"))
+	assert.False(t, selection.Matches("second", "tests/first"))
+	assert.False(t, selection.Matches("third", "tests/third"))
+	assert.Equal(t, `^chainsaw$/^(first|second|a[/]b\.c)$`, selection.IncludeTestRegex())
+	name := regexp.MustCompile(`^(first|second|a[/]b\.c)$`)
+	assert.True(t, name.MatchString("a/b.c"))
+	assert.False(t, name.MatchString("a/bxc"))
+}
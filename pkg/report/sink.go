@@ -0,0 +1,167 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ReportSink writes serialized reports to a destination.
+type ReportSink interface {
+	// Write writes data under the given name, the meaning of name depends on the sink.
+	Write(ctx context.Context, name string, data []byte) error
+}
+
+// StreamingReportSink is implemented by sinks able to write reports while they are serialized, without buffering them.
+type StreamingReportSink interface {
+	ReportSink
+	// WriteStream writes the data written by write under the given name.
+	WriteStream(ctx context.Context, name string, write func(io.Writer) error) error
+}
+
+// SinkFactory creates the sink for a destination and returns the name the data should be written under.
+type SinkFactory func(destination string) (ReportSink, string, error)
+
+var (
+	sinksLock sync.RWMutex
+	sinks     = map[string]SinkFactory{
+		"-":     newStdoutSink,
+		"file":  newFileSink,
+		"http":  newHTTPSink,
+		"https": newHTTPSink,
+		"s3":    newObjectStorageSink,
+	}
+)
+
+// Register makes a sink available for destinations with the given scheme, for example "gs" or "gs://".
+// It panics if the scheme is already registered or the factory is nil.
+func Register(scheme string, factory SinkFactory) {
+	scheme = strings.TrimSuffix(scheme, "://")
+	if scheme == "" {
+		panic("report: Register sink with empty scheme")
+	}
+	if factory == nil {
+		panic("report: Register sink factory is nil")
+	}
+	sinksLock.Lock()
+	defer sinksLock.Unlock()
+	if _, found := sinks[scheme]; found {
+		panic("report: Register called twice for sink " + scheme)
+	}
+	sinks[scheme] = factory
+}
+
+// Schemes returns the sorted list of registered sink schemes.
+func Schemes() []string {
+	sinksLock.RLock()
+	defer sinksLock.RUnlock()
+	schemes := make([]string, 0, len(sinks))
+	for scheme := range sinks {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+	return schemes
+}
+
+// scheme returns the scheme of a destination, destinations without scheme are files.
+func scheme(destination string) string {
+	if destination == StdoutName {
+		return StdoutName
+	}
+	if scheme, _, ok := strings.Cut(destination, "://"); ok {
+		return scheme
+	}
+	return "file"
+}
+
+// IsFile returns true if the destination is a local file.
+func IsFile(destination string) bool {
+	return scheme(destination) == "file"
+}
+
+// ResolveSink returns the sink for a destination and the name to write the data under.
+func ResolveSink(destination string) (ReportSink, string, error) {
+	scheme := scheme(destination)
+	sinksLock.RLock()
+	factory, found := sinks[scheme]
+	sinksLock.RUnlock()
+	if !found {
+		return nil, "", fmt.Errorf("no report sink registered for scheme %q", scheme)
+	}
+	return factory(destination)
+}
+
+func newStdoutSink(string) (ReportSink, string, error) {
+	return writerSink{writer: stdout}, StdoutName, nil
+}
+
+// writerSink writes data to a writer, the name is ignored.
+type writerSink struct {
+	writer io.Writer
+}
+
+func (s writerSink) Write(ctx context.Context, name string, data []byte) error {
+	return s.WriteStream(ctx, name, writeBytes(data))
+}
+
+func (s writerSink) WriteStream(_ context.Context, _ string, write func(io.Writer) error) error {
+	return write(s.writer)
+}
+
+func newFileSink(destination string) (ReportSink, string, error) {
+	return fileSink{}, strings.TrimPrefix(destination, "file://"), nil
+}
+
+// fileSink writes data atomically to the file with the given name.
+type fileSink struct{}
+
+func (s fileSink) Write(ctx context.Context, filePath string, data []byte) error {
+	return s.WriteStream(ctx, filePath, writeBytes(data))
+}
+
+func (fileSink) WriteStream(_ context.Context, filePath string, write func(io.Writer) error) error {
+	if info, err := os.Stat(filePath); err == nil && info.IsDir() {
+		return fmt.Errorf("report path %s is a directory", filePath)
+	}
+	if dir := filepath.Dir(filePath); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create report directory %s: %w", dir, err)
+		}
+	}
+	return writeFile(filePath, 0o600, write)
+}
+
+func newHTTPSink(destination string) (ReportSink, string, error) {
+	return &HTTPReportSink{URL: destination}, destination, nil
+}
+
+func newObjectStorageSink(destination string) (ReportSink, string, error) {
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(destination, "s3://"), "/")
+	if bucket == "" || key == "" {
+		return nil, "", fmt.Errorf("invalid object storage destination %q, expected s3://<bucket>/<key>", destination)
+	}
+	client, err := NewS3Client(os.Getenv("AWS_ENDPOINT_URL"), "")
+	if err != nil {
+		return nil, "", err
+	}
+	return &ObjectStorageSink{Client: client, Bucket: bucket}, key, nil
+}
+
+// contentTypeFromName returns the media type matching the extension of a report name.
+func contentTypeFromName(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		return "application/json"
+	case ".xml":
+		return "application/xml"
+	case ".gz":
+		return "application/gzip"
+	default:
+		return "application/octet-stream"
+	}
+}
@@ -0,0 +1,251 @@
+package report
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kyverno/chainsaw/pkg/report/sinktest"
+	"github.com/stretchr/testify/assert"
+)
+
+type memorySink struct {
+	sync.Mutex
+	data map[string][]byte
+}
+
+func (s *memorySink) Write(_ context.Context, name string, data []byte) error {
+	s.Lock()
+	defer s.Unlock()
+	s.data[name] = bytes.Clone(data)
+	return nil
+}
+
+// lastWriter keeps a copy of the last write only.
+type lastWriter struct {
+	data []byte
+}
+
+func (w *lastWriter) Write(data []byte) (int, error) {
+	w.data = bytes.Clone(data)
+	return len(data), nil
+}
+
+var memory = &memorySink{data: map[string][]byte{}}
+
+func init() {
+	Register("memory://", func(destination string) (ReportSink, string, error) {
+		return memory, destination, nil
+	})
+}
+
+func TestRegister(t *testing.T) {
+	factory := func(string) (ReportSink, string, error) { return memory, "", nil }
+	assert.Panics(t, func() { Register("memory", factory) })
+	assert.Panics(t, func() { Register("file://", factory) })
+	assert.Panics(t, func() { Register("", factory) })
+	assert.Panics(t, func() { Register("nil", nil) })
+	assert.Equal(t, []string{"-", "file", "http", "https", "memory", "s3"}, Schemes())
+}
+
+func TestResolveSink(t *testing.T) {
+	tests := []struct {
+		name        string
+		destination string
+		want        ReportSink
+		wantName    string
+		wantErr     bool
+	}{{
+		name:        "stdout",
+		destination: "-",
+		want:        writerSink{writer: stdout},
+		wantName:    "-",
+	}, {
+		name:        "file",
+		destination: "reports/report.json",
+		want:        fileSink{},
+		wantName:    "reports/report.json",
+	}, {
+		name:        "file scheme",
+		destination: "file:///tmp/report.json",
+		want:        fileSink{},
+		wantName:    "/tmp/report.json",
+	}, {
+		name:        "https",
+		destination: "https://example.com/reports",
+		want:        &HTTPReportSink{URL: "https://example.com/reports"},
+		wantName:    "https://example.com/reports",
+	}, {
+		name:        "custom",
+		destination: "memory://report",
+		want:        memory,
+		wantName:    "memory://report",
+	}, {
+		name:        "s3 without key",
+		destination: "s3://bucket",
+		wantErr:     true,
+	}, {
+		name:        "unknown",
+		destination: "ftp://example.com/report.json",
+		wantErr:     true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink, name, err := ResolveSink(tt.destination)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, sink)
+				assert.Equal(t, tt.wantName, name)
+			}
+		})
+	}
+}
+
+func TestResolveSink_ObjectStorage(t *testing.T) {
+	sink, name, err := ResolveSink("s3://bucket/reports/report.json")
+	assert.NoError(t, err)
+	assert.Equal(t, "reports/report.json", name)
+	assert.Equal(t, "bucket", sink.(*ObjectStorageSink).Bucket)
+}
+
+func TestIsFile(t *testing.T) {
+	assert.True(t, IsFile("report.json"))
+	assert.True(t, IsFile("file:///report.json"))
+	assert.False(t, IsFile("-"))
+	assert.False(t, IsFile("https://example.com"))
+}
+
+func TestSaveReportContext_CustomSink(t *testing.T) {
+	report := NewTests("suite")
+	assert.NoError(t, SaveReportContext(context.Background(), report, JSONSerializer{}, "memory://suite", SaveOptions{}))
+	want, err := JSONSerializer{}.Serialize(report)
+	assert.NoError(t, err)
+	memory.Lock()
+	defer memory.Unlock()
+	assert.Equal(t, want, memory.data["memory://suite"])
+}
+
+func TestSaveReportContext_HTTPContentType(t *testing.T) {
+	var contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+	}))
+	defer server.Close()
+	assert.NoError(t, SaveReportContext(context.Background(), NewTests("suite"), XMLSerializer{}, server.URL, SaveOptions{}))
+	assert.Equal(t, "application/xml", contentType)
+}
+
+// streamSink records the reports written through WriteStream apart from those written through Write.
+type streamSink struct {
+	memorySink
+	streamed map[string][]byte
+}
+
+func (s *streamSink) WriteStream(_ context.Context, name string, write func(io.Writer) error) error {
+	var buf bytes.Buffer
+	if err := write(&buf); err != nil {
+		return err
+	}
+	s.Lock()
+	defer s.Unlock()
+	s.streamed[name] = buf.Bytes()
+	return nil
+}
+
+func TestSaveReportContext_Streaming(t *testing.T) {
+	sink := &streamSink{memorySink: memorySink{data: map[string][]byte{}}, streamed: map[string][]byte{}}
+	Register("stream", func(destination string) (ReportSink, string, error) {
+		return sink, strings.TrimPrefix(destination, "stream://"), nil
+	})
+	report := NewTests("suite")
+	want, err := JSONSerializer{}.Serialize(report)
+	assert.NoError(t, err)
+	assert.NoError(t, SaveReportContext(context.Background(), report, JSONSerializer{}, "stream://report.json", SaveOptions{}))
+	assert.Equal(t, want, sink.streamed["report.json"])
+	// reports are compressed while they are streamed
+	assert.NoError(t, SaveReportContext(context.Background(), report, JSONSerializer{}, "stream://report.json", SaveOptions{Compress: true}))
+	gz, err := gzip.NewReader(bytes.NewReader(sink.streamed["report.json.gz"]))
+	assert.NoError(t, err)
+	data, err := io.ReadAll(gz)
+	assert.NoError(t, err)
+	assert.Equal(t, want, data)
+	assert.Empty(t, sink.data)
+}
+
+func TestSaveReportContext_HTTPSinkNotModified(t *testing.T) {
+	var contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+	}))
+	defer server.Close()
+	shared := &HTTPReportSink{URL: server.URL}
+	Register("shared-http", func(destination string) (ReportSink, string, error) {
+		return shared, destination, nil
+	})
+	assert.NoError(t, SaveReportContext(context.Background(), NewTests("suite"), XMLSerializer{}, "shared-http://report", SaveOptions{}))
+	assert.Equal(t, "application/xml", contentType)
+	assert.NoError(t, SaveReportContext(context.Background(), NewTests("suite"), JSONSerializer{}, "shared-http://report", SaveOptions{}))
+	assert.Equal(t, "application/json", contentType)
+	assert.Equal(t, "", shared.ContentType)
+}
+
+func TestSinkConformance(t *testing.T) {
+	t.Run("file", func(t *testing.T) {
+		dir := t.TempDir()
+		sinktest.Run(t, filepath.Join(dir, "nested", "report.json"), func(t *testing.T) (sinktest.Sink, sinktest.ReadFunc) {
+			return fileSink{}, func(t *testing.T, name string) []byte {
+				data, err := os.ReadFile(name)
+				assert.NoError(t, err)
+				return data
+			}
+		})
+	})
+	t.Run("writer", func(t *testing.T) {
+		sinktest.Run(t, StdoutName, func(t *testing.T) (sinktest.Sink, sinktest.ReadFunc) {
+			var writer lastWriter
+			return writerSink{writer: &writer}, func(t *testing.T, _ string) []byte {
+				return writer.data
+			}
+		})
+	})
+	t.Run("memory", func(t *testing.T) {
+		sinktest.Run(t, "report", func(t *testing.T) (sinktest.Sink, sinktest.ReadFunc) {
+			sink := &memorySink{data: map[string][]byte{}}
+			return sink, func(t *testing.T, name string) []byte {
+				return sink.data[name]
+			}
+		})
+	})
+	t.Run("http", func(t *testing.T) {
+		sinktest.Run(t, "report.json", func(t *testing.T) (sinktest.Sink, sinktest.ReadFunc) {
+			var last []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				data, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				last = data
+			}))
+			t.Cleanup(server.Close)
+			return &HTTPReportSink{URL: server.URL}, func(t *testing.T, _ string) []byte {
+				return last
+			}
+		})
+	})
+	t.Run("object storage", func(t *testing.T) {
+		sinktest.Run(t, "reports/report.json", func(t *testing.T) (sinktest.Sink, sinktest.ReadFunc) {
+			client := &fakeObjectStorageClient{}
+			return &ObjectStorageSink{Client: client, Bucket: "bucket", PartSize: minPartSize}, func(t *testing.T, name string) []byte {
+				return client.objects["bucket/"+name]
+			}
+		})
+	})
+}
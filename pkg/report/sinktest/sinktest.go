@@ -0,0 +1,52 @@
+// Package sinktest provides conformance tests for report sink implementations.
+package sinktest
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Sink is the interface under test, it matches report.ReportSink.
+type Sink interface {
+	Write(ctx context.Context, name string, data []byte) error
+}
+
+// ReadFunc returns the data stored under name by the sink under test.
+type ReadFunc func(t *testing.T, name string) []byte
+
+// Factory creates a fresh sink and the function used to read back what it wrote.
+type Factory func(t *testing.T) (Sink, ReadFunc)
+
+// Run runs the conformance tests against sinks created by factory.
+// name is used as the destination name, sinks ignoring names are expected to keep the last write.
+func Run(t *testing.T, name string, factory Factory) {
+	t.Helper()
+	t.Run("write", func(t *testing.T) {
+		sink, read := factory(t)
+		data := []byte(`{"name":"suite"}`)
+		assert.NoError(t, sink.Write(context.Background(), name, data))
+		assert.Equal(t, data, read(t, name))
+	})
+	t.Run("overwrite", func(t *testing.T) {
+		sink, read := factory(t)
+		assert.NoError(t, sink.Write(context.Background(), name, []byte("first")))
+		assert.NoError(t, sink.Write(context.Background(), name, []byte("second")))
+		assert.Equal(t, []byte("second"), read(t, name))
+	})
+	t.Run("large", func(t *testing.T) {
+		sink, read := factory(t)
+		data := bytes.Repeat([]byte("chainsaw"), 1024*1024)
+		assert.NoError(t, sink.Write(context.Background(), name, data))
+		assert.True(t, bytes.Equal(data, read(t, name)))
+	})
+	t.Run("does not retain data", func(t *testing.T) {
+		sink, read := factory(t)
+		data := []byte("original")
+		assert.NoError(t, sink.Write(context.Background(), name, data))
+		copy(data, "mutated!")
+		assert.Equal(t, []byte("original"), read(t, name))
+	})
+}
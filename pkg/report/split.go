@@ -0,0 +1,183 @@
+package report
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SplitIndex lists the parts of a report split into multiple files.
+type SplitIndex struct {
+	// Name of the test suite.
+	Name string `json:"name"`
+	// RunID identifies the run that produced the report.
+	RunID string `json:"runId,omitempty"`
+	// Parts lists the part files, relative to the index.
+	Parts []SplitPart `json:"parts"`
+}
+
+// SplitPart describes a single part of a split report.
+type SplitPart struct {
+	// File is the name of the part file.
+	File string `json:"file"`
+	// Tests counts the tests in the part.
+	Tests int `json:"tests"`
+	// Failures counts the failed tests in the part.
+	Failures int `json:"failures"`
+}
+
+// part returns a standalone report holding the given tests, with recomputed counts.
+func (tr *TestsReport) part(tests []*TestReport) *TestsReport {
+	part := &TestsReport{
+		Name:      tr.Name,
+		RunID:     tr.RunID,
+		Git:       tr.Git,
+		Version:   tr.Version,
+		TimeStamp: tr.TimeStamp,
+		Time:      tr.Time,
+		Reports:   tests,
+	}
+	if part.Reports == nil {
+		part.Reports = []*TestReport{}
+	}
+	part.aggregate()
+	return part
+}
+
+// Split splits the report into standalone reports serializing to at most maxSize bytes each.
+// A test is never split across parts, a test that doesn't fit alone gets a part of its own.
+func Split(report *TestsReport, serializer ReportSerializer, maxSize int) ([]*TestsReport, error) {
+	empty, err := serializer.Serialize(report.part(nil))
+	if err != nil {
+		return nil, err
+	}
+	overhead := len(empty)
+	// pack tests greedily using their standalone size, then check the actual size of every part
+	var groups [][]*TestReport
+	var current []*TestReport
+	size := overhead
+	for _, test := range report.Reports {
+		single, err := serializer.Serialize(report.part([]*TestReport{test}))
+		if err != nil {
+			return nil, err
+		}
+		contribution := len(single) - overhead
+		if len(current) != 0 && size+contribution > maxSize {
+			groups = append(groups, current)
+			current, size = nil, overhead
+		}
+		current = append(current, test)
+		size += contribution
+	}
+	if len(current) != 0 {
+		groups = append(groups, current)
+	}
+	var parts []*TestsReport
+	for len(groups) != 0 {
+		group := groups[0]
+		groups = groups[1:]
+		part := report.part(group)
+		data, err := serializer.Serialize(part)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) > maxSize && len(group) > 1 {
+			half := len(group) / 2
+			groups = append([][]*TestReport{group[:half], group[half:]}, groups...)
+			continue
+		}
+		parts = append(parts, part)
+	}
+	if len(parts) == 0 {
+		parts = append(parts, report.part(nil))
+	}
+	return parts, nil
+}
+
+// PartName returns the destination of the i-th part of a split report, e.g. chainsaw-report-1.xml.
+func PartName(destination string, i int) string {
+	base, ext := splitExt(destination)
+	return fmt.Sprintf("%s-%d%s", base, i, ext)
+}
+
+// IndexName returns the destination of the index of a split report, e.g. chainsaw-report.index.json.
+func IndexName(destination string) string {
+	base, _ := splitExt(destination)
+	return base + ".index.json"
+}
+
+// splitExt splits the extension of a destination, the .gz extension is kept along with the format extension.
+func splitExt(destination string) (string, string) {
+	base := strings.TrimSuffix(destination, ".gz")
+	ext := filepath.Ext(base)
+	return strings.TrimSuffix(base, ext), destination[len(base)-len(ext):]
+}
+
+func saveSplit(ctx context.Context, report *TestsReport, serializer ReportSerializer, destination string, options SaveOptions) error {
+	parts, err := Split(report, serializer, options.MaxSize)
+	if err != nil {
+		return err
+	}
+	index := SplitIndex{
+		Name:  report.Name,
+		RunID: report.RunID,
+	}
+	for i, part := range parts {
+		data, err := serializer.Serialize(part)
+		if err != nil {
+			return err
+		}
+		name := options.destination(PartName(destination, i+1))
+		if err := save(ctx, serializer, name, options, writeBytes(data)); err != nil {
+			return err
+		}
+		index.Parts = append(index.Parts, SplitPart{
+			File:     filepath.Base(name),
+			Tests:    len(part.Reports),
+			Failures: part.Failures,
+		})
+	}
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	return save(ctx, JSONSerializer{}, IndexName(destination), SaveOptions{}, writeBytes(data))
+}
+
+// LoadSplit loads the parts listed in a split report index and recombines them into a single report.
+func LoadSplit(indexPath string) (*TestsReport, error) {
+	data, err := os.ReadFile(indexPath)
+	if err != nil {
+		return nil, err
+	}
+	var index SplitIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse report index %s: %w", indexPath, err)
+	}
+	report := &TestsReport{
+		Name:    index.Name,
+		RunID:   index.RunID,
+		Version: FormatVersion,
+		Reports: []*TestReport{},
+	}
+	for i, file := range index.Parts {
+		part, err := Load(filepath.Join(filepath.Dir(indexPath), file.File))
+		if err != nil {
+			return nil, err
+		}
+		if len(part.Reports) != file.Tests {
+			return nil, fmt.Errorf("report part %s has %d tests, expected %d", file.File, len(part.Reports), file.Tests)
+		}
+		if i == 0 {
+			report.Git = part.Git
+			report.TimeStamp = part.TimeStamp
+			report.Time = part.Time
+		}
+		report.Reports = append(report.Reports, part.Reports...)
+	}
+	report.aggregate()
+	return report, nil
+}
@@ -0,0 +1,143 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplit(t *testing.T) {
+	report := syntheticReport(200)
+	report.Reports[3].NewFailure("failed")
+	for _, serializer := range []ReportSerializer{JSONSerializer{}, XMLSerializer{}} {
+		data, err := serializer.Serialize(report)
+		assert.NoError(t, err)
+		maxSize := len(data) / 5
+		parts, err := Split(report, serializer, maxSize)
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, len(parts), 5)
+		var tests []*TestReport
+		failures := 0
+		for _, part := range parts {
+			data, err := serializer.Serialize(part)
+			assert.NoError(t, err)
+			assert.LessOrEqual(t, len(data), maxSize)
+			assert.Equal(t, report.Name, part.Name)
+			assert.Equal(t, report.TimeStamp, part.TimeStamp)
+			// every synthetic test holds a single operation
+			assert.Equal(t, len(part.Reports), part.Test)
+			tests = append(tests, part.Reports...)
+			failures += part.Failures
+		}
+		assert.Equal(t, report.Reports, tests)
+		assert.Equal(t, 1, failures)
+	}
+}
+
+func TestSplit_OversizedTest(t *testing.T) {
+	report := syntheticReport(3)
+	report.Reports[1].NewFailure(strings.Repeat("x", 10000))
+	parts, err := Split(report, JSONSerializer{}, 5000)
+	assert.NoError(t, err)
+	assert.Len(t, parts, 3)
+	assert.Equal(t, []*TestReport{report.Reports[1]}, parts[1].Reports)
+}
+
+func TestSplit_Empty(t *testing.T) {
+	parts, err := Split(NewTests("suite"), JSONSerializer{}, 10)
+	assert.NoError(t, err)
+	assert.Len(t, parts, 1)
+	assert.Empty(t, parts[0].Reports)
+}
+
+func TestPartName(t *testing.T) {
+	tests := []struct {
+		destination string
+		wantPart    string
+		wantIndex   string
+	}{{
+		destination: "chainsaw-report.xml",
+		wantPart:    "chainsaw-report-2.xml",
+		wantIndex:   "chainsaw-report.index.json",
+	}, {
+		destination: "reports/chainsaw-report.json.gz",
+		wantPart:    "reports/chainsaw-report-2.json.gz",
+		wantIndex:   "reports/chainsaw-report.index.json",
+	}, {
+		destination: "chainsaw-report",
+		wantPart:    "chainsaw-report-2",
+		wantIndex:   "chainsaw-report.index.json",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.destination, func(t *testing.T) {
+			assert.Equal(t, tt.wantPart, PartName(tt.destination, 2))
+			assert.Equal(t, tt.wantIndex, IndexName(tt.destination))
+		})
+	}
+}
+
+func TestSaveReport_Split(t *testing.T) {
+	tests := []struct {
+		name       string
+		compress   bool
+		serializer ReportSerializer
+		ext        string
+	}{{
+		name:       "plain",
+		serializer: JSONSerializer{},
+		ext:        ".json",
+	}, {
+		name:       "compressed",
+		compress:   true,
+		serializer: JSONSerializer{},
+		ext:        ".json.gz",
+	}, {
+		name:       "xml",
+		serializer: XMLSerializer{},
+		ext:        ".xml",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			report := syntheticReport(100)
+			report.RunID = "run"
+			options := SaveOptions{MaxSize: 10000, Compress: tt.compress}
+			assert.NoError(t, SaveReportWithOptions(report, tt.serializer, filepath.Join(dir, "chainsaw-report"+strings.TrimSuffix(tt.ext, ".gz")), options))
+			_, err := os.Stat(filepath.Join(dir, "chainsaw-report"+tt.ext))
+			assert.True(t, os.IsNotExist(err))
+			_, err = os.Stat(filepath.Join(dir, "chainsaw-report-1"+tt.ext))
+			assert.NoError(t, err)
+			loaded, err := LoadSplit(filepath.Join(dir, "chainsaw-report.index.json"))
+			assert.NoError(t, err)
+			assert.Equal(t, report.Name, loaded.Name)
+			assert.Equal(t, report.RunID, loaded.RunID)
+			assert.Equal(t, report.Time, loaded.Time)
+			assert.Equal(t, report.Test, loaded.Test)
+			assert.Len(t, loaded.Reports, len(report.Reports))
+			for i := range report.Reports {
+				assert.Equal(t, report.Reports[i].Name, loaded.Reports[i].Name)
+			}
+		})
+	}
+}
+
+func TestSaveReport_NoSplitBelowMaxSize(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, SaveReportWithOptions(syntheticReport(2), JSONSerializer{}, filepath.Join(dir, "report.json"), SaveOptions{MaxSize: 1 << 20}))
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "report.json", entries[0].Name())
+}
+
+func TestLoadSplit_MissingTests(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, SaveReport(syntheticReport(1), JSONSerializer{}, filepath.Join(dir, "part.json")))
+	index := filepath.Join(dir, "report.index.json")
+	assert.NoError(t, os.WriteFile(index, []byte(`{"name":"suite","parts":[{"file":"part.json","tests":2}]}`), 0o600))
+	_, err := LoadSplit(index)
+	assert.ErrorContains(t, err, "expected 2")
+}
@@ -0,0 +1,320 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+	"golang.org/x/term"
+)
+
+const (
+	defaultSummarySlowest = 5
+	defaultSummaryWidth   = 120
+	// minSummaryColumn is the narrowest a truncated column can get.
+	minSummaryColumn = 8
+)
+
+// SummaryOptions configures the summary printed by PrintSummary.
+type SummaryOptions struct {
+	// Slowest is the number of slowest tests listed, defaults to 5. Negative values disable the list.
+	Slowest int
+	// Width is the maximum width of a line, longer cells are truncated.
+	// It defaults to the terminal width, or 120 when the writer is not a terminal.
+	Width int
+	// Passed colors the passed tests count, defaults to bold green.
+	Passed *color.Color
+	// Failed colors the failed tests, defaults to bold red.
+	Failed *color.Color
+	// Warning colors the quarantined failures and the skipped tests count, defaults to bold yellow.
+	Warning *color.Color
+}
+
+// summaryTest is the summary row of a test, read under the test lock.
+type summaryTest struct {
+	name        string
+	step        string
+	duration    time.Duration
+	message     string
+	failed      bool
+	skipped     bool
+	quarantined bool
+	iteration   int
+}
+
+// PrintSummary renders the outcome of a run: a table of the failed tests, the slowest tests and a totals line.
+// Failures of quarantined tests are listed apart, as well as quarantined tests that passed.
+// Output is colored when w is a terminal and colors are not disabled.
+func PrintSummary(w io.Writer, report *TestsReport, opts SummaryOptions) error {
+	width, terminal := terminalWidth(w)
+	if opts.Width <= 0 {
+		opts.Width = width
+	}
+	return writeSummary(w, report, opts, terminal && !color.NoColor)
+}
+
+// terminalWidth returns the width of the terminal w writes to, if any.
+func terminalWidth(w io.Writer) (int, bool) {
+	f, ok := w.(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return defaultSummaryWidth, false
+	}
+	width, _, err := term.GetSize(int(f.Fd()))
+	if err != nil || width <= 0 {
+		return defaultSummaryWidth, true
+	}
+	return width, true
+}
+
+func writeSummary(w io.Writer, report *TestsReport, opts SummaryOptions, colorize bool) error {
+	// colors are copied, enabling or disabling them must not change those of the options
+	paint := func(c *color.Color, attributes ...color.Attribute) *color.Color {
+		if c == nil {
+			c = color.New(attributes...)
+		} else {
+			copied := *c
+			c = &copied
+		}
+		if colorize {
+			c.EnableColor()
+		} else {
+			c.DisableColor()
+		}
+		return c
+	}
+	red := paint(opts.Failed, color.FgRed, color.Bold)
+	green := paint(opts.Passed, color.FgGreen, color.Bold)
+	yellow := paint(opts.Warning, color.FgYellow, color.Bold)
+	bold := paint(nil, color.Bold)
+	if opts.Width <= 0 {
+		opts.Width = defaultSummaryWidth
+	}
+	slowest := opts.Slowest
+	if slowest == 0 {
+		slowest = defaultSummarySlowest
+	}
+	tests, wallTime := summaryTests(report)
+	var failed, quarantinedFailed, quarantinedPassed, skipped []summaryTest
+	for _, test := range tests {
+		switch {
+		case test.failed && test.quarantined:
+			quarantinedFailed = append(quarantinedFailed, test)
+		case test.failed:
+			failed = append(failed, test)
+		case test.skipped:
+			skipped = append(skipped, test)
+		case test.quarantined:
+			quarantinedPassed = append(quarantinedPassed, test)
+		}
+	}
+	var out strings.Builder
+	failures := func(title string, tests []summaryTest, c *color.Color) {
+		fmt.Fprintln(&out, c.Sprint(title))
+		rows := [][]string{{"TEST", "STEP", "DURATION", "MESSAGE"}}
+		for _, test := range tests {
+			rows = append(rows, []string{test.name, test.step, formatSummaryDuration(test.duration), test.message})
+		}
+		writeTable(&out, rows, opts.Width, bold, c)
+	}
+	if len(failed) != 0 {
+		failures("Failed tests:", failed, red)
+	}
+	if len(quarantinedFailed) != 0 {
+		failures("Quarantined failures:", quarantinedFailed, yellow)
+	}
+	// quarantined tests that passed may not be flaky anymore
+	if len(quarantinedPassed) != 0 {
+		fmt.Fprintln(&out, bold.Sprint("Quarantined tests that passed:"))
+		rows := [][]string{{"TEST"}}
+		for _, test := range quarantinedPassed {
+			rows = append(rows, []string{test.name})
+		}
+		writeTable(&out, rows, opts.Width, bold, nil)
+	}
+	if slowest > 0 {
+		ran := make([]summaryTest, 0, len(tests))
+		for _, test := range tests {
+			if !test.skipped {
+				ran = append(ran, test)
+			}
+		}
+		sort.SliceStable(ran, func(i, j int) bool { return ran[i].duration > ran[j].duration })
+		if len(ran) > slowest {
+			ran = ran[:slowest]
+		}
+		if len(ran) != 0 {
+			fmt.Fprintln(&out, bold.Sprint("Slowest tests:"))
+			rows := [][]string{{"TEST", "DURATION"}}
+			for _, test := range ran {
+				rows = append(rows, []string{test.name, formatSummaryDuration(test.duration)})
+			}
+			writeTable(&out, rows, opts.Width, bold, nil)
+		}
+	}
+	// flaky tests run many times are rolled up by test
+	if rows := iterationRows(tests); len(rows) != 0 {
+		fmt.Fprintln(&out, bold.Sprint("Iterations:"))
+		writeTable(&out, append([][]string{{"TEST", "PASSED", "PASS RATE"}}, rows...), opts.Width, bold, nil)
+	}
+	failedCount := fmt.Sprintf("%d failed", len(failed))
+	if len(failed) != 0 {
+		failedCount = red.Sprint(failedCount)
+	}
+	passed := len(tests) - len(failed) - len(quarantinedFailed) - len(skipped)
+	fmt.Fprintf(&out, "Tests: %s, %s", green.Sprintf("%d passed", passed), failedCount)
+	if len(quarantinedFailed) != 0 {
+		fmt.Fprintf(&out, ", %s", yellow.Sprintf("%d quarantined", len(quarantinedFailed)))
+	}
+	fmt.Fprintf(&out, ", %s", yellow.Sprintf("%d skipped", len(skipped)))
+	if wallTime > 0 {
+		fmt.Fprintf(&out, " in %s", formatSummaryDuration(wallTime))
+	}
+	fmt.Fprintln(&out)
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// iterationRows returns a row per test run more than once with the number of passed iterations and the pass rate,
+// skipped iterations are left out.
+func iterationRows(tests []summaryTest) [][]string {
+	type iterations struct {
+		passed, ran int
+	}
+	var names []string
+	byName := map[string]*iterations{}
+	for _, test := range tests {
+		if test.iteration == 0 {
+			continue
+		}
+		name := strings.TrimSuffix(test.name, fmt.Sprintf("#%d", test.iteration))
+		results := byName[name]
+		if results == nil {
+			results = &iterations{}
+			byName[name] = results
+			names = append(names, name)
+		}
+		if test.skipped {
+			continue
+		}
+		results.ran++
+		if !test.failed {
+			results.passed++
+		}
+	}
+	var rows [][]string
+	for _, name := range names {
+		results := byName[name]
+		rate := "-"
+		if results.ran != 0 {
+			rate = fmt.Sprintf("%d%%", results.passed*100/results.ran)
+		}
+		rows = append(rows, []string{name, fmt.Sprintf("%d/%d", results.passed, results.ran), rate})
+	}
+	return rows
+}
+
+// summaryTests reads the summary rows of the report tests and the wall time of the run.
+func summaryTests(report *TestsReport) ([]summaryTest, time.Duration) {
+	report.lock.Lock()
+	defer report.lock.Unlock()
+	wallTime, _ := parseDuration(report.Time)
+	tests := make([]summaryTest, 0, len(report.Reports))
+	for _, test := range report.Reports {
+		tests = append(tests, test.summary())
+	}
+	return tests, wallTime
+}
+
+func (t *TestReport) summary() summaryTest {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	duration, _ := parseDuration(t.Time)
+	summary := summaryTest{
+		name:        t.Name,
+		duration:    duration,
+		skipped:     t.Skip,
+		quarantined: t.Quarantined,
+		iteration:   t.Iteration,
+	}
+	if t.Failure != nil {
+		summary.failed = true
+		summary.message = firstLine(t.Failure.Message)
+		summary.step = t.failedStep()
+	}
+	return summary
+}
+
+// failedStep returns the name of the first step holding a failed operation, the caller owns the test lock.
+func (t *TestReport) failedStep() string {
+	for _, step := range t.Steps {
+		step.lock.Lock()
+		failed := false
+		for _, op := range step.Results {
+			op.lock.Lock()
+			failed = failed || op.Result == "Failure"
+			op.lock.Unlock()
+		}
+		name := step.Name
+		step.lock.Unlock()
+		if failed {
+			return name
+		}
+	}
+	return ""
+}
+
+// writeTable writes aligned rows, the first one being the header. Columns are truncated so that lines fit in width,
+// the last column takes the remaining space.
+func writeTable(out *strings.Builder, rows [][]string, width int, header, first *color.Color) {
+	const indent, gap = "  ", "  "
+	columns := len(rows[0])
+	widths := make([]int, columns)
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+	// leading columns get at most half of the remaining space, the last one fits in what remains
+	available := width - len(indent) - (columns-1)*len(gap)
+	for i := 0; i < columns-1; i++ {
+		widths[i] = min(widths[i], max(minSummaryColumn, available/2))
+		available -= widths[i]
+	}
+	widths[columns-1] = min(widths[columns-1], max(minSummaryColumn, available))
+	for r, row := range rows {
+		out.WriteString(indent)
+		for i, cell := range row {
+			cell = truncate(cell, widths[i])
+			padded := cell
+			if i != columns-1 {
+				padded += strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)) + gap
+			}
+			switch {
+			case r == 0:
+				padded = header.Sprint(padded)
+			case i == 0 && first != nil:
+				padded = first.Sprint(padded)
+			}
+			out.WriteString(padded)
+		}
+		out.WriteString("\n")
+	}
+}
+
+// truncate shortens s to at most n characters, marking the cut with an ellipsis.
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:n-1]) + "…"
+}
+
+func formatSummaryDuration(d time.Duration) string {
+	return d.Round(time.Millisecond).String()
+}
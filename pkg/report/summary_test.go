@@ -0,0 +1,169 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+)
+
+func summaryReport() *TestsReport {
+	return &TestsReport{
+		Name: "suite",
+		Time: "75.300",
+		Reports: []*TestReport{{
+			Name: "quick",
+			Time: "1.200",
+		}, {
+			Name: "slow",
+			Time: "40.000",
+		}, {
+			Name: "broken",
+			Time: "12.500",
+			Steps: []*TestSpecStepReport{{
+				Name:    "setup",
+				Results: []*OperationReport{{Name: "apply", Result: "Success"}},
+			}, {
+				Name:    "check",
+				Results: []*OperationReport{{Name: "assert", Result: "Failure"}},
+			}},
+			Failure: &Failure{Message: "resource not found\ndetails"},
+		}, {
+			Name: "ignored",
+			Skip: true,
+		}},
+	}
+}
+
+func TestPrintSummary(t *testing.T) {
+	var out bytes.Buffer
+	assert.NoError(t, PrintSummary(&out, summaryReport(), SummaryOptions{Slowest: 2}))
+	expected := `Failed tests:
+  TEST    STEP   DURATION  MESSAGE
+  broken  check  12.5s     resource not found
+Slowest tests:
+  TEST    DURATION
+  slow    40s
+  broken  12.5s
+Tests: 2 passed, 1 failed, 1 skipped in 1m15.3s
+`
+	assert.Equal(t, expected, out.String())
+}
+
+func TestPrintSummary_Options(t *testing.T) {
+	tests := []struct {
+		name   string
+		report *TestsReport
+		opts   SummaryOptions
+		want   string
+	}{{
+		name:   "empty",
+		report: &TestsReport{Name: "suite"},
+		want:   "Tests: 0 passed, 0 failed, 0 skipped\n",
+	}, {
+		name: "no slowest tests",
+		report: &TestsReport{
+			Name:    "suite",
+			Time:    "1.000",
+			Reports: []*TestReport{{Name: "quick", Time: "1.000"}},
+		},
+		opts: SummaryOptions{Slowest: -1},
+		want: "Tests: 1 passed, 0 failed, 0 skipped in 1s\n",
+	}, {
+		name: "long names are truncated",
+		report: &TestsReport{
+			Name: "suite",
+			Reports: []*TestReport{{
+				Name:    "a-test-with-a-very-long-name-that-does-not-fit",
+				Time:    "2.000",
+				Failure: &Failure{Message: "a message that is way too long to fit on the line"},
+			}},
+		},
+		opts: SummaryOptions{Slowest: -1, Width: 50},
+		want: `Failed tests:
+  TEST                   STEP  DURATION  MESSAGE
+  a-test-with-a-very-l…        2s        a messag…
+Tests: 0 passed, 1 failed, 0 skipped
+`,
+	}, {
+		name: "quarantined tests",
+		report: &TestsReport{
+			Name: "suite",
+			Reports: []*TestReport{
+				{Name: "flaky", Time: "3.000", Quarantined: true, Failure: &Failure{Message: "timed out"}},
+				{Name: "fixed", Time: "1.000", Quarantined: true},
+				{Name: "quick", Time: "1.000"},
+			},
+		},
+		opts: SummaryOptions{Slowest: -1},
+		want: `Quarantined failures:
+  TEST   STEP  DURATION  MESSAGE
+  flaky        3s        timed out
+Quarantined tests that passed:
+  TEST
+  fixed
+Tests: 2 passed, 0 failed, 1 quarantined, 0 skipped
+`,
+	}, {
+		name: "iterations",
+		report: &TestsReport{
+			Name: "suite",
+			Reports: []*TestReport{
+				{Name: "flaky#1", Time: "1.000", Iteration: 1},
+				{Name: "flaky#2", Time: "1.000", Iteration: 2, Failure: &Failure{Message: "timed out"}},
+				{Name: "flaky#3", Time: "1.000", Iteration: 3},
+				{Name: "flaky#4", Iteration: 4, Skip: true},
+				{Name: "quick", Time: "1.000"},
+			},
+		},
+		opts: SummaryOptions{Slowest: -1},
+		want: `Failed tests:
+  TEST     STEP  DURATION  MESSAGE
+  flaky#2        1s        timed out
+Iterations:
+  TEST   PASSED  PASS RATE
+  flaky  2/3     66%
+Tests: 3 passed, 1 failed, 1 skipped
+`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			assert.NoError(t, PrintSummary(&out, tt.report, tt.opts))
+			assert.Equal(t, tt.want, out.String())
+			for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n") {
+				if tt.opts.Width > 0 {
+					assert.LessOrEqual(t, len([]rune(line)), tt.opts.Width)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteSummary_Color(t *testing.T) {
+	var plain, colored bytes.Buffer
+	assert.NoError(t, writeSummary(&plain, summaryReport(), SummaryOptions{}, false))
+	assert.NoError(t, writeSummary(&colored, summaryReport(), SummaryOptions{}, true))
+	assert.NotContains(t, plain.String(), "\x1b[")
+	assert.Contains(t, colored.String(), "\x1b[")
+	assert.Equal(t, plain.String(), StripANSI(colored.String()))
+}
+
+func TestWriteSummary_Colors(t *testing.T) {
+	var out bytes.Buffer
+	opts := SummaryOptions{Passed: color.New(color.FgCyan), Failed: color.New(color.FgMagenta)}
+	assert.NoError(t, writeSummary(&out, summaryReport(), opts, true))
+	assert.Contains(t, out.String(), "\x1b[36m")
+	assert.Contains(t, out.String(), "\x1b[35mFailed tests:")
+	// the colors of the options are left untouched
+	opts.Passed.DisableColor()
+	assert.NoError(t, writeSummary(&out, summaryReport(), opts, true))
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "short", truncate("short", 5))
+	assert.Equal(t, "shor…", truncate("shorter", 5))
+	assert.Equal(t, "ééé…", truncate("ééééé", 4))
+}
@@ -0,0 +1,219 @@
+package report
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SpanStatus is the outcome of a traced unit of work.
+type SpanStatus string
+
+const (
+	SpanStatusUnset SpanStatus = ""
+	SpanStatusOk    SpanStatus = "ok"
+	SpanStatusError SpanStatus = "error"
+)
+
+// SpanEvent is a timestamped annotation of a span.
+type SpanEvent struct {
+	// Name of the event.
+	Name string
+	// Time is when the event happened.
+	Time time.Time
+	// Attributes describe the event.
+	Attributes map[string]string
+}
+
+// Span is a unit of work in a trace built from a report, spans are exporter agnostic.
+type Span struct {
+	// Name of the span.
+	Name string
+	// Start and End are the real start and end times recorded in the report.
+	Start time.Time
+	End   time.Time
+	// Status is the outcome of the span, Message explains failures.
+	Status  SpanStatus
+	Message string
+	// Attributes describe the span.
+	Attributes map[string]string
+	// Events annotate the span.
+	Events []SpanEvent
+	// Children are the spans nested below this span.
+	Children []*Span
+}
+
+// TraceExporter sends a trace built from a report to a tracing backend.
+type TraceExporter interface {
+	Export(ctx context.Context, root *Span) error
+}
+
+// ExportTrace converts the report into a trace and sends it with the exporter.
+func ExportTrace(ctx context.Context, report *TestsReport, exporter TraceExporter) error {
+	return exporter.Export(ctx, Trace(report))
+}
+
+// Trace converts a report into a tree of spans: the suite is the root, tests are its children, steps and operations are nested below.
+func Trace(report *TestsReport) *Span {
+	report.lock.Lock()
+	defer report.lock.Unlock()
+	root := &Span{
+		Name:  report.Name,
+		Start: report.TimeStamp,
+		End:   spanEnd(report.TimeStamp, report.Time),
+		Attributes: map[string]string{
+			"chainsaw.suite":    report.Name,
+			"chainsaw.run_id":   report.RunID,
+			"chainsaw.tests":    strconv.Itoa(report.Test),
+			"chainsaw.failures": strconv.Itoa(report.Failures),
+		},
+		Status: SpanStatusOk,
+	}
+	if report.Failures > 0 {
+		root.Status = SpanStatusError
+	}
+	if report.Git != nil {
+		root.Attributes["chainsaw.git.commit"] = report.Git.Commit
+	}
+	if report.Parallel != 0 {
+		root.Attributes["chainsaw.parallel"] = strconv.Itoa(report.Parallel)
+	}
+	for _, test := range report.Reports {
+		root.Children = append(root.Children, test.span())
+	}
+	return root
+}
+
+func (t *TestReport) span() *Span {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	span := &Span{
+		Name:  t.Name,
+		Start: t.TimeStamp,
+		End:   spanEnd(t.TimeStamp, t.Time),
+		Attributes: map[string]string{
+			"chainsaw.test": t.Name,
+		},
+		Status: SpanStatusOk,
+	}
+	if t.Namespace != "" {
+		span.Attributes["chainsaw.namespace"] = t.Namespace
+	}
+	if t.Iteration != 0 {
+		span.Attributes["chainsaw.iteration"] = strconv.Itoa(t.Iteration)
+	}
+	if t.Skip {
+		span.Attributes["chainsaw.skipped"] = "true"
+		if t.SkipReason != "" {
+			span.Attributes["chainsaw.skip_reason"] = t.SkipReason
+		}
+		span.Status = SpanStatusUnset
+	}
+	if t.Failure != nil {
+		span.Status = SpanStatusError
+		span.Message = t.Failure.Message
+		span.Events = append(span.Events, SpanEvent{
+			Name:       "failure",
+			Time:       span.End,
+			Attributes: map[string]string{"message": t.Failure.Message},
+		})
+	}
+	for _, warning := range t.Warnings {
+		span.Events = append(span.Events, SpanEvent{
+			Name:       "warning",
+			Time:       span.End,
+			Attributes: map[string]string{"type": string(warning.Type), "message": warning.Message},
+		})
+	}
+	for _, step := range t.Steps {
+		span.Children = append(span.Children, step.span(span.Start))
+	}
+	return span
+}
+
+// span returns the step span, steps don't record timings and span their operations, empty steps start with the test.
+func (ts *TestSpecStepReport) span(testStart time.Time) *Span {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+	span := &Span{
+		Name:       ts.Name,
+		Start:      testStart,
+		End:        testStart,
+		Attributes: map[string]string{"chainsaw.step": ts.Name},
+		Status:     SpanStatusOk,
+	}
+	if ts.Skip {
+		span.Attributes["chainsaw.step.skip_reason"] = ts.SkipReason
+	}
+	for i, op := range ts.Results {
+		child := op.span()
+		if i == 0 || child.Start.Before(span.Start) {
+			span.Start = child.Start
+		}
+		if i == 0 || child.End.After(span.End) {
+			span.End = child.End
+		}
+		if child.Status == SpanStatusError {
+			span.Status = SpanStatusError
+			span.Message = child.Message
+		}
+		span.Children = append(span.Children, child)
+	}
+	return span
+}
+
+func (op *OperationReport) span() *Span {
+	op.lock.Lock()
+	defer op.lock.Unlock()
+	span := &Span{
+		Name:  op.Name,
+		Start: op.TimeStamp,
+		End:   spanEnd(op.TimeStamp, op.Time),
+		Attributes: map[string]string{
+			"chainsaw.operation.type":   string(op.OperationType),
+			"chainsaw.operation.result": op.Result,
+		},
+		Status: SpanStatusOk,
+	}
+	if op.Attempts != 0 {
+		span.Attributes["chainsaw.operation.attempts"] = strconv.Itoa(op.Attempts)
+	}
+	if op.Timeout != "" {
+		span.Attributes["chainsaw.operation.timeout"] = op.Timeout
+	}
+	if op.Result == "Failure" {
+		span.Status = SpanStatusError
+		span.Message = op.Message
+	}
+	return span
+}
+
+// spanEnd returns the end of a span given its start and the duration recorded in the report.
+func spanEnd(start time.Time, duration string) time.Time {
+	d, err := parseDuration(duration)
+	if err != nil {
+		return start
+	}
+	return start.Add(d)
+}
+
+// MemoryTraceExporter keeps exported traces in memory, it is meant for tests.
+type MemoryTraceExporter struct {
+	lock   sync.Mutex
+	traces []*Span
+}
+
+func (e *MemoryTraceExporter) Export(_ context.Context, root *Span) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.traces = append(e.traces, root)
+	return nil
+}
+
+// Traces returns the exported traces.
+func (e *MemoryTraceExporter) Traces() []*Span {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return append([]*Span{}, e.traces...)
+}
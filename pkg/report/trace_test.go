@@ -0,0 +1,136 @@
+package report
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func traceReport() *TestsReport {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	report := &TestsReport{
+		Name:      "suite",
+		RunID:     "run",
+		TimeStamp: start,
+		Time:      "60.000",
+		Reports: []*TestReport{{
+			Name:      "passing",
+			TimeStamp: start.Add(time.Second),
+			Time:      "10.000",
+			Namespace: "chainsaw-ns",
+			Warnings:  []Warning{{Type: WarningTypeSlowCleanup, Message: "slow"}},
+			Steps: []*TestSpecStepReport{{
+				Name: "step-1",
+				Results: []*OperationReport{{
+					Name:          "apply",
+					OperationType: OperationTypeApply,
+					TimeStamp:     start.Add(2 * time.Second),
+					Time:          "1.500",
+					Result:        "Success",
+				}, {
+					Name:          "assert",
+					OperationType: OperationTypeAssert,
+					TimeStamp:     start.Add(4 * time.Second),
+					Time:          "2.000",
+					Timeout:       "30.000",
+					Result:        "Success",
+					Attempts:      3,
+				}},
+			}, {
+				Name: "empty",
+			}},
+		}, {
+			Name:      "failing",
+			TimeStamp: start.Add(20 * time.Second),
+			Time:      "5.000",
+			Failure:   &Failure{Message: "test failed"},
+			Steps: []*TestSpecStepReport{{
+				Name: "step-1",
+				Results: []*OperationReport{{
+					Name:          "script",
+					OperationType: OperationTypeScript,
+					TimeStamp:     start.Add(21 * time.Second),
+					Time:          "1.000",
+					Result:        "Failure",
+					Message:       "exit status 1",
+				}},
+			}},
+		}},
+	}
+	report.aggregate()
+	return report
+}
+
+func TestTrace(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	root := Trace(traceReport())
+	assert.Equal(t, "suite", root.Name)
+	assert.Equal(t, start, root.Start)
+	assert.Equal(t, start.Add(time.Minute), root.End)
+	assert.Equal(t, SpanStatusError, root.Status)
+	assert.Equal(t, "run", root.Attributes["chainsaw.run_id"])
+	assert.Equal(t, "1", root.Attributes["chainsaw.failures"])
+	assert.Len(t, root.Children, 2)
+	// passing test
+	passing := root.Children[0]
+	assert.Equal(t, SpanStatusOk, passing.Status)
+	assert.Equal(t, start.Add(time.Second), passing.Start)
+	assert.Equal(t, start.Add(11*time.Second), passing.End)
+	assert.Equal(t, "chainsaw-ns", passing.Attributes["chainsaw.namespace"])
+	assert.Equal(t, []SpanEvent{{Name: "warning", Time: passing.End, Attributes: map[string]string{"type": "slowCleanup", "message": "slow"}}}, passing.Events)
+	assert.Len(t, passing.Children, 2)
+	step := passing.Children[0]
+	assert.Equal(t, start.Add(2*time.Second), step.Start)
+	assert.Equal(t, start.Add(6*time.Second), step.End)
+	assert.Len(t, step.Children, 2)
+	assert.Equal(t, start.Add(3500*time.Millisecond), step.Children[0].End)
+	assert.Equal(t, "apply", step.Children[0].Attributes["chainsaw.operation.type"])
+	assert.Equal(t, "3", step.Children[1].Attributes["chainsaw.operation.attempts"])
+	assert.Equal(t, "30.000", step.Children[1].Attributes["chainsaw.operation.timeout"])
+	assert.NotContains(t, step.Children[0].Attributes, "chainsaw.operation.timeout")
+	empty := passing.Children[1]
+	assert.Equal(t, passing.Start, empty.Start)
+	assert.Equal(t, passing.Start, empty.End)
+	assert.Empty(t, empty.Children)
+	// failing test
+	failing := root.Children[1]
+	assert.Equal(t, SpanStatusError, failing.Status)
+	assert.Equal(t, "test failed", failing.Message)
+	assert.Equal(t, "failure", failing.Events[0].Name)
+	assert.Equal(t, SpanStatusError, failing.Children[0].Status)
+	assert.Equal(t, "exit status 1", failing.Children[0].Message)
+	assert.Equal(t, "exit status 1", failing.Children[0].Children[0].Message)
+}
+
+func TestTrace_Attributes(t *testing.T) {
+	report := NewTests("suite")
+	report.Parallel = 4
+	skipped := NewTest("skipped")
+	skipped.MarkSkipped(SkipReasonFailFast)
+	skipped.Iteration = 2
+	report.AddTest(skipped)
+	root := Trace(report)
+	assert.Equal(t, "4", root.Attributes["chainsaw.parallel"])
+	assert.Len(t, root.Children, 1)
+	assert.Equal(t, SpanStatusUnset, root.Children[0].Status)
+	assert.Equal(t, "true", root.Children[0].Attributes["chainsaw.skipped"])
+	assert.Equal(t, SkipReasonFailFast, root.Children[0].Attributes["chainsaw.skip_reason"])
+	assert.Equal(t, "2", root.Children[0].Attributes["chainsaw.iteration"])
+	// skipped steps
+	step := NewTestSpecStep("step")
+	step.MarkSkipped("skipIf: $values.skip")
+	assert.Equal(t, "skipIf: $values.skip", step.span(time.Now()).Attributes["chainsaw.step.skip_reason"])
+	assert.NotContains(t, NewTestSpecStep("step").span(time.Now()).Attributes, "chainsaw.step.skip_reason")
+}
+
+func TestExportTrace(t *testing.T) {
+	exporter := &MemoryTraceExporter{}
+	assert.NoError(t, ExportTrace(context.Background(), traceReport(), exporter))
+	assert.NoError(t, ExportTrace(context.Background(), syntheticReport(1), exporter))
+	traces := exporter.Traces()
+	assert.Len(t, traces, 2)
+	assert.Equal(t, "suite", traces[0].Name)
+	assert.Equal(t, SpanStatusOk, traces[1].Status)
+}
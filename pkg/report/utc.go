@@ -0,0 +1,35 @@
+package report
+
+import "time"
+
+// InUTC returns a copy of the report with all its timestamps in UTC, the report is left untouched.
+// Serialized reports then don't depend on the time zone of the host that produced them, see SaveOptions.
+func (tr *TestsReport) InUTC() *TestsReport {
+	out := tr.DeepCopy()
+	out.TimeStamp = out.TimeStamp.UTC()
+	walkGroups(out.Groups, func(group *TestsGroup) {
+		group.TimeStamp = group.TimeStamp.UTC()
+	})
+	for _, test := range out.Reports {
+		test.TimeStamp = test.TimeStamp.UTC()
+		for i := range test.Logs {
+			test.Logs[i].Time = test.Logs[i].Time.UTC()
+		}
+		for _, step := range test.Steps {
+			for _, op := range step.Results {
+				op.TimeStamp = op.TimeStamp.UTC()
+				op.FirstAttemptAt = utcPointer(op.FirstAttemptAt)
+				op.LastAttemptAt = utcPointer(op.LastAttemptAt)
+			}
+		}
+	}
+	return out
+}
+
+func utcPointer(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
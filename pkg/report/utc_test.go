@@ -0,0 +1,93 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func utcReport(zone *time.Location) *TestsReport {
+	start := time.Date(2024, 3, 1, 10, 30, 0, 0, zone)
+	first, last := start.Add(time.Second), start.Add(2*time.Second)
+	return &TestsReport{
+		Name:      "suite",
+		TimeStamp: start,
+		Reports: []*TestReport{{
+			Name:      "test",
+			TimeStamp: start,
+			Logs:      Logs{{Time: start, Message: "line"}},
+			Steps: []*TestSpecStepReport{{
+				Name:  "step",
+				Index: 1,
+				Results: []*OperationReport{{
+					Name:           "assert",
+					Phase:          OperationPhaseTry,
+					Index:          2,
+					TimeStamp:      start,
+					FirstAttemptAt: &first,
+					LastAttemptAt:  &last,
+				}},
+			}},
+		}},
+	}
+}
+
+func TestTestsReport_InUTC(t *testing.T) {
+	cet := time.FixedZone("CET", 60*60)
+	report := utcReport(cet)
+	utc := report.InUTC()
+	assert.Equal(t, utcReport(time.UTC).TimeStamp.Add(-time.Hour), utc.TimeStamp)
+	assert.Equal(t, time.UTC, utc.TimeStamp.Location())
+	test := utc.Reports[0]
+	assert.Equal(t, time.UTC, test.TimeStamp.Location())
+	assert.Equal(t, time.UTC, test.Logs[0].Time.Location())
+	op := test.Steps[0].Results[0]
+	assert.Equal(t, time.UTC, op.TimeStamp.Location())
+	assert.Equal(t, time.UTC, op.FirstAttemptAt.Location())
+	assert.Equal(t, time.UTC, op.LastAttemptAt.Location())
+	assert.True(t, op.LastAttemptAt.Equal(*report.Reports[0].Steps[0].Results[0].LastAttemptAt))
+	// the ordinals are copied
+	assert.Equal(t, 1, test.Steps[0].Index)
+	assert.Equal(t, OperationPhaseTry, op.Phase)
+	assert.Equal(t, 2, op.Index)
+	// the report is left untouched
+	assert.Equal(t, cet, report.TimeStamp.Location())
+	assert.Equal(t, cet, report.Reports[0].Logs[0].Time.Location())
+	assert.Equal(t, cet, report.Reports[0].Steps[0].Results[0].FirstAttemptAt.Location())
+}
+
+func TestSaveReportWithOptions_UTC(t *testing.T) {
+	cet := time.FixedZone("CET", 60*60)
+	tests := []struct {
+		name string
+		utc  bool
+		want string
+	}{{
+		name: "local",
+		want: `"timestamp": "2024-03-01T10:30:00+01:00"`,
+	}, {
+		name: "utc",
+		utc:  true,
+		want: `"timestamp": "2024-03-01T09:30:00Z"`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "report.json")
+			assert.NoError(t, SaveReportWithOptions(utcReport(cet), JSONSerializer{}, path, SaveOptions{UTC: tt.utc}))
+			data, err := os.ReadFile(path)
+			assert.NoError(t, err)
+			assert.Contains(t, string(data), tt.want)
+			if tt.utc {
+				assert.NotContains(t, string(data), "+01:00")
+			}
+		})
+	}
+	// XML timestamps are normalized too
+	var out strings.Builder
+	assert.NoError(t, SaveReportTo(utcReport(cet).InUTC(), XMLSerializer{}, &out))
+	assert.Contains(t, out.String(), `timestamp="2024-03-01T09:30:00Z"`)
+}
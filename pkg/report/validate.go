@@ -0,0 +1,120 @@
+package report
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/multierr"
+)
+
+// ValidateOptions configures the invariants checked by ValidateWithOptions.
+type ValidateOptions struct {
+	// AllowDuplicateNames accepts tests sharing the same name, as produced when tests are repeated.
+	AllowDuplicateNames bool
+}
+
+// Validate checks the structural invariants of a closed report and returns an error describing every violation:
+// missing timestamps or durations, negative durations, counts not matching the tests, duplicate test names
+// and steps without operations.
+func (tr *TestsReport) Validate() error {
+	return tr.ValidateWithOptions(ValidateOptions{})
+}
+
+// ValidateWithOptions is like Validate but allows relaxing some invariants.
+func (tr *TestsReport) ValidateWithOptions(options ValidateOptions) error {
+	tr.lock.Lock()
+	defer tr.lock.Unlock()
+	var errs []error
+	errs = append(errs, validateTiming("report", tr.TimeStamp, tr.Time)...)
+	failures, quarantined, warnings, tests := 0, 0, 0, 0
+	names := map[string]int{}
+	for i, test := range tr.Reports {
+		path := fmt.Sprintf("tests[%d] (%s)", i, test.Name)
+		if previous, found := names[test.Name]; found && !options.AllowDuplicateNames {
+			errs = append(errs, fmt.Errorf("%s: duplicate test name, already used by tests[%d]", path, previous))
+		} else {
+			names[test.Name] = i
+		}
+		test.lock.Lock()
+		if test.Failure != nil {
+			if test.Quarantined {
+				quarantined++
+			} else {
+				failures++
+			}
+		}
+		warnings += len(test.Warnings)
+		tests += test.Test
+		errs = append(errs, test.validate(path)...)
+		test.lock.Unlock()
+	}
+	if tr.Failures != failures {
+		errs = append(errs, fmt.Errorf("report: failures count is %d but %d tests failed", tr.Failures, failures))
+	}
+	if tr.QuarantinedFailures != quarantined {
+		errs = append(errs, fmt.Errorf("report: quarantined failures count is %d but %d quarantined tests failed", tr.QuarantinedFailures, quarantined))
+	}
+	if tr.Warnings != warnings {
+		errs = append(errs, fmt.Errorf("report: warnings count is %d but tests raised %d warnings", tr.Warnings, warnings))
+	}
+	if tr.Test != tests {
+		errs = append(errs, fmt.Errorf("report: tests count is %d but tests hold %d operations", tr.Test, tests))
+	}
+	return multierr.Combine(errs...)
+}
+
+// validate checks the invariants of a test, the caller holds the test lock.
+func (t *TestReport) validate(path string) []error {
+	var errs []error
+	if t.Name == "" {
+		errs = append(errs, fmt.Errorf("%s: missing name", path))
+	}
+	// skipped tests never run, they have no timing nor steps
+	if t.Skip {
+		return errs
+	}
+	errs = append(errs, validateTiming(path, t.TimeStamp, t.Time)...)
+	operations := 0
+	for i, step := range t.Steps {
+		stepPath := fmt.Sprintf("%s: steps[%d] (%s)", path, i, step.Name)
+		step.lock.Lock()
+		// skipped steps run no operation
+		if len(step.Results) == 0 && !step.Skip {
+			errs = append(errs, fmt.Errorf("%s: step has no operations", stepPath))
+		}
+		operations += len(step.Results)
+		for j, op := range step.Results {
+			opPath := fmt.Sprintf("%s: operations[%d] (%s)", stepPath, j, op.Name)
+			op.lock.Lock()
+			errs = append(errs, validateTiming(opPath, op.TimeStamp, op.Time)...)
+			if op.Result == "" {
+				errs = append(errs, fmt.Errorf("%s: missing result", opPath))
+			}
+			op.lock.Unlock()
+		}
+		step.lock.Unlock()
+	}
+	if t.Test != operations {
+		errs = append(errs, fmt.Errorf("%s: tests count is %d but steps hold %d operations", path, t.Test, operations))
+	}
+	return errs
+}
+
+// validateTiming checks a start timestamp and the duration that was computed when the element ended.
+func validateTiming(path string, start time.Time, duration string) []error {
+	var errs []error
+	if start.IsZero() {
+		errs = append(errs, fmt.Errorf("%s: missing start time", path))
+	}
+	if duration == "" {
+		return append(errs, fmt.Errorf("%s: missing end time", path))
+	}
+	d, err := parseDuration(duration)
+	if err != nil {
+		return append(errs, fmt.Errorf("%s: invalid duration %q", path, duration))
+	}
+	if d < 0 {
+		errs = append(errs, fmt.Errorf("%s: ends before it starts (duration %s)", path, duration))
+	}
+	return errs
+}
@@ -0,0 +1,145 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/multierr"
+)
+
+func validReport() *TestsReport {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	op := func(name string) *OperationReport {
+		return &OperationReport{Name: name, TimeStamp: start, Time: "0.500", Result: "Success", OperationType: OperationTypeApply}
+	}
+	test := func(name string, ops ...*OperationReport) *TestReport {
+		return &TestReport{
+			Name:      name,
+			TimeStamp: start,
+			Time:      "1.000",
+			Test:      len(ops),
+			Steps:     []*TestSpecStepReport{{Name: "step", Results: ops}},
+		}
+	}
+	return &TestsReport{
+		Name:      "suite",
+		TimeStamp: start,
+		Time:      "2.000",
+		Test:      3,
+		Reports:   []*TestReport{test("a", op("apply")), test("b", op("apply"), op("assert"))},
+	}
+}
+
+func TestTestsReport_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*TestsReport)
+		options ValidateOptions
+		want    []string
+	}{{
+		name:   "valid",
+		mutate: func(*TestsReport) {},
+	}, {
+		name:   "missing report start time",
+		mutate: func(r *TestsReport) { r.TimeStamp = time.Time{} },
+		want:   []string{"report: missing start time"},
+	}, {
+		name:   "missing report end time",
+		mutate: func(r *TestsReport) { r.Time = "" },
+		want:   []string{"report: missing end time"},
+	}, {
+		name:   "invalid report duration",
+		mutate: func(r *TestsReport) { r.Time = "soon" },
+		want:   []string{`report: invalid duration "soon"`},
+	}, {
+		name:   "report ends before start",
+		mutate: func(r *TestsReport) { r.Time = "-1.000" },
+		want:   []string{"report: ends before it starts (duration -1.000)"},
+	}, {
+		name:   "missing test end time",
+		mutate: func(r *TestsReport) { r.Reports[0].Time = "" },
+		want:   []string{"tests[0] (a): missing end time"},
+	}, {
+		name:   "negative operation duration",
+		mutate: func(r *TestsReport) { r.Reports[1].Steps[0].Results[1].Time = "-0.250" },
+		want:   []string{"tests[1] (b): steps[0] (step): operations[1] (assert): ends before it starts (duration -0.250)"},
+	}, {
+		name:   "missing operation result",
+		mutate: func(r *TestsReport) { r.Reports[0].Steps[0].Results[0].Result = "" },
+		want:   []string{"tests[0] (a): steps[0] (step): operations[0] (apply): missing result"},
+	}, {
+		name:   "failures mismatch",
+		mutate: func(r *TestsReport) { r.Reports[0].Failure = &Failure{Message: "failed"} },
+		want:   []string{"report: failures count is 0 but 1 tests failed"},
+	}, {
+		name:   "warnings mismatch",
+		mutate: func(r *TestsReport) { r.Warnings = 2 },
+		want:   []string{"report: warnings count is 2 but tests raised 0 warnings"},
+	}, {
+		name:   "tests mismatch",
+		mutate: func(r *TestsReport) { r.Test = 10 },
+		want:   []string{"report: tests count is 10 but tests hold 3 operations"},
+	}, {
+		name: "test operations mismatch",
+		mutate: func(r *TestsReport) {
+			r.Reports[1].Test = 1
+			r.Test = 2
+		},
+		want: []string{"tests[1] (b): tests count is 1 but steps hold 2 operations"},
+	}, {
+		name:   "duplicate test names",
+		mutate: func(r *TestsReport) { r.Reports[1].Name = "a" },
+		want:   []string{"tests[1] (a): duplicate test name, already used by tests[0]"},
+	}, {
+		name:    "duplicate test names allowed",
+		mutate:  func(r *TestsReport) { r.Reports[1].Name = "a" },
+		options: ValidateOptions{AllowDuplicateNames: true},
+	}, {
+		name:   "missing test name",
+		mutate: func(r *TestsReport) { r.Reports[1].Name = "" },
+		want:   []string{"tests[1] (): missing name"},
+	}, {
+		name: "step without operations",
+		mutate: func(r *TestsReport) {
+			r.Reports[0].Steps = append(r.Reports[0].Steps, &TestSpecStepReport{Name: "empty"})
+		},
+		want: []string{"tests[0] (a): steps[1] (empty): step has no operations"},
+	}, {
+		name: "skipped step without operations",
+		mutate: func(r *TestsReport) {
+			r.Reports[0].Steps = append(r.Reports[0].Steps, &TestSpecStepReport{Name: "skipped", Skip: true})
+		},
+	}, {
+		name: "skipped test without timing",
+		mutate: func(r *TestsReport) {
+			r.Reports = append(r.Reports, &TestReport{Name: "skipped", Skip: true})
+		},
+	}, {
+		name: "multiple violations",
+		mutate: func(r *TestsReport) {
+			r.Time = ""
+			r.Failures = 1
+		},
+		want: []string{
+			"report: missing end time",
+			"report: failures count is 1 but 0 tests failed",
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := validReport()
+			tt.mutate(report)
+			err := report.ValidateWithOptions(tt.options)
+			var got []string
+			for _, err := range multierr.Errors(err) {
+				got = append(got, err.Error())
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTestsReport_ValidateGenerated(t *testing.T) {
+	assert.NoError(t, syntheticReport(10).Validate())
+}
@@ -0,0 +1,30 @@
+package report
+
+type WarningType string
+
+const (
+	WarningTypeDeprecatedAPI WarningType = "deprecatedAPI"
+	WarningTypeSlowCleanup   WarningType = "slowCleanup"
+	WarningTypeLastAttempt   WarningType = "lastAttempt"
+	// WarningTypeQuarantinePassed flags quarantined tests that passed, they can be removed from the quarantine.
+	WarningTypeQuarantinePassed WarningType = "quarantinePassed"
+	WarningTypeOther            WarningType = "other"
+)
+
+// Warning represents a condition worth surfacing that doesn't fail the test.
+type Warning struct {
+	// Type categorizes the warning.
+	Type WarningType `json:"type" xml:"type,attr"`
+	// Message provides details about the warning.
+	Message string `json:"message" xml:"message,attr"`
+}
+
+// AddWarning adds a warning to the TestReport, warnings never change the test status.
+func (t *TestReport) AddWarning(warningType WarningType, message string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.Warnings = append(t.Warnings, Warning{
+		Type:    warningType,
+		Message: message,
+	})
+}
@@ -0,0 +1,62 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddWarning(t *testing.T) {
+	testReport := NewTest("Test1")
+	testReport.AddWarning(WarningTypeSlowCleanup, "cleanup took 2m0s")
+	testReport.AddWarning(WarningTypeDeprecatedAPI, "extensions/v1beta1 is deprecated")
+	assert.Equal(t, []Warning{
+		{Type: WarningTypeSlowCleanup, Message: "cleanup took 2m0s"},
+		{Type: WarningTypeDeprecatedAPI, Message: "extensions/v1beta1 is deprecated"},
+	}, testReport.Warnings)
+	assert.Nil(t, testReport.Failure, "Warnings must not fail the test")
+}
+
+func TestClose_Warnings(t *testing.T) {
+	testsReport := NewTests("SampleTestSuite")
+	test1 := NewTest("Test1")
+	test1.AddWarning(WarningTypeOther, "warning 1")
+	test2 := NewTest("Test2")
+	test2.AddWarning(WarningTypeOther, "warning 2")
+	test2.AddWarning(WarningTypeOther, "warning 3")
+	testsReport.AddTest(test1)
+	testsReport.AddTest(test2)
+	testsReport.Close()
+	assert.Equal(t, 3, testsReport.Warnings)
+	assert.Equal(t, 0, testsReport.Failures)
+}
+
+func TestSerialize_Warnings(t *testing.T) {
+	testsReport := NewTests("SampleTestSuite")
+	testReport := NewTest("Test1")
+	testReport.AddWarning(WarningTypeSlowCleanup, "cleanup took 2m0s")
+	testsReport.AddTest(testReport)
+	testsReport.Close()
+	tests := []struct {
+		name       string
+		serializer ReportSerializer
+		expected   []string
+	}{{
+		name:       "json",
+		serializer: JSONSerializer{},
+		expected:   []string{`"warnings": 1`, `"type": "slowCleanup"`, `"message": "cleanup took 2m0s"`},
+	}, {
+		name:       "xml",
+		serializer: XMLSerializer{},
+		expected:   []string{`warnings="1"`, `<warning type="slowCleanup" message="cleanup took 2m0s"></warning>`},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.serializer.Serialize(testsReport)
+			assert.NoError(t, err)
+			for _, expected := range tt.expected {
+				assert.Contains(t, string(data), expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package report
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// rename is used to move the temporary report file into place, it can be overridden in tests.
+var rename = os.Rename
+
+// writeFile atomically writes the content produced by write to the file at filePath.
+// Content is written to a temporary file in the same directory, synced to disk and renamed into place
+// so that readers never observe a partially written report.
+func writeFile(filePath string, perm os.FileMode, write func(io.Writer) error) (_err error) {
+	dir, base := filepath.Split(filePath)
+	if dir == "" {
+		dir = "."
+	}
+	tmp, err := os.CreateTemp(dir, "."+base+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if _err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+	if err := write(tmp); err != nil {
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil && runtime.GOOS != "windows" {
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := rename(tmp.Name(), filePath); err != nil {
+		// on windows, renaming over an existing file can fail, remove it and try again
+		if runtime.GOOS != "windows" {
+			return err
+		}
+		if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return rename(tmp.Name(), filePath)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package report
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "report.json")
+	write := func(content string) func(io.Writer) error {
+		return func(w io.Writer) error {
+			_, err := w.Write([]byte(content))
+			return err
+		}
+	}
+	assert.NoError(t, writeFile(filePath, 0o600, write("first")))
+	assert.NoError(t, writeFile(filePath, 0o600, write("second")))
+	content, err := os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "second", string(content))
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(filePath)
+		assert.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	}
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1, "temporary files should not be left behind")
+}
+
+func TestWriteFile_RenameFailure(t *testing.T) {
+	original := rename
+	defer func() { rename = original }()
+	rename = func(string, string) error {
+		return errors.New("killed before rename")
+	}
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "report.json")
+	err := SaveReport(NewTests("SampleTestSuite"), JSONSerializer{}, filePath)
+	assert.Error(t, err)
+	_, err = os.Stat(filePath)
+	assert.True(t, os.IsNotExist(err), "no partial file should exist at the target path")
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, entries, "temporary files should be cleaned up")
+}
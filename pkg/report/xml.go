@@ -0,0 +1,74 @@
+package report
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// ansiEscape matches ANSI CSI and OSC escape sequences, as produced by colored terminal output.
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(\x07|\x1b\\)`)
+
+// isXMLChar returns true if the rune is allowed in XML 1.0 documents.
+func isXMLChar(r rune) bool {
+	return r == '\t' || r == '\n' || r == '\r' ||
+		(r >= 0x20 && r <= 0xD7FF) ||
+		(r >= 0xE000 && r <= 0xFFFD) ||
+		(r >= 0x10000 && r <= 0x10FFFF)
+}
+
+// sanitizeXML removes ANSI escape sequences, invalid UTF-8 and code points not allowed in XML 1.0.
+func sanitizeXML(s string) string {
+	if s == "" {
+		return s
+	}
+	s = ansiEscape.ReplaceAllString(s, "")
+	return strings.Map(func(r rune) rune {
+		if r == utf8.RuneError || !isXMLChar(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// xmlSafe returns a copy of the report with all text sanitized for XML output, the report is left untouched.
+func (tr *TestsReport) xmlSafe() *TestsReport {
+	out := tr.DeepCopy()
+	out.Name = sanitizeXML(out.Name)
+	out.RunID = sanitizeXML(out.RunID)
+	if out.Git != nil {
+		out.Git.Branch = sanitizeXML(out.Git.Branch)
+	}
+	for _, test := range out.Reports {
+		test.Name = sanitizeXML(test.Name)
+		test.Namespace = sanitizeXML(test.Namespace)
+		test.Path = sanitizeXML(test.Path)
+		if test.Failure != nil {
+			test.Failure.Message = sanitizeXML(test.Failure.Message)
+		}
+		for i := range test.Warnings {
+			test.Warnings[i].Message = sanitizeXML(test.Warnings[i].Message)
+		}
+		for i := range test.Environment {
+			test.Environment[i].Value = sanitizeXML(test.Environment[i].Value)
+		}
+		for i := range test.Logs {
+			test.Logs[i].Message = sanitizeXML(test.Logs[i].Message)
+		}
+		for i := range test.Artifacts {
+			test.Artifacts[i] = sanitizeXML(test.Artifacts[i])
+		}
+		for _, step := range test.Steps {
+			step.Name = sanitizeXML(step.Name)
+			for _, op := range step.Results {
+				op.Name = sanitizeXML(op.Name)
+				op.Result = sanitizeXML(op.Result)
+				op.Message = sanitizeXML(op.Message)
+			}
+		}
+	}
+	walkGroups(out.Groups, func(group *TestsGroup) {
+		group.Name = sanitizeXML(group.Name)
+	})
+	return out
+}
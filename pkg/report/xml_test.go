@@ -0,0 +1,122 @@
+package report
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// strictParse decodes every token of an XML document the way strict external parsers do,
+// rejecting documents holding characters not allowed in XML 1.0.
+func strictParse(t *testing.T, data []byte) {
+	t.Helper()
+	assert.True(t, utf8.Valid(data), "document is not valid UTF-8")
+	for _, r := range string(data) {
+		assert.True(t, isXMLChar(r), "document holds invalid character %U", r)
+	}
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = true
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			return
+		}
+		if !assert.NoError(t, err) {
+			return
+		}
+	}
+}
+
+func TestSanitizeXML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{{
+		name:  "plain",
+		input: "all good",
+		want:  "all good",
+	}, {
+		name:  "ansi colors",
+		input: "\x1b[31mfailed\x1b[0m: \x1b[1;32mok\x1b[m",
+		want:  "failed: ok",
+	}, {
+		name:  "osc hyperlink",
+		input: "\x1b]8;;https://example.com\x07link\x1b]8;;\x07",
+		want:  "link",
+	}, {
+		name:  "nul and control characters",
+		input: "bin\x00ary\x01\x08\x7f data",
+		want:  "binary\x7f data",
+	}, {
+		name:  "whitespace is kept",
+		input: "a\tb\nc\rd",
+		want:  "a\tb\nc\rd",
+	}, {
+		name:  "emoji",
+		input: "done 🎉",
+		want:  "done 🎉",
+	}, {
+		name:  "invalid utf8",
+		input: "bad\xffbyte",
+		want:  "badbyte",
+	}, {
+		name:  "non characters",
+		input: "a\uFFFEb\uFFFFc",
+		want:  "abc",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sanitizeXML(tt.input))
+		})
+	}
+}
+
+func TestXMLSerializer_RoundTrip(t *testing.T) {
+	report := NewTests("suite \x1b[1mbold\x1b[0m")
+	test := NewTest("test\x00")
+	test.Namespace = "chainsaw-\x1b[33mns"
+	test.AddWarning(WarningTypeOther, "warning \x07 🚧")
+	step := NewTestSpecStep("step 🎯")
+	op := NewOperation("script", OperationTypeScript)
+	op.MarkOperationEnd(errors.New("\x1b[31merror\x1b[0m: <nul>\x00 & \"quotes\" 💥"))
+	step.AddOperation(op)
+	test.AddTestStep(step)
+	test.NewFailure("failed \x1b[31mred\x1b[0m\x00")
+	test.MarkTestEnd()
+	report.AddTest(test)
+	report.Close()
+	for _, streaming := range []bool{false, true} {
+		var data []byte
+		if streaming {
+			var buf bytes.Buffer
+			assert.NoError(t, XMLSerializer{}.SerializeTo(&buf, report))
+			data = buf.Bytes()
+		} else {
+			serialized, err := XMLSerializer{}.Serialize(report)
+			assert.NoError(t, err)
+			data = serialized
+		}
+		assert.True(t, strings.HasPrefix(string(data), `<?xml version="1.0" encoding="UTF-8"?>`+"\n"))
+		strictParse(t, data)
+		var parsed TestsReport
+		assert.NoError(t, xml.Unmarshal(data, &parsed))
+		assert.Equal(t, "suite bold", parsed.Name)
+		assert.Len(t, parsed.Reports, 1)
+		assert.Equal(t, "test", parsed.Reports[0].Name)
+		assert.Equal(t, "chainsaw-ns", parsed.Reports[0].Namespace)
+		assert.Equal(t, "failed red", parsed.Reports[0].Failure.Message)
+		assert.Equal(t, "warning  🚧", parsed.Reports[0].Warnings[0].Message)
+		assert.Equal(t, "step 🎯", parsed.Reports[0].Steps[0].Name)
+		assert.Equal(t, `error: <nul> & "quotes" 💥`, parsed.Reports[0].Steps[0].Results[0].Message)
+	}
+	// the original report is left untouched
+	assert.Equal(t, "test\x00", report.Reports[0].Name)
+	assert.Equal(t, "failed \x1b[31mred\x1b[0m\x00", report.Reports[0].Failure.Message)
+}
@@ -37,7 +37,7 @@ func Load(pattern string, manifest bool) ([]unstructured.Unstructured, error) {
 		}
 		tests, err := Parse(content, manifest)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
 		}
 		resources = append(resources, tests...)
 	}
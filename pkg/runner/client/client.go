@@ -5,7 +5,6 @@ import (
 
 	"github.com/kyverno/chainsaw/pkg/client"
 	"github.com/kyverno/chainsaw/pkg/runner/logging"
-	"github.com/kyverno/kyverno/ext/output/color"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -95,15 +94,9 @@ func (c *runnerClient) RESTMapper() meta.RESTMapper {
 }
 
 func (c *runnerClient) ok(ctx context.Context, op logging.Operation, obj ctrlclient.Object) {
-	logger := logging.FromContext(ctx)
-	if logger != nil {
-		logger.WithResource(obj).Log(op, logging.OkStatus, color.BoldGreen)
-	}
+	logging.Success(logging.FromContext(ctx).WithResource(obj), op, logging.OkStatus)
 }
 
 func (c *runnerClient) error(ctx context.Context, op logging.Operation, obj ctrlclient.Object, err error) {
-	logger := logging.FromContext(ctx)
-	if logger != nil {
-		logger.WithResource(obj).Log(op, logging.ErrorStatus, color.BoldYellow, logging.ErrSection(err))
-	}
+	logging.Warn(logging.FromContext(ctx).WithResource(obj), op, logging.ErrorStatus, logging.ErrSection(err))
 }
@@ -0,0 +1,206 @@
+package dashboard
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kyverno/chainsaw/pkg/runner/events"
+	"github.com/kyverno/chainsaw/pkg/runner/logging"
+	"github.com/kyverno/chainsaw/pkg/runner/progress"
+	"golang.org/x/term"
+	"k8s.io/utils/clock"
+)
+
+const (
+	// RefreshInterval is how often the dashboard is redrawn on terminals.
+	RefreshInterval = 250 * time.Millisecond
+	// ProgressInterval is how often a progress line is printed when the output is not a terminal.
+	ProgressInterval = 30 * time.Second
+	// maxRunning is the number of in-flight tests listed on terminals.
+	maxRunning   = 20
+	defaultWidth = 120
+)
+
+// Dashboard renders the progress of a run from the events published by the runner.
+// On terminals the dashboard is redrawn in place, otherwise a plain progress line is printed periodically.
+type Dashboard struct {
+	out         io.Writer
+	clock       clock.PassiveClock
+	interactive bool
+	width       int
+	lock        sync.Mutex
+	start       time.Time
+	tracker     *progress.Tracker
+	running     map[logging.TestKey]*runningTest
+	failures    []string
+	drawn       int
+	stop        chan struct{}
+	done        chan struct{}
+}
+
+type runningTest struct {
+	name  string
+	step  string
+	start time.Time
+}
+
+// New returns a dashboard writing to out, it is interactive when out is a terminal.
+func New(out io.Writer, clock clock.PassiveClock) *Dashboard {
+	interactive, width := false, defaultWidth
+	if f, ok := out.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		interactive = true
+		if w, _, err := term.GetSize(int(f.Fd())); err == nil && w > 0 {
+			width = w
+		}
+	}
+	return newDashboard(out, clock, interactive, width)
+}
+
+func newDashboard(out io.Writer, clock clock.PassiveClock, interactive bool, width int) *Dashboard {
+	return &Dashboard{
+		out:         out,
+		clock:       clock,
+		interactive: interactive,
+		width:       width,
+		start:       clock.Now(),
+		tracker:     progress.NewTracker(0),
+		running:     map[logging.TestKey]*runningTest{},
+	}
+}
+
+// Interactive returns true if the dashboard is redrawn in place.
+func (d *Dashboard) Interactive() bool {
+	return d.interactive
+}
+
+// Handle updates the dashboard state, it is meant to be subscribed to the runner events bus.
+func (d *Dashboard) Handle(event events.Event) {
+	d.tracker.Handle(event)
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	// tests of different directories may have the same name
+	key := logging.TestKey{Path: event.Path, Name: event.Test}
+	switch event.Type {
+	case events.TestStarted:
+		d.running[key] = &runningTest{name: event.Test, start: event.Time}
+	case events.StepStarted:
+		if test := d.running[key]; test != nil {
+			test.step = event.Step
+		}
+	case events.TestFinished:
+		delete(d.running, key)
+		if event.Failed {
+			d.failures = append(d.failures, event.Test)
+		}
+	}
+}
+
+// Start renders the dashboard periodically until Stop is called.
+func (d *Dashboard) Start() {
+	interval := ProgressInterval
+	if d.interactive {
+		interval = RefreshInterval
+	}
+	d.stop, d.done = make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(d.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-d.stop:
+				return
+			case <-ticker.C:
+				d.render()
+			}
+		}
+	}()
+}
+
+// Stop stops the periodic rendering and renders the final state.
+func (d *Dashboard) Stop() {
+	if d.stop != nil {
+		close(d.stop)
+		<-d.done
+		d.stop = nil
+	}
+	d.render()
+}
+
+// render draws a frame on terminals, or prints a progress line.
+func (d *Dashboard) render() {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if !d.interactive {
+		fmt.Fprintln(d.out, d.header())
+		return
+	}
+	lines := d.frame()
+	var out strings.Builder
+	// move back to the top of the previous frame and clear it
+	if d.drawn > 0 {
+		fmt.Fprintf(&out, "\x1b[%dA\x1b[J", d.drawn)
+	}
+	for _, line := range lines {
+		out.WriteString(truncate(line, d.width))
+		out.WriteString("\n")
+	}
+	d.drawn = len(lines)
+	_, _ = io.WriteString(d.out, out.String())
+}
+
+// frame returns the lines of the dashboard: the header, the failed tests and the in-flight tests, the caller owns the lock.
+func (d *Dashboard) frame() []string {
+	now := d.clock.Now()
+	lines := []string{d.header()}
+	for _, name := range d.failures {
+		lines = append(lines, "FAIL "+name)
+	}
+	running := make([]*runningTest, 0, len(d.running))
+	for _, test := range d.running {
+		running = append(running, test)
+	}
+	sort.Slice(running, func(i, j int) bool {
+		if running[i].start.Equal(running[j].start) {
+			return running[i].name < running[j].name
+		}
+		return running[i].start.Before(running[j].start)
+	})
+	for i, test := range running {
+		if i == maxRunning {
+			lines = append(lines, fmt.Sprintf("  ... and %d more", len(running)-maxRunning))
+			break
+		}
+		line := "  " + test.name
+		if test.step != "" {
+			line += " | " + test.step
+		}
+		lines = append(lines, fmt.Sprintf("%s (%s)", line, formatElapsed(now.Sub(test.start))))
+	}
+	return lines
+}
+
+// header returns the elapsed time and the test counts, the caller owns the lock.
+func (d *Dashboard) header() string {
+	counters := d.tracker.Counters()
+	return fmt.Sprintf("[%s] %d passed, %d failed, %d skipped, %d running", formatElapsed(d.clock.Since(d.start)), counters.Passed, counters.Failed, counters.Skipped, len(d.running))
+}
+
+func formatElapsed(d time.Duration) string {
+	return d.Round(time.Second).String()
+}
+
+// truncate shortens s to at most n characters so that lines don't wrap, wrapped lines would break redraws.
+func truncate(s string, n int) string {
+	if n <= 0 || utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:n-1]) + "…"
+}
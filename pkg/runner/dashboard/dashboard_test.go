@@ -0,0 +1,101 @@
+package dashboard
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/runner/events"
+	"github.com/stretchr/testify/assert"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+func TestDashboard_Frame(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	clock := tclock.NewFakePassiveClock(start)
+	var out bytes.Buffer
+	board := newDashboard(&out, clock, true, 80)
+	for _, event := range []events.Event{
+		{Type: events.TestStarted, Time: start, Test: "first"},
+		{Type: events.TestStarted, Time: start.Add(5 * time.Second), Test: "second"},
+		{Type: events.TestStarted, Time: start.Add(10 * time.Second), Test: "third"},
+		{Type: events.TestStarted, Time: start.Add(15 * time.Second), Test: "fourth"},
+		{Type: events.StepStarted, Time: start.Add(20 * time.Second), Test: "first", Step: "step-2"},
+		{Type: events.TestFinished, Time: start.Add(25 * time.Second), Test: "second", Failed: true},
+		{Type: events.TestFinished, Time: start.Add(25 * time.Second), Test: "third"},
+		{Type: events.TestFinished, Time: start.Add(25 * time.Second), Test: "skipped", Skipped: true},
+	} {
+		board.Handle(event)
+	}
+	clock.SetTime(start.Add(90 * time.Second))
+	board.render()
+	expected := strings.Join([]string{
+		"[1m30s] 1 passed, 1 failed, 1 skipped, 2 running",
+		"FAIL second",
+		"  first | step-2 (1m30s)",
+		"  fourth (1m15s)",
+	}, "\n") + "\n"
+	assert.Equal(t, expected, out.String())
+	// the next frame replaces the previous one
+	out.Reset()
+	board.Handle(events.Event{Type: events.TestFinished, Test: "first"})
+	board.render()
+	assert.Equal(t, "\x1b[4A\x1b[J[1m30s] 2 passed, 1 failed, 1 skipped, 1 running\nFAIL second\n  fourth (1m15s)\n", out.String())
+}
+
+func TestDashboard_DuplicateNames(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	clock := tclock.NewFakePassiveClock(start)
+	var out bytes.Buffer
+	board := newDashboard(&out, clock, true, 80)
+	for _, event := range []events.Event{
+		{Type: events.TestStarted, Time: start, Test: "test", Path: "tests/a"},
+		{Type: events.TestStarted, Time: start.Add(5 * time.Second), Test: "test", Path: "tests/b"},
+		{Type: events.StepStarted, Time: start.Add(10 * time.Second), Test: "test", Path: "tests/a", Step: "step-2"},
+	} {
+		board.Handle(event)
+	}
+	clock.SetTime(start.Add(time.Minute))
+	board.render()
+	// tests sharing a name are tracked apart
+	assert.Equal(t, "[1m0s] 0 passed, 0 failed, 0 skipped, 2 running\n  test | step-2 (1m0s)\n  test (55s)\n", out.String())
+	out.Reset()
+	board.Handle(events.Event{Type: events.TestFinished, Test: "test", Path: "tests/a"})
+	board.render()
+	assert.Equal(t, "\x1b[3A\x1b[J[1m0s] 1 passed, 0 failed, 0 skipped, 1 running\n  test (55s)\n", out.String())
+}
+
+func TestDashboard_FrameLimits(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	clock := tclock.NewFakePassiveClock(start)
+	var out bytes.Buffer
+	board := newDashboard(&out, clock, true, 30)
+	for i := 0; i < maxRunning+3; i++ {
+		board.Handle(events.Event{Type: events.TestStarted, Time: start, Test: fmt.Sprintf("test-%02d-with-a-long-name", i)})
+	}
+	board.render()
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	assert.Len(t, lines, maxRunning+2)
+	assert.Equal(t, "  ... and 3 more", lines[len(lines)-1])
+	assert.Equal(t, "  test-00-with-a-long-name (0…", lines[1])
+	for _, line := range lines {
+		assert.LessOrEqual(t, len([]rune(line)), 30)
+	}
+}
+
+func TestDashboard_Plain(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	clock := tclock.NewFakePassiveClock(start)
+	var out bytes.Buffer
+	board := New(&out, clock)
+	assert.False(t, board.Interactive())
+	board.Start()
+	board.Handle(events.Event{Type: events.TestStarted, Time: start, Test: "first"})
+	board.Handle(events.Event{Type: events.TestFinished, Time: start, Test: "first", Failed: true})
+	clock.SetTime(start.Add(time.Minute))
+	board.Stop()
+	// the final state is printed as a progress line, without escape sequences
+	assert.Equal(t, "[1m0s] 0 passed, 1 failed, 0 skipped, 0 running\n", out.String())
+}
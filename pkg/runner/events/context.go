@@ -0,0 +1,48 @@
+package events
+
+import (
+	"context"
+)
+
+type contextKey struct{}
+
+type scopeKey struct{}
+
+// scope is the test and step events are published for.
+type scope struct {
+	test string
+	step string
+}
+
+func FromContext(ctx context.Context) *Bus {
+	if ctx != nil {
+		if v, ok := ctx.Value(contextKey{}).(*Bus); ok {
+			return v
+		}
+	}
+	return nil
+}
+
+func IntoContext(ctx context.Context, bus *Bus) context.Context {
+	return context.WithValue(ctx, contextKey{}, bus)
+}
+
+// WithScope returns a context publishing the events not naming a test or a step for test and step.
+func WithScope(ctx context.Context, test, step string) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope{test: test, step: step})
+}
+
+// Publish delivers the event to the bus registered in the context, if any.
+func Publish(ctx context.Context, event Event) {
+	if bus := FromContext(ctx); bus != nil {
+		if scope, ok := ctx.Value(scopeKey{}).(scope); ok {
+			if event.Test == "" {
+				event.Test = scope.test
+			}
+			if event.Step == "" {
+				event.Step = scope.step
+			}
+		}
+		bus.Publish(event)
+	}
+}
@@ -0,0 +1,99 @@
+package events
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/report"
+	"k8s.io/utils/clock"
+)
+
+type Type string
+
+const (
+	// SuiteStarted is published when the run starts running tests.
+	SuiteStarted Type = "SuiteStarted"
+	// TestStarted is published when a test starts running.
+	TestStarted Type = "TestStarted"
+	// StepStarted is published when a test starts running a step.
+	StepStarted Type = "StepStarted"
+	// OperationStarted is published when a step starts running an operation.
+	OperationStarted Type = "OperationStarted"
+	// OperationFinished is published when an operation completes, successfully or not.
+	OperationFinished Type = "OperationFinished"
+	// StepFinished is published when a step completes, passed or failed.
+	StepFinished Type = "StepFinished"
+	// TestFinished is published when a test completes, passed, failed or skipped.
+	TestFinished Type = "TestFinished"
+	// SuiteFinished is published when all the tests of the run completed.
+	SuiteFinished Type = "SuiteFinished"
+)
+
+// Event is a progress notification published by the runner while tests run.
+type Event struct {
+	// Type of the event.
+	Type Type
+	// Time is when the event happened.
+	Time time.Time
+	// Test is the name of the test.
+	Test string
+	// Path is the directory of the test, set on test and step events, tests may share a name but not a name and a path.
+	Path string
+	// Step is the name of the step, set on step and operation events.
+	Step string
+	// Operation and OperationType identify the operation, set on operation events.
+	Operation     string
+	OperationType report.OperationType
+	// Failed and Skipped are the outcome of the test, step or operation, set on finished events.
+	Failed  bool
+	Skipped bool
+	// Err is the error of a failed operation, set on OperationFinished events.
+	Err error
+	// Tests is the number of tests of the run, set on SuiteStarted events.
+	Tests int
+	// Summary counts the tests of the run by outcome, set on SuiteFinished events.
+	Summary *Summary
+}
+
+// Summary counts the tests of a run by outcome.
+type Summary struct {
+	Passed  int
+	Failed  int
+	Skipped int
+}
+
+// Bus delivers events to its subscribers, in the order they are published.
+type Bus struct {
+	lock        sync.Mutex
+	clock       clock.PassiveClock
+	subscribers []func(Event)
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// NewBusWithClock returns a bus stamping the events published without a time with clock.
+func NewBusWithClock(clock clock.PassiveClock) *Bus {
+	return &Bus{clock: clock}
+}
+
+// Subscribe registers a handler called for every event published afterwards.
+// Handlers are called synchronously by the publisher and should return quickly.
+func (b *Bus) Subscribe(handler func(Event)) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.subscribers = append(b.subscribers, handler)
+}
+
+// Publish delivers the event to all subscribers.
+func (b *Bus) Publish(event Event) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if event.Time.IsZero() && b.clock != nil {
+		event.Time = b.clock.Now()
+	}
+	for _, handler := range b.subscribers {
+		handler(event)
+	}
+}
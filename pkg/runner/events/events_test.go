@@ -0,0 +1,67 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+func TestBus(t *testing.T) {
+	bus := NewBus()
+	var first, second []Event
+	bus.Subscribe(func(event Event) { first = append(first, event) })
+	published := []Event{
+		{Type: TestStarted, Time: time.Now(), Test: "test"},
+		{Type: StepStarted, Time: time.Now(), Test: "test", Step: "step-1"},
+	}
+	bus.Publish(published[0])
+	// late subscribers only get the events published afterwards
+	bus.Subscribe(func(event Event) { second = append(second, event) })
+	bus.Publish(published[1])
+	assert.Equal(t, published, first)
+	assert.Equal(t, published[1:], second)
+}
+
+func TestPublish(t *testing.T) {
+	var got []Event
+	bus := NewBus()
+	bus.Subscribe(func(event Event) { got = append(got, event) })
+	event := Event{Type: TestFinished, Test: "test", Failed: true}
+	// without a bus in the context events are dropped
+	Publish(context.Background(), event)
+	Publish(nil, event) //nolint:staticcheck
+	assert.Empty(t, got)
+	Publish(IntoContext(context.Background(), bus), event)
+	assert.Equal(t, []Event{event}, got)
+	assert.Same(t, bus, FromContext(IntoContext(context.Background(), bus)))
+}
+
+func TestNewBusWithClock(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	bus := NewBusWithClock(tclock.NewFakePassiveClock(now))
+	var got []Event
+	bus.Subscribe(func(event Event) { got = append(got, event) })
+	bus.Publish(Event{Type: TestStarted, Test: "test"})
+	// times set by the publisher are kept
+	bus.Publish(Event{Type: TestFinished, Time: now.Add(time.Second), Test: "test"})
+	assert.Equal(t, []Event{
+		{Type: TestStarted, Time: now, Test: "test"},
+		{Type: TestFinished, Time: now.Add(time.Second), Test: "test"},
+	}, got)
+}
+
+func TestWithScope(t *testing.T) {
+	var got []Event
+	bus := NewBus()
+	bus.Subscribe(func(event Event) { got = append(got, event) })
+	ctx := WithScope(IntoContext(context.Background(), bus), "test", "step-1")
+	Publish(ctx, Event{Type: OperationStarted, Operation: "Apply"})
+	Publish(ctx, Event{Type: StepStarted, Test: "other", Step: "step-2"})
+	assert.Equal(t, []Event{
+		{Type: OperationStarted, Test: "test", Step: "step-1", Operation: "Apply"},
+		{Type: StepStarted, Test: "other", Step: "step-2"},
+	}, got)
+}
@@ -0,0 +1,114 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
+	"github.com/kyverno/chainsaw/pkg/report"
+	"github.com/kyverno/chainsaw/pkg/runner/logging"
+)
+
+// InterruptedExitCode is the exit code of runs interrupted by a signal, after the partial report has been written.
+const InterruptedExitCode = 130
+
+var (
+	// shutdownGracePeriod bounds the time spent writing the partial report of an interrupted run.
+	shutdownGracePeriod = 30 * time.Second
+	// exit terminates the process, it can be overridden in tests.
+	exit = os.Exit
+	// notifySignals relays the signals interrupting the run, it can be overridden in tests.
+	notifySignals = func(c chan<- os.Signal) {
+		signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	}
+)
+
+// reportFinalizer saves the reports of a run once, either when the run completes or when it is interrupted.
+type reportFinalizer struct {
+	lock  sync.Mutex
+	saved bool
+}
+
+func (f *reportFinalizer) save(config v1alpha1.ConfigurationSpec, testsReport *report.TestsReport, journal *report.Journal) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.saved {
+		return nil
+	}
+	f.saved = true
+	return saveReports(config, testsReport, journal)
+}
+
+// shutdown finalizes the report of an interrupted run and writes the configured formats.
+// Tests that didn't complete are reported as interrupted failures, tests still running are left alone.
+func shutdown(config v1alpha1.ConfigurationSpec, testsReport *report.TestsReport, journal *report.Journal, finalizer *reportFinalizer) error {
+	interrupted := testsReport.Interrupt()
+	testsReport.Close()
+	// tests may still be running, work on a snapshot
+	snapshot := testsReport.DeepCopy()
+	fmt.Fprintf(stderr, "Run interrupted, %d test(s) didn't complete\n", interrupted)
+	if err := finalizer.save(config, snapshot, journal); err != nil {
+		return err
+	}
+	out := stdout
+	if config.ReportName == report.StdoutName {
+		out = stderr
+	}
+	return report.PrintSummary(out, snapshot, summaryOptions())
+}
+
+// summaryOptions returns the options of the summary, colored with the logging theme.
+func summaryOptions() report.SummaryOptions {
+	theme := logging.GetTheme()
+	return report.SummaryOptions{
+		Passed:  theme.Success,
+		Failed:  theme.Failure,
+		Warning: theme.Warning,
+	}
+}
+
+// handleSignals runs shutdown when the run is interrupted and exits with InterruptedExitCode.
+// A second signal, or shutdown taking longer than the grace period, exits immediately.
+// The returned function stops handling signals.
+func handleSignals(shutdown func() error) func() {
+	signals := make(chan os.Signal, 2)
+	notifySignals(signals)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-done:
+			return
+		case sig := <-signals:
+			// the run may have completed concurrently
+			select {
+			case <-done:
+				return
+			default:
+			}
+			fmt.Fprintf(stderr, "Received %s, writing partial report (send it again to exit immediately)...\n", sig)
+			finished := make(chan struct{})
+			go func() {
+				defer close(finished)
+				if err := shutdown(); err != nil {
+					fmt.Fprintln(stderr, "Error:", err)
+				}
+			}()
+			select {
+			case <-finished:
+			case sig := <-signals:
+				fmt.Fprintf(stderr, "Received %s again, exiting\n", sig)
+			case <-time.After(shutdownGracePeriod):
+				fmt.Fprintf(stderr, "Partial report not written after %s, exiting\n", shutdownGracePeriod)
+			}
+			exit(InterruptedExitCode)
+		}
+	}()
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
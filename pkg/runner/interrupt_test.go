@@ -0,0 +1,130 @@
+package runner
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
+	"github.com/kyverno/chainsaw/pkg/report"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShutdown(t *testing.T) {
+	defer func(out, err io.Writer) { stdout, stderr = out, err }(stdout, stderr)
+	var out, errOut bytes.Buffer
+	stdout, stderr = &out, &errOut
+	dir := t.TempDir()
+	config := v1alpha1.ConfigurationSpec{
+		ReportFormat:   v1alpha1.JSONFormat,
+		ReportPath:     dir,
+		ReportName:     "chainsaw-report",
+		ReportFailures: true,
+	}
+	testsReport := report.NewTests("chainsaw")
+	filePath := report.FilePath(config.ReportFormat, config.ReportPath, config.ReportName)
+	journal, err := report.NewJournal(report.JournalPath(filePath), testsReport)
+	assert.NoError(t, err)
+	testsReport.SetJournal(journal)
+	completed := report.NewTest("completed")
+	testsReport.AddTest(completed)
+	completed.MarkTestEnd()
+	failed := report.NewTest("failed")
+	testsReport.AddTest(failed)
+	failed.NewFailure("test failed")
+	failed.MarkTestEnd()
+	running := report.NewTest("running")
+	testsReport.AddTest(running)
+	var finalizer reportFinalizer
+	assert.NoError(t, shutdown(config, testsReport, journal, &finalizer))
+	// the partial report holds all the tests
+	saved, err := report.Load(filePath)
+	assert.NoError(t, err)
+	assert.Len(t, saved.Reports, 3)
+	assert.Equal(t, 2, saved.Failures)
+	assert.False(t, saved.Reports[0].Interrupted)
+	assert.Nil(t, saved.Reports[0].Failure)
+	assert.False(t, saved.Reports[1].Interrupted)
+	assert.Equal(t, &report.Failure{Message: "test failed"}, saved.Reports[1].Failure)
+	assert.True(t, saved.Reports[2].Interrupted)
+	assert.Equal(t, &report.Failure{Message: report.InterruptedMessage}, saved.Reports[2].Failure)
+	assert.NoError(t, saved.Validate())
+	assert.FileExists(t, filepath.Join(dir, "chainsaw-report-failures.json"))
+	assert.NoFileExists(t, report.JournalPath(filePath))
+	assert.Contains(t, errOut.String(), "Run interrupted, 1 test(s) didn't complete\n")
+	assert.Contains(t, out.String(), "Tests: 1 passed, 2 failed, 0 skipped")
+	// reports are saved once, a run completing while shutting down doesn't overwrite them
+	assert.NoError(t, os.Remove(filePath))
+	assert.NoError(t, finalizer.save(config, testsReport, journal))
+	assert.NoFileExists(t, filePath)
+}
+
+func TestHandleSignals(t *testing.T) {
+	defer func(out, err io.Writer) { stdout, stderr = out, err }(stdout, stderr)
+	defer func(f func(int)) { exit = f }(exit)
+	defer func(f func(chan<- os.Signal)) { notifySignals = f }(notifySignals)
+	defer func(d time.Duration) { shutdownGracePeriod = d }(shutdownGracePeriod)
+	stdout, stderr = io.Discard, io.Discard
+	var signals chan<- os.Signal
+	notifySignals = func(c chan<- os.Signal) { signals = c }
+	codes := make(chan int, 1)
+	exit = func(code int) { codes <- code }
+	block := make(chan struct{})
+	defer close(block)
+	tests := []struct {
+		name        string
+		shutdown    func() error
+		signals     int
+		gracePeriod time.Duration
+	}{{
+		name:        "report written",
+		shutdown:    func() error { return nil },
+		signals:     1,
+		gracePeriod: time.Minute,
+	}, {
+		name:        "report failed",
+		shutdown:    func() error { return errors.New("disk full") },
+		signals:     1,
+		gracePeriod: time.Minute,
+	}, {
+		name:        "second signal",
+		shutdown:    func() error { <-block; return nil },
+		signals:     2,
+		gracePeriod: time.Minute,
+	}, {
+		name:        "grace period",
+		shutdown:    func() error { <-block; return nil },
+		signals:     1,
+		gracePeriod: 10 * time.Millisecond,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shutdownGracePeriod = tt.gracePeriod
+			stop := handleSignals(tt.shutdown)
+			defer stop()
+			for i := 0; i < tt.signals; i++ {
+				signals <- syscall.SIGTERM
+			}
+			select {
+			case code := <-codes:
+				assert.Equal(t, InterruptedExitCode, code)
+			case <-time.After(10 * time.Second):
+				assert.Fail(t, "exit was not called")
+			}
+		})
+	}
+	// signals received once the run completed are not handled
+	stop := handleSignals(func() error { return nil })
+	stop()
+	signals <- syscall.SIGTERM
+	select {
+	case <-codes:
+		assert.Fail(t, "exit was called")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,119 @@
+package logging
+
+import (
+	"bytes"
+	"sync"
+	"sync/atomic"
+
+	"github.com/kyverno/chainsaw/pkg/client"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// textCache holds the parts of human readable lines that don't change between the lines of a logger, the names of
+// the test, step and operation, and the resource. Loggers derived with another resource or operation get their own cache.
+// Parts are cached with what they are rendered from and rendered again when it changed, like the name of a resource
+// set once created, the output is the same as without cache. A nil textCache caches nothing, it is safe for concurrent use.
+type textCache struct {
+	names    atomic.Pointer[cachedNames]
+	resource atomic.Pointer[cachedResource]
+}
+
+type cachedNames struct {
+	test      string
+	step      string
+	operation string
+	ordinals  Ordinals
+	columns   ColumnWidths
+	text      string
+}
+
+type cachedResource struct {
+	gvk       schema.GroupVersionKind
+	namespace string
+	name      string
+	format    ResourceFormat
+	text      string
+}
+
+// namesText returns the uncolored test, step and operation name columns of entry.
+func (c *textCache) namesText(entry Entry) string {
+	if c == nil {
+		return formatNames(entry, nil)
+	}
+	if cached := c.names.Load(); cached != nil && cached.test == entry.Test && cached.step == entry.Step &&
+		cached.operation == entry.OperationName && cached.ordinals == entry.Ordinals && cached.columns == entry.Columns {
+		return cached.text
+	}
+	text := formatNames(entry, nil)
+	c.names.Store(&cachedNames{
+		test:      entry.Test,
+		step:      entry.Step,
+		operation: entry.OperationName,
+		ordinals:  entry.Ordinals,
+		columns:   entry.Columns,
+		text:      text,
+	})
+	return text
+}
+
+// resourceText returns the resource of entry in its resource format, entry.Resource must not be nil.
+func (c *textCache) resourceText(entry Entry) string {
+	if c == nil {
+		return FormatResource(entry.Resource, entry.ResourceFormat)
+	}
+	gvk := entry.Resource.GetObjectKind().GroupVersionKind()
+	key := client.ObjectKey(entry.Resource)
+	if cached := c.resource.Load(); cached != nil && cached.gvk == gvk && cached.namespace == key.Namespace &&
+		cached.name == key.Name && cached.format == entry.ResourceFormat {
+		return cached.text
+	}
+	text := FormatResource(entry.Resource, entry.ResourceFormat)
+	c.resource.Store(&cachedResource{
+		gvk:       gvk,
+		namespace: key.Namespace,
+		name:      key.Name,
+		format:    entry.ResourceFormat,
+		text:      text,
+	})
+	return text
+}
+
+// newTextCache returns the cache of the lines of l, with the parts rendered once from the names and resource of l,
+// it is called when a logger is created or derived with another resource or operation.
+func newTextCache(l *logger) *textCache {
+	c := &textCache{}
+	entry := Entry{
+		Test:           l.test,
+		Step:           l.step,
+		OperationName:  l.operationName,
+		Ordinals:       l.ordinals,
+		Columns:        l.columns,
+		Resource:       l.resource,
+		ResourceFormat: l.resourceFormat,
+	}
+	c.namesText(entry)
+	if entry.Resource != nil {
+		c.resourceText(entry)
+	}
+	return c
+}
+
+// maxPooledBuffer is the capacity above which buffers are not returned to the pool, huge messages would pin their memory.
+const maxPooledBuffer = 64 * 1024
+
+// textBuffers are the buffers human readable lines are assembled in.
+var textBuffers = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+func getTextBuffer() *bytes.Buffer {
+	buf := textBuffers.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+func putTextBuffer(buf *bytes.Buffer) {
+	if buf.Cap() <= maxPooledBuffer {
+		textBuffers.Put(buf)
+	}
+}
@@ -0,0 +1,82 @@
+package logging
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/report"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+func Test_textCache(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	var pod unstructured.Unstructured
+	pod.SetAPIVersion("v1")
+	pod.SetKind("Pod")
+	pod.SetNamespace("default")
+	pod.SetGenerateName("nginx-")
+	var out strings.Builder
+	logger := NewWriterLogger(&out, fakeClock, "test", "step", WithColor(ColorNever)).WithResource(&pod)
+	logger.Log(Create, RunStatus, nil)
+	// resources created with a generated name get it once created, lines logged afterwards have it
+	pod.SetName("nginx-x7k2p")
+	logger.Log(Create, OkStatus, nil)
+	// derived loggers have their own cache, the parent keeps its lines
+	logger.WithOperation("Create pod.yaml", report.OperationTypeCreate).Log(Create, DoneStatus, nil)
+	logger.Log(Create, DoneStatus, nil)
+	assert.Equal(t, `| 10:30:00 | test | step | CREATE    | RUN   | v1/Pod @ default/*
+| 10:30:00 | test | step | CREATE    | OK    | v1/Pod @ default/nginx-x7k2p
+| 10:30:00 | test | step | Create pod.yaml | CREATE    | DONE  | v1/Pod @ default/nginx-x7k2p
+| 10:30:00 | test | step | CREATE    | DONE  | v1/Pod @ default/nginx-x7k2p
+`, out.String())
+}
+
+func Test_textCache_Entry(t *testing.T) {
+	var pod unstructured.Unstructured
+	pod.SetAPIVersion("v1")
+	pod.SetKind("Pod")
+	pod.SetName("nginx")
+	var entries []Entry
+	logger := NewSinkLogger(sinkFunc(func(entry Entry) error {
+		entries = append(entries, entry)
+		return nil
+	}), tclock.NewFakePassiveClock(time.Time{}), "test", "step", WithTimestampLayout(NoTimestamp)).WithResource(&pod)
+	logger.Log(Get, OkStatus, nil)
+	// entries changed by sinks are rendered from what they hold, not from the cache of their logger
+	entry := entries[0]
+	entry.Test, entry.ResourceFormat = "other", ShortResource
+	assert.Equal(t, "| test | step | GET       | OK    | v1/Pod @ nginx", FormatText(entries[0], false))
+	assert.Equal(t, "| other | step | GET       | OK    | Pod/nginx", FormatText(entry, false))
+}
+
+func Test_textCache_Concurrent(t *testing.T) {
+	var pod unstructured.Unstructured
+	pod.SetAPIVersion("v1")
+	pod.SetKind("Pod")
+	pod.SetName("nginx")
+	var lock sync.Mutex
+	lines := map[string]int{}
+	logger := NewSinkLogger(sinkFunc(func(entry Entry) error {
+		line := FormatText(entry, false)
+		lock.Lock()
+		defer lock.Unlock()
+		lines[line]++
+		return nil
+	}), tclock.NewFakePassiveClock(time.Time{}), "test", "step", WithTimestampLayout(NoTimestamp)).WithResource(&pod)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				logger.Log(Get, OkStatus, nil, s("line"))
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, map[string]int{"| test | step | GET       | OK    | v1/Pod @ nginx\nline": 800}, lines)
+}
@@ -0,0 +1,56 @@
+package logging
+
+import (
+	"fmt"
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// loggingDir is the folder of the files of the logging package, their frames are skipped when looking up the caller.
+var loggingDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return path.Dir(file)
+}()
+
+// WithCaller records the location of the code logging the lines at or below level, typically ErrorLevel or
+// WarnLevel, like processors/step.go:42. It is printed dimmed after the resource and written as "caller" by the JSON,
+// logr and slog sinks.
+func WithCaller(level Level) Option {
+	return func(l *logger) {
+		l.callers = true
+		l.callerLevel = level
+	}
+}
+
+// ParseCallerLevel parses the level of the lines whose caller is recorded, see WithCaller, either error or warn.
+func ParseCallerLevel(name string) (Level, error) {
+	level, err := ParseLevel(name)
+	if err != nil || level > WarnLevel {
+		return ErrorLevel, fmt.Errorf("invalid caller level %q (error|warn)", name)
+	}
+	return level, nil
+}
+
+// callerFrames is the number of frames of the logger between runtime.Callers and the code logging a line, the
+// runtime.Callers, caller and logStyle frames.
+const callerFrames = 3
+
+// caller returns the location, as dir/file.go:line, of the first frame outside the files of the logging package.
+// The frames of the helpers like Failure and of the Deduper vary, they are skipped by file rather than counted.
+// The tests of the logging package are callers like any other.
+func caller() string {
+	var pcs [32]uintptr
+	n := runtime.Callers(callerFrames, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if frame.File != "" && (path.Dir(frame.File) != loggingDir || strings.HasSuffix(frame.File, "_test.go")) {
+			return path.Base(path.Dir(frame.File)) + "/" + path.Base(frame.File) + ":" + strconv.Itoa(frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+// here returns the location of the line before the one calling it, as recorded by WithCaller.
+func here() string {
+	_, _, line, _ := runtime.Caller(1)
+	return fmt.Sprintf("logging/caller_test.go:%d", line-1)
+}
+
+func TestParseCallerLevel(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Level
+		wantErr bool
+	}{
+		{name: "error", want: ErrorLevel},
+		{name: "WARN", want: WarnLevel},
+		{name: "info", want: ErrorLevel, wantErr: true},
+		{name: "loud", want: ErrorLevel, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCallerLevel(tt.name)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWithCaller(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Now())
+	var entries []Entry
+	sink := sinkFunc(func(entry Entry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	logger := NewSinkLogger(sink, fakeClock, "test", "step", WithCaller(ErrorLevel))
+	resource := &unstructured.Unstructured{}
+	resource.SetAPIVersion("v1")
+	resource.SetKind("ConfigMap")
+	resource.SetName("quick-start")
+	var want []string
+	Failure(logger, Assert, ErrorStatus, s("direct"))
+	want = append(want, here())
+	logger.WithResource(resource).LogLevel(ErrorLevel, Assert, ErrorStatus, nil)
+	want = append(want, here())
+	Failure(logger.WithOperation("assert", "assert").WithFields(map[string]any{"attempt": 1}), Assert, ErrorStatus)
+	want = append(want, here())
+	deduper := Dedupe(logger, fakeClock)
+	Failure(deduper.WithResource(resource), Assert, ErrorStatus)
+	want = append(want, here())
+	deduper.Flush()
+	// lines above the level have no caller
+	Warn(logger, Apply, WarnStatus)
+	logger.Log(Apply, OkStatus, nil)
+	want = append(want, "", "")
+	var got []string
+	for _, entry := range entries {
+		got = append(got, entry.Caller)
+	}
+	assert.Equal(t, want, got)
+	// without the option no caller is recorded
+	entries = nil
+	Failure(NewSinkLogger(sink, fakeClock, "test", "step"), Assert, ErrorStatus)
+	require.Len(t, entries, 1)
+	assert.Empty(t, entries[0].Caller)
+}
+
+func TestWithCaller_Sinks(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	mockT := &tlogging.FakeTLogger{}
+	var buf bytes.Buffer
+	logger := NewLogger(mockT, fakeClock, "test", "step", WithCaller(WarnLevel), WithColor(ColorNever), WithJSON(NewJSONWriter(&buf)))
+	Warn(logger, Apply, WarnStatus, s("slow"))
+	location := here()
+	require.Len(t, mockT.Messages, 1)
+	assert.True(t, strings.HasSuffix(mockT.Messages[0], "| APPLY     | WARN  | ("+location+")\nslow"), mockT.Messages[0])
+	var line JSONLine
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, location, line.Caller)
+	// the caller is dimmed when colors are enabled
+	assert.Contains(t, FormatText(Entry{Caller: location}, true), colorSprint(callerColor)("("+location+")"))
+}
@@ -0,0 +1,127 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/kyverno/chainsaw/pkg/report"
+)
+
+// DefaultCaptureLines is the default maximum number of lines kept per test by NewCaptures.
+const DefaultCaptureLines = 1000
+
+// DefaultCaptureSize is the default maximum size in bytes of the lines kept per test in reports.
+const DefaultCaptureSize = 64 * 1024
+
+// Captures keeps the most recent log lines of each test in bounded buffers, it is safe for concurrent use.
+// Lines are stored without ANSI escape sequences, with the time they were logged at. Once a buffer holds more lines
+// or bytes than allowed the oldest lines are dropped, the most recent line is always kept.
+type Captures struct {
+	lock     sync.Mutex
+	maxLines int
+	maxSize  int
+	// keepFull keeps the full text of truncated messages
+	keepFull bool
+	buffers  map[TestKey]*captureBuffer
+}
+
+// captureBuffer holds the lines of a test, oldest first.
+type captureBuffer struct {
+	lines   []report.LogLine
+	size    int
+	dropped int
+}
+
+// NewCaptures returns captures keeping at most maxLines lines per test, DefaultCaptureLines if maxLines isn't positive.
+func NewCaptures(maxLines int) *Captures {
+	if maxLines <= 0 {
+		maxLines = DefaultCaptureLines
+	}
+	return &Captures{
+		maxLines: maxLines,
+		buffers:  map[TestKey]*captureBuffer{},
+	}
+}
+
+// NewCapturesWithMaxSize returns captures keeping at most maxSize bytes of lines per test whatever their number,
+// lines are kept without limit if maxSize isn't positive.
+func NewCapturesWithMaxSize(maxSize int) *Captures {
+	return &Captures{
+		maxSize: maxSize,
+		buffers: map[TestKey]*captureBuffer{},
+	}
+}
+
+// KeepFullMessages makes the captures keep the full text of truncated messages, other sinks still get the truncated text.
+func (c *Captures) KeepFullMessages() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.keepFull = true
+}
+
+func (c *Captures) add(test TestKey, line report.LogLine) {
+	line.Message = report.StripANSI(strings.TrimLeft(line.Message, "\b"))
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	b, ok := c.buffers[test]
+	if !ok {
+		b = &captureBuffer{}
+		c.buffers[test] = b
+	}
+	b.lines = append(b.lines, line)
+	b.size += len(line.Message)
+	for len(b.lines) > 1 && ((c.maxLines > 0 && len(b.lines) > c.maxLines) || (c.maxSize > 0 && b.size > c.maxSize)) {
+		b.size -= len(b.lines[0].Message)
+		b.lines = b.lines[1:]
+		b.dropped++
+	}
+}
+
+// WriteEntry keeps entry in the human readable format, without colors.
+// Multi-line messages are kept as they were logged, without continuation prefixes, the line is a single entry anyway.
+// Outcomes are marked with the ASCII glyphs, reports are read in places mangling non-ASCII characters.
+func (c *Captures) WriteEntry(entry Entry) error {
+	c.lock.Lock()
+	keepFull := c.keepFull
+	c.lock.Unlock()
+	if keepFull && entry.FullMessage != "" {
+		entry.Message = entry.FullMessage
+	}
+	entry.Continuation = NoContinuation
+	entry.Glyphs = ASCIIGlyphs()
+	c.add(entry.TestKey(), report.LogLine{Time: entry.Time, Message: FormatText(entry, false)})
+	return nil
+}
+
+// Lines returns the lines captured for test, oldest first, a leading line records how many lines were dropped if any.
+func (c *Captures) Lines(test TestKey) []report.LogLine {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	b, ok := c.buffers[test]
+	if !ok {
+		return nil
+	}
+	lines := make([]report.LogLine, 0, len(b.lines)+1)
+	if b.dropped != 0 {
+		lines = append(lines, report.LogLine{
+			Time:    b.lines[0].Time,
+			Message: fmt.Sprintf("... %d earlier lines dropped", b.dropped),
+		})
+	}
+	return append(lines, b.lines...)
+}
+
+// Clear releases the lines captured for test, typically once the test finished and its lines were consumed.
+func (c *Captures) Clear(test TestKey) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.buffers, test)
+}
+
+// Take returns the lines captured for test and clears them.
+func (c *Captures) Take(test TestKey) []report.LogLine {
+	lines := c.Lines(test)
+	c.Clear(test)
+	return lines
+}
@@ -0,0 +1,123 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/report"
+	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
+	"github.com/stretchr/testify/assert"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+func TestCaptures(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	captures := NewCaptures(3)
+	for i := 0; i < 5; i++ {
+		captures.add(TestKey{Name: "test"}, report.LogLine{Time: now.Add(time.Duration(i) * time.Second), Message: fmt.Sprintf("\b\b\x1b[32mline %d\x1b[0m", i)})
+	}
+	captures.add(TestKey{Name: "other"}, report.LogLine{Time: now, Message: "other"})
+	want := []report.LogLine{
+		{Time: now.Add(2 * time.Second), Message: "... 2 earlier lines dropped"},
+		{Time: now.Add(2 * time.Second), Message: "line 2"},
+		{Time: now.Add(3 * time.Second), Message: "line 3"},
+		{Time: now.Add(4 * time.Second), Message: "line 4"},
+	}
+	assert.Equal(t, want, captures.Lines(TestKey{Name: "test"}))
+	assert.Equal(t, want, captures.Take(TestKey{Name: "test"}))
+	assert.Nil(t, captures.Lines(TestKey{Name: "test"}))
+	assert.Equal(t, []report.LogLine{{Time: now, Message: "other"}}, captures.Lines(TestKey{Name: "other"}))
+	captures.Clear(TestKey{Name: "other"})
+	assert.Nil(t, captures.Lines(TestKey{Name: "other"}))
+	assert.Nil(t, captures.Lines(TestKey{Name: "missing"}))
+	// not full yet
+	captures.add(TestKey{Name: "test"}, report.LogLine{Time: now, Message: "again"})
+	assert.Equal(t, []report.LogLine{{Time: now, Message: "again"}}, captures.Lines(TestKey{Name: "test"}))
+}
+
+func TestCapturesWithMaxSize(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		maxSize int
+		logs    []string
+		want    []report.LogLine
+	}{{
+		name:    "unlimited",
+		maxSize: 0,
+		logs:    []string{eraser + "first", "\x1b[32msecond\x1b[0m"},
+		want: []report.LogLine{
+			{Time: now, Message: "first"},
+			{Time: now, Message: "second"},
+		},
+	}, {
+		name:    "truncated",
+		maxSize: 10,
+		logs:    []string{"aaaa", "bbbb", "cccc", "dddd"},
+		want: []report.LogLine{
+			{Time: now, Message: "... 2 earlier lines dropped"},
+			{Time: now, Message: "cccc"},
+			{Time: now, Message: "dddd"},
+		},
+	}, {
+		name:    "single line over limit",
+		maxSize: 2,
+		logs:    []string{"aaaa"},
+		want: []report.LogLine{
+			{Time: now, Message: "aaaa"},
+		},
+	}, {
+		name:    "empty",
+		maxSize: 10,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			captures := NewCapturesWithMaxSize(tt.maxSize)
+			for _, log := range tt.logs {
+				captures.add(TestKey{Name: "test"}, report.LogLine{Time: now, Message: log})
+			}
+			assert.Equal(t, tt.want, captures.Lines(TestKey{Name: "test"}))
+		})
+	}
+}
+
+func TestCaptures_Logger(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	captures := NewCaptures(0)
+	mockT := &tlogging.FakeTLogger{}
+	// lines are captured even when the console output is disabled
+	Failure(NewLogger(mockT, fakeClock, "test", "step", WithCapture(captures), WithColor(ColorAlways), WithoutText()), Assert, ErrorStatus, s("boom"))
+	assert.Empty(t, mockT.Messages)
+	// captured lines are never colored, markers keep the severity
+	assert.Equal(t, []report.LogLine{{
+		Time:    fakeClock.Now(),
+		Message: "[fail] | 10:30:00 | test | step | ASSERT    | ERROR |\nboom",
+	}}, captures.Lines(TestKey{Name: "test"}))
+}
+
+func TestCaptures_Concurrent(t *testing.T) {
+	captures := NewCaptures(50)
+	fakeClock := tclock.NewFakePassiveClock(time.Now())
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			test := fmt.Sprintf("test-%d", i)
+			l := NewLogger(&tlogging.FakeTLogger{}, fakeClock, test, "step", WithCapture(captures))
+			for j := 0; j < 100; j++ {
+				l.Log(Script, LogStatus, nil, s(fmt.Sprintf("%s line %d", test, j)))
+			}
+			lines := captures.Take(TestKey{Name: test})
+			assert.Len(t, lines, 51)
+			// no cross-talk between tests, the most recent lines are kept in order
+			for k, line := range lines[1:] {
+				assert.True(t, strings.HasSuffix(line.Message, fmt.Sprintf("| %s | step | SCRIPT    | LOG   |\n%s line %d", test, test, 50+k)))
+			}
+		}(i)
+	}
+	wg.Wait()
+}
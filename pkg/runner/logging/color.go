@@ -0,0 +1,85 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/term"
+)
+
+// ColorMode determines whether log lines are colored.
+type ColorMode string
+
+const (
+	// ColorAuto colors log lines unless the NO_COLOR environment variable is set, stdout is not a terminal or the terminal
+	// doesn't render escape sequences, like Windows consoles older than Windows 10.
+	ColorAuto ColorMode = "auto"
+	// ColorAlways always colors log lines.
+	ColorAlways ColorMode = "always"
+	// ColorNever never colors log lines.
+	ColorNever ColorMode = "never"
+)
+
+// NoColorEnv is the environment variable disabling colors in auto mode when set to a non empty value, see https://no-color.org.
+const NoColorEnv = "NO_COLOR"
+
+var (
+	// lookupEnv, stdoutIsTerminal and stdoutRendersColors detect the environment in auto mode, they can be overridden in tests.
+	lookupEnv        = os.LookupEnv
+	stdoutIsTerminal = func() bool { return term.IsTerminal(int(os.Stdout.Fd())) }
+	// stdoutRendersColors enables the rendering of escape sequences by the terminal of stdout where it must be, like
+	// Windows consoles, and returns whether it renders them. The terminal is only set up once.
+	stdoutRendersColors = sync.OnceValue(enableVirtualTerminal)
+	colorMode           atomic.Value
+)
+
+func init() {
+	colorMode.Store(ColorAuto)
+}
+
+// SupportedColorModes returns the supported color modes.
+func SupportedColorModes() []string {
+	return []string{string(ColorAuto), string(ColorAlways), string(ColorNever)}
+}
+
+// ParseColorMode parses a color mode name.
+func ParseColorMode(name string) (ColorMode, error) {
+	for _, mode := range SupportedColorModes() {
+		if strings.EqualFold(name, mode) {
+			return ColorMode(mode), nil
+		}
+	}
+	return ColorAuto, fmt.Errorf("invalid color mode %q (auto|always|never)", name)
+}
+
+// Enabled returns true if log lines are colored in this mode.
+func (m ColorMode) Enabled() bool {
+	switch m {
+	case ColorAlways:
+		// the terminal may render colors once set up, they are enabled either way
+		if stdoutIsTerminal() {
+			stdoutRendersColors()
+		}
+		return true
+	case ColorNever:
+		return false
+	default:
+		if value, ok := lookupEnv(NoColorEnv); ok && value != "" {
+			return false
+		}
+		return stdoutIsTerminal() && stdoutRendersColors()
+	}
+}
+
+// SetColorMode sets the color mode of the loggers created afterwards without the WithColor option.
+func SetColorMode(mode ColorMode) {
+	colorMode.Store(mode)
+}
+
+// GetColorMode returns the color mode of the loggers created without the WithColor option.
+func GetColorMode() ColorMode {
+	return colorMode.Load().(ColorMode)
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package logging
+
+// enableVirtualTerminal returns true, terminals render escape sequences outside of Windows.
+func enableVirtualTerminal() bool {
+	return true
+}
@@ -0,0 +1,152 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	creport "github.com/kyverno/chainsaw/pkg/report"
+	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
+	"github.com/stretchr/testify/assert"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+func TestColorMode_Enabled(t *testing.T) {
+	defer func(f func(string) (string, bool), g, h func() bool) {
+		lookupEnv, stdoutIsTerminal, stdoutRendersColors = f, g, h
+	}(lookupEnv, stdoutIsTerminal, stdoutRendersColors)
+	tests := []struct {
+		name     string
+		mode     ColorMode
+		env      map[string]string
+		terminal bool
+		// unrendered is a terminal printing escape sequences raw, like old Windows consoles
+		unrendered bool
+		want       bool
+		wantSetup  bool
+	}{{
+		name:      "auto on terminal",
+		mode:      ColorAuto,
+		terminal:  true,
+		want:      true,
+		wantSetup: true,
+	}, {
+		name:       "auto on terminal not rendering colors",
+		mode:       ColorAuto,
+		terminal:   true,
+		unrendered: true,
+		wantSetup:  true,
+	}, {
+		name: "auto not on terminal",
+		mode: ColorAuto,
+	}, {
+		name:     "auto with NO_COLOR",
+		mode:     ColorAuto,
+		env:      map[string]string{NoColorEnv: "1"},
+		terminal: true,
+	}, {
+		name:      "auto with empty NO_COLOR",
+		mode:      ColorAuto,
+		env:       map[string]string{NoColorEnv: ""},
+		terminal:  true,
+		want:      true,
+		wantSetup: true,
+	}, {
+		name: "always",
+		mode: ColorAlways,
+		env:  map[string]string{NoColorEnv: "1"},
+		want: true,
+	}, {
+		name:       "always on terminal not rendering colors",
+		mode:       ColorAlways,
+		terminal:   true,
+		unrendered: true,
+		want:       true,
+		wantSetup:  true,
+	}, {
+		name:     "never",
+		mode:     ColorNever,
+		terminal: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookupEnv = func(name string) (string, bool) {
+				value, ok := tt.env[name]
+				return value, ok
+			}
+			stdoutIsTerminal = func() bool { return tt.terminal }
+			setup := false
+			stdoutRendersColors = func() bool {
+				setup = true
+				return !tt.unrendered
+			}
+			assert.Equal(t, tt.want, tt.mode.Enabled())
+			assert.Equal(t, tt.wantSetup, setup)
+		})
+	}
+}
+
+func TestParseColorMode(t *testing.T) {
+	for _, name := range []string{"auto", "always", "never", "Always"} {
+		mode, err := ParseColorMode(name)
+		assert.NoError(t, err)
+		assert.Equal(t, ColorMode(strings.ToLower(name)), mode)
+	}
+	_, err := ParseColorMode("sometimes")
+	assert.Error(t, err)
+}
+
+func Test_logger_Color(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Now())
+	enabled := color.New(color.FgRed)
+	enabled.EnableColor()
+	disabled := color.New(color.FgRed)
+	disabled.DisableColor()
+	tests := []struct {
+		name    string
+		mode    ColorMode
+		color   *color.Color
+		wantESC bool
+	}{{
+		name:  "never with enabled color",
+		mode:  ColorNever,
+		color: enabled,
+	}, {
+		name:  "never without color",
+		mode:  ColorNever,
+		color: nil,
+	}, {
+		name:    "always with enabled color",
+		mode:    ColorAlways,
+		color:   enabled,
+		wantESC: true,
+	}, {
+		name:    "always with disabled color",
+		mode:    ColorAlways,
+		color:   disabled,
+		wantESC: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockT := &tlogging.FakeTLogger{}
+			NewLogger(mockT, fakeClock, "test", "step", WithColor(tt.mode)).WithResource(nil).Log(Apply, ErrorStatus, tt.color, s("message"))
+			assert.Len(t, mockT.Messages, 1)
+			assert.Equal(t, tt.wantESC, strings.Contains(mockT.Messages[0], "\x1b"))
+			// columns stay aligned whether colors are enabled or not
+			assert.Contains(t, creport.StripANSI(mockT.Messages[0]), "| test | step | APPLY     | ERROR |")
+		})
+	}
+	// forcing colors doesn't change the shared color
+	assert.Equal(t, "message", disabled.Sprint("message"))
+}
+
+func TestSetColorMode(t *testing.T) {
+	defer SetColorMode(GetColorMode())
+	SetColorMode(ColorNever)
+	assert.False(t, NewLogger(&tlogging.FakeTLogger{}, tclock.NewFakePassiveClock(time.Now()), "test", "step").(*logger).colors)
+	SetColorMode(ColorAlways)
+	assert.True(t, NewLogger(&tlogging.FakeTLogger{}, tclock.NewFakePassiveClock(time.Now()), "test", "step").(*logger).colors)
+	// the option takes precedence
+	assert.False(t, NewLogger(&tlogging.FakeTLogger{}, tclock.NewFakePassiveClock(time.Now()), "test", "step", WithColor(ColorNever)).(*logger).colors)
+}
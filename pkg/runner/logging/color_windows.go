@@ -0,0 +1,28 @@
+package logging
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// enableVirtualTerminal enables the processing of escape sequences by the console stdout writes to, available since
+// Windows 10. Older consoles print them raw, colors are then only enabled for consoles known to render them anyway,
+// like ConEmu or those with ANSICON loaded.
+func enableVirtualTerminal() bool {
+	handle := windows.Handle(os.Stdout.Fd())
+	var mode uint32
+	if err := windows.GetConsoleMode(handle, &mode); err == nil {
+		if mode&windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING != 0 {
+			return true
+		}
+		if err := windows.SetConsoleMode(handle, mode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING); err == nil {
+			return true
+		}
+	}
+	if value, ok := lookupEnv("ConEmuANSI"); ok && value == "ON" {
+		return true
+	}
+	_, ok := lookupEnv("ANSICON")
+	return ok
+}
@@ -0,0 +1,109 @@
+package logging
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/width"
+)
+
+// ellipsis replaces the middle of names truncated to fit their column.
+const ellipsis = "…"
+
+// ColumnWidths are the widths of the test, step and operation name columns of human readable log lines.
+// A zero width leaves the column as is, names are then neither padded nor truncated.
+type ColumnWidths struct {
+	Test      int
+	Step      int
+	Operation int
+}
+
+// DefaultColumnWidths returns widths fitting log lines about a resource in a 160 columns terminal.
+func DefaultColumnWidths() ColumnWidths {
+	return ColumnWidths{
+		Test:      32,
+		Step:      16,
+		Operation: 24,
+	}
+}
+
+// ParseColumnWidths parses column widths, either "default" or a comma separated list of overrides
+// of the default widths like "test=40,step=20". Keys are test, step and operation.
+func ParseColumnWidths(value string) (ColumnWidths, error) {
+	widths := DefaultColumnWidths()
+	if value == "default" {
+		return widths, nil
+	}
+	for _, override := range strings.Split(value, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(override), "=")
+		if !ok {
+			return ColumnWidths{}, fmt.Errorf("invalid column width %q, expected <column>=<width>", override)
+		}
+		w, err := strconv.Atoi(value)
+		if err != nil || w < 1 {
+			return ColumnWidths{}, fmt.Errorf("invalid column width %q, the width must be a positive integer", override)
+		}
+		switch key {
+		case "test":
+			widths.Test = w
+		case "step":
+			widths.Step = w
+		case "operation":
+			widths.Operation = w
+		default:
+			return ColumnWidths{}, fmt.Errorf("invalid column %q (test|step|operation)", key)
+		}
+	}
+	return widths, nil
+}
+
+// FitColumn pads name to the display width w, or truncates its middle when it is wider, keeping most of the end
+// which usually tells similar names apart. Wide runes take two columns. FitColumn returns name unchanged if w is zero.
+func FitColumn(name string, w int) string {
+	if w <= 0 {
+		return name
+	}
+	runes := []rune(name)
+	if size := stringWidth(runes); size <= w {
+		return name + strings.Repeat(" ", w-size)
+	}
+	if w == 1 {
+		return ellipsis
+	}
+	headBudget := (w - 1) / 3
+	tailBudget := w - 1 - headBudget
+	var head, tail int
+	size := 0
+	for head < len(runes) && size+runeWidth(runes[head]) <= headBudget {
+		size += runeWidth(runes[head])
+		head++
+	}
+	// the tail gets what the head left, a wide rune may not fit in the head
+	tailBudget += headBudget - size
+	size = 0
+	for tail < len(runes)-head && size+runeWidth(runes[len(runes)-1-tail]) <= tailBudget {
+		size += runeWidth(runes[len(runes)-1-tail])
+		tail++
+	}
+	fitted := string(runes[:head]) + ellipsis + string(runes[len(runes)-tail:])
+	return fitted + strings.Repeat(" ", w-stringWidth([]rune(fitted)))
+}
+
+// runeWidth returns the number of terminal columns taken by r.
+func runeWidth(r rune) int {
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	default:
+		return 1
+	}
+}
+
+func stringWidth(runes []rune) int {
+	size := 0
+	for _, r := range runes {
+		size += runeWidth(r)
+	}
+	return size
+}
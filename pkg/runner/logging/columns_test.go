@@ -0,0 +1,139 @@
+package logging
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseColumnWidths(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    ColumnWidths
+		wantErr bool
+	}{{
+		name:  "default",
+		value: "default",
+		want:  DefaultColumnWidths(),
+	}, {
+		name:  "overrides",
+		value: "test=40, operation=10",
+		want:  ColumnWidths{Test: 40, Step: 16, Operation: 10},
+	}, {
+		name:    "unknown column",
+		value:   "status=5",
+		wantErr: true,
+	}, {
+		name:    "not a number",
+		value:   "test=wide",
+		wantErr: true,
+	}, {
+		name:    "zero",
+		value:   "step=0",
+		wantErr: true,
+	}, {
+		name:    "missing width",
+		value:   "test",
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseColumnWidths(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+	// the defaults leave room for the resource in a 160 columns terminal
+	widths := DefaultColumnWidths()
+	prefix := len("FAIL | 15:04:05 |  |  |  | APPLY     | ERROR | ")
+	assert.LessOrEqual(t, prefix+widths.Test+widths.Step+widths.Operation, 120)
+}
+
+func TestFitColumn(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		width int
+		want  string
+	}{{
+		name:  "no width",
+		value: "quick-start",
+		want:  "quick-start",
+	}, {
+		name:  "padded",
+		value: "step-1",
+		width: 8,
+		want:  "step-1  ",
+	}, {
+		name:  "exact",
+		value: "step-1",
+		width: 6,
+		want:  "step-1",
+	}, {
+		name:  "truncated keeps the suffix",
+		value: "very-long-test-name-for-deployments-01",
+		width: 16,
+		want:  "very-…oyments-01",
+	}, {
+		name:  "similar names stay distinct",
+		value: "very-long-test-name-for-deployments-02",
+		width: 16,
+		want:  "very-…oyments-02",
+	}, {
+		name:  "one column",
+		value: "quick-start",
+		width: 1,
+		want:  "…",
+	}, {
+		name:  "multi byte runes are padded by runes",
+		value: "tést-ünïcode",
+		width: 14,
+		want:  "tést-ünïcode  ",
+	}, {
+		name:  "multi byte runes are truncated by runes",
+		value: "tést-ünïcode-ñame",
+		width: 10,
+		want:  "tés…e-ñame",
+	}, {
+		name:  "wide runes take two columns",
+		value: "テスト",
+		width: 8,
+		want:  "テスト  ",
+	}, {
+		name:  "wide runes truncated",
+		value: "テストのステップ名",
+		width: 9,
+		want:  "テ…ップ名",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FitColumn(tt.value, tt.width)
+			assert.Equal(t, tt.want, got)
+			if tt.width > 0 {
+				assert.Equal(t, tt.width, stringWidth([]rune(got)))
+			}
+			// the same name always fits the same way
+			assert.Equal(t, got, FitColumn(tt.value, tt.width))
+		})
+	}
+}
+
+func TestFormatText_Columns(t *testing.T) {
+	entry := Entry{
+		Test:            "very-long-test-name-for-deployments-01",
+		Step:            "step-1  ",
+		Operation:       Apply,
+		Status:          OkStatus,
+		TimestampLayout: NoTimestamp,
+		Columns:         ColumnWidths{Test: 16, Step: 10, Operation: 8},
+	}
+	// the operation column is kept outside operations
+	assert.Equal(t, "| very-…oyments-01 | step-1     |          | APPLY     | OK    |", FormatText(entry, false))
+	entry.OperationName = "Apply configmap.yaml"
+	assert.Equal(t, "| very-…oyments-01 | step-1     | Ap….yaml | APPLY     | OK    |", FormatText(entry, false))
+}
@@ -2,19 +2,83 @@ package logging
 
 import (
 	"context"
+	"time"
+
+	"k8s.io/utils/clock"
 )
 
-type contextKey struct{}
+type (
+	contextKey      struct{}
+	quietConsoleKey struct{}
+	dedupeKey       struct{}
+	waitIntervalKey struct{}
+)
 
+// FromContext returns the logger stored in the context, or a logger dropping everything when there is none.
+// Callers can log without checking for nil.
 func FromContext(ctx context.Context) Logger {
+	if logger, ok := fromContext(ctx); ok {
+		return logger
+	}
+	return NoOp()
+}
+
+// fromContext returns the logger stored in the context, if any.
+func fromContext(ctx context.Context) (Logger, bool) {
 	if ctx != nil {
-		if v, ok := ctx.Value(contextKey{}).(Logger); ok {
-			return v
+		if v, ok := ctx.Value(contextKey{}).(Logger); ok && v != nil {
+			return v, true
 		}
 	}
-	return nil
+	return nil, false
 }
 
+// IntoContext stores logger in the context, derived loggers can be stored again in a child context.
 func IntoContext(ctx context.Context, logger Logger) context.Context {
 	return context.WithValue(ctx, contextKey{}, logger)
 }
+
+// WithQuietConsole marks the context so that test processors don't write test logs to the console.
+func WithQuietConsole(ctx context.Context) context.Context {
+	return context.WithValue(ctx, quietConsoleKey{}, true)
+}
+
+// IsQuietConsole returns true if test logs should not be written to the console.
+func IsQuietConsole(ctx context.Context) bool {
+	if ctx != nil {
+		if v, ok := ctx.Value(quietConsoleKey{}).(bool); ok {
+			return v
+		}
+	}
+	return false
+}
+
+// WithDedupe marks the context so that operations collapse their repeated log lines, the time they span is measured with clock.
+func WithDedupe(ctx context.Context, clock clock.PassiveClock) context.Context {
+	return context.WithValue(ctx, dedupeKey{}, clock)
+}
+
+// WithWaitInterval stores the interval at which polling operations log that they are still waiting, zero disables it.
+func WithWaitInterval(ctx context.Context, interval time.Duration) context.Context {
+	return context.WithValue(ctx, waitIntervalKey{}, interval)
+}
+
+// WaitInterval returns the interval stored by WithWaitInterval, or DefaultWaitInterval if none was stored.
+func WaitInterval(ctx context.Context) time.Duration {
+	if ctx != nil {
+		if v, ok := ctx.Value(waitIntervalKey{}).(time.Duration); ok {
+			return v
+		}
+	}
+	return DefaultWaitInterval
+}
+
+// DedupeClock returns the clock stored by WithDedupe, or nil if repeated log lines should not be collapsed.
+func DedupeClock(ctx context.Context) clock.PassiveClock {
+	if ctx != nil {
+		if v, ok := ctx.Value(dedupeKey{}).(clock.PassiveClock); ok {
+			return v
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/report"
+	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+func TestFromContext_NoOp(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+	}{{
+		name: "nil",
+	}, {
+		name: "empty",
+		ctx:  context.Background(),
+	}, {
+		name: "nil logger",
+		ctx:  IntoContext(context.Background(), nil),
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := FromContext(tt.ctx)
+			assert.Equal(t, NoOp(), logger)
+			// library consumers can log without checking for nil
+			assert.NotPanics(t, func() {
+				logger.Log(Apply, OkStatus, nil, s("dropped"))
+				logger.WithResource(&unstructured.Unstructured{}).LogLevel(ErrorLevel, Apply, ErrorStatus, nil)
+				logger.WithOperation("Apply", report.OperationTypeApply).Log(Apply, OkStatus, nil)
+				Success(logger, Apply, OkStatus)
+				OperationStarted(logger).Log(Apply, OkStatus, nil)
+				Log(tt.ctx, Apply, OkStatus, nil)
+				LogLevel(tt.ctx, ErrorLevel, Apply, ErrorStatus, nil)
+			})
+			assert.Equal(t, NoOp(), logger.WithResource(nil))
+			assert.Equal(t, NoOp(), logger.WithOperation("Apply", report.OperationTypeApply))
+		})
+	}
+}
+
+func TestIntoContext_Derived(t *testing.T) {
+	mockT := &tlogging.FakeTLogger{}
+	parent := NewLogger(mockT, tclock.NewFakePassiveClock(time.Now()), "test", "step", WithTimestampLayout(NoTimestamp))
+	ctx := IntoContext(context.Background(), parent)
+	// derived loggers are stored again in child contexts
+	child := IntoContext(ctx, FromContext(ctx).WithOperation("Apply", report.OperationTypeApply))
+	FromContext(child).Log(Apply, OkStatus, nil)
+	FromContext(ctx).Log(Apply, OkStatus, nil)
+	assert.Equal(t, []string{
+		eraser + "| test | step | Apply | APPLY     | OK    |",
+		eraser + "| test | step | APPLY     | OK    |",
+	}, mockT.Messages)
+}
@@ -0,0 +1,64 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kyverno/chainsaw/pkg/report"
+)
+
+// ContinuationFormat determines how the lines of multi-line messages, like diffs, are rendered in human readable output.
+type ContinuationFormat string
+
+const (
+	// NoContinuation prints the lines of messages as is, below the prefix of the log line.
+	NoContinuation ContinuationFormat = "none"
+	// PrefixContinuation repeats the prefix of the log line, its time, test, step, operation and status, on every line of messages.
+	PrefixContinuation ContinuationFormat = "prefix"
+	// IndentContinuation indents the lines of messages by the width of the prefix of the log line, followed by a | marker.
+	IndentContinuation ContinuationFormat = "indent"
+)
+
+// continuationMarker ends the indentation of the lines of messages, it is aligned with the end of the prefix.
+const continuationMarker = "|"
+
+// SupportedContinuationFormats returns the supported continuation formats.
+func SupportedContinuationFormats() []string {
+	return []string{string(NoContinuation), string(PrefixContinuation), string(IndentContinuation)}
+}
+
+// ParseContinuationFormat parses a continuation format name, an empty name is the none format.
+func ParseContinuationFormat(name string) (ContinuationFormat, error) {
+	if name == "" {
+		return NoContinuation, nil
+	}
+	for _, format := range SupportedContinuationFormats() {
+		if strings.EqualFold(name, format) {
+			return ContinuationFormat(format), nil
+		}
+	}
+	return NoContinuation, fmt.Errorf("invalid continuation format %q (none|prefix|indent)", name)
+}
+
+// formatContinuation renders the lines of message in format, prefix is the prefix of the log line.
+// Lines ending with CRLF lose the carriage return and trailing line breaks are dropped, they would only repeat the prefix.
+func formatContinuation(message string, prefix string, format ContinuationFormat) string {
+	switch format {
+	case PrefixContinuation:
+	case IndentContinuation:
+		// the prefix may be colored, escape sequences take no room on the terminal
+		prefix = strings.Repeat(" ", max(utf8.RuneCountInString(report.StripANSI(prefix))-len(continuationMarker), 0)) + continuationMarker
+	default:
+		return message
+	}
+	lines := strings.Split(strings.TrimRight(message, "\r\n"), "\n")
+	for i, line := range lines {
+		if line = strings.TrimSuffix(line, "\r"); line != "" {
+			lines[i] = prefix + " " + line
+		} else {
+			lines[i] = prefix
+		}
+	}
+	return strings.Join(lines, "\n")
+}
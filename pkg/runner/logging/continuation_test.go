@@ -0,0 +1,132 @@
+package logging
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/report"
+	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
+	"github.com/stretchr/testify/assert"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+func TestParseContinuationFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		want    ContinuationFormat
+		wantErr bool
+	}{{
+		name: "empty",
+		want: NoContinuation,
+	}, {
+		name:   "prefix",
+		format: "prefix",
+		want:   PrefixContinuation,
+	}, {
+		name:   "case insensitive",
+		format: "Indent",
+		want:   IndentContinuation,
+	}, {
+		name:    "unsupported",
+		format:  "wrap",
+		want:    NoContinuation,
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseContinuationFormat(tt.format)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFormatText_Continuation(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  ContinuationFormat
+		message string
+		style   Style
+		want    string
+	}{{
+		name:    "none",
+		format:  NoContinuation,
+		message: "first\r\nsecond\n",
+		want:    "| 10:30:00 | test | step | ASSERT    | ERROR |\nfirst\r\nsecond\n",
+	}, {
+		name:    "prefix",
+		format:  PrefixContinuation,
+		message: "first\nsecond",
+		want:    "| 10:30:00 | test | step | ASSERT    | ERROR |\n| 10:30:00 | test | step | ASSERT    | ERROR | first\n| 10:30:00 | test | step | ASSERT    | ERROR | second",
+	}, {
+		name:    "prefix with marker",
+		format:  PrefixContinuation,
+		message: "first\nsecond",
+		style:   GetPalette().Failure,
+		want:    "FAIL | 10:30:00 | test | step | ASSERT    | ERROR |\nFAIL | 10:30:00 | test | step | ASSERT    | ERROR | first\nFAIL | 10:30:00 | test | step | ASSERT    | ERROR | second",
+	}, {
+		name:    "indent",
+		format:  IndentContinuation,
+		message: "first\nsecond",
+		want:    "| 10:30:00 | test | step | ASSERT    | ERROR |\n                                             | first\n                                             | second",
+	}, {
+		name:    "crlf",
+		format:  IndentContinuation,
+		message: "first\r\nsecond\r\n",
+		want:    "| 10:30:00 | test | step | ASSERT    | ERROR |\n                                             | first\n                                             | second",
+	}, {
+		name:    "trailing and empty lines",
+		format:  PrefixContinuation,
+		message: "first\n\nsecond\n\n\n",
+		want:    "| 10:30:00 | test | step | ASSERT    | ERROR |\n| 10:30:00 | test | step | ASSERT    | ERROR | first\n| 10:30:00 | test | step | ASSERT    | ERROR |\n| 10:30:00 | test | step | ASSERT    | ERROR | second",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := Entry{
+				Time:         time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC),
+				Test:         "test",
+				Step:         "step",
+				Operation:    Assert,
+				Status:       ErrorStatus,
+				Style:        tt.style,
+				Message:      tt.message,
+				Continuation: tt.format,
+			}
+			assert.Equal(t, tt.want, FormatText(entry, false))
+		})
+	}
+}
+
+func TestFormatText_ContinuationColors(t *testing.T) {
+	entry := Entry{
+		Time:         time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC),
+		Test:         "test",
+		Step:         "step",
+		Operation:    Assert,
+		Status:       ErrorStatus,
+		Style:        GetPalette().Failure,
+		Message:      "first",
+		Continuation: IndentContinuation,
+	}
+	// escape sequences don't shift the continuation marker
+	assert.Equal(t, "| 10:30:00 | test | step | ASSERT    | ERROR |\n                                             | first", report.StripANSI(FormatText(entry, true)))
+}
+
+func TestCaptures_Continuation(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	captures := NewCaptures(0)
+	mockT := &tlogging.FakeTLogger{}
+	logger := NewLogger(mockT, fakeClock, "test", "step", WithCapture(captures), WithContinuation(PrefixContinuation))
+	logger.Log(Assert, ErrorStatus, nil, s("first\r\nsecond\n"))
+	assert.Equal(t, []string{"\b\b\b\b\b\b\b\b\b| 10:30:00 | test | step | ASSERT    | ERROR |\n| 10:30:00 | test | step | ASSERT    | ERROR | first\n| 10:30:00 | test | step | ASSERT    | ERROR | second"}, mockT.Messages)
+	// captures keep the message as logged, in a single line
+	assert.Equal(t, []report.LogLine{{
+		Time:    fakeClock.Now(),
+		Message: "| 10:30:00 | test | step | ASSERT    | ERROR |\nfirst\r\nsecond\n",
+	}}, captures.Lines(TestKey{Name: "test"}))
+}
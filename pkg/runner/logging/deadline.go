@@ -0,0 +1,57 @@
+package logging
+
+import (
+	"fmt"
+	"time"
+)
+
+// WithOperationTimeout returns a logger whose operation must complete within timeout, from now on the clock of l.
+// Heartbeats of waiting operations and TimeoutProgress render the elapsed time against it, a timeout of zero or less
+// removes it. Loggers not created with NewLogger, NewWriterLogger or NewSinkLogger are returned unchanged.
+func WithOperationTimeout(l Logger, timeout time.Duration) Logger {
+	if l, ok := l.(*logger); ok {
+		c := *l
+		c.operationTimeout, c.operationDeadline = 0, time.Time{}
+		if timeout > 0 {
+			c.operationTimeout, c.operationDeadline = timeout, l.clock.Now().Add(timeout)
+		}
+		return &c
+	}
+	return l
+}
+
+// TimeoutProgress returns the time elapsed in the operation of l against its timeout at the time of the clock of l,
+// like "elapsed 2m10s / timeout 5m (2m50s remaining)", or an empty string when no timeout applies, see WithOperationTimeout.
+func TimeoutProgress(l Logger) string {
+	deadline, timeout, ok := operationDeadline(l)
+	if !ok {
+		return ""
+	}
+	remaining := deadline.Sub(loggerClock(l).Now())
+	return FormatTimeoutProgress(l, timeout-remaining, timeout)
+}
+
+// FormatTimeoutProgress renders elapsed against timeout with the durations of l, see TimeoutProgress.
+// The remaining time doesn't go below zero once the timeout has elapsed.
+func FormatTimeoutProgress(l Logger, elapsed time.Duration, timeout time.Duration) string {
+	remaining := timeout - elapsed
+	if remaining < 0 {
+		remaining = 0
+	}
+	return fmt.Sprintf("elapsed %s / timeout %s (%s remaining)",
+		formatDuration(l, elapsed, formatWait),
+		formatDuration(l, timeout, formatWait),
+		formatDuration(l, remaining, formatWait),
+	)
+}
+
+// operationDeadline returns the deadline and the timeout of the operation of l, if any, see WithOperationTimeout.
+func operationDeadline(l Logger) (time.Time, time.Duration, bool) {
+	switch l := l.(type) {
+	case *logger:
+		return l.operationDeadline, l.operationTimeout, l.operationTimeout > 0
+	case *Deduper:
+		return operationDeadline(l.logger)
+	}
+	return time.Time{}, 0, false
+}
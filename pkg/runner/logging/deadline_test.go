@@ -0,0 +1,95 @@
+package logging
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+func TestTimeoutProgress(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		elapsed time.Duration
+		want    string
+	}{{
+		name:    "no timeout",
+		elapsed: time.Minute,
+		want:    "",
+	}, {
+		name:    "started",
+		timeout: 5 * time.Minute,
+		want:    "elapsed 0s / timeout 5m (5m remaining)",
+	}, {
+		name:    "running",
+		timeout: 5 * time.Minute,
+		elapsed: 2*time.Minute + 10*time.Second,
+		want:    "elapsed 2m10s / timeout 5m (2m50s remaining)",
+	}, {
+		name:    "rounded",
+		timeout: 30 * time.Second,
+		elapsed: 12*time.Second + 400*time.Millisecond,
+		want:    "elapsed 12s / timeout 30s (18s remaining)",
+	}, {
+		name:    "elapsed",
+		timeout: 5 * time.Minute,
+		elapsed: 6 * time.Minute,
+		want:    "elapsed 6m / timeout 5m (0s remaining)",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+			logger := WithOperationTimeout(NewSinkLogger(sinkFunc(func(Entry) error { return nil }), fakeClock, "test", "step"), tt.timeout)
+			fakeClock.SetTime(fakeClock.Now().Add(tt.elapsed))
+			assert.Equal(t, tt.want, TimeoutProgress(logger))
+			// derived loggers keep the deadline
+			assert.Equal(t, tt.want, TimeoutProgress(Dedupe(logger.WithResource(nil), fakeClock)))
+		})
+	}
+}
+
+func TestWithOperationTimeout(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	logger := NewSinkLogger(sinkFunc(func(Entry) error { return nil }), fakeClock, "test", "step")
+	timed := WithOperationTimeout(logger, time.Minute)
+	// the original logger is left unchanged
+	assert.Equal(t, "", TimeoutProgress(logger))
+	// the deadline is measured from the time the timeout is set
+	fakeClock.SetTime(fakeClock.Now().Add(20 * time.Second))
+	assert.Equal(t, "elapsed 0s / timeout 1m (1m remaining)", TimeoutProgress(WithOperationTimeout(logger, time.Minute)))
+	assert.Equal(t, "elapsed 20s / timeout 1m (40s remaining)", TimeoutProgress(timed))
+	// no timeout removes it
+	assert.Equal(t, "", TimeoutProgress(WithOperationTimeout(timed, 0)))
+	// deterministic loggers render durations as placeholders
+	deterministic := WithOperationTimeout(NewSinkLogger(sinkFunc(func(Entry) error { return nil }), fakeClock, "test", "step", WithDeterministic()), time.Minute)
+	assert.Equal(t, "elapsed <duration> / timeout <duration> (<duration> remaining)", TimeoutProgress(deterministic))
+	// loggers of unknown types are returned unchanged
+	assert.Equal(t, NoOp(), WithOperationTimeout(NoOp(), time.Minute))
+	assert.Equal(t, "", TimeoutProgress(NoOp()))
+}
+
+func TestWaitHeartbeat_OperationTimeout(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	var entries []Entry
+	logger := NewSinkLogger(sinkFunc(func(entry Entry) error {
+		entries = append(entries, entry)
+		return nil
+	}), fakeClock, "test", "step")
+	logger = WithOperationTimeout(logger, 5*time.Minute)
+	// the heartbeat starts after the operation did
+	fakeClock.SetTime(fakeClock.Now().Add(10 * time.Second))
+	// the deadline of the context is ignored, the timeout of the operation is the one enforced
+	ctx, cancel := context.WithTimeout(WithWaitInterval(context.Background(), time.Minute), time.Hour)
+	defer cancel()
+	heartbeat := StartWaitHeartbeat(ctx, logger, Assert)
+	fakeClock.SetTime(fakeClock.Now().Add(time.Minute))
+	heartbeat.Beat("v1/Pod @ default/nginx", nil)
+	fakeClock.SetTime(fakeClock.Now().Add(time.Minute))
+	heartbeat.Beat("v1/Pod @ default/nginx", nil)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "still waiting for v1/Pod @ default/nginx, elapsed 1m10s / timeout 5m (3m50s remaining)", entries[0].Message)
+	assert.Equal(t, "still waiting for v1/Pod @ default/nginx, elapsed 2m10s / timeout 5m (2m50s remaining)", entries[1].Message)
+}
@@ -0,0 +1,164 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/report"
+	"github.com/kyverno/kyverno/ext/output/color"
+	"k8s.io/utils/clock"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Deduper is a Logger collapsing consecutive identical lines into one, followed by a line telling how many times it
+// was repeated once a different line is logged or the deduper is flushed. It is safe for concurrent use.
+// Loggers derived with WithResource, WithResources or WithFields share the deduper state, a line about another resource
+// or with other fields is a different line.
+// Loggers derived with WithOperation are not deduplicated, each operation is expected to dedupe its own logger.
+type Deduper struct {
+	logger   Logger
+	clock    clock.PassiveClock
+	resource ctrlclient.Object
+	// resources are set instead of resource when lines are about several resources
+	resources []ctrlclient.Object
+	fields    Fields
+	state     *dedupeState
+}
+
+type dedupeState struct {
+	lock sync.Mutex
+	last *dedupeLine
+}
+
+// dedupeLine is the last line logged, with the number of times it was repeated since.
+type dedupeLine struct {
+	key       string
+	logger    Logger
+	level     Level
+	operation Operation
+	status    Status
+	color     *color.Color
+	count     int
+	first     time.Time
+	last      time.Time
+}
+
+// Dedupe returns a Deduper writing to logger, the time repeated lines span is measured with clock.
+func Dedupe(logger Logger, clock clock.PassiveClock) *Deduper {
+	return &Deduper{logger: logger, clock: clock, state: &dedupeState{}}
+}
+
+func (d *Deduper) Log(operation Operation, status Status, color *color.Color, args ...fmt.Stringer) {
+	d.LogLevel(InfoLevel, operation, status, color, args...)
+}
+
+func (d *Deduper) LogLevel(level Level, operation Operation, status Status, color *color.Color, args ...fmt.Stringer) {
+	if !Enabled(level) {
+		return
+	}
+	key := d.key(level, operation, status, args...)
+	now := d.clock.Now()
+	d.state.lock.Lock()
+	defer d.state.lock.Unlock()
+	if last := d.state.last; last != nil && last.key == key {
+		last.count++
+		last.last = now
+		return
+	}
+	d.state.flush()
+	logger := d.withResources()
+	logger.LogLevel(level, operation, status, color, args...)
+	d.state.last = &dedupeLine{
+		key:       key,
+		logger:    logger,
+		level:     level,
+		operation: operation,
+		status:    status,
+		color:     color,
+		first:     now,
+		last:      now,
+	}
+}
+
+// key identifies a line, the test and step are those of the underlying logger.
+func (d *Deduper) key(level Level, operation Operation, status Status, args ...fmt.Stringer) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d|%s|%s|", level, operation, status)
+	if d.resource != nil {
+		b.WriteString(FormatResource(d.resource, FullResource))
+	}
+	for _, resource := range d.resources {
+		b.WriteString(FormatResource(resource, FullResource) + ",")
+	}
+	if len(d.fields) != 0 {
+		b.WriteString("|" + formatFields(d.fields))
+	}
+	for _, arg := range args {
+		b.WriteString("|")
+		b.WriteString(arg.String())
+	}
+	return b.String()
+}
+
+// Flush writes how many times the last line was repeated, typically when the operation ends.
+func (d *Deduper) Flush() {
+	d.state.lock.Lock()
+	defer d.state.lock.Unlock()
+	d.state.flush()
+	d.state.last = nil
+}
+
+func (s *dedupeState) flush() {
+	if last := s.last; last != nil && last.count != 0 {
+		times := "times"
+		if last.count == 1 {
+			times = "time"
+		}
+		last.logger.LogLevel(last.level, last.operation, last.status, last.color,
+			message(fmt.Sprintf("… repeated %d %s over %s", last.count, times, formatDuration(last.logger, last.last.Sub(last.first), roundDuration(time.Millisecond)))))
+		last.count = 0
+	}
+}
+
+func (d *Deduper) WithResource(resource ctrlclient.Object) Logger {
+	c := *d
+	c.resource, c.resources = resource, nil
+	return &c
+}
+
+func (d *Deduper) WithResources(resources ...ctrlclient.Object) Logger {
+	c := *d
+	c.resource, c.resources = withResources(resources)
+	return &c
+}
+
+func (d *Deduper) WithOperation(name string, operationType report.OperationType) Logger {
+	return d.withResources().WithOperation(name, operationType)
+}
+
+func (d *Deduper) WithFields(fields map[string]any) Logger {
+	c := *d
+	c.fields = d.fields.withFields(fields)
+	return &c
+}
+
+func (d *Deduper) WithField(key string, value any) Logger {
+	return d.WithFields(map[string]any{key: value})
+}
+
+// withResources returns the underlying logger with the resources and the fields of the deduper.
+func (d *Deduper) withResources() Logger {
+	logger := d.logger
+	switch {
+	case d.resource != nil:
+		logger = logger.WithResource(d.resource)
+	case len(d.resources) != 0:
+		logger = logger.WithResources(d.resources...)
+	}
+	if len(d.fields) != 0 {
+		logger = logger.WithFields(d.fields.Map())
+	}
+	return logger
+}
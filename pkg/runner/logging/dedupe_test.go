@@ -0,0 +1,85 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/report"
+	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+// dedupeLines returns a logger recording its lines as "<operation name> <operation> <status> <resource> <message>".
+func dedupeLines(fakeClock *tclock.FakeClock) (Logger, *[]string) {
+	var lines []string
+	sink := sinkFunc(func(entry Entry) error {
+		resource := ""
+		if entry.Resource != nil {
+			resource = FormatResource(entry.Resource, ShortResource)
+		}
+		lines = append(lines, strings.Join([]string{entry.OperationName, string(entry.Operation), string(entry.Status), resource, entry.Message}, " "))
+		return nil
+	})
+	return NewLogger(&tlogging.FakeTLogger{}, fakeClock, "test", "step", WithSink(sink), WithoutText()), &lines
+}
+
+func TestDedupe(t *testing.T) {
+	fakeClock := tclock.NewFakeClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	logger, lines := dedupeLines(fakeClock)
+	deduper := Dedupe(logger.WithOperation("assert", report.OperationTypeAssert), fakeClock)
+	pod := &unstructured.Unstructured{}
+	pod.SetKind("Pod")
+	pod.SetName("nginx")
+	deduper.Log(Assert, RunStatus, nil)
+	for i := 0; i < 4; i++ {
+		fakeClock.Step(500 * time.Millisecond)
+		deduper.WithResource(pod).Log(Assert, ErrorStatus, nil, s("not ready"))
+	}
+	// the status is part of the line
+	deduper.WithResource(pod).Log(Assert, DoneStatus, nil, s("not ready"))
+	deduper.WithResource(pod).Log(Assert, DoneStatus, nil, s("not ready"))
+	deduper.Flush()
+	// flushing twice doesn't repeat the count, lines logged after a flush are new
+	deduper.Flush()
+	deduper.WithResource(pod).Log(Assert, DoneStatus, nil, s("not ready"))
+	assert.Equal(t, []string{
+		"assert ASSERT RUN  ",
+		"assert ASSERT ERROR Pod/nginx not ready",
+		"assert ASSERT ERROR Pod/nginx … repeated 3 times over 1.5s",
+		"assert ASSERT DONE Pod/nginx not ready",
+		"assert ASSERT DONE Pod/nginx … repeated 1 time over 0s",
+		"assert ASSERT DONE Pod/nginx not ready",
+	}, *lines)
+}
+
+func TestDedupe_Interleaved(t *testing.T) {
+	fakeClock := tclock.NewFakeClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	logger, lines := dedupeLines(fakeClock)
+	first := Dedupe(logger.WithOperation("first", report.OperationTypeAssert), fakeClock)
+	second := Dedupe(logger.WithOperation("second", report.OperationTypeAssert), fakeClock)
+	// the same message from two operations doesn't suppress each other
+	for i := 0; i < 3; i++ {
+		first.Log(Assert, ErrorStatus, nil, s("not ready"))
+		second.Log(Assert, ErrorStatus, nil, s("not ready"))
+		fakeClock.Step(time.Second)
+	}
+	second.Log(Assert, DoneStatus, nil)
+	first.Flush()
+	second.Flush()
+	assert.Equal(t, []string{
+		"first ASSERT ERROR  not ready",
+		"second ASSERT ERROR  not ready",
+		"second ASSERT ERROR  … repeated 2 times over 2s",
+		"second ASSERT DONE  ",
+		"first ASSERT ERROR  … repeated 2 times over 2s",
+	}, *lines)
+	// derived operations are not deduplicated
+	*lines = nil
+	nested := first.WithOperation("nested", report.OperationTypeCommand)
+	nested.Log(Command, LogStatus, nil, s("same"))
+	nested.Log(Command, LogStatus, nil, s("same"))
+	assert.Equal(t, []string{"nested CMD LOG  same", "nested CMD LOG  same"}, *lines)
+}
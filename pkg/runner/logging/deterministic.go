@@ -0,0 +1,51 @@
+package logging
+
+import (
+	"sort"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/client"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DurationPlaceholder replaces the durations of the lines of deterministic loggers, see WithDeterministic.
+const DurationPlaceholder = "<duration>"
+
+// formatDuration renders d with format, or DurationPlaceholder when l is deterministic.
+func formatDuration(l Logger, d time.Duration, format func(time.Duration) string) string {
+	if isDeterministic(l) {
+		return DurationPlaceholder
+	}
+	return format(d)
+}
+
+// roundDuration returns a format rendering durations rounded to a multiple of m.
+func roundDuration(m time.Duration) func(time.Duration) string {
+	return func(d time.Duration) string {
+		return d.Round(m).String()
+	}
+}
+
+// isDeterministic returns true when l was created with WithDeterministic.
+func isDeterministic(l Logger) bool {
+	switch l := l.(type) {
+	case *logger:
+		return l.deterministic
+	case *Deduper:
+		return isDeterministic(l.logger)
+	}
+	return false
+}
+
+// sortResources sorts resources by group, version, kind, namespace and name, in place.
+func sortResources(resources []ctrlclient.Object) {
+	sort.SliceStable(resources, func(i, j int) bool {
+		return resourceSortKey(resources[i]) < resourceSortKey(resources[j])
+	})
+}
+
+func resourceSortKey(resource ctrlclient.Object) string {
+	gvk := resource.GetObjectKind().GroupVersionKind()
+	key := client.ObjectKey(resource)
+	return gvk.Group + "/" + gvk.Version + "/" + gvk.Kind + "/" + key.Namespace + "/" + key.Name
+}
@@ -0,0 +1,82 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/report"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	tclock "k8s.io/utils/clock/testing"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// deterministicRun logs the lines of a test the way the runner does, with the start time, the worker slot, the order of
+// the resources and the durations of a run.
+func deterministicRun(t *testing.T, start time.Time, worker int, step time.Duration, reversed bool, options ...Option) []byte {
+	t.Helper()
+	fakeClock := tclock.NewFakeClock(start)
+	var out bytes.Buffer
+	options = append([]Option{
+		WithColor(ColorAlways),
+		WithGlyphs(GlyphUnicode),
+		WithTimestampLayout(TimestampLayout(time.RFC3339Nano)),
+		WithWorker(worker),
+		WithElapsed(start),
+		WithResourceList(ListResources),
+	}, options...)
+	logger := NewWriterLogger(&out, fakeClock, "test", "step", options...)
+	var resources []ctrlclient.Object
+	for _, name := range []string{"a", "b", "c"} {
+		pod := &unstructured.Unstructured{}
+		pod.SetAPIVersion("v1")
+		pod.SetKind("Pod")
+		pod.SetNamespace("default")
+		pod.SetName(name)
+		resources = append(resources, pod)
+	}
+	if reversed {
+		resources[0], resources[2] = resources[2], resources[0]
+	}
+	apply := StartOperation(logger, fakeClock, "apply pods", report.OperationTypeApply, nil)
+	fakeClock.Step(step)
+	Success(apply.Logger().WithResources(resources...), Apply, OkStatus)
+	_ = apply.Done(nil)
+	wait := StartOperation(logger, fakeClock, "assert pod", report.OperationTypeAssert, nil)
+	ctx, cancel := context.WithTimeout(WithWaitInterval(context.Background(), time.Second), time.Hour)
+	defer cancel()
+	heartbeat := StartWaitHeartbeat(ctx, wait.Logger(), Assert)
+	deduper := Dedupe(wait.Logger(), fakeClock)
+	for i := 0; i < 3; i++ {
+		fakeClock.Step(step)
+		deduper.WithResource(resources[1]).Log(Assert, ErrorStatus, nil, s("not ready"))
+		heartbeat.Beat("the pod", errors.New("not ready"))
+	}
+	deduper.Flush()
+	_ = wait.Done(nil)
+	return out.Bytes()
+}
+
+func TestWithDeterministic_Golden(t *testing.T) {
+	first := deterministicRun(t, time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC), 1, 1500*time.Millisecond, false, WithDeterministic())
+	second := deterministicRun(t, time.Date(2025, 7, 9, 23, 59, 58, 0, time.FixedZone("CET", 60*60)), 4, 2*time.Second, true, WithDeterministic())
+	assert.Equal(t, string(first), string(second))
+	assertGolden(t, "deterministic.golden", first)
+	// runs differ otherwise
+	assert.NotEqual(t,
+		string(deterministicRun(t, time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC), 1, 1500*time.Millisecond, false)),
+		string(deterministicRun(t, time.Date(2025, 7, 9, 23, 59, 58, 0, time.UTC), 4, 2*time.Second, true)),
+	)
+}
+
+func TestWithDeterministic_OptionOrder(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	var before, after bytes.Buffer
+	NewWriterLogger(&before, fakeClock, "test", "step", WithDeterministic(), WithColor(ColorAlways), WithWorker(2)).Log(Apply, OkStatus, nil)
+	NewWriterLogger(&after, fakeClock, "test", "step", WithColor(ColorAlways), WithWorker(2), WithDeterministic()).Log(Apply, OkStatus, nil)
+	assert.Equal(t, "| test | step | APPLY     | OK    |\n", before.String())
+	assert.Equal(t, before.String(), after.String())
+}
@@ -0,0 +1,151 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	diffutils "github.com/kyverno/chainsaw/pkg/utils/diff"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// DefaultDiffMaxHunks is the default maximum number of hunks of the diffs logged with LogDiff.
+const DefaultDiffMaxHunks = 10
+
+// UnifiedDiff returns the unified diff of the expected and actual YAML, with three lines of context, empty when they
+// don't differ.
+func UnifiedDiff(expected string, actual string) string {
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(expected),
+		B:        difflib.SplitLines(actual),
+		FromFile: "expected",
+		ToFile:   "actual",
+		Context:  3,
+	})
+	if err != nil {
+		// diffs are written to a buffer, it doesn't fail
+		return ""
+	}
+	return diff
+}
+
+// FormatDiff renders the unified diff, keeping its first maxHunks hunks, the omitted ones are counted on a last line.
+// A maxHunks of zero or less keeps them all. With a theme, removed lines take its failure color, added lines its
+// success color and the other lines its debug color, else lines are told apart by their +/- prefixes only.
+func FormatDiff(diff string, theme *Theme, maxHunks int) string {
+	if diff == "" {
+		return ""
+	}
+	lines := strings.Split(strings.TrimSuffix(diff, "\n"), "\n")
+	var out []string
+	hunks := 0
+	for i, line := range lines {
+		if strings.HasPrefix(line, "@@") {
+			hunks++
+			if maxHunks > 0 && hunks > maxHunks {
+				omitted := 0
+				for _, line := range lines[i:] {
+					if strings.HasPrefix(line, "@@") {
+						omitted++
+					}
+				}
+				out = append(out, fmt.Sprintf("... %d more %s omitted", omitted, plural(omitted, "hunk", "hunks")))
+				break
+			}
+		}
+		out = append(out, colorDiffLine(line, theme))
+	}
+	return strings.Join(out, "\n")
+}
+
+// colorDiffLine colors a line of a unified diff with theme, it is returned as is without theme.
+func colorDiffLine(line string, theme *Theme) string {
+	if theme == nil {
+		return line
+	}
+	var c *color.Color
+	switch {
+	case strings.HasPrefix(line, "---"), strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "@@"):
+		c = theme.Info
+	case strings.HasPrefix(line, "-"):
+		c = theme.Failure
+	case strings.HasPrefix(line, "+"):
+		c = theme.Success
+	default:
+		c = theme.Debug
+	}
+	if sprint := colorSprint(c); sprint != nil {
+		return sprint(line)
+	}
+	return line
+}
+
+func plural(n int, one string, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
+
+// LogDiff logs the diff of the expected and actual YAML as a failure of operation, colored when l prints colors, with
+// the maximum number of hunks of l, see WithDiffMaxHunks. It returns the uncolored diff, to record it in reports.
+// Nothing is logged when they don't differ.
+func LogDiff(l Logger, operation Operation, expected string, actual string) string {
+	diff := UnifiedDiff(expected, actual)
+	if diff == "" {
+		return ""
+	}
+	maxHunks := DiffMaxHunks(l)
+	Failure(l, operation, ErrorStatus, Section("diff", FormatDiff(diff, diffTheme(l), maxHunks)))
+	return FormatDiff(diff, nil, maxHunks)
+}
+
+// FormatMismatches renders the mismatches one per line, with a theme their fields take its info color.
+func FormatMismatches(mismatches []diffutils.Mismatch, theme *Theme) string {
+	lines := make([]string, 0, len(mismatches))
+	for _, mismatch := range mismatches {
+		path := mismatch.Path
+		if theme != nil {
+			if sprint := colorSprint(theme.Info); sprint != nil {
+				path = sprint(path)
+			}
+		}
+		lines = append(lines, fmt.Sprintf("field %s %s", path, mismatch.Message()))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// LogMismatches logs the mismatches of the expected and actual resources as a failure of operation, colored when l
+// prints colors. It returns them uncolored, to record them in reports. Nothing is logged without mismatches.
+func LogMismatches(l Logger, operation Operation, mismatches []diffutils.Mismatch) string {
+	if len(mismatches) == 0 {
+		return ""
+	}
+	Failure(l, operation, ErrorStatus, Section("mismatches", FormatMismatches(mismatches, diffTheme(l))))
+	return FormatMismatches(mismatches, nil)
+}
+
+// DiffMaxHunks returns the maximum number of hunks of the diffs logged with l, zero or less when they are kept whole.
+// Loggers not created with NewLogger, NewWriterLogger or NewSinkLogger are assumed to keep DefaultDiffMaxHunks hunks.
+func DiffMaxHunks(l Logger) int {
+	switch l := l.(type) {
+	case *logger:
+		return l.diffMaxHunks
+	case *Deduper:
+		return DiffMaxHunks(l.logger)
+	}
+	return DefaultDiffMaxHunks
+}
+
+// diffTheme returns the theme diffs are colored with, nil unless l prints colors.
+func diffTheme(l Logger) *Theme {
+	switch l := l.(type) {
+	case *logger:
+		if l.colors {
+			return l.theme
+		}
+	case *Deduper:
+		return diffTheme(l.logger)
+	}
+	return nil
+}
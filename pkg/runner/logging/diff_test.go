@@ -0,0 +1,177 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
+	diffutils "github.com/kyverno/chainsaw/pkg/utils/diff"
+	"github.com/stretchr/testify/assert"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+// diffYAML returns a YAML document of n fields, the fields in changed have another value.
+func diffYAML(n int, changed ...int) string {
+	var lines []string
+	for i := 0; i < n; i++ {
+		value := "a"
+		for _, c := range changed {
+			if c == i {
+				value = "b"
+			}
+		}
+		lines = append(lines, fmt.Sprintf("field%02d: %s", i, value))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	assert.Equal(t, "", UnifiedDiff(diffYAML(5), diffYAML(5)))
+	// the documents end with a new line, it is an empty line of context
+	assert.Equal(t, "--- expected\n+++ actual\n@@ -1,4 +1,4 @@\n field00: a\n-field01: a\n+field01: b\n field02: a\n \n", UnifiedDiff(diffYAML(3), diffYAML(3, 1)))
+}
+
+func TestFormatDiff(t *testing.T) {
+	// changes far enough apart make a hunk each
+	diff := UnifiedDiff(diffYAML(40), diffYAML(40, 0, 10, 20, 30))
+	tests := []struct {
+		name      string
+		diff      string
+		maxHunks  int
+		wantHunks int
+		want      string
+	}{{
+		name: "no difference",
+		diff: "",
+		want: "",
+	}, {
+		name:      "whole",
+		diff:      diff,
+		maxHunks:  4,
+		wantHunks: 4,
+	}, {
+		name:      "unlimited",
+		diff:      diff,
+		wantHunks: 4,
+	}, {
+		name:      "truncated",
+		diff:      diff,
+		maxHunks:  1,
+		wantHunks: 1,
+		want:      "... 3 more hunks omitted",
+	}, {
+		name:      "one omitted",
+		diff:      diff,
+		maxHunks:  3,
+		wantHunks: 3,
+		want:      "... 1 more hunk omitted",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FormatDiff(tt.diff, nil, tt.maxHunks)
+			assert.Equal(t, tt.wantHunks, strings.Count(got, "\n@@"))
+			if tt.want != "" {
+				assert.True(t, strings.HasSuffix(got, "\n"+tt.want), got)
+			} else if tt.diff != "" {
+				assert.Equal(t, strings.TrimSuffix(tt.diff, "\n"), got)
+			} else {
+				assert.Equal(t, "", got)
+			}
+		})
+	}
+}
+
+func TestFormatDiff_Colors(t *testing.T) {
+	theme := DefaultTheme()
+	theme.Debug = nil
+	diff := UnifiedDiff(diffYAML(3), diffYAML(3, 1))
+	got := FormatDiff(diff, &theme, 0)
+	lines := strings.Split(got, "\n")
+	assert.Equal(t, colorSprint(theme.Info)("--- expected"), lines[0])
+	assert.Equal(t, colorSprint(theme.Info)("@@ -1,4 +1,4 @@"), lines[2])
+	// context lines are left uncolored without a debug color
+	assert.Equal(t, " field00: a", lines[3])
+	assert.Equal(t, colorSprint(theme.Failure)("-field01: a"), lines[4])
+	assert.Equal(t, colorSprint(theme.Success)("+field01: b"), lines[5])
+	// the plain form keeps the +/- prefixes
+	assert.Equal(t, strings.TrimSuffix(diff, "\n"), FormatDiff(diff, nil, 0))
+}
+
+func TestLogDiff(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	expected, actual := diffYAML(40), diffYAML(40, 0, 10, 20, 30)
+	tests := []struct {
+		name      string
+		options   []Option
+		colored   bool
+		wantHunks int
+	}{{
+		name:      "default",
+		options:   []Option{WithColor(ColorNever)},
+		wantHunks: 4,
+	}, {
+		name:      "colored",
+		options:   []Option{WithColor(ColorAlways)},
+		colored:   true,
+		wantHunks: 4,
+	}, {
+		name:      "truncated",
+		options:   []Option{WithColor(ColorNever), WithDiffMaxHunks(2)},
+		wantHunks: 2,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockT := &tlogging.FakeTLogger{}
+			logger := NewLogger(mockT, fakeClock, "test", "step", tt.options...)
+			diff := LogDiff(logger, Assert, expected, actual)
+			assert.Len(t, mockT.Messages, 1)
+			assert.Contains(t, mockT.Messages[0], "|\n=== DIFF\n")
+			assert.Equal(t, tt.colored, strings.Contains(mockT.Messages[0], colorSprint(GetTheme().Failure)("-field00: a")))
+			// the returned diff is uncolored
+			assert.NotContains(t, diff, "\x1b[")
+			assert.Equal(t, tt.wantHunks, strings.Count(diff, "\n@@"))
+			assert.Equal(t, FormatDiff(UnifiedDiff(expected, actual), nil, tt.wantHunks), diff)
+		})
+	}
+	// nothing is logged without differences
+	mockT := &tlogging.FakeTLogger{}
+	assert.Equal(t, "", LogDiff(NewLogger(mockT, fakeClock, "test", "step"), Assert, expected, expected))
+	assert.Empty(t, mockT.Messages)
+}
+
+func TestLogMismatches(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	mismatches := []diffutils.Mismatch{
+		{Path: "spec.replicas", Expected: int64(3), Actual: int64(1)},
+		{Path: "spec.args", Note: "expected 1 element, got 2"},
+	}
+	want := "field spec.replicas expected 3, got 1\nfield spec.args expected 1 element, got 2"
+	for _, colored := range []bool{false, true} {
+		mode := ColorNever
+		if colored {
+			mode = ColorAlways
+		}
+		mockT := &tlogging.FakeTLogger{}
+		got := LogMismatches(NewLogger(mockT, fakeClock, "test", "step", WithColor(mode)), Assert, mismatches)
+		// the returned mismatches are uncolored
+		assert.Equal(t, want, got)
+		assert.Len(t, mockT.Messages, 1)
+		assert.Contains(t, mockT.Messages[0], "|\n=== MISMATCHES\n")
+		assert.Equal(t, colored, strings.Contains(mockT.Messages[0], colorSprint(GetTheme().Info)("spec.replicas")))
+	}
+	// nothing is logged without mismatches
+	mockT := &tlogging.FakeTLogger{}
+	assert.Equal(t, "", LogMismatches(NewLogger(mockT, fakeClock, "test", "step"), Assert, nil))
+	assert.Empty(t, mockT.Messages)
+}
+
+func TestDiffMaxHunks(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Now())
+	assert.Equal(t, DefaultDiffMaxHunks, DiffMaxHunks(NewLogger(&tlogging.FakeTLogger{}, fakeClock, "test", "step")))
+	logger := NewLogger(&tlogging.FakeTLogger{}, fakeClock, "test", "step", WithDiffMaxHunks(0))
+	assert.Equal(t, 0, DiffMaxHunks(logger))
+	assert.Equal(t, 0, DiffMaxHunks(Dedupe(logger, fakeClock)))
+	assert.Equal(t, DefaultDiffMaxHunks, DiffMaxHunks(NoOp()))
+}
@@ -0,0 +1,24 @@
+package logging
+
+// discard is a TLogger that drops everything, used when the console is taken over by the dashboard.
+type discard struct {
+	t TLogger
+}
+
+// Discard returns a TLogger dropping the lines logged, Helper calls are still forwarded to t.
+func Discard(t TLogger) TLogger {
+	return discard{t: t}
+}
+
+func (d discard) Log(...any) {}
+
+func (d discard) Helper() {
+	d.t.Helper()
+}
+
+// Cleanup registers f with the Cleanup of the TLogger, if it has one.
+func (d discard) Cleanup(f func()) {
+	if t, ok := d.t.(cleanupTLogger); ok {
+		t.Cleanup(f)
+	}
+}
@@ -0,0 +1,23 @@
+package logging
+
+import (
+	"context"
+	"testing"
+
+	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscard(t *testing.T) {
+	fake := &tlogging.FakeTLogger{}
+	logger := Discard(fake)
+	logger.Helper()
+	logger.Log("dropped")
+	assert.Empty(t, fake.Messages)
+}
+
+func TestIsQuietConsole(t *testing.T) {
+	assert.False(t, IsQuietConsole(nil)) //nolint:staticcheck
+	assert.False(t, IsQuietConsole(context.Background()))
+	assert.True(t, IsQuietConsole(WithQuietConsole(context.Background())))
+}
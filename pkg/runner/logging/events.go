@@ -0,0 +1,183 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/report"
+	"github.com/kyverno/chainsaw/pkg/runner/events"
+)
+
+// EventStreamFDPrefix prefixes the file descriptors event streams are written to, like fd:3.
+const EventStreamFDPrefix = "fd:"
+
+// ParseEventStreamFD returns the file descriptor of an event stream target and true, or false when target is a file path.
+func ParseEventStreamFD(target string) (int, bool, error) {
+	value, ok := strings.CutPrefix(target, EventStreamFDPrefix)
+	if !ok {
+		return 0, false, nil
+	}
+	fd, err := strconv.Atoi(value)
+	if err != nil || fd < 0 {
+		return 0, true, fmt.Errorf("invalid file descriptor %q, expected %s<n> with n a non negative integer", value, EventStreamFDPrefix)
+	}
+	return fd, true, nil
+}
+
+// Statuses of finished tests, steps and operations in the event stream.
+const (
+	EventPassed  = "passed"
+	EventFailed  = "failed"
+	EventSkipped = "skipped"
+)
+
+// JSONEvent is an event written by an EventStream.
+type JSONEvent struct {
+	// Seq numbers the events of a stream from 1, it never goes backwards.
+	Seq       int64       `json:"seq"`
+	Timestamp time.Time   `json:"timestamp"`
+	Type      events.Type `json:"type"`
+	Test      string      `json:"test,omitempty"`
+	Step      string      `json:"step,omitempty"`
+	// OperationName and OperationType identify the operation of operation events.
+	OperationName string               `json:"operationName,omitempty"`
+	OperationType report.OperationType `json:"operationType,omitempty"`
+	// Resources are the resources the operation logged lines about, set on OperationFinished events.
+	Resources []JSONResource `json:"resources,omitempty"`
+	// Status is the outcome of finished events, passed, failed or skipped.
+	Status string `json:"status,omitempty"`
+	Error  string `json:"error,omitempty"`
+	// Tests is the number of tests of the run, set on SuiteStarted events.
+	Tests int `json:"tests,omitempty"`
+	// Summary counts the tests of the run by outcome, set on SuiteFinished events.
+	Summary *JSONSummary `json:"summary,omitempty"`
+}
+
+// JSONSummary counts the tests of a run by outcome.
+type JSONSummary struct {
+	Passed  int `json:"passed"`
+	Failed  int `json:"failed"`
+	Skipped int `json:"skipped"`
+}
+
+// operationKey identifies a running operation, the operations of a step run one at a time.
+type operationKey struct {
+	test      string
+	step      string
+	operation string
+}
+
+// EventStream writes the events of a run as JSON objects, one per line, for machines following the run.
+// Events are written with WriteEvent, typically subscribed to the event bus, and it is also a sink of the loggers:
+// the resources an operation logs lines about are written with its OperationFinished event.
+// It is safe for concurrent use.
+type EventStream struct {
+	lock      sync.Mutex
+	w         io.Writer
+	seq       int64
+	resources map[operationKey][]JSONResource
+}
+
+// NewEventStream returns an EventStream writing to w.
+func NewEventStream(w io.Writer) *EventStream {
+	return &EventStream{
+		w:         w,
+		resources: map[operationKey][]JSONResource{},
+	}
+}
+
+// WriteEvent writes event with the next sequence number, events are numbered in the order they are written.
+func (s *EventStream) WriteEvent(event events.Event) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	line := JSONEvent{
+		Seq:           s.seq + 1,
+		Timestamp:     event.Time,
+		Type:          event.Type,
+		Test:          event.Test,
+		Step:          event.Step,
+		OperationName: event.Operation,
+		OperationType: event.OperationType,
+		Tests:         event.Tests,
+	}
+	switch event.Type {
+	case events.OperationStarted:
+		// lines logged by an earlier run of the same operation don't leak into this one
+		delete(s.resources, operationKey{test: event.Test, step: event.Step, operation: event.Operation})
+	case events.OperationFinished:
+		key := operationKey{test: event.Test, step: event.Step, operation: event.Operation}
+		line.Resources = s.resources[key]
+		delete(s.resources, key)
+		line.Status = eventStatus(event)
+	case events.StepFinished, events.TestFinished:
+		line.Status = eventStatus(event)
+	case events.SuiteFinished:
+		if event.Summary != nil {
+			line.Summary = &JSONSummary{
+				Passed:  event.Summary.Passed,
+				Failed:  event.Summary.Failed,
+				Skipped: event.Summary.Skipped,
+			}
+		}
+	}
+	if event.Err != nil {
+		line.Error = jsonMessage(event.Err.Error())
+	}
+	data, err := json.Marshal(line)
+	if err != nil {
+		return err
+	}
+	s.seq++
+	data = append(data, '\n')
+	_, err = s.w.Write(data)
+	return err
+}
+
+// WriteEntry records the resources of the lines logged by operations, lines outside operations are ignored.
+func (s *EventStream) WriteEntry(entry Entry) error {
+	if entry.OperationName == "" {
+		return nil
+	}
+	resources := jsonResources(entry.Resources)
+	if resource := jsonResource(entry.Resource); resource != nil {
+		resources = append(resources, *resource)
+	}
+	if len(resources) == 0 {
+		return nil
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	// steps are padded for alignment in the human readable output
+	key := operationKey{test: entry.Test, step: strings.TrimSpace(entry.Step), operation: entry.OperationName}
+	for _, resource := range resources {
+		if !containsResource(s.resources[key], resource) {
+			s.resources[key] = append(s.resources[key], resource)
+		}
+	}
+	return nil
+}
+
+func containsResource(resources []JSONResource, resource JSONResource) bool {
+	for _, r := range resources {
+		if r == resource {
+			return true
+		}
+	}
+	return false
+}
+
+func eventStatus(event events.Event) string {
+	switch {
+	case event.Skipped:
+		return EventSkipped
+	case event.Failed:
+		return EventFailed
+	default:
+		return EventPassed
+	}
+}
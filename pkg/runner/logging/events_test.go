@@ -0,0 +1,141 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/report"
+	"github.com/kyverno/chainsaw/pkg/runner/events"
+	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files of the tests")
+
+// assertGolden compares got with the golden file name of testdata, the file is written instead with -update.
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		assert.NoError(t, os.WriteFile(path, got, 0o600))
+	}
+	want, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+}
+
+func TestEventStream(t *testing.T) {
+	start := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	fakeClock := tclock.NewFakeClock(start)
+	var out bytes.Buffer
+	stream := NewEventStream(&out)
+	bus := events.NewBusWithClock(fakeClock)
+	bus.Subscribe(func(event events.Event) {
+		assert.NoError(t, stream.WriteEvent(event))
+	})
+	var configMap unstructured.Unstructured
+	configMap.SetAPIVersion("v1")
+	configMap.SetKind("ConfigMap")
+	configMap.SetNamespace("chainsaw-happy-cat")
+	configMap.SetName("quick-start")
+	// steps are padded in log lines, not in events
+	logger := NewLogger(&tlogging.FakeTLogger{}, fakeClock, "quick-start", "step-1  ", WithSink(stream), WithoutText())
+	tick := func() { fakeClock.Step(500 * time.Millisecond) }
+	bus.Publish(events.Event{Type: events.SuiteStarted, Tests: 2})
+	bus.Publish(events.Event{Type: events.TestStarted, Test: "quick-start"})
+	tick()
+	bus.Publish(events.Event{Type: events.StepStarted, Test: "quick-start", Step: "step-1"})
+	// lines outside operations are not attributed to any
+	logger.WithResource(&configMap).Log(Get, OkStatus, nil)
+	bus.Publish(events.Event{Type: events.OperationStarted, Test: "quick-start", Step: "step-1", Operation: "Apply configmap.yaml", OperationType: report.OperationTypeApply})
+	apply := logger.WithOperation("Apply configmap.yaml", report.OperationTypeApply).WithResource(&configMap)
+	apply.Log(Apply, RunStatus, nil)
+	apply.Log(Apply, DoneStatus, nil)
+	tick()
+	bus.Publish(events.Event{Type: events.OperationFinished, Test: "quick-start", Step: "step-1", Operation: "Apply configmap.yaml", OperationType: report.OperationTypeApply})
+	bus.Publish(events.Event{Type: events.OperationStarted, Test: "quick-start", Step: "step-1", Operation: "Assert", OperationType: report.OperationTypeAssert})
+	logger.WithOperation("Assert", report.OperationTypeAssert).WithResource(&configMap).Log(Assert, ErrorStatus, nil)
+	tick()
+	bus.Publish(events.Event{Type: events.OperationFinished, Test: "quick-start", Step: "step-1", Operation: "Assert", OperationType: report.OperationTypeAssert, Failed: true, Err: errors.New("data.foo: Invalid value: \"bar\": Expected value: \"baz\"")})
+	bus.Publish(events.Event{Type: events.StepFinished, Test: "quick-start", Step: "step-1", Failed: true})
+	bus.Publish(events.Event{Type: events.TestFinished, Test: "quick-start", Failed: true})
+	bus.Publish(events.Event{Type: events.TestStarted, Test: "skipped"})
+	bus.Publish(events.Event{Type: events.TestFinished, Test: "skipped", Skipped: true})
+	tick()
+	bus.Publish(events.Event{Type: events.SuiteFinished, Summary: &events.Summary{Failed: 1, Skipped: 1}})
+	assertGolden(t, "events.jsonl", out.Bytes())
+}
+
+func TestEventStream_Resources(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	var out bytes.Buffer
+	stream := NewEventStream(&out)
+	var first, second unstructured.Unstructured
+	first.SetAPIVersion("v1")
+	first.SetKind("Pod")
+	first.SetName("first")
+	second.SetAPIVersion("v1")
+	second.SetKind("Pod")
+	second.SetName("second")
+	logger := NewLogger(&tlogging.FakeTLogger{}, fakeClock, "test", "step", WithSink(stream), WithoutText()).WithOperation("Delete", report.OperationTypeDelete)
+	operation := events.Event{Test: "test", Step: "step", Operation: "Delete", OperationType: report.OperationTypeDelete}
+	started, finished := operation, operation
+	started.Type, finished.Type = events.OperationStarted, events.OperationFinished
+	// lines of a previous run of the operation are forgotten when it starts again
+	logger.WithResource(&first).Log(Delete, OkStatus, nil)
+	assert.NoError(t, stream.WriteEvent(started))
+	// resources are listed once, in the order they are logged
+	logger.WithResources(&second, &first).Log(Delete, RunStatus, nil)
+	logger.WithResource(&second).Log(Delete, OkStatus, nil)
+	assert.NoError(t, stream.WriteEvent(finished))
+	assert.Equal(t, `{"seq":1,"timestamp":"0001-01-01T00:00:00Z","type":"OperationStarted","test":"test","step":"step","operationName":"Delete","operationType":"delete"}
+{"seq":2,"timestamp":"0001-01-01T00:00:00Z","type":"OperationFinished","test":"test","step":"step","operationName":"Delete","operationType":"delete","resources":[{"apiVersion":"v1","kind":"Pod","name":"second"},{"apiVersion":"v1","kind":"Pod","name":"first"}],"status":"passed"}
+`, out.String())
+}
+
+func TestParseEventStreamFD(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  string
+		want    int
+		wantFD  bool
+		wantErr bool
+	}{{
+		name:   "file",
+		target: "events.jsonl",
+	}, {
+		name:   "file descriptor",
+		target: "fd:3",
+		want:   3,
+		wantFD: true,
+	}, {
+		name:    "not a number",
+		target:  "fd:three",
+		wantFD:  true,
+		wantErr: true,
+	}, {
+		name:    "negative",
+		target:  "fd:-1",
+		wantFD:  true,
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, fd, err := ParseEventStreamFD(tt.target)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantFD, fd)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// FailuresOnlyEnv is the environment variable enabling FailureOutput when set to a true boolean, like "true" or "1".
+const FailuresOnlyEnv = "CHAINSAW_LOG_FAILURES_ONLY"
+
+// FailuresOnly returns true when the environment asks for the logs of failed tests only.
+func FailuresOnly() bool {
+	value, _ := lookupEnv(FailuresOnlyEnv)
+	enabled, err := strconv.ParseBool(value)
+	return err == nil && enabled
+}
+
+// FailureOutput buffers the log lines of each test and only writes those of failed tests, when they complete.
+// Passing tests write nothing. It is safe for concurrent use.
+type FailureOutput struct {
+	lock     sync.Mutex
+	w        io.Writer
+	captures *Captures
+	tests    map[TestKey]bool
+}
+
+// NewFailureOutput returns a FailureOutput writing to w, at most maxLines lines are kept per test.
+func NewFailureOutput(w io.Writer, maxLines int) *FailureOutput {
+	return &FailureOutput{
+		w:        w,
+		captures: NewCaptures(maxLines),
+		tests:    map[TestKey]bool{},
+	}
+}
+
+// Start starts buffering the lines of test, lines of tests not started are dropped.
+func (f *FailureOutput) Start(test TestKey) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.tests[test] = true
+}
+
+func (f *FailureOutput) WriteEntry(entry Entry) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if !f.tests[entry.TestKey()] {
+		return nil
+	}
+	return f.captures.WriteEntry(entry)
+}
+
+// Complete writes the lines of test followed by its failure when it failed, and drops them otherwise.
+func (f *FailureOutput) Complete(test TestKey, failed bool) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.complete(test, failed)
+}
+
+func (f *FailureOutput) complete(test TestKey, failed bool) error {
+	if !f.tests[test] {
+		return nil
+	}
+	delete(f.tests, test)
+	lines := f.captures.Take(test)
+	if !failed {
+		return nil
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "===== FAIL %s\n", test.Name)
+	for _, line := range lines {
+		b.WriteString(line.Message)
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "----- %s failed\n", test.Name)
+	_, err := io.WriteString(f.w, b.String())
+	return err
+}
+
+// Close writes the lines of tests not completed as failures, typically when the run is interrupted.
+func (f *FailureOutput) Close() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	tests := make([]TestKey, 0, len(f.tests))
+	for test := range f.tests {
+		tests = append(tests, test)
+	}
+	sort.Slice(tests, func(i, j int) bool { return tests[i].Less(tests[j]) })
+	for _, test := range tests {
+		if err := f.complete(test, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
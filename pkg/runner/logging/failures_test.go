@@ -0,0 +1,99 @@
+package logging
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
+	"github.com/stretchr/testify/assert"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+func TestFailuresOnly(t *testing.T) {
+	defer func(f func(string) (string, bool)) { lookupEnv = f }(lookupEnv)
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{{
+		name: "not set",
+	}, {
+		name: "true",
+		env:  map[string]string{FailuresOnlyEnv: "true"},
+		want: true,
+	}, {
+		name: "one",
+		env:  map[string]string{FailuresOnlyEnv: "1"},
+		want: true,
+	}, {
+		name: "invalid",
+		env:  map[string]string{FailuresOnlyEnv: "yes"},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookupEnv = func(name string) (string, bool) {
+				value, ok := tt.env[name]
+				return value, ok
+			}
+			assert.Equal(t, tt.want, FailuresOnly())
+		})
+	}
+}
+
+func TestFailureOutput(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	var out bytes.Buffer
+	failures := NewFailureOutput(&out, 0)
+	mockT := &tlogging.FakeTLogger{}
+	logger := func(test string) Logger {
+		return NewLogger(mockT, fakeClock, test, "step-1", WithSink(failures), WithoutText(), WithColor(ColorNever), WithGlyphs(GlyphASCII))
+	}
+	// lines of tests not started are dropped
+	logger("chainsaw").Log(Script, LogStatus, nil, s("main"))
+	failures.Start(TestKey{Name: "passing"})
+	failures.Start(TestKey{Name: "failing"})
+	failures.Start(TestKey{Name: "interrupted"})
+	logger("passing").Log(Apply, OkStatus, nil)
+	Failure(logger("passing"), Assert, ErrorStatus, s("retried"))
+	logger("failing").Log(Apply, OkStatus, nil)
+	Failure(logger("failing"), Assert, ErrorStatus, s("boom"))
+	logger("interrupted").Log(Sleep, RunStatus, nil)
+	// passing tests write nothing, even with error lines
+	assert.NoError(t, failures.Complete(TestKey{Name: "passing"}, false))
+	assert.Zero(t, out.Len())
+	assert.NoError(t, failures.Complete(TestKey{Name: "failing"}, true))
+	assert.Equal(t, `===== FAIL failing
+| 10:30:00 | failing | step-1 | APPLY     | OK    |
+[fail] | 10:30:00 | failing | step-1 | ASSERT    | ERROR |
+boom
+----- failing failed
+`, out.String())
+	out.Reset()
+	// completing a test twice is a no-op
+	assert.NoError(t, failures.Complete(TestKey{Name: "failing"}, true))
+	assert.Zero(t, out.Len())
+	// tests not completed are written as failures on close
+	assert.NoError(t, failures.Close())
+	assert.Equal(t, "===== FAIL interrupted\n| 10:30:00 | interrupted | step-1 | SLEEP     | RUN   |\n----- interrupted failed\n", out.String())
+	assert.Empty(t, mockT.Messages)
+}
+
+func TestFailureOutput_DuplicateNames(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	var out bytes.Buffer
+	failures := NewFailureOutput(&out, 0)
+	logger := func(path string) Logger {
+		return NewLogger(&tlogging.FakeTLogger{}, fakeClock, "test", "step-1", WithSink(failures), WithoutText(), WithTestPath(path))
+	}
+	failures.Start(TestKey{Path: "tests/a", Name: "test"})
+	failures.Start(TestKey{Path: "tests/b", Name: "test"})
+	logger("tests/a").Log(Script, LogStatus, nil, s("a"))
+	logger("tests/b").Log(Script, LogStatus, nil, s("b"))
+	// the tests sharing a name keep their own lines
+	assert.NoError(t, failures.Complete(TestKey{Path: "tests/b", Name: "test"}, true))
+	assert.Equal(t, "===== FAIL test\n| 10:30:00 | test | step-1 | SCRIPT    | LOG   |\nb\n----- test failed\n", out.String())
+	out.Reset()
+	assert.NoError(t, failures.Complete(TestKey{Path: "tests/a", Name: "test"}, true))
+	assert.Equal(t, "===== FAIL test\n| 10:30:00 | test | step-1 | SCRIPT    | LOG   |\na\n----- test failed\n", out.String())
+}
@@ -0,0 +1,145 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Field is a key/value pair of structured data attached to log lines, like cluster=staging or attempt=3.
+// The value is rendered when a line is written, a fmt.Stringer is rendered with its String method.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// Fields are the fields of a log line, sorted by key, each key appears once.
+type Fields []Field
+
+// Get returns the value of key, and whether fields have one.
+func (f Fields) Get(key string) (any, bool) {
+	i := sort.Search(len(f), func(i int) bool { return f[i].Key >= key })
+	if i < len(f) && f[i].Key == key {
+		return f[i].Value, true
+	}
+	return nil, false
+}
+
+// Map returns the fields in a map, it is nil when there are none.
+func (f Fields) Map() map[string]any {
+	if len(f) == 0 {
+		return nil
+	}
+	out := make(map[string]any, len(f))
+	for _, field := range f {
+		out[field.Key] = field.Value
+	}
+	return out
+}
+
+// withFields returns the fields of f overridden with values, f is left unchanged, loggers derived from the same
+// logger never share their fields.
+func (f Fields) withFields(values map[string]any) Fields {
+	if len(values) == 0 {
+		return f
+	}
+	out := make(Fields, 0, len(f)+len(values))
+	for _, field := range f {
+		if _, ok := values[field.Key]; !ok {
+			out = append(out, field)
+		}
+	}
+	for key, value := range values {
+		out = append(out, Field{Key: key, Value: value})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out
+}
+
+// FormatFieldValue renders the value of a field, nil values and nil pointers are rendered as <nil>.
+func FormatFieldValue(value any) string {
+	// fmt uses the String and Error methods, and recovers from those called on nil pointers
+	return fmt.Sprint(value)
+}
+
+// formatFields renders fields as space separated key=value pairs, values are quoted when empty or when they hold
+// spaces, quotes or equal signs, to keep the pairs readable.
+func formatFields(fields Fields) string {
+	var b strings.Builder
+	for i, field := range fields {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(field.Key)
+		b.WriteByte('=')
+		value := FormatFieldValue(field.Value)
+		if value == "" || strings.ContainsAny(value, " \t\r\n\"=") {
+			value = strconv.Quote(value)
+		}
+		b.WriteString(value)
+	}
+	return b.String()
+}
+
+// structuredFieldValue returns the value of a field for structured loggers, Stringers and errors are rendered as
+// strings, other values are kept as is.
+func structuredFieldValue(value any) any {
+	switch value.(type) {
+	case fmt.Stringer, error:
+		return FormatFieldValue(value)
+	}
+	return value
+}
+
+// structuredFields returns fields in a map for structured loggers, see structuredFieldValue.
+func structuredFields(fields Fields) map[string]any {
+	out := make(map[string]any, len(fields))
+	for _, field := range fields {
+		out[field.Key] = structuredFieldValue(field.Value)
+	}
+	return out
+}
+
+// jsonFields returns fields as JSON values, values that can't be marshaled are rendered as strings.
+func jsonFields(fields Fields) map[string]json.RawMessage {
+	if len(fields) == 0 {
+		return nil
+	}
+	out := make(map[string]json.RawMessage, len(fields))
+	for _, field := range fields {
+		value := field.Value
+		// marshalers know best how to render themselves in JSON
+		if _, ok := value.(json.Marshaler); !ok {
+			value = structuredFieldValue(value)
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			data, _ = json.Marshal(FormatFieldValue(field.Value))
+		}
+		out[field.Key] = data
+	}
+	return out
+}
+
+// redactFields returns fields with the secrets their values hold removed, values holding none are kept as is.
+func redactFields(redactor *Redactor, fields Fields) Fields {
+	if redactor == nil || len(fields) == 0 {
+		return fields
+	}
+	var out Fields
+	for i, field := range fields {
+		value := FormatFieldValue(field.Value)
+		if redacted := redactor.Redact(value)[0]; redacted != value {
+			if out == nil {
+				out = append(Fields(nil), fields...)
+			}
+			out[i].Value = redacted
+		}
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
@@ -0,0 +1,190 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+// counter is a fmt.Stringer counting the times it is rendered.
+type counter struct {
+	calls int
+}
+
+func (c *counter) String() string {
+	c.calls++
+	return "rendered"
+}
+
+// nilStringer is a fmt.Stringer panicking when called on a nil pointer.
+type nilStringer struct {
+	value string
+}
+
+func (n *nilStringer) String() string { return n.value }
+
+func TestLogger_WithFields(t *testing.T) {
+	var entries []Entry
+	logger := NewSinkLogger(sinkFunc(func(entry Entry) error {
+		entries = append(entries, entry)
+		return nil
+	}), tclock.NewFakePassiveClock(time.Now()), "test", "step")
+	parent := logger.WithFields(map[string]any{"cluster": "staging", "attempt": 1})
+	child := parent.WithField("attempt", 2).WithFields(map[string]any{"region": "eu"})
+	// deriving a logger without fields keeps those of its parent
+	child = child.WithOperation("Assert", "assert").WithResources().WithFields(nil)
+	parent.Log(Assert, RunStatus, nil)
+	child.Log(Assert, RunStatus, nil)
+	logger.Log(Assert, RunStatus, nil)
+	assert.Equal(t, Fields{{Key: "attempt", Value: 1}, {Key: "cluster", Value: "staging"}}, entries[0].Fields)
+	assert.Equal(t, Fields{{Key: "attempt", Value: 2}, {Key: "cluster", Value: "staging"}, {Key: "region", Value: "eu"}}, entries[1].Fields)
+	assert.Nil(t, entries[2].Fields)
+	value, ok := entries[1].Fields.Get("attempt")
+	assert.True(t, ok)
+	assert.Equal(t, 2, value)
+	_, ok = entries[1].Fields.Get("unknown")
+	assert.False(t, ok)
+	assert.Equal(t, map[string]any{"attempt": 2, "cluster": "staging", "region": "eu"}, entries[1].Fields.Map())
+}
+
+func TestLogger_WithFields_Lazy(t *testing.T) {
+	defer SetLevel(GetLevel())
+	SetLevel(InfoLevel)
+	var out bytes.Buffer
+	value := &counter{}
+	logger := NewWriterLogger(&out, tclock.NewFakePassiveClock(time.Now()), "test", "step", WithColor(ColorNever)).WithField("value", value)
+	assert.Zero(t, value.calls)
+	// lines filtered by the level are never rendered
+	logger.LogLevel(DebugLevel, Apply, OkStatus, nil)
+	assert.Zero(t, value.calls)
+	logger.Log(Apply, OkStatus, nil)
+	assert.Equal(t, 1, value.calls)
+	assert.Contains(t, out.String(), "| APPLY     | OK    | value=rendered\n")
+}
+
+func TestFormatFields(t *testing.T) {
+	var nilPointer *nilStringer
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{name: "string", value: "staging", want: "key=staging"},
+		{name: "int", value: 3, want: "key=3"},
+		{name: "bool", value: true, want: "key=true"},
+		{name: "stringer", value: &nilStringer{value: "custom"}, want: "key=custom"},
+		{name: "error", value: errors.New("not found"), want: `key="not found"`},
+		{name: "nil", value: nil, want: "key=<nil>"},
+		{name: "nil stringer", value: nilPointer, want: "key=<nil>"},
+		{name: "empty", value: "", want: `key=""`},
+		{name: "spaces", value: "two words", want: `key="two words"`},
+		{name: "equal sign", value: "a=b", want: `key="a=b"`},
+		{name: "quotes", value: `say "hi"`, want: `key="say \"hi\""`},
+		{name: "lines", value: "one\ntwo", want: `key="one\ntwo"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, formatFields(Fields{{Key: "key", Value: tt.value}}))
+		})
+	}
+	assert.Equal(t, "attempt=3 cluster=staging", formatFields(Fields{{Key: "attempt", Value: 3}, {Key: "cluster", Value: "staging"}}))
+}
+
+func TestFields_Text(t *testing.T) {
+	var pod unstructured.Unstructured
+	pod.SetAPIVersion("v1")
+	pod.SetKind("Pod")
+	pod.SetName("nginx")
+	var out bytes.Buffer
+	logger := NewWriterLogger(&out, tclock.NewFakePassiveClock(time.Now()), "test", "step", WithColor(ColorNever), WithTimestampLayout(NoTimestamp), WithContinuation(PrefixContinuation))
+	logger = logger.WithFields(map[string]any{"cluster": "staging", "attempt": 3})
+	logger.Log(Apply, RunStatus, nil)
+	logger.WithResource(&pod).Log(Apply, OkStatus, nil, s("created"))
+	// continuations repeat the prefix, not the resource nor the fields
+	assert.Equal(t, `| test | step | APPLY     | RUN   | attempt=3 cluster=staging
+| test | step | APPLY     | OK    | v1/Pod @ nginx attempt=3 cluster=staging
+| test | step | APPLY     | OK    | created
+`, out.String())
+}
+
+func TestFields_JSON(t *testing.T) {
+	var out bytes.Buffer
+	logger := NewLogger(&tlogging.FakeTLogger{}, tclock.NewFakePassiveClock(time.Now()), "test", "step", WithJSON(NewJSONWriter(&out)), WithoutText())
+	quantity := resource.MustParse("1Gi")
+	logger.WithFields(map[string]any{
+		"attempt":  3,
+		"cluster":  "staging",
+		"error":    errors.New("not found"),
+		"memory":   quantity,
+		"nil":      nil,
+		"ready":    false,
+		"channel":  make(chan int),
+		"stringer": &nilStringer{value: "custom"},
+	}).Log(Apply, OkStatus, nil)
+	var line struct {
+		Fields map[string]any `json:"fields"`
+	}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &line))
+	// values that can't be marshaled are rendered
+	assert.True(t, strings.HasPrefix(line.Fields["channel"].(string), "0x"))
+	delete(line.Fields, "channel")
+	assert.Equal(t, map[string]any{
+		"attempt":  float64(3),
+		"cluster":  "staging",
+		"error":    "not found",
+		"memory":   "1Gi",
+		"nil":      nil,
+		"ready":    false,
+		"stringer": "custom",
+	}, line.Fields)
+}
+
+func TestFields_Redacted(t *testing.T) {
+	var entries []Entry
+	logger := NewSinkLogger(sinkFunc(func(entry Entry) error {
+		entries = append(entries, entry)
+		return nil
+	}), tclock.NewFakePassiveClock(time.Now()), "test", "step", WithRedactor(NewRedactor([]string{"s3cr3t"})))
+	logger.WithFields(map[string]any{"token": "Bearer s3cr3t", "attempt": 3}).Log(Apply, OkStatus, nil)
+	assert.Equal(t, Fields{{Key: "attempt", Value: 3}, {Key: "token", Value: "Bearer " + Redacted}}, entries[0].Fields)
+}
+
+func TestFields_Adapters(t *testing.T) {
+	var out bytes.Buffer
+	logger := NewSinkLogger(NewSlogEntrySink(slog.New(slog.NewJSONHandler(&out, nil))), tclock.NewFakePassiveClock(time.Now()), "test", "step")
+	logger.WithFields(map[string]any{"cluster": "staging", "stringer": &nilStringer{value: "custom"}}).Log(Apply, OkStatus, nil)
+	var line map[string]any
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &line))
+	assert.Equal(t, map[string]any{"cluster": "staging", "stringer": "custom"}, line["fields"])
+}
+
+func TestDedupe_Fields(t *testing.T) {
+	var messages []string
+	fakeClock := tclock.NewFakePassiveClock(time.Now())
+	logger := NewSinkLogger(sinkFunc(func(entry Entry) error {
+		messages = append(messages, formatFields(entry.Fields)+" "+entry.Message)
+		return nil
+	}), fakeClock, "test", "step")
+	deduper := Dedupe(logger, fakeClock)
+	first := deduper.WithField("attempt", 1)
+	first.Log(Assert, ErrorStatus, nil, s("not ready"))
+	first.Log(Assert, ErrorStatus, nil, s("not ready"))
+	// lines with other fields are different lines
+	deduper.WithField("attempt", 2).Log(Assert, ErrorStatus, nil, s("not ready"))
+	deduper.Flush()
+	assert.Equal(t, []string{
+		"attempt=1 not ready",
+		"attempt=1 … repeated 1 time over 0s",
+		"attempt=2 not ready",
+	}, messages)
+}
@@ -0,0 +1,142 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kyverno/chainsaw/pkg/report"
+)
+
+// GitHubActionsEnv is the environment variable set to "true" by GitHub Actions runners.
+const GitHubActionsEnv = "GITHUB_ACTIONS"
+
+// GitHubActions returns true when running in GitHub Actions.
+func GitHubActions() bool {
+	value, _ := lookupEnv(GitHubActionsEnv)
+	return value == "true"
+}
+
+// GitHubGroups buffers the log lines of each test and writes them in a collapsible group when the test completes,
+// errors are also written as annotations to surface them in the run summary. It is safe for concurrent use.
+// Actions doesn't support nested groups, steps are separated by plain lines.
+type GitHubGroups struct {
+	lock     sync.Mutex
+	w        io.Writer
+	captures *Captures
+	tests    map[TestKey]*githubTest
+}
+
+// githubTest is the state of a started test.
+type githubTest struct {
+	step   string
+	errors []string
+}
+
+// NewGitHubGroups returns GitHubGroups writing to w, at most maxLines lines are kept per test.
+func NewGitHubGroups(w io.Writer, maxLines int) *GitHubGroups {
+	return &GitHubGroups{
+		w:        w,
+		captures: NewCaptures(maxLines),
+		tests:    map[TestKey]*githubTest{},
+	}
+}
+
+// Start starts buffering the lines of test, lines of tests not started are dropped, they are not grouped.
+func (g *GitHubGroups) Start(test TestKey) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.tests[test] = &githubTest{}
+}
+
+func (g *GitHubGroups) WriteEntry(entry Entry) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	test, ok := g.tests[entry.TestKey()]
+	if !ok {
+		return nil
+	}
+	if step := strings.TrimSpace(entry.Step); step != test.step {
+		test.step = step
+		if step != "" {
+			g.captures.add(entry.TestKey(), report.LogLine{Time: entry.Time, Message: "----- " + step})
+		}
+	}
+	if entry.Level == ErrorLevel {
+		test.errors = append(test.errors, githubError(entry))
+	}
+	return g.captures.WriteEntry(entry)
+}
+
+// Complete writes the group of test, and its errors. A failed test without errors gets a generic annotation.
+func (g *GitHubGroups) Complete(test TestKey, failed bool) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	return g.complete(test, failed)
+}
+
+func (g *GitHubGroups) complete(test TestKey, failed bool) error {
+	state, ok := g.tests[test]
+	if !ok {
+		return nil
+	}
+	delete(g.tests, test)
+	var b strings.Builder
+	fmt.Fprintf(&b, "::group::%s\n", githubEscape(test.Name))
+	for _, line := range g.captures.Take(test) {
+		b.WriteString(line.Message)
+		b.WriteString("\n")
+	}
+	b.WriteString("::endgroup::\n")
+	errors := state.errors
+	if failed && len(errors) == 0 {
+		errors = append(errors, fmt.Sprintf("::error title=%s::test failed", githubEscapeProperty(test.Name)))
+	}
+	for _, line := range errors {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(g.w, b.String())
+	return err
+}
+
+// Close writes the groups of tests not completed, typically when the run is interrupted.
+func (g *GitHubGroups) Close() error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	tests := make([]TestKey, 0, len(g.tests))
+	for test := range g.tests {
+		tests = append(tests, test)
+	}
+	sort.Slice(tests, func(i, j int) bool { return tests[i].Less(tests[j]) })
+	for _, test := range tests {
+		if err := g.complete(test, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// githubError renders entry as an error annotation titled after its test.
+func githubError(entry Entry) string {
+	message := fmt.Sprintf("%s %s", entry.Operation, entry.Status)
+	if step := strings.TrimSpace(entry.Step); step != "" {
+		message = step + " " + message
+	}
+	if entry.Message != "" {
+		message += ": " + jsonMessage(entry.Message)
+	}
+	return fmt.Sprintf("::error title=%s::%s", githubEscapeProperty(entry.Test), githubEscape(message))
+}
+
+// githubEscape escapes the message of a workflow command.
+func githubEscape(value string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(value)
+}
+
+// githubEscapeProperty escapes a property of a workflow command.
+func githubEscapeProperty(value string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(value)
+}
@@ -0,0 +1,101 @@
+package logging
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
+	"github.com/stretchr/testify/assert"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+func TestGitHubActions(t *testing.T) {
+	defer func(f func(string) (string, bool)) { lookupEnv = f }(lookupEnv)
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{{
+		name: "not set",
+	}, {
+		name: "true",
+		env:  map[string]string{GitHubActionsEnv: "true"},
+		want: true,
+	}, {
+		name: "false",
+		env:  map[string]string{GitHubActionsEnv: "false"},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookupEnv = func(name string) (string, bool) {
+				value, ok := tt.env[name]
+				return value, ok
+			}
+			assert.Equal(t, tt.want, GitHubActions())
+		})
+	}
+}
+
+func TestGitHubGroups(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	var out bytes.Buffer
+	groups := NewGitHubGroups(&out, 0)
+	mockT := &tlogging.FakeTLogger{}
+	logger := func(test, step string) Logger {
+		return NewLogger(mockT, fakeClock, test, step, WithSink(groups), WithoutText(), WithColor(ColorAlways), WithGlyphs(GlyphASCII))
+	}
+	// lines of tests not started are dropped
+	logger("chainsaw", "@main").Log(Script, LogStatus, nil, s("main"))
+	groups.Start(TestKey{Name: "foo"})
+	groups.Start(TestKey{Name: "bar"})
+	logger("foo", "step-1  ").Log(Apply, RunStatus, nil)
+	logger("bar", "step-1  ").Log(Apply, RunStatus, nil)
+	Failure(logger("foo", "step-2  "), Assert, ErrorStatus, s("boom\n100%"))
+	logger("foo", "@cleanup").Log(Delete, DoneStatus, nil)
+	assert.NoError(t, groups.Complete(TestKey{Name: "foo"}, true))
+	assert.Equal(t, `::group::foo
+----- step-1
+| 10:30:00 | foo | step-1   | APPLY     | RUN   |
+----- step-2
+[fail] | 10:30:00 | foo | step-2   | ASSERT    | ERROR |
+boom
+100%
+----- @cleanup
+| 10:30:00 | foo | @cleanup | DELETE    | DONE  |
+::endgroup::
+::error title=foo::step-2 ASSERT ERROR: boom%0A100%25
+`, out.String())
+	out.Reset()
+	// completing a test twice is a no-op
+	assert.NoError(t, groups.Complete(TestKey{Name: "foo"}, true))
+	assert.Empty(t, out.String())
+	// failed tests without an error line get a generic annotation, titles are escaped
+	groups.Start(TestKey{Name: "a:b"})
+	assert.NoError(t, groups.Complete(TestKey{Name: "a:b"}, true))
+	assert.Equal(t, "::group::a:b\n::endgroup::\n::error title=a%3Ab::test failed\n", out.String())
+	out.Reset()
+	// tests not completed are written on close
+	assert.NoError(t, groups.Close())
+	assert.Equal(t, "::group::bar\n----- step-1\n| 10:30:00 | bar | step-1   | APPLY     | RUN   |\n::endgroup::\n", out.String())
+	assert.Empty(t, mockT.Messages)
+}
+
+func TestGitHubGroups_DuplicateNames(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	var out bytes.Buffer
+	groups := NewGitHubGroups(&out, 0)
+	logger := func(path string) Logger {
+		return NewLogger(&tlogging.FakeTLogger{}, fakeClock, "test", "step-1", WithSink(groups), WithoutText(), WithTestPath(path))
+	}
+	groups.Start(TestKey{Path: "tests/a", Name: "test"})
+	groups.Start(TestKey{Path: "tests/b", Name: "test"})
+	logger("tests/a").Log(Script, LogStatus, nil, s("a"))
+	logger("tests/b").Log(Script, LogStatus, nil, s("b"))
+	// the tests sharing a name are grouped apart
+	assert.NoError(t, groups.Complete(TestKey{Path: "tests/b", Name: "test"}, false))
+	assert.Equal(t, "::group::test\n----- step-1\n| 10:30:00 | test | step-1 | SCRIPT    | LOG   |\nb\n::endgroup::\n", out.String())
+	out.Reset()
+	assert.NoError(t, groups.Close())
+	assert.Equal(t, "::group::test\n----- step-1\n| 10:30:00 | test | step-1 | SCRIPT    | LOG   |\na\n::endgroup::\n", out.String())
+}
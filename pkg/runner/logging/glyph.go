@@ -0,0 +1,119 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// Outcome identifies the outcome a style renders, outcomes are marked with glyphs at the start of log lines.
+type Outcome string
+
+const (
+	SuccessOutcome Outcome = "success"
+	FailureOutcome Outcome = "failure"
+	SkipOutcome    Outcome = "skip"
+)
+
+// Glyphs are the markers of outcomes printed at the start of log lines.
+type Glyphs struct {
+	Success string
+	Failure string
+	Skip    string
+}
+
+// UnicodeGlyphs returns the unicode glyphs, ✓, ✗ and ⏭.
+func UnicodeGlyphs() Glyphs {
+	return Glyphs{Success: "✓", Failure: "✗", Skip: "⏭"}
+}
+
+// ASCIIGlyphs returns the plain ASCII glyphs, [ok], [fail] and [skip], for consoles mangling non-ASCII characters.
+func ASCIIGlyphs() Glyphs {
+	return Glyphs{Success: "[ok]", Failure: "[fail]", Skip: "[skip]"}
+}
+
+// Glyph returns the glyph of outcome, or an empty string when the outcome has none.
+func (g Glyphs) Glyph(outcome Outcome) string {
+	switch outcome {
+	case SuccessOutcome:
+		return g.Success
+	case FailureOutcome:
+		return g.Failure
+	case SkipOutcome:
+		return g.Skip
+	default:
+		return ""
+	}
+}
+
+// GlyphMode determines the glyphs marking outcomes in log lines.
+type GlyphMode string
+
+const (
+	// GlyphAuto uses the unicode glyphs when stdout is a terminal and the locale advertises UTF-8, the ASCII glyphs otherwise.
+	GlyphAuto GlyphMode = "auto"
+	// GlyphUnicode always uses the unicode glyphs.
+	GlyphUnicode GlyphMode = "unicode"
+	// GlyphASCII always uses the ASCII glyphs.
+	GlyphASCII GlyphMode = "ascii"
+)
+
+// localeEnvs are the environment variables the locale is read from, the first one set wins like in POSIX.
+var localeEnvs = []string{"LC_ALL", "LC_CTYPE", "LANG"}
+
+var glyphMode atomic.Value
+
+func init() {
+	glyphMode.Store(GlyphAuto)
+}
+
+// SupportedGlyphModes returns the supported glyph modes.
+func SupportedGlyphModes() []string {
+	return []string{string(GlyphAuto), string(GlyphUnicode), string(GlyphASCII)}
+}
+
+// ParseGlyphMode parses a glyph mode name.
+func ParseGlyphMode(name string) (GlyphMode, error) {
+	for _, mode := range SupportedGlyphModes() {
+		if strings.EqualFold(name, mode) {
+			return GlyphMode(mode), nil
+		}
+	}
+	return GlyphAuto, fmt.Errorf("invalid glyph mode %q (auto|unicode|ascii)", name)
+}
+
+// Glyphs returns the glyphs of this mode.
+func (m GlyphMode) Glyphs() Glyphs {
+	switch m {
+	case GlyphUnicode:
+		return UnicodeGlyphs()
+	case GlyphASCII:
+		return ASCIIGlyphs()
+	default:
+		if stdoutIsTerminal() && localeIsUTF8() {
+			return UnicodeGlyphs()
+		}
+		return ASCIIGlyphs()
+	}
+}
+
+// localeIsUTF8 returns true if the locale of the environment uses the UTF-8 encoding, like en_US.UTF-8 or C.utf8.
+func localeIsUTF8() bool {
+	for _, name := range localeEnvs {
+		if value, ok := lookupEnv(name); ok && value != "" {
+			value = strings.ToLower(value)
+			return strings.Contains(value, "utf-8") || strings.Contains(value, "utf8")
+		}
+	}
+	return false
+}
+
+// SetGlyphMode sets the glyph mode of the loggers created afterwards without the WithGlyphs option.
+func SetGlyphMode(mode GlyphMode) {
+	glyphMode.Store(mode)
+}
+
+// GetGlyphMode returns the glyph mode of the loggers created without the WithGlyphs option.
+func GetGlyphMode() GlyphMode {
+	return glyphMode.Load().(GlyphMode)
+}
@@ -0,0 +1,154 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/report"
+	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
+	"github.com/stretchr/testify/assert"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+func TestGlyphMode_Glyphs(t *testing.T) {
+	defer func(f func(string) (string, bool), g func() bool) { lookupEnv, stdoutIsTerminal = f, g }(lookupEnv, stdoutIsTerminal)
+	tests := []struct {
+		name     string
+		mode     GlyphMode
+		env      map[string]string
+		terminal bool
+		want     Glyphs
+	}{{
+		name:     "auto with UTF-8 LANG",
+		mode:     GlyphAuto,
+		env:      map[string]string{"LANG": "en_US.UTF-8"},
+		terminal: true,
+		want:     UnicodeGlyphs(),
+	}, {
+		name:     "auto with utf8 LANG",
+		mode:     GlyphAuto,
+		env:      map[string]string{"LANG": "C.utf8"},
+		terminal: true,
+		want:     UnicodeGlyphs(),
+	}, {
+		name:     "auto with non UTF-8 LANG",
+		mode:     GlyphAuto,
+		env:      map[string]string{"LANG": "en_US.ISO-8859-1"},
+		terminal: true,
+		want:     ASCIIGlyphs(),
+	}, {
+		name:     "auto with POSIX LANG",
+		mode:     GlyphAuto,
+		env:      map[string]string{"LANG": "C"},
+		terminal: true,
+		want:     ASCIIGlyphs(),
+	}, {
+		name:     "auto without locale",
+		mode:     GlyphAuto,
+		terminal: true,
+		want:     ASCIIGlyphs(),
+	}, {
+		name:     "auto with LC_ALL overriding LANG",
+		mode:     GlyphAuto,
+		env:      map[string]string{"LC_ALL": "C", "LANG": "en_US.UTF-8"},
+		terminal: true,
+		want:     ASCIIGlyphs(),
+	}, {
+		name:     "auto with UTF-8 LC_ALL",
+		mode:     GlyphAuto,
+		env:      map[string]string{"LC_ALL": "fr_FR.UTF-8", "LANG": "C"},
+		terminal: true,
+		want:     UnicodeGlyphs(),
+	}, {
+		name:     "auto with empty LC_ALL",
+		mode:     GlyphAuto,
+		env:      map[string]string{"LC_ALL": "", "LANG": "en_US.UTF-8"},
+		terminal: true,
+		want:     UnicodeGlyphs(),
+	}, {
+		name:     "auto with LC_CTYPE",
+		mode:     GlyphAuto,
+		env:      map[string]string{"LC_CTYPE": "UTF-8", "LANG": "C"},
+		terminal: true,
+		want:     UnicodeGlyphs(),
+	}, {
+		name: "auto not on terminal",
+		mode: GlyphAuto,
+		env:  map[string]string{"LANG": "en_US.UTF-8"},
+		want: ASCIIGlyphs(),
+	}, {
+		name:     "unicode",
+		mode:     GlyphUnicode,
+		env:      map[string]string{"LANG": "C"},
+		terminal: false,
+		want:     UnicodeGlyphs(),
+	}, {
+		name:     "ascii",
+		mode:     GlyphASCII,
+		env:      map[string]string{"LANG": "en_US.UTF-8"},
+		terminal: true,
+		want:     ASCIIGlyphs(),
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookupEnv = func(name string) (string, bool) {
+				value, ok := tt.env[name]
+				return value, ok
+			}
+			stdoutIsTerminal = func() bool { return tt.terminal }
+			assert.Equal(t, tt.want, tt.mode.Glyphs())
+		})
+	}
+}
+
+func TestParseGlyphMode(t *testing.T) {
+	for _, name := range []string{"auto", "unicode", "ascii", "ASCII"} {
+		mode, err := ParseGlyphMode(name)
+		assert.NoError(t, err)
+		assert.Equal(t, GlyphMode(strings.ToLower(name)), mode)
+	}
+	_, err := ParseGlyphMode("emoji")
+	assert.Error(t, err)
+}
+
+func TestGlyphs_Glyph(t *testing.T) {
+	glyphs := ASCIIGlyphs()
+	assert.Equal(t, "[ok]", glyphs.Glyph(SuccessOutcome))
+	assert.Equal(t, "[fail]", glyphs.Glyph(FailureOutcome))
+	assert.Equal(t, "[skip]", glyphs.Glyph(SkipOutcome))
+	assert.Empty(t, glyphs.Glyph(""))
+}
+
+func TestFormatText_Glyphs(t *testing.T) {
+	entry := Entry{
+		Time:      time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC),
+		Test:      "test",
+		Step:      "step",
+		Operation: Apply,
+		Status:    DoneStatus,
+		Style:     GetPalette().Success,
+	}
+	// without glyphs the marker is printed
+	assert.Equal(t, "OK   | 10:30:00 | test | step | APPLY     | DONE  |", FormatText(entry, false))
+	entry.Glyphs = UnicodeGlyphs()
+	assert.Equal(t, "✓ | 10:30:00 | test | step | APPLY     | DONE  |", FormatText(entry, false))
+	entry.Glyphs = ASCIIGlyphs()
+	assert.Equal(t, "[ok] | 10:30:00 | test | step | APPLY     | DONE  |", FormatText(entry, false))
+	// styles without an outcome keep their marker
+	entry.Style = GetPalette().Warning
+	assert.Equal(t, "WARN | 10:30:00 | test | step | APPLY     | DONE  |", FormatText(entry, false))
+}
+
+func TestCaptures_Glyphs(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	captures := NewCaptures(0)
+	mockT := &tlogging.FakeTLogger{}
+	Success(NewLogger(mockT, fakeClock, "test", "step", WithCapture(captures), WithColor(ColorNever), WithGlyphs(GlyphUnicode)), Apply, DoneStatus)
+	assert.Equal(t, []string{"\b\b\b\b\b\b\b\b\b✓ | 10:30:00 | test | step | APPLY     | DONE  |"}, mockT.Messages)
+	// captured lines flow into reports, they are marked with the ASCII glyphs
+	assert.Equal(t, []report.LogLine{{
+		Time:    fakeClock.Now(),
+		Message: "[ok] | 10:30:00 | test | step | APPLY     | DONE  |",
+	}}, captures.Lines(TestKey{Name: "test"}))
+}
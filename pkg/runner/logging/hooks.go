@@ -0,0 +1,147 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// hooks are the functions registered with OnEntry, and the counters of the lines they are called with.
+var hooks = &hookList{
+	onError: func(err error) {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+	},
+}
+
+type hookList struct {
+	lock    sync.RWMutex
+	next    int
+	hooks   []hook
+	onError func(error)
+	counts  lineCounter
+}
+
+type hook struct {
+	id int
+	fn func(Entry)
+}
+
+// OnEntry registers hook, it is called with every log line of the loggers writing to sinks, like those of NewLogger,
+// NewWriterLogger and NewSinkLogger. Hooks are called synchronously once the line is written to the sinks,
+// in the order they were registered. A panicking hook is recovered and reported with the handler set with
+// SetHookErrorHandler, the other hooks still run. The returned function removes the hook.
+func OnEntry(fn func(Entry)) (remove func()) {
+	hooks.lock.Lock()
+	defer hooks.lock.Unlock()
+	hooks.next++
+	id := hooks.next
+	hooks.hooks = append(hooks.hooks, hook{id: id, fn: fn})
+	return func() {
+		hooks.lock.Lock()
+		defer hooks.lock.Unlock()
+		for i, h := range hooks.hooks {
+			if h.id == id {
+				hooks.hooks = append(hooks.hooks[:i:i], hooks.hooks[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// SetHookErrorHandler sets the function panicking hooks are reported with, they are printed to stderr by default.
+func SetHookErrorHandler(handler func(error)) {
+	hooks.lock.Lock()
+	defer hooks.lock.Unlock()
+	hooks.onError = handler
+}
+
+// runHooks counts entry and calls the registered hooks with it, hooks can read the counts of the lines up to entry.
+func runHooks(entry Entry) {
+	hooks.counts.add(entry)
+	hooks.lock.RLock()
+	registered, onError := hooks.hooks, hooks.onError
+	hooks.lock.RUnlock()
+	for _, h := range registered {
+		if err := runHook(h.fn, entry); err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
+
+func runHook(fn func(Entry), entry Entry) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("log hook panicked: %v", r)
+		}
+	}()
+	fn(entry)
+	return nil
+}
+
+// LineCounts counts log lines per level, over all tests and per test.
+type LineCounts struct {
+	Levels map[Level]int
+	Tests  map[string]map[Level]int
+}
+
+// Level returns the number of lines logged for level.
+func (c LineCounts) Level(level Level) int {
+	return c.Levels[level]
+}
+
+// Test returns the number of lines logged for level by test.
+func (c LineCounts) Test(test string, level Level) int {
+	return c.Tests[test][level]
+}
+
+// Counts returns a snapshot of the counts of the log lines passed to hooks since the start or the last call to
+// ResetCounts, it doesn't change with lines logged afterwards.
+func Counts() LineCounts {
+	return hooks.counts.snapshot()
+}
+
+// ResetCounts resets the counts of log lines, typically before a run.
+func ResetCounts() {
+	hooks.counts.reset()
+}
+
+// lineCounter counts log lines, it is safe for concurrent use.
+type lineCounter struct {
+	lock   sync.Mutex
+	counts LineCounts
+}
+
+func (c *lineCounter) add(entry Entry) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.counts.Levels == nil {
+		c.counts = LineCounts{Levels: map[Level]int{}, Tests: map[string]map[Level]int{}}
+	}
+	c.counts.Levels[entry.Level]++
+	if c.counts.Tests[entry.Test] == nil {
+		c.counts.Tests[entry.Test] = map[Level]int{}
+	}
+	c.counts.Tests[entry.Test][entry.Level]++
+}
+
+func (c *lineCounter) reset() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.counts = LineCounts{}
+}
+
+func (c *lineCounter) snapshot() LineCounts {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	out := LineCounts{Levels: map[Level]int{}, Tests: map[string]map[Level]int{}}
+	for level, count := range c.counts.Levels {
+		out.Levels[level] = count
+	}
+	for test, levels := range c.counts.Tests {
+		out.Tests[test] = map[Level]int{}
+		for level, count := range levels {
+			out.Tests[test][level] = count
+		}
+	}
+	return out
+}
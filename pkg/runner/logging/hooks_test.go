@@ -0,0 +1,118 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
+	"github.com/stretchr/testify/assert"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+func TestOnEntry(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	var calls []string
+	var written []string
+	sink := sinkFunc(func(entry Entry) error {
+		written = append(written, entry.Message)
+		return nil
+	})
+	removeFirst := OnEntry(func(entry Entry) {
+		// lines are written to the sinks first
+		calls = append(calls, fmt.Sprintf("first %s %d", entry.Message, len(written)))
+	})
+	defer removeFirst()
+	removeSecond := OnEntry(func(entry Entry) { calls = append(calls, "second "+entry.Message) })
+	defer removeSecond()
+	logger := NewSinkLogger(sink, fakeClock, "test", "step")
+	logger.Log(Apply, OkStatus, nil, s("one"))
+	removeFirst()
+	// removing a hook twice is a no-op
+	removeFirst()
+	logger.Log(Apply, OkStatus, nil, s("two"))
+	assert.Equal(t, []string{"first one 1", "second one", "second two"}, calls)
+}
+
+func TestOnEntry_Panic(t *testing.T) {
+	defer SetHookErrorHandler(hooks.onError)
+	var reported []error
+	SetHookErrorHandler(func(err error) { reported = append(reported, err) })
+	var called bool
+	defer OnEntry(func(Entry) { panic("boom") })()
+	defer OnEntry(func(Entry) { panic(errors.New("bang")) })()
+	defer OnEntry(func(Entry) { called = true })()
+	logger := NewSinkLogger(sinkFunc(func(Entry) error { return nil }), tclock.NewFakePassiveClock(time.Now()), "test", "step")
+	assert.NotPanics(t, func() { logger.Log(Apply, OkStatus, nil) })
+	// panicking hooks don't prevent the next ones from running
+	assert.True(t, called)
+	assert.Equal(t, []error{
+		errors.New("log hook panicked: boom"),
+		errors.New("log hook panicked: bang"),
+	}, reported)
+}
+
+func TestCounts(t *testing.T) {
+	ResetCounts()
+	defer ResetCounts()
+	defer func(level Level) { SetLevel(level) }(GetLevel())
+	SetLevel(InfoLevel)
+	fakeClock := tclock.NewFakePassiveClock(time.Now())
+	first := NewLogger(&tlogging.FakeTLogger{}, fakeClock, "first", "step")
+	second := NewLogger(&tlogging.FakeTLogger{}, fakeClock, "second", "step")
+	first.Log(Apply, OkStatus, nil)
+	Failure(first, Assert, ErrorStatus)
+	Warn(second, Assert, WarnStatus)
+	// lines filtered by the level are not counted
+	second.LogLevel(DebugLevel, Apply, OkStatus, nil)
+	counts := Counts()
+	assert.Equal(t, 1, counts.Level(InfoLevel))
+	assert.Equal(t, 1, counts.Level(ErrorLevel))
+	assert.Equal(t, 1, counts.Level(WarnLevel))
+	assert.Zero(t, counts.Level(DebugLevel))
+	assert.Equal(t, 1, counts.Test("first", ErrorLevel))
+	assert.Zero(t, counts.Test("second", ErrorLevel))
+	assert.Equal(t, 1, counts.Test("second", WarnLevel))
+	assert.Zero(t, counts.Test("unknown", InfoLevel))
+	// snapshots don't change with lines logged afterwards
+	first.Log(Apply, OkStatus, nil)
+	assert.Equal(t, 1, counts.Level(InfoLevel))
+	assert.Equal(t, 2, Counts().Level(InfoLevel))
+	ResetCounts()
+	assert.Equal(t, LineCounts{Levels: map[Level]int{}, Tests: map[string]map[Level]int{}}, Counts())
+}
+
+func TestCounts_Concurrent(t *testing.T) {
+	ResetCounts()
+	defer ResetCounts()
+	var lock sync.Mutex
+	hooked := map[string]int{}
+	defer OnEntry(func(entry Entry) {
+		lock.Lock()
+		defer lock.Unlock()
+		hooked[entry.Test]++
+	})()
+	fakeClock := tclock.NewFakePassiveClock(time.Now())
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(test string) {
+			defer wg.Done()
+			logger := NewSinkLogger(sinkFunc(func(Entry) error { return nil }), fakeClock, test, "step")
+			for j := 0; j < 100; j++ {
+				Failure(logger, Assert, ErrorStatus)
+				_ = Counts()
+			}
+		}(fmt.Sprintf("test-%d", i))
+	}
+	wg.Wait()
+	counts := Counts()
+	assert.Equal(t, 800, counts.Level(ErrorLevel))
+	for i := 0; i < 8; i++ {
+		test := fmt.Sprintf("test-%d", i)
+		assert.Equal(t, 100, counts.Test(test, ErrorLevel))
+		assert.Equal(t, 100, hooked[test])
+	}
+}
@@ -0,0 +1,149 @@
+package logging
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/kyverno/chainsaw/pkg/client"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// HyperlinkMode determines whether the resources of log lines are hyperlinks to the page of a cluster dashboard.
+type HyperlinkMode string
+
+const (
+	// HyperlinkAuto makes resources hyperlinks when stdout is a terminal known to support them, like iTerm2, VS Code,
+	// Windows Terminal or the VTE based terminals.
+	HyperlinkAuto HyperlinkMode = "auto"
+	// HyperlinkAlways always makes resources hyperlinks.
+	HyperlinkAlways HyperlinkMode = "always"
+	// HyperlinkNever never makes resources hyperlinks.
+	HyperlinkNever HyperlinkMode = "never"
+)
+
+// SupportedHyperlinkModes returns the supported hyperlink modes.
+func SupportedHyperlinkModes() []string {
+	return []string{string(HyperlinkAuto), string(HyperlinkAlways), string(HyperlinkNever)}
+}
+
+// ParseHyperlinkMode parses a hyperlink mode name, the empty name is HyperlinkAuto.
+func ParseHyperlinkMode(name string) (HyperlinkMode, error) {
+	if name == "" {
+		return HyperlinkAuto, nil
+	}
+	for _, mode := range SupportedHyperlinkModes() {
+		if strings.EqualFold(name, mode) {
+			return HyperlinkMode(mode), nil
+		}
+	}
+	return HyperlinkAuto, fmt.Errorf("invalid hyperlink mode %q (auto|always|never)", name)
+}
+
+// Enabled returns true if resources are hyperlinks in this mode.
+func (m HyperlinkMode) Enabled() bool {
+	switch m {
+	case HyperlinkAlways:
+		return true
+	case HyperlinkNever:
+		return false
+	default:
+		return stdoutIsTerminal() && terminalSupportsHyperlinks()
+	}
+}
+
+// terminalSupportsHyperlinks returns true if the environment is that of a terminal known to render OSC 8 hyperlinks.
+// Terminals not supporting them print the text of the link, but there is no way to ask them.
+func terminalSupportsHyperlinks() bool {
+	if value, _ := lookupEnv("TERM"); value == "dumb" {
+		return false
+	}
+	for _, name := range []string{"WT_SESSION", "KITTY_WINDOW_ID", "WEZTERM_PANE", "KONSOLE_VERSION", "DOMTERM"} {
+		if value, ok := lookupEnv(name); ok && value != "" {
+			return true
+		}
+	}
+	switch value, _ := lookupEnv("TERM_PROGRAM"); value {
+	case "iTerm.app", "vscode", "WezTerm", "Hyper", "ghostty":
+		return true
+	}
+	// VTE based terminals, like GNOME Terminal, render them since 0.50
+	if value, ok := lookupEnv("VTE_VERSION"); ok {
+		if version, err := strconv.Atoi(value); err == nil && version >= 5000 {
+			return true
+		}
+	}
+	return false
+}
+
+// ResourceLink holds the fields of a resource the URL template of ResourceLinks is executed with.
+type ResourceLink struct {
+	Group     string
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// ResourceLinks builds the URLs of the pages of resources in a cluster dashboard from a template.
+type ResourceLinks struct {
+	template *template.Template
+}
+
+// ParseResourceLinks parses a URL template like https://dashboard/#/{{.Kind}}/{{.Namespace}}/{{.Name}}, see
+// ResourceLink for its fields. Templates referencing unknown fields or failing to execute are rejected.
+func ParseResourceLinks(text string) (*ResourceLinks, error) {
+	tmpl, err := template.New("link").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid resource link template %q: %w", text, err)
+	}
+	links := &ResourceLinks{template: tmpl}
+	// templates fail when the configuration is loaded rather than on the first line of a resource
+	if _, err := links.url(ResourceLink{Group: "apps", Kind: "Deployment", Namespace: "default", Name: "name"}); err != nil {
+		return nil, fmt.Errorf("invalid resource link template %q: %w", text, err)
+	}
+	return links, nil
+}
+
+func (r *ResourceLinks) url(link ResourceLink) (string, error) {
+	var b strings.Builder
+	if err := r.template.Execute(&b, link); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// URL returns the URL of the page of resource.
+func (r *ResourceLinks) URL(resource ctrlclient.Object) (string, error) {
+	gvk := resource.GetObjectKind().GroupVersionKind()
+	key := client.ObjectKey(resource)
+	return r.url(ResourceLink{Group: gvk.Group, Kind: gvk.Kind, Namespace: key.Namespace, Name: key.Name})
+}
+
+// Hyperlink returns text as an OSC 8 hyperlink to url, terminals not supporting them print text only.
+func Hyperlink(url string, text string) string {
+	return "\x1b]8;;" + url + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
+
+// WithResourceLinks makes the resources of the human readable lines written to the terminal hyperlinks to the URLs of
+// links, when mode is enabled. Lines without colors, like those of log files, and captured lines never hold them.
+func WithResourceLinks(links *ResourceLinks, mode HyperlinkMode) Option {
+	return func(l *logger) {
+		l.links = nil
+		if mode.Enabled() {
+			l.links = links
+		}
+	}
+}
+
+// resourceLink returns the resource text of entry as a hyperlink when its logger has resource links, as is otherwise.
+func resourceLink(entry Entry, text string) string {
+	if entry.ResourceLinks == nil {
+		return text
+	}
+	url, err := entry.ResourceLinks.URL(entry.Resource)
+	if err != nil || url == "" {
+		return text
+	}
+	return Hyperlink(url, text)
+}
@@ -0,0 +1,127 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/report"
+	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+func TestParseHyperlinkMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    HyperlinkMode
+		wantErr bool
+	}{
+		{name: "", want: HyperlinkAuto},
+		{name: "auto", want: HyperlinkAuto},
+		{name: "Always", want: HyperlinkAlways},
+		{name: "never", want: HyperlinkNever},
+		{name: "sometimes", want: HyperlinkAuto, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseHyperlinkMode(tt.name)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestHyperlinkMode_Enabled(t *testing.T) {
+	defer func(f func(string) (string, bool), g func() bool) {
+		lookupEnv, stdoutIsTerminal = f, g
+	}(lookupEnv, stdoutIsTerminal)
+	tests := []struct {
+		name     string
+		mode     HyperlinkMode
+		env      map[string]string
+		terminal bool
+		want     bool
+	}{
+		{name: "auto on iTerm2", mode: HyperlinkAuto, env: map[string]string{"TERM_PROGRAM": "iTerm.app"}, terminal: true, want: true},
+		{name: "auto on Windows Terminal", mode: HyperlinkAuto, env: map[string]string{"WT_SESSION": "1"}, terminal: true, want: true},
+		{name: "auto on recent VTE", mode: HyperlinkAuto, env: map[string]string{"VTE_VERSION": "6003"}, terminal: true, want: true},
+		{name: "auto on old VTE", mode: HyperlinkAuto, env: map[string]string{"VTE_VERSION": "4205"}, terminal: true},
+		{name: "auto on dumb terminal", mode: HyperlinkAuto, env: map[string]string{"TERM": "dumb", "WT_SESSION": "1"}, terminal: true},
+		{name: "auto on unknown terminal", mode: HyperlinkAuto, terminal: true},
+		{name: "auto not on terminal", mode: HyperlinkAuto, env: map[string]string{"TERM_PROGRAM": "vscode"}},
+		{name: "always", mode: HyperlinkAlways, want: true},
+		{name: "never", mode: HyperlinkNever, env: map[string]string{"TERM_PROGRAM": "vscode"}, terminal: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookupEnv = func(name string) (string, bool) {
+				value, ok := tt.env[name]
+				return value, ok
+			}
+			stdoutIsTerminal = func() bool { return tt.terminal }
+			assert.Equal(t, tt.want, tt.mode.Enabled())
+		})
+	}
+}
+
+func TestParseResourceLinks(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		wantErr  bool
+	}{
+		{name: "valid", template: "https://dashboard/#/{{.Kind}}/{{.Namespace}}/{{.Name}}?group={{.Group}}"},
+		{name: "static", template: "https://dashboard"},
+		{name: "unknown field", template: "https://dashboard/{{.Cluster}}", wantErr: true},
+		{name: "syntax", template: "https://dashboard/{{.Name", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseResourceLinks(tt.template)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWithResourceLinks(t *testing.T) {
+	links, err := ParseResourceLinks("https://dashboard/#/{{.Group}}/{{.Kind}}/{{.Namespace}}/{{.Name}}")
+	require.NoError(t, err)
+	resource := &unstructured.Unstructured{}
+	resource.SetAPIVersion("apps/v1")
+	resource.SetKind("Deployment")
+	resource.SetNamespace("default")
+	resource.SetName("nginx")
+	url, err := links.URL(resource)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://dashboard/#/apps/Deployment/default/nginx", url)
+	assert.Equal(t, "\x1b]8;;https://dashboard\x1b\\text\x1b]8;;\x1b\\", Hyperlink("https://dashboard", "text"))
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	log := func(options ...Option) string {
+		mockT := &tlogging.FakeTLogger{}
+		NewLogger(mockT, fakeClock, "test", "step", options...).WithResource(resource).Log(Apply, OkStatus, nil)
+		require.Len(t, mockT.Messages, 1)
+		return strings.TrimPrefix(mockT.Messages[0], eraser)
+	}
+	plain := "| 10:30:00 | test | step | APPLY     | OK    | apps/v1/Deployment @ default/nginx"
+	assert.Equal(t, "| 10:30:00 | test | step | APPLY     | OK    | \x1b]8;;https://dashboard/#/apps/Deployment/default/nginx\x1b\\apps/v1/Deployment @ default/nginx\x1b]8;;\x1b\\",
+		log(WithColor(ColorAlways), WithResourceLinks(links, HyperlinkAlways)))
+	// lines without colors and modes disabled fall back to the plain resource
+	assert.Equal(t, plain, log(WithColor(ColorNever), WithResourceLinks(links, HyperlinkAlways)))
+	assert.Equal(t, plain, log(WithColor(ColorAlways), WithResourceLinks(links, HyperlinkNever)))
+	assert.Equal(t, plain, log(WithColor(ColorAlways)))
+	// log files are written without colors
+	assert.NotContains(t, FormatText(Entry{Resource: resource, ResourceLinks: links}, false), "\x1b]8")
+	// captured lines are stripped of the hyperlinks
+	assert.Equal(t, plain, report.StripANSI(log(WithColor(ColorAlways), WithResourceLinks(links, HyperlinkAlways))))
+}
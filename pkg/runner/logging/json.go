@@ -0,0 +1,146 @@
+package logging
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/client"
+	"github.com/kyverno/chainsaw/pkg/report"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Format is the format of the log lines printed on the console.
+type Format string
+
+const (
+	// TextFormat prints human readable log lines.
+	TextFormat Format = "text"
+	// JSONFormat prints one JSON object per log line.
+	JSONFormat Format = "json"
+)
+
+// SupportedFormats returns the supported console log formats.
+func SupportedFormats() []string {
+	return []string{string(TextFormat), string(JSONFormat)}
+}
+
+// JSONWriter writes log lines as JSON objects, one per line, it is safe for concurrent use.
+type JSONWriter struct {
+	lock sync.Mutex
+	w    io.Writer
+}
+
+func NewJSONWriter(w io.Writer) *JSONWriter {
+	return &JSONWriter{w: w}
+}
+
+// JSONLine is a log line written by a JSONWriter.
+type JSONLine struct {
+	Timestamp time.Time `json:"timestamp"`
+	Level     string    `json:"level"`
+	Test      string    `json:"test"`
+	Step      string    `json:"step"`
+	// OperationName and OperationType identify the operation the line is logged for, if any.
+	OperationName string               `json:"operationName,omitempty"`
+	OperationType report.OperationType `json:"operationType,omitempty"`
+	// TestID and OperationID are the correlation identifiers of the test and the operation in the report, if any.
+	TestID      string `json:"testId,omitempty"`
+	OperationID string `json:"operationId,omitempty"`
+	// Worker is the worker slot the test runs in, if printed.
+	Worker int `json:"worker,omitempty"`
+	// Namespace is the namespace of the test, if known.
+	Namespace string         `json:"namespace,omitempty"`
+	Operation Operation      `json:"operation"`
+	Status    Status         `json:"status"`
+	Resource  *JSONResource  `json:"resource,omitempty"`
+	Resources []JSONResource `json:"resources,omitempty"`
+	Message   string         `json:"message,omitempty"`
+	// Fields are the structured fields of the line, if any.
+	Fields map[string]json.RawMessage `json:"fields,omitempty"`
+	// Caller is the location of the code logging the line, if recorded.
+	Caller string `json:"caller,omitempty"`
+}
+
+// JSONResource identifies the resource a log line is about.
+type JSONResource struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+}
+
+func jsonResource(resource ctrlclient.Object) *JSONResource {
+	if resource == nil {
+		return nil
+	}
+	gvk := resource.GetObjectKind().GroupVersionKind()
+	key := client.ObjectKey(resource)
+	return &JSONResource{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Namespace:  key.Namespace,
+		Name:       key.Name,
+	}
+}
+
+// jsonResources identifies the resources of a line about several resources, they are always listed.
+func jsonResources(resources []ctrlclient.Object) []JSONResource {
+	if len(resources) == 0 {
+		return nil
+	}
+	out := make([]JSONResource, 0, len(resources))
+	for _, resource := range resources {
+		out = append(out, *jsonResource(resource))
+	}
+	return out
+}
+
+// jsonMessage strips color codes from a message.
+func jsonMessage(message string) string {
+	return report.StripANSI(message)
+}
+
+func (w *JSONWriter) WriteEntry(entry Entry) error {
+	// steps are padded for alignment in the human readable output
+	return w.Write(JSONLine{
+		Timestamp:     entry.Time,
+		Level:         LevelName(entry.Level),
+		Test:          entry.Test,
+		Step:          strings.TrimSpace(entry.Step),
+		OperationName: entry.OperationName,
+		OperationType: entry.OperationType,
+		TestID:        entry.TestID,
+		OperationID:   entry.OperationID,
+		Worker:        entry.Worker,
+		Namespace:     entry.Namespace,
+		Operation:     entry.Operation,
+		Status:        entry.Status,
+		Resource:      jsonResource(entry.Resource),
+		Resources:     jsonResources(entry.Resources),
+		Message:       jsonMessage(entry.Message),
+		Fields:        jsonFields(entry.Fields),
+		Caller:        entry.Caller,
+	})
+}
+
+// Flush flushes the writer, files are committed to disk.
+func (w *JSONWriter) Flush() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	return flushWriter(w.w)
+}
+
+func (w *JSONWriter) Write(line JSONLine) error {
+	data, err := json.Marshal(line)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	_, err = w.w.Write(data)
+	return err
+}
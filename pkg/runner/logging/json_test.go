@@ -0,0 +1,187 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/kyverno/chainsaw/pkg/report"
+	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+func Test_logger_JSON(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	fakeClock := tclock.NewFakePassiveClock(now)
+	var resource unstructured.Unstructured
+	resource.SetAPIVersion("apps/v1")
+	resource.SetKind("Deployment")
+	resource.SetNamespace("default")
+	resource.SetName("web")
+	tests := []struct {
+		name    string
+		log     func(Logger)
+		want    map[string]any
+		wantLog bool
+	}{{
+		name: "without resource",
+		log: func(l Logger) {
+			l.Log(Apply, OkStatus, color.New(color.FgGreen), s("first"), s("\x1b[31msecond\x1b[0m"))
+		},
+		want: map[string]any{
+			"timestamp": "2024-03-01T10:30:00Z",
+			"level":     "info",
+			"test":      "quick-start",
+			"step":      "step-1",
+			"operation": "APPLY",
+			"status":    "OK",
+			"message":   "first\nsecond",
+		},
+	}, {
+		name: "with resource",
+		log: func(l Logger) {
+			l.WithResource(&resource).LogLevel(ErrorLevel, Assert, ErrorStatus, nil)
+		},
+		want: map[string]any{
+			"timestamp": "2024-03-01T10:30:00Z",
+			"level":     "error",
+			"test":      "quick-start",
+			"step":      "step-1",
+			"operation": "ASSERT",
+			"status":    "ERROR",
+			"resource": map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"namespace":  "default",
+				"name":       "web",
+			},
+		},
+	}, {
+		name: "with resources",
+		log: func(l Logger) {
+			l.WithResources(&resource, nil, &resource).Log(Delete, DoneStatus, nil)
+		},
+		want: map[string]any{
+			"timestamp": "2024-03-01T10:30:00Z",
+			"level":     "info",
+			"test":      "quick-start",
+			"step":      "step-1",
+			"operation": "DELETE",
+			"status":    "DONE",
+			"resources": []any{
+				map[string]any{"apiVersion": "apps/v1", "kind": "Deployment", "namespace": "default", "name": "web"},
+				map[string]any{"apiVersion": "apps/v1", "kind": "Deployment", "namespace": "default", "name": "web"},
+			},
+		},
+	}, {
+		name: "with operation",
+		log: func(l Logger) {
+			l.WithOperation("Apply deployment.yaml", report.OperationTypeApply).Log(Apply, DoneStatus, nil)
+		},
+		want: map[string]any{
+			"timestamp":     "2024-03-01T10:30:00Z",
+			"level":         "info",
+			"test":          "quick-start",
+			"step":          "step-1",
+			"operationName": "Apply deployment.yaml",
+			"operationType": "apply",
+			"operation":     "APPLY",
+			"status":        "DONE",
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			mockT := &tlogging.FakeTLogger{}
+			// steps are padded by the test processor
+			l := NewLogger(mockT, fakeClock, "quick-start", fmt.Sprintf("%-*s", 10, "step-1"), WithJSON(NewJSONWriter(&buf)), WithoutText())
+			tt.log(l)
+			var got map[string]any
+			assert.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+			assert.Equal(t, tt.want, got)
+			assert.Empty(t, mockT.Messages)
+		})
+	}
+}
+
+func Test_logger_JSONAndText(t *testing.T) {
+	var first, second bytes.Buffer
+	mockT := &tlogging.FakeTLogger{}
+	l := NewLogger(mockT, tclock.NewFakePassiveClock(time.Now()), "test", "step", WithJSON(NewJSONWriter(&first)), WithJSON(NewJSONWriter(&second)))
+	l.Log(Apply, OkStatus, nil)
+	assert.Len(t, mockT.Messages, 1)
+	assert.Equal(t, 1, bytes.Count(first.Bytes(), []byte("\n")))
+	assert.Equal(t, first.String(), second.String())
+	// disabled levels are not written
+	defer SetLevel(GetLevel())
+	SetLevel(InfoLevel)
+	l.LogLevel(DebugLevel, Apply, OkStatus, nil)
+	assert.Equal(t, 1, bytes.Count(first.Bytes(), []byte("\n")))
+}
+
+func Test_logger_JSONCorrelation(t *testing.T) {
+	var buf bytes.Buffer
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	l := NewLogger(&tlogging.FakeTLogger{}, fakeClock, "test", "step", WithJSON(NewJSONWriter(&buf)), WithoutText(), WithTestCorrelationID("3f9a1c-1"))
+	// lines outside operations only carry the test identifier
+	l.Log(Apply, OkStatus, nil)
+	CorrelateOperation(l.WithOperation("Apply", report.OperationTypeApply), "3f9a1c-2").Log(Apply, OkStatus, nil)
+	var lines []JSONLine
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var line JSONLine
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	assert.Len(t, lines, 2)
+	assert.Equal(t, "3f9a1c-1", lines[0].TestID)
+	assert.Empty(t, lines[0].OperationID)
+	assert.Equal(t, "3f9a1c-1", lines[1].TestID)
+	assert.Equal(t, "3f9a1c-2", lines[1].OperationID)
+	// loggers of other implementations are returned unchanged
+	noop := NoOp()
+	assert.Equal(t, noop, CorrelateOperation(noop, "3f9a1c-2"))
+}
+
+func TestJSONWriter_Concurrent(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewJSONWriter(&buf)
+	fakeClock := tclock.NewFakePassiveClock(time.Now())
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			l := NewLogger(&tlogging.FakeTLogger{}, fakeClock, fmt.Sprintf("test-%d", i), "step", WithJSON(w), WithoutText())
+			for j := 0; j < 50; j++ {
+				l.Log(Script, LogStatus, nil, s(fmt.Sprintf("line %d", j)))
+			}
+		}(i)
+	}
+	wg.Wait()
+	lines := 0
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var line JSONLine
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines++
+	}
+	assert.Equal(t, 1000, lines)
+}
+
+func TestOptionsFromContext(t *testing.T) {
+	assert.Nil(t, OptionsFromContext(nil)) //nolint:staticcheck
+	assert.Nil(t, OptionsFromContext(context.Background()))
+	ctx := WithOptions(context.Background(), WithoutText())
+	assert.Len(t, OptionsFromContext(ctx), 1)
+	ctx = WithOptions(ctx, WithJSON(NewJSONWriter(&bytes.Buffer{})))
+	assert.Len(t, OptionsFromContext(ctx), 2)
+}
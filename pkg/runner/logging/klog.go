@@ -0,0 +1,150 @@
+package logging
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/go-logr/logr"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+// Klog is the operation of the lines logged through klog, like the messages of client-go.
+const Klog Operation = "KLOG"
+
+// RunnerTest is the test of the lines not logged by a test, like klog lines logged without a context.
+const RunnerTest = "runner"
+
+// OffLevel is the level of the V-levels whose lines are dropped, whatever the log level threshold.
+const OffLevel Level = math.MaxInt32
+
+// VerbosityLevels maps V-levels, the verbosity of klog and logr lines, to log levels.
+type VerbosityLevels map[int]Level
+
+// DefaultVerbosityLevels logs V-level 0 lines at the info level and V-levels 1 to 4 at the debug level,
+// the request and response traces of client-go from V-level 5 are dropped.
+func DefaultVerbosityLevels() VerbosityLevels {
+	return VerbosityLevels{0: InfoLevel, 1: DebugLevel, 5: OffLevel}
+}
+
+// Level returns the level of the lines of V-level v, the level of the highest V-level mapped at or below v.
+// V-levels below the lowest one mapped are logged at the info level.
+func (m VerbosityLevels) Level(v int) Level {
+	level, found := InfoLevel, -1
+	for mapped, l := range m {
+		if mapped <= v && mapped > found {
+			level, found = l, mapped
+		}
+	}
+	return level
+}
+
+// ParseVerbosityLevels parses verbosity levels, either "default" or a comma separated list of V-levels and the level
+// of their lines like "0=info,4=debug,6=off", off drops the lines. A V-level missing from the list gets the level of the
+// highest V-level listed below it, so "0=info,4=debug,6=off" logs V-levels 0 to 3 at the info level.
+func ParseVerbosityLevels(value string) (VerbosityLevels, error) {
+	if value == "default" {
+		return DefaultVerbosityLevels(), nil
+	}
+	levels := VerbosityLevels{}
+	for _, mapping := range strings.Split(value, ",") {
+		key, name, ok := strings.Cut(strings.TrimSpace(mapping), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid verbosity level %q, expected <v-level>=<level>", mapping)
+		}
+		v, err := strconv.Atoi(key)
+		if err != nil || v < 0 {
+			return nil, fmt.Errorf("invalid verbosity level %q, the v-level must be a non negative integer", mapping)
+		}
+		if strings.EqualFold(name, "off") {
+			levels[v] = OffLevel
+			continue
+		}
+		level, err := ParseLevel(name)
+		if err != nil {
+			return nil, err
+		}
+		levels[v] = level
+	}
+	return levels, nil
+}
+
+// klogLevels are the verbosity levels of the klog bridge, they are nil unless it is installed.
+var klogLevels atomic.Pointer[VerbosityLevels]
+
+// InstallKlog routes the lines logged through klog to logger, typically a logger of the RunnerTest, V-levels are
+// mapped with levels. The warnings returned by the API server, like deprecated API usage, are logged at the warning level.
+// Code logging with the logr logger of a context, like klog.FromContext, logs to the context set with KlogIntoContext instead.
+// The returned function restores the default output of klog.
+func InstallKlog(logger Logger, levels VerbosityLevels) (restore func()) {
+	// klog drops the lines above its own verbosity before they reach the logger
+	flags := flag.NewFlagSet("klog", flag.ContinueOnError)
+	klog.InitFlags(flags)
+	verbosity := flags.Lookup("v").Value.String()
+	_ = flags.Set("v", strconv.Itoa(levels.maxVerbosity()))
+	klogLevels.Store(&levels)
+	klog.SetLogger(klogLogger(logger, levels))
+	rest.SetDefaultWarningHandler(klogWarnings{logger: logger})
+	return func() {
+		klog.ClearLogger()
+		_ = flags.Set("v", verbosity)
+		rest.SetDefaultWarningHandler(rest.WarningLogger{})
+		klogLevels.Store(nil)
+	}
+}
+
+// maxVerbosity returns the highest V-level whose lines are kept at the current log level threshold.
+func (m VerbosityLevels) maxVerbosity() int {
+	max := 0
+	for v, level := range m {
+		if !Enabled(level) {
+			continue
+		}
+		// the highest V-level mapped applies to all V-levels above
+		next := math.MaxInt32
+		for other := range m {
+			if other > v && other < next {
+				next = other
+			}
+		}
+		if next == math.MaxInt32 {
+			return math.MaxInt32
+		}
+		if next-1 > max {
+			max = next - 1
+		}
+	}
+	return max
+}
+
+// KlogIntoContext returns a context whose logr logger, the logger of klog.FromContext and of the log.FromContext of
+// controller-runtime, writes to logger. The context is returned unchanged unless the klog bridge is installed.
+func KlogIntoContext(ctx context.Context, logger Logger) context.Context {
+	levels := klogLevels.Load()
+	if levels == nil {
+		return ctx
+	}
+	return logr.NewContext(ctx, klogLogger(logger, *levels))
+}
+
+func klogLogger(logger Logger, levels VerbosityLevels) logr.Logger {
+	return logr.New(&logrSink{logger: logger, operation: Klog, levels: levels})
+}
+
+// klogWarnings logs the warnings of the API server, the default handler logs them with klog at the info level.
+type klogWarnings struct {
+	logger Logger
+}
+
+func (w klogWarnings) HandleWarningHeader(code int, _ string, text string) {
+	// like the default handler, only warnings of the 299 code are logged
+	if code != 299 || text == "" {
+		return
+	}
+	Warn(w.logger, Klog, WarnStatus, message(text))
+}
@@ -0,0 +1,131 @@
+package logging
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/klog/v2"
+	tclock "k8s.io/utils/clock/testing"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestParseVerbosityLevels(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    VerbosityLevels
+		wantErr bool
+	}{{
+		name:  "default",
+		value: "default",
+		want:  DefaultVerbosityLevels(),
+	}, {
+		name:  "list",
+		value: "0=warn, 4=debug",
+		want:  VerbosityLevels{0: WarnLevel, 4: DebugLevel},
+	}, {
+		name:  "off",
+		value: "0=info,6=OFF",
+		want:  VerbosityLevels{0: InfoLevel, 6: OffLevel},
+	}, {
+		name:    "missing level",
+		value:   "0",
+		wantErr: true,
+	}, {
+		name:    "negative v-level",
+		value:   "-1=info",
+		wantErr: true,
+	}, {
+		name:    "invalid level",
+		value:   "0=verbose",
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseVerbosityLevels(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestVerbosityLevels_Level(t *testing.T) {
+	levels := VerbosityLevels{2: WarnLevel, 4: DebugLevel}
+	// below the lowest v-level mapped
+	assert.Equal(t, InfoLevel, levels.Level(0))
+	assert.Equal(t, WarnLevel, levels.Level(2))
+	assert.Equal(t, WarnLevel, levels.Level(3))
+	assert.Equal(t, DebugLevel, levels.Level(10))
+	assert.Equal(t, InfoLevel, DefaultVerbosityLevels().Level(0))
+	assert.Equal(t, DebugLevel, DefaultVerbosityLevels().Level(1))
+	assert.Equal(t, OffLevel, DefaultVerbosityLevels().Level(8))
+}
+
+func TestVerbosityLevels_maxVerbosity(t *testing.T) {
+	defer SetLevel(GetLevel())
+	SetLevel(InfoLevel)
+	assert.Equal(t, 0, DefaultVerbosityLevels().maxVerbosity())
+	assert.Equal(t, 3, VerbosityLevels{0: InfoLevel, 4: DebugLevel}.maxVerbosity())
+	SetLevel(DebugLevel)
+	assert.Equal(t, 4, DefaultVerbosityLevels().maxVerbosity())
+	// the highest v-level mapped applies to the v-levels above
+	assert.Equal(t, math.MaxInt32, VerbosityLevels{0: InfoLevel, 4: DebugLevel}.maxVerbosity())
+	SetLevel(ErrorLevel)
+	assert.Equal(t, 0, DefaultVerbosityLevels().maxVerbosity())
+}
+
+func TestInstallKlog(t *testing.T) {
+	defer SetLevel(GetLevel())
+	SetLevel(InfoLevel)
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	var entries []Entry
+	sink := sinkFunc(func(entry Entry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	runner := NewSinkLogger(sink, fakeClock, RunnerTest, "@klog")
+	ctx := context.Background()
+	// without the bridge, contexts are left unchanged
+	assert.Equal(t, ctx, KlogIntoContext(ctx, runner))
+	restore := InstallKlog(runner, VerbosityLevels{0: InfoLevel, 3: WarnLevel, 4: DebugLevel})
+	klog.Info("global")
+	klog.V(3).Info("throttled")
+	// filtered by the log level
+	klog.V(4).Info("verbose")
+	klog.Error("failed")
+	test := NewSinkLogger(sink, fakeClock, "quick-start", "step-1").WithOperation("Apply", "apply")
+	ctx = KlogIntoContext(ctx, test)
+	klog.FromContext(ctx).Info("contextual", "key", "value")
+	ctrllog.FromContext(ctx).V(3).Info("controller-runtime")
+	klogWarnings{logger: runner}.HandleWarningHeader(299, "", "v1beta1 Foo is deprecated")
+	// other codes are ignored, like the default handler does
+	klogWarnings{logger: runner}.HandleWarningHeader(199, "", "ignored")
+	restore()
+	assert.Equal(t, ctx, KlogIntoContext(ctx, test))
+	type line struct {
+		test      string
+		operation string
+		level     Level
+		op        Operation
+		message   string
+	}
+	var got []line
+	for _, entry := range entries {
+		got = append(got, line{entry.Test, entry.OperationName, entry.Level, entry.Operation, entry.Message})
+	}
+	assert.Equal(t, []line{
+		{RunnerTest, "", InfoLevel, Klog, "global"},
+		{RunnerTest, "", WarnLevel, Klog, "throttled"},
+		{RunnerTest, "", ErrorLevel, Klog, "failed"},
+		{"quick-start", "Apply", InfoLevel, Klog, "contextual key=value"},
+		{"quick-start", "Apply", WarnLevel, Klog, "controller-runtime"},
+		{RunnerTest, "", WarnLevel, Klog, "v1beta1 Foo is deprecated"},
+	}, got)
+}
@@ -2,8 +2,11 @@ package logging
 
 import (
 	"fmt"
+	"io"
+	"strings"
+	"time"
 
-	"github.com/kyverno/chainsaw/pkg/client"
+	"github.com/kyverno/chainsaw/pkg/report"
 	"github.com/kyverno/kyverno/ext/output/color"
 	"k8s.io/utils/clock"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -17,48 +20,299 @@ type logger struct {
 	test     string
 	step     string
 	resource ctrlclient.Object
+	// resources are set instead of resource when lines are about several resources
+	resources []ctrlclient.Object
+	noText    bool
+	// quiet drops the lines below the error level, see WithVerbose
+	quiet bool
+	// callers records the location of the code logging the lines at or below callerLevel, see WithCaller
+	callers     bool
+	callerLevel Level
+	// consoleTypes limit the human readable lines of the TLogger or the writer to some operation types, see WithOperationTypes
+	consoleTypes []report.OperationType
+	colors       bool
+	glyphs       Glyphs
+	// theme colors the prefix and the resource of human readable lines, the outcome helpers color the rest
+	theme *Theme
+	// operationName and operationType identify the operation the lines are logged for, if any
+	operationName string
+	operationType report.OperationType
+	// testPath is the folder of the test, if known, see WithTestPath
+	testPath string
+	// testID and operationID are the correlation identifiers of the report, if any
+	testID      string
+	operationID string
+	// ordinals number the step and the operation in the prefix of human readable lines, see Ordinals
+	ordinals Ordinals
+	// worker is the worker slot of the test, zero unless it is printed
+	worker int
+	// namespace is the namespace of the test, if known, namespaceColumn prints it in human readable lines
+	namespace       string
+	namespaceColumn bool
+	// testStart and operationStart are zero unless elapsed durations are printed
+	testStart      time.Time
+	operationStart time.Time
+	// operationTimeout is the timeout of the operation and operationDeadline when it elapses, see WithOperationTimeout
+	operationTimeout  time.Duration
+	operationDeadline time.Time
+	timestamp         TimestampLayout
+	// utc stamps lines in UTC instead of the local time zone
+	utc bool
+	// deterministic renders the same lines on every run, see WithDeterministic
+	deterministic  bool
+	resourceFormat ResourceFormat
+	resourceList   ResourceListFormat
+	// links make the resource of colored lines a hyperlink, see WithResourceLinks
+	links          *ResourceLinks
+	messageMaxSize int
+	// diffMaxHunks is the maximum number of hunks of the diffs logged with LogDiff
+	diffMaxHunks int
+	redactor     *Redactor
+	columns      ColumnWidths
+	continuation ContinuationFormat
+	// sinks are the sinks added by options, the sink of the TLogger comes first
+	sinks []Sink
+	sink  MultiSink
+	// fields are the structured fields of the lines, merged down the derivation chain
+	fields Fields
+	// warnings collects the warnings raised with the logger for the report of the test, see WithWarnings
+	warnings *WarningCollector
+	// text caches the parts of human readable lines rendered from the names and the resource, see textCache
+	text *textCache
 }
 
-func NewLogger(t TLogger, clock clock.PassiveClock, test string, step string) Logger {
+// NewLogger returns a Logger writing human readable lines to t, typically the testing.T of a test.
+// When t has a Cleanup method, like testing.T, the sinks of the logger are flushed when the test completes.
+// When t is a testing.T and go test runs without -v, only failures are logged unless options set WithVerbose.
+func NewLogger(t TLogger, clock clock.PassiveClock, test string, step string, options ...Option) Logger {
 	t.Helper()
-	return &logger{
-		t:     t,
+	options = append([]Option{testingVerbose(t)}, options...)
+	l := newLogger(clock, test, step, func(colors bool) Sink { return NewTextSink(t, colors) }, options...)
+	l.t = t
+	if t, ok := t.(cleanupTLogger); ok {
+		// sinks report their own errors, the test is over
+		t.Cleanup(func() { _ = l.sink.Flush() })
+	}
+	return l
+}
+
+// NewWriterLogger returns a Logger writing human readable lines to w, for use outside of tests like when the runner
+// is embedded in a CLI or a server. Each line is written with a single call, see NewWriterSink.
+func NewWriterLogger(w io.Writer, clock clock.PassiveClock, test string, step string, options ...Option) Logger {
+	return newLogger(clock, test, step, func(colors bool) Sink { return NewWriterSink(w, colors) }, options...)
+}
+
+// NewSinkLogger returns a Logger writing entries to sink only, like the sinks of NewLogrEntrySink and NewSlogEntrySink.
+// No human readable line is written unless sinks writing them are added with options.
+func NewSinkLogger(sink Sink, clock clock.PassiveClock, test string, step string, options ...Option) Logger {
+	return newLogger(clock, test, step, nil, append([]Option{WithSink(sink)}, options...)...)
+}
+
+// newLogger returns a logger whose human readable lines are written to the sink returned by text, if any.
+func newLogger(clock clock.PassiveClock, test string, step string, text func(colors bool) Sink, options ...Option) *logger {
+	l := &logger{
 		clock: clock,
 		test:  test,
 		step:  step,
+		// colors are resolved once, detecting the terminal on every line would be wasteful
+		colors:         GetColorMode().Enabled(),
+		glyphs:         GetGlyphMode().Glyphs(),
+		theme:          currentTheme(),
+		utc:            GetUTC(),
+		messageMaxSize: DefaultMessageMaxSize,
+		diffMaxHunks:   DefaultDiffMaxHunks,
+	}
+	for _, option := range options {
+		option(l)
+	}
+	// whatever the order of the options, nothing differing between runs is printed
+	if l.deterministic {
+		l.colors = false
+		l.glyphs = ASCIIGlyphs()
+		l.timestamp = NoTimestamp
+		l.worker = 0
+		l.namespaceColumn = false
+		sortResources(l.resources)
 	}
+	if !l.noText && text != nil {
+		l.sink = append(l.sink, FilterOperationTypes(text(l.colors), l.consoleTypes...))
+	}
+	l.sink = append(l.sink, l.sinks...)
+	l.text = newTextCache(l)
+	return l
 }
 
 func (l *logger) Log(operation Operation, status Status, color *color.Color, args ...fmt.Stringer) {
-	sprint := fmt.Sprint
-	opLen := 9
-	stLen := 5
-	if color != nil {
-		sprint = color.Sprint
-		opLen += 14
-		stLen += 14
-	}
-	a := make([]any, 0, len(args)+2)
-	prefix := fmt.Sprintf("%s| %s | %s | %s | %-*s | %-*s |", eraser, l.clock.Now().Format("15:04:05"), sprint(l.test), sprint(l.step), opLen, sprint(operation), stLen, sprint(status))
-	if l.resource != nil {
-		gvk := l.resource.GetObjectKind().GroupVersionKind()
-		key := client.ObjectKey(l.resource)
-		prefix = fmt.Sprintf("%s %s/%s @ %s", prefix, gvk.GroupVersion(), gvk.Kind, client.Name(key))
-	}
-	a = append(a, prefix)
+	l.LogLevel(InfoLevel, operation, status, color, args...)
+}
+
+func (l *logger) LogLevel(level Level, operation Operation, status Status, color *color.Color, args ...fmt.Stringer) {
+	l.logStyle(level, operation, status, Style{Color: color}, args...)
+}
+
+func (l *logger) logStyle(level Level, operation Operation, status Status, style Style, args ...fmt.Stringer) {
+	if !Enabled(level) || (l.quiet && level > ErrorLevel) || len(l.sink) == 0 {
+		return
+	}
+	// huge messages are truncated before any sink sees them too, sinks keeping everything get the full message
+	text, fullText := l.message(args), ""
+	if truncated, omitted := TruncateMessage(text, l.messageMaxSize); omitted != 0 {
+		text, fullText = truncated, text
+	}
+	entry := Entry{
+		Time:            stamp(l.clock.Now(), l.utc),
+		Level:           level,
+		Test:            l.test,
+		TestPath:        l.testPath,
+		Step:            l.step,
+		Operation:       operation,
+		Status:          status,
+		Resource:        l.resource,
+		Resources:       l.resources,
+		OperationName:   l.operationName,
+		OperationType:   l.operationType,
+		TestID:          l.testID,
+		OperationID:     l.operationID,
+		Ordinals:        l.ordinals,
+		Worker:          l.worker,
+		Namespace:       l.namespace,
+		NamespaceColumn: l.namespaceColumn,
+		Style:           style,
+		Glyphs:          l.glyphs,
+		Theme:           l.theme,
+		Message:         text,
+		FullMessage:     fullText,
+		TestStart:       l.testStart,
+		OperationStart:  l.operationStart,
+		TimestampLayout: l.timestamp,
+		ResourceFormat:  l.resourceFormat,
+		ResourceList:    l.resourceList,
+		ResourceLinks:   l.links,
+		Columns:         l.columns,
+		Continuation:    l.continuation,
+		Deterministic:   l.deterministic,
+		Fields:          redactFields(l.redactor, l.fields),
+		text:            l.text,
+	}
+	if l.callers && level <= l.callerLevel {
+		entry.Caller = caller()
+	}
+	// sinks report their own errors, the logger has no way to surface them
+	_ = l.sink.WriteEntry(entry)
+	runHooks(entry)
+}
+
+// message joins the arguments of a log call, one per line, with the secrets they hold removed before any sink sees them.
+func (l *logger) message(args []fmt.Stringer) string {
+	switch len(args) {
+	case 0:
+		return ""
+	case 1:
+		// most lines have a single argument, it needs neither a slice nor a join
+		if l.redactor == nil {
+			return args[0].String()
+		}
+		return l.redactor.Redact(args[0].String())[0]
+	}
+	messages := make([]string, 0, len(args))
 	for _, arg := range args {
-		a = append(a, "\n")
-		a = append(a, arg)
+		messages = append(messages, arg.String())
 	}
-	l.t.Log(fmt.Sprint(a...))
+	return strings.Join(l.redactor.Redact(messages...), "\n")
 }
 
 func (l *logger) WithResource(resource ctrlclient.Object) Logger {
-	return &logger{
-		t:        l.t,
-		clock:    l.clock,
-		test:     l.test,
-		step:     l.step,
-		resource: resource,
+	c := *l
+	c.resource, c.resources = resource, nil
+	c.text = newTextCache(&c)
+	return &c
+}
+
+func (l *logger) WithResources(resources ...ctrlclient.Object) Logger {
+	c := *l
+	c.resource, c.resources = withResources(resources)
+	if c.deterministic {
+		// the resources are in a copy, see withResources
+		sortResources(c.resources)
+	}
+	c.text = newTextCache(&c)
+	return &c
+}
+
+func (l *logger) WithOperation(name string, operationType report.OperationType) Logger {
+	c := *l
+	c.operationName = name
+	c.operationType = operationType
+	c.text = newTextCache(&c)
+	return &c
+}
+
+func (l *logger) WithFields(fields map[string]any) Logger {
+	c := *l
+	c.fields = l.fields.withFields(fields)
+	return &c
+}
+
+func (l *logger) WithField(key string, value any) Logger {
+	return l.WithFields(map[string]any{key: value})
+}
+
+// CorrelateOperation returns a logger attributing its lines to the operation identified by id in the report.
+// Loggers not created with NewLogger, NewWriterLogger or NewSinkLogger are returned unchanged.
+func CorrelateOperation(l Logger, id string) Logger {
+	if l, ok := l.(*logger); ok {
+		c := *l
+		c.operationID = id
+		return &c
+	}
+	return l
+}
+
+// Flush writes through the lines held by the sinks of l, see Flusher, and the repeated line held by a Deduper.
+// Loggers not created with NewLogger, NewWriterLogger or NewSinkLogger have nothing to flush.
+func Flush(l Logger) error {
+	switch l := l.(type) {
+	case *logger:
+		return l.sink.Flush()
+	case *Deduper:
+		l.Flush()
+		return Flush(l.logger)
+	}
+	return nil
+}
+
+// Close flushes l like Flush, then closes its sinks, lines logged afterwards may be dropped.
+// It is meant for when the process is about to die, like when an operation panics, shared sinks are closed too.
+func Close(l Logger) error {
+	switch l := l.(type) {
+	case *logger:
+		return l.sink.Close()
+	case *Deduper:
+		l.Flush()
+		return Close(l.logger)
+	}
+	return nil
+}
+
+// MessageMaxSize returns the size in bytes messages logged with l are truncated at, zero or less when they are kept whole.
+// Loggers not created with NewLogger, NewWriterLogger or NewSinkLogger are assumed to truncate at DefaultMessageMaxSize.
+func MessageMaxSize(l Logger) int {
+	switch l := l.(type) {
+	case *logger:
+		return l.messageMaxSize
+	case *Deduper:
+		return MessageMaxSize(l.logger)
+	}
+	return DefaultMessageMaxSize
+}
+
+// OperationStarted returns a logger also printing the duration elapsed since now, the start of an operation, when elapsed durations are printed.
+// Loggers not created with NewLogger, or without the WithElapsed option, are returned unchanged.
+func OperationStarted(l Logger) Logger {
+	if l, ok := l.(*logger); ok && !l.testStart.IsZero() {
+		c := *l
+		c.operationStart = l.clock.Now()
+		return &c
 	}
+	return l
 }
@@ -2,11 +2,13 @@ package logging
 
 import (
 	"fmt"
+	"io"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/fatih/color"
+	"github.com/kyverno/chainsaw/pkg/report"
 	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
 	"github.com/stretchr/testify/assert"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -29,6 +31,65 @@ func TestNewLogger(t *testing.T) {
 	assert.Nil(t, logger.resource)
 }
 
+func TestNewWriterLogger(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	tests := []struct {
+		name    string
+		args    []fmt.Stringer
+		options []Option
+		want    string
+		// wantT is the line of a logger writing to a TLogger instead
+		wantT string
+	}{{
+		name:  "without message",
+		want:  "| 10:30:00 | test | step | APPLY     | OK    |\n",
+		wantT: eraser + "| 10:30:00 | test | step | APPLY     | OK    |",
+	}, {
+		name:  "trailing newline",
+		args:  []fmt.Stringer{s("first"), s("second\n")},
+		want:  "| 10:30:00 | test | step | APPLY     | OK    |\nfirst\nsecond\n",
+		wantT: eraser + "| 10:30:00 | test | step | APPLY     | OK    |\nfirst\nsecond\n",
+	}, {
+		name:    "without text",
+		options: []Option{WithoutText()},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := append([]Option{WithColor(ColorNever)}, tt.options...)
+			var out strings.Builder
+			// no TLogger, Helper calls have nowhere to go
+			l := NewWriterLogger(&out, fakeClock, "test", "step", options...)
+			assert.Nil(t, l.(*logger).t)
+			l.Log(Apply, OkStatus, nil, tt.args...)
+			assert.Equal(t, tt.want, out.String())
+			// the TLogger adds its own newline and prints the file and line the eraser hides
+			mockT := &tlogging.FakeTLogger{}
+			NewLogger(mockT, fakeClock, "test", "step", options...).Log(Apply, OkStatus, nil, tt.args...)
+			if tt.wantT == "" {
+				assert.Empty(t, mockT.Messages)
+			} else {
+				assert.Equal(t, []string{tt.wantT}, mockT.Messages)
+			}
+		})
+	}
+}
+
+func TestNewSinkLogger(t *testing.T) {
+	var entries []Entry
+	sink := sinkFunc(func(entry Entry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	l := NewSinkLogger(sink, fakeClock, "test", "step")
+	l.Log(Apply, OkStatus, nil, s("applied"))
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "applied", entries[0].Message)
+	assert.Equal(t, fakeClock.Now(), entries[0].Time)
+	// there is no text sink
+	assert.Len(t, l.(*logger).sink, 1)
+}
+
 type s string
 
 func (v s) String() string { return string(v) }
@@ -155,3 +216,142 @@ func Test_logger_WithResource(t *testing.T) {
 		})
 	}
 }
+
+func Test_logger_WithResources(t *testing.T) {
+	var first, second unstructured.Unstructured
+	first.SetName("first")
+	second.SetName("second")
+	base := &logger{t: t, clock: tclock.NewFakePassiveClock(time.Now()), test: "testName", step: "stepName"}
+	tests := []struct {
+		name          string
+		resources     []ctrlclient.Object
+		wantResource  ctrlclient.Object
+		wantResources []ctrlclient.Object
+	}{{
+		name: "none",
+	}, {
+		name:      "nil entries",
+		resources: []ctrlclient.Object{nil, (*unstructured.Unstructured)(nil)},
+	}, {
+		name:         "single",
+		resources:    []ctrlclient.Object{nil, &first},
+		wantResource: &first,
+	}, {
+		name:          "several",
+		resources:     []ctrlclient.Object{&first, nil, &second},
+		wantResources: []ctrlclient.Object{&first, &second},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			derived := base.WithResource(&first).WithResources(tt.resources...).(*logger)
+			assert.Equal(t, tt.wantResource, derived.resource)
+			assert.Equal(t, tt.wantResources, derived.resources)
+			// the receiver is left unchanged
+			assert.Nil(t, base.resource)
+			assert.Nil(t, base.resources)
+		})
+	}
+	// the resources are copied, changing the slice passed doesn't change the logger
+	resources := []ctrlclient.Object{&first, &second}
+	derived := base.WithResources(resources...).(*logger)
+	resources[0] = nil
+	assert.Equal(t, []ctrlclient.Object{&first, &second}, derived.resources)
+	// WithResource replaces the resources
+	assert.Nil(t, derived.WithResource(&first).(*logger).resources)
+}
+
+func Test_logger_WithOperation(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	var entries []Entry
+	sink := sinkFunc(func(entry Entry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	parent := NewLogger(&tlogging.FakeTLogger{}, fakeClock, "test", "step", WithoutText(), WithSink(sink), WithTimestampLayout(NoTimestamp))
+	derived := parent.WithOperation("Apply configmap.yaml", report.OperationTypeApply)
+	// derived loggers keep the operation
+	var resource unstructured.Unstructured
+	resource.SetAPIVersion("v1")
+	resource.SetKind("ConfigMap")
+	resource.SetName("settings")
+	derived.WithResource(&resource).Log(Apply, OkStatus, nil)
+	derived.Log(Apply, DoneStatus, nil)
+	// the parent is left unchanged
+	parent.Log(Apply, OkStatus, nil)
+	assert.Equal(t, "", parent.(*logger).operationName)
+	assert.Equal(t, report.OperationType(""), parent.(*logger).operationType)
+	assert.Len(t, entries, 3)
+	assert.Equal(t, "Apply configmap.yaml", entries[0].OperationName)
+	assert.Equal(t, report.OperationTypeApply, entries[0].OperationType)
+	assert.Equal(t, "| test | step | Apply configmap.yaml | APPLY     | OK    | v1/ConfigMap @ settings", FormatText(entries[0], false))
+	assert.Equal(t, "| test | step | Apply configmap.yaml | APPLY     | DONE  |", FormatText(entries[1], false))
+	assert.Equal(t, "| test | step | APPLY     | OK    |", FormatText(entries[2], false))
+	assert.Empty(t, entries[2].OperationName)
+}
+
+// TestFormatText_Golden renders lines with every option of the human readable format, the output must stay byte-identical.
+func TestFormatText_Golden(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	var pod, namespace unstructured.Unstructured
+	pod.SetAPIVersion("v1")
+	pod.SetKind("Pod")
+	pod.SetNamespace("chainsaw-happy-cat")
+	pod.SetName("nginx")
+	namespace.SetAPIVersion("v1")
+	namespace.SetKind("Namespace")
+	namespace.SetName("chainsaw-happy-cat")
+	red := color.New(color.FgRed)
+	var out strings.Builder
+	for _, colors := range []ColorMode{ColorNever, ColorAlways} {
+		for i, options := range [][]Option{
+			nil,
+			{WithGlyphs(GlyphASCII)},
+			{WithGlyphs(GlyphUnicode), WithTimestampLayout(NoTimestamp)},
+			{WithColumnWidths(DefaultColumnWidths()), WithContinuation(IndentContinuation)},
+			{WithColumnWidths(ColumnWidths{Test: 8, Step: 4}), WithContinuation(PrefixContinuation), WithResourceFormat(ShortResource)},
+			{WithResourceFormat(NamespacedResource), WithResourceList(ListResources), WithWorker(3)},
+			{WithElapsed(fakeClock.Now().Add(-90 * time.Second)), WithMessageMaxSize(16)},
+		} {
+			fmt.Fprintf(&out, "# colors=%s options=%d\n", colors, i)
+			logger := NewWriterLogger(&out, fakeClock, "quick-start", "step-1  ", append([]Option{WithColor(colors)}, options...)...)
+			logger.Log(Apply, RunStatus, nil)
+			logger.WithResource(&pod).Log(Apply, OkStatus, red)
+			Success(logger.WithResource(&pod), Apply, OkStatus, s("created"))
+			operation := logger.WithOperation("Assert pod.yaml", report.OperationTypeAssert)
+			Failure(operation.WithResource(&pod), Assert, ErrorStatus, s("status.phase: Invalid value: \"Pending\": Expected value: \"Running\""), s("--- expected\n+++ actual\r\n"))
+			Warn(operation.WithResources(&pod, &namespace), Delete, WarnStatus, s("résumé ✓ of a long message"))
+			Skip(operation.WithResource(&namespace), Assert, SkipStatus)
+			logger.Log(Script, OutStatus, nil, s(""), s("two"))
+		}
+	}
+	assertGolden(t, "text.golden", []byte(out.String()))
+}
+
+func BenchmarkLog(b *testing.B) {
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	var pod unstructured.Unstructured
+	pod.SetAPIVersion("v1")
+	pod.SetKind("Pod")
+	pod.SetNamespace("chainsaw-happy-cat")
+	pod.SetName("nginx")
+	for _, bench := range []struct {
+		name     string
+		resource ctrlclient.Object
+	}{
+		{name: "without resource"},
+		{name: "with resource", resource: &pod},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			logger := NewWriterLogger(io.Discard, fakeClock, "quick-start", "step-1", WithColor(ColorNever)).WithOperation("Assert pod.yaml", report.OperationTypeAssert)
+			if bench.resource != nil {
+				logger = logger.WithResource(bench.resource)
+			}
+			arg := s("status.phase: Invalid value: \"Pending\": Expected value: \"Running\"")
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				logger.Log(Assert, ErrorStatus, nil, arg)
+			}
+		})
+	}
+}
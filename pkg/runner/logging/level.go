@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	ErrorLevel Level = iota
+	WarnLevel
+	InfoLevel
+	DebugLevel
+)
+
+// LevelEnv is the environment variable holding the initial log level threshold (error|warn|info|debug).
+const LevelEnv = "CHAINSAW_LOG_LEVEL"
+
+// DefaultLevel is the log level threshold used when none is configured.
+const DefaultLevel = InfoLevel
+
+var levelNames = map[Level]string{
+	ErrorLevel: "error",
+	WarnLevel:  "warn",
+	InfoLevel:  "info",
+	DebugLevel: "debug",
+}
+
+var threshold atomic.Int32
+
+func init() {
+	threshold.Store(int32(levelFromEnv(os.LookupEnv)))
+}
+
+// levelFromEnv returns the level configured in the environment, DefaultLevel if not set or invalid.
+func levelFromEnv(lookup func(string) (string, bool)) Level {
+	if value, ok := lookup(LevelEnv); ok {
+		if level, err := ParseLevel(value); err == nil {
+			return level
+		}
+	}
+	return DefaultLevel
+}
+
+// ParseLevel parses a log level name.
+func ParseLevel(name string) (Level, error) {
+	for level, levelName := range levelNames {
+		if strings.EqualFold(name, levelName) {
+			return level, nil
+		}
+	}
+	return DefaultLevel, fmt.Errorf("invalid log level %q (error|warn|info|debug)", name)
+}
+
+// LevelName returns the name of a log level.
+func LevelName(level Level) string {
+	if name, ok := levelNames[level]; ok {
+		return name
+	}
+	return fmt.Sprintf("level(%d)", level)
+}
+
+// SetLevel sets the log level threshold, lines logged above it are dropped.
+func SetLevel(level Level) {
+	threshold.Store(int32(level))
+}
+
+// GetLevel returns the log level threshold.
+func GetLevel() Level {
+	return Level(threshold.Load())
+}
+
+// Enabled returns true if lines logged at the given level are kept.
+func Enabled(level Level) bool {
+	return level <= GetLevel()
+}
@@ -0,0 +1,90 @@
+package logging
+
+import (
+	"testing"
+	"time"
+
+	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
+	"github.com/stretchr/testify/assert"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Level
+		wantErr bool
+	}{
+		{name: "error", want: ErrorLevel},
+		{name: "warn", want: WarnLevel},
+		{name: "INFO", want: InfoLevel},
+		{name: "Debug", want: DebugLevel},
+		{name: "verbose", want: DefaultLevel, wantErr: true},
+		{name: "", want: DefaultLevel, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLevel(tt.name)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+	assert.Equal(t, "warn", LevelName(WarnLevel))
+	assert.Equal(t, "level(7)", LevelName(Level(7)))
+}
+
+func TestLevelFromEnv(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want Level
+	}{
+		{name: "not set", want: DefaultLevel},
+		{name: "set", env: map[string]string{LevelEnv: "debug"}, want: DebugLevel},
+		{name: "invalid", env: map[string]string{LevelEnv: "loud"}, want: DefaultLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := levelFromEnv(func(key string) (string, bool) {
+				value, ok := tt.env[key]
+				return value, ok
+			})
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_logger_LogLevel(t *testing.T) {
+	defer SetLevel(GetLevel())
+	fakeClock := tclock.NewFakePassiveClock(time.Now())
+	levels := []Level{ErrorLevel, WarnLevel, InfoLevel, DebugLevel}
+	for _, threshold := range levels {
+		t.Run(LevelName(threshold), func(t *testing.T) {
+			SetLevel(threshold)
+			mockT := &tlogging.FakeTLogger{}
+			logger := NewLogger(mockT, fakeClock, "test", "step")
+			for _, level := range levels {
+				logger.LogLevel(level, Apply, LogStatus, nil, s(LevelName(level)))
+			}
+			// Log is the info level
+			logger.Log(Apply, LogStatus, nil, s("log"))
+			var want []string
+			for _, level := range levels {
+				if level <= threshold {
+					want = append(want, LevelName(level))
+				}
+			}
+			if threshold >= InfoLevel {
+				want = append(want, "log")
+			}
+			assert.Len(t, mockT.Messages, len(want))
+			for i, message := range mockT.Messages {
+				assert.Contains(t, message, want[i])
+			}
+		})
+	}
+}
@@ -8,8 +8,9 @@ import (
 )
 
 func Log(ctx context.Context, operation Operation, status Status, color *color.Color, args ...fmt.Stringer) {
-	logger := FromContext(ctx)
-	if logger != nil {
-		logger.Log(operation, status, color, args...)
-	}
+	FromContext(ctx).Log(operation, status, color, args...)
+}
+
+func LogLevel(ctx context.Context, level Level, operation Operation, status Status, color *color.Color, args ...fmt.Stringer) {
+	FromContext(ctx).LogLevel(level, operation, status, color, args...)
 }
@@ -8,6 +8,7 @@ type (
 	Logger    = tlogging.Logger
 	Operation = tlogging.Operation
 	Status    = tlogging.Status
+	Level     = tlogging.Level
 )
 
 const (
@@ -22,6 +23,7 @@ const (
 	Get      Operation = "GET"
 	Internal Operation = "INTERNAL"
 	Patch    Operation = "PATCH"
+	Report   Operation = "REPORT"
 	Script   Operation = "SCRIPT"
 	Sleep    Operation = "SLEEP"
 	Stderr   Operation = "STDERR"
@@ -36,4 +38,10 @@ const (
 	OkStatus    Status = "OK"
 	RunStatus   Status = "RUN"
 	LogStatus   Status = "LOG"
+	WarnStatus  Status = "WARN"
+	OutStatus   Status = "OUT"
+	ErrStatus   Status = "ERR"
+	SkipStatus  Status = "SKIP"
+	WaitStatus  Status = "WAIT"
+	RetryStatus Status = "RETRY"
 )
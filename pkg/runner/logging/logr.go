@@ -0,0 +1,208 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/kyverno/chainsaw/pkg/client"
+	"github.com/kyverno/chainsaw/pkg/report"
+	"github.com/kyverno/kyverno/ext/output/color"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Logr is the operation of the lines logged through a logr.Logger.
+const Logr Operation = "LOGR"
+
+// message is a log argument holding plain text.
+type message string
+
+func (m message) String() string { return string(m) }
+
+// ToLogr returns a logr.Logger writing to logger.
+func ToLogr(logger Logger) logr.Logger {
+	return logr.New(NewLogrSink(logger))
+}
+
+// NewLogrSink returns a logr.LogSink writing to logger.
+// V-levels above zero are logged at the debug level, names are appended to the step name and values are printed after the message.
+func NewLogrSink(logger Logger) logr.LogSink {
+	return &logrSink{logger: logger, operation: Logr, levels: VerbosityLevels{0: InfoLevel, 1: DebugLevel}}
+}
+
+type logrSink struct {
+	logger Logger
+	// operation is the operation of the lines, Logr unless they come from klog
+	operation Operation
+	levels    VerbosityLevels
+	// names can't be appended to the step of foreign loggers, they prefix the message instead
+	names  []string
+	values []any
+}
+
+func (s *logrSink) Init(logr.RuntimeInfo) {}
+
+func (s *logrSink) Enabled(level int) bool {
+	return Enabled(s.levels.Level(level))
+}
+
+func (s *logrSink) Info(level int, msg string, keysAndValues ...any) {
+	s.logger.LogLevel(s.levels.Level(level), s.operation, LogStatus, nil, s.format(msg, keysAndValues))
+}
+
+func (s *logrSink) Error(err error, msg string, keysAndValues ...any) {
+	if err != nil {
+		keysAndValues = append([]any{"error", err}, keysAndValues...)
+	}
+	Failure(s.logger, s.operation, ErrorStatus, s.format(msg, keysAndValues))
+}
+
+func (s *logrSink) format(msg string, keysAndValues []any) fmt.Stringer {
+	parts := make([]string, 0, 2)
+	if len(s.names) != 0 {
+		parts = append(parts, strings.Join(s.names, "/")+":")
+	}
+	parts = append(parts, msg)
+	values := append(s.values[:len(s.values):len(s.values)], keysAndValues...)
+	for i := 0; i < len(values); i += 2 {
+		var value any = "<missing>"
+		if i+1 < len(values) {
+			value = values[i+1]
+		}
+		parts = append(parts, fmt.Sprintf("%v=%v", values[i], value))
+	}
+	return message(strings.Join(parts, " "))
+}
+
+func (s *logrSink) WithValues(keysAndValues ...any) logr.LogSink {
+	c := *s
+	c.values = append(s.values[:len(s.values):len(s.values)], keysAndValues...)
+	return &c
+}
+
+func (s *logrSink) WithName(name string) logr.LogSink {
+	c := *s
+	if l, ok := s.logger.(*logger); ok {
+		named := *l
+		named.step = strings.TrimSpace(l.step) + "/" + name
+		c.logger = &named
+	} else {
+		c.names = append(s.names[:len(s.names):len(s.names)], name)
+	}
+	return &c
+}
+
+// FromLogr returns a Logger writing to logger.
+// Operations, statuses and resources are logged as key/value pairs, colors are ignored.
+func FromLogr(logger logr.Logger) Logger {
+	return &logrLogger{logger: logger}
+}
+
+// NewLogrEntrySink returns a Sink writing entries to logger like FromLogr, the test and step, and their
+// correlation identifiers, namespace and caller if any, are logged as key/value pairs.
+func NewLogrEntrySink(logger logr.Logger) Sink {
+	return logrEntrySink{logger: logger}
+}
+
+type logrEntrySink struct {
+	logger logr.Logger
+}
+
+func (s logrEntrySink) WriteEntry(entry Entry) error {
+	logger := s.logger.WithValues("test", entry.Test, "step", strings.TrimSpace(entry.Step))
+	if entry.TestID != "" {
+		logger = logger.WithValues("testId", entry.TestID)
+	}
+	if entry.OperationID != "" {
+		logger = logger.WithValues("operationId", entry.OperationID)
+	}
+	if entry.Namespace != "" {
+		logger = logger.WithValues("namespace", entry.Namespace)
+	}
+	if entry.Caller != "" {
+		logger = logger.WithValues("caller", entry.Caller)
+	}
+	logToAdapter(FromLogr(logger), entry)
+	return nil
+}
+
+type logrLogger struct {
+	logger        logr.Logger
+	resource      ctrlclient.Object
+	resources     []ctrlclient.Object
+	operationName string
+	operationType report.OperationType
+	fields        Fields
+}
+
+func (l *logrLogger) Log(operation Operation, status Status, color *color.Color, args ...fmt.Stringer) {
+	l.LogLevel(InfoLevel, operation, status, color, args...)
+}
+
+func (l *logrLogger) LogLevel(level Level, operation Operation, status Status, _ *color.Color, args ...fmt.Stringer) {
+	if !Enabled(level) {
+		return
+	}
+	messages := make([]string, 0, len(args))
+	for _, arg := range args {
+		messages = append(messages, arg.String())
+	}
+	msg := jsonMessage(strings.Join(messages, "\n"))
+	var keysAndValues []any
+	if l.operationName != "" {
+		keysAndValues = append(keysAndValues, "operationName", l.operationName, "operationType", string(l.operationType))
+	}
+	keysAndValues = append(keysAndValues, "operation", string(operation), "status", string(status))
+	if l.resource != nil {
+		gvk := l.resource.GetObjectKind().GroupVersionKind()
+		key := client.ObjectKey(l.resource)
+		keysAndValues = append(keysAndValues, "apiVersion", gvk.GroupVersion().String(), "kind", gvk.Kind)
+		if key.Namespace != "" {
+			keysAndValues = append(keysAndValues, "namespace", key.Namespace)
+		}
+		keysAndValues = append(keysAndValues, "name", key.Name)
+	}
+	if len(l.resources) != 0 {
+		keysAndValues = append(keysAndValues, "resources", resourceNames(l.resources))
+	}
+	if len(l.fields) != 0 {
+		keysAndValues = append(keysAndValues, "fields", structuredFields(l.fields))
+	}
+	switch level {
+	case ErrorLevel:
+		l.logger.Error(nil, msg, keysAndValues...)
+	case DebugLevel:
+		l.logger.V(1).Info(msg, keysAndValues...)
+	default:
+		l.logger.Info(msg, keysAndValues...)
+	}
+}
+
+func (l *logrLogger) WithResource(resource ctrlclient.Object) Logger {
+	c := *l
+	c.resource, c.resources = resource, nil
+	return &c
+}
+
+func (l *logrLogger) WithResources(resources ...ctrlclient.Object) Logger {
+	c := *l
+	c.resource, c.resources = withResources(resources)
+	return &c
+}
+
+func (l *logrLogger) WithOperation(name string, operationType report.OperationType) Logger {
+	c := *l
+	c.operationName = name
+	c.operationType = operationType
+	return &c
+}
+
+func (l *logrLogger) WithFields(fields map[string]any) Logger {
+	c := *l
+	c.fields = l.fields.withFields(fields)
+	return &c
+}
+
+func (l *logrLogger) WithField(key string, value any) Logger {
+	return l.WithFields(map[string]any{key: value})
+}
@@ -0,0 +1,181 @@
+package logging
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+	"github.com/kyverno/chainsaw/pkg/report"
+	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+func TestToLogr(t *testing.T) {
+	defer SetLevel(GetLevel())
+	SetLevel(InfoLevel)
+	fakeClock := tclock.NewFakePassiveClock(time.Now())
+	tests := []struct {
+		name  string
+		log   func(logr.Logger)
+		lines int
+		want  []string
+	}{{
+		name:  "info",
+		log:   func(l logr.Logger) { l.Info("reconciled", "attempt", 2) },
+		lines: 1,
+		want:  []string{"| test | step-1 | LOGR", "| LOG   |\nreconciled attempt=2"},
+	}, {
+		name:  "values and name",
+		log:   func(l logr.Logger) { l.WithValues("controller", "web").WithName("reconciler").Info("started") },
+		lines: 1,
+		want:  []string{"| test | step-1/reconciler | LOGR", "\nstarted controller=web"},
+	}, {
+		name:  "error",
+		log:   func(l logr.Logger) { l.Error(errors.New("conflict"), "update failed", "retry", true) },
+		lines: 1,
+		want:  []string{"| ERROR |\nupdate failed error=conflict retry=true"},
+	}, {
+		name: "verbosity above threshold",
+		log:  func(l logr.Logger) { l.V(1).Info("noisy") },
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockT := &tlogging.FakeTLogger{}
+			tt.log(ToLogr(NewLogger(mockT, fakeClock, "test", "step-1")))
+			assert.Len(t, mockT.Messages, tt.lines)
+			for _, want := range tt.want {
+				assert.Contains(t, strings.Join(mockT.Messages, ""), want)
+			}
+		})
+	}
+}
+
+func TestToLogr_ForeignLogger(t *testing.T) {
+	var fake tlogging.FakeLogger
+	l := ToLogr(&fake).WithName("harness").WithName("setup").WithValues("k", "v")
+	l.Info("ready")
+	assert.Equal(t, 1, fake.NumCalls())
+	// names prefix the message of loggers that don't have a step
+	assert.Equal(t, "harness/setup: ready k=v odd=<missing>", l.GetSink().(*logrSink).format("ready", []any{"odd"}).String())
+	// debug lines follow the configured threshold
+	defer SetLevel(GetLevel())
+	SetLevel(DebugLevel)
+	assert.True(t, l.V(3).Enabled())
+	SetLevel(InfoLevel)
+	assert.False(t, l.V(1).Enabled())
+}
+
+func TestFromLogr(t *testing.T) {
+	defer SetLevel(GetLevel())
+	SetLevel(DebugLevel)
+	var resource unstructured.Unstructured
+	resource.SetAPIVersion("apps/v1")
+	resource.SetKind("Deployment")
+	resource.SetNamespace("default")
+	resource.SetName("web")
+	tests := []struct {
+		name string
+		log  func(Logger)
+		want map[string]any
+	}{{
+		name: "info",
+		log:  func(l Logger) { l.Log(Apply, OkStatus, nil, s("first"), s("\x1b[31msecond\x1b[0m")) },
+		want: map[string]any{"logger": "", "level": float64(0), "msg": "first\nsecond", "operation": "APPLY", "status": "OK"},
+	}, {
+		name: "resource",
+		log:  func(l Logger) { l.WithResource(&resource).Log(Create, DoneStatus, nil) },
+		want: map[string]any{
+			"logger":     "",
+			"level":      float64(0),
+			"msg":        "",
+			"operation":  "CREATE",
+			"status":     "DONE",
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"namespace":  "default",
+			"name":       "web",
+		},
+	}, {
+		name: "resources",
+		log:  func(l Logger) { l.WithResources(&resource, &resource).Log(Delete, DoneStatus, nil) },
+		want: map[string]any{
+			"logger":    "",
+			"level":     float64(0),
+			"msg":       "",
+			"operation": "DELETE",
+			"status":    "DONE",
+			"resources": []any{"apps/v1/Deployment @ default/web", "apps/v1/Deployment @ default/web"},
+		},
+	}, {
+		name: "operation",
+		log:  func(l Logger) { l.WithOperation("Script", report.OperationTypeScript).Log(Script, LogStatus, nil) },
+		want: map[string]any{
+			"logger":        "",
+			"level":         float64(0),
+			"msg":           "",
+			"operationName": "Script",
+			"operationType": "script",
+			"operation":     "SCRIPT",
+			"status":        "LOG",
+		},
+	}, {
+		name: "debug",
+		log:  func(l Logger) { l.LogLevel(DebugLevel, Assert, ErrorStatus, nil, s("attempt 1")) },
+		want: map[string]any{"logger": "", "level": float64(1), "msg": "attempt 1", "operation": "ASSERT", "status": "ERROR"},
+	}, {
+		name: "error",
+		log:  func(l Logger) { l.LogLevel(ErrorLevel, Assert, ErrorStatus, nil, s("failed")) },
+		want: map[string]any{"logger": "", "msg": "failed", "error": nil, "operation": "ASSERT", "status": "ERROR"},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var lines []string
+			sink := funcr.NewJSON(func(obj string) { lines = append(lines, obj) }, funcr.Options{Verbosity: 1})
+			tt.log(FromLogr(sink))
+			assert.Len(t, lines, 1)
+			var got map[string]any
+			assert.NoError(t, json.Unmarshal([]byte(lines[0]), &got))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewLogrEntrySink(t *testing.T) {
+	var resource unstructured.Unstructured
+	resource.SetAPIVersion("apps/v1")
+	resource.SetKind("Deployment")
+	resource.SetNamespace("default")
+	resource.SetName("web")
+	var lines []string
+	sink := NewLogrEntrySink(funcr.NewJSON(func(obj string) { lines = append(lines, obj) }, funcr.Options{}))
+	// steps are padded by the test processor
+	logger := NewSinkLogger(sink, tclock.NewFakePassiveClock(time.Now()), "quick-start", "step-1  ", WithTestCorrelationID("run-1"))
+	logger = CorrelateOperation(logger.WithOperation("Apply deployment.yaml", report.OperationTypeApply), "run-2")
+	logger.WithResource(&resource).Log(Apply, DoneStatus, nil, s("applied"))
+	assert.Len(t, lines, 1)
+	var got map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &got))
+	assert.Equal(t, map[string]any{
+		"logger":        "",
+		"level":         float64(0),
+		"msg":           "applied",
+		"test":          "quick-start",
+		"step":          "step-1",
+		"testId":        "run-1",
+		"operationId":   "run-2",
+		"operationName": "Apply deployment.yaml",
+		"operationType": "apply",
+		"operation":     "APPLY",
+		"status":        "DONE",
+		"apiVersion":    "apps/v1",
+		"kind":          "Deployment",
+		"namespace":     "default",
+		"name":          "web",
+	}, got)
+}
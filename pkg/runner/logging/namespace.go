@@ -0,0 +1,14 @@
+package logging
+
+// WithNamespace returns a logger whose lines are logged in namespace, the namespace of the test, typically once it is
+// assigned after the logger was created. It is rendered in a column of human readable lines with WithNamespaceColumn
+// and always written by the JSON, logr and slog sinks. Loggers not created with NewLogger, NewWriterLogger or
+// NewSinkLogger are returned unchanged.
+func WithNamespace(l Logger, namespace string) Logger {
+	if l, ok := l.(*logger); ok {
+		c := *l
+		c.namespace = namespace
+		return &c
+	}
+	return l
+}
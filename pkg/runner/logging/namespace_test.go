@@ -0,0 +1,50 @@
+package logging
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
+	"github.com/stretchr/testify/assert"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+func TestWithNamespace(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	var buf bytes.Buffer
+	var lines []string
+	sink := sinkFunc(func(entry Entry) error {
+		lines = append(lines, FormatText(entry, false))
+		return nil
+	})
+	mockT := &tlogging.FakeTLogger{}
+	logger := NewLogger(mockT, fakeClock, "test", "step", WithoutText(), WithSink(sink), WithJSON(NewJSONWriter(&buf)), WithNamespaceColumn(), WithWorker(2))
+	// the column is omitted until the namespace is known
+	logger.Log(Create, RunStatus, nil)
+	WithNamespace(logger, "chainsaw-happy-cat").Log(Create, OkStatus, nil)
+	// without the option, the namespace is only written to the structured sinks
+	WithNamespace(NewLogger(mockT, fakeClock, "test", "step", WithoutText(), WithSink(sink), WithJSON(NewJSONWriter(&buf))), "chainsaw-happy-cat").Log(Apply, OkStatus, nil)
+	// nothing differing between runs is printed
+	WithNamespace(NewLogger(mockT, fakeClock, "test", "step", WithoutText(), WithSink(sink), WithNamespaceColumn(), WithDeterministic()), "chainsaw-happy-cat").Log(Apply, OkStatus, nil)
+	assert.Equal(t, []string{
+		"| w02 | 10:30:00 | test | step | CREATE    | RUN   |",
+		"| w02 | 10:30:00 | [chainsaw-happy-cat] | test | step | CREATE    | OK    |",
+		"| 10:30:00 | test | step | APPLY     | OK    |",
+		"| test | step | APPLY     | OK    |",
+	}, lines)
+	assert.Empty(t, mockT.Messages)
+	jsonLines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, jsonLines, 3)
+	assert.NotContains(t, jsonLines[0], `"namespace"`)
+	assert.Contains(t, jsonLines[1], `"namespace":"chainsaw-happy-cat"`)
+	assert.Contains(t, jsonLines[2], `"namespace":"chainsaw-happy-cat"`)
+	// loggers created once the namespace is known get it with an option
+	lines = nil
+	NewLogger(mockT, fakeClock, "test", "step", WithoutText(), WithSink(sink), WithTestNamespace("chainsaw"), WithNamespaceColumn()).Log(Apply, OkStatus, nil)
+	assert.Equal(t, []string{"| 10:30:00 | [chainsaw] | test | step | APPLY     | OK    |"}, lines)
+	// other loggers are returned unchanged
+	noop := NoOp()
+	assert.Equal(t, noop, WithNamespace(noop, "chainsaw-happy-cat"))
+}
@@ -0,0 +1,41 @@
+package logging
+
+import (
+	"fmt"
+
+	"github.com/kyverno/chainsaw/pkg/report"
+	"github.com/kyverno/kyverno/ext/output/color"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// noop is a Logger dropping everything, FromContext returns it when the context has no logger.
+type noop struct{}
+
+// NoOp returns a Logger dropping the lines logged, loggers derived from it drop them too.
+func NoOp() Logger {
+	return noop{}
+}
+
+func (noop) Log(Operation, Status, *color.Color, ...fmt.Stringer) {}
+
+func (noop) LogLevel(Level, Operation, Status, *color.Color, ...fmt.Stringer) {}
+
+func (n noop) WithResource(ctrlclient.Object) Logger {
+	return n
+}
+
+func (n noop) WithResources(...ctrlclient.Object) Logger {
+	return n
+}
+
+func (n noop) WithOperation(string, report.OperationType) Logger {
+	return n
+}
+
+func (n noop) WithFields(map[string]any) Logger {
+	return n
+}
+
+func (n noop) WithField(string, any) Logger {
+	return n
+}
@@ -0,0 +1,111 @@
+package logging
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/report"
+	"k8s.io/utils/clock"
+)
+
+// operations maps the operation types of reports to the operations of log lines.
+var operations = map[report.OperationType]Operation{
+	report.OperationTypeApply:   Apply,
+	report.OperationTypeAssert:  Assert,
+	report.OperationTypeCommand: Command,
+	report.OperationTypeCreate:  Create,
+	report.OperationTypeDelete:  Delete,
+	report.OperationTypeError:   Error,
+	report.OperationTypeScript:  Script,
+	report.OperationTypeSleep:   Sleep,
+}
+
+// OperationLog logs the start and the end of an operation, with the duration measured in between.
+// It is safe for concurrent use, only the first call to Done logs the end.
+type OperationLog struct {
+	lock      sync.Mutex
+	logger    Logger
+	clock     clock.PassiveClock
+	operation Operation
+	report    *report.OperationReport
+	start     time.Time
+	done      bool
+}
+
+// StartOperation logs the start of the operation name to logger and returns a handle logging its end,
+// the lines are attributed to the operation and its duration is measured with clock.
+// The end of the operation is also marked in operationReport, when not nil.
+func StartOperation(logger Logger, clock clock.PassiveClock, name string, operationType report.OperationType, operationReport *report.OperationReport) *OperationLog {
+	operation, ok := operations[operationType]
+	if !ok {
+		operation = Internal
+	}
+	logger = OperationStarted(logger.WithOperation(name, operationType))
+	Running(logger, operation, RunStatus)
+	return &OperationLog{
+		logger:    logger,
+		clock:     clock,
+		operation: operation,
+		report:    operationReport,
+		start:     clock.Now(),
+	}
+}
+
+// Logger returns the logger lines of the operation are logged with.
+func (o *OperationLog) Logger() Logger {
+	return o.logger
+}
+
+// Done logs the end of the operation, a success without err and a failure with it, and returns err.
+// When Done is deferred, a panic of the operation is recovered and logged as a failure, see also DoneWith.
+func (o *OperationLog) Done(err error) error {
+	if r := recover(); r != nil {
+		err = panicError(r)
+	}
+	return o.end(err)
+}
+
+// DoneWith is Done for deferred calls, the error is read from errp and the error of a recovered panic is written to it:
+//
+//	defer op.DoneWith(&err)
+func (o *OperationLog) DoneWith(errp *error) {
+	var err error
+	if errp != nil {
+		err = *errp
+	}
+	if r := recover(); r != nil {
+		err = panicError(r)
+	}
+	err = o.end(err)
+	if errp != nil {
+		*errp = err
+	}
+}
+
+func (o *OperationLog) end(err error) error {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	if o.done {
+		return err
+	}
+	o.done = true
+	took := message("took " + formatDuration(o.logger, o.clock.Since(o.start), roundDuration(time.Millisecond)))
+	if err != nil {
+		Failure(o.logger, o.operation, ErrorStatus, took, ErrSection(err))
+	} else {
+		Success(o.logger, o.operation, DoneStatus, took)
+	}
+	if o.report != nil {
+		o.report.MarkOperationEnd(err)
+	}
+	return err
+}
+
+// panicError converts a recovered panic value to an error.
+func panicError(r any) error {
+	if err, ok := r.(error); ok {
+		return fmt.Errorf("operation panicked: %w", err)
+	}
+	return fmt.Errorf("operation panicked: %v", r)
+}
@@ -0,0 +1,98 @@
+package logging
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/report"
+	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
+	"github.com/stretchr/testify/assert"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+// operationLines returns a logger recording its lines as "<operation name> <operation> <status> <marker> <message>".
+func operationLines(fakeClock *tclock.FakeClock) (Logger, *[]string) {
+	var lines []string
+	sink := sinkFunc(func(entry Entry) error {
+		lines = append(lines, strings.Join([]string{entry.OperationName, string(entry.Operation), string(entry.Status), entry.Style.Marker, entry.Message}, " "))
+		return nil
+	})
+	return NewLogger(&tlogging.FakeTLogger{}, fakeClock, "test", "step", WithSink(sink), WithoutText()), &lines
+}
+
+func TestStartOperation(t *testing.T) {
+	start := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		operationType report.OperationType
+		err           error
+		want          []string
+		wantResult    string
+	}{{
+		name:          "success",
+		operationType: report.OperationTypeApply,
+		want: []string{
+			"Apply deployment.yaml APPLY RUN  ",
+			"Apply deployment.yaml APPLY DONE OK took 1.5s",
+		},
+		wantResult: "Success",
+	}, {
+		name:          "failure",
+		operationType: report.OperationTypeAssert,
+		err:           errors.New("not ready"),
+		want: []string{
+			"Apply deployment.yaml ASSERT RUN  ",
+			"Apply deployment.yaml ASSERT ERROR FAIL took 1.5s\n=== ERROR\nnot ready",
+		},
+		wantResult: "Failure",
+	}, {
+		name:          "unknown type",
+		operationType: "wait",
+		want: []string{
+			"Apply deployment.yaml INTERNAL RUN  ",
+			"Apply deployment.yaml INTERNAL DONE OK took 1.5s",
+		},
+		wantResult: "Success",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClock := tclock.NewFakeClock(start)
+			logger, lines := operationLines(fakeClock)
+			operationReport := report.NewOperationWithClock("Apply deployment.yaml", tt.operationType, fakeClock)
+			op := StartOperation(logger, fakeClock, "Apply deployment.yaml", tt.operationType, operationReport)
+			fakeClock.Step(1500 * time.Millisecond)
+			assert.Equal(t, tt.err, op.Done(tt.err))
+			// the end is logged once
+			op.Done(errors.New("again"))
+			assert.Equal(t, tt.want, *lines)
+			assert.Equal(t, tt.wantResult, operationReport.Result)
+			assert.Equal(t, "1.500", operationReport.Time)
+		})
+	}
+}
+
+func TestOperationLog_Panic(t *testing.T) {
+	fakeClock := tclock.NewFakeClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	logger, lines := operationLines(fakeClock)
+	run := func() (err error) {
+		op := StartOperation(logger, fakeClock, "Script", report.OperationTypeScript, nil)
+		defer op.DoneWith(&err)
+		fakeClock.Step(time.Second)
+		panic("boom")
+	}
+	assert.EqualError(t, run(), "operation panicked: boom")
+	// a deferred Done recovers too, the error can't reach the caller
+	assert.NotPanics(t, func() {
+		op := StartOperation(logger, fakeClock, "Script", report.OperationTypeScript, nil)
+		defer op.Done(nil)
+		panic(errors.New("boom"))
+	})
+	assert.Equal(t, []string{
+		"Script SCRIPT RUN  ",
+		"Script SCRIPT ERROR FAIL took 1s\n=== ERROR\noperation panicked: boom",
+		"Script SCRIPT RUN  ",
+		"Script SCRIPT ERROR FAIL took 0s\n=== ERROR\noperation panicked: boom",
+	}, *lines)
+}
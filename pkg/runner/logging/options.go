@@ -0,0 +1,214 @@
+package logging
+
+import (
+	"context"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/report"
+)
+
+// Option configures a logger created with NewLogger.
+type Option func(*logger)
+
+// WithJSON also writes the log lines to w as JSON objects.
+func WithJSON(w *JSONWriter) Option {
+	return func(l *logger) {
+		l.sinks = append(l.sinks, w)
+	}
+}
+
+// WithSink also writes the log lines to sink.
+func WithSink(sink Sink) Option {
+	return func(l *logger) {
+		l.sinks = append(l.sinks, sink)
+	}
+}
+
+// WithTestCorrelationID attributes the log lines to the test identified by id in the report, see CorrelateOperation for operations.
+func WithTestCorrelationID(id string) Option {
+	return func(l *logger) {
+		l.testID = id
+	}
+}
+
+// WithWorker prints the worker slot the test runs in, like w03, in a column of the log lines, see the Worker of the report.
+func WithWorker(worker int) Option {
+	return func(l *logger) {
+		l.worker = worker
+	}
+}
+
+// WithoutText disables the human readable log lines written to the TLogger, typically when JSON lines replace them.
+func WithoutText() Option {
+	return func(l *logger) {
+		l.noText = true
+	}
+}
+
+// WithTee also writes the human readable log lines to the files of tee, even when they are not written to the TLogger.
+func WithTee(tee *Tee) Option {
+	return func(l *logger) {
+		l.sinks = append(l.sinks, tee)
+	}
+}
+
+// WithColor sets the color mode of the logger, it defaults to the mode set with SetColorMode.
+func WithColor(mode ColorMode) Option {
+	return func(l *logger) {
+		l.colors = mode.Enabled()
+	}
+}
+
+// WithGlyphs sets the glyph mode of the logger, it defaults to the mode set with SetGlyphMode.
+func WithGlyphs(mode GlyphMode) Option {
+	return func(l *logger) {
+		l.glyphs = mode.Glyphs()
+	}
+}
+
+// WithCapture also keeps the human readable log lines in captures, under the key of the test of the logger, see TestKey.
+func WithCapture(captures *Captures) Option {
+	return func(l *logger) {
+		l.sinks = append(l.sinks, captures)
+	}
+}
+
+// WithElapsed prints the duration elapsed since start, the start of the test, in each log line.
+func WithElapsed(start time.Time) Option {
+	return func(l *logger) {
+		l.testStart = start
+	}
+}
+
+// WithTimestampLayout sets the layout of the timestamps of human readable log lines, see ParseTimestampLayout.
+func WithTimestampLayout(layout TimestampLayout) Option {
+	return func(l *logger) {
+		l.timestamp = layout
+	}
+}
+
+// WithTestNamespace sets the namespace of the test the lines are logged for, see WithNamespace for loggers created before
+// it is known.
+func WithTestNamespace(namespace string) Option {
+	return func(l *logger) {
+		l.namespace = namespace
+	}
+}
+
+// WithNamespaceColumn prints the namespace of the test, like [chainsaw-happy-cat], in a column of the log lines once it
+// is known, see WithNamespace.
+func WithNamespaceColumn() Option {
+	return func(l *logger) {
+		l.namespaceColumn = true
+	}
+}
+
+// WithDeterministic makes the logger render the same lines on every identical run, typically to compare the output of
+// a run with golden files: timestamps are suppressed, colors are disabled, outcomes are marked with the ASCII glyphs,
+// durations are rendered as DurationPlaceholder, the worker slot and the namespace are not printed and the resources of
+// lines about several resources are sorted. It takes precedence over the options setting the colors, the glyphs, the
+// timestamps, the worker slot and the namespace column, whatever their order.
+func WithDeterministic() Option {
+	return func(l *logger) {
+		l.deterministic = true
+	}
+}
+
+// WithStepOrdinal sets the position of the step in the test, numbered from 1, and the number of steps of the test.
+// Steps without a name are labeled with it in human readable lines, see StepLabel.
+func WithStepOrdinal(index int, count int) Option {
+	return func(l *logger) {
+		l.ordinals.Step, l.ordinals.Steps = index, count
+	}
+}
+
+// WithOrdinals makes human readable lines render the ordinals of the step and the operation after their names too,
+// by default they replace empty names only.
+func WithOrdinals() Option {
+	return func(l *logger) {
+		l.ordinals.Always = true
+	}
+}
+
+// WithUTC sets whether the lines of the logger are stamped in UTC, it defaults to the value set with SetUTC.
+func WithUTC(enabled bool) Option {
+	return func(l *logger) {
+		l.utc = enabled
+	}
+}
+
+// WithResourceFormat sets how the resources of human readable log lines are rendered, see ParseResourceFormat.
+func WithResourceFormat(format ResourceFormat) Option {
+	return func(l *logger) {
+		l.resourceFormat = format
+	}
+}
+
+// WithResourceList sets how the resources of human readable lines about several resources are rendered, see ParseResourceListFormat.
+func WithResourceList(format ResourceListFormat) Option {
+	return func(l *logger) {
+		l.resourceList = format
+	}
+}
+
+// WithMessageMaxSize truncates the messages of log lines longer than maxSize bytes, it defaults to DefaultMessageMaxSize.
+// A maxSize of zero or less keeps messages whole, see TruncateMessage.
+func WithMessageMaxSize(maxSize int) Option {
+	return func(l *logger) {
+		l.messageMaxSize = maxSize
+	}
+}
+
+// WithDiffMaxHunks keeps the first maxHunks hunks of the diffs logged with LogDiff, it defaults to DefaultDiffMaxHunks.
+// A maxHunks of zero or less keeps diffs whole.
+func WithDiffMaxHunks(maxHunks int) Option {
+	return func(l *logger) {
+		l.diffMaxHunks = maxHunks
+	}
+}
+
+// WithOperationTypes limits the human readable lines written to the TLogger or the writer of the logger to those of
+// the operations of the given types, see FilterOperationTypes. Sinks added with other options get every line.
+func WithOperationTypes(types ...report.OperationType) Option {
+	return func(l *logger) {
+		l.consoleTypes = types
+	}
+}
+
+// WithRedactor replaces the secrets found by redactor in the messages of log lines, before they are written to any sink.
+func WithRedactor(redactor *Redactor) Option {
+	return func(l *logger) {
+		l.redactor = redactor
+	}
+}
+
+// WithColumnWidths pads and truncates the test, step and operation names of human readable log lines, see FitColumn.
+func WithColumnWidths(widths ColumnWidths) Option {
+	return func(l *logger) {
+		l.columns = widths
+	}
+}
+
+// WithContinuation sets how the lines of multi-line messages of human readable log lines are rendered, see ParseContinuationFormat.
+func WithContinuation(format ContinuationFormat) Option {
+	return func(l *logger) {
+		l.continuation = format
+	}
+}
+
+type optionsKey struct{}
+
+// WithOptions stores options in the context, they are applied to the loggers created by the test processors.
+func WithOptions(ctx context.Context, options ...Option) context.Context {
+	return context.WithValue(ctx, optionsKey{}, append(OptionsFromContext(ctx), options...))
+}
+
+// OptionsFromContext returns the logger options stored in the context.
+func OptionsFromContext(ctx context.Context) []Option {
+	if ctx != nil {
+		if v, ok := ctx.Value(optionsKey{}).([]Option); ok {
+			return v[:len(v):len(v)]
+		}
+	}
+	return nil
+}
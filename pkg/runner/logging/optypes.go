@@ -0,0 +1,101 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kyverno/chainsaw/pkg/report"
+)
+
+// OperationTypesEnv is the environment variable limiting the console output to the lines of some operation types, as a
+// comma separated list like "script,command", see ParseOperationTypes.
+const OperationTypesEnv = "CHAINSAW_LOG_OPERATION_TYPES"
+
+// operationTypes are the types of the operations of a step, sorted by name.
+var operationTypes = []report.OperationType{
+	report.OperationTypeApply,
+	report.OperationTypeAssert,
+	report.OperationTypeCommand,
+	report.OperationTypeCreate,
+	report.OperationTypeDelete,
+	report.OperationTypeError,
+	report.OperationTypeScript,
+	report.OperationTypeSleep,
+}
+
+// SupportedOperationTypes returns the names of the operation types.
+func SupportedOperationTypes() []string {
+	names := make([]string, 0, len(operationTypes))
+	for _, operationType := range operationTypes {
+		names = append(names, string(operationType))
+	}
+	return names
+}
+
+// ParseOperationType parses the name of an operation type, like "script".
+func ParseOperationType(name string) (report.OperationType, error) {
+	for _, operationType := range operationTypes {
+		if strings.EqualFold(name, string(operationType)) {
+			return operationType, nil
+		}
+	}
+	return "", fmt.Errorf("invalid operation type %q (%s)", name, strings.Join(SupportedOperationTypes(), "|"))
+}
+
+// ParseOperationTypes parses a comma separated list of operation types like "script,command", empty items are ignored.
+func ParseOperationTypes(value string) ([]report.OperationType, error) {
+	var types []report.OperationType
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		operationType, err := ParseOperationType(name)
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, operationType)
+	}
+	return types, nil
+}
+
+// OperationTypesFromEnv returns the operation types the environment limits the console output to, none when it doesn't.
+func OperationTypesFromEnv() ([]report.OperationType, error) {
+	value, _ := lookupEnv(OperationTypesEnv)
+	types, err := ParseOperationTypes(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", OperationTypesEnv, err)
+	}
+	return types, nil
+}
+
+// FilterOperationTypes returns a sink writing to sink the lines of the operations of the given types only, lines not
+// logged by an operation, like those of the runner, always pass. Without types, sink is returned as is.
+func FilterOperationTypes(sink Sink, types ...report.OperationType) Sink {
+	if len(types) == 0 {
+		return sink
+	}
+	allowed := make(map[report.OperationType]bool, len(types))
+	for _, operationType := range types {
+		allowed[operationType] = true
+	}
+	return operationTypeFilter{sink: sink, allowed: allowed}
+}
+
+type operationTypeFilter struct {
+	sink    Sink
+	allowed map[report.OperationType]bool
+}
+
+func (f operationTypeFilter) WriteEntry(entry Entry) error {
+	if entry.OperationType == "" || f.allowed[entry.OperationType] {
+		return f.sink.WriteEntry(entry)
+	}
+	return nil
+}
+
+func (f operationTypeFilter) Flush() error {
+	if flusher, ok := f.sink.(Flusher); ok {
+		return flusher.Flush()
+	}
+	return nil
+}
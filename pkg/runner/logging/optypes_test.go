@@ -0,0 +1,119 @@
+package logging
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/report"
+	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
+	"github.com/stretchr/testify/assert"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+func TestParseOperationTypes(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []report.OperationType
+		wantErr string
+	}{{
+		name: "empty",
+	}, {
+		name:  "list",
+		value: "script, Command,,",
+		want:  []report.OperationType{report.OperationTypeScript, report.OperationTypeCommand},
+	}, {
+		name:    "invalid",
+		value:   "script,shell",
+		wantErr: `invalid operation type "shell" (apply|assert|command|create|delete|error|script|sleep)`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseOperationTypes(tt.value)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestOperationTypesFromEnv(t *testing.T) {
+	defer func(f func(string) (string, bool)) { lookupEnv = f }(lookupEnv)
+	lookupEnv = func(string) (string, bool) { return "", false }
+	types, err := OperationTypesFromEnv()
+	assert.NoError(t, err)
+	assert.Empty(t, types)
+	lookupEnv = func(name string) (string, bool) { return map[string]string{OperationTypesEnv: "sleep"}[name], true }
+	types, err = OperationTypesFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, []report.OperationType{report.OperationTypeSleep}, types)
+	lookupEnv = func(name string) (string, bool) { return map[string]string{OperationTypesEnv: "nap"}[name], true }
+	_, err = OperationTypesFromEnv()
+	assert.ErrorContains(t, err, OperationTypesEnv+`: invalid operation type "nap"`)
+}
+
+func TestWithOperationTypes(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	mockT := &tlogging.FakeTLogger{}
+	captures := NewCapturesWithMaxSize(0)
+	var json bytes.Buffer
+	logger := NewLogger(mockT, fakeClock, "test", "step", WithColor(ColorNever), WithOperationTypes(report.OperationTypeScript),
+		WithCapture(captures), WithJSON(NewJSONWriter(&json)))
+	logger.Log(Internal, LogStatus, nil, s("suite"))
+	logger.WithOperation("apply pod", report.OperationTypeApply).Log(Apply, OkStatus, nil)
+	logger.WithOperation("run script", report.OperationTypeScript).Log(Script, LogStatus, nil, s("hello"))
+	// the console only gets the lines of scripts and those of no operation
+	var console []string
+	for _, message := range mockT.Messages {
+		console = append(console, strings.TrimLeft(message, "\b"))
+	}
+	assert.Equal(t, []string{
+		"| 10:30:00 | test | step | INTERNAL  | LOG   |\nsuite",
+		"| 10:30:00 | test | step | run script | SCRIPT    | LOG   |\nhello",
+	}, console)
+	// the captures and the other sinks get every line
+	var captured []string
+	for _, line := range captures.Lines(TestKey{Name: "test"}) {
+		captured = append(captured, line.Message)
+	}
+	assert.Equal(t, []string{
+		"| 10:30:00 | test | step | INTERNAL  | LOG   |\nsuite",
+		"| 10:30:00 | test | step | apply pod | APPLY     | OK    |",
+		"| 10:30:00 | test | step | run script | SCRIPT    | LOG   |\nhello",
+	}, captured)
+	assert.Equal(t, 3, strings.Count(json.String(), "\n"))
+}
+
+func TestFilterOperationTypes_FailuresOnly(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	var out bytes.Buffer
+	failures := NewFailureOutput(&out, 0)
+	sink := FilterOperationTypes(failures, report.OperationTypeScript, report.OperationTypeCommand)
+	logger := func(test string) Logger {
+		return NewLogger(&tlogging.FakeTLogger{}, fakeClock, test, "step", WithSink(sink), WithoutText(), WithColor(ColorNever))
+	}
+	failures.Start(TestKey{Name: "passing"})
+	failures.Start(TestKey{Name: "failing"})
+	logger("passing").WithOperation("run script", report.OperationTypeScript).Log(Script, LogStatus, nil, s("passed"))
+	logger("failing").Log(Internal, LogStatus, nil, s("setup"))
+	Failure(logger("failing").WithOperation("assert pod", report.OperationTypeAssert), Assert, ErrorStatus, s("boom"))
+	logger("failing").WithOperation("run command", report.OperationTypeCommand).Log(Command, LogStatus, nil, s("debug"))
+	// passing tests still print nothing, failed ones their lines of the given types
+	assert.NoError(t, failures.Complete(TestKey{Name: "passing"}, false))
+	assert.Zero(t, out.Len())
+	assert.NoError(t, failures.Complete(TestKey{Name: "failing"}, true))
+	assert.Equal(t, `===== FAIL failing
+| 10:30:00 | failing | step | INTERNAL  | LOG   |
+setup
+| 10:30:00 | failing | step | run command | CMD       | LOG   |
+debug
+----- failing failed
+`, out.String())
+	// without types the sink is left as is
+	assert.Equal(t, Sink(failures), FilterOperationTypes(failures))
+}
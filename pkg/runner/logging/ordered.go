@@ -0,0 +1,306 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/report"
+	"k8s.io/utils/clock"
+)
+
+const (
+	// DefaultBufferLines is the default maximum number of lines buffered per test by OrderedOutput.
+	DefaultBufferLines = 5000
+	// DefaultHeartbeatInterval is the default interval between heartbeat lines of tests being buffered.
+	DefaultHeartbeatInterval = time.Minute
+)
+
+// BufferOrder determines the order in which OrderedOutput flushes the blocks of buffered tests.
+type BufferOrder string
+
+const (
+	// CompletionOrder flushes the block of a test as soon as it completes.
+	CompletionOrder BufferOrder = "completion"
+	// DeclarationOrder flushes the block of a test once the tests declared before it were flushed.
+	DeclarationOrder BufferOrder = "declaration"
+)
+
+// SupportedBufferOrders returns the supported buffer orders.
+func SupportedBufferOrders() []string {
+	return []string{string(CompletionOrder), string(DeclarationOrder)}
+}
+
+// ParseBufferOrder parses a buffer order name, an empty name is the completion order.
+func ParseBufferOrder(name string) (BufferOrder, error) {
+	if name == "" {
+		return CompletionOrder, nil
+	}
+	for _, order := range SupportedBufferOrders() {
+		if strings.EqualFold(name, order) {
+			return BufferOrder(order), nil
+		}
+	}
+	return CompletionOrder, fmt.Errorf("invalid buffer order %q (completion|declaration)", name)
+}
+
+// OrderedOutput writes test logs without interleaving the lines of concurrent tests, it is safe for concurrent use.
+// A test starting while no other test streams is streamed live, tests starting meanwhile are buffered
+// and written as a contiguous block when they complete. A test buffering more than the maximum number of lines
+// has its block written early and is streamed from then on. The lines of failed tests are written again on close.
+type OrderedOutput struct {
+	lock     sync.Mutex
+	w        io.Writer
+	clock    clock.PassiveClock
+	order    BufferOrder
+	maxLines int
+	captures *Captures
+	tests    map[TestKey]*orderedTest
+	live     TestKey
+	// declared are the tests in declaration order, next is the index of the first one not written yet
+	declared []TestKey
+	next     int
+	written  map[TestKey]bool
+	failed   []orderedBlock
+	stop     chan struct{}
+}
+
+// orderedTest is the state of a started test.
+type orderedTest struct {
+	started   time.Time
+	lines     int
+	streaming bool
+	done      bool
+	failed    bool
+}
+
+// orderedBlock holds the lines of a failed test, written again on close.
+type orderedBlock struct {
+	test  TestKey
+	lines []report.LogLine
+}
+
+// NewOrderedOutput returns an OrderedOutput writing to w, at most maxLines lines are buffered per test.
+// The declared tests are only used by the declaration order.
+func NewOrderedOutput(w io.Writer, clock clock.PassiveClock, order BufferOrder, maxLines int, declared ...TestKey) *OrderedOutput {
+	if maxLines <= 0 {
+		maxLines = DefaultBufferLines
+	}
+	return &OrderedOutput{
+		w:        w,
+		clock:    clock,
+		order:    order,
+		maxLines: maxLines,
+		captures: NewCaptures(maxLines),
+		tests:    map[TestKey]*orderedTest{},
+		declared: declared,
+		written:  map[TestKey]bool{},
+	}
+}
+
+// Start starts writing the lines of test, lines of tests not started are dropped.
+func (o *OrderedOutput) Start(test TestKey) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	state := &orderedTest{started: o.clock.Now()}
+	if o.live == (TestKey{}) {
+		o.live = test
+		state.streaming = true
+	}
+	o.tests[test] = state
+}
+
+func (o *OrderedOutput) WriteEntry(entry Entry) error {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	state, ok := o.tests[entry.TestKey()]
+	if !ok || state.done {
+		return nil
+	}
+	// the buffer is full, it is written and the test is streamed from then on
+	if !state.streaming && state.lines == o.maxLines {
+		if err := o.writeBlock(entry.TestKey(), fmt.Sprintf("===== %s (more than %d lines, streaming)", entry.Test, o.maxLines)); err != nil {
+			return err
+		}
+		state.streaming = true
+	}
+	state.lines++
+	// streamed lines are also captured, they are written again if the test fails
+	if err := o.captures.WriteEntry(entry); err != nil {
+		return err
+	}
+	if state.streaming {
+		_, err := fmt.Fprintln(o.w, report.StripANSI(FormatText(entry, false)))
+		return err
+	}
+	return nil
+}
+
+// Complete marks test as completed, its block is written according to the buffer order.
+func (o *OrderedOutput) Complete(test TestKey, failed bool) error {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	state, ok := o.tests[test]
+	if !ok {
+		// skipped before starting, there is nothing to write
+		o.written[test] = true
+		return o.flush(false)
+	}
+	state.done = true
+	state.failed = failed
+	if o.live == test {
+		o.live = TestKey{}
+	}
+	return o.flush(false)
+}
+
+// flush writes the blocks of completed tests allowed by the buffer order,
+// when forced declared tests not started don't hold back the tests declared after them.
+func (o *OrderedOutput) flush(force bool) error {
+	if o.order == DeclarationOrder {
+		declared := map[TestKey]bool{}
+		for _, test := range o.declared {
+			declared[test] = true
+		}
+		for ; o.next < len(o.declared); o.next++ {
+			test := o.declared[o.next]
+			if o.written[test] {
+				continue
+			}
+			state, ok := o.tests[test]
+			if !ok && force {
+				continue
+			}
+			if !ok || !state.done {
+				break
+			}
+			if err := o.complete(test, state); err != nil {
+				return err
+			}
+		}
+		// tests not declared don't wait
+		for _, test := range o.sortedTests() {
+			if state := o.tests[test]; state.done && !declared[test] {
+				if err := o.complete(test, state); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	for _, test := range o.sortedTests() {
+		if state := o.tests[test]; state.done {
+			if err := o.complete(test, state); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// complete writes the block of a completed test if it was buffered, and keeps its lines if it failed.
+func (o *OrderedOutput) complete(test TestKey, state *orderedTest) error {
+	delete(o.tests, test)
+	o.written[test] = true
+	if state.failed {
+		o.failed = append(o.failed, orderedBlock{test: test, lines: o.captures.Lines(test)})
+	}
+	if state.streaming {
+		o.captures.Clear(test)
+		return nil
+	}
+	return o.writeBlock(test, "===== "+test.Name)
+}
+
+// writeBlock writes header and the lines buffered for test.
+func (o *OrderedOutput) writeBlock(test TestKey, header string) error {
+	var b strings.Builder
+	b.WriteString(header)
+	b.WriteString("\n")
+	for _, line := range o.captures.Take(test) {
+		b.WriteString(line.Message)
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(o.w, b.String())
+	return err
+}
+
+// Heartbeat writes a line for each test being buffered, telling it is still running.
+func (o *OrderedOutput) Heartbeat() error {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	for _, test := range o.sortedTests() {
+		state := o.tests[test]
+		if state.streaming || state.done {
+			continue
+		}
+		elapsed := o.clock.Since(state.started).Round(time.Second)
+		if _, err := fmt.Fprintf(o.w, "..... %s still running (%s, %d lines buffered)\n", test.Name, elapsed, state.lines); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StartHeartbeat writes heartbeat lines every interval, until the output is closed.
+func (o *OrderedOutput) StartHeartbeat(interval time.Duration) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	if o.stop != nil || interval <= 0 {
+		return
+	}
+	o.stop = make(chan struct{})
+	go func(stop <-chan struct{}) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				_ = o.Heartbeat()
+			}
+		}
+	}(o.stop)
+}
+
+// Close writes the blocks of tests not completed, typically when the run is interrupted, then the lines of failed tests.
+func (o *OrderedOutput) Close() error {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	if o.stop != nil {
+		close(o.stop)
+		o.stop = nil
+	}
+	for _, state := range o.tests {
+		state.done = true
+	}
+	if err := o.flush(true); err != nil {
+		return err
+	}
+	for _, block := range o.failed {
+		var b strings.Builder
+		fmt.Fprintf(&b, "===== FAIL %s\n", block.test.Name)
+		for _, line := range block.lines {
+			b.WriteString(line.Message)
+			b.WriteString("\n")
+		}
+		if _, err := io.WriteString(o.w, b.String()); err != nil {
+			return err
+		}
+	}
+	o.failed = nil
+	return nil
+}
+
+func (o *OrderedOutput) sortedTests() []TestKey {
+	tests := make([]TestKey, 0, len(o.tests))
+	for test := range o.tests {
+		tests = append(tests, test)
+	}
+	sort.Slice(tests, func(i, j int) bool { return tests[i].Less(tests[j]) })
+	return tests
+}
@@ -0,0 +1,149 @@
+package logging
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
+	"github.com/stretchr/testify/assert"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+func TestParseBufferOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    BufferOrder
+		wantErr bool
+	}{{
+		name: "",
+		want: CompletionOrder,
+	}, {
+		name: "completion",
+		want: CompletionOrder,
+	}, {
+		name: "Declaration",
+		want: DeclarationOrder,
+	}, {
+		name:    "random",
+		want:    CompletionOrder,
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseBufferOrder(tt.name)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestOrderedOutput(t *testing.T) {
+	fakeClock := tclock.NewFakeClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	line := func(test string) string {
+		return "| 10:30:00 | " + test + " | step | APPLY     | RUN   |\n"
+	}
+	tests := []struct {
+		name  string
+		order BufferOrder
+		want  string
+	}{{
+		name:  "completion",
+		order: CompletionOrder,
+		want: line("first") +
+			"===== third\n" + line("third") +
+			line("first") +
+			"===== second\n" + line("second") +
+			"===== FAIL third\n" + line("third"),
+	}, {
+		name:  "declaration",
+		order: DeclarationOrder,
+		want: line("first") +
+			line("first") +
+			"===== second\n" + line("second") +
+			"===== third\n" + line("third") +
+			"===== FAIL third\n" + line("third"),
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			output := NewOrderedOutput(&out, fakeClock, tt.order, 0, TestKey{Name: "first"}, TestKey{Name: "skipped"}, TestKey{Name: "second"}, TestKey{Name: "third"})
+			logger := func(test string) Logger {
+				return NewLogger(&tlogging.FakeTLogger{}, fakeClock, test, "step", WithSink(output), WithoutText())
+			}
+			// the first test streams live, the tests started meanwhile are buffered
+			output.Start(TestKey{Name: "first"})
+			output.Start(TestKey{Name: "second"})
+			output.Start(TestKey{Name: "third"})
+			logger("first").Log(Apply, RunStatus, nil)
+			logger("second").Log(Apply, RunStatus, nil)
+			logger("third").Log(Apply, RunStatus, nil)
+			assert.NoError(t, output.Complete(TestKey{Name: "skipped"}, false))
+			assert.NoError(t, output.Complete(TestKey{Name: "third"}, true))
+			logger("first").Log(Apply, RunStatus, nil)
+			assert.NoError(t, output.Complete(TestKey{Name: "second"}, false))
+			assert.NoError(t, output.Complete(TestKey{Name: "first"}, false))
+			// lines of completed and unknown tests are dropped
+			logger("first").Log(Apply, RunStatus, nil)
+			logger("chainsaw").Log(Apply, RunStatus, nil)
+			assert.NoError(t, output.Close())
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestOrderedOutput_Limit(t *testing.T) {
+	fakeClock := tclock.NewFakeClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	var out bytes.Buffer
+	output := NewOrderedOutput(&out, fakeClock, CompletionOrder, 2)
+	logger := NewLogger(&tlogging.FakeTLogger{}, fakeClock, "second", "step", WithSink(output), WithoutText())
+	output.Start(TestKey{Name: "first"})
+	output.Start(TestKey{Name: "second"})
+	logger.Log(Apply, RunStatus, nil, s("1"))
+	logger.Log(Apply, RunStatus, nil, s("2"))
+	fakeClock.Step(90 * time.Second)
+	assert.NoError(t, output.Heartbeat())
+	assert.Equal(t, "..... second still running (1m30s, 2 lines buffered)\n", out.String())
+	out.Reset()
+	// the buffer is full, the test is streamed
+	logger.Log(Apply, RunStatus, nil, s("3"))
+	assert.Equal(t, `===== second (more than 2 lines, streaming)
+| 10:30:00 | second | step | APPLY     | RUN   |
+1
+| 10:30:00 | second | step | APPLY     | RUN   |
+2
+| 10:31:30 | second | step | APPLY     | RUN   |
+3
+`, out.String())
+	out.Reset()
+	assert.NoError(t, output.Heartbeat())
+	assert.Empty(t, out.String())
+	// streamed tests have nothing left to write on close
+	assert.NoError(t, output.Close())
+	assert.Empty(t, out.String())
+}
+
+func TestOrderedOutput_DuplicateNames(t *testing.T) {
+	fakeClock := tclock.NewFakeClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	var out bytes.Buffer
+	a, b := TestKey{Path: "tests/a", Name: "test"}, TestKey{Path: "tests/b", Name: "test"}
+	output := NewOrderedOutput(&out, fakeClock, DeclarationOrder, 0, a, b)
+	logger := func(path string) Logger {
+		return NewLogger(&tlogging.FakeTLogger{}, fakeClock, "test", "step", WithSink(output), WithoutText(), WithTestPath(path))
+	}
+	output.Start(a)
+	output.Start(b)
+	logger("tests/a").Log(Script, LogStatus, nil, s("a"))
+	logger("tests/b").Log(Script, LogStatus, nil, s("b"))
+	assert.Equal(t, "| 10:30:00 | test | step | SCRIPT    | LOG   |\na\n", out.String())
+	out.Reset()
+	// the second test is buffered, its block waits for the first one and holds its lines only
+	assert.NoError(t, output.Complete(b, false))
+	assert.Empty(t, out.String())
+	assert.NoError(t, output.Complete(a, false))
+	assert.Equal(t, "===== test\n| 10:30:00 | test | step | SCRIPT    | LOG   |\nb\n", out.String())
+}
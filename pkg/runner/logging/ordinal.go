@@ -0,0 +1,74 @@
+package logging
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/kyverno/chainsaw/pkg/report"
+)
+
+// Ordinals are the positions of the step and the operation the lines of a logger are logged for, derived from the test
+// spec. Zero positions are unknown, no ordinal is rendered for them.
+type Ordinals struct {
+	// Step is the position of the step in the test and Steps the number of steps of the test, Step is numbered from 1.
+	Step  int
+	Steps int
+	// Phase is the phase of the step the operation runs in and Operation the position of the operation in its phase,
+	// catch and finally operations are numbered within their own phase.
+	Phase     report.OperationPhase
+	Operation int
+	// Always renders the ordinals after the names too, by default they replace empty names only.
+	Always bool
+}
+
+// StepLabel returns the label of a step in the prefix of human readable lines, its name or "step 2/5" when it has none.
+// With always, the ordinal follows the name like "deploy (2/5)". The name is returned as is when index is zero.
+func StepLabel(name string, index int, count int, always bool) string {
+	if index <= 0 {
+		return name
+	}
+	ordinal := strconv.Itoa(index)
+	if count > 0 {
+		ordinal = fmt.Sprintf("%d/%d", index, count)
+	}
+	// steps without a name are already named after their ordinal by the test processor
+	if unnamed := "step " + ordinal; name == "" || name == unnamed {
+		return unnamed
+	}
+	if always {
+		return name + " (" + ordinal + ")"
+	}
+	return name
+}
+
+// OperationLabel returns the label of an operation in the prefix of human readable lines, its name or its ordinal in
+// its phase when it has none, like "op 3" in try, "catch 3" and "finally 3". With always, the ordinal follows the name
+// like "Apply pod.yaml (op 3)". The name is returned as is when index is zero.
+func OperationLabel(name string, phase report.OperationPhase, index int, always bool) string {
+	if index <= 0 {
+		return name
+	}
+	ordinal := "op " + strconv.Itoa(index)
+	if phase != "" && phase != report.OperationPhaseTry {
+		ordinal = string(phase) + " " + strconv.Itoa(index)
+	}
+	if name == "" {
+		return ordinal
+	}
+	if always {
+		return name + " (" + ordinal + ")"
+	}
+	return name
+}
+
+// NumberOperation returns a logger whose lines are logged for the operation at index in phase, see OperationLabel.
+// Loggers not created with NewLogger, NewWriterLogger or NewSinkLogger are returned unchanged.
+func NumberOperation(l Logger, phase report.OperationPhase, index int) Logger {
+	if l, ok := l.(*logger); ok {
+		c := *l
+		c.ordinals.Phase, c.ordinals.Operation = phase, index
+		c.text = newTextCache(&c)
+		return &c
+	}
+	return l
+}
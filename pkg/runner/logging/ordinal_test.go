@@ -0,0 +1,176 @@
+package logging
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/report"
+	"github.com/stretchr/testify/assert"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+func TestStepLabel(t *testing.T) {
+	tests := []struct {
+		name   string
+		step   string
+		index  int
+		count  int
+		always bool
+		want   string
+	}{{
+		name: "unknown",
+		step: "deploy",
+		want: "deploy",
+	}, {
+		name:  "named",
+		step:  "deploy",
+		index: 2,
+		count: 5,
+		want:  "deploy",
+	}, {
+		name:  "unnamed",
+		index: 2,
+		count: 5,
+		want:  "step 2/5",
+	}, {
+		name:  "unnamed without count",
+		index: 2,
+		want:  "step 2",
+	}, {
+		name:   "always",
+		step:   "deploy",
+		index:  2,
+		count:  5,
+		always: true,
+		want:   "deploy (2/5)",
+	}, {
+		name:   "always unnamed",
+		step:   "step 2/5",
+		index:  2,
+		count:  5,
+		always: true,
+		want:   "step 2/5",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, StepLabel(tt.step, tt.index, tt.count, tt.always))
+		})
+	}
+}
+
+func TestOperationLabel(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation string
+		phase     report.OperationPhase
+		index     int
+		always    bool
+		want      string
+	}{{
+		name:      "unknown",
+		operation: "Apply pod.yaml",
+		phase:     report.OperationPhaseTry,
+		want:      "Apply pod.yaml",
+	}, {
+		name:      "named",
+		operation: "Apply pod.yaml",
+		phase:     report.OperationPhaseTry,
+		index:     3,
+		want:      "Apply pod.yaml",
+	}, {
+		name:  "try",
+		phase: report.OperationPhaseTry,
+		index: 3,
+		want:  "op 3",
+	}, {
+		name:  "no phase",
+		index: 3,
+		want:  "op 3",
+	}, {
+		name:  "catch",
+		phase: report.OperationPhaseCatch,
+		index: 1,
+		want:  "catch 1",
+	}, {
+		name:  "finally",
+		phase: report.OperationPhaseFinally,
+		index: 2,
+		want:  "finally 2",
+	}, {
+		name:      "always",
+		operation: "Apply pod.yaml",
+		phase:     report.OperationPhaseTry,
+		index:     3,
+		always:    true,
+		want:      "Apply pod.yaml (op 3)",
+	}, {
+		name:      "always catch",
+		operation: "Describe",
+		phase:     report.OperationPhaseCatch,
+		index:     1,
+		always:    true,
+		want:      "Describe (catch 1)",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, OperationLabel(tt.operation, tt.phase, tt.index, tt.always))
+		})
+	}
+}
+
+func TestLogger_Ordinals(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	tests := []struct {
+		name    string
+		step    string
+		options []Option
+		want    string
+	}{{
+		name: "unnumbered",
+		step: "deploy      ",
+		want: "| test | deploy       | Apply pod.yaml | APPLY     | OK    |\n| test | deploy       | op 2 | APPLY     | OK    |\n",
+	}, {
+		name:    "named",
+		step:    "deploy      ",
+		options: []Option{WithStepOrdinal(2, 5)},
+		want:    "| test | deploy       | Apply pod.yaml | APPLY     | OK    |\n| test | deploy       | op 2 | APPLY     | OK    |\n",
+	}, {
+		name:    "unnamed",
+		step:    "step 2/5    ",
+		options: []Option{WithStepOrdinal(2, 5)},
+		want:    "| test | step 2/5     | Apply pod.yaml | APPLY     | OK    |\n| test | step 2/5     | op 2 | APPLY     | OK    |\n",
+	}, {
+		name:    "always",
+		step:    "deploy      ",
+		options: []Option{WithStepOrdinal(2, 5), WithOrdinals()},
+		want:    "| test | deploy (2/5) | Apply pod.yaml (op 1) | APPLY     | OK    |\n| test | deploy (2/5) | op 2 | APPLY     | OK    |\n",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			options := append([]Option{WithTimestampLayout(NoTimestamp)}, tt.options...)
+			logger := NewWriterLogger(&out, fakeClock, "test", tt.step, options...)
+			named := NumberOperation(logger.WithOperation("Apply pod.yaml", report.OperationTypeApply), report.OperationPhaseTry, 1)
+			named.Log(Apply, OkStatus, nil)
+			unnamed := NumberOperation(logger.WithOperation("", report.OperationTypeApply), report.OperationPhaseTry, 2)
+			unnamed.Log(Apply, OkStatus, nil)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func TestNumberOperation_Catch(t *testing.T) {
+	var out bytes.Buffer
+	logger := NewWriterLogger(&out, tclock.NewFakePassiveClock(time.Now()), "test", "step", WithTimestampLayout(NoTimestamp))
+	NumberOperation(logger, report.OperationPhaseCatch, 1).Log(Catch, RunStatus, nil)
+	NumberOperation(logger, report.OperationPhaseFinally, 1).Log(Finally, RunStatus, nil)
+	assert.Equal(t, "| test | step | catch 1 | CATCH     | RUN   |\n| test | step | finally 1 | FINALLY   | RUN   |\n", out.String())
+	// the numbers are kept down the derivation chain
+	var other bytes.Buffer
+	numbered := NumberOperation(NewWriterLogger(&other, tclock.NewFakePassiveClock(time.Now()), "test", "step", WithTimestampLayout(NoTimestamp)), report.OperationPhaseTry, 4)
+	numbered.WithResource(nil).Log(Apply, OkStatus, nil)
+	assert.Equal(t, "| test | step | op 4 | APPLY     | OK    |\n", other.String())
+	// loggers of unknown types are returned unchanged
+	assert.Equal(t, NoOp(), NumberOperation(NoOp(), report.OperationPhaseTry, 1))
+}
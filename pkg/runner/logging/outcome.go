@@ -0,0 +1,93 @@
+package logging
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/fatih/color"
+)
+
+// Style is how log lines of an outcome are rendered, with the color or with the marker when colors are disabled.
+// The glyph of the outcome, if any, replaces the marker and is printed with colors too.
+type Style struct {
+	Color   *color.Color
+	Marker  string
+	Outcome Outcome
+}
+
+// Palette holds the styles of the outcomes logged with Success, Failure, Skip, Warn, Running and Debug.
+type Palette struct {
+	Success Style
+	Failure Style
+	Skip    Style
+	Warning Style
+	Running Style
+	Debug   Style
+}
+
+// markerWidth is the width markers are padded to, they are printed in front of the line when colors are disabled.
+const markerWidth = 4
+
+// DefaultPalette returns the palette of the default theme, see DefaultTheme.
+func DefaultPalette() Palette {
+	return DefaultTheme().Palette()
+}
+
+// palette is set with the theme when the package is initialized, see SetTheme.
+var palette atomic.Pointer[Palette]
+
+// SetPalette overrides the palette used by the outcome helpers, SetTheme also sets it.
+func SetPalette(p Palette) {
+	palette.Store(&p)
+}
+
+// GetPalette returns the palette used by the outcome helpers.
+func GetPalette() Palette {
+	return *palette.Load()
+}
+
+// styledLogger is implemented by loggers rendering the marker of a style when colors are disabled.
+type styledLogger interface {
+	logStyle(Level, Operation, Status, Style, ...fmt.Stringer)
+}
+
+func logStyle(logger Logger, level Level, operation Operation, status Status, style Style, args ...fmt.Stringer) {
+	if logger == nil {
+		return
+	}
+	if l, ok := logger.(styledLogger); ok {
+		l.logStyle(level, operation, status, style, args...)
+	} else {
+		logger.LogLevel(level, operation, status, style.Color, args...)
+	}
+}
+
+// Success logs a passed operation at the info level.
+func Success(logger Logger, operation Operation, status Status, args ...fmt.Stringer) {
+	logStyle(logger, InfoLevel, operation, status, GetPalette().Success, args...)
+}
+
+// Failure logs a failed operation at the error level.
+func Failure(logger Logger, operation Operation, status Status, args ...fmt.Stringer) {
+	logStyle(logger, ErrorLevel, operation, status, GetPalette().Failure, args...)
+}
+
+// Skip logs a skipped operation at the info level.
+func Skip(logger Logger, operation Operation, status Status, args ...fmt.Stringer) {
+	logStyle(logger, InfoLevel, operation, status, GetPalette().Skip, args...)
+}
+
+// Warn logs a warning at the warn level, use Warning to also record the warning in the report.
+func Warn(logger Logger, operation Operation, status Status, args ...fmt.Stringer) {
+	logStyle(logger, WarnLevel, operation, status, GetPalette().Warning, args...)
+}
+
+// Running logs a running operation, or its output, at the info level.
+func Running(logger Logger, operation Operation, status Status, args ...fmt.Stringer) {
+	logStyle(logger, InfoLevel, operation, status, GetPalette().Running, args...)
+}
+
+// Debug logs a line at the debug level.
+func Debug(logger Logger, operation Operation, status Status, args ...fmt.Stringer) {
+	logStyle(logger, DebugLevel, operation, status, GetPalette().Debug, args...)
+}
@@ -0,0 +1,107 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
+	"github.com/stretchr/testify/assert"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+func TestOutcomes(t *testing.T) {
+	defer SetLevel(GetLevel())
+	SetLevel(DebugLevel)
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	tests := []struct {
+		name      string
+		log       func(Logger)
+		wantPlain string
+		wantColor string
+		// wantGlyph is the colored glyph starting colored lines, other lines start with the first column
+		wantGlyph string
+	}{{
+		name:      "success",
+		log:       func(l Logger) { Success(l, Apply, DoneStatus) },
+		wantPlain: "[ok] | 10:30:00 | test | step | APPLY     | DONE  |",
+		wantColor: "\x1b[32;1mAPPLY    ",
+		wantGlyph: "\x1b[32;1m✓\x1b[0;22m ",
+	}, {
+		name:      "failure",
+		log:       func(l Logger) { Failure(l, Assert, ErrorStatus, s("boom")) },
+		wantPlain: "[fail] | 10:30:00 | test | step | ASSERT    | ERROR |\nboom",
+		wantColor: "\x1b[31;1mASSERT   ",
+		wantGlyph: "\x1b[31;1m✗\x1b[0;22m ",
+	}, {
+		name:      "skip",
+		log:       func(l Logger) { Skip(l, Apply, SkipStatus) },
+		wantPlain: "[skip] | 10:30:00 | test | step | APPLY     | SKIP  |",
+		wantColor: "\x1b[35;1mAPPLY    ",
+		wantGlyph: "\x1b[35;1m⏭\x1b[0;22m ",
+	}, {
+		name:      "warning",
+		log:       func(l Logger) { Warn(l, Delete, WarnStatus) },
+		wantPlain: "WARN | 10:30:00 | test | step | DELETE    | WARN  |",
+		wantColor: "\x1b[33;1mDELETE   ",
+	}, {
+		name:      "running",
+		log:       func(l Logger) { Running(l, Try, RunStatus) },
+		wantPlain: "\b| 10:30:00 | test | step | TRY       | RUN   |",
+		wantColor: "\x1b[36;1mTRY      ",
+	}, {
+		name:      "debug",
+		log:       func(l Logger) { Debug(l, Assert, LogStatus) },
+		wantPlain: "\b| 10:30:00 | test | step | ASSERT    | LOG   |",
+		wantColor: "\x1b[2mASSERT   ",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plain := &tlogging.FakeTLogger{}
+			tt.log(NewLogger(plain, fakeClock, "test", "step", WithColor(ColorNever), WithGlyphs(GlyphASCII)))
+			assert.Len(t, plain.Messages, 1)
+			assert.Contains(t, plain.Messages[0], tt.wantPlain)
+			assert.NotContains(t, plain.Messages[0], "\x1b")
+			colored := &tlogging.FakeTLogger{}
+			tt.log(NewLogger(colored, fakeClock, "test", "step", WithColor(ColorAlways), WithGlyphs(GlyphUnicode)))
+			assert.Len(t, colored.Messages, 1)
+			assert.Contains(t, colored.Messages[0], tt.wantColor)
+			// markers are only printed without colors, glyphs are printed anyway
+			assert.True(t, strings.HasPrefix(strings.TrimLeft(colored.Messages[0], "\b"), tt.wantGlyph+"| "))
+		})
+	}
+}
+
+func TestOutcomes_Levels(t *testing.T) {
+	defer SetLevel(GetLevel())
+	SetLevel(WarnLevel)
+	mockT := &tlogging.FakeTLogger{}
+	l := NewLogger(mockT, tclock.NewFakePassiveClock(time.Now()), "test", "step")
+	Success(l, Apply, DoneStatus)
+	Running(l, Apply, RunStatus)
+	Debug(l, Apply, LogStatus)
+	assert.Empty(t, mockT.Messages)
+	Warn(l, Apply, WarnStatus)
+	Failure(l, Apply, ErrorStatus)
+	assert.Len(t, mockT.Messages, 2)
+	// nil and foreign loggers are supported
+	Failure(nil, Apply, ErrorStatus)
+	var fake tlogging.FakeLogger
+	Failure(&fake, Apply, ErrorStatus)
+	assert.Equal(t, 1, fake.NumCalls())
+}
+
+func TestSetPalette(t *testing.T) {
+	defer SetPalette(GetPalette())
+	p := DefaultPalette()
+	p.Failure = Style{Color: color.New(color.FgMagenta), Marker: "BAD"}
+	SetPalette(p)
+	fakeClock := tclock.NewFakePassiveClock(time.Now())
+	plain := &tlogging.FakeTLogger{}
+	Failure(NewLogger(plain, fakeClock, "test", "step", WithColor(ColorNever)), Apply, ErrorStatus)
+	assert.Contains(t, plain.Messages[0], "BAD  | ")
+	colored := &tlogging.FakeTLogger{}
+	Failure(NewLogger(colored, fakeClock, "test", "step", WithColor(ColorAlways)), Apply, ErrorStatus)
+	assert.Contains(t, colored.Messages[0], "\x1b[35m")
+}
@@ -0,0 +1,93 @@
+package logging
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Redacted replaces the secrets found in log lines.
+const Redacted = "***"
+
+// minRedactedLength is the length under which literal values are not redacted, they would mask too much of the output.
+const minRedactedLength = 4
+
+// DefaultRedactionPatterns returns the patterns redacted by default: bearer tokens and AWS access keys.
+func DefaultRedactionPatterns() []*regexp.Regexp {
+	return []*regexp.Regexp{
+		regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]+=*`),
+		regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),
+		regexp.MustCompile(`(?i)\baws_secret_access_key\s*[=:]\s*[A-Za-z0-9/+=]{40}`),
+	}
+}
+
+// Redactor replaces secrets in log lines with Redacted, it is safe for concurrent use once created.
+type Redactor struct {
+	values   []string
+	patterns []*regexp.Regexp
+}
+
+// NewRedactor returns a Redactor replacing literal values and matches of patterns.
+// Values shorter than four characters are ignored.
+func NewRedactor(values []string, patterns ...*regexp.Regexp) *Redactor {
+	r := &Redactor{patterns: patterns}
+	for _, value := range values {
+		if len(value) >= minRedactedLength {
+			r.values = append(r.values, value)
+		}
+	}
+	// longer values first, a value containing another one is masked as a whole
+	sort.SliceStable(r.values, func(i, j int) bool { return len(r.values[i]) > len(r.values[j]) })
+	return r
+}
+
+// Redact replaces the secrets found in parts, the arguments of a log call.
+// Literal values are also found when they are split across consecutive parts, every part holding a piece is masked.
+func (r *Redactor) Redact(parts ...string) []string {
+	if r == nil || (len(r.values) == 0 && len(r.patterns) == 0) {
+		return parts
+	}
+	masked := r.mask(strings.Join(parts, ""))
+	out := make([]string, 0, len(parts))
+	offset := 0
+	for _, part := range parts {
+		var b strings.Builder
+		inSecret := false
+		for i := 0; i < len(part); i++ {
+			if masked[offset+i] {
+				if !inSecret {
+					b.WriteString(Redacted)
+				}
+				inSecret = true
+			} else {
+				b.WriteByte(part[i])
+				inSecret = false
+			}
+		}
+		offset += len(part)
+		line := b.String()
+		for _, pattern := range r.patterns {
+			line = pattern.ReplaceAllString(line, Redacted)
+		}
+		out = append(out, line)
+	}
+	return out
+}
+
+// mask returns, for each byte of s, whether it belongs to a literal value.
+func (r *Redactor) mask(s string) []bool {
+	masked := make([]bool, len(s))
+	for _, value := range r.values {
+		for start := 0; start < len(s); {
+			i := strings.Index(s[start:], value)
+			if i < 0 {
+				break
+			}
+			for j := start + i; j < start+i+len(value); j++ {
+				masked[j] = true
+			}
+			start += i + len(value)
+		}
+	}
+	return masked
+}
@@ -0,0 +1,142 @@
+package logging
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/kyverno/chainsaw/pkg/client"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ResourceFormat determines how the resource of human readable log lines is rendered.
+type ResourceFormat string
+
+const (
+	// FullResource renders the group, version, kind, namespace and name of the resource, e.g. apps/v1/Deployment @ default/nginx.
+	FullResource ResourceFormat = "full"
+	// NamespacedResource renders the kind, namespace and name of the resource, e.g. Deployment/default/nginx.
+	NamespacedResource ResourceFormat = "namespaced"
+	// ShortResource renders the kind and name of the resource, e.g. Deployment/nginx.
+	ShortResource ResourceFormat = "short"
+)
+
+// SupportedResourceFormats returns the supported resource formats.
+func SupportedResourceFormats() []string {
+	return []string{string(FullResource), string(NamespacedResource), string(ShortResource)}
+}
+
+// ParseResourceFormat parses a resource format name, an empty name is the full format.
+func ParseResourceFormat(name string) (ResourceFormat, error) {
+	if name == "" {
+		return FullResource, nil
+	}
+	for _, format := range SupportedResourceFormats() {
+		if strings.EqualFold(name, format) {
+			return ResourceFormat(format), nil
+		}
+	}
+	return FullResource, fmt.Errorf("invalid resource format %q (full|namespaced|short)", name)
+}
+
+// FormatResource renders resource in the given format, it defaults to the full format.
+// Cluster scoped resources have no namespace segment, and resources without an API version have no version segment.
+func FormatResource(resource ctrlclient.Object, format ResourceFormat) string {
+	gvk := resource.GetObjectKind().GroupVersionKind()
+	key := client.ObjectKey(resource)
+	switch format {
+	case ShortResource:
+		key.Namespace = ""
+		return gvk.Kind + "/" + client.Name(key)
+	case NamespacedResource:
+		return gvk.Kind + "/" + client.Name(key)
+	default:
+		kind := gvk.Kind
+		// core resources have no group, GroupVersion is then the version alone
+		if groupVersion := gvk.GroupVersion().String(); groupVersion != "" {
+			kind = groupVersion + "/" + kind
+		}
+		return kind + " @ " + client.Name(key)
+	}
+}
+
+// ResourceListFormat determines how the resources of human readable lines about several resources are rendered.
+type ResourceListFormat string
+
+const (
+	// CountResources renders the number of resources, e.g. 3 objects.
+	CountResources ResourceListFormat = "count"
+	// ListResources renders the resources in the resource format, separated by commas, e.g. Deployment/nginx, Service/nginx.
+	ListResources ResourceListFormat = "list"
+)
+
+// SupportedResourceListFormats returns the supported resource list formats.
+func SupportedResourceListFormats() []string {
+	return []string{string(CountResources), string(ListResources)}
+}
+
+// ParseResourceListFormat parses a resource list format name, an empty name is the count format.
+func ParseResourceListFormat(name string) (ResourceListFormat, error) {
+	if name == "" {
+		return CountResources, nil
+	}
+	for _, format := range SupportedResourceListFormats() {
+		if strings.EqualFold(name, format) {
+			return ResourceListFormat(format), nil
+		}
+	}
+	return CountResources, fmt.Errorf("invalid resource list format %q (count|list)", name)
+}
+
+// FormatResources renders resources, each in the given format with the list format, it defaults to the count format.
+func FormatResources(resources []ctrlclient.Object, format ResourceFormat, list ResourceListFormat) string {
+	if list != ListResources {
+		if len(resources) == 1 {
+			return "1 object"
+		}
+		return fmt.Sprintf("%d objects", len(resources))
+	}
+	formatted := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		formatted = append(formatted, FormatResource(resource, format))
+	}
+	return strings.Join(formatted, ", ")
+}
+
+// withResources returns the resources of a logger derived with WithResources, nil entries are skipped.
+// A single resource is returned alone, several are returned in a copy of objs, loggers derived from the
+// same logger never share the resources.
+func withResources(objs []ctrlclient.Object) (ctrlclient.Object, []ctrlclient.Object) {
+	var resources []ctrlclient.Object
+	for _, obj := range objs {
+		if !isNil(obj) {
+			resources = append(resources, obj)
+		}
+	}
+	switch len(resources) {
+	case 0:
+		return nil, nil
+	case 1:
+		return resources[0], nil
+	default:
+		return nil, resources
+	}
+}
+
+// isNil returns true for nil objects, including nil pointers held by the interface.
+func isNil(obj ctrlclient.Object) bool {
+	if obj == nil {
+		return true
+	}
+	value := reflect.ValueOf(obj)
+	return value.Kind() == reflect.Pointer && value.IsNil()
+}
+
+// resourceNames renders resources in the full format, for structured loggers.
+func resourceNames(resources []ctrlclient.Object) []string {
+	names := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		names = append(names, FormatResource(resource, FullResource))
+	}
+	return names
+}
@@ -0,0 +1,211 @@
+package logging
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestParseResourceFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		want    ResourceFormat
+		wantErr bool
+	}{{
+		name: "empty",
+		want: FullResource,
+	}, {
+		name:   "short",
+		format: "short",
+		want:   ShortResource,
+	}, {
+		name:   "case insensitive",
+		format: "Namespaced",
+		want:   NamespacedResource,
+	}, {
+		name:    "unsupported",
+		format:  "long",
+		want:    FullResource,
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseResourceFormat(tt.format)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFormatResource(t *testing.T) {
+	resource := func(apiVersion, kind, namespace, name string) *unstructured.Unstructured {
+		var resource unstructured.Unstructured
+		resource.SetAPIVersion(apiVersion)
+		resource.SetKind(kind)
+		resource.SetNamespace(namespace)
+		resource.SetName(name)
+		return &resource
+	}
+	deployment := resource("apps/v1", "Deployment", "default", "nginx")
+	configMap := resource("v1", "ConfigMap", "default", "settings")
+	clusterRole := resource("rbac.authorization.k8s.io/v1", "ClusterRole", "", "admin")
+	noVersion := resource("", "Namespace", "", "chainsaw")
+	tests := []struct {
+		name     string
+		resource *unstructured.Unstructured
+		format   ResourceFormat
+		want     string
+	}{{
+		name:     "default",
+		resource: deployment,
+		want:     "apps/v1/Deployment @ default/nginx",
+	}, {
+		name:     "full",
+		resource: deployment,
+		format:   FullResource,
+		want:     "apps/v1/Deployment @ default/nginx",
+	}, {
+		name:     "full core",
+		resource: configMap,
+		format:   FullResource,
+		want:     "v1/ConfigMap @ default/settings",
+	}, {
+		name:     "full cluster scoped",
+		resource: clusterRole,
+		format:   FullResource,
+		want:     "rbac.authorization.k8s.io/v1/ClusterRole @ admin",
+	}, {
+		name:     "full without version",
+		resource: noVersion,
+		format:   FullResource,
+		want:     "Namespace @ chainsaw",
+	}, {
+		name:     "namespaced",
+		resource: deployment,
+		format:   NamespacedResource,
+		want:     "Deployment/default/nginx",
+	}, {
+		name:     "namespaced cluster scoped",
+		resource: clusterRole,
+		format:   NamespacedResource,
+		want:     "ClusterRole/admin",
+	}, {
+		name:     "short",
+		resource: deployment,
+		format:   ShortResource,
+		want:     "Deployment/nginx",
+	}, {
+		name:     "short cluster scoped",
+		resource: clusterRole,
+		format:   ShortResource,
+		want:     "ClusterRole/admin",
+	}, {
+		name:     "short without name",
+		resource: resource("v1", "Pod", "default", ""),
+		format:   ShortResource,
+		want:     "Pod/*",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, FormatResource(tt.resource, tt.format))
+		})
+	}
+}
+
+func TestFormatText_ResourceFormat(t *testing.T) {
+	var resource unstructured.Unstructured
+	resource.SetAPIVersion("v1")
+	resource.SetKind("ConfigMap")
+	resource.SetNamespace("default")
+	resource.SetName("settings")
+	entry := Entry{
+		Test:            "test",
+		Step:            "step",
+		Operation:       Apply,
+		Status:          OkStatus,
+		Resource:        &resource,
+		TimestampLayout: NoTimestamp,
+		ResourceFormat:  ShortResource,
+	}
+	assert.Equal(t, "| test | step | APPLY     | OK    | ConfigMap/settings", FormatText(entry, false))
+}
+
+func TestParseResourceListFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		want    ResourceListFormat
+		wantErr bool
+	}{{
+		name: "empty",
+		want: CountResources,
+	}, {
+		name:   "case insensitive",
+		format: "List",
+		want:   ListResources,
+	}, {
+		name:    "unsupported",
+		format:  "table",
+		want:    CountResources,
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseResourceListFormat(tt.format)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFormatText_ResourceList(t *testing.T) {
+	var deployment, service unstructured.Unstructured
+	deployment.SetAPIVersion("apps/v1")
+	deployment.SetKind("Deployment")
+	deployment.SetNamespace("default")
+	deployment.SetName("nginx")
+	service.SetAPIVersion("v1")
+	service.SetKind("Service")
+	service.SetNamespace("default")
+	service.SetName("nginx")
+	tests := []struct {
+		name   string
+		format ResourceFormat
+		list   ResourceListFormat
+		want   string
+	}{{
+		name: "default",
+		want: "| test | step | DELETE    | DONE  | 2 objects",
+	}, {
+		name:   "list",
+		format: ShortResource,
+		list:   ListResources,
+		want:   "| test | step | DELETE    | DONE  | Deployment/nginx, Service/nginx",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := Entry{
+				Test:            "test",
+				Step:            "step",
+				Operation:       Delete,
+				Status:          DoneStatus,
+				Resources:       []ctrlclient.Object{&deployment, &service},
+				TimestampLayout: NoTimestamp,
+				ResourceFormat:  tt.format,
+				ResourceList:    tt.list,
+			}
+			assert.Equal(t, tt.want, FormatText(entry, false))
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package logging
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// Rotation rotates log files once they reach a size, for suites running long enough to fill the disk otherwise.
+type Rotation struct {
+	// MaxSize is the size in bytes a file is rotated at, zero or less disables rotation.
+	MaxSize int64
+	// MaxBackups is the number of rotated files kept, the oldest are removed, zero or less keeps them all.
+	MaxBackups int
+	// Compress compresses rotated files with gzip, they are then named after the file followed by .N.gz.
+	Compress bool
+}
+
+// RotatingFile is a file rotated once it reaches the maximum size of its rotation, it is safe for concurrent use.
+// Rotated files are named after the file followed by .1 for the most recent, .2 and so on, the active file is always at
+// its path: it is given the name of the first backup with a hard link, then a new file replaces it with an atomic rename.
+// A write never spans two files, a write larger than the maximum size goes to a file of its own.
+type RotatingFile struct {
+	lock     sync.Mutex
+	path     string
+	rotation Rotation
+	file     *os.File
+	size     int64
+}
+
+// CreateRotatingFile creates or truncates the file at path, rotated with rotation.
+func CreateRotatingFile(path string, rotation Rotation) (*RotatingFile, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &RotatingFile{path: path, rotation: rotation, file: file}, nil
+}
+
+// OpenRotatingFile opens the file at path for appending, creating it if needed, rotated with rotation.
+func OpenRotatingFile(path string, rotation Rotation) (*RotatingFile, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o666)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return &RotatingFile{path: path, rotation: rotation, file: file, size: info.Size()}, nil
+}
+
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	var rerr error
+	if f.rotation.MaxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.rotation.MaxSize {
+		// a failed rotation loses no line, they keep being written to the active file
+		if err := f.rotate(); err != nil {
+			rerr = fmt.Errorf("failed to rotate log file: %w", err)
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	if err == nil {
+		err = rerr
+	}
+	return n, err
+}
+
+// Sync commits the active file to disk.
+func (f *RotatingFile) Sync() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	return f.file.Sync()
+}
+
+// Close closes the active file, writes fail afterwards.
+func (f *RotatingFile) Close() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+// rotate moves the active file to the first backup, shifting the others, and replaces it with an empty file.
+func (f *RotatingFile) rotate() error {
+	if err := f.shift(); err != nil {
+		return err
+	}
+	backup := f.backup(1, false)
+	linked := true
+	if err := os.Link(f.path, backup); err != nil {
+		// hard links are not supported by every file system, the path is then missing until the new file is renamed
+		if err := os.Rename(f.path, backup); err != nil {
+			return err
+		}
+		linked = false
+	}
+	tmp := f.path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o666)
+	if err == nil {
+		if err = os.Rename(tmp, f.path); err != nil {
+			_ = file.Close()
+		}
+	}
+	if err != nil {
+		// the active file keeps being written, it must not be a backup too
+		if linked {
+			_ = os.Remove(backup)
+		} else {
+			_ = os.Rename(backup, f.path)
+		}
+		return err
+	}
+	// the previous file is still open, lines written before the rotation are committed to the backup
+	err = f.file.Sync()
+	if cerr := f.file.Close(); err == nil {
+		err = cerr
+	}
+	f.file, f.size = file, 0
+	if err != nil {
+		return err
+	}
+	if f.rotation.Compress {
+		return compressFile(backup, f.backup(1, true))
+	}
+	return nil
+}
+
+// shift renames the backups to make room for a new first one, backups beyond the maximum are removed.
+func (f *RotatingFile) shift() error {
+	last := 0
+	for f.exists(last + 1) {
+		last++
+	}
+	for i := last; i > 0; i-- {
+		compressed := fileExists(f.backup(i, true))
+		if f.rotation.MaxBackups > 0 && i >= f.rotation.MaxBackups {
+			if err := os.Remove(f.backup(i, compressed)); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := os.Rename(f.backup(i, compressed), f.backup(i+1, compressed)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backup returns the path of the nth backup.
+func (f *RotatingFile) backup(n int, compressed bool) string {
+	path := f.path + "." + strconv.Itoa(n)
+	if compressed {
+		path += ".gz"
+	}
+	return path
+}
+
+func (f *RotatingFile) exists(n int) bool {
+	return fileExists(f.backup(n, false)) || fileExists(f.backup(n, true))
+}
+
+func fileExists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+// compressFile compresses the file at src to dst and removes src, dst appears once complete.
+func compressFile(src string, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	tmp := dst + ".tmp"
+	out, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o666)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(out)
+	_, err = io.Copy(zw, in)
+	if cerr := zw.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, dst)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Remove(src)
+}
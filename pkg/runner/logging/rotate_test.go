@@ -0,0 +1,209 @@
+package logging
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rotatedLines returns the lines of the file at path and of its backups, oldest first, and the names of the files.
+func rotatedLines(t *testing.T, path string) ([]string, []string) {
+	t.Helper()
+	entries, err := os.ReadDir(filepath.Dir(path))
+	assert.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	var lines []string
+	for n := len(names); n >= 0; n-- {
+		name := path
+		if n > 0 {
+			name = fmt.Sprintf("%s.%d", path, n)
+		}
+		var data []byte
+		if file, err := os.Open(name + ".gz"); err == nil {
+			zr, err := gzip.NewReader(file)
+			assert.NoError(t, err)
+			data, err = io.ReadAll(zr)
+			assert.NoError(t, err)
+			assert.NoError(t, file.Close())
+		} else if data, err = os.ReadFile(name); err != nil {
+			continue
+		}
+		lines = append(lines, strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")...)
+	}
+	return lines, names
+}
+
+func numberedLines(count int) []string {
+	var lines []string
+	for i := 0; i < count; i++ {
+		lines = append(lines, fmt.Sprintf("line %03d", i))
+	}
+	return lines
+}
+
+func TestRotatingFile(t *testing.T) {
+	tests := []struct {
+		name      string
+		rotation  Rotation
+		lines     int
+		wantFiles []string
+		wantLines int
+	}{{
+		name:      "no rotation",
+		lines:     100,
+		wantFiles: []string{"chainsaw.log"},
+		wantLines: 100,
+	}, {
+		name:      "below the limit",
+		rotation:  Rotation{MaxSize: 90},
+		lines:     10,
+		wantFiles: []string{"chainsaw.log"},
+		wantLines: 10,
+	}, {
+		// lines are 9 bytes, ten lines fit in a file
+		name:      "all backups",
+		rotation:  Rotation{MaxSize: 90},
+		lines:     35,
+		wantFiles: []string{"chainsaw.log", "chainsaw.log.1", "chainsaw.log.2", "chainsaw.log.3"},
+		wantLines: 35,
+	}, {
+		name:      "max backups",
+		rotation:  Rotation{MaxSize: 90, MaxBackups: 2},
+		lines:     35,
+		wantFiles: []string{"chainsaw.log", "chainsaw.log.1", "chainsaw.log.2"},
+		wantLines: 25,
+	}, {
+		name:      "compressed",
+		rotation:  Rotation{MaxSize: 90, MaxBackups: 2, Compress: true},
+		lines:     35,
+		wantFiles: []string{"chainsaw.log", "chainsaw.log.1.gz", "chainsaw.log.2.gz"},
+		wantLines: 25,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "chainsaw.log")
+			file, err := CreateRotatingFile(path, tt.rotation)
+			assert.NoError(t, err)
+			all := numberedLines(tt.lines)
+			for _, line := range all {
+				n, err := io.WriteString(file, line+"\n")
+				assert.NoError(t, err)
+				assert.Equal(t, len(line)+1, n)
+				// the active file is always at its path
+				assert.FileExists(t, path)
+			}
+			assert.NoError(t, file.Close())
+			lines, names := rotatedLines(t, path)
+			assert.Equal(t, tt.wantFiles, names)
+			// lines follow each other across files, the oldest are those of the removed backups
+			assert.Equal(t, all[len(all)-tt.wantLines:], lines)
+		})
+	}
+}
+
+func TestRotatingFile_LargeWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chainsaw.log")
+	file, err := CreateRotatingFile(path, Rotation{MaxSize: 10})
+	assert.NoError(t, err)
+	for _, line := range []string{"short\n", strings.Repeat("x", 20) + "\n", "short\n"} {
+		_, err := io.WriteString(file, line)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, file.Close())
+	// writes are never split, a write larger than the limit takes a file of its own
+	for name, want := range map[string]string{
+		path:        "short\n",
+		path + ".1": strings.Repeat("x", 20) + "\n",
+		path + ".2": "short\n",
+	} {
+		data, err := os.ReadFile(name)
+		assert.NoError(t, err)
+		assert.Equal(t, want, string(data))
+	}
+	_, err = io.WriteString(file, "closed\n")
+	assert.ErrorIs(t, err, os.ErrClosed)
+}
+
+func TestOpenRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chainsaw.log")
+	assert.NoError(t, os.WriteFile(path, []byte("existing\n"), 0o666))
+	file, err := OpenRotatingFile(path, Rotation{MaxSize: 15})
+	assert.NoError(t, err)
+	// the size of the existing content counts towards the limit
+	for _, line := range []string{"first\n", "second\n"} {
+		_, err := io.WriteString(file, line)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, file.Close())
+	for name, want := range map[string]string{
+		path:        "second\n",
+		path + ".1": "existing\nfirst\n",
+	} {
+		data, err := os.ReadFile(name)
+		assert.NoError(t, err)
+		assert.Equal(t, want, string(data))
+	}
+}
+
+func TestRotatingFile_Concurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chainsaw.log")
+	file, err := CreateRotatingFile(path, Rotation{MaxSize: 1000})
+	assert.NoError(t, err)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				_, err := fmt.Fprintf(file, "writer %d line %03d\n", i, j)
+				assert.NoError(t, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.NoError(t, file.Close())
+	lines, names := rotatedLines(t, path)
+	assert.Greater(t, len(names), 1)
+	assert.Len(t, lines, 1600)
+	// no line is lost nor torn, and the lines of each writer keep their order across files
+	next := map[string]int{}
+	for _, line := range lines {
+		var writer, number int
+		_, err := fmt.Sscanf(line, "writer %d line %d", &writer, &number)
+		assert.NoError(t, err, line)
+		key := fmt.Sprint(writer)
+		assert.Equal(t, next[key], number)
+		next[key]++
+	}
+	for _, name := range names {
+		info, err := os.Stat(filepath.Join(filepath.Dir(path), name))
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, info.Size(), int64(1000))
+	}
+}
+
+func TestTee_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	tee, err := NewPerTestTee(dir, WithRotation(Rotation{MaxSize: 100, MaxBackups: 1}))
+	assert.NoError(t, err)
+	for _, line := range numberedLines(20) {
+		assert.NoError(t, tee.Write(TestKey{Name: "quick start"}, line))
+	}
+	assert.NoError(t, tee.Write(TestKey{Name: "other"}, "line"))
+	assert.NoError(t, tee.Flush())
+	assert.NoError(t, tee.Close())
+	lines, names := rotatedLines(t, filepath.Join(dir, TestFileName("quick start")))
+	assert.Equal(t, []string{"other.log", "quick_start.log", "quick_start.log.1"}, names)
+	// lines are 9 bytes, eleven fit in a file, none was removed yet
+	assert.Equal(t, numberedLines(20), lines)
+}
@@ -0,0 +1,518 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+	"github.com/kyverno/chainsaw/pkg/report"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Entry is a log line written to sinks.
+type Entry struct {
+	Time  time.Time
+	Level Level
+	Test  string
+	// TestPath is the folder of the test, if known, see WithTestPath.
+	TestPath  string
+	Step      string
+	Operation Operation
+	Status    Status
+	Resource  ctrlclient.Object
+	// Resources are the resources of lines about several resources, Resource is then nil.
+	Resources []ctrlclient.Object
+	// OperationName and OperationType identify the operation the line is logged for, they are empty outside operations.
+	OperationName string
+	OperationType report.OperationType
+	// TestID and OperationID are the correlation identifiers of the test and the operation in the report, if any.
+	TestID      string
+	OperationID string
+	// Ordinals are the positions of the step and the operation in the test spec, they number them in human readable output.
+	Ordinals Ordinals
+	// Worker is the worker slot the test runs in, numbered from 1, it is zero unless worker slots are printed.
+	Worker int
+	// Namespace is the namespace of the test, if known, NamespaceColumn prints it in a column of human readable output.
+	Namespace       string
+	NamespaceColumn bool
+	// Style is the color, or marker when colors are disabled, of human readable output.
+	Style Style
+	// Deterministic renders the elapsed durations of human readable output as DurationPlaceholder.
+	Deterministic bool
+	// Glyphs mark the outcome of the style in human readable output, no outcome is marked with the zero value.
+	Glyphs Glyphs
+	// Theme colors the prefix and the resource of human readable output when colors are enabled, if not nil.
+	Theme *Theme
+	// Message holds the arguments of the log call, one per line.
+	Message string
+	// FullMessage is the message before it was truncated, it is empty unless Message was truncated.
+	FullMessage string
+	// TestStart and OperationStart are the start times elapsed durations are printed against, they are zero if not printed.
+	TestStart      time.Time
+	OperationStart time.Time
+	// TimestampLayout renders Time in human readable output, it defaults to DefaultTimestampLayout.
+	TimestampLayout TimestampLayout
+	// ResourceFormat renders Resource in human readable output, it defaults to FullResource.
+	ResourceFormat ResourceFormat
+	// ResourceList renders Resources in human readable output, it defaults to CountResources.
+	ResourceList ResourceListFormat
+	// Columns are the widths of the name columns of human readable output, zero widths leave names as is.
+	Columns ColumnWidths
+	// Continuation renders the lines of multi-line messages in human readable output, it defaults to NoContinuation.
+	Continuation ContinuationFormat
+	// Fields are the structured fields of the line, sorted by key, they are printed as key=value pairs after the resource.
+	Fields Fields
+	// ResourceLinks makes Resource a hyperlink in colored human readable output, if not nil, see WithResourceLinks.
+	ResourceLinks *ResourceLinks
+	// Caller is the location of the code logging the line, like processors/step.go:42, if recorded, see WithCaller.
+	Caller string
+	// text caches the parts of human readable output shared by the lines of a logger, it is nil for entries built otherwise.
+	text *textCache
+}
+
+// callerColor dims the caller of human readable lines, it is secondary to the line itself.
+var callerColor = color.New(color.Faint)
+
+// Sink receives the log lines of loggers.
+type Sink interface {
+	WriteEntry(Entry) error
+}
+
+// Flusher is implemented by sinks holding lines, Flush writes them through to their destination.
+// Sinks are flushed when the test of a logger completes, see Flush.
+type Flusher interface {
+	Flush() error
+}
+
+// flushWriter flushes w if it holds lines, and commits regular files to disk, terminals and pipes can't be synced.
+func flushWriter(w io.Writer) error {
+	switch w := w.(type) {
+	case Flusher:
+		return w.Flush()
+	case *os.File:
+		if info, err := w.Stat(); err == nil && info.Mode().IsRegular() {
+			return w.Sync()
+		}
+	}
+	return nil
+}
+
+// MultiSink fans entries out to several sinks, in order.
+// A failing sink doesn't prevent the others from receiving the entry, errors are joined.
+type MultiSink []Sink
+
+func NewMultiSink(sinks ...Sink) MultiSink {
+	return MultiSink(sinks)
+}
+
+func (m MultiSink) WriteEntry(entry Entry) error {
+	var errs []error
+	for _, sink := range m {
+		if err := writeEntry(sink, entry); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Flush flushes the sinks implementing Flusher, errors are joined.
+func (m MultiSink) Flush() error {
+	var errs []error
+	for _, sink := range m {
+		if flusher, ok := sink.(Flusher); ok {
+			if err := flusher.Flush(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close flushes the sinks and closes those implementing io.Closer, errors are joined.
+func (m MultiSink) Close() error {
+	errs := []error{m.Flush()}
+	for _, sink := range m {
+		if closer, ok := sink.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// writeEntry writes entry to sink, a panicking sink is reported as an error.
+func writeEntry(sink Sink, entry Entry) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("log sink panicked: %v", r)
+		}
+	}()
+	return sink.WriteEntry(entry)
+}
+
+// FormatText renders entry in the human readable format.
+// Colors are applied to the prefix when enabled, otherwise the marker of the style prefixes the line.
+func FormatText(entry Entry, colors bool) string {
+	buf := getTextBuffer()
+	defer putTextBuffer(buf)
+	appendText(buf, entry, colors)
+	return buf.String()
+}
+
+// appendText appends entry in the human readable format to buf, see FormatText.
+// Lines are assembled in place, the parts that don't change between the lines of a logger come from its cache.
+func appendText(buf *bytes.Buffer, entry Entry, colors bool) {
+	start := buf.Len()
+	var sprint, prefix, resource func(...any) string
+	if colors {
+		sprint = colorSprint(entry.Style.Color)
+		prefix = sprint
+		if entry.Theme != nil {
+			if entry.Theme.Prefix != nil {
+				prefix = colorSprint(entry.Theme.Prefix)
+			}
+			resource = colorSprint(entry.Theme.Resource)
+		}
+	}
+	if glyph := entry.Glyphs.Glyph(entry.Style.Outcome); glyph != "" {
+		writeColored(buf, glyph, sprint)
+		buf.WriteByte(' ')
+	} else if !colors && entry.Style.Marker != "" {
+		// without colors a marker keeps the severity scannable
+		writePadded(buf, entry.Style.Marker, markerWidth)
+		buf.WriteByte(' ')
+	}
+	buf.WriteByte('|')
+	appendWorker(buf, entry)
+	appendTime(buf, entry)
+	appendNamespace(buf, entry)
+	if prefix == nil {
+		buf.WriteString(entry.text.namesText(entry))
+	} else {
+		buf.WriteString(formatNames(entry, prefix))
+	}
+	buf.WriteByte(' ')
+	if sprint == nil {
+		writePadded(buf, string(entry.Operation), 9)
+		buf.WriteString(" | ")
+		writePadded(buf, string(entry.Status), 5)
+	} else {
+		buf.WriteString(sprint(padded(string(entry.Operation), 9)))
+		buf.WriteString(" | ")
+		buf.WriteString(sprint(padded(string(entry.Status), 5)))
+	}
+	buf.WriteString(" |")
+	end := buf.Len()
+	if entry.Resource != nil {
+		buf.WriteByte(' ')
+		text := entry.text.resourceText(entry)
+		if resource != nil {
+			text = resource(text)
+		}
+		// hyperlinks are escape sequences like colors, lines without colors never hold them
+		if colors {
+			text = resourceLink(entry, text)
+		}
+		buf.WriteString(text)
+	} else if len(entry.Resources) != 0 {
+		buf.WriteByte(' ')
+		writeColored(buf, FormatResources(entry.Resources, entry.ResourceFormat, entry.ResourceList), resource)
+	}
+	if len(entry.Fields) != 0 {
+		buf.WriteByte(' ')
+		buf.WriteString(formatFields(entry.Fields))
+	}
+	if entry.Caller != "" {
+		buf.WriteByte(' ')
+		var dim func(...any) string
+		if colors {
+			dim = colorSprint(callerColor)
+		}
+		writeColored(buf, "("+entry.Caller+")", dim)
+	}
+	if entry.Message != "" {
+		buf.WriteByte('\n')
+		switch entry.Continuation {
+		case PrefixContinuation, IndentContinuation:
+			prefix := string(buf.Bytes()[start:end])
+			buf.WriteString(formatContinuation(entry.Message, prefix, entry.Continuation))
+		default:
+			buf.WriteString(entry.Message)
+		}
+	}
+}
+
+// formatNames renders the test, step and operation name columns of entry with sprint, uncolored if sprint is nil.
+// Columns are padded before being colored, escape sequences don't change the alignment.
+func formatNames(entry Entry, sprint func(...any) string) string {
+	test, step := entry.Test, entry.Step
+	if entry.Columns.Test > 0 {
+		test = FitColumn(test, entry.Columns.Test)
+	}
+	if ordinals := entry.Ordinals; ordinals.Step > 0 {
+		// steps are already padded by the test processor, with room for the ordinal
+		label := StepLabel(strings.TrimRight(step, " "), ordinals.Step, ordinals.Steps, ordinals.Always)
+		step = padded(label, utf8.RuneCountInString(step))
+	}
+	if entry.Columns.Step > 0 {
+		step = FitColumn(strings.TrimRight(step, " "), entry.Columns.Step)
+	}
+	if sprint == nil {
+		sprint = fmt.Sprint
+	}
+	return " " + sprint(test) + " | " + sprint(step) + " |" + formatOperationName(entry, sprint)
+}
+
+// formatOperationName renders the name of the operation of entry in a column.
+// Without a column width, the column is omitted outside operations.
+func formatOperationName(entry Entry, sprint func(...any) string) string {
+	name := OperationLabel(entry.OperationName, entry.Ordinals.Phase, entry.Ordinals.Operation, entry.Ordinals.Always)
+	if entry.Columns.Operation > 0 {
+		return " " + sprint(FitColumn(name, entry.Columns.Operation)) + " |"
+	}
+	if name == "" {
+		return ""
+	}
+	return " " + sprint(name) + " |"
+}
+
+// writeColored writes s to buf with sprint, as is if sprint is nil.
+func writeColored(buf *bytes.Buffer, s string, sprint func(...any) string) {
+	if sprint == nil {
+		buf.WriteString(s)
+	} else {
+		buf.WriteString(sprint(s))
+	}
+}
+
+// colorSprint returns the Sprint of a copy of c with colors enabled, leaving the shared color untouched, nil if c is nil.
+func colorSprint(c *color.Color) func(...any) string {
+	if c == nil {
+		return nil
+	}
+	enabled := *c
+	enabled.EnableColor()
+	return enabled.Sprint
+}
+
+// writePadded writes s to buf padded with spaces to w runes, like the %-*s verb.
+func writePadded(buf *bytes.Buffer, s string, w int) {
+	buf.WriteString(s)
+	for n := utf8.RuneCountInString(s); n < w; n++ {
+		buf.WriteByte(' ')
+	}
+}
+
+// padded returns s padded with spaces to w runes, like the %-*s verb.
+func padded(s string, w int) string {
+	if n := utf8.RuneCountInString(s); n < w {
+		return s + strings.Repeat(" ", w-n)
+	}
+	return s
+}
+
+// appendWorker appends the worker slot of entry in a fixed width column, the column is omitted when it is zero.
+func appendWorker(buf *bytes.Buffer, entry Entry) {
+	if entry.Worker <= 0 {
+		return
+	}
+	buf.WriteString(" w")
+	if entry.Worker < 10 {
+		buf.WriteByte('0')
+	}
+	buf.Write(strconv.AppendInt(buf.AvailableBuffer(), int64(entry.Worker), 10))
+	buf.WriteString(" |")
+}
+
+// appendNamespace appends the namespace of entry in brackets in a column, the column is omitted unless it is printed
+// and known.
+func appendNamespace(buf *bytes.Buffer, entry Entry) {
+	if !entry.NamespaceColumn || entry.Namespace == "" {
+		return
+	}
+	buf.WriteString(" [")
+	buf.WriteString(entry.Namespace)
+	buf.WriteString("] |")
+}
+
+// appendTime appends the timestamp and elapsed durations of entry in a column, the column is omitted when both are empty.
+func appendTime(buf *bytes.Buffer, entry Entry) {
+	start := buf.Len()
+	buf.WriteByte(' ')
+	switch entry.TimestampLayout {
+	case NoTimestamp:
+	case "":
+		buf.Write(entry.Time.AppendFormat(buf.AvailableBuffer(), string(DefaultTimestampLayout)))
+	default:
+		buf.Write(entry.Time.AppendFormat(buf.AvailableBuffer(), string(entry.TimestampLayout)))
+	}
+	appendElapsed(buf, entry)
+	// layouts may start or end with spaces, they are trimmed like an empty timestamp before the durations
+	column := bytes.TrimSpace(buf.Bytes()[start+1:])
+	if len(column) == 0 {
+		buf.Truncate(start)
+		return
+	}
+	buf.Truncate(start + 1 + copy(buf.Bytes()[start+1:], column))
+	buf.WriteString(" |")
+}
+
+// appendElapsed appends the durations elapsed since the start of the test and operation, if any.
+func appendElapsed(buf *bytes.Buffer, entry Entry) {
+	first := true
+	for _, start := range [...]time.Time{entry.TestStart, entry.OperationStart} {
+		if start.IsZero() {
+			continue
+		}
+		if first {
+			buf.WriteString(" [")
+			first = false
+		} else {
+			buf.WriteByte(' ')
+		}
+		if entry.Deterministic {
+			buf.WriteString(DurationPlaceholder)
+		} else {
+			buf.WriteString(FormatElapsed(entry.Time.Sub(start)))
+		}
+	}
+	if !first {
+		buf.WriteByte(']')
+	}
+}
+
+// FormatElapsed renders a duration in a compact form, seven characters wide up to a hundred hours:
+// +0.350s below ten seconds, +01m23s below an hour and +01h23m above.
+func FormatElapsed(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	switch {
+	case d < 10*time.Second:
+		return fmt.Sprintf("+%d.%03ds", d/time.Second, (d%time.Second)/time.Millisecond)
+	case d < time.Hour:
+		return fmt.Sprintf("+%02dm%02ds", d/time.Minute, (d%time.Minute)/time.Second)
+	default:
+		return fmt.Sprintf("+%02dh%02dm", d/time.Hour, (d%time.Hour)/time.Minute)
+	}
+}
+
+// logToAdapter logs entry with logger, one of the logr or slog adapters.
+func logToAdapter(logger Logger, entry Entry) {
+	if entry.OperationName != "" {
+		logger = logger.WithOperation(entry.OperationName, entry.OperationType)
+	}
+	if entry.Resource != nil {
+		logger = logger.WithResource(entry.Resource)
+	} else if len(entry.Resources) != 0 {
+		logger = logger.WithResources(entry.Resources...)
+	}
+	if len(entry.Fields) != 0 {
+		logger = logger.WithFields(entry.Fields.Map())
+	}
+	var args []fmt.Stringer
+	if entry.Message != "" {
+		args = append(args, message(entry.Message))
+	}
+	logger.LogLevel(entry.Level, entry.Operation, entry.Status, nil, args...)
+}
+
+// writerSink writes entries in the human readable format to a writer.
+type writerSink struct {
+	w      io.Writer
+	colors bool
+}
+
+// NewWriterSink returns a Sink writing entries in the human readable format to w, each entry ends with a single newline.
+// An entry is written with a single call, wrap w with NewLineWriter when other goroutines write partial lines to it.
+func NewWriterSink(w io.Writer, colors bool) Sink {
+	return writerSink{w: w, colors: colors}
+}
+
+func (s writerSink) WriteEntry(entry Entry) error {
+	buf := getTextBuffer()
+	defer putTextBuffer(buf)
+	appendText(buf, entry, s.colors)
+	if line := buf.Bytes(); len(line) == 0 || line[len(line)-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+	_, err := s.w.Write(buf.Bytes())
+	return err
+}
+
+// Flush flushes the writer, like a LineWriter holding a partial line, files are committed to disk.
+func (s writerSink) Flush() error {
+	return flushWriter(s.w)
+}
+
+// LineWriter serializes the writes of concurrent goroutines line by line, it is safe for concurrent use.
+// The lines completed by a write are written with a single call, a trailing partial line is held until its end is written
+// or the writer is flushed.
+type LineWriter struct {
+	lock    sync.Mutex
+	w       io.Writer
+	partial []byte
+}
+
+// NewLineWriter returns a LineWriter writing to w.
+func NewLineWriter(w io.Writer) *LineWriter {
+	return &LineWriter{w: w}
+}
+
+func (w *LineWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	end := bytes.LastIndexByte(p, '\n')
+	if end < 0 {
+		w.partial = append(w.partial, p...)
+		return len(p), nil
+	}
+	lines := append(w.partial, p[:end+1]...)
+	w.partial = append([]byte(nil), p[end+1:]...)
+	if _, err := w.w.Write(lines); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush writes the partial line held, if any.
+func (w *LineWriter) Flush() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if len(w.partial) == 0 {
+		return nil
+	}
+	_, err := w.w.Write(w.partial)
+	w.partial = nil
+	return err
+}
+
+// textSink writes entries in the human readable format to a TLogger.
+type textSink struct {
+	t      TLogger
+	colors bool
+}
+
+// NewTextSink returns a Sink writing entries in the human readable format to t.
+func NewTextSink(t TLogger, colors bool) Sink {
+	return textSink{t: t, colors: colors}
+}
+
+func (s textSink) WriteEntry(entry Entry) error {
+	// the eraser hides the file and line go test prints in front of logs
+	buf := getTextBuffer()
+	defer putTextBuffer(buf)
+	buf.WriteString(eraser)
+	appendText(buf, entry, s.colors)
+	s.t.Log(buf.String())
+	return nil
+}
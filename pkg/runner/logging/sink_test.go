@@ -0,0 +1,355 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+// recordSink records the entries it receives in a shared journal.
+type recordSink struct {
+	name    string
+	journal *[]string
+	err     error
+	panics  bool
+}
+
+func (s recordSink) WriteEntry(entry Entry) error {
+	if s.panics {
+		panic("boom")
+	}
+	*s.journal = append(*s.journal, s.name+":"+entry.Message)
+	return s.err
+}
+
+// sinkFunc adapts a function to the Sink interface.
+type sinkFunc func(Entry) error
+
+func (f sinkFunc) WriteEntry(entry Entry) error {
+	return f(entry)
+}
+
+func TestLineWriter(t *testing.T) {
+	var writes []string
+	w := NewLineWriter(writerFunc(func(p []byte) (int, error) {
+		writes = append(writes, string(p))
+		return len(p), nil
+	}))
+	for _, p := range []string{"par", "tial\nnext", " line\nlast\n", "trailing"} {
+		n, err := w.Write([]byte(p))
+		assert.NoError(t, err)
+		assert.Equal(t, len(p), n)
+	}
+	// partial lines are held until their end is written
+	assert.Equal(t, []string{"partial\n", "next line\nlast\n"}, writes)
+	assert.NoError(t, w.Flush())
+	assert.NoError(t, w.Flush())
+	assert.Equal(t, []string{"partial\n", "next line\nlast\n", "trailing"}, writes)
+}
+
+func TestLineWriter_Concurrent(t *testing.T) {
+	var lock sync.Mutex
+	var out []byte
+	// the underlying writer writes byte by byte, lines of concurrent writes would interleave without the line writer
+	w := NewLineWriter(writerFunc(func(p []byte) (int, error) {
+		for _, b := range p {
+			lock.Lock()
+			out = append(out, b)
+			lock.Unlock()
+			runtime.Gosched()
+		}
+		return len(p), nil
+	}))
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				_, _ = fmt.Fprintf(w, "goroutine %d line %d\n", i, j)
+			}
+		}(i)
+	}
+	wg.Wait()
+	lines := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+	assert.Len(t, lines, 100)
+	for _, line := range lines {
+		assert.Regexp(t, `^goroutine \d line \d$`, line)
+	}
+}
+
+// writerFunc adapts a function to the io.Writer interface.
+type writerFunc func([]byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
+
+func TestMultiSink(t *testing.T) {
+	tests := []struct {
+		name        string
+		sinks       func(*[]string) []Sink
+		wantJournal []string
+		wantErr     []string
+	}{{
+		name: "empty",
+		sinks: func(*[]string) []Sink {
+			return nil
+		},
+	}, {
+		name: "ordering",
+		sinks: func(journal *[]string) []Sink {
+			return []Sink{recordSink{name: "a", journal: journal}, recordSink{name: "b", journal: journal}, recordSink{name: "c", journal: journal}}
+		},
+		wantJournal: []string{"a:message", "b:message", "c:message"},
+	}, {
+		name: "failing sinks",
+		sinks: func(journal *[]string) []Sink {
+			return []Sink{
+				recordSink{name: "a", journal: journal, err: errors.New("disk full")},
+				recordSink{name: "b", journal: journal},
+				recordSink{name: "c", journal: journal, err: errors.New("closed")},
+			}
+		},
+		wantJournal: []string{"a:message", "b:message", "c:message"},
+		wantErr:     []string{"disk full", "closed"},
+	}, {
+		name: "panicking sink",
+		sinks: func(journal *[]string) []Sink {
+			return []Sink{recordSink{name: "a", journal: journal, panics: true}, recordSink{name: "b", journal: journal}}
+		},
+		wantJournal: []string{"b:message"},
+		wantErr:     []string{"log sink panicked: boom"},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var journal []string
+			err := NewMultiSink(tt.sinks(&journal)...).WriteEntry(Entry{Message: "message"})
+			assert.Equal(t, tt.wantJournal, journal)
+			if len(tt.wantErr) == 0 {
+				assert.NoError(t, err)
+			} else {
+				for _, want := range tt.wantErr {
+					assert.ErrorContains(t, err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatText(t *testing.T) {
+	var resource unstructured.Unstructured
+	resource.SetAPIVersion("v1")
+	resource.SetKind("ConfigMap")
+	resource.SetNamespace("default")
+	resource.SetName("settings")
+	entry := Entry{
+		Time:      time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC),
+		Test:      "test",
+		Step:      "step-1  ",
+		Operation: Apply,
+		Status:    ErrorStatus,
+		Style:     GetPalette().Failure,
+	}
+	assert.Equal(t, "FAIL | 10:30:00 | test | step-1   | APPLY     | ERROR |", FormatText(entry, false))
+	entry.Resource = &resource
+	entry.Message = "first\nsecond"
+	entry.Style = Style{}
+	assert.Equal(t, "| 10:30:00 | test | step-1   | APPLY     | ERROR | v1/ConfigMap @ default/settings\nfirst\nsecond", FormatText(entry, false))
+}
+
+func TestNewLogger_Sinks(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Now())
+	var journal []string
+	mockT := &tlogging.FakeTLogger{}
+	l := NewLogger(mockT, fakeClock, "test", "step", WithSink(recordSink{name: "a", journal: &journal, err: errors.New("failed")}), WithSink(recordSink{name: "b", journal: &journal}))
+	l.Log(Apply, OkStatus, nil, s("first"), s("second"))
+	l.WithResource(nil).Log(Apply, OkStatus, nil)
+	// the TLogger still gets the lines when a sink fails
+	assert.Len(t, mockT.Messages, 2)
+	assert.Equal(t, []string{"a:first\nsecond", "b:first\nsecond", "a:", "b:"}, journal)
+	// without text, custom sinks only
+	journal = nil
+	mockT = &tlogging.FakeTLogger{}
+	NewLogger(mockT, fakeClock, "test", "step", WithoutText(), WithSink(recordSink{name: "a", journal: &journal})).Log(Apply, OkStatus, nil)
+	assert.Empty(t, mockT.Messages)
+	assert.Equal(t, []string{"a:"}, journal)
+}
+
+func TestFormatElapsed(t *testing.T) {
+	tests := []struct {
+		name string
+		d    time.Duration
+		want string
+	}{{
+		name: "zero",
+		want: "+0.000s",
+	}, {
+		name: "negative",
+		d:    -time.Second,
+		want: "+0.000s",
+	}, {
+		name: "sub second",
+		d:    350 * time.Millisecond,
+		want: "+0.350s",
+	}, {
+		name: "seconds",
+		d:    9*time.Second + 999*time.Millisecond,
+		want: "+9.999s",
+	}, {
+		name: "minutes",
+		d:    time.Minute + 23*time.Second + 400*time.Millisecond,
+		want: "+01m23s",
+	}, {
+		name: "ten seconds",
+		d:    10 * time.Second,
+		want: "+00m10s",
+	}, {
+		name: "hours",
+		d:    time.Hour + 23*time.Minute + 45*time.Second,
+		want: "+01h23m",
+	}, {
+		name: "days",
+		d:    50 * time.Hour,
+		want: "+50h00m",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FormatElapsed(tt.d)
+			assert.Equal(t, tt.want, got)
+			// fixed width keeps the columns aligned
+			assert.Len(t, got, 7)
+		})
+	}
+}
+
+func TestNewLogger_Elapsed(t *testing.T) {
+	start := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	fakeClock := tclock.NewFakeClock(start)
+	var entries []Entry
+	sink := sinkFunc(func(entry Entry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	mockT := &tlogging.FakeTLogger{}
+	l := NewLogger(mockT, fakeClock, "test", "step", WithoutText(), WithSink(sink), WithElapsed(start))
+	fakeClock.Step(90 * time.Second)
+	l.Log(Apply, OkStatus, nil)
+	op := OperationStarted(l)
+	fakeClock.Step(1500 * time.Millisecond)
+	op.Log(Assert, OkStatus, nil)
+	// the operation start doesn't leak into the original logger
+	l.Log(Apply, OkStatus, nil)
+	assert.Len(t, entries, 3)
+	assert.Equal(t, "| 10:31:30 [+01m30s] | test | step | APPLY     | OK    |", FormatText(entries[0], false))
+	assert.Equal(t, "| 10:31:31 [+01m31s +1.500s] | test | step | ASSERT    | OK    |", FormatText(entries[1], false))
+	assert.Equal(t, "| 10:31:31 [+01m31s] | test | step | APPLY     | OK    |", FormatText(entries[2], false))
+	// without the option, the operation start is ignored
+	entries = nil
+	OperationStarted(NewLogger(mockT, fakeClock, "test", "step", WithoutText(), WithSink(sink))).Log(Apply, OkStatus, nil)
+	assert.Equal(t, "| 10:31:31 | test | step | APPLY     | OK    |", FormatText(entries[0], false))
+}
+
+func TestNewLogger_Worker(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	var buf bytes.Buffer
+	var lines []string
+	sink := sinkFunc(func(entry Entry) error {
+		lines = append(lines, FormatText(entry, false))
+		return nil
+	})
+	mockT := &tlogging.FakeTLogger{}
+	NewLogger(mockT, fakeClock, "test", "step", WithoutText(), WithSink(sink), WithJSON(NewJSONWriter(&buf)), WithWorker(3)).Log(Apply, OkStatus, nil)
+	NewLogger(mockT, fakeClock, "test", "step", WithoutText(), WithSink(sink), WithWorker(12), WithTimestampLayout(NoTimestamp)).Log(Apply, OkStatus, nil)
+	// without the option, the column is absent
+	NewLogger(mockT, fakeClock, "test", "step", WithoutText(), WithSink(sink), WithJSON(NewJSONWriter(&buf))).Log(Apply, OkStatus, nil)
+	assert.Equal(t, []string{
+		"| w03 | 10:30:00 | test | step | APPLY     | OK    |",
+		"| w12 | test | step | APPLY     | OK    |",
+		"| 10:30:00 | test | step | APPLY     | OK    |",
+	}, lines)
+	assert.Empty(t, mockT.Messages)
+	jsonLines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, jsonLines, 2)
+	assert.Contains(t, jsonLines[0], `"worker":3`)
+	assert.NotContains(t, jsonLines[1], `"worker"`)
+}
+
+// lifecycleSink counts the times it is flushed and closed.
+type lifecycleSink struct {
+	flushed *int
+	closed  *int
+}
+
+func (s lifecycleSink) WriteEntry(Entry) error { return nil }
+
+func (s lifecycleSink) Flush() error {
+	*s.flushed++
+	return nil
+}
+
+func (s lifecycleSink) Close() error {
+	*s.closed++
+	return nil
+}
+
+// cleanupT is a TLogger recording the functions registered with Cleanup.
+type cleanupT struct {
+	tlogging.FakeTLogger
+	cleanups []func()
+}
+
+func (t *cleanupT) Cleanup(f func()) {
+	t.cleanups = append(t.cleanups, f)
+}
+
+func TestFlush(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Now())
+	var flushed, closed int
+	sink := lifecycleSink{flushed: &flushed, closed: &closed}
+	mockT := &cleanupT{}
+	l := NewLogger(mockT, fakeClock, "test", "step", WithSink(sink))
+	// sinks are flushed when the test completes
+	assert.Len(t, mockT.cleanups, 1)
+	mockT.cleanups[0]()
+	assert.Equal(t, 1, flushed)
+	assert.NoError(t, Flush(l))
+	assert.Equal(t, 2, flushed)
+	// the repeated line held by a deduper is written before flushing
+	deduper := Dedupe(l, fakeClock)
+	deduper.Log(Apply, OkStatus, nil)
+	deduper.Log(Apply, OkStatus, nil)
+	assert.NoError(t, Flush(deduper))
+	assert.Equal(t, 3, flushed)
+	assert.Contains(t, mockT.Messages[len(mockT.Messages)-1], "repeated 1 time")
+	assert.NoError(t, Close(l))
+	assert.Equal(t, 4, flushed)
+	assert.Equal(t, 1, closed)
+	// other loggers have nothing to flush
+	assert.NoError(t, Flush(NoOp()))
+	assert.NoError(t, Close(NoOp()))
+	// TLoggers without Cleanup are fine
+	NewLogger(&tlogging.FakeTLogger{}, fakeClock, "test", "step", WithSink(sink))
+	assert.Equal(t, 4, flushed)
+}
+
+func TestFlush_LineWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewLineWriter(&buf)
+	l := NewWriterLogger(w, tclock.NewFakePassiveClock(time.Now()), "test", "step", WithTimestampLayout(NoTimestamp))
+	_, err := w.Write([]byte("partial"))
+	assert.NoError(t, err)
+	assert.Empty(t, buf.String())
+	assert.NoError(t, Flush(l))
+	assert.Equal(t, "partial", buf.String())
+}
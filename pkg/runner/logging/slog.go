@@ -0,0 +1,229 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/kyverno/chainsaw/pkg/client"
+	"github.com/kyverno/chainsaw/pkg/report"
+	"github.com/kyverno/kyverno/ext/output/color"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Slog is the operation of the lines logged through a slog.Logger.
+const Slog Operation = "SLOG"
+
+// NewSlogHandler returns a slog.Handler writing to logger.
+// Records logged with a context holding a logger are written to this logger instead, they interleave with the lines of the step.
+// Attributes are printed after the message, the keys of attributes in groups are prefixed with the group names.
+func NewSlogHandler(logger Logger) slog.Handler {
+	return &slogHandler{logger: logger}
+}
+
+type slogHandler struct {
+	logger Logger
+	prefix string
+	attrs  []string
+}
+
+func slogLevel(level slog.Level) Level {
+	switch {
+	case level >= slog.LevelError:
+		return ErrorLevel
+	case level >= slog.LevelWarn:
+		return WarnLevel
+	case level >= slog.LevelInfo:
+		return InfoLevel
+	default:
+		return DebugLevel
+	}
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return Enabled(slogLevel(level))
+}
+
+func (h *slogHandler) Handle(ctx context.Context, record slog.Record) error {
+	logger, ok := fromContext(ctx)
+	if !ok {
+		logger = h.logger
+	}
+	if logger == nil {
+		return nil
+	}
+	parts := append([]string{record.Message}, h.attrs...)
+	record.Attrs(func(attr slog.Attr) bool {
+		parts = appendAttr(parts, h.prefix, attr)
+		return true
+	})
+	msg := message(strings.Join(parts, " "))
+	switch slogLevel(record.Level) {
+	case ErrorLevel:
+		Failure(logger, Slog, ErrorStatus, msg)
+	case WarnLevel:
+		Warn(logger, Slog, WarnStatus, msg)
+	case DebugLevel:
+		Debug(logger, Slog, LogStatus, msg)
+	default:
+		logger.Log(Slog, LogStatus, nil, msg)
+	}
+	return nil
+}
+
+// appendAttr flattens attr into key=value pairs, groups are inlined with their name prefixing the keys.
+func appendAttr(parts []string, prefix string, attr slog.Attr) []string {
+	value := attr.Value.Resolve()
+	if value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, attr := range value.Group() {
+			parts = appendAttr(parts, prefix, attr)
+		}
+		return parts
+	}
+	if attr.Key == "" {
+		return parts
+	}
+	return append(parts, fmt.Sprintf("%s%s=%s", prefix, attr.Key, value))
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	flattened := h.attrs[:len(h.attrs):len(h.attrs)]
+	for _, attr := range attrs {
+		flattened = appendAttr(flattened, h.prefix, attr)
+	}
+	return &slogHandler{logger: h.logger, prefix: h.prefix, attrs: flattened}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &slogHandler{logger: h.logger, prefix: h.prefix + name + ".", attrs: h.attrs}
+}
+
+// FromSlog returns a Logger writing to logger.
+// Operations and statuses are logged as attributes, resources as a group of attributes, colors are ignored.
+func FromSlog(logger *slog.Logger) Logger {
+	return &slogLogger{logger: logger}
+}
+
+// NewSlogEntrySink returns a Sink writing entries to logger like FromSlog, the test and step, and their
+// correlation identifiers, namespace and caller if any, are logged as attributes.
+func NewSlogEntrySink(logger *slog.Logger) Sink {
+	return slogEntrySink{logger: logger}
+}
+
+type slogEntrySink struct {
+	logger *slog.Logger
+}
+
+func (s slogEntrySink) WriteEntry(entry Entry) error {
+	logger := s.logger.With(slog.String("test", entry.Test), slog.String("step", strings.TrimSpace(entry.Step)))
+	if entry.TestID != "" {
+		logger = logger.With(slog.String("testId", entry.TestID))
+	}
+	if entry.OperationID != "" {
+		logger = logger.With(slog.String("operationId", entry.OperationID))
+	}
+	if entry.Namespace != "" {
+		logger = logger.With(slog.String("namespace", entry.Namespace))
+	}
+	if entry.Caller != "" {
+		logger = logger.With(slog.String("caller", entry.Caller))
+	}
+	logToAdapter(FromSlog(logger), entry)
+	return nil
+}
+
+type slogLogger struct {
+	logger        *slog.Logger
+	resource      ctrlclient.Object
+	resources     []ctrlclient.Object
+	operationName string
+	operationType report.OperationType
+	fields        Fields
+}
+
+func (l *slogLogger) Log(operation Operation, status Status, color *color.Color, args ...fmt.Stringer) {
+	l.LogLevel(InfoLevel, operation, status, color, args...)
+}
+
+func (l *slogLogger) LogLevel(level Level, operation Operation, status Status, _ *color.Color, args ...fmt.Stringer) {
+	if !Enabled(level) {
+		return
+	}
+	messages := make([]string, 0, len(args))
+	for _, arg := range args {
+		messages = append(messages, arg.String())
+	}
+	var attrs []slog.Attr
+	if l.operationName != "" {
+		attrs = append(attrs, slog.String("operationName", l.operationName), slog.String("operationType", string(l.operationType)))
+	}
+	attrs = append(attrs, slog.String("operation", string(operation)), slog.String("status", string(status)))
+	if l.resource != nil {
+		gvk := l.resource.GetObjectKind().GroupVersionKind()
+		key := client.ObjectKey(l.resource)
+		resource := []any{slog.String("apiVersion", gvk.GroupVersion().String()), slog.String("kind", gvk.Kind)}
+		if key.Namespace != "" {
+			resource = append(resource, slog.String("namespace", key.Namespace))
+		}
+		resource = append(resource, slog.String("name", key.Name))
+		attrs = append(attrs, slog.Group("resource", resource...))
+	}
+	if len(l.resources) != 0 {
+		attrs = append(attrs, slog.Any("resources", resourceNames(l.resources)))
+	}
+	if len(l.fields) != 0 {
+		fields := make([]any, 0, len(l.fields))
+		for _, field := range l.fields {
+			fields = append(fields, slog.Any(field.Key, structuredFieldValue(field.Value)))
+		}
+		attrs = append(attrs, slog.Group("fields", fields...))
+	}
+	var slevel slog.Level
+	switch level {
+	case ErrorLevel:
+		slevel = slog.LevelError
+	case WarnLevel:
+		slevel = slog.LevelWarn
+	case DebugLevel:
+		slevel = slog.LevelDebug
+	default:
+		slevel = slog.LevelInfo
+	}
+	l.logger.LogAttrs(context.Background(), slevel, jsonMessage(strings.Join(messages, "\n")), attrs...)
+}
+
+func (l *slogLogger) WithResource(resource ctrlclient.Object) Logger {
+	c := *l
+	c.resource, c.resources = resource, nil
+	return &c
+}
+
+func (l *slogLogger) WithResources(resources ...ctrlclient.Object) Logger {
+	c := *l
+	c.resource, c.resources = withResources(resources)
+	return &c
+}
+
+func (l *slogLogger) WithOperation(name string, operationType report.OperationType) Logger {
+	c := *l
+	c.operationName = name
+	c.operationType = operationType
+	return &c
+}
+
+func (l *slogLogger) WithFields(fields map[string]any) Logger {
+	c := *l
+	c.fields = l.fields.withFields(fields)
+	return &c
+}
+
+func (l *slogLogger) WithField(key string, value any) Logger {
+	return l.WithFields(map[string]any{key: value})
+}
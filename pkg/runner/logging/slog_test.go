@@ -0,0 +1,175 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/report"
+	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+func TestNewSlogHandler(t *testing.T) {
+	defer SetLevel(GetLevel())
+	SetLevel(InfoLevel)
+	fakeClock := tclock.NewFakePassiveClock(time.Now())
+	tests := []struct {
+		name  string
+		log   func(*slog.Logger)
+		lines int
+		want  []string
+	}{{
+		name:  "info",
+		log:   func(l *slog.Logger) { l.With("test", "quick-start").Info("deployed", "replicas", 3) },
+		lines: 1,
+		want:  []string{"| SLOG", "| LOG   |\ndeployed test=quick-start replicas=3"},
+	}, {
+		name: "groups",
+		log: func(l *slog.Logger) {
+			l.WithGroup("http").With("method", "GET").Info("request", slog.Group("response", "code", 200), slog.Group("", "inline", true), "", "ignored")
+		},
+		lines: 1,
+		want:  []string{"\nrequest http.method=GET http.response.code=200 http.inline=true"},
+	}, {
+		name:  "warn",
+		log:   func(l *slog.Logger) { l.Warn("slow") },
+		lines: 1,
+		want:  []string{"| WARN  |\nslow"},
+	}, {
+		name:  "error",
+		log:   func(l *slog.Logger) { l.Error("failed", "error", "conflict") },
+		lines: 1,
+		want:  []string{"| ERROR |\nfailed error=conflict"},
+	}, {
+		name: "debug dropped",
+		log:  func(l *slog.Logger) { l.Debug("noisy") },
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockT := &tlogging.FakeTLogger{}
+			tt.log(slog.New(NewSlogHandler(NewLogger(mockT, fakeClock, "test", "step"))))
+			assert.Len(t, mockT.Messages, tt.lines)
+			for _, want := range tt.want {
+				assert.Contains(t, strings.Join(mockT.Messages, ""), want)
+			}
+		})
+	}
+}
+
+func TestNewSlogHandler_Context(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Now())
+	mainT := &tlogging.FakeTLogger{}
+	stepT := &tlogging.FakeTLogger{}
+	l := slog.New(NewSlogHandler(NewLogger(mainT, fakeClock, "test", "@main")))
+	// records logged with the context of a step go to the step logger
+	ctx := IntoContext(context.Background(), NewLogger(stepT, fakeClock, "test", "step-1"))
+	l.InfoContext(ctx, "from step")
+	l.Info("from main")
+	assert.Len(t, stepT.Messages, 1)
+	assert.Contains(t, stepT.Messages[0], "| step-1 |")
+	assert.Len(t, mainT.Messages, 1)
+	// without a logger records are dropped
+	assert.NoError(t, NewSlogHandler(nil).Handle(context.Background(), slog.Record{}))
+}
+
+func TestFromSlog(t *testing.T) {
+	defer SetLevel(GetLevel())
+	SetLevel(DebugLevel)
+	var resource unstructured.Unstructured
+	resource.SetAPIVersion("v1")
+	resource.SetKind("ConfigMap")
+	resource.SetName("settings")
+	tests := []struct {
+		name string
+		log  func(Logger)
+		want map[string]any
+	}{{
+		name: "info",
+		log:  func(l Logger) { l.Log(Apply, OkStatus, nil, s("first"), s("\x1b[31msecond\x1b[0m")) },
+		want: map[string]any{"level": "INFO", "msg": "first\nsecond", "operation": "APPLY", "status": "OK"},
+	}, {
+		name: "resource",
+		log:  func(l Logger) { l.WithResource(&resource).LogLevel(WarnLevel, Delete, WarnStatus, nil) },
+		want: map[string]any{
+			"level":     "WARN",
+			"msg":       "",
+			"operation": "DELETE",
+			"status":    "WARN",
+			"resource":  map[string]any{"apiVersion": "v1", "kind": "ConfigMap", "name": "settings"},
+		},
+	}, {
+		name: "operation",
+		log:  func(l Logger) { l.WithOperation("Script", report.OperationTypeScript).Log(Script, LogStatus, nil) },
+		want: map[string]any{
+			"level":         "INFO",
+			"msg":           "",
+			"operationName": "Script",
+			"operationType": "script",
+			"operation":     "SCRIPT",
+			"status":        "LOG",
+		},
+	}, {
+		name: "debug",
+		log:  func(l Logger) { l.LogLevel(DebugLevel, Assert, ErrorStatus, nil, s("attempt 1")) },
+		want: map[string]any{"level": "DEBUG", "msg": "attempt 1", "operation": "ASSERT", "status": "ERROR"},
+	}, {
+		name: "error",
+		log:  func(l Logger) { l.LogLevel(ErrorLevel, Assert, ErrorStatus, nil, s("failed")) },
+		want: map[string]any{"level": "ERROR", "msg": "failed", "operation": "ASSERT", "status": "ERROR"},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+				Level: slog.LevelDebug,
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if len(groups) == 0 && a.Key == slog.TimeKey {
+						return slog.Attr{}
+					}
+					return a
+				},
+			})
+			tt.log(FromSlog(slog.New(handler)))
+			var got map[string]any
+			assert.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewSlogEntrySink(t *testing.T) {
+	var resource unstructured.Unstructured
+	resource.SetAPIVersion("v1")
+	resource.SetKind("ConfigMap")
+	resource.SetName("settings")
+	var buf bytes.Buffer
+	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	// steps are padded by the test processor
+	logger := NewSinkLogger(NewSlogEntrySink(slog.New(handler)), tclock.NewFakePassiveClock(time.Now()), "quick-start", "step-1  ")
+	Failure(logger.WithResources(&resource, &resource), Delete, ErrorStatus, s("failed"))
+	var got map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	assert.Equal(t, map[string]any{
+		"level":     "ERROR",
+		"msg":       "failed",
+		"test":      "quick-start",
+		"step":      "step-1",
+		"operation": "DELETE",
+		"status":    "ERROR",
+		"resources": []any{"v1/ConfigMap @ settings", "v1/ConfigMap @ settings"},
+	}, got)
+}
@@ -0,0 +1,81 @@
+package logging
+
+import (
+	"bytes"
+	"sync"
+)
+
+// StreamWriter logs the output of a process line by line, like the stdout or the stderr of a script, it is safe
+// for concurrent use. Each line is logged with the operation and the status of the writer, OutStatus or ErrStatus
+// typically, and the context of the logger. Lines may end with LF or CRLF, a trailing partial line is held until
+// its end is written or the writer is flushed. Lines longer than the message size of the logger are logged in
+// chunks, see MessageMaxSize, they are never truncated.
+type StreamWriter struct {
+	lock      sync.Mutex
+	logger    Logger
+	operation Operation
+	status    Status
+	maxSize   int
+	partial   []byte
+}
+
+// NewStreamWriter returns a StreamWriter logging lines to logger with operation and status.
+func NewStreamWriter(logger Logger, operation Operation, status Status) *StreamWriter {
+	return &StreamWriter{
+		logger:    logger,
+		operation: operation,
+		status:    status,
+		maxSize:   MessageMaxSize(logger),
+	}
+}
+
+func (w *StreamWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	data := append(w.partial, p...)
+	for {
+		end := bytes.IndexByte(data, '\n')
+		if end < 0 {
+			break
+		}
+		w.log(string(bytes.TrimSuffix(data[:end], []byte("\r"))))
+		data = data[end+1:]
+	}
+	// long lines are logged as they come, a process printing a huge line doesn't grow the writer unbounded
+	for w.maxSize > 0 && len(data) > w.maxSize {
+		cut := w.cut(string(data))
+		w.logger.Log(w.operation, w.status, nil, message(data[:cut]))
+		data = data[cut:]
+	}
+	w.partial = append([]byte(nil), data...)
+	return len(p), nil
+}
+
+// Flush logs the partial line held, if any, typically once the process exited.
+func (w *StreamWriter) Flush() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if len(w.partial) != 0 {
+		w.log(string(bytes.TrimSuffix(w.partial, []byte("\r"))))
+		w.partial = nil
+	}
+	return nil
+}
+
+// log logs line, in chunks of at most the message size of the logger.
+func (w *StreamWriter) log(line string) {
+	for w.maxSize > 0 && len(line) > w.maxSize {
+		cut := w.cut(line)
+		w.logger.Log(w.operation, w.status, nil, message(line[:cut]))
+		line = line[cut:]
+	}
+	w.logger.Log(w.operation, w.status, nil, message(line))
+}
+
+// cut returns the index line longer than the message size is chunked at, chunks are never empty.
+func (w *StreamWriter) cut(line string) int {
+	if cut := messageCut(line, w.maxSize); cut > 0 {
+		return cut
+	}
+	return w.maxSize
+}
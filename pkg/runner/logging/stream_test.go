@@ -0,0 +1,147 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+// streamLines returns a logger recording the messages of the lines it logs per status, and the recorded lines.
+func streamLines(options ...Option) (Logger, func() map[Status][]string) {
+	var lock sync.Mutex
+	lines := map[Status][]string{}
+	sink := sinkFunc(func(entry Entry) error {
+		lock.Lock()
+		defer lock.Unlock()
+		lines[entry.Status] = append(lines[entry.Status], entry.Message)
+		return nil
+	})
+	logger := NewSinkLogger(sink, tclock.NewFakePassiveClock(time.Now()), "test", "step", options...)
+	return logger, func() map[Status][]string {
+		lock.Lock()
+		defer lock.Unlock()
+		return lines
+	}
+}
+
+func TestStreamWriter(t *testing.T) {
+	tests := []struct {
+		name    string
+		maxSize int
+		writes  []string
+		want    []string
+	}{{
+		name:   "lines",
+		writes: []string{"one\ntwo\n"},
+		want:   []string{"one", "two"},
+	}, {
+		name:   "lines split across writes",
+		writes: []string{"o", "ne\ntw", "o\n"},
+		want:   []string{"one", "two"},
+	}, {
+		name:   "crlf",
+		writes: []string{"one\r\ntwo\r", "\n"},
+		want:   []string{"one", "two"},
+	}, {
+		name:   "empty lines",
+		writes: []string{"one\n\ntwo\n"},
+		want:   []string{"one", "", "two"},
+	}, {
+		name:   "partial line",
+		writes: []string{"one\ntwo"},
+		want:   []string{"one", "two"},
+	}, {
+		name:   "partial line with carriage return",
+		writes: []string{"one\r"},
+		want:   []string{"one"},
+	}, {
+		name:    "long line",
+		maxSize: 4,
+		writes:  []string{"abcdefghij\n"},
+		want:    []string{"abcd", "efgh", "ij"},
+	}, {
+		name:    "long partial line",
+		maxSize: 4,
+		writes:  []string{"abcde", "fghij"},
+		want:    []string{"abcd", "efgh", "ij"},
+	}, {
+		name:    "long line with runes",
+		maxSize: 4,
+		writes:  []string{"abcé\n"},
+		want:    []string{"abc", "é"},
+	}, {
+		name:    "no chunks",
+		maxSize: -1,
+		writes:  []string{strings.Repeat("a", 2*DefaultMessageMaxSize) + "\n"},
+		want:    []string{strings.Repeat("a", 2*DefaultMessageMaxSize)},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var options []Option
+			if tt.maxSize != 0 {
+				options = append(options, WithMessageMaxSize(tt.maxSize))
+			}
+			logger, lines := streamLines(options...)
+			w := NewStreamWriter(logger, Script, OutStatus)
+			for _, write := range tt.writes {
+				n, err := w.Write([]byte(write))
+				assert.NoError(t, err)
+				assert.Equal(t, len(write), n)
+			}
+			assert.NoError(t, w.Flush())
+			assert.Equal(t, tt.want, lines()[OutStatus])
+			// flushing twice logs nothing more
+			assert.NoError(t, w.Flush())
+			assert.Equal(t, tt.want, lines()[OutStatus])
+		})
+	}
+}
+
+func TestStreamWriter_Context(t *testing.T) {
+	var entries []Entry
+	sink := sinkFunc(func(entry Entry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	logger := NewSinkLogger(sink, tclock.NewFakePassiveClock(time.Now()), "test", "step").WithOperation("Script", "script")
+	w := NewStreamWriter(logger, Script, ErrStatus)
+	_, err := w.Write([]byte("oops\n"))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "test", entries[0].Test)
+	assert.Equal(t, "step", entries[0].Step)
+	assert.Equal(t, "Script", entries[0].OperationName)
+	assert.Equal(t, Script, entries[0].Operation)
+	assert.Equal(t, ErrStatus, entries[0].Status)
+	assert.Equal(t, "oops", entries[0].Message)
+}
+
+func TestStreamWriter_Interleaved(t *testing.T) {
+	logger, lines := streamLines()
+	stdout := NewStreamWriter(logger, Script, OutStatus)
+	stderr := NewStreamWriter(logger, Script, ErrStatus)
+	var want []string
+	for i := 0; i < 100; i++ {
+		want = append(want, fmt.Sprintf("line %d", i))
+	}
+	var wg sync.WaitGroup
+	for _, w := range []*StreamWriter{stdout, stderr} {
+		wg.Add(1)
+		go func(w *StreamWriter) {
+			defer wg.Done()
+			for _, line := range want {
+				// lines are written in pieces, like a process flushing its buffers at random
+				_, _ = w.Write([]byte(line[:3]))
+				_, _ = w.Write([]byte(line[3:] + "\n"))
+			}
+		}(w)
+	}
+	wg.Wait()
+	assert.Equal(t, want, lines()[OutStatus])
+	assert.Equal(t, want, lines()[ErrStatus])
+}
@@ -0,0 +1,197 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/report"
+)
+
+// SyslogLocal is the syslog target of the local syslog daemon, like journald, listening on a unix socket.
+const SyslogLocal = "local"
+
+// syslogFacility is the user-level facility of RFC 5424, chainsaw runs alongside the programs of its user.
+const syslogFacility = 1
+
+// syslogSDID is the identifier of the structured data of the messages, 32473 is the enterprise number of RFC 5612,
+// reserved for documentation and examples.
+const syslogSDID = "chainsaw@32473"
+
+// syslogSeverities map the levels of lines to the severities of RFC 5424.
+var syslogSeverities = map[Level]int{
+	ErrorLevel: 3,
+	WarnLevel:  4,
+	InfoLevel:  6,
+	DebugLevel: 7,
+}
+
+// ParseSyslogTarget parses a syslog target, either SyslogLocal or a URL like unix:///dev/log, udp://host:514 or
+// tcp://host:601, and returns the network and the address to dial. The address of SyslogLocal is empty, the socket
+// of the local daemon is looked up when dialing.
+func ParseSyslogTarget(target string) (string, string, error) {
+	if target == SyslogLocal {
+		return "unix", "", nil
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid syslog target %q: %w", target, err)
+	}
+	switch u.Scheme {
+	case "unix":
+		if u.Path == "" {
+			return "", "", fmt.Errorf("invalid syslog target %q, expected a socket path like unix:///dev/log", target)
+		}
+		return "unix", u.Path, nil
+	case "udp", "tcp":
+		if u.Host == "" {
+			return "", "", fmt.Errorf("invalid syslog target %q, expected a host like %s://localhost:514", target, u.Scheme)
+		}
+		return u.Scheme, u.Host, nil
+	}
+	return "", "", fmt.Errorf("invalid syslog target %q (local|unix://<path>|udp://<host:port>|tcp://<host:port>)", target)
+}
+
+// SyslogSink writes log lines to a syslog daemon as RFC 5424 messages, the level of the line is mapped to the severity
+// of the message and the test name is its MSGID. The test, step, operation and status are in its structured data.
+// Messages are sent as datagrams over unix sockets and UDP, with octet counting framing over TCP.
+// It is safe for concurrent use.
+type SyslogSink struct {
+	lock     sync.Mutex
+	conn     net.Conn
+	framed   bool
+	hostname string
+	pid      int
+}
+
+// DialSyslog connects to the syslog daemon of target, see ParseSyslogTarget.
+func DialSyslog(target string) (*SyslogSink, error) {
+	network, address, err := ParseSyslogTarget(target)
+	if err != nil {
+		return nil, err
+	}
+	var conn net.Conn
+	if network == "unix" {
+		conn, err = dialUnixSyslog(address)
+	} else {
+		conn, err = net.DialTimeout(network, address, 5*time.Second)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to syslog %s: %w", target, err)
+	}
+	return newSyslogSink(conn, network == "tcp"), nil
+}
+
+// dialUnixSyslog connects to the unix socket at address, or to the first socket of a local daemon found when address
+// is empty. Daemons listen on datagram sockets, stream sockets are tried too like the standard log/syslog does.
+func dialUnixSyslog(address string) (net.Conn, error) {
+	addresses := localSyslogAddresses()
+	if address != "" {
+		addresses = []string{address}
+	}
+	if len(addresses) == 0 {
+		return nil, errors.New("no local syslog daemon on this platform")
+	}
+	var errs []error
+	for _, address := range addresses {
+		for _, network := range []string{"unixgram", "unix"} {
+			conn, err := net.Dial(network, address)
+			if err == nil {
+				return conn, nil
+			}
+			errs = append(errs, err)
+		}
+	}
+	return nil, errors.Join(errs...)
+}
+
+func newSyslogSink(conn net.Conn, framed bool) *SyslogSink {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	return &SyslogSink{
+		conn:     conn,
+		framed:   framed,
+		hostname: hostname,
+		pid:      os.Getpid(),
+	}
+}
+
+func (s *SyslogSink) WriteEntry(entry Entry) error {
+	message := FormatSyslog(entry, s.hostname, s.pid)
+	if s.framed {
+		message = strconv.Itoa(len(message)) + " " + message
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	_, err := s.conn.Write([]byte(message))
+	return err
+}
+
+// Close closes the connection to the daemon.
+func (s *SyslogSink) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.conn.Close()
+}
+
+// FormatSyslog renders entry as an RFC 5424 message sent from hostname by the process pid, its text is the human
+// readable line of entry without escape sequences.
+func FormatSyslog(entry Entry, hostname string, pid int) string {
+	severity, ok := syslogSeverities[entry.Level]
+	if !ok {
+		severity = syslogSeverities[InfoLevel]
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "<%d>1 %s %s chainsaw %d %s ", syslogFacility*8+severity, entry.Time.Format(time.RFC3339Nano),
+		syslogName(hostname, 255), pid, syslogName(entry.Test, 32))
+	b.WriteString("[" + syslogSDID)
+	params := [][2]string{
+		{"test", entry.Test},
+		{"step", strings.TrimSpace(entry.Step)},
+		{"operation", string(entry.Operation)},
+		{"status", string(entry.Status)},
+	}
+	if entry.OperationType != "" {
+		params = append(params, [2]string{"operationType", string(entry.OperationType)})
+	}
+	if entry.Namespace != "" {
+		params = append(params, [2]string{"namespace", entry.Namespace})
+	}
+	for _, param := range params {
+		b.WriteString(" " + param[0] + `="` + syslogParamValue(param[1]) + `"`)
+	}
+	b.WriteString("] ")
+	// messages may hold escape sequences, like those of colored diffs, they have no meaning in the journal
+	b.WriteString(report.StripANSI(FormatText(entry, false)))
+	return b.String()
+}
+
+// syslogName returns name as a header field of at most size printable ASCII characters, the nil value "-" when empty.
+func syslogName(name string, size int) string {
+	name = strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' {
+			return '_'
+		}
+		return r
+	}, name)
+	if name == "" {
+		return "-"
+	}
+	if len(name) > size {
+		name = name[:size]
+	}
+	return name
+}
+
+// syslogParamValue escapes the characters of a structured data parameter value, see RFC 5424 section 6.3.3.
+func syslogParamValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(value)
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package logging
+
+// localSyslogAddresses returns the sockets local syslog daemons listen on, journald listens on /dev/log on Linux and
+// the BSDs and macOS use the others.
+func localSyslogAddresses() []string {
+	return []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+}
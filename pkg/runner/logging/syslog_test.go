@@ -0,0 +1,190 @@
+package logging
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+func TestParseSyslogTarget(t *testing.T) {
+	tests := []struct {
+		target      string
+		wantNetwork string
+		wantAddress string
+		wantErr     bool
+	}{{
+		target:      "local",
+		wantNetwork: "unix",
+	}, {
+		target:      "unix:///dev/log",
+		wantNetwork: "unix",
+		wantAddress: "/dev/log",
+	}, {
+		target:      "udp://localhost:514",
+		wantNetwork: "udp",
+		wantAddress: "localhost:514",
+	}, {
+		target:      "tcp://10.0.0.1:601",
+		wantNetwork: "tcp",
+		wantAddress: "10.0.0.1:601",
+	}, {
+		target:  "unix://",
+		wantErr: true,
+	}, {
+		target:  "tcp://",
+		wantErr: true,
+	}, {
+		target:  "http://localhost:514",
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			network, address, err := ParseSyslogTarget(tt.target)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantNetwork, network)
+			assert.Equal(t, tt.wantAddress, address)
+		})
+	}
+}
+
+func TestFormatSyslog(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		entry Entry
+		want  string
+	}{{
+		name:  "info",
+		entry: Entry{Time: now, Level: InfoLevel, Test: "quick start", Step: "step-1  ", Operation: Apply, Status: OkStatus},
+		want:  `<14>1 2024-03-01T10:30:00Z host chainsaw 42 quick_start [chainsaw@32473 test="quick start" step="step-1" operation="APPLY" status="OK"] | 10:30:00 | quick start | step-1   | APPLY     | OK    |`,
+	}, {
+		name: "error",
+		entry: Entry{
+			Time: now, Level: ErrorLevel, Test: "test", Step: "step", Operation: Assert, Status: ErrorStatus,
+			OperationName: "assert", OperationType: report.OperationTypeAssert, Namespace: "chainsaw-happy-cat",
+			Message: "\x1b[31mboom\x1b[0m",
+		},
+		want: `<11>1 2024-03-01T10:30:00Z host chainsaw 42 test [chainsaw@32473 test="test" step="step" operation="ASSERT" status="ERROR" operationType="assert" namespace="chainsaw-happy-cat"] | 10:30:00 | test | step | assert | ASSERT    | ERROR |` + "\nboom",
+	}, {
+		name:  "escaped",
+		entry: Entry{Time: now, Level: DebugLevel, Test: `a "quoted" [test]`, Operation: Script, Status: LogStatus, TimestampLayout: NoTimestamp},
+		want:  `<15>1 2024-03-01T10:30:00Z host chainsaw 42 a_"quoted"_[test] [chainsaw@32473 test="a \"quoted\" [test\]" step="" operation="SCRIPT" status="LOG"] | a "quoted" [test] |  | SCRIPT    | LOG   |`,
+	}, {
+		name:  "no test",
+		entry: Entry{Time: now, Level: WarnLevel, Operation: Internal, Status: WarnStatus, TimestampLayout: NoTimestamp},
+		want:  `<12>1 2024-03-01T10:30:00Z host chainsaw 42 - [chainsaw@32473 test="" step="" operation="INTERNAL" status="WARN"] |  |  | INTERNAL  | WARN  |`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, FormatSyslog(tt.entry, "host", 42))
+		})
+	}
+	// MSGID holds 32 characters at most
+	assert.Contains(t, FormatSyslog(Entry{Time: now, Test: strings.Repeat("x", 40)}, "host", 42), " 42 "+strings.Repeat("x", 32)+" [")
+}
+
+func TestDialSyslog(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	logLine := func(t *testing.T, target string) {
+		t.Helper()
+		sink, err := DialSyslog(target)
+		require.NoError(t, err)
+		defer sink.Close()
+		logger := NewSinkLogger(sink, fakeClock, "test", "step")
+		logger.Log(Apply, OkStatus, nil)
+		Failure(logger, Assert, ErrorStatus, s("boom"))
+	}
+	t.Run("udp", func(t *testing.T) {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer conn.Close()
+		logLine(t, "udp://"+conn.LocalAddr().String())
+		var messages []string
+		buf := make([]byte, 4096)
+		for i := 0; i < 2; i++ {
+			assert.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+			n, _, err := conn.ReadFrom(buf)
+			require.NoError(t, err)
+			messages = append(messages, string(buf[:n]))
+		}
+		assert.True(t, strings.HasPrefix(messages[0], "<14>1 2024-03-01T10:30:00Z "), messages[0])
+		assert.Contains(t, messages[0], fmt.Sprintf(" chainsaw %d test [chainsaw@32473 test=\"test\"", os.Getpid()))
+		assert.True(t, strings.HasPrefix(messages[1], "<11>1 "), messages[1])
+		assert.True(t, strings.HasSuffix(messages[1], "\nboom"), messages[1])
+	})
+	t.Run("tcp", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer listener.Close()
+		received := make(chan string, 1)
+		go func() {
+			conn, err := listener.Accept()
+			if err != nil {
+				received <- err.Error()
+				return
+			}
+			defer conn.Close()
+			reader := bufio.NewReader(conn)
+			var frames []string
+			for i := 0; i < 2; i++ {
+				var size int
+				if _, err := fmt.Fscanf(reader, "%d ", &size); err != nil {
+					received <- err.Error()
+					return
+				}
+				frame := make([]byte, size)
+				if _, err := io.ReadFull(reader, frame); err != nil {
+					received <- err.Error()
+					return
+				}
+				frames = append(frames, string(frame))
+			}
+			received <- strings.Join(frames, "|FRAME|")
+		}()
+		logLine(t, "tcp://"+listener.Addr().String())
+		select {
+		case frames := <-received:
+			parts := strings.Split(frames, "|FRAME|")
+			require.Len(t, parts, 2, frames)
+			assert.True(t, strings.HasPrefix(parts[0], "<14>1 "), parts[0])
+			assert.True(t, strings.HasSuffix(parts[1], "\nboom"), parts[1])
+		case <-time.After(5 * time.Second):
+			t.Fatal("no message received")
+		}
+	})
+	t.Run("unix", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("no unix datagram sockets")
+		}
+		path := filepath.Join(t.TempDir(), "log")
+		conn, err := net.ListenPacket("unixgram", path)
+		require.NoError(t, err)
+		defer conn.Close()
+		logLine(t, "unix://"+path)
+		buf := make([]byte, 4096)
+		assert.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		n, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(string(buf[:n]), "<14>1 "), string(buf[:n]))
+	})
+	t.Run("unreachable", func(t *testing.T) {
+		_, err := DialSyslog("unix://" + filepath.Join(t.TempDir(), "missing"))
+		assert.ErrorContains(t, err, "failed to connect to syslog unix://")
+	})
+}
@@ -0,0 +1,8 @@
+//go:build windows
+
+package logging
+
+// localSyslogAddresses returns no socket, Windows has no local syslog daemon, remote daemons are reached over UDP or TCP.
+func localSyslogAddresses() []string {
+	return nil
+}
@@ -4,3 +4,18 @@ type TLogger interface {
 	Log(args ...any)
 	Helper()
 }
+
+// cleanupTLogger is implemented by TLoggers running functions when the test completes, like testing.T.
+type cleanupTLogger interface {
+	TLogger
+	Cleanup(func())
+}
+
+// testingTLogger is implemented by the TLoggers of the testing package, like testing.T.
+// It is probed rather than the testing package imported, programs embedding the runner don't get its flags.
+type testingTLogger interface {
+	TLogger
+	Name() string
+	Failed() bool
+	Skipped() bool
+}
@@ -0,0 +1,281 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/kyverno/chainsaw/pkg/report"
+)
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// DefaultTeeMaxOpenFiles is the default maximum number of files a per test Tee keeps open at once.
+const DefaultTeeMaxOpenFiles = 64
+
+// Tee writes a copy of the log lines to files, ANSI escape sequences are stripped.
+// Lines are written whole with a single write, it is safe for concurrent use.
+type Tee struct {
+	lock    sync.Mutex
+	path    string
+	perTest bool
+	// files are the open files, by test in per test mode, the zero key holds the file of the Tee otherwise
+	files map[TestKey]*RotatingFile
+	// rotation rotates the files, see WithRotation
+	rotation Rotation
+	// dirty holds the files written since they were last flushed
+	dirty map[TestKey]bool
+	// paths are the files assigned to the tests in per test mode, names are the file names taken
+	paths map[TestKey]string
+	names map[string]bool
+	// maxOpen is the maximum number of open files in per test mode, the least recently written is closed beyond it,
+	// used orders the files by their last write
+	maxOpen int
+	used    map[TestKey]uint64
+	tick    uint64
+	err     error
+}
+
+// TeeOption configures a Tee.
+type TeeOption func(*Tee)
+
+// WithRotation rotates the files of a Tee once they reach the maximum size of rotation, see RotatingFile.
+func WithRotation(rotation Rotation) TeeOption {
+	return func(t *Tee) {
+		t.rotation = rotation
+	}
+}
+
+// WithMaxOpenFiles keeps at most maxOpen files open at once in per test mode, it defaults to DefaultTeeMaxOpenFiles.
+// The least recently written file is closed to open another one, it is reopened for appending if its test logs again.
+// A maxOpen of zero or less doesn't limit them.
+func WithMaxOpenFiles(maxOpen int) TeeOption {
+	return func(t *Tee) {
+		t.maxOpen = maxOpen
+	}
+}
+
+// NewTee creates a Tee writing all log lines to the file at path.
+func NewTee(path string, options ...TeeOption) (*Tee, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	t := &Tee{path: path, dirty: map[TestKey]bool{}}
+	for _, option := range options {
+		option(t)
+	}
+	file, err := CreateRotatingFile(path, t.rotation)
+	if err != nil {
+		return nil, err
+	}
+	t.files = map[TestKey]*RotatingFile{{}: file}
+	return t, nil
+}
+
+// NewPerTestTee creates a Tee writing the log lines of each test to its own file in the dir folder.
+// Files are named after the tests and created when the first line of the test is written, each is rotated on its own.
+// Tests whose names map to the same file name, like tests of different folders with the same name, get a numbered
+// file, like test-2.log. Files are closed when their test
+// completes, see CloseTest, and when too many are open, see WithMaxOpenFiles.
+func NewPerTestTee(dir string, options ...TeeOption) (*Tee, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	t := &Tee{
+		path:    dir,
+		perTest: true,
+		files:   map[TestKey]*RotatingFile{},
+		dirty:   map[TestKey]bool{},
+		paths:   map[TestKey]string{},
+		names:   map[string]bool{},
+		maxOpen: DefaultTeeMaxOpenFiles,
+		used:    map[TestKey]uint64{},
+	}
+	for _, option := range options {
+		option(t)
+	}
+	return t, nil
+}
+
+// TestFileName returns the name of the file holding the log lines of test in per test mode.
+func TestFileName(test string) string {
+	return strings.Trim(unsafeFileChars.ReplaceAllString(test, "_"), "_") + ".log"
+}
+
+func (t *Tee) file(test TestKey) (io.Writer, error) {
+	if !t.perTest {
+		t.dirty[TestKey{}] = true
+		return t.files[TestKey{}], nil
+	}
+	t.tick++
+	if file, ok := t.files[test]; ok {
+		t.dirty[test], t.used[test] = true, t.tick
+		return file, nil
+	}
+	if t.maxOpen > 0 && len(t.files) >= t.maxOpen {
+		if err := t.closeLeastRecent(); err != nil {
+			return nil, err
+		}
+	}
+	// a file closed before is reopened, it isn't truncated
+	open := OpenRotatingFile
+	path, ok := t.paths[test]
+	if !ok {
+		path, open = filepath.Join(t.path, t.fileName(test)), CreateRotatingFile
+	}
+	file, err := open(path, t.rotation)
+	if err != nil {
+		return nil, err
+	}
+	t.paths[test] = path
+	t.names[filepath.Base(path)] = true
+	t.files[test] = file
+	t.dirty[test], t.used[test] = true, t.tick
+	return file, nil
+}
+
+// fileName returns the name of a file of test not taken by another test, numbered after TestFileName on collisions.
+func (t *Tee) fileName(test TestKey) string {
+	name := TestFileName(test.Name)
+	base := strings.TrimSuffix(name, ".log")
+	for i := 2; t.names[name]; i++ {
+		name = fmt.Sprintf("%s-%d.log", base, i)
+	}
+	return name
+}
+
+// closeLeastRecent closes the least recently written file.
+func (t *Tee) closeLeastRecent() error {
+	var oldest TestKey
+	for test := range t.files {
+		if oldest == (TestKey{}) || t.used[test] < t.used[oldest] {
+			oldest = test
+		}
+	}
+	return t.closeFile(oldest)
+}
+
+// closeFile closes the file of test, if it is open, its lines are committed to disk first.
+func (t *Tee) closeFile(test TestKey) error {
+	file, ok := t.files[test]
+	if !ok {
+		return nil
+	}
+	delete(t.files, test)
+	delete(t.dirty, test)
+	delete(t.used, test)
+	err := file.Sync()
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+	return nil
+}
+
+// CloseTest closes the file of test in per test mode, typically when the test completes, and returns its path.
+// The path is empty when the test wrote no line or the Tee isn't in per test mode. Lines of test written afterwards
+// are appended to the file.
+func (t *Tee) CloseTest(test TestKey) (string, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if !t.perTest || t.files == nil {
+		return "", nil
+	}
+	return t.paths[test], t.closeFile(test)
+}
+
+// Write writes a log line of test, the first error is kept and returned by Close.
+func (t *Tee) Write(test TestKey, line string) error {
+	line = report.StripANSI(strings.TrimLeft(line, "\b")) + "\n"
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.files == nil {
+		return nil
+	}
+	w, err := t.file(test)
+	if err == nil {
+		_, err = io.WriteString(w, line)
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to write log file: %w", err)
+		if t.err == nil {
+			t.err = err
+		}
+	}
+	return err
+}
+
+// WriteEntry writes entry in the human readable format, without colors.
+func (t *Tee) WriteEntry(entry Entry) error {
+	return t.Write(entry.TestKey(), FormatText(entry, false))
+}
+
+// Flush commits the files written since the last flush to disk, so they survive the process dying.
+func (t *Tee) Flush() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	var err error
+	for name := range t.dirty {
+		if file, ok := t.files[name]; ok {
+			if serr := file.Sync(); serr != nil && err == nil {
+				err = fmt.Errorf("failed to flush log file: %w", serr)
+			}
+		}
+		delete(t.dirty, name)
+	}
+	return err
+}
+
+// Close closes the files, lines written afterwards are dropped.
+func (t *Tee) Close() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	err := t.err
+	for _, file := range t.files {
+		if cerr := file.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	t.files = nil
+	return err
+}
+
+// CloseTestFiles closes the files of test written by the per test Tees of l, see WithTee, and returns their paths.
+// Errors closing them are kept and returned by the Close of their Tee.
+func CloseTestFiles(l Logger, test TestKey) []string {
+	switch l := l.(type) {
+	case *logger:
+		var paths []string
+		for _, sink := range l.sinks {
+			tee, ok := sink.(*Tee)
+			if !ok {
+				continue
+			}
+			path, err := tee.CloseTest(test)
+			if err != nil {
+				tee.keepError(err)
+			}
+			if path != "" {
+				paths = append(paths, path)
+			}
+		}
+		return paths
+	case *Deduper:
+		return CloseTestFiles(l.logger, test)
+	}
+	return nil
+}
+
+func (t *Tee) keepError(err error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.err == nil {
+		t.err = err
+	}
+}
@@ -0,0 +1,220 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
+	"github.com/kyverno/kyverno/ext/output/color"
+	"github.com/stretchr/testify/assert"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+func TestTee(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "chainsaw.log")
+	tee, err := NewTee(path)
+	assert.NoError(t, err)
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			l := NewLogger(&tlogging.FakeTLogger{}, fakeClock, fmt.Sprintf("test-%d", i), "step", WithTee(tee))
+			for j := 0; j < 100; j++ {
+				l.Log(Script, LogStatus, color.BoldGreen, s(strings.Repeat("x", 200)))
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.NoError(t, tee.Close())
+	// lines written after close are dropped
+	tee.Write(TestKey{Name: "late"}, "line")
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "\x1b")
+	assert.NotContains(t, string(data), "\b")
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	// each log call spans two lines, the prefix and the argument
+	assert.Len(t, lines, 2000)
+	for i := 0; i < len(lines); i += 2 {
+		assert.Regexp(t, `^\| 10:30:00 \| test-\d \| step \| SCRIPT    \| LOG   \|$`, lines[i])
+		assert.Equal(t, strings.Repeat("x", 200), lines[i+1])
+	}
+}
+
+func TestPerTestTee(t *testing.T) {
+	dir := t.TempDir()
+	tee, err := NewPerTestTee(dir)
+	assert.NoError(t, err)
+	fakeClock := tclock.NewFakePassiveClock(time.Now())
+	mockT := &tlogging.FakeTLogger{}
+	// lines are still written when the console output is disabled
+	NewLogger(mockT, fakeClock, "quick-start", "step-1", WithTee(tee), WithoutText()).Log(Apply, OkStatus, nil)
+	NewLogger(mockT, fakeClock, "nested/test name", "step-1", WithTee(tee), WithoutText()).Log(Assert, DoneStatus, nil)
+	assert.NoError(t, tee.Close())
+	assert.Empty(t, mockT.Messages)
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Equal(t, []string{"nested_test_name.log", "quick-start.log"}, names)
+	data, err := os.ReadFile(filepath.Join(dir, "quick-start.log"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "| quick-start | step-1 | APPLY")
+	assert.NotContains(t, string(data), "ASSERT")
+}
+
+func TestTee_Error(t *testing.T) {
+	dir := t.TempDir()
+	tee, err := NewPerTestTee(dir)
+	assert.NoError(t, err)
+	// file names collide with an existing folder
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, TestFileName("test")), 0o755))
+	tee.Write(TestKey{Name: "test"}, "line")
+	assert.Error(t, tee.Close())
+}
+
+func TestTee_Flush(t *testing.T) {
+	dir := t.TempDir()
+	tee, err := NewPerTestTee(dir)
+	assert.NoError(t, err)
+	assert.NoError(t, tee.Write(TestKey{Name: "first"}, "line"))
+	assert.NoError(t, tee.Write(TestKey{Name: "second"}, "line"))
+	assert.NoError(t, tee.Flush())
+	assert.Empty(t, tee.dirty)
+	// files not written since are not synced again
+	assert.NoError(t, tee.Write(TestKey{Name: "first"}, "line"))
+	assert.Equal(t, map[TestKey]bool{{Name: "first"}: true}, tee.dirty)
+	assert.NoError(t, tee.Flush())
+	assert.NoError(t, tee.Close())
+	assert.NoError(t, tee.Flush())
+	data, err := os.ReadFile(filepath.Join(dir, TestFileName("first")))
+	assert.NoError(t, err)
+	assert.Equal(t, "line\nline\n", string(data))
+}
+
+func TestPerTestTee_Collision(t *testing.T) {
+	dir := t.TempDir()
+	tee, err := NewPerTestTee(dir)
+	assert.NoError(t, err)
+	// both names are sanitized to the same file name
+	assert.NoError(t, tee.Write(TestKey{Name: "test name"}, "first"))
+	assert.NoError(t, tee.Write(TestKey{Name: "test/name"}, "second"))
+	assert.NoError(t, tee.Write(TestKey{Name: "test name"}, "first again"))
+	assert.NoError(t, tee.Close())
+	for name, want := range map[string]string{"test_name.log": "first\nfirst again\n", "test_name-2.log": "second\n"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		assert.NoError(t, err)
+		assert.Equal(t, want, string(data), name)
+	}
+}
+
+func TestPerTestTee_CloseTest(t *testing.T) {
+	dir := t.TempDir()
+	tee, err := NewPerTestTee(dir)
+	assert.NoError(t, err)
+	path, err := tee.CloseTest(TestKey{Name: "test"})
+	assert.NoError(t, err)
+	assert.Empty(t, path)
+	assert.NoError(t, tee.Write(TestKey{Name: "test"}, "first"))
+	path, err = tee.CloseTest(TestKey{Name: "test"})
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "test.log"), path)
+	assert.Empty(t, tee.files)
+	// lines written afterwards are appended to the file
+	assert.NoError(t, tee.Write(TestKey{Name: "test"}, "second"))
+	assert.NoError(t, tee.Close())
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "first\nsecond\n", string(data))
+	// files of a single Tee are not per test
+	single, err := NewTee(filepath.Join(dir, "all.log"))
+	assert.NoError(t, err)
+	assert.NoError(t, single.Write(TestKey{Name: "test"}, "line"))
+	path, err = single.CloseTest(TestKey{Name: "test"})
+	assert.NoError(t, err)
+	assert.Empty(t, path)
+	assert.NoError(t, single.Close())
+}
+
+func TestPerTestTee_MaxOpenFiles(t *testing.T) {
+	dir := t.TempDir()
+	tee, err := NewPerTestTee(dir, WithMaxOpenFiles(2))
+	assert.NoError(t, err)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				assert.NoError(t, tee.Write(TestKey{Name: fmt.Sprintf("test-%d", i)}, fmt.Sprintf("line %d", j)))
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.LessOrEqual(t, len(tee.files), 2)
+	assert.NoError(t, tee.Close())
+	for i := 0; i < 20; i++ {
+		data, err := os.ReadFile(filepath.Join(dir, fmt.Sprintf("test-%d.log", i)))
+		assert.NoError(t, err)
+		assert.Equal(t, 10, strings.Count(string(data), "\n"))
+	}
+	// the least recently written file is closed first
+	tee, err = NewPerTestTee(t.TempDir(), WithMaxOpenFiles(2))
+	assert.NoError(t, err)
+	assert.NoError(t, tee.Write(TestKey{Name: "first"}, "line"))
+	assert.NoError(t, tee.Write(TestKey{Name: "second"}, "line"))
+	assert.NoError(t, tee.Write(TestKey{Name: "first"}, "line"))
+	assert.NoError(t, tee.Write(TestKey{Name: "third"}, "line"))
+	assert.Contains(t, tee.files, TestKey{Name: "first"})
+	assert.Contains(t, tee.files, TestKey{Name: "third"})
+	assert.NotContains(t, tee.files, TestKey{Name: "second"})
+	assert.NoError(t, tee.Close())
+}
+
+func TestPerTestTee_DuplicateNames(t *testing.T) {
+	dir := t.TempDir()
+	tee, err := NewPerTestTee(dir)
+	assert.NoError(t, err)
+	fakeClock := tclock.NewFakePassiveClock(time.Now())
+	a := NewLogger(&tlogging.FakeTLogger{}, fakeClock, "test", "step", WithTee(tee), WithTestPath("tests/a"))
+	b := NewLogger(&tlogging.FakeTLogger{}, fakeClock, "test", "step", WithTee(tee), WithTestPath("tests/b"))
+	a.Log(Script, LogStatus, nil, s("a"))
+	b.Log(Script, LogStatus, nil, s("b"))
+	// the tests sharing a name get their own file, closing one leaves the other open
+	assert.Equal(t, []string{filepath.Join(dir, "test.log")}, CloseTestFiles(a, TestKey{Path: "tests/a", Name: "test"}))
+	b.Log(Script, LogStatus, nil, s("b again"))
+	assert.Equal(t, []string{filepath.Join(dir, "test-2.log")}, CloseTestFiles(b, TestKey{Path: "tests/b", Name: "test"}))
+	assert.NoError(t, tee.Close())
+	data, err := os.ReadFile(filepath.Join(dir, "test.log"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "\na\n")
+	assert.NotContains(t, string(data), "\nb")
+	data, err = os.ReadFile(filepath.Join(dir, "test-2.log"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "\nb\n")
+	assert.Contains(t, string(data), "\nb again\n")
+	assert.NotContains(t, string(data), "\na\n")
+}
+
+func TestCloseTestFiles(t *testing.T) {
+	dir := t.TempDir()
+	tee, err := NewPerTestTee(dir)
+	assert.NoError(t, err)
+	fakeClock := tclock.NewFakePassiveClock(time.Now())
+	logger := NewLogger(&tlogging.FakeTLogger{}, fakeClock, "test", "step", WithTee(tee))
+	logger.Log(Apply, OkStatus, nil)
+	assert.Equal(t, []string{filepath.Join(dir, "test.log")}, CloseTestFiles(Dedupe(logger, fakeClock), TestKey{Name: "test"}))
+	assert.Empty(t, tee.files)
+	assert.Empty(t, CloseTestFiles(logger, TestKey{Name: "other"}))
+	assert.Empty(t, CloseTestFiles(NoOp(), TestKey{Name: "test"}))
+	assert.NoError(t, tee.Close())
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/fatih/color"
+	"github.com/kyverno/chainsaw/pkg/report"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -18,12 +19,36 @@ func (f *FakeLogger) WithResource(resource ctrlclient.Object) Logger {
 	return f
 }
 
+func (f *FakeLogger) WithResources(resources ...ctrlclient.Object) Logger {
+	defer func() { f.numCalls++ }()
+	return f
+}
+
+func (f *FakeLogger) WithOperation(name string, operationType report.OperationType) Logger {
+	defer func() { f.numCalls++ }()
+	return f
+}
+
+func (f *FakeLogger) WithFields(fields map[string]any) Logger {
+	defer func() { f.numCalls++ }()
+	return f
+}
+
+func (f *FakeLogger) WithField(key string, value any) Logger {
+	defer func() { f.numCalls++ }()
+	return f
+}
+
 func (f *FakeLogger) Log(operation Operation, status Status, color *color.Color, args ...fmt.Stringer) {
 	defer func() { f.numCalls++ }()
 	message := fmt.Sprintf("%s: %s - %v", operation, status, args)
 	f.Logs = append(f.Logs, message)
 }
 
+func (f *FakeLogger) LogLevel(level Level, operation Operation, status Status, color *color.Color, args ...fmt.Stringer) {
+	f.Log(operation, status, color, args...)
+}
+
 func (f *FakeLogger) NumCalls() int {
 	return f.numCalls
 }
@@ -4,15 +4,31 @@ import (
 	"fmt"
 
 	"github.com/fatih/color"
+	"github.com/kyverno/chainsaw/pkg/report"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type (
 	Operation string
 	Status    string
+	// Level is the verbosity of a log line, lower levels are more important.
+	Level int
 )
 
 type Logger interface {
+	// Log logs at the info level.
 	Log(Operation, Status, *color.Color, ...fmt.Stringer)
+	// LogLevel logs at the given level, lines above the configured threshold are dropped.
+	LogLevel(Level, Operation, Status, *color.Color, ...fmt.Stringer)
 	WithResource(ctrlclient.Object) Logger
+	// WithResources returns a logger attributing the lines it logs to several resources, nil entries are skipped.
+	// With a single resource left it is the same as WithResource, the receiver is left unchanged.
+	WithResources(...ctrlclient.Object) Logger
+	// WithOperation returns a logger attributing the lines it logs to an operation, the receiver is left unchanged.
+	WithOperation(string, report.OperationType) Logger
+	// WithFields returns a logger attaching structured key/value fields to the lines it logs, the receiver is left unchanged.
+	// Fields are merged with those of the receiver, values of the same keys override them.
+	WithFields(map[string]any) Logger
+	// WithField returns a logger attaching a single field to the lines it logs, like WithFields.
+	WithField(string, any) Logger
 }
@@ -0,0 +1,31 @@
+package logging
+
+// TestKey identifies a test in the sinks holding the lines of each test, tests of different folders may have the same
+// name. The lines of a test are attributed to it with WithTestPath.
+type TestKey struct {
+	// Path is the folder of the test.
+	Path string
+	// Name is the name of the test.
+	Name string
+}
+
+// Less orders the keys by name, then by path.
+func (k TestKey) Less(other TestKey) bool {
+	if k.Name != other.Name {
+		return k.Name < other.Name
+	}
+	return k.Path < other.Path
+}
+
+// TestKey returns the key of the test the entry was logged for.
+func (e Entry) TestKey() TestKey {
+	return TestKey{Path: e.TestPath, Name: e.Test}
+}
+
+// WithTestPath attributes the log lines to the test of the folder at path, the test is then identified by its name
+// and its path in the sinks holding the lines of each test, see TestKey.
+func WithTestPath(path string) Option {
+	return func(l *logger) {
+		l.testPath = path
+	}
+}
@@ -0,0 +1,195 @@
+package logging
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/fatih/color"
+)
+
+// ThemeEnv is the environment variable overriding the colors of the default theme, see ParseTheme.
+const ThemeEnv = "CHAINSAW_THEME"
+
+// Theme holds the colors of log lines, the styles of the outcome helpers are made of them, see Palette.
+// A nil color leaves the text it applies to uncolored.
+type Theme struct {
+	// Success colors passed operations.
+	Success *color.Color
+	// Failure colors failed operations.
+	Failure *color.Color
+	// Skip colors skipped operations.
+	Skip *color.Color
+	// Warning colors warnings.
+	Warning *color.Color
+	// Info colors running operations and their output.
+	Info *color.Color
+	// Debug colors debug lines.
+	Debug *color.Color
+	// Prefix colors the test, step and operation name columns, they take the color of the line when it is nil.
+	Prefix *color.Color
+	// Resource colors the resource of the lines.
+	Resource *color.Color
+}
+
+// DefaultTheme returns the default theme: green for passed operations, red for failures, magenta for skipped ones,
+// yellow for warnings, cyan for running operations and dim for debug lines. Prefixes take the color of the line and
+// resources are not colored.
+func DefaultTheme() Theme {
+	return Theme{
+		Success: color.New(color.FgGreen, color.Bold),
+		Failure: color.New(color.FgRed, color.Bold),
+		Skip:    color.New(color.FgMagenta, color.Bold),
+		Warning: color.New(color.FgYellow, color.Bold),
+		Info:    color.New(color.FgCyan, color.Bold),
+		Debug:   color.New(color.Faint),
+	}
+}
+
+// Palette returns the styles of the outcome helpers colored with the theme.
+func (t Theme) Palette() Palette {
+	return Palette{
+		Success: Style{Color: t.Success, Marker: "OK", Outcome: SuccessOutcome},
+		Failure: Style{Color: t.Failure, Marker: "FAIL", Outcome: FailureOutcome},
+		Skip:    Style{Color: t.Skip, Marker: "SKIP", Outcome: SkipOutcome},
+		Warning: Style{Color: t.Warning, Marker: "WARN"},
+		Running: Style{Color: t.Info},
+		Debug:   Style{Color: t.Debug},
+	}
+}
+
+// themeKeys are the keys of the theme format, with the color they set.
+var themeKeys = map[string]func(*Theme) **color.Color{
+	"success":  func(t *Theme) **color.Color { return &t.Success },
+	"failure":  func(t *Theme) **color.Color { return &t.Failure },
+	"skip":     func(t *Theme) **color.Color { return &t.Skip },
+	"warning":  func(t *Theme) **color.Color { return &t.Warning },
+	"info":     func(t *Theme) **color.Color { return &t.Info },
+	"debug":    func(t *Theme) **color.Color { return &t.Debug },
+	"prefix":   func(t *Theme) **color.Color { return &t.Prefix },
+	"resource": func(t *Theme) **color.Color { return &t.Resource },
+}
+
+// themeColors are the color names of the theme format, hi- names are their high intensity variants.
+var themeColors = map[string]color.Attribute{
+	"black":      color.FgBlack,
+	"red":        color.FgRed,
+	"green":      color.FgGreen,
+	"yellow":     color.FgYellow,
+	"blue":       color.FgBlue,
+	"magenta":    color.FgMagenta,
+	"cyan":       color.FgCyan,
+	"white":      color.FgWhite,
+	"hi-black":   color.FgHiBlack,
+	"hi-red":     color.FgHiRed,
+	"hi-green":   color.FgHiGreen,
+	"hi-yellow":  color.FgHiYellow,
+	"hi-blue":    color.FgHiBlue,
+	"hi-magenta": color.FgHiMagenta,
+	"hi-cyan":    color.FgHiCyan,
+	"hi-white":   color.FgHiWhite,
+	"bold":       color.Bold,
+	"faint":      color.Faint,
+	"italic":     color.Italic,
+	"underline":  color.Underline,
+}
+
+// SupportedThemeKeys returns the keys of the theme format.
+func SupportedThemeKeys() []string {
+	return sortedKeys(themeKeys)
+}
+
+// SupportedThemeColors returns the color names of the theme format.
+func SupportedThemeColors() []string {
+	return sortedKeys(themeColors)
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ParseTheme parses comma separated overrides of the colors of the default theme, like "success=cyan,failure=magenta+bold".
+// A color is a color name, optionally combined with attributes like bold or underline with +, or none to leave the text
+// uncolored. Unknown keys and color names are errors, an empty spec is the default theme.
+func ParseTheme(spec string) (Theme, error) {
+	theme := DefaultTheme()
+	if strings.TrimSpace(spec) == "" {
+		return theme, nil
+	}
+	for _, override := range strings.Split(spec, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(override), "=")
+		if !ok {
+			return DefaultTheme(), fmt.Errorf("invalid theme color %q, expected <key>=<color>", override)
+		}
+		field, ok := themeKeys[strings.ToLower(strings.TrimSpace(key))]
+		if !ok {
+			return DefaultTheme(), fmt.Errorf("invalid theme key %q (%s)", key, strings.Join(SupportedThemeKeys(), "|"))
+		}
+		c, err := parseThemeColor(value)
+		if err != nil {
+			return DefaultTheme(), err
+		}
+		*field(&theme) = c
+	}
+	return theme, nil
+}
+
+// parseThemeColor parses a color of the theme format, none is a nil color.
+func parseThemeColor(value string) (*color.Color, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "none" {
+		return nil, nil
+	}
+	var attributes []color.Attribute
+	for _, name := range strings.Split(value, "+") {
+		attribute, ok := themeColors[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("invalid theme color %q (none|%s)", name, strings.Join(SupportedThemeColors(), "|"))
+		}
+		attributes = append(attributes, attribute)
+	}
+	return color.New(attributes...), nil
+}
+
+var theme atomic.Pointer[Theme]
+
+func init() {
+	SetTheme(DefaultTheme())
+}
+
+// SetTheme sets the theme of the loggers created afterwards, and the palette of the outcome helpers to its palette.
+func SetTheme(t Theme) {
+	theme.Store(&t)
+	SetPalette(t.Palette())
+}
+
+// GetTheme returns the theme of the loggers created afterwards.
+func GetTheme() Theme {
+	return *currentTheme()
+}
+
+// currentTheme returns the theme set last, it is never changed afterwards and can be shared by loggers.
+func currentTheme() *Theme {
+	return theme.Load()
+}
+
+// LoadThemeEnv sets the theme parsed from the ThemeEnv environment variable, if set, typically at startup.
+// It returns an error when the variable holds unknown keys or color names, the theme is then left unchanged.
+func LoadThemeEnv() error {
+	spec, ok := lookupEnv(ThemeEnv)
+	if !ok {
+		return nil
+	}
+	t, err := ParseTheme(spec)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", ThemeEnv, err)
+	}
+	SetTheme(t)
+	return nil
+}
@@ -0,0 +1,139 @@
+package logging
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+func TestParseTheme(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    func(*Theme)
+		wantErr bool
+	}{{
+		name: "empty",
+		spec: "",
+		want: func(*Theme) {},
+	}, {
+		name: "colors",
+		spec: "success=cyan,failure=magenta",
+		want: func(t *Theme) {
+			t.Success = color.New(color.FgCyan)
+			t.Failure = color.New(color.FgMagenta)
+		},
+	}, {
+		name: "combined",
+		spec: " Prefix = hi-blue+bold , resource=underline",
+		want: func(t *Theme) {
+			t.Prefix = color.New(color.FgHiBlue, color.Bold)
+			t.Resource = color.New(color.Underline)
+		},
+	}, {
+		name: "none",
+		spec: "debug=none",
+		want: func(t *Theme) {
+			t.Debug = nil
+		},
+	}, {
+		name:    "unknown key",
+		spec:    "success=cyan,banner=red",
+		wantErr: true,
+	}, {
+		name:    "unknown color",
+		spec:    "success=teal",
+		wantErr: true,
+	}, {
+		name:    "unknown combined color",
+		spec:    "success=cyan+blink",
+		wantErr: true,
+	}, {
+		name:    "missing color",
+		spec:    "success",
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTheme(tt.spec)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			want := DefaultTheme()
+			tt.want(&want)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestLoadThemeEnv(t *testing.T) {
+	defer func(f func(string) (string, bool)) { lookupEnv = f }(lookupEnv)
+	defer SetTheme(GetTheme())
+	env := map[string]string{}
+	lookupEnv = func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+	// unset, the theme is left unchanged
+	assert.NoError(t, LoadThemeEnv())
+	assert.Equal(t, DefaultTheme(), GetTheme())
+	env[ThemeEnv] = "success=cyan"
+	assert.NoError(t, LoadThemeEnv())
+	assert.Equal(t, color.New(color.FgCyan), GetTheme().Success)
+	assert.Equal(t, color.New(color.FgCyan), GetPalette().Success.Color)
+	// unknown colors are errors
+	env[ThemeEnv] = "success=teal"
+	err := LoadThemeEnv()
+	assert.ErrorContains(t, err, "invalid CHAINSAW_THEME")
+	assert.Equal(t, color.New(color.FgCyan), GetTheme().Success)
+}
+
+func TestSetTheme(t *testing.T) {
+	defer SetTheme(GetTheme())
+	theme := DefaultTheme()
+	theme.Failure = color.New(color.FgBlue)
+	SetTheme(theme)
+	assert.Equal(t, theme, GetTheme())
+	assert.Equal(t, theme.Palette(), GetPalette())
+	colored := &tlogging.FakeTLogger{}
+	Failure(NewLogger(colored, tclock.NewFakePassiveClock(time.Now()), "test", "step", WithColor(ColorAlways)), Apply, ErrorStatus)
+	assert.Contains(t, colored.Messages[0], "\x1b[34m")
+	// the markers are kept
+	assert.Equal(t, "FAIL", GetPalette().Failure.Marker)
+}
+
+func TestFormatText_Theme(t *testing.T) {
+	theme := DefaultTheme()
+	theme.Prefix = color.New(color.FgBlue)
+	theme.Resource = color.New(color.FgMagenta)
+	pod := &unstructured.Unstructured{}
+	pod.SetKind("Pod")
+	pod.SetName("nginx")
+	entry := Entry{
+		Time:      time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC),
+		Test:      "test",
+		Step:      "step",
+		Operation: Apply,
+		Status:    DoneStatus,
+		Resource:  pod,
+		Style:     theme.Palette().Success,
+		Theme:     &theme,
+	}
+	colored := FormatText(entry, true)
+	assert.Contains(t, colored, colorSprint(theme.Prefix)("test"))
+	assert.Contains(t, colored, colorSprint(theme.Resource)(FormatResource(pod, entry.ResourceFormat)))
+	// the operation keeps the color of the line
+	assert.Contains(t, colored, colorSprint(theme.Success)(padded(string(Apply), 9)))
+	// themes don't apply without colors
+	entry.Theme = nil
+	plain := FormatText(entry, false)
+	entry.Theme = &theme
+	assert.Equal(t, plain, FormatText(entry, false))
+}
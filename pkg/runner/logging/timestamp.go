@@ -0,0 +1,64 @@
+package logging
+
+import (
+	"fmt"
+	"time"
+)
+
+// TimestampLayout is the Go reference layout timestamps of human readable log lines are rendered with.
+type TimestampLayout string
+
+const (
+	// DefaultTimestampLayout renders the time of day, it is used when no layout is set.
+	DefaultTimestampLayout TimestampLayout = "15:04:05"
+	// NoTimestamp suppresses timestamps, typically to compare the output of a run with golden files.
+	NoTimestamp TimestampLayout = "none"
+)
+
+// namedLayouts are the layouts of the time package that can be referred to by name.
+var namedLayouts = map[string]string{
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"DateTime":    time.DateTime,
+	"TimeOnly":    time.TimeOnly,
+	"Kitchen":     time.Kitchen,
+	"StampMilli":  time.StampMilli,
+}
+
+// layoutSample is the time layouts are checked against, none of its fields match the reference time of layouts.
+var layoutSample = time.Date(2024, time.November, 23, 22, 47, 38, 987654321, time.FixedZone("CET", 60*60))
+
+// ParseTimestampLayout parses a timestamp layout.
+// It accepts "none", the name of a standard layout like RFC3339Nano, or a Go reference layout.
+// A reference layout must render the time and parse it back, catching layouts without any layout element.
+func ParseTimestampLayout(layout string) (TimestampLayout, error) {
+	if layout == "" {
+		return DefaultTimestampLayout, nil
+	}
+	if TimestampLayout(layout) == NoTimestamp {
+		return NoTimestamp, nil
+	}
+	if named, ok := namedLayouts[layout]; ok {
+		return TimestampLayout(named), nil
+	}
+	formatted := layoutSample.Format(layout)
+	if formatted == layout {
+		return DefaultTimestampLayout, fmt.Errorf("invalid timestamp layout %q, it doesn't contain any layout element", layout)
+	}
+	if _, err := time.Parse(layout, formatted); err != nil {
+		return DefaultTimestampLayout, fmt.Errorf("invalid timestamp layout %q: %w", layout, err)
+	}
+	return TimestampLayout(layout), nil
+}
+
+// Format renders t with the layout, it returns an empty string when timestamps are suppressed.
+func (l TimestampLayout) Format(t time.Time) string {
+	switch l {
+	case NoTimestamp:
+		return ""
+	case "":
+		return t.Format(string(DefaultTimestampLayout))
+	default:
+		return t.Format(string(l))
+	}
+}
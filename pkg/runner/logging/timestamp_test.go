@@ -0,0 +1,95 @@
+package logging
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTimestampLayout(t *testing.T) {
+	tests := []struct {
+		name    string
+		layout  string
+		want    TimestampLayout
+		wantErr bool
+	}{{
+		name: "empty",
+		want: DefaultTimestampLayout,
+	}, {
+		name:   "none",
+		layout: "none",
+		want:   NoTimestamp,
+	}, {
+		name:   "named",
+		layout: "RFC3339Nano",
+		want:   time.RFC3339Nano,
+	}, {
+		name:   "reference layout",
+		layout: "2006-01-02 15:04:05.000 MST",
+		want:   "2006-01-02 15:04:05.000 MST",
+	}, {
+		name:    "no layout element",
+		layout:  "hh:mm:ss",
+		want:    DefaultTimestampLayout,
+		wantErr: true,
+	}, {
+		name:    "unknown name",
+		layout:  "ISO",
+		want:    DefaultTimestampLayout,
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTimestampLayout(tt.layout)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTimestampLayout_Format(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 30, 0, 120000000, time.UTC)
+	tests := []struct {
+		name   string
+		layout TimestampLayout
+		want   string
+	}{{
+		name: "default",
+		want: "10:30:00",
+	}, {
+		name:   "none",
+		layout: NoTimestamp,
+		want:   "",
+	}, {
+		name:   "rfc3339 nano",
+		layout: time.RFC3339Nano,
+		want:   "2024-03-01T10:30:00.12Z",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.layout.Format(now))
+		})
+	}
+}
+
+func TestFormatText_TimestampLayout(t *testing.T) {
+	entry := Entry{
+		Time:            time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC),
+		Test:            "test",
+		Step:            "step",
+		Operation:       Apply,
+		Status:          OkStatus,
+		TimestampLayout: time.RFC3339,
+	}
+	assert.Equal(t, "| 2024-03-01T10:30:00Z | test | step | APPLY     | OK    |", FormatText(entry, false))
+	entry.TimestampLayout = NoTimestamp
+	assert.Equal(t, "| test | step | APPLY     | OK    |", FormatText(entry, false))
+	// elapsed durations keep their column
+	entry.TestStart = entry.Time.Add(-time.Second)
+	assert.Equal(t, "| [+1.000s] | test | step | APPLY     | OK    |", FormatText(entry, false))
+}
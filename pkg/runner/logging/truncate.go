@@ -0,0 +1,57 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultMessageMaxSize is the default maximum size in bytes of the message of a log line.
+const DefaultMessageMaxSize = 16 * 1024
+
+// ansiReset resets the attributes of the terminal, it closes the colors of truncated messages.
+const ansiReset = "\x1b[0m"
+
+// TruncateMessage truncates message to at most maxSize bytes followed by a "(truncated, N bytes omitted)" suffix,
+// it returns the message and the number of bytes omitted. Messages are never cut inside a rune or an ANSI escape
+// sequence, and colors left open are reset. A maxSize of zero or less disables truncation.
+func TruncateMessage(message string, maxSize int) (string, int) {
+	if maxSize <= 0 || len(message) <= maxSize {
+		return message, 0
+	}
+	cut := messageCut(message, maxSize)
+	kept := message[:cut]
+	if strings.IndexByte(kept, '\x1b') >= 0 {
+		kept += ansiReset
+	}
+	omitted := len(message) - cut
+	return fmt.Sprintf("%s (truncated, %d bytes omitted)", kept, omitted), omitted
+}
+
+// messageCut returns the index message longer than maxSize bytes is cut at, at most maxSize and never inside a rune
+// or an ANSI escape sequence.
+func messageCut(message string, maxSize int) int {
+	cut := maxSize
+	for cut > 0 && !utf8.RuneStart(message[cut]) {
+		cut--
+	}
+	if escape := strings.LastIndexByte(message[:cut], '\x1b'); escape >= 0 && ansiEnd(message, escape) > cut {
+		cut = escape
+	}
+	return cut
+}
+
+// ansiEnd returns the index following the escape sequence starting at start, control sequences end with a byte
+// in the 0x40-0x7e range, other sequences are two bytes long.
+func ansiEnd(message string, start int) int {
+	i := start + 1
+	if i >= len(message) || message[i] != '[' {
+		return i + 1
+	}
+	for i++; i < len(message); i++ {
+		if message[i] >= 0x40 && message[i] <= 0x7e {
+			return i + 1
+		}
+	}
+	return len(message) + 1
+}
@@ -0,0 +1,110 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
+	"github.com/stretchr/testify/assert"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+func TestTruncateMessage(t *testing.T) {
+	tests := []struct {
+		name        string
+		message     string
+		maxSize     int
+		want        string
+		wantOmitted int
+	}{{
+		name:    "disabled",
+		message: "0123456789",
+		want:    "0123456789",
+	}, {
+		name:    "at the limit",
+		message: "0123456789",
+		maxSize: 10,
+		want:    "0123456789",
+	}, {
+		name:        "one byte over the limit",
+		message:     "0123456789",
+		maxSize:     9,
+		want:        "012345678 (truncated, 1 bytes omitted)",
+		wantOmitted: 1,
+	}, {
+		// é is two bytes long, the limit falls on its second byte
+		name:        "inside a rune",
+		message:     "abcé",
+		maxSize:     4,
+		want:        "abc (truncated, 2 bytes omitted)",
+		wantOmitted: 2,
+	}, {
+		name:        "after a rune",
+		message:     "abcéd",
+		maxSize:     5,
+		want:        "abcé (truncated, 1 bytes omitted)",
+		wantOmitted: 1,
+	}, {
+		name:        "inside an escape sequence",
+		message:     "ab\x1b[31mred\x1b[0m",
+		maxSize:     5,
+		want:        "ab (truncated, 12 bytes omitted)",
+		wantOmitted: 12,
+	}, {
+		name:        "after an escape sequence",
+		message:     "ab\x1b[31mred\x1b[0m",
+		maxSize:     8,
+		want:        "ab\x1b[31mr\x1b[0m (truncated, 6 bytes omitted)",
+		wantOmitted: 6,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, omitted := TruncateMessage(tt.message, tt.maxSize)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantOmitted, omitted)
+		})
+	}
+}
+
+func Test_logger_MessageMaxSize(t *testing.T) {
+	clock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	var entries []Entry
+	sink := sinkFunc(func(entry Entry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	logger := NewLogger(&tlogging.FakeTLogger{}, clock, "test", "step", WithSink(sink), WithMessageMaxSize(8))
+	logger.Log(Script, LogStatus, nil, s("short"))
+	logger.Log(Script, LogStatus, nil, s("first"), s("second"))
+	assert.Equal(t, "short", entries[0].Message)
+	assert.Empty(t, entries[0].FullMessage)
+	// the message is truncated once its arguments are joined
+	assert.Equal(t, "first\nse (truncated, 4 bytes omitted)", entries[1].Message)
+	assert.Equal(t, "first\nsecond", entries[1].FullMessage)
+	// messages are capped by default
+	entries = nil
+	NewLogger(&tlogging.FakeTLogger{}, clock, "test", "step", WithSink(sink)).Log(Script, LogStatus, nil, s(strings.Repeat("a", DefaultMessageMaxSize+10)))
+	assert.Equal(t, strings.Repeat("a", DefaultMessageMaxSize)+" (truncated, 10 bytes omitted)", entries[0].Message)
+}
+
+func TestCaptures_KeepFullMessages(t *testing.T) {
+	clock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	for _, keepFull := range []bool{false, true} {
+		mockT := &tlogging.FakeTLogger{}
+		captures := NewCapturesWithMaxSize(DefaultCaptureSize)
+		if keepFull {
+			captures.KeepFullMessages()
+		}
+		NewLogger(mockT, clock, "test", "step", WithColor(ColorNever), WithMessageMaxSize(4), WithCapture(captures)).Log(Script, LogStatus, nil, s("0123456789"))
+		// the console always gets the truncated message
+		assert.Equal(t, []string{eraser + "| 10:30:00 | test | step | SCRIPT    | LOG   |\n0123 (truncated, 6 bytes omitted)"}, mockT.Messages)
+		want := "| 10:30:00 | test | step | SCRIPT    | LOG   |\n0123 (truncated, 6 bytes omitted)"
+		if keepFull {
+			want = "| 10:30:00 | test | step | SCRIPT    | LOG   |\n0123456789"
+		}
+		lines := captures.Lines(TestKey{Name: "test"})
+		assert.Len(t, lines, 1)
+		assert.Equal(t, want, lines[0].Message)
+	}
+}
@@ -0,0 +1,38 @@
+package logging
+
+import (
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// UTCEnv is the environment variable rendering timestamps in UTC when set to a true boolean, like "true" or "1".
+const UTCEnv = "CHAINSAW_LOG_UTC"
+
+// UTCFromEnv returns true when the environment asks for timestamps in UTC.
+func UTCFromEnv() bool {
+	value, _ := lookupEnv(UTCEnv)
+	enabled, err := strconv.ParseBool(value)
+	return err == nil && enabled
+}
+
+var utc atomic.Bool
+
+// SetUTC sets whether the loggers and captures created afterwards stamp lines in UTC rather than in the local time zone,
+// so that the logs of runs on hosts in different time zones can be compared.
+func SetUTC(enabled bool) {
+	utc.Store(enabled)
+}
+
+// GetUTC returns whether the loggers and captures created afterwards stamp lines in UTC.
+func GetUTC() bool {
+	return utc.Load()
+}
+
+// stamp returns t in UTC if utc is true, as is otherwise.
+func stamp(t time.Time, utc bool) time.Time {
+	if utc {
+		return t.UTC()
+	}
+	return t
+}
@@ -0,0 +1,121 @@
+package logging
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
+	"github.com/stretchr/testify/assert"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+// cet is a time zone other than UTC, the tests must not depend on the zone of the host
+var cet = time.FixedZone("CET", 60*60)
+
+func TestUTCFromEnv(t *testing.T) {
+	defer func(f func(string) (string, bool)) { lookupEnv = f }(lookupEnv)
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{{
+		name: "unset",
+		want: false,
+	}, {
+		name: "true",
+		env:  map[string]string{UTCEnv: "true"},
+		want: true,
+	}, {
+		name: "one",
+		env:  map[string]string{UTCEnv: "1"},
+		want: true,
+	}, {
+		name: "false",
+		env:  map[string]string{UTCEnv: "false"},
+		want: false,
+	}, {
+		name: "invalid",
+		env:  map[string]string{UTCEnv: "utc"},
+		want: false,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookupEnv = func(name string) (string, bool) {
+				value, ok := tt.env[name]
+				return value, ok
+			}
+			assert.Equal(t, tt.want, UTCFromEnv())
+		})
+	}
+}
+
+func TestLogger_UTC(t *testing.T) {
+	defer SetUTC(GetUTC())
+	tests := []struct {
+		name         string
+		global       bool
+		options      []Option
+		want         *time.Location
+		wantTime     string
+		wantWaitTime string
+	}{{
+		name:         "local",
+		want:         cet,
+		wantTime:     "10:30:00",
+		wantWaitTime: "10:30:01",
+	}, {
+		name:         "global",
+		global:       true,
+		want:         time.UTC,
+		wantTime:     "09:30:00",
+		wantWaitTime: "09:30:01",
+	}, {
+		name:         "option",
+		options:      []Option{WithUTC(true)},
+		want:         time.UTC,
+		wantTime:     "09:30:00",
+		wantWaitTime: "09:30:01",
+	}, {
+		name:         "option overrides global",
+		global:       true,
+		options:      []Option{WithUTC(false)},
+		want:         cet,
+		wantTime:     "10:30:00",
+		wantWaitTime: "10:30:01",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetUTC(tt.global)
+			fakeClock := tclock.NewFakeClock(time.Date(2024, 3, 1, 10, 30, 0, 0, cet))
+			mockT := &tlogging.FakeTLogger{}
+			var entries []Entry
+			sink := sinkFunc(func(entry Entry) error {
+				entries = append(entries, entry)
+				return nil
+			})
+			options := append([]Option{WithColor(ColorNever), WithSink(sink)}, tt.options...)
+			logger := NewLogger(mockT, fakeClock, "test", "step", options...)
+			logger.Log(Apply, DoneStatus, nil)
+			assert.Contains(t, mockT.Messages[0], "| "+tt.wantTime+" |")
+			assert.Equal(t, tt.want, entries[0].Time.Location())
+			// heartbeats are stamped like the other lines
+			heartbeat := StartWaitHeartbeat(WithWaitInterval(context.Background(), time.Second), logger, Assert)
+			fakeClock.Step(time.Second)
+			heartbeat.Beat("the resource", nil)
+			assert.Contains(t, mockT.Messages[1], "| "+tt.wantWaitTime+" |")
+			assert.Contains(t, mockT.Messages[1], "still waiting for the resource")
+			assert.Equal(t, tt.want, entries[1].Time.Location())
+		})
+	}
+}
+
+func TestCaptures_UTC(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, cet))
+	captures := NewCaptures(0)
+	logger := NewLogger(&tlogging.FakeTLogger{}, fakeClock, "test", "step", WithCapture(captures), WithUTC(true))
+	logger.Log(Apply, DoneStatus, nil)
+	lines := captures.Lines(TestKey{Name: "test"})
+	assert.Equal(t, time.UTC, lines[0].Time.Location())
+	assert.Contains(t, lines[0].Message, "| 09:30:00 |")
+}
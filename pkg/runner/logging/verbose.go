@@ -0,0 +1,33 @@
+package logging
+
+import (
+	"flag"
+)
+
+// WithVerbose sets whether the lines below the error level are logged, whatever the -v flag of go test, see
+// testingVerbose. They are still subject to the level threshold, see SetLevel.
+func WithVerbose(verbose bool) Option {
+	return func(l *logger) {
+		l.quiet = !verbose
+	}
+}
+
+// testingVerbose returns the verbosity of the loggers of t by default. Tests run by go test without -v only log
+// failures, like the testing package prints the output of failed tests only, other TLoggers log everything.
+func testingVerbose(t TLogger) Option {
+	if _, ok := t.(testingTLogger); !ok {
+		return WithVerbose(true)
+	}
+	return WithVerbose(testVerboseFlag())
+}
+
+// testVerboseFlag returns the value of the -test.v flag, it is registered by the testing package when tests are
+// built by go test. It reads like a bool or "test2json" when go test converts the output to JSON.
+func testVerboseFlag() bool {
+	f := flag.Lookup("test.v")
+	if f == nil {
+		return true
+	}
+	value := f.Value.String()
+	return value != "" && value != "false"
+}
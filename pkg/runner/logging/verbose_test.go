@@ -0,0 +1,90 @@
+package logging
+
+import (
+	"flag"
+	"testing"
+	"time"
+
+	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
+	"github.com/stretchr/testify/assert"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+// fakeTestingT is a TLogger looking like a testing.T.
+type fakeTestingT struct {
+	tlogging.FakeTLogger
+}
+
+func (t *fakeTestingT) Name() string  { return "test" }
+func (t *fakeTestingT) Failed() bool  { return false }
+func (t *fakeTestingT) Skipped() bool { return false }
+
+func setTestVerbose(t *testing.T, value string) {
+	t.Helper()
+	f := flag.Lookup("test.v")
+	previous := f.Value.String()
+	assert.NoError(t, flag.Set("test.v", value))
+	t.Cleanup(func() { _ = flag.Set("test.v", previous) })
+}
+
+func TestNewLogger_Verbose(t *testing.T) {
+	tests := []struct {
+		name    string
+		testing bool
+		verbose string
+		options []Option
+		want    int
+	}{{
+		name:    "go test",
+		testing: true,
+		verbose: "false",
+		want:    1,
+	}, {
+		name:    "go test -v",
+		testing: true,
+		verbose: "true",
+		want:    3,
+	}, {
+		name:    "go test -json",
+		testing: true,
+		verbose: "test2json",
+		want:    3,
+	}, {
+		name:    "explicit verbose",
+		testing: true,
+		verbose: "false",
+		options: []Option{WithVerbose(true)},
+		want:    3,
+	}, {
+		name:    "explicit quiet",
+		testing: true,
+		verbose: "true",
+		options: []Option{WithVerbose(false)},
+		want:    1,
+	}, {
+		name:    "not a test",
+		verbose: "false",
+		want:    3,
+	}, {
+		name:    "not a test quiet",
+		verbose: "true",
+		options: []Option{WithVerbose(false)},
+		want:    1,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setTestVerbose(t, tt.verbose)
+			mockT := &fakeTestingT{}
+			var tlogger TLogger = &mockT.FakeTLogger
+			if tt.testing {
+				tlogger = mockT
+			}
+			logger := NewLogger(tlogger, tclock.NewFakePassiveClock(time.Now()), "test", "step", tt.options...)
+			logger.Log(Apply, OkStatus, nil)
+			Warn(logger, Apply, WarnStatus, s("slow"))
+			Failure(logger, Assert, ErrorStatus, s("boom"))
+			assert.Len(t, mockT.Messages, tt.want)
+			assert.Contains(t, mockT.Messages[len(mockT.Messages)-1], "boom")
+		})
+	}
+}
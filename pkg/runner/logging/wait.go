@@ -0,0 +1,102 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+// DefaultWaitInterval is the interval of heartbeats when none is stored in the context, see WithWaitInterval.
+const DefaultWaitInterval = 30 * time.Second
+
+// WaitHeartbeat logs that a polling operation is still waiting, at an interval measured with the clock of its logger.
+// It is driven by the polling loop: every attempt calls Beat, a line is logged once the interval has elapsed since
+// the start or the previous line, and the heartbeat stops as soon as the loop does, on success or timeout.
+// A nil WaitHeartbeat is disabled.
+type WaitHeartbeat struct {
+	logger    Logger
+	operation Operation
+	clock     clock.PassiveClock
+	interval  time.Duration
+	timeout   time.Duration
+	start     time.Time
+	next      time.Time
+}
+
+// StartWaitHeartbeat returns the heartbeat of a polling operation logging to logger, with the interval stored in ctx.
+// The timeout printed is the one of the operation of logger, see WithOperationTimeout, or else the time left before the
+// deadline of ctx, if any. It returns nil when the interval is zero or less.
+func StartWaitHeartbeat(ctx context.Context, logger Logger, operation Operation) *WaitHeartbeat {
+	interval := WaitInterval(ctx)
+	if interval <= 0 || logger == nil {
+		return nil
+	}
+	h := &WaitHeartbeat{
+		logger:    logger,
+		operation: operation,
+		clock:     loggerClock(logger),
+		interval:  interval,
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		// deadlines are set with the real clock
+		h.timeout = time.Until(deadline).Round(time.Second)
+	}
+	h.start = h.clock.Now()
+	h.next = h.start.Add(interval)
+	return h
+}
+
+// Beat logs a line telling the operation is still waiting for what, with lastErr if not nil, when the interval has elapsed.
+// Intervals missed by a slow attempt are skipped, a single line is logged.
+func (h *WaitHeartbeat) Beat(what string, lastErr error) {
+	if h == nil {
+		return
+	}
+	now := h.clock.Now()
+	if now.Before(h.next) {
+		return
+	}
+	for !h.next.After(now) {
+		h.next = h.next.Add(h.interval)
+	}
+	var text string
+	if progress := TimeoutProgress(h.logger); progress != "" {
+		// the timeout of the operation is the one the runner enforces
+		text = fmt.Sprintf("still waiting for %s, %s", what, progress)
+	} else {
+		text = fmt.Sprintf("still waiting for %s, elapsed %s", what, formatDuration(h.logger, now.Sub(h.start), formatWait))
+		if h.timeout > 0 {
+			text += fmt.Sprintf(" of %s timeout", formatDuration(h.logger, h.timeout, formatWait))
+		}
+	}
+	if lastErr != nil {
+		text += ", last error: " + lastErr.Error()
+	}
+	Running(h.logger, h.operation, WaitStatus, message(text))
+}
+
+// formatWait renders d to the second, without the zero trailing units, e.g. 1m30s or 5m.
+func formatWait(d time.Duration) string {
+	text := d.Round(time.Second).String()
+	if strings.HasSuffix(text, "m0s") {
+		text = strings.TrimSuffix(text, "0s")
+	}
+	if strings.HasSuffix(text, "h0m") {
+		text = strings.TrimSuffix(text, "0m")
+	}
+	return text
+}
+
+// loggerClock returns the clock of l, or the real clock for loggers not created with NewLogger, NewWriterLogger or NewSinkLogger.
+func loggerClock(l Logger) clock.PassiveClock {
+	switch l := l.(type) {
+	case *logger:
+		return l.clock
+	case *Deduper:
+		return loggerClock(l.logger)
+	}
+	return clock.RealClock{}
+}
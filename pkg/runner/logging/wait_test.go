@@ -0,0 +1,87 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+func TestWaitHeartbeat(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	var entries []Entry
+	sink := sinkFunc(func(entry Entry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	logger := NewSinkLogger(sink, fakeClock, "test", "step")
+	ctx, cancel := context.WithTimeout(WithWaitInterval(context.Background(), 30*time.Second), 5*time.Minute)
+	defer cancel()
+	heartbeat := StartWaitHeartbeat(ctx, logger, Assert)
+	// attempts are polled more often than the interval
+	for i := 0; i < 12; i++ {
+		fakeClock.SetTime(fakeClock.Now().Add(10 * time.Second))
+		heartbeat.Beat("v1/Pod @ default/nginx", errors.New("actual resource not found"))
+	}
+	assert.Len(t, entries, 4)
+	assert.Equal(t, Assert, entries[0].Operation)
+	assert.Equal(t, WaitStatus, entries[0].Status)
+	assert.Equal(t, InfoLevel, entries[0].Level)
+	assert.Equal(t, "still waiting for v1/Pod @ default/nginx, elapsed 30s of 5m timeout, last error: actual resource not found", entries[0].Message)
+	assert.Equal(t, "still waiting for v1/Pod @ default/nginx, elapsed 2m of 5m timeout, last error: actual resource not found", entries[3].Message)
+	// a slow attempt missing intervals logs a single line
+	fakeClock.SetTime(fakeClock.Now().Add(95 * time.Second))
+	heartbeat.Beat("v1/Pod @ default/nginx", nil)
+	assert.Len(t, entries, 5)
+	assert.Equal(t, "still waiting for v1/Pod @ default/nginx, elapsed 3m35s of 5m timeout", entries[4].Message)
+	// the next line is logged at the next interval
+	fakeClock.SetTime(fakeClock.Now().Add(20 * time.Second))
+	heartbeat.Beat("v1/Pod @ default/nginx", nil)
+	assert.Len(t, entries, 5)
+	fakeClock.SetTime(fakeClock.Now().Add(5 * time.Second))
+	heartbeat.Beat("v1/Pod @ default/nginx", nil)
+	assert.Len(t, entries, 6)
+	assert.Equal(t, "still waiting for v1/Pod @ default/nginx, elapsed 4m of 5m timeout", entries[5].Message)
+}
+
+func TestWaitHeartbeat_Disabled(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Now())
+	var entries []Entry
+	logger := NewSinkLogger(sinkFunc(func(entry Entry) error {
+		entries = append(entries, entry)
+		return nil
+	}), fakeClock, "test", "step")
+	heartbeat := StartWaitHeartbeat(WithWaitInterval(context.Background(), 0), logger, Assert)
+	assert.Nil(t, heartbeat)
+	fakeClock.SetTime(fakeClock.Now().Add(time.Hour))
+	// a nil heartbeat does nothing
+	heartbeat.Beat("v1/Pod @ default/nginx", nil)
+	assert.Empty(t, entries)
+	// without interval in the context, the default interval is used and no timeout is printed
+	heartbeat = StartWaitHeartbeat(context.Background(), logger, Assert)
+	fakeClock.SetTime(fakeClock.Now().Add(DefaultWaitInterval))
+	heartbeat.Beat("the expectation", nil)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "still waiting for the expectation, elapsed 30s", entries[0].Message)
+}
+
+func Test_formatWait(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		want     string
+	}{
+		{duration: 1500 * time.Millisecond, want: "2s"},
+		{duration: 90 * time.Second, want: "1m30s"},
+		{duration: 5 * time.Minute, want: "5m"},
+		{duration: time.Hour, want: "1h"},
+		{duration: time.Hour + 30*time.Second, want: "1h0m30s"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, formatWait(tt.duration))
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/kyverno/chainsaw/pkg/report"
+)
+
+// DefaultMaxWarnings is the default maximum number of warnings a WarningCollector keeps.
+const DefaultMaxWarnings = 100
+
+// WarningCollector collects the warnings raised by the loggers of a test, see WithWarnings, for the runner to add them
+// to the report of the test when it completes. It keeps a bounded number of warnings, those raised beyond are counted.
+// It is safe for concurrent use by the operations of a test.
+type WarningCollector struct {
+	lock     sync.Mutex
+	limit    int
+	warnings []report.Warning
+	dropped  int
+}
+
+// NewWarningCollector returns a WarningCollector keeping up to limit warnings, DefaultMaxWarnings if limit is zero or
+// less.
+func NewWarningCollector(limit int) *WarningCollector {
+	if limit <= 0 {
+		limit = DefaultMaxWarnings
+	}
+	return &WarningCollector{limit: limit}
+}
+
+func (c *WarningCollector) add(warning report.Warning) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if len(c.warnings) >= c.limit {
+		c.dropped++
+		return
+	}
+	c.warnings = append(c.warnings, warning)
+}
+
+// Warnings returns the warnings collected so far, in the order they were raised, and the number of warnings dropped
+// once the collector was full.
+func (c *WarningCollector) Warnings() ([]report.Warning, int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return append([]report.Warning(nil), c.warnings...), c.dropped
+}
+
+// AddTo adds the collected warnings to test, followed by a warning telling how many were dropped, if any.
+func (c *WarningCollector) AddTo(test *report.TestReport) {
+	warnings, dropped := c.Warnings()
+	for _, warning := range warnings {
+		test.AddWarning(warning.Type, warning.Message)
+	}
+	if dropped != 0 {
+		test.AddWarning(report.WarningTypeOther, fmt.Sprintf("%d more warnings were dropped", dropped))
+	}
+}
+
+// WithWarnings collects the warnings raised with RaiseWarning and Warning into c.
+func WithWarnings(c *WarningCollector) Option {
+	return func(l *logger) {
+		l.warnings = c
+	}
+}
+
+// RaiseWarning logs a warning with l, like Warn, and collects it for the report of the test when l was created
+// WithWarnings. Loggers without a collector only log it.
+func RaiseWarning(l Logger, operation Operation, warningType report.WarningType, message string) {
+	collectWarning(l, report.Warning{Type: warningType, Message: message})
+	Warn(l, operation, WarnStatus, Section("WARNING", message))
+}
+
+// collectWarning collects warning into the collector of l, it returns false when l has none.
+func collectWarning(l Logger, warning report.Warning) bool {
+	switch l := l.(type) {
+	case *logger:
+		if l.warnings != nil {
+			l.warnings.add(warning)
+			return true
+		}
+	case *Deduper:
+		return collectWarning(l.logger, warning)
+	}
+	return false
+}
+
+// Warning logs a warning with the logger of ctx and adds it to the report of the test in ctx, if any. Warnings
+// collected by the logger, see WithWarnings, are added to the report when the test completes instead.
+func Warning(ctx context.Context, operation Operation, warningType report.WarningType, message string) {
+	logger := FromContext(ctx)
+	if !collectWarning(logger, report.Warning{Type: warningType, Message: message}) {
+		if test := report.TestFromContext(ctx); test != nil {
+			test.AddWarning(warningType, message)
+		}
+	}
+	Warn(logger, operation, WarnStatus, Section("WARNING", message))
+}
@@ -0,0 +1,88 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/report"
+	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
+	"github.com/stretchr/testify/assert"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+func TestWarning(t *testing.T) {
+	mockT := &tlogging.FakeTLogger{}
+	logger := NewLogger(mockT, tclock.NewFakePassiveClock(time.Now()), "testName", "stepName")
+	testReport := report.NewTest("testName")
+	ctx := IntoContext(context.Background(), logger)
+	ctx = report.TestIntoContext(ctx, testReport)
+	Warning(ctx, Delete, report.WarningTypeSlowCleanup, "cleanup took 2m0s")
+	assert.Equal(t, []report.Warning{{Type: report.WarningTypeSlowCleanup, Message: "cleanup took 2m0s"}}, testReport.Warnings)
+	assert.Len(t, mockT.Messages, 1)
+	assert.True(t, strings.Contains(mockT.Messages[0], "WARN"))
+	assert.True(t, strings.Contains(mockT.Messages[0], "cleanup took 2m0s"))
+	// no report and no logger in context must not panic
+	Warning(context.Background(), Delete, report.WarningTypeSlowCleanup, "cleanup took 2m0s")
+}
+
+func TestRaiseWarning(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Now())
+	mockT := &tlogging.FakeTLogger{}
+	warnings := NewWarningCollector(0)
+	logger := NewLogger(mockT, fakeClock, "test", "step", WithWarnings(warnings))
+	RaiseWarning(logger.WithOperation("apply", report.OperationTypeApply), Apply, report.WarningTypeDeprecatedAPI, "extensions/v1beta1 is deprecated")
+	RaiseWarning(Dedupe(logger, fakeClock).WithFields(map[string]any{"attempt": 3}), Assert, report.WarningTypeLastAttempt, "assert passed on its last attempt")
+	got, dropped := warnings.Warnings()
+	assert.Equal(t, []report.Warning{
+		{Type: report.WarningTypeDeprecatedAPI, Message: "extensions/v1beta1 is deprecated"},
+		{Type: report.WarningTypeLastAttempt, Message: "assert passed on its last attempt"},
+	}, got)
+	assert.Zero(t, dropped)
+	assert.Len(t, mockT.Messages, 2)
+	assert.Contains(t, mockT.Messages[0], "extensions/v1beta1 is deprecated")
+	// loggers without a collector only log warnings
+	RaiseWarning(NewLogger(mockT, fakeClock, "test", "step"), Apply, report.WarningTypeOther, "logged")
+	RaiseWarning(NoOp(), Apply, report.WarningTypeOther, "dropped")
+	got, _ = warnings.Warnings()
+	assert.Len(t, got, 2)
+	assert.Contains(t, mockT.Messages[len(mockT.Messages)-1], "logged")
+}
+
+func TestWarning_Collected(t *testing.T) {
+	warnings := NewWarningCollector(0)
+	logger := NewLogger(&tlogging.FakeTLogger{}, tclock.NewFakePassiveClock(time.Now()), "test", "step", WithWarnings(warnings))
+	testReport := report.NewTest("test")
+	ctx := report.TestIntoContext(IntoContext(context.Background(), logger), testReport)
+	Warning(ctx, Delete, report.WarningTypeSlowCleanup, "cleanup took 2m0s")
+	// collected warnings are added when the test completes, not twice
+	assert.Empty(t, testReport.Warnings)
+	warnings.AddTo(testReport)
+	assert.Equal(t, []report.Warning{{Type: report.WarningTypeSlowCleanup, Message: "cleanup took 2m0s"}}, testReport.Warnings)
+}
+
+func TestWarningCollector(t *testing.T) {
+	warnings := NewWarningCollector(10)
+	logger := NewSinkLogger(sinkFunc(func(Entry) error { return nil }), tclock.NewFakePassiveClock(time.Now()), "test", "step", WithWarnings(warnings))
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 5; j++ {
+				RaiseWarning(logger, Assert, report.WarningTypeOther, fmt.Sprintf("warning %d-%d", i, j))
+			}
+		}(i)
+	}
+	wg.Wait()
+	got, dropped := warnings.Warnings()
+	assert.Len(t, got, 10)
+	assert.Equal(t, 30, dropped)
+	testReport := report.NewTest("test")
+	warnings.AddTo(testReport)
+	assert.Len(t, testReport.Warnings, 11)
+	assert.Equal(t, report.Warning{Type: report.WarningTypeOther, Message: "30 more warnings were dropped"}, testReport.Warnings[10])
+}
@@ -0,0 +1,283 @@
+package runner
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
+	"github.com/kyverno/chainsaw/pkg/discovery"
+	"github.com/kyverno/chainsaw/pkg/report"
+	"github.com/kyverno/chainsaw/pkg/runner/logging"
+	"github.com/kyverno/chainsaw/pkg/runner/processors"
+	"k8s.io/utils/clock"
+)
+
+// loggerOptions returns the options of the loggers created during the run, the returned function closes the files they write to.
+// Secret looking values are redacted from the logs.
+func loggerOptions(config v1alpha1.ConfigurationSpec, values map[string]any) ([]logging.Option, func(), error) {
+	redactor, err := logRedactor(config, values)
+	if err != nil {
+		return nil, nil, err
+	}
+	// the runner picks what the console prints itself, the -v flag it sets is meant for the testing framework
+	options := []logging.Option{logging.WithVerbose(true), logging.WithRedactor(redactor)}
+	if config.LogResourceFormat != "" {
+		format, err := logging.ParseResourceFormat(config.LogResourceFormat)
+		if err != nil {
+			return nil, nil, err
+		}
+		options = append(options, logging.WithResourceFormat(format))
+	}
+	if config.LogResourceList != "" {
+		format, err := logging.ParseResourceListFormat(config.LogResourceList)
+		if err != nil {
+			return nil, nil, err
+		}
+		options = append(options, logging.WithResourceList(format))
+	}
+	if config.LogColumnWidths != "" {
+		widths, err := logging.ParseColumnWidths(config.LogColumnWidths)
+		if err != nil {
+			return nil, nil, err
+		}
+		options = append(options, logging.WithColumnWidths(widths))
+	}
+	if config.LogContinuation != "" {
+		format, err := logging.ParseContinuationFormat(config.LogContinuation)
+		if err != nil {
+			return nil, nil, err
+		}
+		options = append(options, logging.WithContinuation(format))
+	}
+	if config.LogTimestampFormat != "" {
+		layout, err := logging.ParseTimestampLayout(config.LogTimestampFormat)
+		if err != nil {
+			return nil, nil, err
+		}
+		options = append(options, logging.WithTimestampLayout(layout))
+	}
+	if config.LogDeterministic {
+		options = append(options, logging.WithDeterministic())
+	}
+	if config.LogOrdinals {
+		options = append(options, logging.WithOrdinals())
+	}
+	if config.LogMessageMaxSize != nil {
+		options = append(options, logging.WithMessageMaxSize(*config.LogMessageMaxSize))
+	}
+	if config.LogDiffMaxHunks != nil {
+		options = append(options, logging.WithDiffMaxHunks(*config.LogDiffMaxHunks))
+	}
+	if config.LogResourceLinks != "" {
+		links, err := logging.ParseResourceLinks(config.LogResourceLinks)
+		if err != nil {
+			return nil, nil, err
+		}
+		mode, err := logging.ParseHyperlinkMode(config.LogHyperlinks)
+		if err != nil {
+			return nil, nil, err
+		}
+		options = append(options, logging.WithResourceLinks(links, mode))
+	}
+	if config.LogCaller != "" {
+		level, err := logging.ParseCallerLevel(config.LogCaller)
+		if err != nil {
+			return nil, nil, err
+		}
+		options = append(options, logging.WithCaller(level))
+	}
+	types, err := consoleOperationTypes(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(types) != 0 {
+		options = append(options, logging.WithOperationTypes(types...))
+	}
+	if logging.Format(config.LogFormat) == logging.JSONFormat {
+		// JSON lines replace the human readable output on the console
+		options = append(options, logging.WithJSON(logging.NewJSONWriter(stdout)), logging.WithoutText())
+	}
+	var closers []func() error
+	closeLogs := func() {
+		for _, closer := range closers {
+			if err := closer(); err != nil {
+				fmt.Fprintln(stderr, "Error:", err)
+			}
+		}
+	}
+	if config.LogSyslog != "" {
+		sink, err := logging.DialSyslog(config.LogSyslog)
+		if err != nil {
+			// the run is still logged by the other sinks
+			fmt.Fprintln(stderr, "Warning:", err)
+		} else {
+			closers = append(closers, sink.Close)
+			options = append(options, logging.WithSink(sink))
+		}
+	}
+	if config.LogJSONPath != "" {
+		if err := os.MkdirAll(filepath.Dir(config.LogJSONPath), 0o755); err != nil {
+			return nil, nil, fmt.Errorf("failed to create log file folder: %w", err)
+		}
+		file, err := os.Create(config.LogJSONPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create log file: %w", err)
+		}
+		closers = append(closers, file.Close)
+		options = append(options, logging.WithJSON(logging.NewJSONWriter(file)))
+	}
+	if config.LogFile != "" {
+		var tee *logging.Tee
+		var err error
+		rotation := logging.WithRotation(logging.Rotation{
+			MaxSize:    int64(config.LogFileMaxSize) * 1024 * 1024,
+			MaxBackups: config.LogFileMaxBackups,
+			Compress:   config.LogFileCompress,
+		})
+		if config.LogFilePerTest {
+			tee, err = logging.NewPerTestTee(config.LogFile, rotation)
+		} else {
+			tee, err = logging.NewTee(config.LogFile, rotation)
+		}
+		if err != nil {
+			closeLogs()
+			return nil, nil, fmt.Errorf("failed to create log file: %w", err)
+		}
+		closers = append(closers, tee.Close)
+		options = append(options, logging.WithTee(tee))
+	}
+	return options, closeLogs, nil
+}
+
+// consoleOperationTypes returns the operation types whose lines are printed on the console, none when every line is.
+// Unless configured, they are taken from the environment, see logging.OperationTypesEnv.
+func consoleOperationTypes(config v1alpha1.ConfigurationSpec) ([]report.OperationType, error) {
+	if len(config.LogOperationTypes) == 0 {
+		return logging.OperationTypesFromEnv()
+	}
+	types := make([]report.OperationType, 0, len(config.LogOperationTypes))
+	for _, name := range config.LogOperationTypes {
+		operationType, err := logging.ParseOperationType(name)
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, operationType)
+	}
+	return types, nil
+}
+
+// eventStream returns the event stream of the run and a function closing the file it writes to, or nil when it is disabled.
+// Parent folders of the file are created, file descriptors are expected to be opened by the caller of chainsaw.
+func eventStream(config v1alpha1.ConfigurationSpec) (*logging.EventStream, func() error, error) {
+	if config.EventStream == "" {
+		return nil, nil, nil
+	}
+	fd, ok, err := logging.ParseEventStreamFD(config.EventStream)
+	if err != nil {
+		return nil, nil, err
+	}
+	if ok {
+		file := os.NewFile(uintptr(fd), config.EventStream)
+		return logging.NewEventStream(file), file.Close, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(config.EventStream), 0o755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create event stream folder: %w", err)
+	}
+	file, err := os.Create(config.EventStream)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create event stream file: %w", err)
+	}
+	return logging.NewEventStream(file), file.Close, nil
+}
+
+// githubGroups returns the sink grouping test logs in GitHub Actions, writing to out, or nil when it is disabled.
+func githubGroups(config v1alpha1.ConfigurationSpec, out io.Writer) *logging.GitHubGroups {
+	if !githubGroupsEnabled(config) {
+		return nil
+	}
+	return logging.NewGitHubGroups(out, logging.DefaultCaptureLines)
+}
+
+// githubGroupsEnabled returns true if test logs are grouped.
+// Unless configured, groups are enabled when running in GitHub Actions and the console prints text.
+func githubGroupsEnabled(config v1alpha1.ConfigurationSpec) bool {
+	if config.LogGitHubGroups != nil {
+		return *config.LogGitHubGroups
+	}
+	return logging.GitHubActions() && logging.Format(config.LogFormat) != logging.JSONFormat
+}
+
+// failureOutput returns the sink printing the logs of failed tests only to out, or nil when it is disabled.
+func failureOutput(config v1alpha1.ConfigurationSpec, out io.Writer) *logging.FailureOutput {
+	if !failuresOnlyEnabled(config) {
+		return nil
+	}
+	return logging.NewFailureOutput(out, logging.DefaultCaptureLines)
+}
+
+// failuresOnlyEnabled returns true if only the logs of failed tests are printed, when configured or asked by the environment.
+// JSON lines are never filtered, they are parsed by machines.
+func failuresOnlyEnabled(config v1alpha1.ConfigurationSpec) bool {
+	return (config.LogFailuresOnly || logging.FailuresOnly()) && logging.Format(config.LogFormat) != logging.JSONFormat
+}
+
+// orderedOutput returns the sink printing the logs of concurrent tests as contiguous blocks to out, or nil when it is disabled.
+// JSON lines are never buffered, each of them holds the name of its test.
+func orderedOutput(config v1alpha1.ConfigurationSpec, out io.Writer, clock clock.PassiveClock, tests ...discovery.Test) (*logging.OrderedOutput, error) {
+	if !config.LogBuffered || logging.Format(config.LogFormat) == logging.JSONFormat {
+		return nil, nil
+	}
+	order, err := logging.ParseBufferOrder(config.LogBufferOrder)
+	if err != nil {
+		return nil, err
+	}
+	declared := make([]logging.TestKey, 0, len(tests))
+	for _, test := range tests {
+		// the iterations of a test are printed as separate tests
+		if iterations := processors.Iterations(config, test); iterations > 1 {
+			for iteration := 1; iteration <= iterations; iteration++ {
+				declared = append(declared, logging.TestKey{Path: test.BasePath, Name: report.IterationName(test.Name, iteration)})
+			}
+		} else {
+			declared = append(declared, logging.TestKey{Path: test.BasePath, Name: test.Name})
+		}
+	}
+	return logging.NewOrderedOutput(out, clock, order, logging.DefaultBufferLines, declared...), nil
+}
+
+// logRedactor returns the redactor of the run logs: the default patterns, the configured ones,
+// and the string values whose key looks like a secret.
+func logRedactor(config v1alpha1.ConfigurationSpec, values map[string]any) (*logging.Redactor, error) {
+	patterns := logging.DefaultRedactionPatterns()
+	for _, pattern := range config.LogRedactPatterns {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid log redaction pattern: %w", err)
+		}
+		patterns = append(patterns, compiled)
+	}
+	return logging.NewRedactor(secretValues(values, false), patterns...), nil
+}
+
+// secretValues returns the string values found under keys looking like a secret, nested values of a secret key are secrets too.
+func secretValues(value any, secret bool) []string {
+	var secrets []string
+	switch value := value.(type) {
+	case map[string]any:
+		for key, nested := range value {
+			secrets = append(secrets, secretValues(nested, secret || report.DefaultSecretPattern.MatchString(key))...)
+		}
+	case []any:
+		for _, nested := range value {
+			secrets = append(secrets, secretValues(nested, secret)...)
+		}
+	case string:
+		if secret {
+			secrets = append(secrets, value)
+		}
+	}
+	return secrets
+}
@@ -0,0 +1,371 @@
+package runner
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
+	"github.com/kyverno/chainsaw/pkg/discovery"
+	"github.com/kyverno/chainsaw/pkg/report"
+	"github.com/kyverno/chainsaw/pkg/runner/events"
+	"github.com/kyverno/chainsaw/pkg/runner/logging"
+	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	tclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+)
+
+func TestLoggerOptions(t *testing.T) {
+	defer func(out io.Writer) { stdout = out }(stdout)
+	path := filepath.Join(t.TempDir(), "logs", "chainsaw.jsonl")
+	logFile := filepath.Join(t.TempDir(), "chainsaw.log")
+	logDir := t.TempDir()
+	rotatedFile := filepath.Join(t.TempDir(), "rotated.log")
+	tests := []struct {
+		name       string
+		config     v1alpha1.ConfigurationSpec
+		wantText   bool
+		wantStdout bool
+		wantFile   bool
+		wantLog    string
+	}{{
+		name:     "text",
+		config:   v1alpha1.ConfigurationSpec{},
+		wantText: true,
+	}, {
+		name:       "json",
+		config:     v1alpha1.ConfigurationSpec{LogFormat: string(logging.JSONFormat)},
+		wantStdout: true,
+	}, {
+		name:     "text and json file",
+		config:   v1alpha1.ConfigurationSpec{LogFormat: string(logging.TextFormat), LogJSONPath: path},
+		wantText: true,
+		wantFile: true,
+	}, {
+		name:       "json and log file",
+		config:     v1alpha1.ConfigurationSpec{LogFormat: string(logging.JSONFormat), LogFile: logFile},
+		wantStdout: true,
+		wantLog:    logFile,
+	}, {
+		name:     "log file per test",
+		config:   v1alpha1.ConfigurationSpec{LogFile: logDir, LogFilePerTest: true},
+		wantText: true,
+		wantLog:  filepath.Join(logDir, "test.log"),
+	}, {
+		name:     "rotated log file",
+		config:   v1alpha1.ConfigurationSpec{LogFile: rotatedFile, LogFileMaxSize: 1, LogFileMaxBackups: 2, LogFileCompress: true},
+		wantText: true,
+		wantLog:  rotatedFile,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			stdout = &out
+			options, closeLogs, err := loggerOptions(tt.config, nil)
+			assert.NoError(t, err)
+			mockT := &tlogging.FakeTLogger{}
+			logging.NewLogger(mockT, tclock.NewFakePassiveClock(time.Now()), "test", "step", options...).Log(logging.Apply, logging.OkStatus, nil)
+			closeLogs()
+			assert.Equal(t, tt.wantText, len(mockT.Messages) != 0)
+			assert.Equal(t, tt.wantStdout, out.Len() != 0)
+			if tt.wantFile {
+				data, err := os.ReadFile(path)
+				assert.NoError(t, err)
+				assert.Contains(t, string(data), `"operation":"APPLY"`)
+			}
+			if tt.wantLog != "" {
+				data, err := os.ReadFile(tt.wantLog)
+				assert.NoError(t, err)
+				assert.Contains(t, string(data), "| test | step | APPLY")
+			}
+		})
+	}
+}
+
+func TestLoggerOptions_TimestampFormat(t *testing.T) {
+	// typos fail before any test runs
+	_, _, err := loggerOptions(v1alpha1.ConfigurationSpec{LogTimestampFormat: "hh:mm:ss"}, nil)
+	assert.Error(t, err)
+	logFile := filepath.Join(t.TempDir(), "chainsaw.log")
+	options, closeLogs, err := loggerOptions(v1alpha1.ConfigurationSpec{LogTimestampFormat: string(logging.NoTimestamp), LogFile: logFile}, nil)
+	assert.NoError(t, err)
+	mockT := &tlogging.FakeTLogger{}
+	logging.NewLogger(mockT, tclock.NewFakePassiveClock(time.Now()), "test", "step", options...).Log(logging.Apply, logging.OkStatus, nil)
+	closeLogs()
+	data, err := os.ReadFile(logFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "| test | step | APPLY     | OK    |\n", string(data))
+}
+
+func TestLoggerOptions_Deterministic(t *testing.T) {
+	options, closeLogs, err := loggerOptions(v1alpha1.ConfigurationSpec{LogTimestampFormat: "RFC3339", LogDeterministic: true}, nil)
+	assert.NoError(t, err)
+	defer closeLogs()
+	mockT := &tlogging.FakeTLogger{}
+	options = append(options, logging.WithColor(logging.ColorAlways), logging.WithWorker(3))
+	logging.NewLogger(mockT, tclock.NewFakePassiveClock(time.Now()), "test", "step", options...).Log(logging.Apply, logging.OkStatus, nil)
+	assert.Equal(t, "| test | step | APPLY     | OK    |", strings.TrimLeft(mockT.Messages[0], "\b"))
+}
+
+func TestLoggerOptions_Ordinals(t *testing.T) {
+	options, closeLogs, err := loggerOptions(v1alpha1.ConfigurationSpec{LogTimestampFormat: "none", LogOrdinals: true}, nil)
+	assert.NoError(t, err)
+	defer closeLogs()
+	mockT := &tlogging.FakeTLogger{}
+	options = append(options, logging.WithColor(logging.ColorNever), logging.WithStepOrdinal(2, 3))
+	logging.NewLogger(mockT, tclock.NewFakePassiveClock(time.Now()), "test", "deploy      ", options...).Log(logging.Apply, logging.OkStatus, nil)
+	assert.Equal(t, "| test | deploy (2/3) | APPLY     | OK    |", strings.TrimLeft(mockT.Messages[0], "\b"))
+}
+
+func TestConsoleOperationTypes(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  []string
+		env     string
+		want    []report.OperationType
+		wantErr bool
+	}{{
+		name: "none",
+	}, {
+		name:   "configured",
+		config: []string{"script", "command"},
+		env:    "sleep",
+		want:   []report.OperationType{report.OperationTypeScript, report.OperationTypeCommand},
+	}, {
+		name: "environment",
+		env:  "sleep,assert",
+		want: []report.OperationType{report.OperationTypeSleep, report.OperationTypeAssert},
+	}, {
+		name:    "invalid",
+		config:  []string{"shell"},
+		wantErr: true,
+	}, {
+		name:    "invalid environment",
+		env:     "shell",
+		wantErr: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(logging.OperationTypesEnv, tt.env)
+			got, err := consoleOperationTypes(v1alpha1.ConfigurationSpec{LogOperationTypes: tt.config})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLoggerOptions_MessageMaxSize(t *testing.T) {
+	message := strings.Repeat("x", 64)
+	tests := []struct {
+		name    string
+		maxSize *int
+		want    string
+	}{{
+		name: "default",
+		want: message,
+	}, {
+		name:    "truncated",
+		maxSize: ptr.To(16),
+		want:    strings.Repeat("x", 16) + " (truncated, 48 bytes omitted)",
+	}, {
+		name:    "disabled",
+		maxSize: ptr.To(0),
+		want:    message,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options, closeLogs, err := loggerOptions(v1alpha1.ConfigurationSpec{LogMessageMaxSize: tt.maxSize}, nil)
+			assert.NoError(t, err)
+			defer closeLogs()
+			var out bytes.Buffer
+			options = append(options, logging.WithSink(logging.NewWriterSink(&out, false)), logging.WithoutText())
+			logging.NewLogger(&tlogging.FakeTLogger{}, tclock.NewFakePassiveClock(time.Now()), "test", "step", options...).Log(logging.Script, logging.LogStatus, nil, bytes.NewBufferString(message))
+			lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+			assert.Equal(t, tt.want, lines[len(lines)-1])
+		})
+	}
+}
+
+func TestLoggerOptions_DiffMaxHunks(t *testing.T) {
+	options, closeLogs, err := loggerOptions(v1alpha1.ConfigurationSpec{LogDiffMaxHunks: ptr.To(2)}, nil)
+	assert.NoError(t, err)
+	defer closeLogs()
+	logger := logging.NewLogger(&tlogging.FakeTLogger{}, tclock.NewFakePassiveClock(time.Now()), "test", "step", options...)
+	assert.Equal(t, 2, logging.DiffMaxHunks(logger))
+	// the default applies unless configured
+	options, closeLogs, err = loggerOptions(v1alpha1.ConfigurationSpec{}, nil)
+	assert.NoError(t, err)
+	defer closeLogs()
+	logger = logging.NewLogger(&tlogging.FakeTLogger{}, tclock.NewFakePassiveClock(time.Now()), "test", "step", options...)
+	assert.Equal(t, logging.DefaultDiffMaxHunks, logging.DiffMaxHunks(logger))
+}
+
+func TestLoggerOptions_ResourceLinks(t *testing.T) {
+	options, closeLogs, err := loggerOptions(v1alpha1.ConfigurationSpec{LogResourceLinks: "https://dashboard/{{.Name}}", LogHyperlinks: "always"}, nil)
+	assert.NoError(t, err)
+	defer closeLogs()
+	var entries []logging.Entry
+	defer logging.OnEntry(func(entry logging.Entry) { entries = append(entries, entry) })()
+	logging.NewLogger(&tlogging.FakeTLogger{}, tclock.NewFakePassiveClock(time.Now()), "test", "step", options...).Log(logging.Apply, logging.OkStatus, nil)
+	assert.Len(t, entries, 1)
+	assert.NotNil(t, entries[0].ResourceLinks)
+	_, _, err = loggerOptions(v1alpha1.ConfigurationSpec{LogResourceLinks: "https://dashboard/{{.Cluster}}"}, nil)
+	assert.Error(t, err)
+	_, _, err = loggerOptions(v1alpha1.ConfigurationSpec{LogResourceLinks: "https://dashboard/{{.Name}}", LogHyperlinks: "sometimes"}, nil)
+	assert.Error(t, err)
+}
+
+func TestLoggerOptions_Caller(t *testing.T) {
+	options, closeLogs, err := loggerOptions(v1alpha1.ConfigurationSpec{LogCaller: "warn"}, nil)
+	assert.NoError(t, err)
+	defer closeLogs()
+	var entries []logging.Entry
+	defer logging.OnEntry(func(entry logging.Entry) { entries = append(entries, entry) })()
+	logger := logging.NewLogger(&tlogging.FakeTLogger{}, tclock.NewFakePassiveClock(time.Now()), "test", "step", options...)
+	logging.Warn(logger, logging.Apply, logging.WarnStatus)
+	logger.Log(logging.Apply, logging.OkStatus, nil)
+	assert.Len(t, entries, 2)
+	assert.Regexp(t, `^runner/logs_test\.go:\d+$`, entries[0].Caller)
+	assert.Empty(t, entries[1].Caller)
+	_, _, err = loggerOptions(v1alpha1.ConfigurationSpec{LogCaller: "info"}, nil)
+	assert.Error(t, err)
+}
+
+func TestLoggerOptions_Syslog(t *testing.T) {
+	defer func(out, err io.Writer) { stdout, stderr = out, err }(stdout, stderr)
+	var errOut bytes.Buffer
+	stdout, stderr = io.Discard, &errOut
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer conn.Close()
+	options, closeLogs, err := loggerOptions(v1alpha1.ConfigurationSpec{LogSyslog: "udp://" + conn.LocalAddr().String()}, nil)
+	assert.NoError(t, err)
+	logging.NewLogger(&tlogging.FakeTLogger{}, tclock.NewFakePassiveClock(time.Now()), "test", "step", options...).Log(logging.Apply, logging.OkStatus, nil)
+	closeLogs()
+	buf := make([]byte, 4096)
+	assert.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	n, _, err := conn.ReadFrom(buf)
+	assert.NoError(t, err)
+	assert.Contains(t, string(buf[:n]), `[chainsaw@32473 test="test" step="step" operation="APPLY" status="OK"]`)
+	assert.Empty(t, errOut.String())
+	// an unreachable daemon doesn't stop the run
+	options, closeLogs, err = loggerOptions(v1alpha1.ConfigurationSpec{LogSyslog: "unix://" + filepath.Join(t.TempDir(), "missing")}, nil)
+	assert.NoError(t, err)
+	defer closeLogs()
+	assert.NotEmpty(t, options)
+	assert.Contains(t, errOut.String(), "Warning: failed to connect to syslog unix://")
+}
+
+func TestEventStream(t *testing.T) {
+	// disabled by default
+	stream, _, err := eventStream(v1alpha1.ConfigurationSpec{})
+	assert.NoError(t, err)
+	assert.Nil(t, stream)
+	_, _, err = eventStream(v1alpha1.ConfigurationSpec{EventStream: "fd:three"})
+	assert.Error(t, err)
+	// a file descriptor opened by the caller, the stream owns it
+	reader, writer, err := os.Pipe()
+	assert.NoError(t, err)
+	defer reader.Close()
+	fd, err := syscall.Dup(int(writer.Fd()))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+	stream, closeStream, err := eventStream(v1alpha1.ConfigurationSpec{EventStream: fmt.Sprintf("fd:%d", fd)})
+	assert.NoError(t, err)
+	assert.NoError(t, stream.WriteEvent(events.Event{Type: events.TestStarted, Test: "test"}))
+	assert.NoError(t, closeStream())
+	data, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"seq":1,"timestamp":"0001-01-01T00:00:00Z","type":"TestStarted","test":"test"}`+"\n", string(data))
+}
+
+func TestLogRedactor(t *testing.T) {
+	values := map[string]any{
+		"apiToken": "tok-12345",
+		"nested": map[string]any{
+			"password": "pw-abcdef",
+			"name":     "visible-name",
+		},
+		"credentials": []any{"item-1234", map[string]any{"user": "cred-user"}},
+		"count":       42,
+	}
+	redactor, err := logRedactor(v1alpha1.ConfigurationSpec{LogRedactPatterns: []string{`ghp_\w+`}}, values)
+	assert.NoError(t, err)
+	got := redactor.Redact("tok-12345 pw-abcdef visible-name item-1234 cred-user ghp_abc Bearer abc.def")
+	assert.Equal(t, []string{"*** *** visible-name *** *** *** ***"}, got)
+	_, err = logRedactor(v1alpha1.ConfigurationSpec{LogRedactPatterns: []string{"("}}, nil)
+	assert.Error(t, err)
+	_, _, err = loggerOptions(v1alpha1.ConfigurationSpec{LogRedactPatterns: []string{"("}}, nil)
+	assert.Error(t, err)
+}
+
+func TestGitHubGroups(t *testing.T) {
+	assert.Nil(t, githubGroups(v1alpha1.ConfigurationSpec{LogGitHubGroups: ptr.To(false)}, io.Discard))
+	assert.NotNil(t, githubGroups(v1alpha1.ConfigurationSpec{LogGitHubGroups: ptr.To(true)}, io.Discard))
+	t.Setenv(logging.GitHubActionsEnv, "true")
+	assert.NotNil(t, githubGroups(v1alpha1.ConfigurationSpec{}, io.Discard))
+	// JSON lines on the console are not grouped unless configured
+	assert.Nil(t, githubGroups(v1alpha1.ConfigurationSpec{LogFormat: string(logging.JSONFormat)}, io.Discard))
+	assert.Nil(t, githubGroups(v1alpha1.ConfigurationSpec{LogGitHubGroups: ptr.To(false)}, io.Discard))
+	t.Setenv(logging.GitHubActionsEnv, "false")
+	assert.Nil(t, githubGroups(v1alpha1.ConfigurationSpec{}, io.Discard))
+}
+
+func TestFailureOutput(t *testing.T) {
+	assert.Nil(t, failureOutput(v1alpha1.ConfigurationSpec{}, io.Discard))
+	assert.NotNil(t, failureOutput(v1alpha1.ConfigurationSpec{LogFailuresOnly: true}, io.Discard))
+	// JSON lines are never filtered
+	assert.Nil(t, failureOutput(v1alpha1.ConfigurationSpec{LogFailuresOnly: true, LogFormat: string(logging.JSONFormat)}, io.Discard))
+	t.Setenv(logging.FailuresOnlyEnv, "true")
+	assert.NotNil(t, failureOutput(v1alpha1.ConfigurationSpec{}, io.Discard))
+}
+
+func TestOrderedOutput(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Now())
+	output, err := orderedOutput(v1alpha1.ConfigurationSpec{}, io.Discard, fakeClock)
+	assert.NoError(t, err)
+	assert.Nil(t, output)
+	output, err = orderedOutput(v1alpha1.ConfigurationSpec{LogBuffered: true, LogFormat: string(logging.JSONFormat)}, io.Discard, fakeClock)
+	assert.NoError(t, err)
+	assert.Nil(t, output)
+	output, err = orderedOutput(v1alpha1.ConfigurationSpec{LogBuffered: true, LogBufferOrder: "declaration"}, io.Discard, fakeClock)
+	assert.NoError(t, err)
+	assert.NotNil(t, output)
+	_, err = orderedOutput(v1alpha1.ConfigurationSpec{LogBuffered: true, LogBufferOrder: "random"}, io.Discard, fakeClock)
+	assert.Error(t, err)
+}
+
+func TestOrderedOutput_Iterations(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	test := discovery.Test{BasePath: "tests/a", Test: &v1alpha1.Test{ObjectMeta: metav1.ObjectMeta{Name: "test"}}}
+	var out bytes.Buffer
+	output, err := orderedOutput(v1alpha1.ConfigurationSpec{LogBuffered: true, LogBufferOrder: "declaration", Iterations: 2}, &out, fakeClock, test)
+	assert.NoError(t, err)
+	logger := func(test string) logging.Logger {
+		return logging.NewLogger(&tlogging.FakeTLogger{}, fakeClock, test, "step", logging.WithSink(output), logging.WithoutText(), logging.WithTestPath("tests/a"))
+	}
+	first, second := logging.TestKey{Path: "tests/a", Name: "test#1"}, logging.TestKey{Path: "tests/a", Name: "test#2"}
+	output.Start(first)
+	output.Start(second)
+	logger("test#1").Log(logging.Script, logging.LogStatus, nil)
+	logger("test#2").Log(logging.Script, logging.LogStatus, nil)
+	out.Reset()
+	// the iterations are declared, the second one waits for the first one
+	assert.NoError(t, output.Complete(second, false))
+	assert.Empty(t, out.String())
+	assert.NoError(t, output.Complete(first, false))
+	assert.Equal(t, "===== test#2\n| 10:30:00 | test#2 | step | SCRIPT    | LOG   |\n", out.String())
+}
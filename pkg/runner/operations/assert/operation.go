@@ -48,7 +48,7 @@ func (o *operation) Exec(ctx context.Context, bindings binding.Bindings) (_ oper
 	obj := o.base
 	logger := internal.GetLogger(ctx, &obj)
 	defer func() {
-		internal.LogEnd(logger, logging.Assert, _err)
+		internal.LogDiffEnd(ctx, logger, logging.Assert, _err)
 	}()
 	if o.template {
 		if err := template.ResourceRef(ctx, &obj, bindings); err != nil {
@@ -65,13 +65,22 @@ func (o *operation) Exec(ctx context.Context, bindings binding.Bindings) (_ oper
 }
 
 func (o *operation) execute(ctx context.Context, bindings binding.Bindings, obj unstructured.Unstructured) error {
+	logger := internal.GetLogger(ctx, &obj)
+	heartbeat := logging.StartWaitHeartbeat(ctx, logger, logging.Assert)
+	attempt := 0
 	var lastErrs []error
-	err := wait.PollUntilContextCancel(ctx, internal.PollInterval, false, func(ctx context.Context) (_ bool, err error) {
+	err := wait.PollUntilContextCancel(ctx, internal.PollInterval, false, func(ctx context.Context) (done bool, err error) {
+		internal.RecordAttempt(ctx)
+		attempt++
 		var errs []error
 		defer func() {
 			// record last errors only if there was no real error
 			if err == nil {
 				lastErrs = errs
+				if !done {
+					internal.LogAttempt(logger, logging.Assert, attempt, errs)
+					heartbeat.Beat(internal.WaitingFor(&obj), multierr.Combine(errs...))
+				}
 			}
 		}()
 		if obj.GetAPIVersion() == "" || obj.GetKind() == "" {
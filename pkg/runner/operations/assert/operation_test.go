@@ -3,6 +3,7 @@ package assert
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -18,6 +19,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	tclock "k8s.io/utils/clock/testing"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -97,7 +99,9 @@ func Test_operationAssert(t *testing.T) {
 		expectErr: true,
 		expectedLogs: []string{
 			"ASSERT: RUN - []",
-			"ASSERT: ERROR - [=== ERROR\n---------------\nv1/Pod/test-pod\n---------------\n* spec.containers[0].image: Invalid value: \"fake-image\": Expected value: \"test-image\"\n* spec.containers[0].name: Invalid value: \"fake-container\": Expected value: \"test-container\"\n\n--- expected\n+++ actual\n@@ -4,6 +4,6 @@\n   name: test-pod\n spec:\n   containers:\n-  - image: test-image\n-    name: test-container\n+  - image: fake-image\n+    name: fake-container]",
+			"ASSERT: ERROR - [=== ERROR\n---------------\nv1/Pod/test-pod\n---------------\n* spec.containers[0].image: Invalid value: \"fake-image\": Expected value: \"test-image\"\n* spec.containers[0].name: Invalid value: \"fake-container\": Expected value: \"test-container\"]",
+			"ASSERT: ERROR - [=== MISMATCHES\nfield spec.containers[0].image expected \"test-image\", got \"fake-image\"\nfield spec.containers[0].name expected \"test-container\", got \"fake-container\"]",
+			"ASSERT: ERROR - [=== DIFF\n--- expected\n+++ actual\n@@ -4,6 +4,6 @@\n   name: test-pod\n spec:\n   containers:\n-  - image: test-image\n-    name: test-container\n+  - image: fake-image\n+    name: fake-container]",
 		},
 	}, {
 		name: "Not found using Get",
@@ -330,3 +334,66 @@ func Test_operationAssert(t *testing.T) {
 		})
 	}
 }
+
+type sinkFunc func(logging.Entry) error
+
+func (f sinkFunc) WriteEntry(entry logging.Entry) error {
+	return f(entry)
+}
+
+func Test_operationAssert_Heartbeat(t *testing.T) {
+	fakeClock := tclock.NewFakeClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	var heartbeats []string
+	sink := sinkFunc(func(entry logging.Entry) error {
+		if entry.Status == logging.WaitStatus {
+			heartbeats = append(heartbeats, entry.Message)
+		}
+		return nil
+	})
+	attempts := 0
+	client := &tclient.FakeClient{
+		GetFn: func(ctx context.Context, _ int, key ctrlclient.ObjectKey, obj ctrlclient.Object, opts ...ctrlclient.GetOption) error {
+			// every attempt takes as long as the heartbeat interval, the fourth one matches
+			attempts++
+			fakeClock.Step(10 * time.Second)
+			phase := "Pending"
+			if attempts == 4 {
+				phase = "Running"
+			}
+			obj.(*unstructured.Unstructured).Object = map[string]any{
+				"apiVersion": "v1",
+				"kind":       "Pod",
+				"metadata": map[string]any{
+					"name": "test-pod",
+				},
+				"status": map[string]any{
+					"phase": phase,
+				},
+			}
+			return nil
+		},
+	}
+	expected := unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata": map[string]any{
+				"name": "test-pod",
+			},
+			"status": map[string]any{
+				"phase": "Running",
+			},
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	ctx = logging.WithWaitInterval(ctx, 10*time.Second)
+	ctx = logging.IntoContext(ctx, logging.NewSinkLogger(sink, fakeClock, "test", "step"))
+	_, err := New(client, expected, nil, false).Exec(ttesting.IntoContext(ctx, t), nil)
+	assert.NoError(t, err)
+	// no heartbeat is logged once the assert succeeded
+	assert.Len(t, heartbeats, 3)
+	assert.True(t, strings.HasPrefix(heartbeats[0], "still waiting for v1/Pod @ test-pod, elapsed 10s of 1m timeout, last error: "), heartbeats[0])
+	assert.Contains(t, heartbeats[0], `status.phase: Invalid value: "Pending": Expected value: "Running"`)
+	assert.True(t, strings.HasPrefix(heartbeats[2], "still waiting for v1/Pod @ test-pod, elapsed 30s of 1m timeout"), heartbeats[2])
+}
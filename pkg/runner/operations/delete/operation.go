@@ -66,15 +66,15 @@ func (o *operation) Exec(ctx context.Context, bindings binding.Bindings) (_ oper
 		return nil, err
 	}
 	internal.LogStart(logger, logging.Delete)
-	return nil, o.execute(ctx, bindings, obj)
-}
-
-func (o *operation) execute(ctx context.Context, bindings binding.Bindings, obj unstructured.Unstructured) error {
 	resources, err := o.getResourcesToDelete(ctx, obj)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	// a selector can match several resources, the end of the operation is about all of them
+	if len(resources) > 1 {
+		logger = logger.WithResources(internal.Objects(resources)...)
 	}
-	return o.deleteResources(ctx, bindings, resources...)
+	return nil, o.deleteResources(ctx, bindings, resources...)
 }
 
 func (o *operation) getResourcesToDelete(ctx context.Context, obj unstructured.Unstructured) ([]unstructured.Unstructured, error) {
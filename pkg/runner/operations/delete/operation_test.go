@@ -17,6 +17,7 @@ import (
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	tclock "k8s.io/utils/clock/testing"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -185,3 +186,52 @@ func Test_operationDelete(t *testing.T) {
 		})
 	}
 }
+
+type resourcesSink struct {
+	resources [][]string
+}
+
+func (s *resourcesSink) WriteEntry(entry logging.Entry) error {
+	var resources []string
+	if entry.Resource != nil {
+		resources = append(resources, entry.Resource.GetName())
+	}
+	for _, resource := range entry.Resources {
+		resources = append(resources, resource.GetName())
+	}
+	s.resources = append(s.resources, resources)
+	return nil
+}
+
+func Test_operationDelete_Resources(t *testing.T) {
+	var selector unstructured.Unstructured
+	selector.SetAPIVersion("v1")
+	selector.SetKind("Pod")
+	selector.SetLabels(map[string]string{"app": "nginx"})
+	client := &tclient.FakeClient{
+		ListFn: func(_ context.Context, _ int, list ctrlclient.ObjectList, _ ...ctrlclient.ListOption) error {
+			uList := list.(*unstructured.UnstructuredList)
+			for _, name := range []string{"first", "second"} {
+				var pod unstructured.Unstructured
+				pod.SetAPIVersion("v1")
+				pod.SetKind("Pod")
+				pod.SetName(name)
+				uList.Items = append(uList.Items, pod)
+			}
+			return nil
+		},
+		DeleteFn: func(_ context.Context, _ int, _ ctrlclient.Object, _ ...ctrlclient.DeleteOption) error {
+			return nil
+		},
+		GetFn: func(_ context.Context, _ int, key ctrlclient.ObjectKey, obj ctrlclient.Object, _ ...ctrlclient.GetOption) error {
+			return kerrors.NewNotFound(obj.GetObjectKind().GroupVersionKind().GroupVersion().WithResource("pod").GroupResource(), key.Name)
+		},
+	}
+	sink := &resourcesSink{}
+	logger := logging.NewLogger(&tlogging.FakeTLogger{}, tclock.NewFakePassiveClock(time.Now()), "test", "step", logging.WithSink(sink), logging.WithoutText())
+	operation := New(client, selector, nil, false)
+	_, err := operation.Exec(ttesting.IntoContext(logging.IntoContext(context.TODO(), logger), t), nil)
+	assert.NoError(t, err)
+	// the selector starts the operation, the resources matched end it
+	assert.Equal(t, [][]string{{""}, {"first", "second"}}, sink.resources)
+}
@@ -64,13 +64,22 @@ func (o *operation) Exec(ctx context.Context, bindings binding.Bindings) (_ oper
 }
 
 func (o *operation) execute(ctx context.Context, bindings binding.Bindings, obj unstructured.Unstructured) error {
+	logger := internal.GetLogger(ctx, &obj)
+	heartbeat := logging.StartWaitHeartbeat(ctx, logger, logging.Error)
+	attempt := 0
 	var lastErrs []error
-	err := wait.PollUntilContextCancel(ctx, internal.PollInterval, false, func(ctx context.Context) (_ bool, err error) {
+	err := wait.PollUntilContextCancel(ctx, internal.PollInterval, false, func(ctx context.Context) (done bool, err error) {
+		internal.RecordAttempt(ctx)
+		attempt++
 		var errs []error
 		defer func() {
 			// record last errors only if there was no real error
 			if err == nil {
 				lastErrs = errs
+				if !done {
+					internal.LogAttempt(logger, logging.Error, attempt, errs)
+					heartbeat.Beat(internal.WaitingFor(&obj), multierr.Combine(errs...))
+				}
 			}
 		}()
 		if obj.GetAPIVersion() == "" || obj.GetKind() == "" {
@@ -39,7 +39,46 @@ func ResourceError(
 	}
 }
 
+// DiffError is implemented by the errors of assertions holding the expected and actual resources, their diff can be
+// rendered apart from the rest of the error.
+type DiffError interface {
+	error
+	// Summary returns the error without the diff of the resources.
+	Summary() string
+	// YAML returns the YAML of the expected and actual resources compared by the diff.
+	YAML() (string, string, error)
+	// Mismatches returns the fields of the expected resource that the actual resource doesn't match.
+	Mismatches() []diffutils.Mismatch
+}
+
 func (e resourceError) Error() string {
+	lines, expected := e.summary()
+	diff, err := diffutils.PrettyDiff(expected, *e.actual.DeepCopy())
+	if err != nil {
+		lines = append(lines, fmt.Sprintf("* %s", err))
+	} else {
+		lines = append(lines, "", diff)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (e resourceError) Summary() string {
+	lines, _ := e.summary()
+	return strings.Join(lines, "\n")
+}
+
+func (e resourceError) YAML() (string, string, error) {
+	_, expected := e.summary()
+	return diffutils.PrettyYAML(expected, *e.actual.DeepCopy())
+}
+
+func (e resourceError) Mismatches() []diffutils.Mismatch {
+	_, expected := e.summary()
+	return diffutils.Mismatches(expected.UnstructuredContent(), e.actual.UnstructuredContent())
+}
+
+// summary returns the lines of the error before the diff, and the expected resource with the template resolved.
+func (e resourceError) summary() ([]string, unstructured.Unstructured) {
 	var lines []string
 	header := fmt.Sprintf("%s/%s/%s", e.actual.GetAPIVersion(), e.actual.GetKind(), client.Name(client.ObjectKey(&e.actual)))
 	sep := strings.Repeat("-", len(header))
@@ -67,11 +106,5 @@ func (e resourceError) Error() string {
 	if templateErr != nil {
 		lines = append(lines, fmt.Sprintf("* ERROR: failed to compute expected template: %s", templateErr))
 	}
-	diff, err := diffutils.PrettyDiff(expected, *e.actual.DeepCopy())
-	if err != nil {
-		lines = append(lines, fmt.Sprintf("* %s", err))
-	} else {
-		lines = append(lines, "", diff)
-	}
-	return strings.Join(lines, "\n")
+	return lines, expected
 }
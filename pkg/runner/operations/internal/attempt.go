@@ -0,0 +1,13 @@
+package internal
+
+import (
+	"context"
+
+	"github.com/kyverno/chainsaw/pkg/report"
+)
+
+func RecordAttempt(ctx context.Context) {
+	if op := report.OperationFromContext(ctx); op != nil {
+		op.RecordAttemptNow()
+	}
+}
@@ -0,0 +1,30 @@
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/report"
+	"github.com/stretchr/testify/assert"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+func TestRecordAttempt(t *testing.T) {
+	// without an operation report in the context, nothing is recorded
+	RecordAttempt(context.TODO())
+	start := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	fakeClock := tclock.NewFakePassiveClock(start)
+	op := report.NewOperationWithClock("Assert", report.OperationTypeAssert, fakeClock)
+	ctx := report.OperationIntoContext(context.TODO(), op)
+	RecordAttempt(ctx)
+	fakeClock.SetTime(start.Add(250 * time.Millisecond))
+	RecordAttempt(ctx)
+	fakeClock.SetTime(start.Add(time.Second))
+	RecordAttempt(ctx)
+	assert.Equal(t, 3, op.Attempts)
+	assert.Equal(t, start, *op.FirstAttemptAt)
+	assert.Equal(t, start.Add(time.Second), *op.LastAttemptAt)
+	assert.Equal(t, &report.AttemptIntervalStats{Min: "0.250", Max: "0.750", Avg: "0.500"}, op.AttemptIntervalStats)
+	assert.Equal(t, time.Second, op.WaitedFor())
+}
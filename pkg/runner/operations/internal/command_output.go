@@ -3,6 +3,8 @@ package internal
 import (
 	"bytes"
 	"fmt"
+	"io"
+	"os/exec"
 	"strings"
 
 	"github.com/kyverno/chainsaw/pkg/runner/logging"
@@ -21,6 +23,20 @@ func (c *CommandOutput) Err() string {
 	return strings.TrimSpace(c.Stderr.String())
 }
 
+// Stream connects the output of cmd to c, the lines are also logged to logger with operation as the process writes them,
+// stdout lines with the OutStatus and stderr lines with the ErrStatus. The returned function logs the partial lines left,
+// it must be called once cmd exited.
+func (c *CommandOutput) Stream(cmd *exec.Cmd, logger logging.Logger, operation logging.Operation) (flush func()) {
+	stdout := logging.NewStreamWriter(logger, operation, logging.OutStatus)
+	stderr := logging.NewStreamWriter(logger, operation, logging.ErrStatus)
+	cmd.Stdout = io.MultiWriter(&c.Stdout, stdout)
+	cmd.Stderr = io.MultiWriter(&c.Stderr, stderr)
+	return func() {
+		_ = stdout.Flush()
+		_ = stderr.Flush()
+	}
+}
+
 func (c *CommandOutput) Sections() []fmt.Stringer {
 	var sections []fmt.Stringer
 	o := c.Out()
@@ -3,10 +3,14 @@ package internal
 import (
 	"bytes"
 	"fmt"
+	"os/exec"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/kyverno/chainsaw/pkg/runner/logging"
 	"github.com/stretchr/testify/assert"
+	tclock "k8s.io/utils/clock/testing"
 )
 
 func TestCommandOutput(t *testing.T) {
@@ -103,3 +107,35 @@ func TestCommandOutput_Sections(t *testing.T) {
 		})
 	}
 }
+
+type sinkFunc func(logging.Entry) error
+
+func (f sinkFunc) WriteEntry(entry logging.Entry) error {
+	return f(entry)
+}
+
+func TestCommandOutput_Stream(t *testing.T) {
+	var lock sync.Mutex
+	lines := map[logging.Status][]string{}
+	sink := sinkFunc(func(entry logging.Entry) error {
+		lock.Lock()
+		defer lock.Unlock()
+		assert.Equal(t, logging.Script, entry.Operation)
+		lines[entry.Status] = append(lines[entry.Status], entry.Message)
+		return nil
+	})
+	logger := logging.NewSinkLogger(sink, tclock.NewFakePassiveClock(time.Now()), "test", "step")
+	// the streams are interleaved, stderr ends with a partial line
+	cmd := exec.Command("sh", "-c", `for i in 1 2 3; do echo "out $i"; echo "err $i" >&2; done; printf 'out 4\r\n'; printf 'err 4' >&2`)
+	var output CommandOutput
+	flush := output.Stream(cmd, logger, logging.Script)
+	assert.NoError(t, cmd.Run())
+	flush()
+	assert.Equal(t, map[logging.Status][]string{
+		logging.OutStatus: {"out 1", "out 2", "out 3", "out 4"},
+		logging.ErrStatus: {"err 1", "err 2", "err 3", "err 4"},
+	}, lines)
+	// the output is still captured whole
+	assert.Equal(t, "out 1\nout 2\nout 3\nout 4", output.Out())
+	assert.Equal(t, "err 1\nerr 2\nerr 3\nerr 4", output.Err())
+}
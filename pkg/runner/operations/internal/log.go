@@ -2,18 +2,20 @@ package internal
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/kyverno/chainsaw/pkg/report"
 	"github.com/kyverno/chainsaw/pkg/runner/logging"
-	"github.com/kyverno/kyverno/ext/output/color"
+	operrors "github.com/kyverno/chainsaw/pkg/runner/operations/errors"
+	"go.uber.org/multierr"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func GetLogger(ctx context.Context, obj client.Object) logging.Logger {
 	logger := logging.FromContext(ctx)
-	if logger == nil {
-		return logger
-	}
 	if obj != nil {
 		if obj.GetObjectKind().GroupVersionKind().Kind == "" {
 			return logger
@@ -22,18 +24,92 @@ func GetLogger(ctx context.Context, obj client.Object) logging.Logger {
 	return logger.WithResource(obj)
 }
 
+// Objects returns the objects of resources, to attribute log lines to all of them with WithResources.
+func Objects(resources []unstructured.Unstructured) []client.Object {
+	objs := make([]client.Object, 0, len(resources))
+	for i := range resources {
+		objs = append(objs, &resources[i])
+	}
+	return objs
+}
+
 func LogStart(logger logging.Logger, op logging.Operation, args ...fmt.Stringer) {
 	if logger != nil {
-		logger.Log(op, logging.RunStatus, color.BoldFgCyan, args...)
+		logging.Running(logger, op, logging.RunStatus, args...)
 	}
 }
 
 func LogEnd(logger logging.Logger, op logging.Operation, err error) {
 	if logger != nil {
 		if err != nil {
-			logger.Log(op, logging.ErrorStatus, color.BoldRed, logging.ErrSection(err))
+			// failures tell how much of the timeout of the operation was used, if any
+			if progress := logging.TimeoutProgress(logger); progress != "" {
+				logging.Failure(logger, op, logging.ErrorStatus, logging.ErrSection(err), logging.Section("timeout", progress))
+				return
+			}
+			logging.Failure(logger, op, logging.ErrorStatus, logging.ErrSection(err))
+		} else {
+			logging.Success(logger, op, logging.DoneStatus)
+		}
+	}
+}
+
+// LogDiffEnd logs the end of an operation like LogEnd, the errors holding the expected and actual resources are
+// logged with their summary and their mismatches and diffs follow, see logging.LogMismatches and logging.LogDiff. The
+// uncolored mismatches and diffs are recorded in the report of the operation of ctx, if any.
+func LogDiffEnd(ctx context.Context, logger logging.Logger, op logging.Operation, err error) {
+	if logger == nil || err == nil {
+		LogEnd(logger, op, err)
+		return
+	}
+	var errs []error
+	var diffErrs []operrors.DiffError
+	for _, err := range multierr.Errors(err) {
+		var diffErr operrors.DiffError
+		if errors.As(err, &diffErr) {
+			errs = append(errs, errors.New(diffErr.Summary()))
+			diffErrs = append(diffErrs, diffErr)
 		} else {
-			logger.Log(op, logging.DoneStatus, color.BoldGreen)
+			errs = append(errs, err)
+		}
+	}
+	if len(diffErrs) == 0 {
+		LogEnd(logger, op, err)
+		return
+	}
+	LogEnd(logger, op, multierr.Combine(errs...))
+	var diffs []string
+	for _, diffErr := range diffErrs {
+		if mismatches := logging.LogMismatches(logger, op, diffErr.Mismatches()); mismatches != "" {
+			diffs = append(diffs, mismatches)
+		}
+		expected, actual, err := diffErr.YAML()
+		if err != nil {
+			logging.Failure(logger, op, logging.ErrorStatus, logging.ErrSection(err))
+		} else if diff := logging.LogDiff(logger, op, expected, actual); diff != "" {
+			diffs = append(diffs, diff)
+		}
+	}
+	if operationReport := report.OperationFromContext(ctx); operationReport != nil && len(diffs) != 0 {
+		operationReport.SetDiff(strings.Join(diffs, "\n"))
+	}
+}
+
+// WaitingFor returns what a polling operation on obj waits for, the resource or the expectation without a kind.
+func WaitingFor(obj *unstructured.Unstructured) string {
+	if obj.GetAPIVersion() == "" || obj.GetKind() == "" {
+		return "the expectation"
+	}
+	return logging.FormatResource(obj, logging.FullResource)
+}
+
+// LogAttempt logs the errors of a failed polling attempt at the debug level.
+func LogAttempt(logger logging.Logger, op logging.Operation, attempt int, errs []error) {
+	if logger != nil && len(errs) != 0 && logging.Enabled(logging.DebugLevel) {
+		messages := make([]string, 0, len(errs))
+		for _, err := range errs {
+			messages = append(messages, err.Error())
 		}
+		logging.Debug(logger, op, logging.LogStatus, logging.Section(fmt.Sprintf("attempt %d", attempt), strings.Join(messages, "\n")))
 	}
 }
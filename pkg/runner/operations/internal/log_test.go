@@ -4,15 +4,22 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/kyverno/chainsaw/pkg/report"
 	"github.com/kyverno/chainsaw/pkg/runner/logging"
 	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
+	operrors "github.com/kyverno/chainsaw/pkg/runner/operations/errors"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/multierr"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	tclock "k8s.io/utils/clock/testing"
 )
 
 func TestGetLogger(t *testing.T) {
-	assert.Nil(t, GetLogger(context.TODO(), nil))
+	// without a logger in the context, lines are dropped
+	assert.Equal(t, logging.NoOp(), GetLogger(context.TODO(), nil))
 	{
 		logger := &tlogging.FakeLogger{}
 		ctx := logging.IntoContext(context.TODO(), logger)
@@ -32,6 +39,18 @@ func TestGetLogger(t *testing.T) {
 	}
 }
 
+func TestObjects(t *testing.T) {
+	resources := make([]unstructured.Unstructured, 2)
+	resources[0].SetName("first")
+	resources[1].SetName("second")
+	objs := Objects(resources)
+	assert.Len(t, objs, 2)
+	// the objects are the resources themselves, not copies
+	assert.Same(t, &resources[0], objs[0])
+	assert.Same(t, &resources[1], objs[1])
+	assert.Empty(t, Objects(nil))
+}
+
 func TestLogStart(t *testing.T) {
 	logger := &tlogging.FakeLogger{}
 	ctx := logging.IntoContext(context.TODO(), logger)
@@ -70,4 +89,73 @@ func TestLogEnd(t *testing.T) {
 		LogEnd(l, "aaa", errors.New("some error"))
 		assert.Equal(t, []string{"aaa: ERROR - [=== ERROR\nsome error]"}, logger.Logs)
 	}
+	{
+		var entries []logging.Entry
+		fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+		sink := sinkFunc(func(entry logging.Entry) error {
+			entries = append(entries, entry)
+			return nil
+		})
+		l := logging.WithOperationTimeout(logging.NewSinkLogger(sink, fakeClock, "test", "step"), 5*time.Minute)
+		fakeClock.SetTime(fakeClock.Now().Add(130 * time.Second))
+		LogEnd(l, "aaa", errors.New("some error"))
+		assert.Equal(t, "=== ERROR\nsome error\n=== TIMEOUT\nelapsed 2m10s / timeout 5m (2m50s remaining)", entries[0].Message)
+	}
+}
+
+func TestLogAttempt(t *testing.T) {
+	defer logging.SetLevel(logging.GetLevel())
+	errs := []error{errors.New("first"), errors.New("second")}
+	{
+		logging.SetLevel(logging.InfoLevel)
+		logger := &tlogging.FakeLogger{}
+		LogAttempt(logger, "aaa", 1, errs)
+		assert.Nil(t, logger.Logs)
+	}
+	{
+		logging.SetLevel(logging.DebugLevel)
+		logger := &tlogging.FakeLogger{}
+		LogAttempt(logger, "aaa", 2, errs)
+		LogAttempt(logger, "aaa", 3, nil)
+		assert.Equal(t, []string{"aaa: LOG - [=== ATTEMPT 2\nfirst\nsecond]"}, logger.Logs)
+	}
+}
+
+func TestLogDiffEnd(t *testing.T) {
+	expected := unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]any{"name": "quick-start"},
+		"data":       map[string]any{"foo": "bar"},
+	}}
+	actual := *expected.DeepCopy()
+	actual.Object["data"] = map[string]any{"foo": "baz"}
+	errs := field.ErrorList{field.Invalid(field.NewPath("data", "foo"), "baz", `Expected value: "bar"`)}
+	wantMismatches := `field data.foo expected "bar", got "baz"`
+	wantDiff := "--- expected\n+++ actual\n@@ -1,6 +1,6 @@\n apiVersion: v1\n data:\n-  foo: bar\n+  foo: baz\n kind: ConfigMap\n metadata:\n   name: quick-start"
+	{
+		logger := &tlogging.FakeLogger{}
+		operationReport := report.NewOperation("Assert ", report.OperationTypeAssert)
+		ctx := report.OperationIntoContext(context.TODO(), operationReport)
+		LogDiffEnd(ctx, logger, "aaa", multierr.Combine(operrors.ResourceError(expected, actual, false, nil, errs), errors.New("other error")))
+		assert.Equal(t, []string{
+			"aaa: ERROR - [=== ERROR\n------------------------\nv1/ConfigMap/quick-start\n------------------------\n* data.foo: Invalid value: \"baz\": Expected value: \"bar\"\nother error]",
+			"aaa: ERROR - [=== MISMATCHES\n" + wantMismatches + "]",
+			"aaa: ERROR - [=== DIFF\n" + wantDiff + "]",
+		}, logger.Logs)
+		assert.Equal(t, wantMismatches+"\n"+wantDiff, operationReport.Diff)
+	}
+	// errors without resources are logged like LogEnd does
+	{
+		logger := &tlogging.FakeLogger{}
+		operationReport := report.NewOperation("Assert ", report.OperationTypeAssert)
+		LogDiffEnd(report.OperationIntoContext(context.TODO(), operationReport), logger, "aaa", errors.New("some error"))
+		assert.Equal(t, []string{"aaa: ERROR - [=== ERROR\nsome error]"}, logger.Logs)
+		assert.Equal(t, "", operationReport.Diff)
+	}
+	{
+		logger := &tlogging.FakeLogger{}
+		LogDiffEnd(context.TODO(), logger, "aaa", nil)
+		assert.Equal(t, []string{"aaa: DONE - []"}, logger.Logs)
+	}
 }
@@ -0,0 +1,84 @@
+package render
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jmespath-community/go-jmespath/pkg/binding"
+	"github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
+	"github.com/kyverno/chainsaw/pkg/runner/logging"
+	"github.com/kyverno/chainsaw/pkg/runner/mutate"
+	"github.com/kyverno/chainsaw/pkg/runner/namespacer"
+	"github.com/kyverno/chainsaw/pkg/runner/operations"
+	"github.com/kyverno/chainsaw/pkg/runner/operations/internal"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// operation renders a resource without sending it to a cluster, it validates the resources of the operations
+// creating or modifying resources in dry run mode when no cluster is reachable.
+type operation struct {
+	op         logging.Operation
+	base       unstructured.Unstructured
+	namespacer namespacer.Namespacer
+	template   bool
+}
+
+// New returns an operation rendering obj, the lines it logs are those of op.
+func New(
+	op logging.Operation,
+	obj unstructured.Unstructured,
+	namespacer namespacer.Namespacer,
+	template bool,
+) operations.Operation {
+	return &operation{
+		op:         op,
+		base:       obj,
+		namespacer: namespacer,
+		template:   template,
+	}
+}
+
+func (o *operation) Exec(ctx context.Context, bindings binding.Bindings) (_ operations.Outputs, _err error) {
+	if bindings == nil {
+		bindings = binding.NewBindings()
+	}
+	obj := o.base
+	logger := internal.GetLogger(ctx, &obj)
+	defer func() {
+		internal.LogEnd(logger, o.op, _err)
+	}()
+	if o.template {
+		template := v1alpha1.Any{
+			Value: obj.UnstructuredContent(),
+		}
+		if merged, err := mutate.Merge(ctx, obj, bindings, template); err != nil {
+			return nil, err
+		} else {
+			obj = merged
+		}
+	}
+	if err := internal.ApplyNamespacer(o.namespacer, &obj); err != nil {
+		return nil, err
+	}
+	internal.LogStart(logger, o.op)
+	return nil, validate(obj)
+}
+
+// validate checks the fields a cluster needs to identify obj.
+func validate(obj unstructured.Unstructured) error {
+	if obj.GetAPIVersion() == "" {
+		return errors.New("apiVersion must be set")
+	}
+	if _, err := schema.ParseGroupVersion(obj.GetAPIVersion()); err != nil {
+		return fmt.Errorf("invalid apiVersion: %w", err)
+	}
+	if obj.GetKind() == "" {
+		return errors.New("kind must be set")
+	}
+	if obj.GetName() == "" && obj.GetGenerateName() == "" {
+		return errors.New("metadata.name or metadata.generateName must be set")
+	}
+	return nil
+}
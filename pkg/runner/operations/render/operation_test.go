@@ -0,0 +1,92 @@
+package render
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmespath-community/go-jmespath/pkg/binding"
+	"github.com/kyverno/chainsaw/pkg/runner/logging"
+	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_operation_Exec(t *testing.T) {
+	configMap := func(metadata map[string]any) unstructured.Unstructured {
+		return unstructured.Unstructured{
+			Object: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata":   metadata,
+			},
+		}
+	}
+	tests := []struct {
+		name         string
+		object       unstructured.Unstructured
+		template     bool
+		bindings     binding.Bindings
+		wantErr      string
+		expectedLogs []string
+	}{{
+		name:         "valid",
+		object:       configMap(map[string]any{"name": "foo"}),
+		expectedLogs: []string{"CREATE: RUN - []", "CREATE: DONE - []"},
+	}, {
+		name:         "generate name",
+		object:       configMap(map[string]any{"generateName": "foo-"}),
+		expectedLogs: []string{"CREATE: RUN - []", "CREATE: DONE - []"},
+	}, {
+		name:         "template",
+		object:       configMap(map[string]any{"name": "($name)"}),
+		template:     true,
+		bindings:     binding.NewBindings().Register("$name", binding.NewBinding("foo")),
+		expectedLogs: []string{"CREATE: RUN - []", "CREATE: DONE - []"},
+	}, {
+		name:         "template not rendered",
+		object:       configMap(map[string]any{"name": "($name)"}),
+		template:     true,
+		wantErr:      "metadata.name: Internal error: variable not defined: $name",
+		expectedLogs: []string{"CREATE: ERROR - [=== ERROR\nmetadata.name: Internal error: variable not defined: $name]"},
+	}, {
+		name:         "no name",
+		object:       configMap(map[string]any{}),
+		wantErr:      "metadata.name or metadata.generateName must be set",
+		expectedLogs: []string{"CREATE: RUN - []", "CREATE: ERROR - [=== ERROR\nmetadata.name or metadata.generateName must be set]"},
+	}, {
+		name: "no kind",
+		object: unstructured.Unstructured{
+			Object: map[string]any{
+				"apiVersion": "v1",
+				"metadata":   map[string]any{"name": "foo"},
+			},
+		},
+		wantErr:      "kind must be set",
+		expectedLogs: []string{"CREATE: RUN - []", "CREATE: ERROR - [=== ERROR\nkind must be set]"},
+	}, {
+		name: "invalid api version",
+		object: unstructured.Unstructured{
+			Object: map[string]any{
+				"apiVersion": "foo/bar/v1",
+				"kind":       "ConfigMap",
+				"metadata":   map[string]any{"name": "foo"},
+			},
+		},
+		wantErr:      "invalid apiVersion: unexpected GroupVersion string: foo/bar/v1",
+		expectedLogs: []string{"CREATE: RUN - []", "CREATE: ERROR - [=== ERROR\ninvalid apiVersion: unexpected GroupVersion string: foo/bar/v1]"},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := &tlogging.FakeLogger{}
+			operation := New(logging.Create, tt.object, nil, tt.template)
+			outputs, err := operation.Exec(logging.IntoContext(context.Background(), logger), tt.bindings)
+			assert.Nil(t, outputs)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedLogs, logger.Logs)
+		})
+	}
+}
@@ -14,7 +14,6 @@ import (
 	"github.com/kyverno/chainsaw/pkg/runner/operations"
 	"github.com/kyverno/chainsaw/pkg/runner/operations/internal"
 	restutils "github.com/kyverno/chainsaw/pkg/utils/rest"
-	"github.com/kyverno/kyverno/ext/output/color"
 	"k8s.io/client-go/rest"
 )
 
@@ -76,7 +75,7 @@ func (o *operation) createCommand(ctx context.Context, bindings binding.Bindings
 			err := os.Remove(path)
 			if err != nil {
 				logger := internal.GetLogger(ctx, nil)
-				logger.Log(logging.Script, logging.ErrorStatus, color.BoldYellow, logging.ErrSection(err))
+				logging.Warn(logger, logging.Script, logging.ErrorStatus, logging.ErrSection(err))
 			}
 		}
 		defer f.Close()
@@ -94,16 +93,15 @@ func (o *operation) createCommand(ctx context.Context, bindings binding.Bindings
 func (o *operation) execute(ctx context.Context, bindings binding.Bindings, cmd *exec.Cmd) (_outputs operations.Outputs, _err error) {
 	logger := internal.GetLogger(ctx, nil)
 	var output internal.CommandOutput
-	if !o.script.SkipLogOutput {
-		defer func() {
-			if sections := output.Sections(); len(sections) != 0 {
-				logger.Log(logging.Script, logging.LogStatus, color.BoldFgCyan, sections...)
-			}
-		}()
+	flush := func() {}
+	if o.script.SkipLogOutput {
+		cmd.Stdout = &output.Stdout
+		cmd.Stderr = &output.Stderr
+	} else {
+		flush = output.Stream(cmd, logger, logging.Script)
 	}
-	cmd.Stdout = &output.Stdout
-	cmd.Stderr = &output.Stderr
 	err := cmd.Run()
+	flush()
 	bindings = apibindings.RegisterNamedBinding(ctx, bindings, "stdout", output.Out())
 	bindings = apibindings.RegisterNamedBinding(ctx, bindings, "stderr", output.Err())
 	if err == nil {
@@ -0,0 +1,30 @@
+package processors
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmespath-community/go-jmespath/pkg/binding"
+	"github.com/kyverno/chainsaw/pkg/runner/functions"
+	"github.com/kyverno/kyverno-json/pkg/engine/template"
+)
+
+// skipStep evaluates the skipIf expression of a step against the bindings, it returns an error
+// if the expression can't be evaluated or doesn't evaluate to a boolean.
+func skipStep(ctx context.Context, expression string, bindings binding.Bindings) (bool, error) {
+	if expression == "" {
+		return false, nil
+	}
+	if bindings == nil {
+		bindings = binding.NewBindings()
+	}
+	result, err := template.Execute(ctx, expression, nil, bindings, template.WithFunctionCaller(functions.Caller))
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate skipIf (%s): %w", expression, err)
+	}
+	skip, ok := result.(bool)
+	if !ok {
+		return false, fmt.Errorf("skipIf didn't evaluate to a boolean (%s): %v", expression, result)
+	}
+	return skip, nil
+}
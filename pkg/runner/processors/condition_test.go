@@ -0,0 +1,55 @@
+package processors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmespath-community/go-jmespath/pkg/binding"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_skipStep(t *testing.T) {
+	bindings := binding.NewBindings().
+		Register("$env", binding.NewBinding("prod")).
+		Register("$values", binding.NewBinding(map[string]any{"enabled": true}))
+	tests := []struct {
+		name       string
+		expression string
+		want       bool
+		wantErr    bool
+	}{{
+		name: "empty",
+		want: false,
+	}, {
+		name:       "equal",
+		expression: "$env == 'prod'",
+		want:       true,
+	}, {
+		name:       "not equal",
+		expression: "$env == 'dev'",
+		want:       false,
+	}, {
+		name:       "boolean logic",
+		expression: "$values.enabled && $env != 'dev'",
+		want:       true,
+	}, {
+		name:       "not a boolean",
+		expression: "$env",
+		wantErr:    true,
+	}, {
+		name:       "undefined variable",
+		expression: "$missing == 'prod'",
+		wantErr:    true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := skipStep(context.Background(), tt.expression, bindings)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package processors
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+type failFastKey struct{}
+
+// failFast stops the run once a test fails, no test starts after it and the running ones are cancelled
+// if cancel is set.
+type failFast struct {
+	stopped atomic.Bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+// newFailFast returns a fail fast whose stop cancels the running tests if cancel is true.
+func newFailFast(cancel bool) *failFast {
+	f := &failFast{}
+	if cancel {
+		f.ctx, f.cancel = context.WithCancel(context.Background())
+	}
+	return f
+}
+
+// stop records that a test failed, it cancels the running tests if the fail fast was created so.
+func (f *failFast) stop() {
+	if f == nil {
+		return
+	}
+	f.stopped.Store(true)
+	if f.cancel != nil {
+		f.cancel()
+	}
+}
+
+// isStopped returns true if a test failed.
+func (f *failFast) isStopped() bool {
+	return f != nil && f.stopped.Load()
+}
+
+// withCancel returns a copy of ctx cancelled when f stops, withoutFailFast gets the original ctx back.
+func (f *failFast) withCancel(ctx context.Context) (context.Context, context.CancelFunc) {
+	if f == nil || f.ctx == nil {
+		return ctx, func() {}
+	}
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	unregister := context.AfterFunc(f.ctx, cancel)
+	return context.WithValue(ctx, failFastKey{}, parent), func() {
+		unregister()
+		cancel()
+	}
+}
+
+// withoutFailFast returns ctx without the cancellation of the fail fast, with the values of ctx.
+// The catch and finally blocks still run when the test is cancelled.
+func withoutFailFast(ctx context.Context) context.Context {
+	parent, ok := ctx.Value(failFastKey{}).(context.Context)
+	if !ok {
+		return ctx
+	}
+	return valuesContext{Context: parent, values: ctx}
+}
+
+// valuesContext has the cancellation of Context and the values of values.
+type valuesContext struct {
+	context.Context
+	values context.Context
+}
+
+func (c valuesContext) Value(key any) any { return c.values.Value(key) }
@@ -0,0 +1,38 @@
+package processors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type failFastTestKey struct{}
+
+func TestFailFast(t *testing.T) {
+	// without cancel, stopping doesn't cancel the running tests
+	f := newFailFast(false)
+	ctx, cancel := f.withCancel(context.Background())
+	defer cancel()
+	assert.False(t, f.isStopped())
+	f.stop()
+	assert.True(t, f.isStopped())
+	assert.NoError(t, ctx.Err())
+	// with cancel, the running tests are cancelled but not their cleanup
+	f = newFailFast(true)
+	ctx, cancel = f.withCancel(context.Background())
+	defer cancel()
+	ctx = context.WithValue(ctx, failFastTestKey{}, "value")
+	cleanupCtx := withoutFailFast(ctx)
+	f.stop()
+	assert.True(t, f.isStopped())
+	<-ctx.Done()
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+	assert.NoError(t, cleanupCtx.Err())
+	assert.Equal(t, "value", cleanupCtx.Value(failFastTestKey{}))
+	// a nil fail fast never stops
+	var none *failFast
+	none.stop()
+	assert.False(t, none.isStopped())
+	assert.Equal(t, context.Background(), withoutFailFast(context.Background()))
+}
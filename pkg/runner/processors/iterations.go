@@ -0,0 +1,91 @@
+package processors
+
+import (
+	"regexp"
+	"sync"
+
+	"github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
+	"github.com/kyverno/chainsaw/pkg/discovery"
+	"github.com/kyverno/chainsaw/pkg/runner/summary"
+)
+
+// Iterations returns the number of times the test runs, the iterations of the configuration override the ones
+// of the test for the tests matching the iterations filter.
+func Iterations(config v1alpha1.ConfigurationSpec, test discovery.Test) int {
+	if config.Iterations > 0 && matchesIterationsFilter(config, test) {
+		return config.Iterations
+	}
+	if test.Test != nil && test.Spec.Iterations != nil && *test.Spec.Iterations > 0 {
+		return *test.Spec.Iterations
+	}
+	return 1
+}
+
+// matchesIterationsFilter returns true if the name of the test matches the iterations filter, any test does if it is empty.
+func matchesIterationsFilter(config v1alpha1.ConfigurationSpec, test discovery.Test) bool {
+	if config.IterationsFilter == "" {
+		return true
+	}
+	// the filter is checked when the configuration is validated
+	filter, err := regexp.Compile(config.IterationsFilter)
+	if err != nil {
+		return false
+	}
+	return filter.MatchString(test.GetName())
+}
+
+// passRate returns the minimum percentage of passed iterations of the test, the one of the test takes precedence.
+func passRate(config v1alpha1.ConfigurationSpec, test discovery.Test) (int, bool) {
+	if test.Test != nil && test.Spec.PassRate != nil {
+		return *test.Spec.PassRate, true
+	}
+	return config.PassRate, config.PassRate != 0
+}
+
+// iterationResults counts the results of the iterations of a test with a pass rate. The failed iterations are counted
+// once all the iterations completed, as failed if the pass rate is not met and as tolerated otherwise.
+type iterationResults struct {
+	lock       sync.Mutex
+	iterations int
+	passRate   int
+	completed  int
+	skipped    int
+	failed     int
+}
+
+// newIterationResults returns the results of the iterations of the test, nil if it runs once or has no pass rate.
+func newIterationResults(config v1alpha1.ConfigurationSpec, test discovery.Test) *iterationResults {
+	iterations := Iterations(config, test)
+	rate, ok := passRate(config, test)
+	if iterations < 2 || !ok {
+		return nil
+	}
+	return &iterationResults{iterations: iterations, passRate: rate}
+}
+
+// complete records the result of an iteration, the failed iterations are counted in summary after the last one.
+func (r *iterationResults) complete(summary *summary.Summary, skipped, failed bool) {
+	if r == nil {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.completed++
+	if skipped {
+		r.skipped++
+	} else if failed {
+		r.failed++
+	}
+	if r.completed != r.iterations || summary == nil {
+		return
+	}
+	ran := r.completed - r.skipped
+	met := (ran-r.failed)*100 >= r.passRate*ran
+	for i := 0; i < r.failed; i++ {
+		if met {
+			summary.IncTolerated()
+		} else {
+			summary.IncFailed()
+		}
+	}
+}
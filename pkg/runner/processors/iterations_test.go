@@ -0,0 +1,112 @@
+package processors
+
+import (
+	"testing"
+
+	"github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
+	"github.com/kyverno/chainsaw/pkg/discovery"
+	"github.com/kyverno/chainsaw/pkg/runner/summary"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestIterations(t *testing.T) {
+	test := discovery.Test{
+		Test: &v1alpha1.Test{
+			ObjectMeta: metav1.ObjectMeta{Name: "flaky"},
+			Spec:       v1alpha1.TestSpec{Iterations: ptr.To(3)},
+		},
+	}
+	tests := []struct {
+		name   string
+		config v1alpha1.ConfigurationSpec
+		test   discovery.Test
+		want   int
+	}{{
+		name: "once",
+		test: discovery.Test{Test: &v1alpha1.Test{}},
+		want: 1,
+	}, {
+		name: "test iterations",
+		test: test,
+		want: 3,
+	}, {
+		name:   "override",
+		config: v1alpha1.ConfigurationSpec{Iterations: 10},
+		test:   test,
+		want:   10,
+	}, {
+		name:   "override matching the filter",
+		config: v1alpha1.ConfigurationSpec{Iterations: 10, IterationsFilter: "^fla"},
+		test:   test,
+		want:   10,
+	}, {
+		name:   "override not matching the filter",
+		config: v1alpha1.ConfigurationSpec{Iterations: 10, IterationsFilter: "^other$"},
+		test:   test,
+		want:   3,
+	}, {
+		name:   "invalid filter",
+		config: v1alpha1.ConfigurationSpec{Iterations: 10, IterationsFilter: "("},
+		test:   test,
+		want:   3,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Iterations(tt.config, tt.test))
+		})
+	}
+}
+
+func TestIterationResults(t *testing.T) {
+	test := discovery.Test{
+		Test: &v1alpha1.Test{
+			Spec: v1alpha1.TestSpec{Iterations: ptr.To(4), PassRate: ptr.To(75)},
+		},
+	}
+	tests := []struct {
+		name          string
+		results       []bool
+		skipped       int
+		wantFailed    int32
+		wantTolerated int32
+	}{{
+		name:    "all passed",
+		results: []bool{false, false, false, false},
+	}, {
+		name:          "pass rate met",
+		results:       []bool{false, true, false, false},
+		wantTolerated: 1,
+	}, {
+		name:       "pass rate not met",
+		results:    []bool{true, false, true, false},
+		wantFailed: 2,
+	}, {
+		name:       "skipped iterations are left out",
+		results:    []bool{false, false, true},
+		skipped:    1,
+		wantFailed: 1,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s summary.Summary
+			results := newIterationResults(v1alpha1.ConfigurationSpec{}, test)
+			for i := 0; i < tt.skipped; i++ {
+				results.complete(&s, true, false)
+			}
+			for _, failed := range tt.results {
+				// nothing is counted before the last iteration completes
+				assert.Zero(t, s.Failed())
+				results.complete(&s, false, failed)
+			}
+			assert.Equal(t, tt.wantFailed, s.Failed())
+			assert.Equal(t, tt.wantTolerated, s.Tolerated())
+		})
+	}
+	// tests running once or without pass rate count their failures right away
+	assert.Nil(t, newIterationResults(v1alpha1.ConfigurationSpec{PassRate: 50}, discovery.Test{Test: &v1alpha1.Test{}}))
+	assert.Nil(t, newIterationResults(v1alpha1.ConfigurationSpec{}, discovery.Test{Test: &v1alpha1.Test{Spec: v1alpha1.TestSpec{Iterations: ptr.To(4)}}}))
+	// the pass rate of the configuration applies to the tests without one
+	assert.NotNil(t, newIterationResults(v1alpha1.ConfigurationSpec{PassRate: 50}, discovery.Test{Test: &v1alpha1.Test{Spec: v1alpha1.TestSpec{Iterations: ptr.To(4)}}}))
+}
@@ -2,6 +2,8 @@ package processors
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jmespath-community/go-jmespath/pkg/binding"
@@ -9,16 +11,23 @@ import (
 	"github.com/kyverno/chainsaw/pkg/client"
 	"github.com/kyverno/chainsaw/pkg/report"
 	apibindings "github.com/kyverno/chainsaw/pkg/runner/bindings"
+	"github.com/kyverno/chainsaw/pkg/runner/events"
 	"github.com/kyverno/chainsaw/pkg/runner/logging"
 	"github.com/kyverno/chainsaw/pkg/runner/operations"
+	"github.com/kyverno/chainsaw/pkg/runner/retry"
 	"github.com/kyverno/chainsaw/pkg/testing"
-	"github.com/kyverno/kyverno/ext/output/color"
 	"k8s.io/client-go/rest"
 )
 
 type operation struct {
-	info            OperationInfo
+	info OperationInfo
+	// phase is the phase of the step the operation runs in, it is empty outside steps like for cleanup operations
+	phase           report.OperationPhase
 	continueOnError bool
+	// retry determines how the operation is retried on transient errors, it is not retried when nil
+	retry *v1alpha1.Retry
+	// skipReason tells why the operation is skipped, it runs when empty
+	skipReason      string
 	timeout         *time.Duration
 	operation       func(context.Context, binding.Bindings) (operations.Operation, error)
 	operationReport *report.OperationReport
@@ -73,16 +82,89 @@ func newLazyOperation(
 	}
 }
 
+// inPhase returns o running in phase, it is numbered in phase with the id of its info, see OperationInfo.
+func (o operation) inPhase(phase report.OperationPhase) operation {
+	o.phase = phase
+	if o.operationReport != nil {
+		o.operationReport.Phase = phase
+		o.operationReport.Index = o.info.Id
+	}
+	return o
+}
+
 func (o operation) execute(ctx context.Context, bindings binding.Bindings) operations.Outputs {
+	// retried operations share the timeout between their attempts and the delays in between
 	if o.timeout != nil {
 		toCtx, cancel := context.WithTimeout(ctx, *o.timeout)
 		ctx = toCtx
 		defer cancel()
 	}
+	if o.operationReport != nil {
+		ctx = report.OperationIntoContext(ctx, o.operationReport)
+		if o.timeout != nil {
+			o.operationReport.SetTimeout(*o.timeout)
+		}
+	}
+	// everything logged from inside the operation, client calls included, is attributed to it
+	logger := logging.FromContext(ctx)
+	if o.operationReport != nil {
+		logger = logger.WithOperation(strings.TrimSpace(o.operationReport.Name), o.operationReport.OperationType)
+		logger = logging.CorrelateOperation(logger, o.operationReport.CorrelationID)
+	}
+	if o.phase != "" {
+		logger = logging.NumberOperation(logger, o.phase, o.info.Id)
+	}
+	// skipped operations are recorded in the report without running
+	if o.skipReason != "" {
+		if o.operationReport != nil {
+			o.operationReport.MarkOperationSkipped(o.skipReason)
+		}
+		logging.Skip(logger, logging.Internal, logging.SkipStatus, logging.Section("REASON", o.skipReason))
+		return nil
+	}
+	if o.timeout != nil {
+		// the lines tell how much of the timeout enforced above is left
+		logger = logging.WithOperationTimeout(logger, *o.timeout)
+	}
+	logger = logging.OperationStarted(logger)
+	// failures not returned by the operation, like bindings failing to resolve, are reported with handleError
+	var failed bool
+	var opErr error
+	if o.operationReport != nil {
+		name, operationType := strings.TrimSpace(o.operationReport.Name), o.operationReport.OperationType
+		events.Publish(ctx, events.Event{Type: events.OperationStarted, Operation: name, OperationType: operationType})
+		// deferred calls run when the test fails now too
+		defer func() {
+			events.Publish(ctx, events.Event{Type: events.OperationFinished, Operation: name, OperationType: operationType, Failed: failed, Err: opErr})
+		}()
+	}
+	if clock := logging.DedupeClock(ctx); clock != nil {
+		deduper := logging.Dedupe(logger, clock)
+		// repeated lines are counted until the operation ends
+		defer deduper.Flush()
+		logger = deduper
+	}
+	ctx = logging.IntoContext(ctx, logger)
+	// libraries logging with the logr logger of the context attribute their lines to the operation
+	ctx = logging.KlogIntoContext(ctx, logger)
+	// a panic kills the process once it propagates, the lines held by the sinks are written before
+	defer func() {
+		if r := recover(); r != nil {
+			failed, opErr = true, fmt.Errorf("operation panicked: %v", r)
+			logging.Failure(logger, logging.Internal, logging.ErrorStatus, logging.ErrSection(opErr))
+			// sinks report their own errors
+			_ = logging.Close(logger)
+			panic(r)
+		}
+	}()
 	handleError := func(err error) {
+		failed = true
+		if err != nil {
+			opErr = err
+		}
 		t := testing.FromContext(ctx)
 		if err != nil {
-			logging.Log(ctx, logging.Internal, logging.ErrorStatus, color.BoldRed, logging.ErrSection(err))
+			logging.Failure(logging.FromContext(ctx), logging.Internal, logging.ErrorStatus, logging.ErrSection(err))
 		}
 		if o.continueOnError {
 			t.Fail()
@@ -97,14 +179,41 @@ func (o operation) execute(ctx context.Context, bindings binding.Bindings) opera
 	} else if bindings, err := apibindings.RegisterBindings(ctx, bindings, o.variables...); err != nil {
 		handleError(err)
 	} else {
-		outputs, err := operation.Exec(ctx, apibindings.RegisterNamedBinding(ctx, bindings, "operation", o.info))
+		outputs, err := o.exec(ctx, operation, apibindings.RegisterNamedBinding(ctx, bindings, "operation", o.info))
 		if o.operationReport != nil {
 			o.operationReport.MarkOperationEnd(err)
 		}
 		if err != nil {
+			opErr = err
 			handleError(nil)
 		}
 		return outputs
 	}
 	return nil
 }
+
+// exec runs operation, it is attempted again after a delay while it fails with an error of a class it is retried on.
+// Each attempt is recorded in the report of the operation, and ends at the latest when the timeout of the operation does.
+func (o operation) exec(ctx context.Context, operation operations.Operation, bindings binding.Bindings) (operations.Outputs, error) {
+	if o.retry == nil {
+		return operation.Exec(ctx, bindings)
+	}
+	logger := logging.FromContext(ctx)
+	for attempt := 1; ; attempt++ {
+		if o.operationReport != nil {
+			o.operationReport.RecordAttemptNow()
+		}
+		outputs, err := operation.Exec(ctx, bindings)
+		if err == nil || attempt >= o.retry.Attempts || !retry.Retryable(err, o.retry.On...) {
+			return outputs, err
+		}
+		delay := retry.Delay(*o.retry, attempt)
+		logging.Debug(logger, logging.Internal, logging.RetryStatus,
+			logging.Section("RETRY", fmt.Sprintf("attempt %d of %d failed, retrying in %s", attempt, o.retry.Attempts, delay)),
+			logging.ErrSection(err),
+		)
+		if err := retry.Wait(ctx, delay); err != nil {
+			return outputs, err
+		}
+	}
+}
@@ -1,16 +1,33 @@
 package processors
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 	"time"
 
+	"github.com/fatih/color"
 	"github.com/jmespath-community/go-jmespath/pkg/binding"
+	"github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
 	"github.com/kyverno/chainsaw/pkg/report"
+	"github.com/kyverno/chainsaw/pkg/runner/events"
+	"github.com/kyverno/chainsaw/pkg/runner/logging"
+	tlogging "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
 	"github.com/kyverno/chainsaw/pkg/runner/operations"
 	mock "github.com/kyverno/chainsaw/pkg/runner/operations/testing"
 	"github.com/kyverno/chainsaw/pkg/testing"
 	"github.com/stretchr/testify/assert"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+	tclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func TestOperation_Execute(t *testing.T) {
@@ -76,3 +93,500 @@ func TestOperation_Execute(t *testing.T) {
 		})
 	}
 }
+
+func TestOperation_Execute_Logger(t *testing.T) {
+	mockLogger := &recordingLogger{}
+	op := newOperation(
+		OperationInfo{},
+		false,
+		nil,
+		mock.MockOperation{
+			ExecFn: func(ctx context.Context, _ binding.Bindings) (operations.Outputs, error) {
+				logging.FromContext(ctx).Log(logging.Apply, logging.OkStatus, nil)
+				return nil, nil
+			},
+		},
+		report.NewOperation("Apply ", report.OperationTypeApply),
+		nil,
+		nil,
+	)
+	nt := testing.MockT{}
+	ctx := testing.IntoContext(context.Background(), &nt)
+	ctx = logging.IntoContext(ctx, mockLogger)
+	op.execute(ctx, nil)
+	// lines logged from inside the operation are attributed to it
+	assert.Equal(t, []string{"Apply/apply: APPLY"}, mockLogger.derived.logs)
+	assert.Empty(t, mockLogger.logs)
+}
+
+func TestOperation_Execute_Events(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		err  error
+		want events.Event
+	}{{
+		name: "success",
+		want: events.Event{Type: events.OperationFinished, Time: now, Test: "test", Step: "step-1", Operation: "Apply", OperationType: report.OperationTypeApply},
+	}, {
+		name: "failure",
+		err:  errors.New("operation failed"),
+		want: events.Event{Type: events.OperationFinished, Time: now, Test: "test", Step: "step-1", Operation: "Apply", OperationType: report.OperationTypeApply, Failed: true, Err: errors.New("operation failed")},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []events.Event
+			bus := events.NewBusWithClock(tclock.NewFakePassiveClock(now))
+			bus.Subscribe(func(event events.Event) { got = append(got, event) })
+			op := newOperation(
+				OperationInfo{},
+				true,
+				nil,
+				mock.MockOperation{
+					ExecFn: func(_ context.Context, _ binding.Bindings) (operations.Outputs, error) {
+						return nil, tt.err
+					},
+				},
+				report.NewOperation("Apply ", report.OperationTypeApply),
+				nil,
+				nil,
+			)
+			nt := testing.MockT{}
+			ctx := testing.IntoContext(context.Background(), &nt)
+			ctx = events.WithScope(events.IntoContext(ctx, bus), "test", "step-1")
+			op.execute(ctx, nil)
+			assert.Equal(t, []events.Event{
+				{Type: events.OperationStarted, Time: now, Test: "test", Step: "step-1", Operation: "Apply", OperationType: report.OperationTypeApply},
+				tt.want,
+			}, got)
+		})
+	}
+}
+
+func TestOperation_Execute_Panic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chainsaw.log")
+	tee, err := logging.NewTee(path)
+	assert.NoError(t, err)
+	var out bytes.Buffer
+	groups := logging.NewGitHubGroups(&out, 0)
+	groups.Start(logging.TestKey{Name: "test"})
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	logger := logging.NewLogger(&tlogging.FakeTLogger{}, fakeClock, "test", "step-1", logging.WithTee(tee), logging.WithSink(groups), logging.WithoutText(), logging.WithGlyphs(logging.GlyphASCII))
+	ctx := testing.IntoContext(context.Background(), &testing.MockT{})
+	ctx = logging.IntoContext(ctx, logger)
+	op := newOperation(
+		OperationInfo{},
+		false,
+		nil,
+		mock.MockOperation{
+			ExecFn: func(ctx context.Context, _ binding.Bindings) (operations.Outputs, error) {
+				logging.FromContext(ctx).Log(logging.Script, logging.LogStatus, nil, bytes.NewBufferString("last words"))
+				panic("boom")
+			},
+		},
+		report.NewOperation("Script ", report.OperationTypeScript),
+		nil,
+		nil,
+	)
+	// the panic still propagates
+	assert.PanicsWithValue(t, "boom", func() { op.execute(ctx, nil) })
+	// the final lines reach the file, and buffered groups are written
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, `| 10:30:00 | test | step-1 | Script | SCRIPT    | LOG   |
+last words
+[fail] | 10:30:00 | test | step-1 | Script | INTERNAL  | ERROR |
+=== ERROR
+operation panicked: boom
+`, string(data))
+	assert.Contains(t, out.String(), "::group::test\n")
+	assert.Contains(t, out.String(), "last words\n")
+	assert.Contains(t, out.String(), "operation panicked: boom")
+}
+
+// sinkFunc is a logging.Sink calling a function with the entries.
+type sinkFunc func(logging.Entry) error
+
+func (f sinkFunc) WriteEntry(entry logging.Entry) error { return f(entry) }
+
+func TestOperation_Execute_Klog(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Now())
+	var lines []string
+	sink := sinkFunc(func(entry logging.Entry) error {
+		lines = append(lines, fmt.Sprintf("%s/%s: %s", entry.Test, entry.OperationName, entry.Message))
+		return nil
+	})
+	defer logging.InstallKlog(logging.NewSinkLogger(sink, fakeClock, logging.RunnerTest, "@klog"), logging.DefaultVerbosityLevels())()
+	ctx := testing.IntoContext(context.Background(), &testing.MockT{})
+	ctx = logging.IntoContext(ctx, logging.NewSinkLogger(sink, fakeClock, "test", "step-1"))
+	op := newOperation(
+		OperationInfo{},
+		false,
+		nil,
+		mock.MockOperation{
+			ExecFn: func(ctx context.Context, _ binding.Bindings) (operations.Outputs, error) {
+				// libraries logging with the logger of the context are attributed to the operation
+				klog.FromContext(ctx).Info("contextual")
+				klog.Info("global")
+				return nil, nil
+			},
+		},
+		report.NewOperation("Apply ", report.OperationTypeApply),
+		nil,
+		nil,
+	)
+	op.execute(ctx, nil)
+	assert.Equal(t, []string{"test/Apply: contextual", "runner/: global"}, lines)
+}
+
+func TestOperation_Execute_CorrelationIDs(t *testing.T) {
+	// a synthetic run with a test running an operation
+	testsReport := report.NewTests("chainsaw-report")
+	testReport := report.NewTest("test")
+	testsReport.AddTest(testReport)
+	stepReport := report.NewTestSpecStep("step-1")
+	testReport.AddTestStep(stepReport)
+	operationReport := report.NewOperation("Apply ", report.OperationTypeApply)
+	stepReport.AddOperation(operationReport)
+	var logs bytes.Buffer
+	ctx := testing.IntoContext(context.Background(), &testing.MockT{})
+	ctx = logging.WithOptions(ctx, logging.WithJSON(logging.NewJSONWriter(&logs)), logging.WithoutText())
+	ctx = logging.WithOptions(ctx, logging.WithTestCorrelationID(testReport.CorrelationID))
+	ctx = logging.IntoContext(ctx, logging.NewLogger(&tlogging.FakeTLogger{}, tclock.NewFakePassiveClock(time.Now()), "test", "step-1", logging.OptionsFromContext(ctx)...))
+	op := newOperation(
+		OperationInfo{},
+		false,
+		nil,
+		mock.MockOperation{
+			ExecFn: func(ctx context.Context, _ binding.Bindings) (operations.Outputs, error) {
+				logging.FromContext(ctx).Log(logging.Apply, logging.OkStatus, nil)
+				return nil, nil
+			},
+		},
+		operationReport,
+		nil,
+		nil,
+	)
+	op.execute(ctx, nil)
+	testsReport.Close()
+	var line logging.JSONLine
+	assert.NoError(t, json.Unmarshal(logs.Bytes(), &line))
+	data, err := report.JSONSerializer{}.Serialize(testsReport)
+	assert.NoError(t, err)
+	var serialized struct {
+		Tests []struct {
+			CorrelationID string `json:"correlationId"`
+			Steps         []struct {
+				Results []struct {
+					CorrelationID string `json:"correlationId"`
+				} `json:"results"`
+			} `json:"testcase"`
+		} `json:"testsuite"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &serialized))
+	// the log line is joined with the report by its identifiers
+	assert.NotEmpty(t, line.TestID)
+	assert.NotEmpty(t, line.OperationID)
+	assert.NotEqual(t, line.TestID, line.OperationID)
+	assert.Equal(t, serialized.Tests[0].CorrelationID, line.TestID)
+	assert.Equal(t, serialized.Tests[0].Steps[0].Results[0].CorrelationID, line.OperationID)
+}
+
+func TestOperation_Execute_Dedupe(t *testing.T) {
+	mockLogger := &recordingLogger{}
+	op := newOperation(
+		OperationInfo{},
+		false,
+		nil,
+		mock.MockOperation{
+			ExecFn: func(ctx context.Context, _ binding.Bindings) (operations.Outputs, error) {
+				for i := 0; i < 3; i++ {
+					logging.FromContext(ctx).Log(logging.Assert, logging.ErrorStatus, nil)
+				}
+				return nil, nil
+			},
+		},
+		report.NewOperation("Assert", report.OperationTypeAssert),
+		nil,
+		nil,
+	)
+	nt := testing.MockT{}
+	ctx := testing.IntoContext(context.Background(), &nt)
+	ctx = logging.IntoContext(ctx, mockLogger)
+	ctx = logging.WithDedupe(ctx, tclock.NewFakePassiveClock(time.Now()))
+	op.execute(ctx, nil)
+	// the repeated lines are collapsed, the count is written when the operation ends
+	assert.Equal(t, []string{"Assert/assert: ASSERT", "Assert/assert: ASSERT"}, mockLogger.derived.logs)
+}
+
+// recordingLogger records the lines it logs with the operation it was derived for.
+type recordingLogger struct {
+	name          string
+	operationType report.OperationType
+	logs          []string
+	derived       *recordingLogger
+}
+
+func (l *recordingLogger) Log(operation logging.Operation, status logging.Status, color *color.Color, args ...fmt.Stringer) {
+	l.LogLevel(logging.InfoLevel, operation, status, color, args...)
+}
+
+func (l *recordingLogger) LogLevel(_ logging.Level, operation logging.Operation, _ logging.Status, _ *color.Color, _ ...fmt.Stringer) {
+	l.logs = append(l.logs, fmt.Sprintf("%s/%s: %s", l.name, l.operationType, operation))
+}
+
+func (l *recordingLogger) WithResource(ctrlclient.Object) logging.Logger {
+	return l
+}
+
+func (l *recordingLogger) WithResources(...ctrlclient.Object) logging.Logger {
+	return l
+}
+
+func (l *recordingLogger) WithFields(map[string]any) logging.Logger {
+	return l
+}
+
+func (l *recordingLogger) WithField(string, any) logging.Logger {
+	return l
+}
+
+func (l *recordingLogger) WithOperation(name string, operationType report.OperationType) logging.Logger {
+	l.derived = &recordingLogger{name: name, operationType: operationType}
+	return l.derived
+}
+
+func TestOperation_InPhase(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	mockT := &tlogging.FakeTLogger{}
+	logger := logging.NewLogger(mockT, fakeClock, "test", "step 1/2", logging.WithStepOrdinal(1, 2), logging.WithOrdinals(), logging.WithColor(logging.ColorNever))
+	ctx := logging.IntoContext(testing.IntoContext(context.Background(), &testing.MockT{}), logger)
+	operationReport := report.NewOperation("Describe ", report.OperationTypeCommand)
+	op := newOperation(
+		OperationInfo{Id: 2},
+		true,
+		nil,
+		mock.MockOperation{
+			ExecFn: func(ctx context.Context, _ binding.Bindings) (operations.Outputs, error) {
+				logging.FromContext(ctx).Log(logging.Command, logging.LogStatus, nil)
+				return nil, nil
+			},
+		},
+		operationReport,
+		nil,
+		nil,
+	).inPhase(report.OperationPhaseCatch)
+	assert.Equal(t, report.OperationPhaseCatch, operationReport.Phase)
+	assert.Equal(t, 2, operationReport.Index)
+	op.execute(ctx, nil)
+	assert.Contains(t, mockT.Messages[0], "| test | step 1/2 | Describe (catch 2) | CMD       | LOG   |")
+}
+
+func TestOperation_Execute_Timeout(t *testing.T) {
+	fakeClock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	tests := []struct {
+		name    string
+		timeout *time.Duration
+		retry   *v1alpha1.Retry
+		want    string
+	}{{
+		name: "none",
+		want: "",
+	}, {
+		name:    "resolved",
+		timeout: ptr.To(5 * time.Minute),
+		want:    "elapsed 0s / timeout 5m (5m remaining)",
+	}, {
+		name:    "retried",
+		timeout: ptr.To(5 * time.Minute),
+		retry:   &v1alpha1.Retry{Attempts: 2},
+		want:    "elapsed 0s / timeout 5m (5m remaining)",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := testing.IntoContext(context.Background(), &testing.MockT{})
+			ctx = logging.IntoContext(ctx, logging.NewLogger(&tlogging.FakeTLogger{}, fakeClock, "test", "step-1"))
+			var progress string
+			op := newOperation(
+				OperationInfo{},
+				false,
+				tt.timeout,
+				mock.MockOperation{
+					ExecFn: func(ctx context.Context, _ binding.Bindings) (operations.Outputs, error) {
+						progress = logging.TimeoutProgress(logging.FromContext(ctx))
+						return nil, nil
+					},
+				},
+				report.NewOperation("Assert ", report.OperationTypeAssert),
+				nil,
+				nil,
+			)
+			op.retry = tt.retry
+			op.execute(ctx, nil)
+			assert.Equal(t, tt.want, progress)
+		})
+	}
+}
+
+func TestOperation_Execute_Retry(t *testing.T) {
+	conflict := kerrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, "foo", errors.New("the object has been modified"))
+	tests := []struct {
+		name         string
+		retry        *v1alpha1.Retry
+		errs         []error
+		wantCalls    int
+		wantAttempts int
+		wantFail     bool
+	}{{
+		name:      "no retry",
+		errs:      []error{conflict, nil},
+		wantCalls: 1,
+		wantFail:  true,
+	}, {
+		name:         "retried until success",
+		retry:        &v1alpha1.Retry{Attempts: 3, Interval: &metav1.Duration{Duration: time.Millisecond}},
+		errs:         []error{conflict, conflict, nil},
+		wantCalls:    3,
+		wantAttempts: 3,
+	}, {
+		name:         "attempts exhausted",
+		retry:        &v1alpha1.Retry{Attempts: 2, Interval: &metav1.Duration{Duration: time.Millisecond}},
+		errs:         []error{conflict, conflict, nil},
+		wantCalls:    2,
+		wantAttempts: 2,
+		wantFail:     true,
+	}, {
+		name:         "not transient",
+		retry:        &v1alpha1.Retry{Attempts: 3, Interval: &metav1.Duration{Duration: time.Millisecond}},
+		errs:         []error{errors.New("boom"), nil},
+		wantCalls:    1,
+		wantAttempts: 1,
+		wantFail:     true,
+	}, {
+		name:         "unlisted class",
+		retry:        &v1alpha1.Retry{Attempts: 3, Interval: &metav1.Duration{Duration: time.Millisecond}, On: []v1alpha1.RetryOn{v1alpha1.RetryOnNetworkError}},
+		errs:         []error{conflict, nil},
+		wantCalls:    1,
+		wantAttempts: 1,
+		wantFail:     true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			operationReport := report.NewOperation("Apply ", report.OperationTypeApply)
+			op := newOperation(
+				OperationInfo{},
+				false,
+				ptr.To(time.Second),
+				mock.MockOperation{
+					ExecFn: func(ctx context.Context, _ binding.Bindings) (operations.Outputs, error) {
+						err := tt.errs[calls]
+						calls++
+						return nil, err
+					},
+				},
+				operationReport,
+				nil,
+				nil,
+			)
+			op.retry = tt.retry
+			mockLogger := &recordingLogger{}
+			nt := testing.MockT{}
+			ctx := logging.IntoContext(testing.IntoContext(context.Background(), &nt), mockLogger)
+			op.execute(ctx, nil)
+			assert.Equal(t, tt.wantCalls, calls)
+			assert.Equal(t, tt.wantAttempts, operationReport.Attempts)
+			assert.Equal(t, tt.wantFail, nt.FailedVar)
+			// each retry is logged
+			assert.Len(t, mockLogger.derived.logs, max(tt.wantAttempts-1, 0))
+		})
+	}
+}
+
+func TestOperation_Execute_RetryTimeout(t *testing.T) {
+	conflict := kerrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, "foo", errors.New("the object has been modified"))
+	now := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	operationReport := report.NewOperationWithClock("Apply ", report.OperationTypeApply, tclock.NewFakePassiveClock(now))
+	var deadlines []time.Time
+	op := newOperation(
+		OperationInfo{},
+		false,
+		ptr.To(time.Minute),
+		mock.MockOperation{
+			ExecFn: func(ctx context.Context, _ binding.Bindings) (operations.Outputs, error) {
+				deadline, ok := ctx.Deadline()
+				assert.True(t, ok)
+				deadlines = append(deadlines, deadline)
+				return nil, conflict
+			},
+		},
+		operationReport,
+		nil,
+		nil,
+	)
+	op.retry = &v1alpha1.Retry{Attempts: 3, Interval: &metav1.Duration{Duration: time.Millisecond}}
+	nt := testing.MockT{}
+	ctx := logging.IntoContext(testing.IntoContext(context.Background(), &nt), &tlogging.FakeLogger{})
+	op.execute(ctx, nil)
+	assert.True(t, nt.FailedVar)
+	// the attempts share the deadline of the operation
+	assert.Len(t, deadlines, 3)
+	assert.Equal(t, deadlines[0], deadlines[1])
+	assert.Equal(t, deadlines[0], deadlines[2])
+	// the attempts are stamped with the clock of the report
+	assert.Equal(t, 3, operationReport.Attempts)
+	assert.Equal(t, now, *operationReport.FirstAttemptAt)
+	assert.Equal(t, now, *operationReport.LastAttemptAt)
+}
+
+func TestOperation_Execute_RetryTimeoutExpired(t *testing.T) {
+	conflict := kerrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, "foo", errors.New("the object has been modified"))
+	calls := 0
+	op := newOperation(
+		OperationInfo{},
+		false,
+		ptr.To(50*time.Millisecond),
+		mock.MockOperation{
+			ExecFn: func(ctx context.Context, _ binding.Bindings) (operations.Outputs, error) {
+				calls++
+				return nil, conflict
+			},
+		},
+		report.NewOperation("Apply ", report.OperationTypeApply),
+		nil,
+		nil,
+	)
+	// the delay before the retry outlasts the timeout of the operation
+	op.retry = &v1alpha1.Retry{Attempts: 3, Interval: &metav1.Duration{Duration: time.Minute}}
+	nt := testing.MockT{}
+	ctx := logging.IntoContext(testing.IntoContext(context.Background(), &nt), &tlogging.FakeLogger{})
+	start := time.Now()
+	op.execute(ctx, nil)
+	assert.Less(t, time.Since(start), 10*time.Second)
+	assert.Equal(t, 1, calls)
+	assert.True(t, nt.FailedVar)
+}
+
+func TestOperation_Execute_Skipped(t *testing.T) {
+	operationReport := report.NewOperation("Script ", report.OperationTypeScript)
+	op := newOperation(
+		OperationInfo{},
+		false,
+		nil,
+		mock.MockOperation{
+			ExecFn: func(context.Context, binding.Bindings) (operations.Outputs, error) {
+				return nil, errors.New("ran")
+			},
+		},
+		operationReport,
+		nil,
+		nil,
+	)
+	op.skipReason = dryRunSkipReason
+	nt := testing.MockT{}
+	ctx := logging.IntoContext(testing.IntoContext(context.Background(), &nt), &tlogging.FakeLogger{})
+	assert.Nil(t, op.execute(ctx, nil))
+	assert.False(t, nt.FailedVar)
+	assert.Equal(t, "Skipped", operationReport.Result)
+	assert.Equal(t, dryRunSkipReason, operationReport.Message)
+}
@@ -0,0 +1,25 @@
+package processors
+
+import (
+	"slices"
+
+	"github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
+	"github.com/kyverno/chainsaw/pkg/discovery"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// quarantined returns true if the test is known to be flaky, either listed by name or matching the quarantine selector.
+func quarantined(config v1alpha1.ConfigurationSpec, test discovery.Test) bool {
+	if slices.Contains(config.Quarantine, test.Name) {
+		return true
+	}
+	if config.QuarantineSelector == "" {
+		return false
+	}
+	// the selector is checked when the configuration is validated
+	selector, err := labels.Parse(config.QuarantineSelector)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(test.Labels))
+}
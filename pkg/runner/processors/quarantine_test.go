@@ -0,0 +1,54 @@
+package processors
+
+import (
+	"testing"
+
+	"github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
+	"github.com/kyverno/chainsaw/pkg/discovery"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_quarantined(t *testing.T) {
+	test := discovery.Test{
+		Test: &v1alpha1.Test{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "flaky",
+				Labels: map[string]string{"team": "core"},
+			},
+		},
+	}
+	tests := []struct {
+		name   string
+		config v1alpha1.ConfigurationSpec
+		want   bool
+	}{{
+		name: "no quarantine",
+		want: false,
+	}, {
+		name:   "by name",
+		config: v1alpha1.ConfigurationSpec{Quarantine: []string{"other", "flaky"}},
+		want:   true,
+	}, {
+		name:   "other names",
+		config: v1alpha1.ConfigurationSpec{Quarantine: []string{"other"}},
+		want:   false,
+	}, {
+		name:   "by selector",
+		config: v1alpha1.ConfigurationSpec{QuarantineSelector: "team=core"},
+		want:   true,
+	}, {
+		name:   "selector not matching",
+		config: v1alpha1.ConfigurationSpec{QuarantineSelector: "team=ui"},
+		want:   false,
+	}, {
+		name:   "invalid selector",
+		config: v1alpha1.ConfigurationSpec{QuarantineSelector: "team in"},
+		want:   false,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, quarantined(tt.config, test))
+		})
+	}
+}
@@ -3,6 +3,7 @@ package processors
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/url"
 	"path/filepath"
 	"time"
@@ -26,13 +27,13 @@ import (
 	opdelete "github.com/kyverno/chainsaw/pkg/runner/operations/delete"
 	operror "github.com/kyverno/chainsaw/pkg/runner/operations/error"
 	oppatch "github.com/kyverno/chainsaw/pkg/runner/operations/patch"
+	oprender "github.com/kyverno/chainsaw/pkg/runner/operations/render"
 	opscript "github.com/kyverno/chainsaw/pkg/runner/operations/script"
 	opsleep "github.com/kyverno/chainsaw/pkg/runner/operations/sleep"
 	opupdate "github.com/kyverno/chainsaw/pkg/runner/operations/update"
 	runnertemplate "github.com/kyverno/chainsaw/pkg/runner/template"
 	"github.com/kyverno/chainsaw/pkg/runner/timeout"
 	"github.com/kyverno/chainsaw/pkg/testing"
-	"github.com/kyverno/kyverno/ext/output/color"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/rest"
@@ -42,6 +43,9 @@ import (
 // TODO
 // - create if not exists
 
+// dryRunSkipReason is the message of the operations skipped in dry run mode.
+const dryRunSkipReason = "skipped in dry run mode"
+
 type StepProcessor interface {
 	Run(context.Context, binding.Bindings)
 }
@@ -91,34 +95,52 @@ func (p *stepProcessor) Run(ctx context.Context, bindings binding.Bindings) {
 	bindings = apibindings.RegisterClusterBindings(ctx, bindings, config, cluster)
 	bindings, err := apibindings.RegisterBindings(ctx, bindings, p.step.Bindings...)
 	if err != nil {
-		logging.Log(ctx, logging.Internal, logging.ErrorStatus, color.BoldRed, logging.ErrSection(err))
+		logging.Failure(logger, logging.Internal, logging.ErrorStatus, logging.ErrSection(err))
 		t.FailNow()
 	}
+	if p.step.SkipIf != "" {
+		skip, err := skipStep(ctx, p.step.SkipIf, bindings)
+		if err != nil {
+			logging.Failure(logger, logging.Internal, logging.ErrorStatus, logging.ErrSection(err))
+			t.FailNow()
+		}
+		logging.Debug(logger, logging.Internal, logging.LogStatus, logging.Section("SKIP IF", fmt.Sprintf("%s: %t", p.step.SkipIf, skip)))
+		if skip {
+			reason := fmt.Sprintf("skipIf: %s", p.step.SkipIf)
+			if p.stepReport != nil {
+				p.stepReport.MarkSkipped(reason)
+			}
+			logging.Skip(logger, logging.Internal, logging.SkipStatus, logging.Section("REASON", reason))
+			return
+		}
+	}
 	try, err := p.tryOperations()
 	if err != nil {
-		logger.Log(logging.Try, logging.ErrorStatus, color.BoldRed, logging.ErrSection(err))
+		logging.Failure(logger, logging.Try, logging.ErrorStatus, logging.ErrSection(err))
 		t.FailNow()
 	}
 	catch, err := p.catchOperations()
 	if err != nil {
-		logger.Log(logging.Catch, logging.ErrorStatus, color.BoldRed, logging.ErrSection(err))
+		logging.Failure(logger, logging.Catch, logging.ErrorStatus, logging.ErrSection(err))
 		t.FailNow()
 	}
 	finally, err := p.finallyOperations()
 	if err != nil {
-		logger.Log(logging.Finally, logging.ErrorStatus, color.BoldRed, logging.ErrSection(err))
+		logging.Failure(logger, logging.Finally, logging.ErrorStatus, logging.ErrSection(err))
 		t.FailNow()
 	}
+	// catch and finally run when the test is cancelled in fail fast mode
+	cleanupCtx := withoutFailFast(ctx)
 	if len(catch) != 0 {
 		defer func() {
 			if t.Failed() {
 				t.Cleanup(func() {
-					logger.Log(logging.Catch, logging.RunStatus, color.BoldFgCyan)
+					logging.Running(logger, logging.Catch, logging.RunStatus)
 					defer func() {
-						logger.Log(logging.Catch, logging.DoneStatus, color.BoldFgCyan)
+						logging.Running(logger, logging.Catch, logging.DoneStatus)
 					}()
 					for _, operation := range catch {
-						operation.execute(ctx, bindings)
+						operation.execute(cleanupCtx, bindings)
 					}
 				})
 			}
@@ -127,19 +149,19 @@ func (p *stepProcessor) Run(ctx context.Context, bindings binding.Bindings) {
 	if len(finally) != 0 {
 		defer func() {
 			t.Cleanup(func() {
-				logger.Log(logging.Finally, logging.RunStatus, color.BoldFgCyan)
+				logging.Running(logger, logging.Finally, logging.RunStatus)
 				defer func() {
-					logger.Log(logging.Finally, logging.DoneStatus, color.BoldFgCyan)
+					logging.Running(logger, logging.Finally, logging.DoneStatus)
 				}()
 				for _, operation := range finally {
-					operation.execute(ctx, bindings)
+					operation.execute(cleanupCtx, bindings)
 				}
 			})
 		}()
 	}
-	logger.Log(logging.Try, logging.RunStatus, color.BoldFgCyan)
+	logging.Running(logger, logging.Try, logging.RunStatus)
 	defer func() {
-		logger.Log(logging.Try, logging.DoneStatus, color.BoldFgCyan)
+		logging.Running(logger, logging.Try, logging.DoneStatus)
 	}()
 	for _, operation := range try {
 		for k, v := range operation.execute(ctx, bindings) {
@@ -151,23 +173,29 @@ func (p *stepProcessor) Run(ctx context.Context, bindings binding.Bindings) {
 func (p *stepProcessor) tryOperations() ([]operation, error) {
 	var ops []operation
 	for i, handler := range p.step.Try {
+		// only the operations creating or modifying resources are validated in dry run mode
+		validated := handler.Apply != nil || handler.Create != nil || handler.Patch != nil || handler.Update != nil
 		register := func(o ...operation) {
 			continueOnError := handler.ContinueOnError != nil && *handler.ContinueOnError
 			for _, o := range o {
 				o.continueOnError = continueOnError
-				ops = append(ops, o)
+				o.retry = handler.Retry
+				if p.config.DryRun && !validated {
+					o.skipReason = dryRunSkipReason
+				}
+				ops = append(ops, o.inPhase(report.OperationPhaseTry))
 			}
 		}
 		if handler.Apply != nil {
 			loaded, err := p.applyOperation(i+1, *handler.Apply)
 			if err != nil {
-				return nil, err
+				return nil, fmt.Errorf("operation %d: %w", i+1, err)
 			}
 			register(loaded...)
 		} else if handler.Assert != nil {
 			loaded, err := p.assertOperation(i+1, *handler.Assert)
 			if err != nil {
-				return nil, err
+				return nil, fmt.Errorf("operation %d: %w", i+1, err)
 			}
 			register(loaded...)
 		} else if handler.Command != nil {
@@ -175,7 +203,7 @@ func (p *stepProcessor) tryOperations() ([]operation, error) {
 		} else if handler.Create != nil {
 			loaded, err := p.createOperation(i+1, *handler.Create)
 			if err != nil {
-				return nil, err
+				return nil, fmt.Errorf("operation %d: %w", i+1, err)
 			}
 			register(loaded...)
 		} else if handler.Delete != nil {
@@ -184,13 +212,13 @@ func (p *stepProcessor) tryOperations() ([]operation, error) {
 		} else if handler.Error != nil {
 			loaded, err := p.errorOperation(i+1, *handler.Error)
 			if err != nil {
-				return nil, err
+				return nil, fmt.Errorf("operation %d: %w", i+1, err)
 			}
 			register(loaded...)
 		} else if handler.Patch != nil {
 			loaded, err := p.patchOperation(i+1, *handler.Patch)
 			if err != nil {
-				return nil, err
+				return nil, fmt.Errorf("operation %d: %w", i+1, err)
 			}
 			register(loaded...)
 		} else if handler.Script != nil {
@@ -200,7 +228,7 @@ func (p *stepProcessor) tryOperations() ([]operation, error) {
 		} else if handler.Update != nil {
 			loaded, err := p.updateOperation(i+1, *handler.Update)
 			if err != nil {
-				return nil, err
+				return nil, fmt.Errorf("operation %d: %w", i+1, err)
 			}
 			register(loaded...)
 		} else if handler.Wait != nil {
@@ -217,7 +245,10 @@ func (p *stepProcessor) catchOperations() ([]operation, error) {
 	register := func(o ...operation) {
 		for _, o := range o {
 			o.continueOnError = true
-			ops = append(ops, o)
+			if p.config.DryRun {
+				o.skipReason = dryRunSkipReason
+			}
+			ops = append(ops, o.inPhase(report.OperationPhaseCatch))
 		}
 	}
 	var handlers []v1alpha1.Catch
@@ -263,7 +294,10 @@ func (p *stepProcessor) finallyOperations() ([]operation, error) {
 	register := func(o ...operation) {
 		for _, o := range o {
 			o.continueOnError = true
-			ops = append(ops, o)
+			if p.config.DryRun {
+				o.skipReason = dryRunSkipReason
+			}
+			ops = append(ops, o.inPhase(report.OperationPhaseFinally))
 		}
 	}
 	for i, handler := range p.step.Finally {
@@ -308,10 +342,10 @@ func (p *stepProcessor) applyOperation(id int, op v1alpha1.Apply) ([]operation,
 	var ops []operation
 	var operationReport *report.OperationReport
 	if p.stepReport != nil {
-		operationReport = report.NewOperation("Apply "+op.File, report.OperationTypeApply)
+		operationReport = report.NewOperationWithClock("Apply "+op.File, report.OperationTypeApply, p.clock)
 		p.stepReport.AddOperation(operationReport)
 	}
-	dryRun := op.DryRun != nil && *op.DryRun
+	dryRun := p.config.DryRun || op.DryRun != nil && *op.DryRun
 	template := runnertemplate.Get(op.Template, p.step.Template, p.test.Spec.Template, p.config.Template)
 	config, cluster := p.getClient(op.Cluster, dryRun)
 	for i, resource := range resources {
@@ -325,7 +359,7 @@ func (p *stepProcessor) applyOperation(id int, op v1alpha1.Apply) ([]operation,
 			},
 			false,
 			timeout.Get(op.Timeout, p.timeouts.ApplyDuration()),
-			opapply.New(cluster, resource, p.namespacer, p.getCleaner(dryRun), template, op.Expect, op.Outputs),
+			p.dryRunOperation(logging.Apply, cluster, resource, template, opapply.New(cluster, resource, p.namespacer, p.getCleaner(dryRun), template, op.Expect, op.Outputs)),
 			operationReport,
 			config,
 			cluster,
@@ -343,7 +377,7 @@ func (p *stepProcessor) assertOperation(id int, op v1alpha1.Assert) ([]operation
 	var ops []operation
 	var operationReport *report.OperationReport
 	if p.stepReport != nil {
-		operationReport = report.NewOperation("Assert ", report.OperationTypeAssert)
+		operationReport = report.NewOperationWithClock("Assert "+op.File, report.OperationTypeAssert, p.clock)
 		p.stepReport.AddOperation(operationReport)
 	}
 	template := runnertemplate.Get(op.Template, p.step.Template, p.test.Spec.Template, p.config.Template)
@@ -369,7 +403,7 @@ func (p *stepProcessor) assertOperation(id int, op v1alpha1.Assert) ([]operation
 func (p *stepProcessor) commandOperation(id int, op v1alpha1.Command) operation {
 	var operationReport *report.OperationReport
 	if p.stepReport != nil {
-		operationReport = report.NewOperation("Command ", report.OperationTypeCommand)
+		operationReport = report.NewOperationWithClock("Command ", report.OperationTypeCommand, p.clock)
 		p.stepReport.AddOperation(operationReport)
 	}
 	ns := ""
@@ -399,10 +433,10 @@ func (p *stepProcessor) createOperation(id int, op v1alpha1.Create) ([]operation
 	var ops []operation
 	var operationReport *report.OperationReport
 	if p.stepReport != nil {
-		operationReport = report.NewOperation("Create ", report.OperationTypeCreate)
+		operationReport = report.NewOperationWithClock("Create "+op.File, report.OperationTypeCreate, p.clock)
 		p.stepReport.AddOperation(operationReport)
 	}
-	dryRun := op.DryRun != nil && *op.DryRun
+	dryRun := p.config.DryRun || op.DryRun != nil && *op.DryRun
 	template := runnertemplate.Get(op.Template, p.step.Template, p.test.Spec.Template, p.config.Template)
 	config, cluster := p.getClient(op.Cluster, dryRun)
 	for i, resource := range resources {
@@ -416,7 +450,7 @@ func (p *stepProcessor) createOperation(id int, op v1alpha1.Create) ([]operation
 			},
 			false,
 			timeout.Get(op.Timeout, p.timeouts.ApplyDuration()),
-			opcreate.New(cluster, resource, p.namespacer, p.getCleaner(dryRun), template, op.Expect, op.Outputs),
+			p.dryRunOperation(logging.Create, cluster, resource, template, opcreate.New(cluster, resource, p.namespacer, p.getCleaner(dryRun), template, op.Expect, op.Outputs)),
 			operationReport,
 			config,
 			cluster,
@@ -435,7 +469,7 @@ func (p *stepProcessor) deleteOperation(id int, op v1alpha1.Delete) operation {
 	resource.SetLabels(op.Labels)
 	var operationReport *report.OperationReport
 	if p.stepReport != nil {
-		operationReport = report.NewOperation("Delete ", report.OperationTypeDelete)
+		operationReport = report.NewOperationWithClock("Delete ", report.OperationTypeDelete, p.clock)
 		p.stepReport.AddOperation(operationReport)
 	}
 	template := runnertemplate.Get(op.Template, p.step.Template, p.test.Spec.Template, p.config.Template)
@@ -457,7 +491,7 @@ func (p *stepProcessor) deleteOperation(id int, op v1alpha1.Delete) operation {
 func (p *stepProcessor) describeOperation(id int, op v1alpha1.Describe) operation {
 	var operationReport *report.OperationReport
 	if p.stepReport != nil {
-		operationReport = report.NewOperation("Describe ", report.OperationTypeCommand)
+		operationReport = report.NewOperationWithClock("Describe ", report.OperationTypeCommand, p.clock)
 		p.stepReport.AddOperation(operationReport)
 	}
 	ns := ""
@@ -492,7 +526,7 @@ func (p *stepProcessor) errorOperation(id int, op v1alpha1.Error) ([]operation,
 	var ops []operation
 	var operationReport *report.OperationReport
 	if p.stepReport != nil {
-		operationReport = report.NewOperation("Error ", report.OperationTypeCommand)
+		operationReport = report.NewOperationWithClock("Error "+op.File, report.OperationTypeCommand, p.clock)
 		p.stepReport.AddOperation(operationReport)
 	}
 	template := runnertemplate.Get(op.Template, p.step.Template, p.test.Spec.Template, p.config.Template)
@@ -518,7 +552,7 @@ func (p *stepProcessor) errorOperation(id int, op v1alpha1.Error) ([]operation,
 func (p *stepProcessor) getOperation(id int, op v1alpha1.Get) operation {
 	var operationReport *report.OperationReport
 	if p.stepReport != nil {
-		operationReport = report.NewOperation("Get ", report.OperationTypeCommand)
+		operationReport = report.NewOperationWithClock("Get ", report.OperationTypeCommand, p.clock)
 		p.stepReport.AddOperation(operationReport)
 	}
 	ns := ""
@@ -548,7 +582,7 @@ func (p *stepProcessor) getOperation(id int, op v1alpha1.Get) operation {
 func (p *stepProcessor) logsOperation(id int, op v1alpha1.PodLogs) operation {
 	var operationReport *report.OperationReport
 	if p.stepReport != nil {
-		operationReport = report.NewOperation("Logs ", report.OperationTypeCommand)
+		operationReport = report.NewOperationWithClock("Logs ", report.OperationTypeCommand, p.clock)
 		p.stepReport.AddOperation(operationReport)
 	}
 	ns := ""
@@ -583,10 +617,10 @@ func (p *stepProcessor) patchOperation(id int, op v1alpha1.Patch) ([]operation,
 	var ops []operation
 	var operationReport *report.OperationReport
 	if p.stepReport != nil {
-		operationReport = report.NewOperation("Patch ", report.OperationTypeCreate)
+		operationReport = report.NewOperationWithClock("Patch "+op.File, report.OperationTypeCreate, p.clock)
 		p.stepReport.AddOperation(operationReport)
 	}
-	dryRun := op.DryRun != nil && *op.DryRun
+	dryRun := p.config.DryRun || op.DryRun != nil && *op.DryRun
 	template := runnertemplate.Get(op.Template, p.step.Template, p.test.Spec.Template, p.config.Template)
 	config, cluster := p.getClient(op.Cluster, dryRun)
 	for i, resource := range resources {
@@ -600,7 +634,7 @@ func (p *stepProcessor) patchOperation(id int, op v1alpha1.Patch) ([]operation,
 			},
 			false,
 			timeout.Get(op.Timeout, p.timeouts.ApplyDuration()),
-			oppatch.New(cluster, resource, p.namespacer, template, op.Expect, op.Outputs),
+			p.dryRunOperation(logging.Patch, nil, resource, template, oppatch.New(cluster, resource, p.namespacer, template, op.Expect, op.Outputs)),
 			operationReport,
 			config,
 			cluster,
@@ -613,7 +647,7 @@ func (p *stepProcessor) patchOperation(id int, op v1alpha1.Patch) ([]operation,
 func (p *stepProcessor) scriptOperation(id int, op v1alpha1.Script) operation {
 	var operationReport *report.OperationReport
 	if p.stepReport != nil {
-		operationReport = report.NewOperation("Script ", report.OperationTypeScript)
+		operationReport = report.NewOperationWithClock("Script ", report.OperationTypeScript, p.clock)
 		p.stepReport.AddOperation(operationReport)
 	}
 	ns := ""
@@ -638,7 +672,7 @@ func (p *stepProcessor) scriptOperation(id int, op v1alpha1.Script) operation {
 func (p *stepProcessor) sleepOperation(id int, op v1alpha1.Sleep) operation {
 	var operationReport *report.OperationReport
 	if p.stepReport != nil {
-		operationReport = report.NewOperation("Sleep ", report.OperationTypeSleep)
+		operationReport = report.NewOperationWithClock("Sleep ", report.OperationTypeSleep, p.clock)
 		p.stepReport.AddOperation(operationReport)
 	}
 	return newOperation(
@@ -662,10 +696,10 @@ func (p *stepProcessor) updateOperation(id int, op v1alpha1.Update) ([]operation
 	var ops []operation
 	var operationReport *report.OperationReport
 	if p.stepReport != nil {
-		operationReport = report.NewOperation("Update ", report.OperationTypeCreate)
+		operationReport = report.NewOperationWithClock("Update "+op.File, report.OperationTypeCreate, p.clock)
 		p.stepReport.AddOperation(operationReport)
 	}
-	dryRun := op.DryRun != nil && *op.DryRun
+	dryRun := p.config.DryRun || op.DryRun != nil && *op.DryRun
 	template := runnertemplate.Get(op.Template, p.step.Template, p.test.Spec.Template, p.config.Template)
 	config, cluster := p.getClient(op.Cluster, dryRun)
 	for i, resource := range resources {
@@ -679,7 +713,7 @@ func (p *stepProcessor) updateOperation(id int, op v1alpha1.Update) ([]operation
 			},
 			false,
 			timeout.Get(op.Timeout, p.timeouts.ApplyDuration()),
-			opupdate.New(cluster, resource, p.namespacer, template, op.Expect, op.Outputs),
+			p.dryRunOperation(logging.Update, nil, resource, template, opupdate.New(cluster, resource, p.namespacer, template, op.Expect, op.Outputs)),
 			operationReport,
 			config,
 			cluster,
@@ -692,7 +726,7 @@ func (p *stepProcessor) updateOperation(id int, op v1alpha1.Update) ([]operation
 func (p *stepProcessor) waitOperation(id int, op v1alpha1.Wait) operation {
 	var operationReport *report.OperationReport
 	if p.stepReport != nil {
-		operationReport = report.NewOperation("Wait ", report.OperationTypeCommand)
+		operationReport = report.NewOperationWithClock("Wait ", report.OperationTypeCommand, p.clock)
 		p.stepReport.AddOperation(operationReport)
 	}
 	ns := ""
@@ -786,12 +820,21 @@ func (p *stepProcessor) prepareResource(resource unstructured.Unstructured) erro
 
 func (p *stepProcessor) getClient(opCluster string, dryRun bool) (*rest.Config, client.Client) {
 	config, cluster := p.clusters.client(opCluster, p.step.Cluster, p.test.Spec.Cluster)
-	if !dryRun {
+	if !dryRun || cluster == nil {
 		return config, cluster
 	}
 	return config, client.DryRun(cluster)
 }
 
+// dryRunOperation returns op, or the operation rendering resource in dry run mode when cluster is nil. The resources of
+// patches and updates are only rendered, the resources they modify were not created by the dry run.
+func (p *stepProcessor) dryRunOperation(opType logging.Operation, cluster client.Client, resource unstructured.Unstructured, template bool, op operations.Operation) operations.Operation {
+	if !p.config.DryRun || cluster != nil {
+		return op
+	}
+	return oprender.New(opType, resource, p.namespacer, template)
+}
+
 func (p *stepProcessor) getCleaner(dryRun bool) cleanup.Cleaner {
 	if dryRun {
 		return nil
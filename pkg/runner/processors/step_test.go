@@ -2,9 +2,12 @@ package processors
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"path/filepath"
 	"time"
 
+	"github.com/jmespath-community/go-jmespath/pkg/binding"
 	"github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
 	"github.com/kyverno/chainsaw/pkg/client"
 	fake "github.com/kyverno/chainsaw/pkg/client/testing"
@@ -13,6 +16,8 @@ import (
 	"github.com/kyverno/chainsaw/pkg/runner/logging"
 	fakeLogger "github.com/kyverno/chainsaw/pkg/runner/logging/testing"
 	fakeNamespacer "github.com/kyverno/chainsaw/pkg/runner/namespacer/testing"
+	"github.com/kyverno/chainsaw/pkg/runner/operations"
+	mock "github.com/kyverno/chainsaw/pkg/runner/operations/testing"
 	"github.com/kyverno/chainsaw/pkg/testing"
 	"github.com/stretchr/testify/assert"
 	kerror "k8s.io/apimachinery/pkg/api/errors"
@@ -1058,3 +1063,228 @@ func TestStepProcessor_Run(t *testing.T) {
 		})
 	}
 }
+
+func TestStepProcessor_Timeouts(t *testing.T) {
+	testData := filepath.Join("..", "..", "..", "testdata", "runner", "processors")
+	duration := func(d time.Duration) *metav1.Duration {
+		return &metav1.Duration{Duration: d}
+	}
+	file := v1alpha1.FileRef{File: "pod.yaml"}
+	config := v1alpha1.ConfigurationSpec{
+		Timeouts: v1alpha1.Timeouts{
+			Apply:  duration(1 * time.Minute),
+			Assert: duration(1 * time.Minute),
+			Delete: duration(1 * time.Minute),
+			Error:  duration(1 * time.Minute),
+			Exec:   duration(1 * time.Minute),
+		},
+	}
+	testTimeouts := &v1alpha1.Timeouts{
+		Apply: duration(2 * time.Minute),
+		Error: duration(2 * time.Minute),
+	}
+	stepTimeouts := &v1alpha1.Timeouts{
+		Apply: duration(3 * time.Minute),
+		Exec:  duration(3 * time.Minute),
+	}
+	tests := []struct {
+		name      string
+		operation v1alpha1.Operation
+		want      time.Duration
+	}{{
+		name:      "apply from step",
+		operation: v1alpha1.Operation{Apply: &v1alpha1.Apply{FileRefOrResource: v1alpha1.FileRefOrResource{FileRef: file}}},
+		want:      3 * time.Minute,
+	}, {
+		name:      "apply from operation",
+		operation: v1alpha1.Operation{Apply: &v1alpha1.Apply{FileRefOrResource: v1alpha1.FileRefOrResource{FileRef: file}, Timeout: duration(4 * time.Minute)}},
+		want:      4 * time.Minute,
+	}, {
+		name:      "create uses apply",
+		operation: v1alpha1.Operation{Create: &v1alpha1.Create{FileRefOrResource: v1alpha1.FileRefOrResource{FileRef: file}}},
+		want:      3 * time.Minute,
+	}, {
+		name:      "patch uses apply",
+		operation: v1alpha1.Operation{Patch: &v1alpha1.Patch{FileRefOrResource: v1alpha1.FileRefOrResource{FileRef: file}}},
+		want:      3 * time.Minute,
+	}, {
+		name:      "update uses apply",
+		operation: v1alpha1.Operation{Update: &v1alpha1.Update{FileRefOrResource: v1alpha1.FileRefOrResource{FileRef: file}}},
+		want:      3 * time.Minute,
+	}, {
+		name:      "assert from config",
+		operation: v1alpha1.Operation{Assert: &v1alpha1.Assert{FileRefOrCheck: v1alpha1.FileRefOrCheck{FileRef: file}}},
+		want:      1 * time.Minute,
+	}, {
+		name:      "assert from operation",
+		operation: v1alpha1.Operation{Assert: &v1alpha1.Assert{FileRefOrCheck: v1alpha1.FileRefOrCheck{FileRef: file}, Timeout: duration(4 * time.Minute)}},
+		want:      4 * time.Minute,
+	}, {
+		name:      "error from test",
+		operation: v1alpha1.Operation{Error: &v1alpha1.Error{FileRefOrCheck: v1alpha1.FileRefOrCheck{FileRef: file}}},
+		want:      2 * time.Minute,
+	}, {
+		name:      "delete from config",
+		operation: v1alpha1.Operation{Delete: &v1alpha1.Delete{}},
+		want:      1 * time.Minute,
+	}, {
+		name:      "command from step",
+		operation: v1alpha1.Operation{Command: &v1alpha1.Command{Entrypoint: "echo"}},
+		want:      3 * time.Minute,
+	}, {
+		name:      "script from operation",
+		operation: v1alpha1.Operation{Script: &v1alpha1.Script{Content: "echo", Timeout: duration(4 * time.Minute)}},
+		want:      4 * time.Minute,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			test := discovery.Test{
+				Test: &v1alpha1.Test{
+					Spec: v1alpha1.TestSpec{
+						Timeouts: testTimeouts,
+					},
+				},
+				BasePath: testData,
+			}
+			step := v1alpha1.TestStep{
+				TestStepSpec: v1alpha1.TestStepSpec{
+					Timeouts: stepTimeouts,
+					Try:      []v1alpha1.Operation{tt.operation},
+				},
+			}
+			stepReport := report.NewTestSpecStep("step")
+			processor := NewStepProcessor(config, NewClusters(), nil, tclock.NewFakePassiveClock(time.Now()), test, step, stepReport, &cleaner{})
+			ops, err := processor.(*stepProcessor).tryOperations()
+			assert.NoError(t, err)
+			assert.Len(t, ops, 1)
+			assert.Equal(t, tt.want, *ops[0].timeout)
+			// the resolved timeout is recorded in the report when the operation runs
+			ops[0].operation = func(context.Context, binding.Bindings) (operations.Operation, error) {
+				return mock.MockOperation{
+					ExecFn: func(context.Context, binding.Bindings) (operations.Outputs, error) {
+						return nil, nil
+					},
+				}, nil
+			}
+			ops[0].execute(testing.IntoContext(context.Background(), &testing.MockT{}), nil)
+			assert.Equal(t, fmt.Sprintf("%.3f", tt.want.Seconds()), stepReport.Results[0].Timeout)
+		})
+	}
+}
+
+func TestStepProcessor_DryRun(t *testing.T) {
+	testData := filepath.Join("..", "..", "..", "testdata", "runner", "processors")
+	file := v1alpha1.FileRef{File: "pod.yaml"}
+	step := v1alpha1.TestStep{
+		TestStepSpec: v1alpha1.TestStepSpec{
+			Try: []v1alpha1.Operation{
+				{Create: &v1alpha1.Create{FileRefOrResource: v1alpha1.FileRefOrResource{FileRef: file}}},
+				{Patch: &v1alpha1.Patch{FileRefOrResource: v1alpha1.FileRefOrResource{FileRef: file}}},
+				{Assert: &v1alpha1.Assert{FileRefOrCheck: v1alpha1.FileRefOrCheck{FileRef: file}}},
+				{Script: &v1alpha1.Script{Content: "exit 1"}},
+				{Delete: &v1alpha1.Delete{}},
+			},
+		},
+	}
+	want := []string{"Success", "Success", "Skipped", "Skipped", "Skipped"}
+	tests := []struct {
+		name   string
+		client *fake.FakeClient
+	}{{
+		name: "without cluster",
+	}, {
+		name: "with cluster",
+		client: &fake.FakeClient{
+			GetFn: func(ctx context.Context, call int, key ctrlclient.ObjectKey, obj ctrlclient.Object, opts ...ctrlclient.GetOption) error {
+				return kerror.NewNotFound(v1alpha1.Resource("Pod"), key.Name)
+			},
+			// only the create is sent to the server
+			CreateFn: func(ctx context.Context, call int, obj ctrlclient.Object, opts ...ctrlclient.CreateOption) error {
+				if len(opts) != 1 || opts[0] != ctrlclient.DryRunAll {
+					return errors.New("not a dry run")
+				}
+				return nil
+			},
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clusters := NewClusters()
+			if tt.client != nil {
+				clusters.clients[DefaultClient] = cluster{
+					client: tt.client,
+				}
+			}
+			test := discovery.Test{
+				Test:     &v1alpha1.Test{},
+				BasePath: testData,
+			}
+			stepReport := report.NewTestSpecStep("step")
+			processor := NewStepProcessor(v1alpha1.ConfigurationSpec{DryRun: true}, clusters, nil, tclock.NewFakePassiveClock(time.Now()), test, step, stepReport, &cleaner{})
+			nt := &testing.MockT{}
+			ctx := testing.IntoContext(context.Background(), nt)
+			ctx = logging.IntoContext(ctx, &fakeLogger.FakeLogger{})
+			processor.Run(ctx, nil)
+			assert.False(t, nt.FailedVar)
+			var got []string
+			for _, result := range stepReport.Results {
+				got = append(got, result.Result)
+			}
+			assert.Equal(t, want, got)
+			assert.Equal(t, dryRunSkipReason, stepReport.Results[2].Message)
+		})
+	}
+}
+
+func TestStepProcessor_SkipIf(t *testing.T) {
+	tests := []struct {
+		name       string
+		skipIf     string
+		wantSkip   bool
+		wantFailed bool
+	}{{
+		name:     "skipped",
+		skipIf:   "$env == 'prod'",
+		wantSkip: true,
+	}, {
+		name:   "not skipped",
+		skipIf: "$env == 'dev'",
+	}, {
+		name:       "error",
+		skipIf:     "$missing",
+		wantFailed: true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step := v1alpha1.TestStep{
+				TestStepSpec: v1alpha1.TestStepSpec{
+					SkipIf: tt.skipIf,
+					Bindings: []v1alpha1.Binding{{
+						Name:  "env",
+						Value: v1alpha1.Any{Value: "prod"},
+					}},
+					Try: []v1alpha1.Operation{{
+						Sleep: &v1alpha1.Sleep{},
+					}},
+				},
+			}
+			test := discovery.Test{
+				Test: &v1alpha1.Test{},
+			}
+			stepReport := report.NewTestSpecStep("step")
+			processor := NewStepProcessor(v1alpha1.ConfigurationSpec{}, NewClusters(), nil, tclock.NewFakePassiveClock(time.Now()), test, step, stepReport, &cleaner{})
+			nt := &testing.MockT{}
+			ctx := testing.IntoContext(context.Background(), nt)
+			ctx = logging.IntoContext(ctx, &fakeLogger.FakeLogger{})
+			processor.Run(ctx, nil)
+			assert.Equal(t, tt.wantFailed, nt.FailedVar)
+			assert.Equal(t, tt.wantSkip, stepReport.Skip)
+			if tt.wantSkip {
+				assert.Equal(t, "skipIf: $env == 'prod'", stepReport.SkipReason)
+				assert.Empty(t, stepReport.Results)
+			} else {
+				assert.Len(t, stepReport.Results, 1)
+			}
+		})
+	}
+}
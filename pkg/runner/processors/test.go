@@ -3,7 +3,7 @@ package processors
 import (
 	"context"
 	"fmt"
-	"sync/atomic"
+	"time"
 
 	"github.com/jmespath-community/go-jmespath/pkg/binding"
 	"github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
@@ -12,6 +12,7 @@ import (
 	"github.com/kyverno/chainsaw/pkg/report"
 	apibindings "github.com/kyverno/chainsaw/pkg/runner/bindings"
 	"github.com/kyverno/chainsaw/pkg/runner/cleanup"
+	"github.com/kyverno/chainsaw/pkg/runner/events"
 	"github.com/kyverno/chainsaw/pkg/runner/logging"
 	"github.com/kyverno/chainsaw/pkg/runner/mutate"
 	"github.com/kyverno/chainsaw/pkg/runner/namespacer"
@@ -19,15 +20,18 @@ import (
 	"github.com/kyverno/chainsaw/pkg/runner/summary"
 	"github.com/kyverno/chainsaw/pkg/runner/timeout"
 	"github.com/kyverno/chainsaw/pkg/testing"
-	"github.com/kyverno/kyverno/ext/output/color"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/clock"
 )
 
+// slowCleanupThreshold is the cleanup duration above which a warning is raised.
+const slowCleanupThreshold = time.Minute
+
 type TestProcessor interface {
 	Run(context.Context, binding.Bindings, namespacer.Namespacer)
-	CreateStepProcessor(namespacer.Namespacer, *cleaner, v1alpha1.TestStep) StepProcessor
+	CreateStepProcessor(namespacer.Namespacer, *cleaner, int, v1alpha1.TestStep) StepProcessor
 }
 
 func NewTestProcessor(
@@ -37,29 +41,50 @@ func NewTestProcessor(
 	summary *summary.Summary,
 	testReport *report.TestReport,
 	test discovery.Test,
-	shouldFailFast *atomic.Bool,
+	failFast *failFast,
+	workers *workers,
 ) TestProcessor {
+	return newTestProcessor(config, clusters, clock, summary, testReport, test, failFast, workers)
+}
+
+func newTestProcessor(
+	config v1alpha1.ConfigurationSpec,
+	clusters clusters,
+	clock clock.PassiveClock,
+	summary *summary.Summary,
+	testReport *report.TestReport,
+	test discovery.Test,
+	failFast *failFast,
+	workers *workers,
+) *testProcessor {
 	return &testProcessor{
-		config:         config,
-		clusters:       clusters,
-		clock:          clock,
-		summary:        summary,
-		testReport:     testReport,
-		test:           test,
-		shouldFailFast: shouldFailFast,
-		timeouts:       config.Timeouts.Combine(test.Spec.Timeouts),
+		config:      config,
+		clusters:    clusters,
+		clock:       clock,
+		summary:     summary,
+		testReport:  testReport,
+		test:        test,
+		failFast:    failFast,
+		workers:     workers,
+		timeouts:    config.Timeouts.Combine(test.Spec.Timeouts),
+		quarantined: quarantined(config, test),
 	}
 }
 
 type testProcessor struct {
-	config         v1alpha1.ConfigurationSpec
-	clusters       clusters
-	clock          clock.PassiveClock
-	summary        *summary.Summary
-	testReport     *report.TestReport
-	test           discovery.Test
-	shouldFailFast *atomic.Bool
-	timeouts       v1alpha1.Timeouts
+	config      v1alpha1.ConfigurationSpec
+	clusters    clusters
+	clock       clock.PassiveClock
+	summary     *summary.Summary
+	testReport  *report.TestReport
+	test        discovery.Test
+	failFast    *failFast
+	workers     *workers
+	timeouts    v1alpha1.Timeouts
+	quarantined bool
+	// iteration of the test, numbered from 1, 0 if the test runs once
+	iteration        int
+	iterationResults *iterationResults
 }
 
 func (p *testProcessor) Run(ctx context.Context, bindings binding.Bindings, nspacer namespacer.Namespacer) {
@@ -67,35 +92,97 @@ func (p *testProcessor) Run(ctx context.Context, bindings binding.Bindings, nspa
 		bindings = binding.NewBindings()
 	}
 	t := testing.FromContext(ctx)
+	var tlogger logging.TLogger = t
+	// the dashboard owns the console, captured logs still get everything
+	if logging.IsQuietConsole(ctx) {
+		tlogger = logging.Discard(t)
+	}
+	// tests of different folders may have the same name, their lines are told apart by their path
+	// and the lines of the iterations of a test, which may run concurrently, by their name
+	testName := p.name()
+	key := logging.TestKey{Path: p.test.BasePath, Name: testName}
+	ctx = logging.WithOptions(ctx, logging.WithTestPath(p.test.BasePath))
+	// the per test log files are closed once the test completes, many tests may run at once
+	mainLogger := logging.FromContext(ctx)
+	var skipReason string
 	if p.testReport != nil {
+		ctx = report.TestIntoContext(ctx, p.testReport)
+		// the warnings raised by the operations are added to the report when the test completes
+		warnings := logging.NewWarningCollector(logging.DefaultMaxWarnings)
+		ctx = logging.WithOptions(ctx, logging.WithWarnings(warnings))
+		if len(p.config.ReportEnv) != 0 {
+			p.testReport.SetEnvironment(report.EnvCapture{Names: p.config.ReportEnv}.Capture())
+		}
+		// the lines of the test are captured by the loggers of the test, with the times they were logged at
+		var captures *logging.Captures
+		if p.config.ReportLogs {
+			maxSize := logging.DefaultCaptureSize
+			if p.config.ReportLogsMaxSize != nil {
+				maxSize = *p.config.ReportLogsMaxSize
+			}
+			captures = logging.NewCapturesWithMaxSize(maxSize)
+			if p.config.ReportLogsFullMessages {
+				captures.KeepFullMessages()
+			}
+			ctx = logging.WithOptions(ctx, logging.WithCapture(captures))
+		}
 		t.Cleanup(func() {
 			if t.Failed() {
 				p.testReport.NewFailure("test failed")
 			}
+			warnings.AddTo(p.testReport)
+			if captures != nil && (t.Failed() || !p.config.ReportLogsFailedOnly) {
+				p.testReport.SetLogs(captures.Take(key))
+			}
+			if t.Skipped() {
+				p.testReport.MarkSkipped(skipReason)
+			} else if p.quarantined && !t.Failed() {
+				p.testReport.AddWarning(report.WarningTypeQuarantinePassed, "quarantined test passed, it may be removed from the quarantine")
+			}
+			for _, path := range logging.CloseTestFiles(mainLogger, key) {
+				p.testReport.AddArtifact(path)
+			}
 			p.testReport.MarkTestEnd()
 		})
+	} else {
+		t.Cleanup(func() { logging.CloseTestFiles(mainLogger, key) })
 	}
+	steps := len(p.test.Spec.Steps)
 	size := len("@cleanup")
 	for i, step := range p.test.Spec.Steps {
-		name := step.Name
-		if name == "" {
-			name = fmt.Sprintf("step-%d", i+1)
-		}
-		if size < len(name) {
-			size = len(name)
+		// there is room for the ordinal when it is rendered after the name
+		label := logging.StepLabel(step.Name, i+1, steps, p.config.LogOrdinals)
+		if size < len(label) {
+			size = len(label)
 		}
 	}
+	t.Cleanup(func() {
+		events.Publish(ctx, events.Event{
+			Type:    events.TestFinished,
+			Time:    p.clock.Now(),
+			Test:    testName,
+			Path:    p.test.BasePath,
+			Failed:  t.Failed(),
+			Skipped: t.Skipped(),
+		})
+	})
 	if p.summary != nil {
 		t.Cleanup(func() {
 			if t.Skipped() {
 				p.summary.IncSkipped()
 			} else {
-				if t.Failed() {
-					p.summary.IncFailed()
+				if t.Failed() && p.quarantined {
+					p.summary.IncQuarantined()
+				} else if t.Failed() {
+					// failed iterations of a test with a pass rate are counted once all the iterations completed
+					if p.iterationResults == nil {
+						p.summary.IncFailed()
+					}
 				} else {
 					p.summary.IncPassed()
 				}
 			}
+			p.iterationResults.complete(p.summary, t.Skipped(), t.Failed() && !p.quarantined)
 		})
 	}
 	if p.test.Spec.Concurrent == nil || *p.test.Spec.Concurrent {
@@ -104,20 +191,54 @@ func (p *testProcessor) Run(ctx context.Context, bindings binding.Bindings, nspa
 	if p.test.Spec.Skip != nil && *p.test.Spec.Skip {
 		t.SkipNow()
 	}
+	// parallel tests take their slot once they run, not when they are paused waiting for their turn
+	worker := p.workers.acquire()
+	if worker != 0 {
+		t.Cleanup(func() { p.workers.release(worker) })
+	}
+	// a test may have failed while this one was waiting for a slot
 	if p.config.FailFast {
-		if p.shouldFailFast.Load() {
+		if p.failFast.isStopped() {
+			skipReason = report.SkipReasonFailFast
 			t.SkipNow()
 		}
 	}
+	if worker != 0 {
+		if p.testReport != nil {
+			p.testReport.SetWorker(worker)
+		}
+		if p.config.LogWorker {
+			ctx = logging.WithOptions(ctx, logging.WithWorker(worker))
+		}
+	}
+	if p.config.LogNamespace {
+		ctx = logging.WithOptions(ctx, logging.WithNamespaceColumn())
+	}
+	events.Publish(ctx, events.Event{Type: events.TestStarted, Time: p.clock.Now(), Test: testName, Path: p.test.BasePath})
+	// operations publish their events for the test and step they run in
+	ctx = events.WithScope(ctx, testName, "")
+	if p.config.LogElapsed {
+		ctx = logging.WithOptions(ctx, logging.WithElapsed(p.clock.Now()))
+	}
+	// log lines hold the identifiers of the report, to join them
+	if p.testReport != nil {
+		ctx = logging.WithOptions(ctx, logging.WithTestCorrelationID(p.testReport.CorrelationID))
+	}
+	if p.config.LogDedupe {
+		ctx = logging.WithDedupe(ctx, p.clock)
+	}
 	config, cluster := p.clusters.client(p.test.Spec.Cluster)
 	bindings = apibindings.RegisterClusterBindings(ctx, bindings, config, cluster)
-	setupLogger := logging.NewLogger(t, p.clock, p.test.Name, fmt.Sprintf("%-*s", size, "@setup"))
-	cleanupLogger := logging.NewLogger(t, p.clock, p.test.Name, fmt.Sprintf("%-*s", size, "@cleanup"))
+	setupLogger := logging.NewLogger(tlogger, p.clock, testName, fmt.Sprintf("%-*s", size, "@setup"), logging.OptionsFromContext(ctx)...)
+	cleanupLogger := logging.NewLogger(tlogger, p.clock, testName, fmt.Sprintf("%-*s", size, "@cleanup"), logging.OptionsFromContext(ctx)...)
 	var namespace *corev1.Namespace
 	if cluster != nil {
-		if nspacer == nil || p.test.Spec.Namespace != "" {
+		// iterations run in their own namespace, they may run concurrently
+		if nspacer == nil || p.test.Spec.Namespace != "" || p.iteration != 0 {
 			var ns corev1.Namespace
-			if p.test.Spec.Namespace != "" {
+			if p.test.Spec.Namespace != "" && p.iteration != 0 {
+				ns = client.Namespace(fmt.Sprintf("%s-%d", p.test.Spec.Namespace, p.iteration))
+			} else if p.test.Spec.Namespace != "" {
 				ns = client.Namespace(p.test.Spec.Namespace)
 			} else {
 				ns = client.PetNamespace()
@@ -139,37 +260,50 @@ func (p *testProcessor) Run(ctx context.Context, bindings binding.Bindings, nspa
 				bindings = apibindings.RegisterNamedBinding(ctx, bindings, "namespace", object.GetName())
 			}
 			nspacer = namespacer.New(cluster, object.GetName())
-			setupCtx := logging.IntoContext(ctx, setupLogger)
-			cleanupCtx := logging.IntoContext(ctx, cleanupLogger)
+			// the namespace of the test replaces the one of the suite, the loggers of the steps are created later
+			setupLogger = logging.WithNamespace(setupLogger, object.GetName())
+			cleanupLogger = logging.WithNamespace(cleanupLogger, object.GetName())
+			ctx = logging.WithOptions(ctx, logging.WithTestNamespace(object.GetName()))
+			setupCtx := logging.IntoContext(events.WithScope(ctx, testName, "@setup"), setupLogger)
+			cleanupCtx := logging.IntoContext(events.WithScope(ctx, testName, "@cleanup"), cleanupLogger)
 			if err := cluster.Get(setupCtx, client.ObjectKey(&object), object.DeepCopy()); err != nil {
 				if !errors.IsNotFound(err) {
 					// Get doesn't log
-					setupLogger.Log(logging.Get, logging.ErrorStatus, color.BoldRed, logging.ErrSection(err))
+					logging.Failure(setupLogger, logging.Get, logging.ErrorStatus, logging.ErrSection(err))
 					t.FailNow()
 				}
-				if !cleanup.Skip(p.config.SkipDelete, p.test.Spec.SkipDelete, nil) {
-					t.Cleanup(func() {
-						operation := newOperation(
-							OperationInfo{},
-							false,
-							timeout.Get(nil, p.timeouts.CleanupDuration()),
-							opdelete.New(cluster, object, nspacer, false),
-							nil,
-							config,
-							cluster,
-						)
-						operation.execute(cleanupCtx, bindings)
-					})
-				}
-				if err := cluster.Create(logging.IntoContext(setupCtx, setupLogger), object.DeepCopy()); err != nil {
-					t.FailNow()
+				if p.config.DryRun {
+					// the server rejects the resources of a namespace that doesn't exist, they are validated in the default one
+					if err := client.DryRun(cluster).Create(logging.IntoContext(setupCtx, setupLogger), object.DeepCopy()); err != nil {
+						t.FailNow()
+					}
+					bindings = apibindings.RegisterNamedBinding(ctx, bindings, "namespace", metav1.NamespaceDefault)
+					nspacer = namespacer.New(cluster, metav1.NamespaceDefault)
+				} else {
+					if !cleanup.Skip(p.config.SkipDelete, p.test.Spec.SkipDelete, nil) {
+						t.Cleanup(func() {
+							operation := newOperation(
+								OperationInfo{},
+								false,
+								timeout.Get(nil, p.timeouts.CleanupDuration()),
+								opdelete.New(cluster, object, nspacer, false),
+								nil,
+								config,
+								cluster,
+							)
+							operation.execute(cleanupCtx, bindings)
+						})
+					}
+					if err := cluster.Create(logging.IntoContext(setupCtx, setupLogger), object.DeepCopy()); err != nil {
+						t.FailNow()
+					}
 				}
 			}
 		}
 	}
 	bindings, err := apibindings.RegisterBindings(ctx, bindings, p.test.Spec.Bindings...)
 	if err != nil {
-		logging.Log(ctx, logging.Internal, logging.ErrorStatus, color.BoldRed, logging.ErrSection(err))
+		logging.Failure(logging.FromContext(ctx), logging.Internal, logging.ErrorStatus, logging.ErrSection(err))
 		t.FailNow()
 	}
 	delay := p.config.DelayBeforeCleanup
@@ -177,26 +311,55 @@ func (p *testProcessor) Run(ctx context.Context, bindings binding.Bindings, nspa
 		delay = p.test.Spec.DelayBeforeCleanup
 	}
 	cleaner := newCleaner(nspacer, delay)
+	// the steps are cancelled when another test fails in fail fast mode, the cleanup is not
+	stepsCtx, cancel := p.failFast.withCancel(ctx)
+	defer cancel()
 	t.Cleanup(func() {
-		cleaner.run(logging.IntoContext(ctx, cleanupLogger))
+		cleanupCtx := logging.IntoContext(events.WithScope(ctx, testName, "@cleanup"), cleanupLogger)
+		start := p.clock.Now()
+		cleaner.run(cleanupCtx)
+		if elapsed := p.clock.Since(start); elapsed > slowCleanupThreshold {
+			logging.Warning(cleanupCtx, logging.Delete, report.WarningTypeSlowCleanup, fmt.Sprintf("cleanup took %s", elapsed.Round(time.Second)))
+		}
 	})
 	for i, step := range p.test.Spec.Steps {
-		processor := p.CreateStepProcessor(nspacer, cleaner, step)
-		name := step.Name
-		if name == "" {
-			name = fmt.Sprintf("step-%d", i+1)
-		}
-		processor.Run(
-			logging.IntoContext(ctx, logging.NewLogger(t, p.clock, p.test.Name, fmt.Sprintf("%-*s", size, name))),
-			apibindings.RegisterNamedBinding(ctx, bindings, "step", StepInfo{Id: i + 1}),
-		)
+		processor := p.CreateStepProcessor(nspacer, cleaner, i+1, step)
+		// steps without a name are named after their ordinal, like step 2/5
+		name := logging.StepLabel(step.Name, i+1, steps, false)
+		events.Publish(ctx, events.Event{Type: events.StepStarted, Time: p.clock.Now(), Test: testName, Path: p.test.BasePath, Step: name})
+		func() {
+			// the step failed if the test wasn't failed before it ran, its end is published when it fails the test now too
+			failed := t.Failed()
+			defer func() {
+				events.Publish(ctx, events.Event{Type: events.StepFinished, Time: p.clock.Now(), Test: testName, Path: p.test.BasePath, Step: name, Failed: !failed && t.Failed()})
+				// no test starts once the step failed, rather than once the cleanup of the test completes
+				if p.config.FailFast && t.Failed() && !p.quarantined {
+					p.failFast.stop()
+				}
+			}()
+			stepCtx := events.WithScope(stepsCtx, testName, name)
+			processor.Run(
+				logging.IntoContext(stepCtx, logging.NewLogger(tlogger, p.clock, testName, fmt.Sprintf("%-*s", size, name), append(logging.OptionsFromContext(ctx), logging.WithStepOrdinal(i+1, steps))...)),
+				apibindings.RegisterNamedBinding(stepCtx, bindings, "step", StepInfo{Id: i + 1}),
+			)
+		}()
+	}
+}
+
+// CreateStepProcessor returns the processor of step, index is its position in the test, numbered from 1.
+// name returns the name of the test, or the name of its iteration if it runs more than once, see report.IterationName.
+func (p *testProcessor) name() string {
+	if p.iteration != 0 {
+		return report.IterationName(p.test.Name, p.iteration)
 	}
+	return p.test.Name
 }
 
-func (p *testProcessor) CreateStepProcessor(nspacer namespacer.Namespacer, cleaner *cleaner, step v1alpha1.TestStep) StepProcessor {
+func (p *testProcessor) CreateStepProcessor(nspacer namespacer.Namespacer, cleaner *cleaner, index int, step v1alpha1.TestStep) StepProcessor {
 	var stepReport *report.TestSpecStepReport
 	if p.testReport != nil {
 		stepReport = report.NewTestSpecStep(step.Name)
+		stepReport.Index = index
 		p.testReport.AddTestStep(stepReport)
 	}
 	return NewStepProcessor(p.config, p.clusters, nspacer, p.clock, p.test, step, stepReport, cleaner)
@@ -1,9 +1,9 @@
 package processors
 
 import (
+	"bytes"
 	"context"
 	"errors"
-	"sync/atomic"
 	"time"
 
 	"github.com/jmespath-community/go-jmespath/pkg/binding"
@@ -12,12 +12,15 @@ import (
 	fake "github.com/kyverno/chainsaw/pkg/client/testing"
 	"github.com/kyverno/chainsaw/pkg/discovery"
 	"github.com/kyverno/chainsaw/pkg/report"
+	"github.com/kyverno/chainsaw/pkg/runner/logging"
 	fakeNamespacer "github.com/kyverno/chainsaw/pkg/runner/namespacer/testing"
 	"github.com/kyverno/chainsaw/pkg/runner/summary"
 	"github.com/kyverno/chainsaw/pkg/testing"
 	"github.com/stretchr/testify/assert"
 	kerror "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/utils/clock"
 	tclock "k8s.io/utils/clock/testing"
 	"k8s.io/utils/ptr"
@@ -386,8 +389,8 @@ func TestTestProcessor_Run(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			shouldFailVar := &atomic.Bool{}
-			shouldFailVar.Store(tc.shouldFailFast)
+			failFast := newFailFast(false)
+			failFast.stopped.Store(tc.shouldFailFast)
 			clusters := NewClusters()
 			if tc.client != nil {
 				clusters.clients[DefaultClient] = cluster{
@@ -401,7 +404,8 @@ func TestTestProcessor_Run(t *testing.T) {
 				tc.summary,
 				tc.testsReport,
 				tc.test,
-				shouldFailVar,
+				failFast,
+				nil,
 			)
 			nt := &testing.MockT{}
 			ctx := testing.IntoContext(context.Background(), nt)
@@ -412,7 +416,7 @@ func TestTestProcessor_Run(t *testing.T) {
 			} else {
 				assert.False(t, nt.FailedVar, "expected no error but got one")
 			}
-			if shouldFailVar.Load() || tc.skipped {
+			if failFast.isStopped() || tc.skipped {
 				assert.True(t, nt.SkippedVar, "test should be skipped but it was not")
 			} else {
 				assert.False(t, nt.SkippedVar, "test should not be skipped but it was")
@@ -420,3 +424,258 @@ func TestTestProcessor_Run(t *testing.T) {
 		})
 	}
 }
+
+func TestTestProcessor_Worker(t *testing.T) {
+	clusters := NewClusters()
+	clusters.clients[DefaultClient] = cluster{
+		client: &fake.FakeClient{
+			GetFn: func(ctx context.Context, call int, key ctrlclient.ObjectKey, obj ctrlclient.Object, opts ...ctrlclient.GetOption) error {
+				return nil
+			},
+		},
+	}
+	test := discovery.Test{
+		Test: &v1alpha1.Test{
+			Spec: v1alpha1.TestSpec{
+				Namespace: "chainsaw",
+			},
+		},
+	}
+	var slots workers
+	var reports []*report.TestReport
+	for i := 0; i < 2; i++ {
+		testReport := report.NewTest("test")
+		processor := NewTestProcessor(v1alpha1.ConfigurationSpec{LogWorker: true}, clusters, tclock.NewFakePassiveClock(time.Now()), nil, testReport, test, newFailFast(false), &slots)
+		// the mock doesn't run cleanups, slots are never freed
+		processor.Run(testing.IntoContext(context.Background(), &testing.MockT{}), nil, nil)
+		reports = append(reports, testReport)
+	}
+	assert.Equal(t, 1, reports[0].Worker)
+	assert.Equal(t, 2, reports[1].Worker)
+	// without workers no slot is recorded
+	testReport := report.NewTest("test")
+	NewTestProcessor(v1alpha1.ConfigurationSpec{}, clusters, tclock.NewFakePassiveClock(time.Now()), nil, testReport, test, newFailFast(false), nil).Run(testing.IntoContext(context.Background(), &testing.MockT{}), nil, nil)
+	assert.Zero(t, testReport.Worker)
+}
+
+func TestTestProcessor_StepIndex(t *testing.T) {
+	clusters := NewClusters()
+	clusters.clients[DefaultClient] = cluster{
+		client: &fake.FakeClient{
+			GetFn: func(ctx context.Context, call int, key ctrlclient.ObjectKey, obj ctrlclient.Object, opts ...ctrlclient.GetOption) error {
+				return nil
+			},
+		},
+	}
+	test := discovery.Test{
+		Test: &v1alpha1.Test{
+			Spec: v1alpha1.TestSpec{
+				Namespace: "chainsaw",
+				Steps:     []v1alpha1.TestStep{{}, {Name: "deploy"}},
+			},
+		},
+	}
+	testReport := report.NewTest("test")
+	NewTestProcessor(v1alpha1.ConfigurationSpec{}, clusters, tclock.NewFakePassiveClock(time.Now()), nil, testReport, test, newFailFast(false), nil).Run(testing.IntoContext(context.Background(), &testing.MockT{}), nil, nil)
+	assert.Len(t, testReport.Steps, 2)
+	assert.Equal(t, "", testReport.Steps[0].Name)
+	assert.Equal(t, 1, testReport.Steps[0].Index)
+	assert.Equal(t, "deploy", testReport.Steps[1].Name)
+	assert.Equal(t, 2, testReport.Steps[1].Index)
+}
+
+func TestTestProcessor_ReportLogs(t *testing.T) {
+	clusters := NewClusters()
+	clusters.clients[DefaultClient] = cluster{
+		client: &fake.FakeClient{
+			GetFn: func(ctx context.Context, call int, key ctrlclient.ObjectKey, obj ctrlclient.Object, opts ...ctrlclient.GetOption) error {
+				return nil
+			},
+		},
+	}
+	test := discovery.Test{
+		Test: &v1alpha1.Test{
+			ObjectMeta: v1.ObjectMeta{Name: "test"},
+			Spec: v1alpha1.TestSpec{
+				Namespace:  "chainsaw",
+				Concurrent: ptr.To(false),
+				Steps:      []v1alpha1.TestStep{{Name: "deploy"}},
+			},
+		},
+	}
+	clock := tclock.NewFakePassiveClock(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC))
+	testReport := report.NewTest("test")
+	config := v1alpha1.ConfigurationSpec{ReportLogs: true}
+	processor := NewTestProcessor(config, clusters, clock, nil, testReport, test, newFailFast(false), nil)
+	// the cleanups of a real test run when it completes, its loggers log everything whatever the -v flag
+	t.Run("test", func(t *testing.T) {
+		ctx := logging.WithOptions(testing.IntoContext(context.Background(), t), logging.WithVerbose(true))
+		processor.Run(ctx, nil, nil)
+	})
+	// the lines are captured from the loggers of the test, stamped with the time they were logged at
+	assert.NotEmpty(t, testReport.Logs)
+	for _, line := range testReport.Logs {
+		assert.Equal(t, clock.Now(), line.Time)
+		assert.Contains(t, line.Message, "| test |")
+	}
+}
+
+func TestTestProcessor_Namespace(t *testing.T) {
+	clusters := NewClusters()
+	clusters.clients[DefaultClient] = cluster{
+		client: &fake.FakeClient{
+			GetFn: func(ctx context.Context, call int, key ctrlclient.ObjectKey, obj ctrlclient.Object, opts ...ctrlclient.GetOption) error {
+				return nil
+			},
+		},
+	}
+	test := discovery.Test{
+		Test: &v1alpha1.Test{
+			Spec: v1alpha1.TestSpec{
+				Namespace: "chainsaw",
+				Steps: []v1alpha1.TestStep{{
+					TestStepSpec: v1alpha1.TestStepSpec{
+						Try: []v1alpha1.Operation{{
+							Sleep: &v1alpha1.Sleep{Duration: v1.Duration{Duration: time.Millisecond}},
+						}},
+					},
+				}},
+			},
+		},
+	}
+	var entries []logging.Entry
+	defer logging.OnEntry(func(entry logging.Entry) { entries = append(entries, entry) })()
+	ctx := logging.WithOptions(testing.IntoContext(context.Background(), &testing.MockT{}), logging.WithVerbose(true))
+	NewTestProcessor(v1alpha1.ConfigurationSpec{LogNamespace: true}, clusters, tclock.NewFakePassiveClock(time.Now()), nil, nil, test, newFailFast(false), nil).Run(ctx, nil, nil)
+	assert.NotEmpty(t, entries)
+	for _, entry := range entries {
+		assert.Equal(t, "chainsaw", entry.Namespace)
+		assert.True(t, entry.NamespaceColumn)
+	}
+}
+
+func TestTestProcessor_DryRun(t *testing.T) {
+	var created []ctrlclient.Object
+	clusters := NewClusters()
+	clusters.clients[DefaultClient] = cluster{
+		client: &fake.FakeClient{
+			GetFn: func(ctx context.Context, call int, key ctrlclient.ObjectKey, obj ctrlclient.Object, opts ...ctrlclient.GetOption) error {
+				return kerror.NewNotFound(v1alpha1.Resource("Namespace"), key.Name)
+			},
+			CreateFn: func(ctx context.Context, call int, obj ctrlclient.Object, opts ...ctrlclient.CreateOption) error {
+				assert.Equal(t, []ctrlclient.CreateOption{ctrlclient.DryRunAll}, opts)
+				created = append(created, obj)
+				return nil
+			},
+			IsObjectNamespacedFn: func(int, runtime.Object) (bool, error) {
+				return true, nil
+			},
+		},
+	}
+	configMap := unstructured.Unstructured{}
+	configMap.SetAPIVersion("v1")
+	configMap.SetKind("ConfigMap")
+	configMap.SetName("foo")
+	test := discovery.Test{
+		Test: &v1alpha1.Test{
+			Spec: v1alpha1.TestSpec{
+				Namespace: "chainsaw",
+				Steps: []v1alpha1.TestStep{{
+					TestStepSpec: v1alpha1.TestStepSpec{
+						Try: []v1alpha1.Operation{{
+							Create: &v1alpha1.Create{FileRefOrResource: v1alpha1.FileRefOrResource{Resource: &configMap}},
+						}},
+					},
+				}},
+			},
+		},
+	}
+	nt := &testing.MockT{}
+	NewTestProcessor(v1alpha1.ConfigurationSpec{DryRun: true}, clusters, tclock.NewFakePassiveClock(time.Now()), nil, nil, test, newFailFast(false), nil).Run(testing.IntoContext(context.Background(), nt), nil, nil)
+	assert.False(t, nt.FailedVar)
+	// the namespace is dry run, the resources of the test are dry run in the default namespace
+	if assert.Len(t, created, 2) {
+		assert.Equal(t, "chainsaw", created[0].GetName())
+		assert.Equal(t, "default", created[1].GetNamespace())
+	}
+}
+
+// slowClock is a clock reporting every operation took two minutes.
+type slowClock struct {
+	clock.PassiveClock
+}
+
+func (c slowClock) Since(time.Time) time.Duration { return 2 * time.Minute }
+
+func TestTestProcessor_Warnings(t *testing.T) {
+	clusters := NewClusters()
+	clusters.clients[DefaultClient] = cluster{
+		client: &fake.FakeClient{
+			GetFn: func(ctx context.Context, call int, key ctrlclient.ObjectKey, obj ctrlclient.Object, opts ...ctrlclient.GetOption) error {
+				return nil
+			},
+		},
+	}
+	test := discovery.Test{
+		Test: &v1alpha1.Test{
+			ObjectMeta: v1.ObjectMeta{Name: "test"},
+			Spec: v1alpha1.TestSpec{
+				Namespace:  "chainsaw",
+				Concurrent: ptr.To(false),
+			},
+		},
+	}
+	testsReport := report.NewTests("chainsaw")
+	testReport := report.NewTest("test")
+	testsReport.AddTest(testReport)
+	processor := NewTestProcessor(v1alpha1.ConfigurationSpec{}, clusters, slowClock{tclock.NewFakePassiveClock(time.Now())}, nil, testReport, test, newFailFast(false), nil)
+	// the cleanups of a real test run when it completes
+	t.Run("test", func(t *testing.T) {
+		processor.Run(testing.IntoContext(context.Background(), t), nil, nil)
+	})
+	// the slow cleanup warning is raised with the cleanup logger and collected into the report
+	var buf bytes.Buffer
+	assert.NoError(t, report.SaveReportTo(testsReport, report.JSONSerializer{}, &buf))
+	assert.Contains(t, buf.String(), `"type": "slowCleanup"`)
+	assert.Contains(t, buf.String(), `"message": "cleanup took 2m0s"`)
+}
+
+func TestTestProcessor_FailFast(t *testing.T) {
+	clusters := NewClusters()
+	clusters.clients[DefaultClient] = cluster{
+		client: &fake.FakeClient{
+			GetFn: func(ctx context.Context, call int, key ctrlclient.ObjectKey, obj ctrlclient.Object, opts ...ctrlclient.GetOption) error {
+				return nil
+			},
+		},
+	}
+	test := discovery.Test{
+		Test: &v1alpha1.Test{
+			ObjectMeta: v1.ObjectMeta{Name: "test"},
+			Spec: v1alpha1.TestSpec{
+				Namespace:  "chainsaw",
+				Concurrent: ptr.To(false),
+			},
+		},
+	}
+	failFast := newFailFast(false)
+	failFast.stop()
+	testReport := report.NewTest("test")
+	processor := NewTestProcessor(v1alpha1.ConfigurationSpec{FailFast: true}, clusters, tclock.NewFakePassiveClock(time.Now()), nil, testReport, test, failFast, nil)
+	// the cleanups of a real test run when it completes
+	t.Run("test", func(t *testing.T) {
+		processor.Run(testing.IntoContext(context.Background(), t), nil, nil)
+	})
+	// tests not started once a test failed are skipped with the fail fast reason
+	assert.True(t, testReport.Skip)
+	assert.Equal(t, report.SkipReasonFailFast, testReport.SkipReason)
+	// the reason of a skipped test is empty otherwise
+	test.Spec.Skip = ptr.To(true)
+	testReport = report.NewTest("test")
+	processor = NewTestProcessor(v1alpha1.ConfigurationSpec{FailFast: true}, clusters, tclock.NewFakePassiveClock(time.Now()), nil, testReport, test, newFailFast(false), nil)
+	t.Run("test", func(t *testing.T) {
+		processor.Run(testing.IntoContext(context.Background(), t), nil, nil)
+	})
+	assert.True(t, testReport.Skip)
+	assert.Empty(t, testReport.SkipReason)
+}
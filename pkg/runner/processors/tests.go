@@ -2,7 +2,6 @@ package processors
 
 import (
 	"context"
-	"sync/atomic"
 
 	"github.com/jmespath-community/go-jmespath/pkg/binding"
 	"github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
@@ -19,9 +18,10 @@ import (
 	"github.com/kyverno/chainsaw/pkg/runner/summary"
 	"github.com/kyverno/chainsaw/pkg/runner/timeout"
 	"github.com/kyverno/chainsaw/pkg/testing"
-	"github.com/kyverno/kyverno/ext/output/color"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/clock"
+	"k8s.io/utils/ptr"
 )
 
 type TestsProcessor interface {
@@ -44,6 +44,8 @@ func NewTestsProcessor(
 		summary:     summary,
 		testsReport: testsReport,
 		tests:       tests,
+		failFast:    newFailFast(config.FailFast && config.FailFastCancel),
+		workers:     newWorkers(ptr.Deref(config.Parallel, 0)),
 	}
 }
 
@@ -55,7 +57,8 @@ type testsProcessor struct {
 	testsReport *report.TestsReport
 	tests       []discovery.Test
 	// state
-	shouldFailFast atomic.Bool
+	failFast *failFast
+	workers  *workers
 }
 
 func (p *testsProcessor) Run(ctx context.Context, bindings binding.Bindings) {
@@ -66,6 +69,17 @@ func (p *testsProcessor) Run(ctx context.Context, bindings binding.Bindings) {
 	t.Cleanup(func() {
 		if p.testsReport != nil {
 			p.testsReport.Close()
+			options := report.ValidateOptions{
+				AllowDuplicateNames: p.config.RepeatCount != nil && *p.config.RepeatCount > 1,
+			}
+			if err := p.testsReport.ValidateWithOptions(options); err != nil {
+				if p.config.ReportStrict {
+					logging.Failure(logging.FromContext(ctx), logging.Report, logging.ErrorStatus, logging.ErrSection(err))
+					t.Fail()
+				} else {
+					logging.Warn(logging.FromContext(ctx), logging.Report, logging.WarnStatus, logging.ErrSection(err))
+				}
+			}
 		}
 	})
 	var nspacer namespacer.Namespacer
@@ -88,64 +102,104 @@ func (p *testsProcessor) Run(ctx context.Context, bindings binding.Bindings) {
 				bindings = apibindings.RegisterNamedBinding(ctx, bindings, "namespace", object.GetName())
 			}
 			nspacer = namespacer.New(cluster, object.GetName())
+			// the lines of the tests sharing the namespace of the suite are logged in it
+			ctx = logging.WithOptions(ctx, logging.WithTestNamespace(object.GetName()))
 			if err := cluster.Get(ctx, client.ObjectKey(&object), object.DeepCopy()); err != nil {
 				if !errors.IsNotFound(err) {
 					// Get doesn't log
-					logging.Log(ctx, logging.Get, logging.ErrorStatus, color.BoldRed, logging.ErrSection(err))
+					logging.Failure(logging.FromContext(ctx), logging.Get, logging.ErrorStatus, logging.ErrSection(err))
 					t.FailNow()
 				}
-				if !cleanup.Skip(p.config.SkipDelete, nil, nil) {
-					t.Cleanup(func() {
-						operation := newOperation(
-							OperationInfo{},
-							false,
-							timeout.Get(nil, p.config.Timeouts.CleanupDuration()),
-							opdelete.New(cluster, object, nspacer, false),
-							nil,
-							config,
-							cluster,
-						)
-						operation.execute(ctx, bindings)
-					})
-				}
-				if err := cluster.Create(ctx, object.DeepCopy()); err != nil {
-					t.FailNow()
+				if p.config.DryRun {
+					// the server rejects the resources of a namespace that doesn't exist, they are validated in the default one
+					if err := client.DryRun(cluster).Create(ctx, object.DeepCopy()); err != nil {
+						t.FailNow()
+					}
+					bindings = apibindings.RegisterNamedBinding(ctx, bindings, "namespace", metav1.NamespaceDefault)
+					nspacer = namespacer.New(cluster, metav1.NamespaceDefault)
+				} else {
+					if !cleanup.Skip(p.config.SkipDelete, nil, nil) {
+						t.Cleanup(func() {
+							operation := newOperation(
+								OperationInfo{},
+								false,
+								timeout.Get(nil, p.config.Timeouts.CleanupDuration()),
+								opdelete.New(cluster, object, nspacer, false),
+								nil,
+								config,
+								cluster,
+							)
+							operation.execute(ctx, bindings)
+						})
+					}
+					if err := cluster.Create(ctx, object.DeepCopy()); err != nil {
+						t.FailNow()
+					}
 				}
 			}
 		}
 	}
 	bindings, err := apibindings.RegisterBindings(ctx, bindings)
 	if err != nil {
-		logging.Log(ctx, logging.Internal, logging.ErrorStatus, color.BoldRed, logging.ErrSection(err))
+		logging.Failure(logging.FromContext(ctx), logging.Internal, logging.ErrorStatus, logging.ErrSection(err))
 		t.FailNow()
 	}
 	for i, test := range p.tests {
 		name, err := names.Test(p.config, test)
 		if err != nil {
-			logging.Log(ctx, logging.Internal, logging.ErrorStatus, color.BoldRed, logging.ErrSection(err))
+			logging.Failure(logging.FromContext(ctx), logging.Internal, logging.ErrorStatus, logging.ErrSection(err))
 			t.FailNow()
 		}
-		t.Run(name, func(t *testing.T) {
-			t.Helper()
-			t.Cleanup(func() {
-				if t.Failed() {
-					p.shouldFailFast.Store(true)
-				}
+		// the iterations of a test run as separate tests, concurrently if the test is concurrent
+		iterations := Iterations(p.config, test)
+		results := newIterationResults(p.config, test)
+		for iteration := 1; iteration <= iterations; iteration++ {
+			testName, testIteration := name, 0
+			if iterations > 1 {
+				testName, testIteration = report.IterationName(name, iteration), iteration
+			}
+			t.Run(testName, func(t *testing.T) {
+				t.Helper()
+				// failures of quarantined tests don't stop the run
+				isQuarantined := quarantined(p.config, test)
+				t.Cleanup(func() {
+					if t.Failed() && !isQuarantined {
+						p.failFast.stop()
+					}
+				})
+				processor := p.createTestProcessor(test, testIteration, results)
+				processor.Run(
+					testing.IntoContext(ctx, t),
+					apibindings.RegisterNamedBinding(ctx, bindings, "test", TestInfo{Id: i + 1}),
+					nspacer,
+				)
 			})
-			processor := p.CreateTestProcessor(test)
-			processor.Run(
-				testing.IntoContext(ctx, t),
-				apibindings.RegisterNamedBinding(ctx, bindings, "test", TestInfo{Id: i + 1}),
-				nspacer,
-			)
-		})
+		}
 	}
 }
 
 func (p *testsProcessor) CreateTestProcessor(test discovery.Test) TestProcessor {
-	testReport := report.NewTest(test.Name)
+	return p.createTestProcessor(test, 0, nil)
+}
+
+// createTestProcessor returns the processor of an iteration of test, numbered from 1, 0 if the test runs once.
+func (p *testsProcessor) createTestProcessor(test discovery.Test, iteration int, results *iterationResults) *testProcessor {
+	name := test.Name
+	if iteration != 0 {
+		name = report.IterationName(name, iteration)
+	}
+	testReport := report.NewTestWithClock(name, p.clock)
+	testReport.Iteration = iteration
+	testReport.Path = test.BasePath
+	testReport.Labels = test.Labels
+	testReport.Quarantined = quarantined(p.config, test)
+	// concurrent tests are reported so even when the parallelism limit runs them one at a time
+	testReport.Concurrent = test.Spec.Concurrent == nil || *test.Spec.Concurrent
 	if p.testsReport != nil {
 		p.testsReport.AddTest(testReport)
 	}
-	return NewTestProcessor(p.config, p.clusters, p.clock, p.summary, testReport, test, &p.shouldFailFast)
+	processor := newTestProcessor(p.config, p.clusters, p.clock, p.summary, testReport, test, p.failFast, p.workers)
+	processor.iteration = iteration
+	processor.iterationResults = results
+	return processor
 }
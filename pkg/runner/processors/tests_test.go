@@ -2,7 +2,9 @@ package processors
 
 import (
 	"context"
-	"sync/atomic"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/jmespath-community/go-jmespath/pkg/binding"
@@ -11,12 +13,15 @@ import (
 	fake "github.com/kyverno/chainsaw/pkg/client/testing"
 	"github.com/kyverno/chainsaw/pkg/discovery"
 	"github.com/kyverno/chainsaw/pkg/report"
+	"github.com/kyverno/chainsaw/pkg/runner/logging"
 	"github.com/kyverno/chainsaw/pkg/runner/summary"
 	"github.com/kyverno/chainsaw/pkg/testing"
 	"github.com/stretchr/testify/assert"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/clock"
 	tclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -229,15 +234,14 @@ func TestCreateTestProcessor(t *testing.T) {
 				}
 			}
 			processor := testsProcessor{
-				config:         localTC.config,
-				clusters:       clusters,
-				clock:          localTC.clock,
-				summary:        localTC.summary,
-				testsReport:    localTC.testsReport,
-				tests:          localTC.test,
-				shouldFailFast: atomic.Bool{},
+				config:      localTC.config,
+				clusters:    clusters,
+				clock:       localTC.clock,
+				summary:     localTC.summary,
+				testsReport: localTC.testsReport,
+				tests:       localTC.test,
+				failFast:    newFailFast(false),
 			}
-			processor.shouldFailFast.Store(false)
 
 			result := processor.CreateTestProcessor(localTC.test[0])
 
@@ -248,3 +252,109 @@ func TestCreateTestProcessor(t *testing.T) {
 		})
 	}
 }
+
+func TestTestsProcessor_Parallel(t *testing.T) {
+	var tests []discovery.Test
+	for _, name := range []string{"a", "b", "c"} {
+		tests = append(tests, discovery.Test{
+			Test: &v1alpha1.Test{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+			},
+		})
+	}
+	testsReport := report.NewTests("chainsaw")
+	processor := NewTestsProcessor(v1alpha1.ConfigurationSpec{Parallel: ptr.To(1)}, NewClusters(), tclock.NewFakePassiveClock(time.Now()), nil, testsReport, tests...)
+	// the cleanups of a real test run when it completes, and its parallel sub tests complete before
+	t.Run("run", func(t *testing.T) {
+		processor.Run(testing.IntoContext(context.Background(), t), nil)
+	})
+	// a limit of one runs the concurrent tests one at a time, in the same slot, and they are still reported as concurrent
+	assert.Len(t, testsReport.Reports, 3)
+	for _, test := range testsReport.Reports {
+		assert.Equal(t, 1, test.Worker)
+		assert.True(t, test.Concurrent)
+	}
+}
+
+func TestTestsProcessor_Iterations(t *testing.T) {
+	tests := []discovery.Test{{
+		Test: &v1alpha1.Test{
+			ObjectMeta: metav1.ObjectMeta{Name: "flaky"},
+			Spec:       v1alpha1.TestSpec{Iterations: ptr.To(3)},
+		},
+	}, {
+		Test: &v1alpha1.Test{
+			ObjectMeta: metav1.ObjectMeta{Name: "once"},
+		},
+	}}
+	var s summary.Summary
+	testsReport := report.NewTests("chainsaw")
+	processor := NewTestsProcessor(v1alpha1.ConfigurationSpec{}, NewClusters(), tclock.NewFakePassiveClock(time.Now()), &s, testsReport, tests...)
+	t.Run("run", func(t *testing.T) {
+		processor.Run(testing.IntoContext(context.Background(), t), nil)
+	})
+	// each iteration is reported as a separate test
+	var names []string
+	var iterations []int
+	for _, test := range testsReport.Reports {
+		names = append(names, test.Name)
+		iterations = append(iterations, test.Iteration)
+	}
+	assert.Equal(t, []string{"flaky#1", "flaky#2", "flaky#3", "once"}, names)
+	assert.Equal(t, []int{1, 2, 3, 0}, iterations)
+	assert.Equal(t, int32(4), s.Passed())
+}
+
+func TestTestsProcessor_IterationsOutput(t *testing.T) {
+	clusters := NewClusters()
+	clusters.clients[DefaultClient] = cluster{
+		client: &fake.FakeClient{
+			GetFn: func(ctx context.Context, call int, key ctrlclient.ObjectKey, obj ctrlclient.Object, opts ...ctrlclient.GetOption) error {
+				return nil
+			},
+		},
+	}
+	tests := []discovery.Test{{
+		BasePath: "tests/flaky",
+		Test: &v1alpha1.Test{
+			ObjectMeta: metav1.ObjectMeta{Name: "flaky"},
+			Spec: v1alpha1.TestSpec{
+				Iterations: ptr.To(3),
+				Steps: []v1alpha1.TestStep{{
+					TestStepSpec: v1alpha1.TestStepSpec{
+						Try: []v1alpha1.Operation{{
+							Sleep: &v1alpha1.Sleep{Duration: metav1.Duration{Duration: 10 * time.Millisecond}},
+						}},
+					},
+				}},
+			},
+		},
+	}}
+	dir := t.TempDir()
+	tee, err := logging.NewPerTestTee(dir)
+	assert.NoError(t, err)
+	fakeClock := tclock.NewFakePassiveClock(time.Now())
+	testsReport := report.NewTests("chainsaw")
+	processor := NewTestsProcessor(v1alpha1.ConfigurationSpec{ReportLogs: true}, clusters, fakeClock, nil, testsReport, tests...)
+	// the iterations of a concurrent test run concurrently
+	t.Run("run", func(t *testing.T) {
+		options := []logging.Option{logging.WithVerbose(true), logging.WithTee(tee)}
+		ctx := logging.WithOptions(testing.IntoContext(context.Background(), t), options...)
+		ctx = logging.IntoContext(ctx, logging.NewLogger(t, fakeClock, t.Name(), "@main", options...))
+		processor.Run(ctx, nil)
+	})
+	assert.NoError(t, tee.Close())
+	// each iteration gets its own lines, and its own log file
+	assert.Len(t, testsReport.Reports, 3)
+	for _, test := range testsReport.Reports {
+		assert.NotEmpty(t, test.Logs)
+		for _, line := range test.Logs {
+			assert.Contains(t, line.Message, "| "+test.Name+" |")
+		}
+		path := filepath.Join(dir, logging.TestFileName(test.Name))
+		assert.Equal(t, []string{path}, test.Artifacts)
+		data, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, len(test.Logs), strings.Count(string(data), "| "+test.Name+" |"))
+	}
+}
@@ -0,0 +1,61 @@
+package processors
+
+import (
+	"sync"
+)
+
+// workers assigns worker slots to the running tests, a test takes the lowest free slot when it starts running
+// and frees it when it completes, so slots stay within the number of tests running at once.
+// With a limit, no more than limit slots are busy at once, tests wait for a free slot before running.
+type workers struct {
+	lock  sync.Mutex
+	freed *sync.Cond
+	limit int
+	busy  []bool
+}
+
+// newWorkers returns workers running up to limit tests at once, without limit if it is not positive.
+func newWorkers(limit int) *workers {
+	return &workers{limit: limit}
+}
+
+// acquire returns the lowest free slot, numbered from 1, it waits for a slot to be freed when limit slots are busy.
+// A nil workers assigns no slot and returns 0.
+func (w *workers) acquire() int {
+	if w == nil {
+		return 0
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	for {
+		for i, busy := range w.busy {
+			if !busy {
+				w.busy[i] = true
+				return i + 1
+			}
+		}
+		if w.limit <= 0 || len(w.busy) < w.limit {
+			w.busy = append(w.busy, true)
+			return len(w.busy)
+		}
+		if w.freed == nil {
+			w.freed = sync.NewCond(&w.lock)
+		}
+		w.freed.Wait()
+	}
+}
+
+// release frees slot, slots not acquired are ignored.
+func (w *workers) release(slot int) {
+	if w == nil {
+		return
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if slot > 0 && slot <= len(w.busy) {
+		w.busy[slot-1] = false
+		if w.freed != nil {
+			w.freed.Signal()
+		}
+	}
+}
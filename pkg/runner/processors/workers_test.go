@@ -0,0 +1,82 @@
+package processors
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+func TestWorkers(t *testing.T) {
+	var w workers
+	assert.Equal(t, 1, w.acquire())
+	assert.Equal(t, 2, w.acquire())
+	assert.Equal(t, 3, w.acquire())
+	// freed slots are reused, lowest first
+	w.release(3)
+	w.release(1)
+	assert.Equal(t, 1, w.acquire())
+	assert.Equal(t, 3, w.acquire())
+	assert.Equal(t, 4, w.acquire())
+	// unknown slots are ignored
+	w.release(0)
+	w.release(10)
+	assert.Equal(t, 5, w.acquire())
+	// without workers no slot is assigned
+	var none *workers
+	assert.Zero(t, none.acquire())
+	none.release(1)
+}
+
+func TestWorkers_Limit(t *testing.T) {
+	for _, limit := range []int{1, 2, 3} {
+		fakeClock := tclock.NewFakeClock(time.Now())
+		w := newWorkers(limit)
+		var running, overlap, outOfRange atomic.Int32
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				slot := w.acquire()
+				defer w.release(slot)
+				if slot < 1 || slot > limit {
+					outOfRange.Add(1)
+				}
+				current := running.Add(1)
+				for {
+					highest := overlap.Load()
+					if current <= highest || overlap.CompareAndSwap(highest, current) {
+						break
+					}
+				}
+				// every test runs for a second of the fake clock
+				<-fakeClock.After(time.Second)
+				running.Add(-1)
+			}()
+		}
+		done := make(chan struct{})
+		go func() {
+			wg.Wait()
+			close(done)
+		}()
+	wait:
+		for {
+			select {
+			case <-done:
+				break wait
+			default:
+				if fakeClock.HasWaiters() {
+					fakeClock.Step(time.Second)
+				}
+				runtime.Gosched()
+			}
+		}
+		assert.LessOrEqual(t, overlap.Load(), int32(limit))
+		assert.Zero(t, outOfRange.Load())
+	}
+}
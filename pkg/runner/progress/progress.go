@@ -0,0 +1,61 @@
+package progress
+
+import (
+	"sync"
+
+	"github.com/kyverno/chainsaw/pkg/runner/events"
+)
+
+// Counters are the test counts of a run.
+type Counters struct {
+	// Total is the number of tests expected to run, zero when unknown.
+	Total int
+	// Started is the number of tests that started running.
+	Started int
+	// Passed, Failed and Skipped are the numbers of tests completed with each outcome.
+	Passed  int
+	Failed  int
+	Skipped int
+}
+
+// Done returns the number of completed tests, whatever their outcome.
+func (c Counters) Done() int {
+	return c.Passed + c.Failed + c.Skipped
+}
+
+// Tracker counts the tests of a run from the events published by the runner, it is safe for concurrent use.
+type Tracker struct {
+	lock     sync.Mutex
+	counters Counters
+}
+
+// NewTracker returns a Tracker expecting total tests.
+func NewTracker(total int) *Tracker {
+	return &Tracker{counters: Counters{Total: total}}
+}
+
+// Handle updates the counters, it is meant to be subscribed to the runner events bus.
+func (t *Tracker) Handle(event events.Event) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	switch event.Type {
+	case events.TestStarted:
+		t.counters.Started++
+	case events.TestFinished:
+		switch {
+		case event.Failed:
+			t.counters.Failed++
+		case event.Skipped:
+			t.counters.Skipped++
+		default:
+			t.counters.Passed++
+		}
+	}
+}
+
+// Counters returns a snapshot of the counters.
+func (t *Tracker) Counters() Counters {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.counters
+}
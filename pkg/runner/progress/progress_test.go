@@ -0,0 +1,27 @@
+package progress
+
+import (
+	"testing"
+
+	"github.com/kyverno/chainsaw/pkg/runner/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracker(t *testing.T) {
+	tracker := NewTracker(4)
+	for _, event := range []events.Event{
+		{Type: events.TestStarted, Test: "first"},
+		{Type: events.TestStarted, Test: "second"},
+		{Type: events.StepStarted, Test: "first", Step: "step-1"},
+		{Type: events.TestFinished, Test: "first"},
+		{Type: events.TestFinished, Test: "second", Failed: true},
+		// skipped tests can finish without starting
+		{Type: events.TestFinished, Test: "third", Skipped: true},
+		{Type: events.TestStarted, Test: "fourth"},
+	} {
+		tracker.Handle(event)
+	}
+	counters := tracker.Counters()
+	assert.Equal(t, Counters{Total: 4, Started: 3, Passed: 1, Failed: 1, Skipped: 1}, counters)
+	assert.Equal(t, 3, counters.Done())
+}
@@ -0,0 +1,148 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/runner/events"
+	"k8s.io/utils/clock"
+)
+
+// clearLine moves the cursor to the start of the line and clears it.
+const clearLine = "\r\x1b[K"
+
+// Reporter prints the progress of a run, either every interval or, with a zero interval, after each test completion.
+// Interactive reporters rewrite a single status line instead of appending lines. It is safe for concurrent use.
+type Reporter struct {
+	lock        sync.Mutex
+	out         io.Writer
+	clock       clock.PassiveClock
+	tracker     *Tracker
+	interval    time.Duration
+	interactive bool
+	start       time.Time
+	drawn       bool
+	stop        chan struct{}
+	done        chan struct{}
+}
+
+// NewReporter returns a Reporter printing the counters of tracker to out.
+func NewReporter(out io.Writer, clock clock.PassiveClock, tracker *Tracker, interval time.Duration, interactive bool) *Reporter {
+	return &Reporter{
+		out:         out,
+		clock:       clock,
+		tracker:     tracker,
+		interval:    interval,
+		interactive: interactive,
+		start:       clock.Now(),
+	}
+}
+
+// Handle updates the counters and prints the progress after test completions when there is no interval,
+// it is meant to be subscribed to the runner events bus.
+func (r *Reporter) Handle(event events.Event) {
+	r.tracker.Handle(event)
+	if event.Type == events.TestFinished && r.interval <= 0 {
+		r.print()
+	}
+}
+
+// Line returns the progress line, like "progress: 132/400 done, 3 failed, elapsed 18m".
+func (r *Reporter) Line() string {
+	counters := r.tracker.Counters()
+	done := fmt.Sprint(counters.Done())
+	if counters.Total > 0 {
+		done += fmt.Sprintf("/%d", counters.Total)
+	}
+	return fmt.Sprintf("progress: %s done, %d failed, elapsed %s", done, counters.Failed, formatElapsed(r.clock.Since(r.start)))
+}
+
+// Start prints the progress every interval until Stop is called, it does nothing without an interval.
+func (r *Reporter) Start() {
+	if r.interval <= 0 {
+		return
+	}
+	r.stop, r.done = make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(r.done)
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				r.print()
+			}
+		}
+	}()
+}
+
+// Stop stops the periodic printing and prints the final progress, ending the status line of interactive reporters.
+func (r *Reporter) Stop() {
+	if r.stop != nil {
+		close(r.stop)
+		<-r.done
+		r.stop = nil
+	}
+	r.print()
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.drawn {
+		fmt.Fprintln(r.out)
+		r.drawn = false
+	}
+}
+
+func (r *Reporter) print() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.interactive {
+		fmt.Fprint(r.out, clearLine+r.Line())
+		r.drawn = true
+	} else {
+		fmt.Fprintln(r.out, r.Line())
+	}
+}
+
+// Writer returns a writer to w that doesn't garble the status line of interactive reporters,
+// the line is cleared before each write and drawn again after it. Writes are expected to be whole lines.
+func (r *Reporter) Writer(w io.Writer) io.Writer {
+	if !r.interactive {
+		return w
+	}
+	return &writer{reporter: r, w: w}
+}
+
+type writer struct {
+	reporter *Reporter
+	w        io.Writer
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	r := w.reporter
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.drawn {
+		fmt.Fprint(r.out, clearLine)
+	}
+	n, err := w.w.Write(p)
+	if r.drawn {
+		fmt.Fprint(r.out, r.Line())
+	}
+	return n, err
+}
+
+// formatElapsed formats d in seconds below a minute, in minutes below an hour, in hours and minutes above.
+func formatElapsed(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d/time.Second))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d/time.Minute))
+	default:
+		return fmt.Sprintf("%dh%02dm", int(d/time.Hour), int(d%time.Hour/time.Minute))
+	}
+}
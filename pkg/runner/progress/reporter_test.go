@@ -0,0 +1,77 @@
+package progress
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/runner/events"
+	"github.com/stretchr/testify/assert"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+func TestReporter_Plain(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	clock := tclock.NewFakePassiveClock(start)
+	var out bytes.Buffer
+	reporter := NewReporter(&out, clock, NewTracker(400), 0, false)
+	reporter.Start()
+	reporter.Handle(events.Event{Type: events.TestStarted, Test: "first"})
+	assert.Empty(t, out.String())
+	clock.SetTime(start.Add(18*time.Minute + 30*time.Second))
+	reporter.Handle(events.Event{Type: events.TestFinished, Test: "first", Failed: true})
+	reporter.Stop()
+	// a line is printed after each completion, and when stopped
+	assert.Equal(t, "progress: 1/400 done, 1 failed, elapsed 18m\nprogress: 1/400 done, 1 failed, elapsed 18m\n", out.String())
+	// writes are untouched
+	assert.Equal(t, &out, reporter.Writer(&out))
+}
+
+func TestReporter_Interactive(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	clock := tclock.NewFakePassiveClock(start)
+	var status, logs bytes.Buffer
+	reporter := NewReporter(&status, clock, NewTracker(0), time.Hour, true)
+	reporter.Start()
+	w := reporter.Writer(&logs)
+	// nothing to clear before the status line is drawn
+	_, err := fmt.Fprintln(w, "before")
+	assert.NoError(t, err)
+	assert.Empty(t, status.String())
+	reporter.Handle(events.Event{Type: events.TestFinished, Test: "first"})
+	assert.Empty(t, status.String())
+	reporter.print()
+	assert.Equal(t, "\r\x1b[Kprogress: 1 done, 0 failed, elapsed 0s", status.String())
+	status.Reset()
+	// the status line is cleared before writes and drawn again after them
+	_, err = fmt.Fprintln(w, "after")
+	assert.NoError(t, err)
+	assert.Equal(t, "\r\x1b[Kprogress: 1 done, 0 failed, elapsed 0s", status.String())
+	assert.Equal(t, "before\nafter\n", logs.String())
+	status.Reset()
+	clock.SetTime(start.Add(2*time.Hour + 5*time.Minute))
+	reporter.Stop()
+	assert.Equal(t, "\r\x1b[Kprogress: 1 done, 0 failed, elapsed 2h05m\n", status.String())
+}
+
+func Test_formatElapsed(t *testing.T) {
+	tests := []struct {
+		elapsed time.Duration
+		want    string
+	}{{
+		elapsed: 42*time.Second + 500*time.Millisecond,
+		want:    "42s",
+	}, {
+		elapsed: 18*time.Minute + 59*time.Second,
+		want:    "18m",
+	}, {
+		elapsed: time.Hour + 5*time.Minute,
+		want:    "1h05m",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, formatElapsed(tt.elapsed))
+		})
+	}
+}
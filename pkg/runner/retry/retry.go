@@ -0,0 +1,96 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+)
+
+const (
+	// DefaultInterval is the delay before the first retry of an operation when its policy has none.
+	DefaultInterval = time.Second
+	// DefaultFactor multiplies the delay after each retry of an operation when its policy has none.
+	DefaultFactor = 2
+)
+
+// Classes returns the classes of errors operations can be retried on.
+func Classes() []v1alpha1.RetryOn {
+	return []v1alpha1.RetryOn{
+		v1alpha1.RetryOnConflict,
+		v1alpha1.RetryOnServerTimeout,
+		v1alpha1.RetryOnTooManyRequests,
+		v1alpha1.RetryOnNetworkError,
+	}
+}
+
+// Classify returns the class of err, false if err is not transient.
+func Classify(err error) (v1alpha1.RetryOn, bool) {
+	switch {
+	case err == nil:
+		return "", false
+	// the operation ran out of time or the test is stopping, the context errors are net errors too
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return "", false
+	case kerrors.IsConflict(err):
+		return v1alpha1.RetryOnConflict, true
+	case kerrors.IsServerTimeout(err), kerrors.IsTimeout(err):
+		return v1alpha1.RetryOnServerTimeout, true
+	case kerrors.IsTooManyRequests(err):
+		return v1alpha1.RetryOnTooManyRequests, true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) || utilnet.IsConnectionRefused(err) || utilnet.IsConnectionReset(err) || utilnet.IsProbableEOF(err) {
+		return v1alpha1.RetryOnNetworkError, true
+	}
+	return "", false
+}
+
+// Retryable returns true if err belongs to one of the classes, to any of them if classes is empty.
+func Retryable(err error, classes ...v1alpha1.RetryOn) bool {
+	class, ok := Classify(err)
+	if !ok {
+		return false
+	}
+	if len(classes) == 0 {
+		return true
+	}
+	for _, c := range classes {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// Delay returns the delay before the given retry of policy, numbered from 1.
+func Delay(policy v1alpha1.Retry, retry int) time.Duration {
+	delay := DefaultInterval
+	if policy.Interval != nil {
+		delay = policy.Interval.Duration
+	}
+	factor := DefaultFactor
+	if policy.Factor != nil {
+		factor = *policy.Factor
+	}
+	for i := 1; i < retry; i++ {
+		delay *= time.Duration(factor)
+	}
+	return delay
+}
+
+// Wait waits for delay, it returns the error of ctx if it is done first.
+func Wait(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,158 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+)
+
+func TestClassify(t *testing.T) {
+	resource := schema.GroupResource{Resource: "configmaps"}
+	tests := []struct {
+		name   string
+		err    error
+		want   v1alpha1.RetryOn
+		wantOk bool
+	}{{
+		name: "nil",
+	}, {
+		name: "not transient",
+		err:  kerrors.NewNotFound(resource, "foo"),
+	}, {
+		name:   "conflict",
+		err:    kerrors.NewConflict(resource, "foo", errors.New("the object has been modified")),
+		want:   v1alpha1.RetryOnConflict,
+		wantOk: true,
+	}, {
+		name:   "wrapped conflict",
+		err:    fmt.Errorf("failed to update: %w", kerrors.NewConflict(resource, "foo", errors.New("the object has been modified"))),
+		want:   v1alpha1.RetryOnConflict,
+		wantOk: true,
+	}, {
+		name:   "server timeout",
+		err:    kerrors.NewServerTimeout(resource, "create", 1),
+		want:   v1alpha1.RetryOnServerTimeout,
+		wantOk: true,
+	}, {
+		name:   "gateway timeout",
+		err:    kerrors.NewTimeoutError("the request timed out", 1),
+		want:   v1alpha1.RetryOnServerTimeout,
+		wantOk: true,
+	}, {
+		name:   "too many requests",
+		err:    kerrors.NewTooManyRequests("slow down", 1),
+		want:   v1alpha1.RetryOnTooManyRequests,
+		wantOk: true,
+	}, {
+		name:   "connection refused",
+		err:    &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED},
+		want:   v1alpha1.RetryOnNetworkError,
+		wantOk: true,
+	}, {
+		name:   "connection reset",
+		err:    fmt.Errorf("read: %w", syscall.ECONNRESET),
+		want:   v1alpha1.RetryOnNetworkError,
+		wantOk: true,
+	}, {
+		name: "deadline exceeded",
+		err:  context.DeadlineExceeded,
+	}, {
+		name: "canceled",
+		err:  fmt.Errorf("failed: %w", context.Canceled),
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Classify(tt.err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantOk, ok)
+		})
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	conflict := kerrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, "foo", errors.New("the object has been modified"))
+	tests := []struct {
+		name    string
+		err     error
+		classes []v1alpha1.RetryOn
+		want    bool
+	}{{
+		name: "all classes",
+		err:  conflict,
+		want: true,
+	}, {
+		name:    "listed class",
+		err:     conflict,
+		classes: []v1alpha1.RetryOn{v1alpha1.RetryOnNetworkError, v1alpha1.RetryOnConflict},
+		want:    true,
+	}, {
+		name:    "unlisted class",
+		err:     conflict,
+		classes: []v1alpha1.RetryOn{v1alpha1.RetryOnNetworkError},
+		want:    false,
+	}, {
+		name: "not transient",
+		err:  errors.New("boom"),
+		want: false,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Retryable(tt.err, tt.classes...))
+		})
+	}
+}
+
+func TestDelay(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy v1alpha1.Retry
+		want   []time.Duration
+	}{{
+		name:   "defaults",
+		policy: v1alpha1.Retry{Attempts: 4},
+		want:   []time.Duration{time.Second, 2 * time.Second, 4 * time.Second},
+	}, {
+		name: "interval and factor",
+		policy: v1alpha1.Retry{
+			Attempts: 4,
+			Interval: &metav1.Duration{Duration: 100 * time.Millisecond},
+			Factor:   ptr.To(3),
+		},
+		want: []time.Duration{100 * time.Millisecond, 300 * time.Millisecond, 900 * time.Millisecond},
+	}, {
+		name: "constant",
+		policy: v1alpha1.Retry{
+			Attempts: 3,
+			Interval: &metav1.Duration{Duration: time.Second},
+			Factor:   ptr.To(1),
+		},
+		want: []time.Duration{time.Second, time.Second},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []time.Duration
+			for retry := 1; retry < tt.policy.Attempts; retry++ {
+				got = append(got, Delay(tt.policy, retry))
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWait(t *testing.T) {
+	assert.NoError(t, Wait(context.Background(), time.Millisecond))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, Wait(ctx, time.Hour), context.Canceled)
+}
@@ -2,24 +2,40 @@ package runner
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"os"
+	"time"
 
 	"github.com/jmespath-community/go-jmespath/pkg/binding"
 	"github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
 	"github.com/kyverno/chainsaw/pkg/discovery"
 	"github.com/kyverno/chainsaw/pkg/report"
+	"github.com/kyverno/chainsaw/pkg/report/otlp"
 	apibindings "github.com/kyverno/chainsaw/pkg/runner/bindings"
+	"github.com/kyverno/chainsaw/pkg/runner/dashboard"
+	"github.com/kyverno/chainsaw/pkg/runner/events"
 	"github.com/kyverno/chainsaw/pkg/runner/internal"
 	"github.com/kyverno/chainsaw/pkg/runner/logging"
 	"github.com/kyverno/chainsaw/pkg/runner/processors"
+	"github.com/kyverno/chainsaw/pkg/runner/progress"
 	"github.com/kyverno/chainsaw/pkg/runner/summary"
 	"github.com/kyverno/chainsaw/pkg/testing"
 	restutils "github.com/kyverno/chainsaw/pkg/utils/rest"
+	"golang.org/x/term"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/utils/clock"
 )
 
+// stdout and stderr are where the summary of the run is printed, they can be overridden in tests.
+var (
+	stdout io.Writer = os.Stdout
+	stderr io.Writer = os.Stderr
+)
+
 type mainstart interface {
 	Run() int
 }
@@ -43,16 +59,172 @@ func run(
 	tests ...discovery.Test,
 ) (*summary.Summary, error) {
 	var summary summary.Summary
-	var testsReport *report.TestsReport
-	if config.ReportFormat != "" {
-		testsReport = report.NewTests(config.ReportName)
-	}
+	// the run summary and notifications are rendered from the report, collect it even when it isn't saved
+	testsReport := report.NewTestsWithClock(report.SuiteName(config.ReportName), clock)
 	if len(tests) == 0 {
 		return &summary, nil
 	}
+	// tests are expected to live in the same repository, failing to find one is not an error
+	if gitInfoConsumed(config) {
+		testsReport.SetGit(report.LookupGitInfo(context.TODO(), tests[0].BasePath))
+	}
+	if config.Parallel != nil {
+		testsReport.Parallel = *config.Parallel
+	}
+	var journal *report.Journal
+	if config.ReportFormat != "" {
+		if _, err := report.GetSerializer(config.ReportFormat); err != nil {
+			return nil, err
+		}
+		reportName, err := testsReport.ResolveName(config.ReportName)
+		if err != nil {
+			return nil, err
+		}
+		// only local files get a journal, remote sinks and stdout are written once at the end
+		if filePath := report.FilePath(config.ReportFormat, config.ReportPath, reportName); report.IsFile(filePath) {
+			j, err := report.NewJournal(report.JournalPath(filePath), testsReport)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create test report journal: %v", err)
+			}
+			defer func() { _ = j.Close() }()
+			journal = j
+			testsReport.SetJournal(journal)
+		}
+	}
 	if err := internal.SetupFlags(config); err != nil {
 		return nil, err
 	}
+	// the counts of log lines read after the run are those of the run
+	logging.ResetCounts()
+	var bus *events.Bus
+	var board *dashboard.Dashboard
+	// the status line is rewritten in place only when test logs are printed through the sinks wrapping the console
+	reporter := progressReporter(config, clock, iterations(config, tests), isTerminal(stderr) && (githubGroupsEnabled(config) || config.LogBuffered || failuresOnlyEnabled(config)))
+	console := stdout
+	if reporter != nil {
+		console = reporter.Writer(stdout)
+	}
+	// the logs of passing tests are dropped, neither grouped nor buffered
+	failures := failureOutput(config, console)
+	var groups *logging.GitHubGroups
+	if failures == nil {
+		groups = githubGroups(config, console)
+	}
+	// grouped logs are already printed as a block per test
+	var ordered *logging.OrderedOutput
+	if failures == nil && groups == nil {
+		o, err := orderedOutput(config, console, clock, tests...)
+		if err != nil {
+			return nil, err
+		}
+		ordered = o
+	}
+	stream, closeStream, err := eventStream(config)
+	if err != nil {
+		return nil, err
+	}
+	if stream != nil {
+		defer func() {
+			if err := closeStream(); err != nil {
+				fmt.Fprintln(stderr, "Error:", err)
+			}
+		}()
+	}
+	// the exporter is created upfront so that a wrong protocol fails before tests run
+	exporter, err := traceExporter(config)
+	if err != nil {
+		return nil, err
+	}
+	if exporter != nil {
+		defer func() { _ = exporter.Shutdown(context.Background()) }()
+	}
+	if config.Dashboard || failures != nil || groups != nil || ordered != nil || reporter != nil || stream != nil {
+		// operations don't have a clock, their events are stamped by the bus
+		bus = events.NewBusWithClock(clock)
+	}
+	// passing tests are silent, the verbose output of the testing framework would still list them
+	if failures != nil {
+		if err := flag.Set("test.v", "false"); err != nil {
+			return nil, err
+		}
+	}
+	if config.Dashboard {
+		board = dashboard.New(stderr, clock)
+		bus.Subscribe(board.Handle)
+		// the dashboard is redrawn in place, verbose test output would scroll it away
+		if board.Interactive() {
+			if err := flag.Set("test.v", "false"); err != nil {
+				return nil, err
+			}
+		}
+	}
+	logOptions, closeLogs, err := loggerOptions(config, values)
+	if err != nil {
+		return nil, err
+	}
+	defer closeLogs()
+	// the sinks printing on the console get the lines of the configured operation types only
+	consoleTypes, err := consoleOperationTypes(config)
+	if err != nil {
+		return nil, err
+	}
+	if failures != nil {
+		logOptions = append(logOptions, logging.WithSink(logging.FilterOperationTypes(failures, consoleTypes...)))
+		bus.Subscribe(func(event events.Event) {
+			switch event.Type {
+			case events.TestStarted:
+				failures.Start(logging.TestKey{Path: event.Path, Name: event.Test})
+			case events.TestFinished:
+				if err := failures.Complete(logging.TestKey{Path: event.Path, Name: event.Test}, event.Failed); err != nil {
+					fmt.Fprintln(stderr, "Error:", err)
+				}
+			}
+		})
+	}
+	if groups != nil {
+		logOptions = append(logOptions, logging.WithSink(logging.FilterOperationTypes(groups, consoleTypes...)))
+		bus.Subscribe(func(event events.Event) {
+			switch event.Type {
+			case events.TestStarted:
+				groups.Start(logging.TestKey{Path: event.Path, Name: event.Test})
+			case events.TestFinished:
+				if err := groups.Complete(logging.TestKey{Path: event.Path, Name: event.Test}, event.Failed); err != nil {
+					fmt.Fprintln(stderr, "Error:", err)
+				}
+			}
+		})
+	}
+	if stream != nil {
+		// the resources of operations are taken from the lines they log
+		logOptions = append(logOptions, logging.WithSink(stream))
+		bus.Subscribe(func(event events.Event) {
+			if err := stream.WriteEvent(event); err != nil {
+				fmt.Fprintln(stderr, "Error:", err)
+			}
+		})
+	}
+	if ordered != nil {
+		logOptions = append(logOptions, logging.WithSink(logging.FilterOperationTypes(ordered, consoleTypes...)))
+		bus.Subscribe(func(event events.Event) {
+			switch event.Type {
+			case events.TestStarted:
+				ordered.Start(logging.TestKey{Path: event.Path, Name: event.Test})
+			case events.TestFinished:
+				if err := ordered.Complete(logging.TestKey{Path: event.Path, Name: event.Test}, event.Failed); err != nil {
+					fmt.Fprintln(stderr, "Error:", err)
+				}
+			}
+		})
+		ordered.StartHeartbeat(logging.DefaultHeartbeatInterval)
+	}
+	// klog lines, like the messages of client-go, go through the logs of the run, as lines of the runner
+	verbosityLevels := logging.DefaultVerbosityLevels()
+	if config.LogVerbosityLevels != "" {
+		if verbosityLevels, err = logging.ParseVerbosityLevels(config.LogVerbosityLevels); err != nil {
+			return nil, err
+		}
+	}
+	defer logging.InstallKlog(logging.NewWriterLogger(stderr, clock, logging.RunnerTest, "@klog", logOptions...), verbosityLevels)()
 	bindings := binding.NewBindings()
 	bindings = apibindings.RegisterNamedBinding(context.TODO(), bindings, "values", values)
 	clusters := processors.NewClusters()
@@ -80,7 +252,18 @@ func run(
 			t.Parallel()
 			processor := processors.NewTestsProcessor(config, clusters, clock, &summary, testsReport, tests...)
 			ctx := testing.IntoContext(context.Background(), t)
-			ctx = logging.IntoContext(ctx, logging.NewLogger(t, clock, t.Name(), "@main"))
+			ctx = logging.WithOptions(ctx, logOptions...)
+			if config.LogWaitInterval != nil {
+				ctx = logging.WithWaitInterval(ctx, config.LogWaitInterval.Duration)
+			}
+			ctx = logging.IntoContext(ctx, logging.NewLogger(t, clock, t.Name(), "@main", logOptions...))
+			if bus != nil {
+				ctx = events.IntoContext(ctx, bus)
+				// filtered, grouped and buffered test logs are printed when the test completes
+				if failures != nil || groups != nil || ordered != nil || (board != nil && board.Interactive()) {
+					ctx = logging.WithQuietConsole(ctx)
+				}
+			}
 			processor.Run(ctx, bindings)
 		},
 	}}
@@ -88,19 +271,214 @@ func run(
 	if m == nil {
 		m = testing.MainStart(deps, internalTests, nil, nil, nil)
 	}
+	// interrupted runs still write a report holding the tests that completed
+	var finalizer reportFinalizer
+	stopSignals := handleSignals(func() error {
+		return shutdown(config, testsReport, journal, &finalizer)
+	})
+	defer stopSignals()
+	if board != nil {
+		board.Start()
+	}
+	if reporter != nil {
+		bus.Subscribe(reporter.Handle)
+		reporter.Start()
+	}
 	// m.Run() returns:
 	// - 0 if everything went well
 	// - 1 if some of the tests failed
 	// - 2 if running the tests was not possible
 	// In our case, we consider an error only when running the tests was not possible.
 	// For now, the case where some of the tests failed will be covered by the summary.
-	if code := m.Run(); code > 1 {
+	if bus != nil {
+		total := iterations(config, tests)
+		if config.RepeatCount != nil && *config.RepeatCount > 1 {
+			total *= *config.RepeatCount
+		}
+		bus.Publish(events.Event{Type: events.SuiteStarted, Time: clock.Now(), Tests: total})
+	}
+	code := m.Run()
+	if bus != nil {
+		bus.Publish(events.Event{
+			Type: events.SuiteFinished,
+			Time: clock.Now(),
+			Summary: &events.Summary{
+				Passed:  int(summary.Passed()),
+				Failed:  int(summary.Failed()),
+				Skipped: int(summary.Skipped()),
+			},
+		})
+	}
+	if board != nil {
+		board.Stop()
+	}
+	if failures != nil {
+		if err := failures.Close(); err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+		}
+	}
+	if groups != nil {
+		if err := groups.Close(); err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+		}
+	}
+	if ordered != nil {
+		if err := ordered.Close(); err != nil {
+			fmt.Fprintln(stderr, "Error:", err)
+		}
+	}
+	if reporter != nil {
+		reporter.Stop()
+	}
+	if code > 1 {
 		return &summary, fmt.Errorf("testing framework exited with non zero code %d", code)
 	}
-	if testsReport != nil && config.ReportFormat != "" {
-		if err := testsReport.SaveReportBasedOnType(config.ReportFormat, config.ReportPath, config.ReportName); err != nil {
-			return &summary, fmt.Errorf("failed to save test report: %v", err)
+	// the summary is the last thing printed, it goes to stderr when the report is written to stdout
+	defer func() {
+		out := stdout
+		if config.ReportName == report.StdoutName {
+			out = stderr
+		}
+		_ = report.PrintSummary(out, testsReport, summaryOptions())
+	}()
+	// a failing sink doesn't keep the next ones from getting the report, their errors are returned together
+	var errs []error
+	if err := finalizer.save(config, testsReport, journal); err != nil {
+		errs = append(errs, err)
+	}
+	if config.NotificationURL != "" {
+		sink := report.NotificationSink{
+			URL:    config.NotificationURL,
+			Format: report.NotificationFormat(config.NotificationFormat),
+			Always: config.NotificationAlways,
+		}
+		if err := sink.Notify(context.Background(), testsReport); err != nil {
+			errs = append(errs, fmt.Errorf("failed to send notification: %w", err))
+		}
+	}
+	if config.PushgatewayURL != "" {
+		sink := report.NewPushgatewaySink(config.PushgatewayURL, config.PushgatewayJob, config.PushgatewayInstance)
+		if err := sink.Push(context.Background(), testsReport); err != nil {
+			errs = append(errs, fmt.Errorf("failed to push metrics: %w", err))
+		}
+	}
+	// an unreachable collector doesn't fail the run
+	if exporter != nil {
+		if err := report.ExportTrace(context.Background(), testsReport, exporter); err != nil {
+			fmt.Fprintln(stderr, "Warning: failed to export trace:", err)
+		}
+	}
+	return &summary, errors.Join(errs...)
+}
+
+// iterations returns the number of tests run, each iteration of a test counts as a test.
+func iterations(config v1alpha1.ConfigurationSpec, tests []discovery.Test) int {
+	total := 0
+	for _, test := range tests {
+		total += processors.Iterations(config, test)
+	}
+	return total
+}
+
+// progressReporter returns the reporter printing the progress of the run to stderr, or nil when it is disabled.
+// The dashboard already shows the progress, and the progress is never mixed with output parsed by machines.
+func progressReporter(config v1alpha1.ConfigurationSpec, clock clock.PassiveClock, tests int, interactive bool) *progress.Reporter {
+	if !config.Progress || config.Quiet || config.Dashboard || logging.Format(config.LogFormat) == logging.JSONFormat || config.ReportName == report.StdoutName {
+		return nil
+	}
+	total := tests
+	if config.RepeatCount != nil && *config.RepeatCount > 1 {
+		total *= *config.RepeatCount
+	}
+	var interval time.Duration
+	if config.ProgressInterval != nil {
+		interval = config.ProgressInterval.Duration
+	}
+	return progress.NewReporter(stderr, clock, progress.NewTracker(total), interval, interactive)
+}
+
+// isTerminal returns true if w is a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// saveReports writes the configured reports and removes the journal once they are written.
+func saveReports(config v1alpha1.ConfigurationSpec, testsReport *report.TestsReport, journal *report.Journal) error {
+	if config.ReportFormat == "" {
+		return nil
+	}
+	if config.ReportGroupBy != "" {
+		groupBy, err := report.ParseGroupBy(config.ReportGroupBy)
+		if err != nil {
+			return fmt.Errorf("failed to group test report: %w", err)
+		}
+		testsReport.GroupBy(groupBy)
+	}
+	if err := testsReport.SaveReportBasedOnType(config.ReportFormat, config.ReportPath, config.ReportName, report.SaveOptions{Retention: retention(config), UTC: logging.GetUTC()}); err != nil {
+		return fmt.Errorf("failed to save test report: %w", err)
+	}
+	// the failed tests report is only written next to file reports
+	if config.ReportFailures && report.IsFile(config.ReportName) {
+		name, err := testsReport.ResolveName(config.ReportName)
+		if err != nil {
+			return fmt.Errorf("failed to save failed tests report: %w", err)
+		}
+		failed := testsReport.FilterFailed()
+		if err := failed.SaveReportBasedOnType(config.ReportFormat, config.ReportPath, report.FailuresName(name), report.SaveOptions{UTC: logging.GetUTC()}); err != nil {
+			return fmt.Errorf("failed to save failed tests report: %w", err)
+		}
+	}
+	// the final report is written, the journal is not needed anymore
+	if journal != nil {
+		if err := journal.Remove(); err != nil {
+			return fmt.Errorf("failed to finalize test report journal: %v", err)
 		}
 	}
-	return &summary, nil
+	return nil
+}
+
+// retention returns the retention policy applied to saved reports, if configured.
+func retention(config v1alpha1.ConfigurationSpec) *report.RetentionPolicy {
+	if config.ReportRetentionCount == nil && config.ReportRetentionMaxAge == nil {
+		return nil
+	}
+	policy := report.RetentionPolicy{
+		DryRun: config.ReportRetentionDryRun,
+		OnRemove: func(path string) {
+			if config.ReportRetentionDryRun {
+				fmt.Fprintf(stdout, "- Would remove old report %s\n", path)
+			} else {
+				fmt.Fprintf(stdout, "- Removed old report %s\n", path)
+			}
+		},
+	}
+	if config.ReportRetentionCount != nil {
+		policy.Keep = *config.ReportRetentionCount
+	}
+	if config.ReportRetentionMaxAge != nil {
+		policy.MaxAge = config.ReportRetentionMaxAge.Duration
+	}
+	return &policy
+}
+
+// gitInfoConsumed returns true if the git revision of the tests ends up somewhere, in the saved report or in the trace of
+// the run, looking it up runs git several times. Notifications and pushed metrics don't carry it.
+func gitInfoConsumed(config v1alpha1.ConfigurationSpec) bool {
+	return config.ReportFormat != "" || config.OTLPEndpoint != ""
+}
+
+// traceExporter returns the exporter sending the trace of the run to an OTLP collector, or nil when it is disabled.
+func traceExporter(config v1alpha1.ConfigurationSpec) (*otlp.Exporter, error) {
+	if config.OTLPEndpoint == "" {
+		return nil, nil
+	}
+	exporter, err := otlp.NewOTLP(context.Background(), otlp.Options{
+		Endpoint: config.OTLPEndpoint,
+		Protocol: otlp.Protocol(config.OTLPProtocol),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+	}
+	return exporter, nil
 }
@@ -1,15 +1,26 @@
 package runner
 
 import (
+	"bytes"
+	"context"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
 	"github.com/kyverno/chainsaw/pkg/discovery"
+	"github.com/kyverno/chainsaw/pkg/report"
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/rest"
 	tclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
 )
 
 type MockMainStart struct {
@@ -152,3 +163,373 @@ func TestRun(t *testing.T) {
 		})
 	}
 }
+
+func TestRun_ReportFailures(t *testing.T) {
+	dir := t.TempDir()
+	config := v1alpha1.ConfigurationSpec{
+		ReportFormat:   v1alpha1.JSONFormat,
+		ReportPath:     dir,
+		ReportName:     "chainsaw-report",
+		ReportFailures: true,
+	}
+	tests := []discovery.Test{{
+		Test: &v1alpha1.Test{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test1",
+			},
+		},
+	}}
+	_, err := run(nil, tclock.NewFakePassiveClock(time.Now()), config, &MockMainStart{}, nil, tests...)
+	assert.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dir, "chainsaw-report.json"))
+	assert.NoError(t, err)
+	failed, err := report.Load(filepath.Join(dir, "chainsaw-report-failures.json"))
+	assert.NoError(t, err)
+	assert.Empty(t, failed.Reports)
+}
+
+func TestRun_TemplatedReportName(t *testing.T) {
+	dir := t.TempDir()
+	config := v1alpha1.ConfigurationSpec{
+		ReportFormat: v1alpha1.JSONFormat,
+		ReportPath:   dir,
+		ReportName:   "{{ .Name }}-{{ .Date }}",
+	}
+	tests := []discovery.Test{{
+		Test: &v1alpha1.Test{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test1",
+			},
+		},
+	}}
+	now := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	_, err := run(nil, tclock.NewFakePassiveClock(now), config, &MockMainStart{}, nil, tests...)
+	assert.NoError(t, err)
+	saved, err := report.Load(filepath.Join(dir, "chainsaw-2024-03-01.json"))
+	assert.NoError(t, err)
+	assert.Equal(t, report.DefaultSuiteName, saved.Name)
+}
+
+func TestRun_ReportRetention(t *testing.T) {
+	defer func(out io.Writer) { stdout = out }(stdout)
+	var out bytes.Buffer
+	stdout = &out
+	dir := t.TempDir()
+	old := filepath.Join(dir, "chainsaw-0123456789ab.json")
+	assert.NoError(t, os.WriteFile(old, []byte("{}"), 0o600))
+	other := filepath.Join(dir, "notes.json")
+	assert.NoError(t, os.WriteFile(other, []byte("{}"), 0o600))
+	config := v1alpha1.ConfigurationSpec{
+		ReportFormat:         v1alpha1.JSONFormat,
+		ReportPath:           dir,
+		ReportName:           "chainsaw-{{ .RunID }}",
+		ReportRetentionCount: ptr.To(1),
+	}
+	tests := []discovery.Test{{
+		Test: &v1alpha1.Test{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test1",
+			},
+		},
+	}}
+	_, err := run(nil, tclock.NewFakePassiveClock(time.Now()), config, &MockMainStart{}, nil, tests...)
+	assert.NoError(t, err)
+	assert.NoFileExists(t, old)
+	assert.FileExists(t, other)
+	assert.Contains(t, out.String(), "- Removed old report "+old+"\n")
+}
+
+func TestRun_Git(t *testing.T) {
+	dir := t.TempDir()
+	config := v1alpha1.ConfigurationSpec{
+		ReportFormat: v1alpha1.JSONFormat,
+		ReportPath:   dir,
+		ReportName:   "chainsaw-report",
+	}
+	// the tests of this package live in the chainsaw repository
+	tests := []discovery.Test{{
+		BasePath: ".",
+		Test: &v1alpha1.Test{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test1",
+			},
+		},
+	}}
+	_, err := run(nil, tclock.NewFakePassiveClock(time.Now()), config, &MockMainStart{}, nil, tests...)
+	assert.NoError(t, err)
+	saved, err := report.Load(filepath.Join(dir, "chainsaw-report.json"))
+	assert.NoError(t, err)
+	assert.Equal(t, report.LookupGitInfo(context.Background(), "."), saved.Git)
+}
+
+func Test_gitInfoConsumed(t *testing.T) {
+	tests := []struct {
+		name   string
+		config v1alpha1.ConfigurationSpec
+		want   bool
+	}{{
+		name: "nothing",
+	}, {
+		name:   "report",
+		config: v1alpha1.ConfigurationSpec{ReportFormat: v1alpha1.XMLFormat},
+		want:   true,
+	}, {
+		name:   "trace",
+		config: v1alpha1.ConfigurationSpec{OTLPEndpoint: "localhost:4318"},
+		want:   true,
+	}, {
+		name:   "notification",
+		config: v1alpha1.ConfigurationSpec{NotificationURL: "http://localhost"},
+	}, {
+		name:   "pushgateway",
+		config: v1alpha1.ConfigurationSpec{PushgatewayURL: "http://localhost"},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, gitInfoConsumed(tt.config))
+		})
+	}
+}
+
+func TestRun_Notification(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+	}))
+	defer server.Close()
+	config := v1alpha1.ConfigurationSpec{
+		ReportName:         "chainsaw-report",
+		NotificationURL:    server.URL,
+		NotificationFormat: string(report.NotificationFormatWebhook),
+		NotificationAlways: true,
+	}
+	tests := []discovery.Test{{
+		Test: &v1alpha1.Test{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test1",
+			},
+		},
+	}}
+	_, err := run(nil, tclock.NewFakePassiveClock(time.Now()), config, &MockMainStart{}, nil, tests...)
+	assert.NoError(t, err)
+	assert.Contains(t, body, `"suite":"chainsaw-report"`)
+	// the report itself isn't saved without a format
+	_, err = os.Stat("chainsaw-report")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRun_NotificationFailure(t *testing.T) {
+	notifications := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer notifications.Close()
+	var pushed bool
+	pushgateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pushed = pushed || r.Method == http.MethodPut
+	}))
+	defer pushgateway.Close()
+	config := v1alpha1.ConfigurationSpec{
+		ReportName:         "nightly",
+		NotificationURL:    notifications.URL,
+		NotificationAlways: true,
+		PushgatewayURL:     pushgateway.URL,
+	}
+	tests := []discovery.Test{{
+		Test: &v1alpha1.Test{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test1",
+			},
+		},
+	}}
+	_, err := run(nil, tclock.NewFakePassiveClock(time.Now()), config, &MockMainStart{}, nil, tests...)
+	// the metrics are pushed anyway, the notification error is returned
+	assert.True(t, pushed)
+	var uploadErr *report.UploadError
+	assert.ErrorAs(t, err, &uploadErr)
+	assert.ErrorContains(t, err, "failed to send notification")
+}
+
+func TestRun_Pushgateway(t *testing.T) {
+	var requests []string
+	var metrics string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		data, _ := io.ReadAll(r.Body)
+		if r.Method == http.MethodPut {
+			metrics = string(data)
+		}
+	}))
+	defer server.Close()
+	config := v1alpha1.ConfigurationSpec{
+		ReportName:     "nightly",
+		PushgatewayURL: server.URL,
+	}
+	tests := []discovery.Test{{
+		Test: &v1alpha1.Test{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test1",
+			},
+		},
+	}}
+	_, err := run(nil, tclock.NewFakePassiveClock(time.Now()), config, &MockMainStart{}, nil, tests...)
+	assert.NoError(t, err)
+	// the group is the same from one run to the next, the run ID is a label of the info metric
+	assert.Equal(t, []string{
+		"DELETE /metrics/job/chainsaw/instance/nightly",
+		"PUT /metrics/job/chainsaw/instance/nightly",
+	}, requests)
+	assert.Contains(t, metrics, "chainsaw_suite_info{run_id=")
+}
+
+func TestRun_Trace(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_INSECURE", "true")
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+	}))
+	defer server.Close()
+	config := v1alpha1.ConfigurationSpec{
+		OTLPEndpoint: strings.TrimPrefix(server.URL, "http://"),
+		OTLPProtocol: "http",
+	}
+	tests := []discovery.Test{{
+		Test: &v1alpha1.Test{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test1",
+			},
+		},
+	}}
+	_, err := run(nil, tclock.NewFakePassiveClock(time.Now()), config, &MockMainStart{}, nil, tests...)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/v1/traces"}, paths)
+	// a wrong protocol fails before tests run
+	config.OTLPProtocol = "thrift"
+	_, err = run(nil, tclock.NewFakePassiveClock(time.Now()), config, &MockMainStart{}, nil, tests...)
+	assert.EqualError(t, err, `failed to create trace exporter: unsupported OTLP protocol "thrift"`)
+}
+
+func TestRun_Summary(t *testing.T) {
+	defer func(out, err io.Writer) { stdout, stderr = out, err }(stdout, stderr)
+	tests := []discovery.Test{{
+		Test: &v1alpha1.Test{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test1",
+			},
+		},
+	}}
+	for _, name := range []string{"chainsaw-report", report.StdoutName} {
+		t.Run(name, func(t *testing.T) {
+			var out, err bytes.Buffer
+			stdout, stderr = &out, &err
+			_, runErr := run(nil, tclock.NewFakePassiveClock(time.Now()), v1alpha1.ConfigurationSpec{ReportName: name}, &MockMainStart{}, nil, tests...)
+			assert.NoError(t, runErr)
+			// the summary moves out of the way of reports written to stdout
+			if name == report.StdoutName {
+				assert.Empty(t, out.String())
+				assert.Equal(t, "Tests: 0 passed, 0 failed, 0 skipped\n", err.String())
+			} else {
+				assert.Equal(t, "Tests: 0 passed, 0 failed, 0 skipped\n", out.String())
+				assert.Empty(t, err.String())
+			}
+		})
+	}
+}
+
+func TestRun_Dashboard(t *testing.T) {
+	defer func(out, err io.Writer) { stdout, stderr = out, err }(stdout, stderr)
+	var out, err bytes.Buffer
+	stdout, stderr = &out, &err
+	tests := []discovery.Test{{
+		Test: &v1alpha1.Test{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test1",
+			},
+		},
+	}}
+	_, runErr := run(nil, tclock.NewFakePassiveClock(time.Now()), v1alpha1.ConfigurationSpec{Dashboard: true}, &MockMainStart{}, nil, tests...)
+	assert.NoError(t, runErr)
+	// stderr is not a terminal, the dashboard prints a plain progress line once stopped
+	assert.Equal(t, "[0s] 0 passed, 0 failed, 0 skipped, 0 running\n", err.String())
+	assert.Equal(t, "Tests: 0 passed, 0 failed, 0 skipped\n", out.String())
+}
+
+func TestRun_Progress(t *testing.T) {
+	defer func(out, err io.Writer) { stdout, stderr = out, err }(stdout, stderr)
+	tests := []discovery.Test{{
+		Test: &v1alpha1.Test{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test1",
+			},
+		},
+	}}
+	for _, tt := range []struct {
+		name   string
+		config v1alpha1.ConfigurationSpec
+		want   string
+	}{{
+		name:   "progress",
+		config: v1alpha1.ConfigurationSpec{Progress: true, RepeatCount: ptr.To(3)},
+		want:   "progress: 0/3 done, 0 failed, elapsed 0s\n",
+	}, {
+		name:   "quiet",
+		config: v1alpha1.ConfigurationSpec{Progress: true, Quiet: true},
+	}, {
+		name:   "json",
+		config: v1alpha1.ConfigurationSpec{Progress: true, LogFormat: "json"},
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, err bytes.Buffer
+			stdout, stderr = &out, &err
+			_, runErr := run(nil, tclock.NewFakePassiveClock(time.Now()), tt.config, &MockMainStart{}, nil, tests...)
+			assert.NoError(t, runErr)
+			// stderr is not a terminal, the final progress is printed as a plain line
+			assert.Equal(t, tt.want, err.String())
+			assert.Equal(t, "Tests: 0 passed, 0 failed, 0 skipped\n", out.String())
+		})
+	}
+}
+
+func TestRun_FailuresOnly(t *testing.T) {
+	defer func(out, err io.Writer) { stdout, stderr = out, err }(stdout, stderr)
+	defer func(verbose string) { _ = flag.Set("test.v", verbose) }(flag.Lookup("test.v").Value.String())
+	var out, err bytes.Buffer
+	stdout, stderr = &out, &err
+	tests := []discovery.Test{{
+		Test: &v1alpha1.Test{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test1",
+			},
+		},
+	}}
+	_, runErr := run(nil, tclock.NewFakePassiveClock(time.Now()), v1alpha1.ConfigurationSpec{LogFailuresOnly: true}, &MockMainStart{}, nil, tests...)
+	assert.NoError(t, runErr)
+	// nothing failed, the summary is the only output
+	assert.Equal(t, "Tests: 0 passed, 0 failed, 0 skipped\n", out.String())
+	assert.Empty(t, err.String())
+	assert.Equal(t, "false", flag.Lookup("test.v").Value.String())
+}
+
+func TestRun_EventStream(t *testing.T) {
+	defer func(out, err io.Writer) { stdout, stderr = out, err }(stdout, stderr)
+	var out, err bytes.Buffer
+	stdout, stderr = &out, &err
+	path := filepath.Join(t.TempDir(), "events", "chainsaw.jsonl")
+	tests := []discovery.Test{{
+		Test: &v1alpha1.Test{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test1",
+			},
+		},
+	}}
+	now := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	_, runErr := run(nil, tclock.NewFakePassiveClock(now), v1alpha1.ConfigurationSpec{EventStream: path}, &MockMainStart{}, nil, tests...)
+	assert.NoError(t, runErr)
+	// the mocked main doesn't run the tests, the suite still starts and finishes
+	data, readErr := os.ReadFile(path)
+	assert.NoError(t, readErr)
+	assert.Equal(t, `{"seq":1,"timestamp":"2024-03-01T10:30:00Z","type":"SuiteStarted","tests":1}
+{"seq":2,"timestamp":"2024-03-01T10:30:00Z","type":"SuiteFinished","summary":{"passed":0,"failed":0,"skipped":0}}
+`, string(data))
+	assert.Empty(t, err.String())
+}
@@ -5,9 +5,11 @@ import (
 )
 
 type Summary struct {
-	passed  atomic.Int32
-	failed  atomic.Int32
-	skipped atomic.Int32
+	passed      atomic.Int32
+	failed      atomic.Int32
+	skipped     atomic.Int32
+	quarantined atomic.Int32
+	tolerated   atomic.Int32
 }
 
 func (s *Summary) IncPassed() {
@@ -22,6 +24,16 @@ func (s *Summary) IncSkipped() {
 	s.skipped.Add(1)
 }
 
+// IncQuarantined counts a failed quarantined test, it is not counted as failed.
+func (s *Summary) IncQuarantined() {
+	s.quarantined.Add(1)
+}
+
+// IncTolerated counts a failed iteration of a test whose pass rate is met, it is not counted as failed.
+func (s *Summary) IncTolerated() {
+	s.tolerated.Add(1)
+}
+
 func (s *Summary) Passed() int32 {
 	return s.passed.Load()
 }
@@ -33,3 +45,11 @@ func (s *Summary) Failed() int32 {
 func (s *Summary) Skipped() int32 {
 	return s.skipped.Load()
 }
+
+func (s *Summary) Quarantined() int32 {
+	return s.quarantined.Load()
+}
+
+func (s *Summary) Tolerated() int32 {
+	return s.tolerated.Load()
+}
@@ -12,7 +12,7 @@ func TestSummay(t *testing.T) {
 	var s Summary
 	const count int32 = 10000
 	for i := 0; i < int(count); i++ {
-		wg.Add(3)
+		wg.Add(5)
 		go func() {
 			defer wg.Done()
 			s.IncFailed()
@@ -25,9 +25,19 @@ func TestSummay(t *testing.T) {
 			defer wg.Done()
 			s.IncSkipped()
 		}()
+		go func() {
+			defer wg.Done()
+			s.IncQuarantined()
+		}()
+		go func() {
+			defer wg.Done()
+			s.IncTolerated()
+		}()
 	}
 	wg.Wait()
 	assert.Equal(t, count, s.Failed())
 	assert.Equal(t, count, s.Passed())
 	assert.Equal(t, count, s.Skipped())
+	assert.Equal(t, count, s.Quarantined())
+	assert.Equal(t, count, s.Tolerated())
 }
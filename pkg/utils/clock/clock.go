@@ -0,0 +1,27 @@
+package clock
+
+import (
+	"time"
+)
+
+// Clock tells the current time, the clocks of k8s.io/utils/clock satisfy it.
+type Clock interface {
+	Now() time.Time
+}
+
+// Real is the Clock telling the wall time.
+var Real Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// OrReal returns clock, or Real if clock is nil.
+func OrReal(clock Clock) Clock {
+	if clock == nil {
+		return Real
+	}
+	return clock
+}
@@ -0,0 +1,17 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	tclock "k8s.io/utils/clock/testing"
+)
+
+func TestOrReal(t *testing.T) {
+	now := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	fakeClock := tclock.NewFakePassiveClock(now)
+	assert.Equal(t, now, OrReal(fakeClock).Now())
+	assert.Equal(t, Real, OrReal(nil))
+	assert.WithinDuration(t, time.Now(), OrReal(nil).Now(), time.Second)
+}
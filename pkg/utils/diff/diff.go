@@ -12,7 +12,10 @@ func prune(expected map[string]any, actual map[string]any) {
 			delete(actual, k)
 		} else {
 			if v, ok := v.(map[string]any); ok {
-				prune(expected[k].(map[string]any), v)
+				// fields of another type are kept whole, the diff shows them
+				if expected, ok := expected[k].(map[string]any); ok {
+					prune(expected, v)
+				}
 			}
 		}
 	}
@@ -27,7 +30,9 @@ func pruneMetadata(expected map[string]any, actual map[string]any) {
 			delete(actual, k)
 		} else {
 			if v, ok := v.(map[string]any); ok {
-				prune(expected[k].(map[string]any), v)
+				if expected, ok := expected[k].(map[string]any); ok {
+					prune(expected, v)
+				}
 			}
 		}
 	}
@@ -42,10 +47,12 @@ func pruneRoot(expected map[string]any, actual map[string]any) {
 			delete(actual, k)
 		} else {
 			if v, ok := v.(map[string]any); ok {
-				if k == "metadata" {
-					pruneMetadata(expected[k].(map[string]any), v)
-				} else {
-					prune(expected[k].(map[string]any), v)
+				if expected, ok := expected[k].(map[string]any); ok {
+					if k == "metadata" {
+						pruneMetadata(expected, v)
+					} else {
+						prune(expected, v)
+					}
 				}
 			}
 		}
@@ -53,15 +60,12 @@ func pruneRoot(expected map[string]any, actual map[string]any) {
 }
 
 func PrettyDiff(expected unstructured.Unstructured, actual unstructured.Unstructured) (string, error) {
-	pruneRoot(expected.Object, actual.Object)
-	if expectedBuf, err := yaml.Marshal(&expected); err != nil {
-		return "", err
-	} else if actualBuf, err := yaml.Marshal(&actual); err != nil {
+	if expectedYAML, actualYAML, err := PrettyYAML(expected, actual); err != nil {
 		return "", err
 	} else {
 		diffed := difflib.UnifiedDiff{
-			A:        difflib.SplitLines(string(expectedBuf)),
-			B:        difflib.SplitLines(string(actualBuf)),
+			A:        difflib.SplitLines(expectedYAML),
+			B:        difflib.SplitLines(actualYAML),
 			FromFile: "expected",
 			ToFile:   "actual",
 			Context:  3,
@@ -69,3 +73,16 @@ func PrettyDiff(expected unstructured.Unstructured, actual unstructured.Unstruct
 		return difflib.GetUnifiedDiffString(diffed)
 	}
 }
+
+// PrettyYAML returns the YAML of expected and actual, without the fields of actual that expected doesn't have,
+// the documents PrettyDiff compares. The fields of actual are pruned in place.
+func PrettyYAML(expected unstructured.Unstructured, actual unstructured.Unstructured) (string, string, error) {
+	pruneRoot(expected.Object, actual.Object)
+	if expectedBuf, err := yaml.Marshal(&expected); err != nil {
+		return "", "", err
+	} else if actualBuf, err := yaml.Marshal(&actual); err != nil {
+		return "", "", err
+	} else {
+		return string(expectedBuf), string(actualBuf), nil
+	}
+}
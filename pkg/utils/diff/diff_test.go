@@ -48,6 +48,15 @@ func TestPrettyDiff(t *testing.T) {
 		}(),
 		actual: pod,
 		want:   "--- expected\n+++ actual\n@@ -5,7 +5,6 @@\n     bar: baz\n   name: foo\n   namespace: bar\n-  resourceVersion: \"123\"\n spec:\n   data:\n   - foo\n",
+	}, {
+		name: "wrong type",
+		expected: func() unstructured.Unstructured {
+			pod := pod.DeepCopy()
+			assert.NoError(t, unstructured.SetNestedField(pod.UnstructuredContent(), "foo", "spec", "something"))
+			return *pod
+		}(),
+		actual: pod,
+		want:   "--- expected\n+++ actual\n@@ -8,5 +8,6 @@\n spec:\n   data:\n   - foo\n-  something: foo\n+  something:\n+    foo: foos\n \n",
 	}}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,201 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// maxValueLength is the length past which the values of mismatches are truncated when rendered.
+const maxValueLength = 80
+
+// Mismatch is a field whose actual value doesn't match the expected one.
+type Mismatch struct {
+	// Path is the path of the field, like spec.containers[0].image.
+	Path string
+	// Expected is the expected value, nil for unexpected array elements.
+	Expected any
+	// Actual is the actual value, nil for missing fields.
+	Actual any
+	// Note tells why the values don't match when comparing them isn't enough, like arrays of different lengths.
+	Note string
+}
+
+// String renders the mismatch on a line, like "field spec.replicas expected 3, got 1".
+func (m Mismatch) String() string {
+	return fmt.Sprintf("field %s %s", m.Path, m.Message())
+}
+
+// Message renders the mismatch without its path.
+func (m Mismatch) Message() string {
+	var message string
+	switch {
+	case m.Note != "" && m.Expected == nil && m.Actual == nil:
+		return m.Note
+	case m.Actual == nil:
+		message = fmt.Sprintf("expected %s, got nothing", formatValue(m.Expected))
+	case m.Expected == nil:
+		message = fmt.Sprintf("expected nothing, got %s", formatValue(m.Actual))
+	default:
+		message = fmt.Sprintf("expected %s, got %s", formatValue(m.Expected), formatValue(m.Actual))
+	}
+	if m.Note != "" {
+		message = fmt.Sprintf("%s (%s)", message, m.Note)
+	}
+	return message
+}
+
+// Mismatches compares the fields of expected with those of actual, the fields of actual that expected doesn't have
+// are ignored so that only the asserted subtree is compared. Arrays are compared by index, a mismatch telling their
+// lengths comes first when they differ. Fields and values that are expressions can't be compared without evaluating
+// them, they are skipped.
+func Mismatches(expected any, actual any) []Mismatch {
+	return mismatches("", expected, actual)
+}
+
+func mismatches(path string, expected any, actual any) []Mismatch {
+	switch expected := expected.(type) {
+	case map[string]any:
+		actualMap, ok := actual.(map[string]any)
+		if !ok {
+			return []Mismatch{typeMismatch(path, expected, actual)}
+		}
+		keys := make([]string, 0, len(expected))
+		for key := range expected {
+			if !isExpression(key) {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		var out []Mismatch
+		for _, key := range keys {
+			child := key
+			if path != "" {
+				child = path + "." + key
+			}
+			if value, ok := actualMap[key]; !ok || value == nil {
+				if expected[key] != nil {
+					out = append(out, Mismatch{Path: child, Expected: expected[key]})
+				}
+			} else {
+				out = append(out, mismatches(child, expected[key], value)...)
+			}
+		}
+		return out
+	case []any:
+		actualSlice, ok := actual.([]any)
+		if !ok {
+			return []Mismatch{typeMismatch(path, expected, actual)}
+		}
+		var out []Mismatch
+		if len(expected) != len(actualSlice) {
+			out = append(out, Mismatch{Path: path, Note: fmt.Sprintf("expected %s, got %d", elements(len(expected)), len(actualSlice))})
+		}
+		for i := 0; i < len(expected) || i < len(actualSlice); i++ {
+			child := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(actualSlice):
+				out = append(out, Mismatch{Path: child, Expected: expected[i]})
+			case i >= len(expected):
+				out = append(out, Mismatch{Path: child, Actual: actualSlice[i]})
+			default:
+				out = append(out, mismatches(child, expected[i], actualSlice[i])...)
+			}
+		}
+		return out
+	case nil:
+		return nil
+	case string:
+		if isExpression(expected) {
+			return nil
+		}
+	}
+	if kindOf(expected) != kindOf(actual) {
+		return []Mismatch{typeMismatch(path, expected, actual)}
+	}
+	if equal(expected, actual) {
+		return nil
+	}
+	return []Mismatch{{Path: path, Expected: expected, Actual: actual}}
+}
+
+// typeMismatch returns the mismatch of values of different kinds.
+func typeMismatch(path string, expected any, actual any) Mismatch {
+	return Mismatch{
+		Path:     path,
+		Expected: expected,
+		Actual:   actual,
+		Note:     fmt.Sprintf("expected %s, got %s", kindOf(expected), kindOf(actual)),
+	}
+}
+
+// kindOf returns the kind of a value decoded from YAML or JSON, numbers are of the same kind whatever their type.
+func kindOf(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "map"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	}
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// equal compares scalars, numbers are compared by value so that 3 and 3.0 are equal.
+func equal(expected any, actual any) bool {
+	if kindOf(expected) == "number" {
+		return toFloat(expected) == toFloat(actual)
+	}
+	return reflect.DeepEqual(expected, actual)
+}
+
+func toFloat(value any) float64 {
+	v := reflect.ValueOf(value)
+	switch {
+	case v.CanInt():
+		return float64(v.Int())
+	case v.CanUint():
+		return float64(v.Uint())
+	case v.CanFloat():
+		return v.Float()
+	}
+	return 0
+}
+
+// isExpression returns true if s is an expression of an assertion, like (length(@)) or ~.(items).
+func isExpression(s string) bool {
+	return strings.HasPrefix(s, "~") || strings.HasPrefix(s, "(")
+}
+
+func elements(n int) string {
+	if n == 1 {
+		return "1 element"
+	}
+	return fmt.Sprintf("%d elements", n)
+}
+
+// formatValue renders a value as compact JSON, truncated past maxValueLength.
+func formatValue(value any) string {
+	out, err := json.Marshal(value)
+	if err != nil {
+		out = []byte(fmt.Sprint(value))
+	}
+	if s := string(out); len(s) > maxValueLength {
+		return s[:maxValueLength] + "..."
+	} else {
+		return s
+	}
+}
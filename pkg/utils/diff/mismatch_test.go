@@ -0,0 +1,85 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMismatches(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected any
+		actual   any
+		want     []string
+	}{{
+		name:     "same",
+		expected: map[string]any{"spec": map[string]any{"replicas": int64(3)}},
+		actual:   map[string]any{"spec": map[string]any{"replicas": int64(3), "paused": true}},
+	}, {
+		name:     "numbers of different types",
+		expected: map[string]any{"replicas": int64(3)},
+		actual:   map[string]any{"replicas": float64(3)},
+	}, {
+		name:     "wrong value",
+		expected: map[string]any{"spec": map[string]any{"replicas": int64(3)}},
+		actual:   map[string]any{"spec": map[string]any{"replicas": int64(1)}},
+		want:     []string{"field spec.replicas expected 3, got 1"},
+	}, {
+		name:     "missing field",
+		expected: map[string]any{"spec": map[string]any{"replicas": int64(3)}},
+		actual:   map[string]any{"spec": map[string]any{}},
+		want:     []string{"field spec.replicas expected 3, got nothing"},
+	}, {
+		name:     "wrong type",
+		expected: map[string]any{"spec": map[string]any{"replicas": int64(3)}},
+		actual:   map[string]any{"spec": map[string]any{"replicas": "3"}},
+		want:     []string{`field spec.replicas expected 3, got "3" (expected number, got string)`},
+	}, {
+		name:     "map instead of scalar",
+		expected: map[string]any{"spec": "foo"},
+		actual:   map[string]any{"spec": map[string]any{"replicas": int64(3)}},
+		want:     []string{`field spec expected "foo", got {"replicas":3} (expected string, got map)`},
+	}, {
+		name:     "wrong element",
+		expected: map[string]any{"args": []any{"a", "b"}},
+		actual:   map[string]any{"args": []any{"a", "c"}},
+		want:     []string{`field args[1] expected "b", got "c"`},
+	}, {
+		name:     "extra array element",
+		expected: map[string]any{"args": []any{"a"}},
+		actual:   map[string]any{"args": []any{"a", "b"}},
+		want: []string{
+			"field args expected 1 element, got 2",
+			`field args[1] expected nothing, got "b"`,
+		},
+	}, {
+		name:     "missing array element",
+		expected: map[string]any{"args": []any{"a", "b"}},
+		actual:   map[string]any{"args": []any{"b"}},
+		want: []string{
+			"field args expected 2 elements, got 1",
+			`field args[0] expected "a", got "b"`,
+			`field args[1] expected "b", got nothing`,
+		},
+	}, {
+		name:     "expressions",
+		expected: map[string]any{"(length(args))": int64(2), "~.(items)": map[string]any{}, "name": "(starts_with(@, 'foo'))"},
+		actual:   map[string]any{"args": []any{"a"}, "name": "bar"},
+	}, {
+		name:     "large value",
+		expected: map[string]any{"data": map[string]any{"foo": "bar"}},
+		actual:   map[string]any{"data": strings.Repeat("a", 100)},
+		want:     []string{`field data expected {"foo":"bar"}, got "` + strings.Repeat("a", 79) + `... (expected map, got string)`},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, mismatch := range Mismatches(tt.expected, tt.actual) {
+				got = append(got, mismatch.String())
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
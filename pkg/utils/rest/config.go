@@ -2,8 +2,10 @@ package rest
 
 import (
 	"io"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime/serializer/json"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	api "k8s.io/client-go/tools/clientcmd/api/v1"
@@ -29,6 +31,18 @@ func load(loader clientcmd.ClientConfigLoader, overrides clientcmd.ConfigOverrid
 	return config, nil
 }
 
+// Reachable returns an error if the API server of cfg doesn't return its version within timeout.
+func Reachable(cfg *rest.Config, timeout time.Duration) error {
+	cfg = rest.CopyConfig(cfg)
+	cfg.Timeout = timeout
+	client, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return err
+	}
+	_, err = client.ServerVersion()
+	return err
+}
+
 func Save(cfg *rest.Config, w io.Writer) error {
 	var authProvider *api.AuthProviderConfig
 	var execConfig *api.ExecConfig
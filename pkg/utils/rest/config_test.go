@@ -2,6 +2,8 @@ package rest
 
 import (
 	"bytes"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
@@ -192,3 +194,15 @@ users:
 		})
 	}
 }
+
+func TestReachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"major":"1","minor":"30","gitVersion":"v1.30.0"}`))
+	}))
+	defer server.Close()
+	assert.NoError(t, Reachable(&rest.Config{Host: server.URL}, time.Second))
+	// the server is gone
+	server.Close()
+	assert.Error(t, Reachable(&rest.Config{Host: server.URL}, time.Second))
+}
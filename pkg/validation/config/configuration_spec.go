@@ -1,13 +1,136 @@
 package config
 
 import (
+	"regexp"
+
 	"github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
+	"github.com/kyverno/chainsaw/pkg/report"
+	"github.com/kyverno/chainsaw/pkg/runner/logging"
 	"github.com/kyverno/chainsaw/pkg/validation/test"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 func ValidateConfigurationSpec(path *field.Path, obj v1alpha1.ConfigurationSpec) field.ErrorList {
 	var errs field.ErrorList
+	if obj.ReportFormat != v1alpha1.NoReport {
+		if _, err := report.ParseFormat(string(obj.ReportFormat)); err != nil {
+			errs = append(errs, field.NotSupported(path.Child("reportFormat"), obj.ReportFormat, report.SupportedFormats()))
+		}
+	}
+	switch report.NotificationFormat(obj.NotificationFormat) {
+	case "", report.NotificationFormatSlack, report.NotificationFormatWebhook:
+	default:
+		supported := []string{string(report.NotificationFormatSlack), string(report.NotificationFormatWebhook)}
+		errs = append(errs, field.NotSupported(path.Child("notificationFormat"), obj.NotificationFormat, supported))
+	}
+	switch logging.Format(obj.LogFormat) {
+	case "", logging.TextFormat, logging.JSONFormat:
+	default:
+		errs = append(errs, field.NotSupported(path.Child("logFormat"), obj.LogFormat, logging.SupportedFormats()))
+	}
+	if _, err := logging.ParseResourceFormat(obj.LogResourceFormat); err != nil {
+		errs = append(errs, field.NotSupported(path.Child("logResourceFormat"), obj.LogResourceFormat, logging.SupportedResourceFormats()))
+	}
+	if _, err := logging.ParseResourceListFormat(obj.LogResourceList); err != nil {
+		errs = append(errs, field.NotSupported(path.Child("logResourceList"), obj.LogResourceList, logging.SupportedResourceListFormats()))
+	}
+	for i, pattern := range obj.LogRedactPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			errs = append(errs, field.Invalid(path.Child("logRedactPatterns").Index(i), pattern, err.Error()))
+		}
+	}
+	for i, operationType := range obj.LogOperationTypes {
+		if _, err := logging.ParseOperationType(operationType); err != nil {
+			errs = append(errs, field.NotSupported(path.Child("logOperationTypes").Index(i), operationType, logging.SupportedOperationTypes()))
+		}
+	}
+	if obj.LogCaller != "" {
+		if _, err := logging.ParseCallerLevel(obj.LogCaller); err != nil {
+			errs = append(errs, field.NotSupported(path.Child("logCaller"), obj.LogCaller, []string{"error", "warn"}))
+		}
+	}
+	if obj.LogResourceLinks != "" {
+		if _, err := logging.ParseResourceLinks(obj.LogResourceLinks); err != nil {
+			errs = append(errs, field.Invalid(path.Child("logResourceLinks"), obj.LogResourceLinks, err.Error()))
+		}
+	}
+	if _, err := logging.ParseHyperlinkMode(obj.LogHyperlinks); err != nil {
+		errs = append(errs, field.NotSupported(path.Child("logHyperlinks"), obj.LogHyperlinks, logging.SupportedHyperlinkModes()))
+	}
+	if obj.LogSyslog != "" {
+		if _, _, err := logging.ParseSyslogTarget(obj.LogSyslog); err != nil {
+			errs = append(errs, field.Invalid(path.Child("logSyslog"), obj.LogSyslog, err.Error()))
+		}
+	}
+	if _, err := logging.ParseContinuationFormat(obj.LogContinuation); err != nil {
+		errs = append(errs, field.NotSupported(path.Child("logContinuation"), obj.LogContinuation, logging.SupportedContinuationFormats()))
+	}
+	if obj.LogColumnWidths != "" {
+		if _, err := logging.ParseColumnWidths(obj.LogColumnWidths); err != nil {
+			errs = append(errs, field.Invalid(path.Child("logColumnWidths"), obj.LogColumnWidths, err.Error()))
+		}
+	}
+	if obj.LogVerbosityLevels != "" {
+		if _, err := logging.ParseVerbosityLevels(obj.LogVerbosityLevels); err != nil {
+			errs = append(errs, field.Invalid(path.Child("logVerbosityLevels"), obj.LogVerbosityLevels, err.Error()))
+		}
+	}
+	if _, err := logging.ParseBufferOrder(obj.LogBufferOrder); err != nil {
+		errs = append(errs, field.NotSupported(path.Child("logBufferOrder"), obj.LogBufferOrder, logging.SupportedBufferOrders()))
+	}
+	if _, err := logging.ParseTimestampLayout(obj.LogTimestampFormat); err != nil {
+		errs = append(errs, field.Invalid(path.Child("logTimestampFormat"), obj.LogTimestampFormat, err.Error()))
+	}
+	if obj.LogFileMaxSize < 0 {
+		errs = append(errs, field.Invalid(path.Child("logFileMaxSize"), obj.LogFileMaxSize, "must not be negative"))
+	}
+	if obj.LogFileMaxBackups < 0 {
+		errs = append(errs, field.Invalid(path.Child("logFileMaxBackups"), obj.LogFileMaxBackups, "must not be negative"))
+	}
+	if obj.LogMessageMaxSize != nil && *obj.LogMessageMaxSize < 0 {
+		errs = append(errs, field.Invalid(path.Child("logMessageMaxSize"), *obj.LogMessageMaxSize, "must not be negative"))
+	}
+	if obj.LogDiffMaxHunks != nil && *obj.LogDiffMaxHunks < 0 {
+		errs = append(errs, field.Invalid(path.Child("logDiffMaxHunks"), *obj.LogDiffMaxHunks, "must not be negative"))
+	}
+	if obj.LogWaitInterval != nil && obj.LogWaitInterval.Duration < 0 {
+		errs = append(errs, field.Invalid(path.Child("logWaitInterval"), obj.LogWaitInterval.Duration.String(), "must not be negative"))
+	}
+	if _, _, err := logging.ParseEventStreamFD(obj.EventStream); err != nil {
+		errs = append(errs, field.Invalid(path.Child("eventStream"), obj.EventStream, err.Error()))
+	}
+	if obj.ReportGroupBy != "" {
+		if _, err := report.ParseGroupBy(obj.ReportGroupBy); err != nil {
+			errs = append(errs, field.Invalid(path.Child("reportGroupBy"), obj.ReportGroupBy, err.Error()))
+		}
+	}
+	if obj.ReportRetentionCount != nil && *obj.ReportRetentionCount < 1 {
+		errs = append(errs, field.Invalid(path.Child("reportRetentionCount"), *obj.ReportRetentionCount, "must be at least 1"))
+	}
+	if obj.ProgressInterval != nil && obj.ProgressInterval.Duration <= 0 {
+		errs = append(errs, field.Invalid(path.Child("progressInterval"), obj.ProgressInterval.Duration.String(), "must be positive"))
+	}
+	if obj.ReportRetentionMaxAge != nil && obj.ReportRetentionMaxAge.Duration <= 0 {
+		errs = append(errs, field.Invalid(path.Child("reportRetentionMaxAge"), obj.ReportRetentionMaxAge.Duration.String(), "must be positive"))
+	}
+	if obj.QuarantineSelector != "" {
+		if _, err := labels.Parse(obj.QuarantineSelector); err != nil {
+			errs = append(errs, field.Invalid(path.Child("quarantineSelector"), obj.QuarantineSelector, err.Error()))
+		}
+	}
+	if obj.Iterations < 0 {
+		errs = append(errs, field.Invalid(path.Child("iterations"), obj.Iterations, "must not be negative"))
+	}
+	if _, err := regexp.Compile(obj.IterationsFilter); err != nil {
+		errs = append(errs, field.Invalid(path.Child("iterationsFilter"), obj.IterationsFilter, err.Error()))
+	}
+	if obj.PassRate < 0 || obj.PassRate > 100 {
+		errs = append(errs, field.Invalid(path.Child("passRate"), obj.PassRate, "must be between 0 and 100"))
+	}
+	if obj.FailFastCancel && !obj.FailFast {
+		errs = append(errs, field.Invalid(path.Child("failFastCancel"), obj.FailFastCancel, "requires failFast"))
+	}
 	path = path.Child("clusters")
 	for name, cluster := range obj.Clusters {
 		errs = append(errs, ValidateCluster(path.Key(name), cluster)...)
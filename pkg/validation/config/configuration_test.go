@@ -2,10 +2,13 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 )
 
 func TestValidateConfiguration(t *testing.T) {
@@ -38,6 +41,447 @@ func TestValidateConfiguration(t *testing.T) {
 				}},
 			},
 		},
+	}, {
+		name: "with lower case report format",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				ReportFormat: "json",
+			},
+		},
+	}, {
+		name: "with unsupported report format",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				ReportFormat: "yaml",
+			},
+		},
+		want: field.ErrorList{
+			field.NotSupported(field.NewPath("spec", "reportFormat"), v1alpha1.ReportFormatType("yaml"), []string{"JSON", "XML"}),
+		},
+	}, {
+		name: "with webhook notification format",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				NotificationFormat: "webhook",
+			},
+		},
+	}, {
+		name: "with unsupported notification format",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				NotificationFormat: "email",
+			},
+		},
+		want: field.ErrorList{
+			field.NotSupported(field.NewPath("spec", "notificationFormat"), "email", []string{"slack", "webhook"}),
+		},
+	}, {
+		name: "with json log format",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				LogFormat: "json",
+			},
+		},
+	}, {
+		name: "with unsupported log format",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				LogFormat: "yaml",
+			},
+		},
+		want: field.ErrorList{
+			field.NotSupported(field.NewPath("spec", "logFormat"), "yaml", []string{"text", "json"}),
+		},
+	}, {
+		name: "with resource format",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				LogResourceFormat: "short",
+			},
+		},
+	}, {
+		name: "with unsupported resource format",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				LogResourceFormat: "long",
+			},
+		},
+		want: field.ErrorList{
+			field.NotSupported(field.NewPath("spec", "logResourceFormat"), "long", []string{"full", "namespaced", "short"}),
+		},
+	}, {
+		name: "with resource list",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				LogResourceList: "list",
+			},
+		},
+	}, {
+		name: "with unsupported resource list",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				LogResourceList: "table",
+			},
+		},
+		want: field.ErrorList{
+			field.NotSupported(field.NewPath("spec", "logResourceList"), "table", []string{"count", "list"}),
+		},
+	}, {
+		name: "with continuation",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				LogContinuation: "indent",
+			},
+		},
+	}, {
+		name: "with invalid continuation",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				LogContinuation: "wrap",
+			},
+		},
+		want: field.ErrorList{
+			field.NotSupported(field.NewPath("spec", "logContinuation"), "wrap", []string{"none", "prefix", "indent"}),
+		},
+	}, {
+		name: "with message max size",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				LogMessageMaxSize: ptr.To(0),
+			},
+		},
+	}, {
+		name: "with negative message max size",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				LogMessageMaxSize: ptr.To(-1),
+			},
+		},
+		want: field.ErrorList{
+			field.Invalid(field.NewPath("spec", "logMessageMaxSize"), -1, "must not be negative"),
+		},
+	}, {
+		name: "with operation types",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				LogOperationTypes: []string{"script", "command"},
+			},
+		},
+	}, {
+		name: "with invalid operation type",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				LogOperationTypes: []string{"script", "shell"},
+			},
+		},
+		want: field.ErrorList{
+			field.NotSupported(field.NewPath("spec", "logOperationTypes").Index(1), "shell", []string{"apply", "assert", "command", "create", "delete", "error", "script", "sleep"}),
+		},
+	}, {
+		name: "with syslog",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				LogSyslog: "udp://localhost:514",
+			},
+		},
+	}, {
+		name: "with invalid syslog",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				LogSyslog: "http://localhost:514",
+			},
+		},
+		want: field.ErrorList{
+			field.Invalid(field.NewPath("spec", "logSyslog"), "http://localhost:514", `invalid syslog target "http://localhost:514" (local|unix://<path>|udp://<host:port>|tcp://<host:port>)`),
+		},
+	}, {
+		name: "with invalid resource links",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				LogResourceLinks: "https://dashboard/{{.Cluster}}",
+			},
+		},
+		want: field.ErrorList{
+			field.Invalid(field.NewPath("spec", "logResourceLinks"), "https://dashboard/{{.Cluster}}", `invalid resource link template "https://dashboard/{{.Cluster}}": template: link:1:20: executing "link" at <.Cluster>: can't evaluate field Cluster in type logging.ResourceLink`),
+		},
+	}, {
+		name: "with invalid hyperlink mode",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				LogHyperlinks: "sometimes",
+			},
+		},
+		want: field.ErrorList{
+			field.NotSupported(field.NewPath("spec", "logHyperlinks"), "sometimes", []string{"auto", "always", "never"}),
+		},
+	}, {
+		name: "with invalid caller level",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				LogCaller: "info",
+			},
+		},
+		want: field.ErrorList{
+			field.NotSupported(field.NewPath("spec", "logCaller"), "info", []string{"error", "warn"}),
+		},
+	}, {
+		name: "with negative diff max hunks",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				LogDiffMaxHunks: ptr.To(-1),
+			},
+		},
+		want: field.ErrorList{
+			field.Invalid(field.NewPath("spec", "logDiffMaxHunks"), -1, "must not be negative"),
+		},
+	}, {
+		name: "with wait interval",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				LogWaitInterval: &metav1.Duration{},
+			},
+		},
+	}, {
+		name: "with negative wait interval",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				LogWaitInterval: &metav1.Duration{Duration: -time.Second},
+			},
+		},
+		want: field.ErrorList{
+			field.Invalid(field.NewPath("spec", "logWaitInterval"), "-1s", "must not be negative"),
+		},
+	}, {
+		name: "with log file rotation",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				LogFile:           "chainsaw.log",
+				LogFileMaxSize:    100,
+				LogFileMaxBackups: 5,
+				LogFileCompress:   true,
+			},
+		},
+	}, {
+		name: "with negative log file rotation",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				LogFileMaxSize:    -1,
+				LogFileMaxBackups: -1,
+			},
+		},
+		want: field.ErrorList{
+			field.Invalid(field.NewPath("spec", "logFileMaxSize"), -1, "must not be negative"),
+			field.Invalid(field.NewPath("spec", "logFileMaxBackups"), -1, "must not be negative"),
+		},
+	}, {
+		name: "with event stream file descriptor",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				EventStream: "fd:3",
+			},
+		},
+	}, {
+		name: "with invalid event stream file descriptor",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				EventStream: "fd:three",
+			},
+		},
+		want: field.ErrorList{
+			field.Invalid(field.NewPath("spec", "eventStream"), "fd:three", `invalid file descriptor "three", expected fd:<n> with n a non negative integer`),
+		},
+	}, {
+		name: "with redaction patterns",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				LogRedactPatterns: []string{`ghp_\w+`},
+			},
+		},
+	}, {
+		name: "with invalid redaction pattern",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				LogRedactPatterns: []string{`ghp_\w+`, "("},
+			},
+		},
+		want: field.ErrorList{
+			field.Invalid(field.NewPath("spec", "logRedactPatterns").Index(1), "(", "error parsing regexp: missing closing ): `(`"),
+		},
+	}, {
+		name: "with column widths",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				LogColumnWidths: "test=40,step=20",
+			},
+		},
+	}, {
+		name: "with invalid column widths",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				LogColumnWidths: "status=5",
+			},
+		},
+		want: field.ErrorList{
+			field.Invalid(field.NewPath("spec", "logColumnWidths"), "status=5", `invalid column "status" (test|step|operation)`),
+		},
+	}, {
+		name: "with verbosity levels",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				LogVerbosityLevels: "0=info,4=debug,6=off",
+			},
+		},
+	}, {
+		name: "with invalid verbosity levels",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				LogVerbosityLevels: "0=verbose",
+			},
+		},
+		want: field.ErrorList{
+			field.Invalid(field.NewPath("spec", "logVerbosityLevels"), "0=verbose", `invalid log level "verbose" (error|warn|info|debug)`),
+		},
+	}, {
+		name: "with buffer order",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				LogBuffered:    true,
+				LogBufferOrder: "declaration",
+			},
+		},
+	}, {
+		name: "with unsupported buffer order",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				LogBufferOrder: "random",
+			},
+		},
+		want: field.ErrorList{
+			field.NotSupported(field.NewPath("spec", "logBufferOrder"), "random", []string{"completion", "declaration"}),
+		},
+	}, {
+		name: "with timestamp format",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				LogTimestampFormat: "RFC3339Nano",
+			},
+		},
+	}, {
+		name: "with invalid timestamp format",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				LogTimestampFormat: "hh:mm:ss",
+			},
+		},
+		want: field.ErrorList{
+			field.Invalid(field.NewPath("spec", "logTimestampFormat"), "hh:mm:ss", `invalid timestamp layout "hh:mm:ss", it doesn't contain any layout element`),
+		},
+	}, {
+		name: "with label report grouping",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				ReportGroupBy: "label:team",
+			},
+		},
+	}, {
+		name: "with unsupported report grouping",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				ReportGroupBy: "label",
+			},
+		},
+		want: field.ErrorList{
+			field.Invalid(field.NewPath("spec", "reportGroupBy"), "label", `unsupported report grouping "label", expected directory, label:<key> or prefix:<separator>`),
+		},
+	}, {
+		name: "with report retention",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				ReportRetentionCount:  ptr.To(10),
+				ReportRetentionMaxAge: &metav1.Duration{Duration: 24 * time.Hour},
+			},
+		},
+	}, {
+		name: "with invalid progress interval",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				Progress:         true,
+				ProgressInterval: &metav1.Duration{},
+			},
+		},
+		want: field.ErrorList{
+			field.Invalid(field.NewPath("spec", "progressInterval"), "0s", "must be positive"),
+		},
+	}, {
+		name: "with invalid report retention",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				ReportRetentionCount:  ptr.To(0),
+				ReportRetentionMaxAge: &metav1.Duration{Duration: -time.Hour},
+			},
+		},
+		want: field.ErrorList{
+			field.Invalid(field.NewPath("spec", "reportRetentionCount"), 0, "must be at least 1"),
+			field.Invalid(field.NewPath("spec", "reportRetentionMaxAge"), "-1h0m0s", "must be positive"),
+		},
+	}, {
+		name: "with quarantine selector",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				QuarantineSelector: "flaky=true",
+			},
+		},
+	}, {
+		name: "with invalid quarantine selector",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				QuarantineSelector: "flaky in",
+			},
+		},
+		want: field.ErrorList{
+			field.Invalid(field.NewPath("spec", "quarantineSelector"), "flaky in", "unable to parse requirement: found '' expected: '('"),
+		},
+	}, {
+		name: "with fail fast cancel",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				FailFast:       true,
+				FailFastCancel: true,
+			},
+		},
+	}, {
+		name: "with fail fast cancel without fail fast",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				FailFastCancel: true,
+			},
+		},
+		want: field.ErrorList{
+			field.Invalid(field.NewPath("spec", "failFastCancel"), true, "requires failFast"),
+		},
+	}, {
+		name: "with iterations",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				Iterations:       10,
+				IterationsFilter: "^flaky-",
+				PassRate:         90,
+			},
+		},
+	}, {
+		name: "with invalid iterations",
+		obj: &v1alpha1.Configuration{
+			Spec: v1alpha1.ConfigurationSpec{
+				Iterations:       -1,
+				IterationsFilter: "(",
+				PassRate:         101,
+			},
+		},
+		want: field.ErrorList{
+			field.Invalid(field.NewPath("spec", "iterations"), -1, "must not be negative"),
+			field.Invalid(field.NewPath("spec", "iterationsFilter"), "(", "error parsing regexp: missing closing ): `(`"),
+			field.Invalid(field.NewPath("spec", "passRate"), 101, "must be between 0 and 100"),
+		},
 	}}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -58,6 +58,10 @@ func ValidateOperation(path *field.Path, obj v1alpha1.Operation) field.ErrorList
 		errs = append(errs, ValidateScript(path.Child("script"), obj.Script)...)
 		errs = append(errs, ValidateUpdate(path.Child("update"), obj.Update)...)
 		errs = append(errs, ValidateWait(path.Child("wait"), obj.Wait)...)
+		if obj.Retry != nil && obj.Apply == nil && obj.Create == nil && obj.Delete == nil && obj.Patch == nil && obj.Update == nil {
+			errs = append(errs, field.Forbidden(path.Child("retry"), "retry is only supported by apply, create, delete, patch and update operations"))
+		}
+		errs = append(errs, ValidateRetry(path.Child("retry"), obj.Retry)...)
 	}
 	return errs
 }
@@ -172,6 +172,29 @@ func TestValidateOperation(t *testing.T) {
 			Wait: exampleWait,
 		},
 		expectErr: false,
+	}, {
+		name: "Retried Apply operation",
+		input: v1alpha1.Operation{
+			Retry: &v1alpha1.Retry{Attempts: 3},
+			Apply: exampleApply,
+		},
+		expectErr: false,
+	}, {
+		name: "Retried Assert operation",
+		input: v1alpha1.Operation{
+			Retry:  &v1alpha1.Retry{Attempts: 3},
+			Assert: exampleAssert,
+		},
+		expectErr: true,
+		errMsg:    "retry is only supported by apply, create, delete, patch and update operations",
+	}, {
+		name: "Invalid retry",
+		input: v1alpha1.Operation{
+			Retry:  &v1alpha1.Retry{},
+			Delete: exampleDelete,
+		},
+		expectErr: true,
+		errMsg:    "attempts must be at least 1",
 	}}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,34 @@
+package test
+
+import (
+	"slices"
+
+	"github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
+	"github.com/kyverno/chainsaw/pkg/runner/retry"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func ValidateRetry(path *field.Path, obj *v1alpha1.Retry) field.ErrorList {
+	var errs field.ErrorList
+	if obj != nil {
+		if obj.Attempts < 1 {
+			errs = append(errs, field.Invalid(path.Child("attempts"), obj.Attempts, "attempts must be at least 1"))
+		}
+		if obj.Interval != nil && obj.Interval.Duration < 0 {
+			errs = append(errs, field.Invalid(path.Child("interval"), obj.Interval.Duration.String(), "interval must not be negative"))
+		}
+		if obj.Factor != nil && *obj.Factor < 1 {
+			errs = append(errs, field.Invalid(path.Child("factor"), *obj.Factor, "factor must be at least 1"))
+		}
+		var supported []string
+		for _, class := range retry.Classes() {
+			supported = append(supported, string(class))
+		}
+		for i, class := range obj.On {
+			if !slices.Contains(supported, string(class)) {
+				errs = append(errs, field.NotSupported(path.Child("on").Index(i), class, supported))
+			}
+		}
+	}
+	return errs
+}
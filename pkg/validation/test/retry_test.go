@@ -0,0 +1,70 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+)
+
+func TestValidateRetry(t *testing.T) {
+	supported := []string{"Conflict", "ServerTimeout", "TooManyRequests", "NetworkError"}
+	tests := []struct {
+		name string
+		obj  *v1alpha1.Retry
+		want field.ErrorList
+	}{{
+		name: "null",
+	}, {
+		name: "valid",
+		obj: &v1alpha1.Retry{
+			Attempts: 3,
+			Interval: &metav1.Duration{Duration: time.Second},
+			Factor:   ptr.To(2),
+			On:       []v1alpha1.RetryOn{v1alpha1.RetryOnConflict, v1alpha1.RetryOnNetworkError},
+		},
+	}, {
+		name: "no attempts",
+		obj:  &v1alpha1.Retry{},
+		want: field.ErrorList{
+			field.Invalid(field.NewPath("retry", "attempts"), 0, "attempts must be at least 1"),
+		},
+	}, {
+		name: "negative interval",
+		obj: &v1alpha1.Retry{
+			Attempts: 2,
+			Interval: &metav1.Duration{Duration: -time.Second},
+		},
+		want: field.ErrorList{
+			field.Invalid(field.NewPath("retry", "interval"), "-1s", "interval must not be negative"),
+		},
+	}, {
+		name: "zero factor",
+		obj: &v1alpha1.Retry{
+			Attempts: 2,
+			Factor:   ptr.To(0),
+		},
+		want: field.ErrorList{
+			field.Invalid(field.NewPath("retry", "factor"), 0, "factor must be at least 1"),
+		},
+	}, {
+		name: "unsupported class",
+		obj: &v1alpha1.Retry{
+			Attempts: 2,
+			On:       []v1alpha1.RetryOn{v1alpha1.RetryOnConflict, "NotFound"},
+		},
+		want: field.ErrorList{
+			field.NotSupported(field.NewPath("retry", "on").Index(1), v1alpha1.RetryOn("NotFound"), supported),
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ValidateRetry(field.NewPath("retry"), tt.obj)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -15,5 +15,11 @@ func ValidateTestSpec(path *field.Path, obj v1alpha1.TestSpec) field.ErrorList {
 	}
 	errs = append(errs, ValidateCheck(path.Child("namespaceTemplate"), obj.NamespaceTemplate)...)
 	errs = append(errs, ValidateBindings(path.Child("bindings"), obj.Bindings...)...)
+	if obj.Iterations != nil && *obj.Iterations < 1 {
+		errs = append(errs, field.Invalid(path.Child("iterations"), *obj.Iterations, "iterations must be at least 1"))
+	}
+	if obj.PassRate != nil && (*obj.PassRate < 0 || *obj.PassRate > 100) {
+		errs = append(errs, field.Invalid(path.Child("passRate"), *obj.PassRate, "pass rate must be between 0 and 100"))
+	}
 	return errs
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
 	"github.com/stretchr/testify/assert"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 )
 
 func TestValidateTestSpec(t *testing.T) {
@@ -24,6 +25,22 @@ func TestValidateTestSpec(t *testing.T) {
 		want: field.ErrorList{
 			field.Invalid(field.NewPath("catch").Index(0), v1alpha1.Catch{}, "no statement found in operation"),
 		},
+	}, {
+		name: "iterations",
+		obj: v1alpha1.TestSpec{
+			Iterations: ptr.To(10),
+			PassRate:   ptr.To(90),
+		},
+	}, {
+		name: "invalid iterations",
+		obj: v1alpha1.TestSpec{
+			Iterations: ptr.To(0),
+			PassRate:   ptr.To(101),
+		},
+		want: field.ErrorList{
+			field.Invalid(field.NewPath("iterations"), 0, "iterations must be at least 1"),
+			field.Invalid(field.NewPath("passRate"), 101, "pass rate must be between 0 and 100"),
+		},
 	}}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"github.com/jmespath-community/go-jmespath/pkg/parsing"
 	"github.com/kyverno/chainsaw/pkg/apis/v1alpha1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
@@ -20,5 +21,10 @@ func ValidateTestStepSpec(path *field.Path, obj v1alpha1.TestStepSpec) field.Err
 		errs = append(errs, ValidateFinally(path.Child("finally").Index(i), finally)...)
 	}
 	errs = append(errs, ValidateBindings(path.Child("bindings"), obj.Bindings...)...)
+	if obj.SkipIf != "" {
+		if _, err := parsing.NewParser().Parse(obj.SkipIf); err != nil {
+			errs = append(errs, field.Invalid(path.Child("skipIf"), obj.SkipIf, err.Error()))
+		}
+	}
 	return errs
 }
@@ -123,6 +123,20 @@ func TestValidateTestStepSpec(t *testing.T) {
 			Try: nil,
 		},
 		expectErr: true,
+	}, {
+		name: "Valid skipIf",
+		input: v1alpha1.TestStepSpec{
+			SkipIf: "$values.env == 'prod' && !$values.feature",
+			Try:    validTry,
+		},
+		expectErr: false,
+	}, {
+		name: "Invalid skipIf",
+		input: v1alpha1.TestStepSpec{
+			SkipIf: "$values.env ==",
+			Try:    validTry,
+		},
+		expectErr: true,
 	}}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {